@@ -0,0 +1,272 @@
+package cloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	config "finopsbridge/api/internal/config_"
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/defaults"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/costexplorer"
+	"github.com/aws/aws-sdk-go/service/sts"
+)
+
+// STS limits for AssumeRole session duration
+const (
+	minAWSSessionDuration = 15 * time.Minute
+	maxAWSSessionDuration = 12 * time.Hour
+)
+
+// defaultAWSCostMetric is reported when the provider's credentials don't set costMetric. Unlike
+// BlendedCost, it isn't averaged across the organization's RI and Savings Plan rates.
+const defaultAWSCostMetric = "UnblendedCost"
+
+var awsCostMetrics = map[string]bool{
+	"BlendedCost":      true,
+	"UnblendedCost":    true,
+	"AmortizedCost":    true,
+	"NetAmortizedCost": true,
+}
+
+// awsCostMetric reads the Cost Explorer metric to report from the provider's costMetric
+func awsCostMetric(credentials map[string]interface{}) (string, error) {
+	raw, ok := credentials["costMetric"]
+	if !ok {
+		return defaultAWSCostMetric, nil
+	}
+	metric, _ := raw.(string)
+	if metric == "" {
+		return defaultAWSCostMetric, nil
+	}
+	if !awsCostMetrics[metric] {
+		return "", fmt.Errorf("unsupported costMetric %q (expected BlendedCost, UnblendedCost, AmortizedCost or NetAmortizedCost)", raw)
+	}
+	return metric, nil
+}
+
+// awsCostAndUsageInput builds a monthly Cost Explorer request for one metric between start
+// and end, optionally grouped
+func awsCostAndUsageInput(metric string, start, end time.Time, groupBy ...*costexplorer.GroupDefinition) *costexplorer.GetCostAndUsageInput {
+	input := &costexplorer.GetCostAndUsageInput{
+		TimePeriod: &costexplorer.DateInterval{
+			Start: aws.String(start.Format("2006-01-02")),
+			End:   aws.String(end.Format("2006-01-02")),
+		},
+		Granularity: aws.String("MONTHLY"),
+		Metrics:     []*string{aws.String(metric)},
+	}
+	if len(groupBy) > 0 {
+		input.GroupBy = groupBy
+	}
+	return input
+}
+
+// awsRoleOptions are the role-assumption settings read from AWS provider credentials:
+// roleArn, externalId, durationSeconds, serialNumber (the MFA device of an MFA-gated role), and
+// useInstanceRole to assume roleArn from the server's own instance profile or IRSA role. The
+// MFA token code is never stored; see StartAWSMFASession.
+type awsRoleOptions struct {
+	UseInstanceRole bool // Base credentials come only from the server's instance profile or IRSA
+	RoleARN         string
+	ExternalID      string
+	Duration        time.Duration
+	SerialNumber    string
+}
+
+func parseAWSRoleOptions(credentials map[string]interface{}) (*awsRoleOptions, error) {
+	opts := &awsRoleOptions{}
+	opts.RoleARN, _ = credentials["roleArn"].(string)
+	opts.ExternalID, _ = credentials["externalId"].(string)
+	opts.SerialNumber, _ = credentials["serialNumber"].(string)
+	opts.UseInstanceRole, _ = credentials["useInstanceRole"].(bool)
+
+	if raw, ok := credentials["durationSeconds"]; ok {
+		seconds, ok := raw.(float64)
+		if !ok {
+			return nil, fmt.Errorf("durationSeconds must be a number")
+		}
+		opts.Duration = time.Duration(seconds) * time.Second
+		if opts.Duration < minAWSSessionDuration || opts.Duration > maxAWSSessionDuration {
+			return nil, fmt.Errorf("durationSeconds must be between %d and %d",
+				int(minAWSSessionDuration.Seconds()), int(maxAWSSessionDuration.Seconds()))
+		}
+	}
+
+	if opts.SerialNumber != "" && opts.RoleARN == "" {
+		return nil, fmt.Errorf("serialNumber requires roleArn (the MFA-gated role)")
+	}
+
+	return opts, nil
+}
+
+// apply copies the options onto an STS AssumeRole credential provider
+func (o *awsRoleOptions) apply(p *stscreds.AssumeRoleProvider) {
+	if o.ExternalID != "" {
+		p.ExternalID = aws.String(o.ExternalID)
+	}
+	if o.Duration > 0 {
+		p.Duration = o.Duration
+	}
+}
+
+// sessionKey identifies the MFA session of a provider's role, so changing the role or MFA
+// device doesn't reuse a session started for the old one
+func (o *awsRoleOptions) sessionKey(providerID string) string {
+	return providerID + "|" + o.RoleARN + "|" + o.SerialNumber
+}
+
+// awsMFASession holds the temporary credentials of a role assumed with an MFA code. A code can
+// only be used once, so the credentials can't be refreshed when they expire.
+type awsMFASession struct {
+	credentials *credentials.Credentials
+	expiresAt   time.Time
+}
+
+// awsMFASessions are the live MFA sessions by sessionKey. They are only kept in memory: a
+// restart, or the end of the role's session duration, needs a fresh code.
+var (
+	awsMFASessionsMu sync.Mutex
+	awsMFASessions   = make(map[string]awsMFASession)
+)
+
+// TakeAWSTokenCode removes an MFA tokenCode from submitted provider credentials, so it is never
+// stored, and returns it
+func TakeAWSTokenCode(credentials map[string]interface{}) string {
+	code, _ := credentials["tokenCode"].(string)
+	delete(credentials, "tokenCode")
+	return strings.TrimSpace(code)
+}
+
+// StartAWSMFASession assumes an AWS provider's MFA-gated role with a one-time token code and
+// keeps the session's credentials in memory, for the role's session duration, for every later
+// use of the provider. The provider must have its ID and its credentials with serialNumber.
+func StartAWSMFASession(provider models.CloudProvider, cfg *config.Config, tokenCode string) error {
+	var stored map[string]interface{}
+	json.Unmarshal([]byte(provider.Credentials), &stored)
+
+	opts, err := parseAWSRoleOptions(stored)
+	if err != nil {
+		return err
+	}
+	if opts.SerialNumber == "" {
+		return fmt.Errorf("tokenCode requires serialNumber (the MFA device ARN)")
+	}
+
+	sess, err := awsBaseSession(opts, cfg)
+	if err != nil {
+		return err
+	}
+	creds := stscreds.NewCredentials(sess, opts.RoleARN, func(p *stscreds.AssumeRoleProvider) {
+		opts.apply(p)
+		p.SerialNumber = aws.String(opts.SerialNumber)
+		p.TokenCode = aws.String(tokenCode)
+	})
+	value, err := creds.Get()
+	if err != nil {
+		return fmt.Errorf("failed to assume role %s with MFA: %w", opts.RoleARN, err)
+	}
+	expiresAt, err := creds.ExpiresAt()
+	if err != nil {
+		return fmt.Errorf("failed to read the expiry of the role %s session: %w", opts.RoleARN, err)
+	}
+
+	awsMFASessionsMu.Lock()
+	defer awsMFASessionsMu.Unlock()
+	awsMFASessions[opts.sessionKey(provider.ID)] = awsMFASession{
+		credentials: credentials.NewStaticCredentialsFromCreds(value),
+		expiresAt:   expiresAt,
+	}
+	return nil
+}
+
+// awsMFASessionCredentials returns the credentials of a provider's live MFA session
+func awsMFASessionCredentials(providerID string, opts *awsRoleOptions) (*credentials.Credentials, bool) {
+	awsMFASessionsMu.Lock()
+	defer awsMFASessionsMu.Unlock()
+	key := opts.sessionKey(providerID)
+	mfaSession, ok := awsMFASessions[key]
+	if !ok || !time.Now().Before(mfaSession.expiresAt) {
+		delete(awsMFASessions, key)
+		return nil, false
+	}
+	return mfaSession.credentials, true
+}
+
+// awsBaseSession creates the session a provider's role is assumed from
+func awsBaseSession(opts *awsRoleOptions, cfg *config.Config) (*session.Session, error) {
+	sess, err := session.NewSession(&aws.Config{
+		Region: aws.String(cfg.AWSRegion),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.UseInstanceRole {
+		sess = sess.Copy(&aws.Config{Credentials: awsInstanceRoleCredentials(sess)})
+	}
+	return sess, nil
+}
+
+// newAWSSession creates a session for the provider, assuming its roleArn when one is set.
+// The role is assumed eagerly so permission problems surface as clear errors. An MFA-gated
+// role uses the provider's live MFA session, and fails without one.
+func newAWSSession(provider models.CloudProvider, cfg *config.Config) (*session.Session, error) {
+	var credentials map[string]interface{}
+	json.Unmarshal([]byte(provider.Credentials), &credentials)
+
+	opts, err := parseAWSRoleOptions(credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	sess, err := awsBaseSession(opts, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.RoleARN == "" {
+		return sess, nil
+	}
+
+	if opts.SerialNumber != "" {
+		creds, ok := awsMFASessionCredentials(provider.ID, opts)
+		if !ok {
+			return nil, fmt.Errorf("role %s requires MFA: submit a fresh tokenCode to start a session", opts.RoleARN)
+		}
+		return sess.Copy(&aws.Config{Credentials: creds}), nil
+	}
+
+	creds := stscreds.NewCredentials(sess, opts.RoleARN, opts.apply)
+	if _, err := creds.Get(); err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "AccessDenied" &&
+			strings.Contains(strings.ToLower(aerr.Message()), "multifactor") {
+			return nil, fmt.Errorf("role %s requires MFA: add serialNumber to the credentials and submit a tokenCode", opts.RoleARN)
+		}
+		return nil, fmt.Errorf("failed to assume role %s: %w", opts.RoleARN, err)
+	}
+
+	return sess.Copy(&aws.Config{Credentials: creds}), nil
+}
+
+// awsInstanceRoleCredentials returns the server's own workload credentials: the IRSA web
+// identity when running on EKS with a service account role, otherwise the EC2 instance profile
+// (or ECS task role). Unlike the default chain, it never picks up keys from the environment
+// or shared config files, so no secrets need to be stored.
+func awsInstanceRoleCredentials(sess *session.Session) *credentials.Credentials {
+	var providers []credentials.Provider
+	if tokenFile, roleARN := os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE"), os.Getenv("AWS_ROLE_ARN"); tokenFile != "" && roleARN != "" {
+		providers = append(providers, stscreds.NewWebIdentityRoleProvider(sts.New(sess), roleARN, "finopsbridge", tokenFile))
+	}
+	providers = append(providers, defaults.RemoteCredProvider(*sess.Config, sess.Handlers))
+	return credentials.NewChainCredentials(providers)
+}
@@ -0,0 +1,145 @@
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/costexplorer"
+)
+
+// maxAWSCostLookbackMonths bounds costLookbackMonths. Cost Explorer keeps 13 months of history
+// at daily and monthly granularity by default.
+const maxAWSCostLookbackMonths = 12
+
+// CostPoint is the cost of one period of a provider's cost series
+type CostPoint struct {
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"` // Exclusive
+	Amount    float64   `json:"amount"`
+	Currency  string    `json:"currency"`
+	Estimated bool      `json:"estimated,omitempty"` // The period isn't closed yet and may still change
+}
+
+// awsCostSeriesOptions shape the Cost Explorer query behind AWS billing, from the optional
+// costGranularity (MONTHLY or DAILY) and costLookbackMonths credentials. The default, monthly
+// with no lookback, reads the current month only.
+type awsCostSeriesOptions struct {
+	Granularity    string
+	LookbackMonths int
+}
+
+// series reports whether billing should return the cost series, not just the month's total
+func (o awsCostSeriesOptions) series() bool {
+	return o.Granularity != "MONTHLY" || o.LookbackMonths > 0
+}
+
+func parseAWSCostSeriesOptions(credentials map[string]interface{}) (awsCostSeriesOptions, error) {
+	opts := awsCostSeriesOptions{Granularity: "MONTHLY"}
+
+	if raw, ok := credentials["costGranularity"]; ok {
+		granularity, ok := raw.(string)
+		switch strings.ToUpper(granularity) {
+		case "", "MONTHLY":
+		case "DAILY":
+			opts.Granularity = "DAILY"
+		default:
+			ok = false
+		}
+		if !ok {
+			return opts, fmt.Errorf("unsupported costGranularity %v (expected MONTHLY or DAILY)", raw)
+		}
+	}
+
+	if raw, ok := credentials["costLookbackMonths"]; ok {
+		months, ok := raw.(float64)
+		if !ok || months < 0 || months > maxAWSCostLookbackMonths || months != float64(int(months)) {
+			return opts, fmt.Errorf("costLookbackMonths must be a whole number between 0 and %d", maxAWSCostLookbackMonths)
+		}
+		opts.LookbackMonths = int(months)
+	}
+
+	return opts, nil
+}
+
+// awsCostSeriesStart returns the first day of the month lookback months before now's
+func awsCostSeriesStart(now time.Time, lookbackMonths int) time.Time {
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).AddDate(0, -lookbackMonths, 0)
+}
+
+// fetchAWSCostSeries queries one metric from start to end at the options' granularity,
+// following Cost Explorer's pagination
+func fetchAWSCostSeries(ctx context.Context, ce *costexplorer.CostExplorer, metric string, start, end time.Time, opts awsCostSeriesOptions, defaultCurrency string) ([]CostPoint, error) {
+	input := awsCostAndUsageInput(metric, start, end)
+	input.Granularity = &opts.Granularity
+
+	var periods []*costexplorer.ResultByTime
+	for {
+		result, err := ce.GetCostAndUsageWithContext(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		periods = append(periods, result.ResultsByTime...)
+		if result.NextPageToken == nil || *result.NextPageToken == "" {
+			break
+		}
+		input.NextPageToken = result.NextPageToken
+	}
+
+	return buildAWSCostSeries(periods, metric, defaultCurrency), nil
+}
+
+// buildAWSCostSeries turns Cost Explorer periods into cost points in time order. Periods
+// without the metric are left out, as are those whose dates don't parse.
+func buildAWSCostSeries(periods []*costexplorer.ResultByTime, metric, defaultCurrency string) []CostPoint {
+	series := []CostPoint{}
+	for _, period := range periods {
+		if period.TimePeriod == nil || period.TimePeriod.Start == nil || period.TimePeriod.End == nil {
+			continue
+		}
+		cost, ok := period.Total[metric]
+		if !ok || cost.Amount == nil {
+			continue
+		}
+		start, err := time.Parse("2006-01-02", *period.TimePeriod.Start)
+		if err != nil {
+			continue
+		}
+		end, err := time.Parse("2006-01-02", *period.TimePeriod.End)
+		if err != nil {
+			continue
+		}
+
+		point := CostPoint{Start: start, End: end, Currency: defaultCurrency}
+		fmt.Sscanf(*cost.Amount, "%f", &point.Amount)
+		if cost.Unit != nil && *cost.Unit != "" {
+			point.Currency = *cost.Unit
+		}
+		if period.Estimated != nil {
+			point.Estimated = *period.Estimated
+		}
+		series = append(series, point)
+	}
+
+	sort.SliceStable(series, func(i, j int) bool {
+		return series[i].Start.Before(series[j].Start)
+	})
+	return series
+}
+
+// seriesMonthToDate sums the points of a cost series from monthStart on, reporting false when
+// there are none
+func seriesMonthToDate(series []CostPoint, monthStart time.Time) (float64, string, bool) {
+	total, currency, found := 0.0, "", false
+	for _, point := range series {
+		if point.Start.Before(monthStart) {
+			continue
+		}
+		total += point.Amount
+		currency = point.Currency
+		found = true
+	}
+	return total, currency, found
+}
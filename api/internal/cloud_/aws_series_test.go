@@ -0,0 +1,113 @@
+package cloud
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/costexplorer"
+)
+
+func TestParseAWSCostSeriesOptions(t *testing.T) {
+	tests := []struct {
+		credentials map[string]interface{}
+		want        awsCostSeriesOptions
+		series      bool
+	}{
+		{map[string]interface{}{}, awsCostSeriesOptions{Granularity: "MONTHLY"}, false},
+		{map[string]interface{}{"costGranularity": "daily"}, awsCostSeriesOptions{Granularity: "DAILY"}, true},
+		{map[string]interface{}{"costGranularity": "", "costLookbackMonths": 3.0}, awsCostSeriesOptions{Granularity: "MONTHLY", LookbackMonths: 3}, true},
+	}
+	for _, tt := range tests {
+		got, err := parseAWSCostSeriesOptions(tt.credentials)
+		if err != nil || got != tt.want || got.series() != tt.series {
+			t.Errorf("parseAWSCostSeriesOptions(%v) = %+v, %v; want %+v", tt.credentials, got, err, tt.want)
+		}
+	}
+
+	for _, credentials := range []map[string]interface{}{
+		{"costGranularity": "HOURLY"},
+		{"costGranularity": 1.0},
+		{"costLookbackMonths": 13.0},
+		{"costLookbackMonths": -1.0},
+		{"costLookbackMonths": 1.5},
+		{"costLookbackMonths": "3"},
+	} {
+		if _, err := parseAWSCostSeriesOptions(credentials); err == nil {
+			t.Errorf("%v should be rejected", credentials)
+		}
+	}
+}
+
+func TestAWSCostSeriesStart(t *testing.T) {
+	tests := []struct {
+		now      time.Time
+		lookback int
+		want     time.Time
+	}{
+		{time.Date(2026, 3, 15, 9, 0, 0, 0, time.UTC), 0, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2026, 3, 15, 9, 0, 0, 0, time.UTC), 2, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC), 1, time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got := awsCostSeriesStart(tt.now, tt.lookback); !got.Equal(tt.want) {
+			t.Errorf("awsCostSeriesStart(%s, %d) = %s, want %s", tt.now, tt.lookback, got, tt.want)
+		}
+	}
+}
+
+func awsPeriod(start, end, amount, unit string, estimated bool) *costexplorer.ResultByTime {
+	period := &costexplorer.ResultByTime{
+		TimePeriod: &costexplorer.DateInterval{Start: aws.String(start), End: aws.String(end)},
+		Total:      map[string]*costexplorer.MetricValue{"UnblendedCost": {Amount: aws.String(amount)}},
+		Estimated:  aws.Bool(estimated),
+	}
+	if unit != "" {
+		period.Total["UnblendedCost"].Unit = aws.String(unit)
+	}
+	return period
+}
+
+func TestBuildAWSCostSeries(t *testing.T) {
+	withoutMetric := awsPeriod("2026-01-03", "2026-01-04", "1", "USD", false)
+	withoutMetric.Total = map[string]*costexplorer.MetricValue{"AmortizedCost": {Amount: aws.String("1")}}
+
+	got := buildAWSCostSeries([]*costexplorer.ResultByTime{
+		awsPeriod("2026-01-02", "2026-01-03", "20.5", "USD", true),
+		awsPeriod("2026-01-01", "2026-01-02", "10", "", false),
+		withoutMetric,
+		awsPeriod("January", "2026-01-05", "3", "USD", false),
+		{TimePeriod: nil},
+	}, "UnblendedCost", "EUR")
+
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC) }
+	want := []CostPoint{
+		{Start: day(1), End: day(2), Amount: 10, Currency: "EUR"},
+		{Start: day(2), End: day(3), Amount: 20.5, Currency: "USD", Estimated: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	if got := buildAWSCostSeries(nil, "UnblendedCost", "USD"); got == nil {
+		t.Error("an empty series should encode as []")
+	}
+}
+
+func TestSeriesMonthToDate(t *testing.T) {
+	month := func(m time.Month, d int) time.Time { return time.Date(2026, m, d, 0, 0, 0, 0, time.UTC) }
+	series := []CostPoint{
+		{Start: month(2, 28), End: month(3, 1), Amount: 100, Currency: "USD"},
+		{Start: month(3, 1), End: month(3, 2), Amount: 10, Currency: "USD"},
+		{Start: month(3, 2), End: month(3, 3), Amount: 5.5, Currency: "USD"},
+	}
+
+	total, currency, ok := seriesMonthToDate(series, month(3, 1))
+	if total != 15.5 || currency != "USD" || !ok {
+		t.Errorf("got %v, %q, %v; want 15.5 USD", total, currency, ok)
+	}
+	if _, _, ok := seriesMonthToDate(series, month(4, 1)); ok {
+		t.Error("a series without points this month should report false")
+	}
+}
@@ -0,0 +1,180 @@
+package cloud
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	config "finopsbridge/api/internal/config_"
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/service/costexplorer"
+)
+
+func TestAWSCostMetric(t *testing.T) {
+	tests := []struct {
+		credentials map[string]interface{}
+		want        string
+		ok          bool
+	}{
+		{map[string]interface{}{}, "UnblendedCost", true},
+		{map[string]interface{}{"costMetric": ""}, "UnblendedCost", true},
+		{map[string]interface{}{"costMetric": "AmortizedCost"}, "AmortizedCost", true},
+		{map[string]interface{}{"costMetric": "NetAmortizedCost"}, "NetAmortizedCost", true},
+		{map[string]interface{}{"costMetric": "amortizedcost"}, "", false},
+		{map[string]interface{}{"costMetric": "UsageQuantity"}, "", false},
+	}
+
+	for _, tt := range tests {
+		got, err := awsCostMetric(tt.credentials)
+		if got != tt.want || (err == nil) != tt.ok {
+			t.Errorf("awsCostMetric(%v) = %q, %v; want %q, ok=%v", tt.credentials, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestAWSCostAndUsageInput(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	input := awsCostAndUsageInput("AmortizedCost", start, end)
+	if *input.TimePeriod.Start != "2026-03-01" || *input.TimePeriod.End != "2026-03-15" {
+		t.Errorf("time period = %s..%s", *input.TimePeriod.Start, *input.TimePeriod.End)
+	}
+	if len(input.Metrics) != 1 || *input.Metrics[0] != "AmortizedCost" || input.GroupBy != nil {
+		t.Errorf("got %v", input)
+	}
+
+	grouped := awsCostAndUsageInput("UnblendedCost", start, end, &costexplorer.GroupDefinition{
+		Type: aws.String("DIMENSION"),
+		Key:  aws.String("SERVICE"),
+	})
+	if len(grouped.GroupBy) != 1 || *grouped.GroupBy[0].Key != "SERVICE" {
+		t.Errorf("got %v", grouped)
+	}
+}
+
+func TestParseAWSRoleOptions(t *testing.T) {
+	opts, err := parseAWSRoleOptions(map[string]interface{}{
+		"roleArn":         "arn:aws:iam::123456789012:role/finops",
+		"externalId":      "ext",
+		"durationSeconds": float64(3600),
+		"serialNumber":    "arn:aws:iam::123456789012:mfa/alice",
+		"useInstanceRole": true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.Duration != time.Hour || !opts.UseInstanceRole || opts.ExternalID != "ext" {
+		t.Errorf("got %+v", opts)
+	}
+
+	var p stscreds.AssumeRoleProvider
+	opts.apply(&p)
+	if p.Duration != time.Hour || p.ExternalID == nil || *p.ExternalID != "ext" {
+		t.Errorf("apply set duration %v and external ID %v", p.Duration, p.ExternalID)
+	}
+}
+
+func TestParseAWSRoleOptionsRejectsInvalid(t *testing.T) {
+	for _, creds := range []map[string]interface{}{
+		{"roleArn": "arn:aws:iam::123456789012:role/finops", "durationSeconds": "3600"},
+		{"roleArn": "arn:aws:iam::123456789012:role/finops", "durationSeconds": float64(60)},
+		{"roleArn": "arn:aws:iam::123456789012:role/finops", "durationSeconds": float64(13 * 3600)},
+		{"serialNumber": "arn:aws:iam::123456789012:mfa/alice"},
+	} {
+		if _, err := parseAWSRoleOptions(creds); err == nil {
+			t.Errorf("%v should be rejected", creds)
+		}
+	}
+}
+
+func TestTakeAWSTokenCode(t *testing.T) {
+	creds := map[string]interface{}{"roleArn": "arn", "tokenCode": " 123456 "}
+	if code := TakeAWSTokenCode(creds); code != "123456" {
+		t.Errorf("got %q", code)
+	}
+	if _, ok := creds["tokenCode"]; ok {
+		t.Error("the token code must be removed so it is never stored")
+	}
+	if code := TakeAWSTokenCode(creds); code != "" {
+		t.Errorf("got %q without a token code", code)
+	}
+}
+
+func TestAWSMFASessionCredentials(t *testing.T) {
+	opts := &awsRoleOptions{RoleARN: "arn:aws:iam::123456789012:role/finops", SerialNumber: "mfa"}
+	providerID := "provider-" + t.Name()
+	key := opts.sessionKey(providerID)
+	t.Cleanup(func() {
+		awsMFASessionsMu.Lock()
+		delete(awsMFASessions, key)
+		awsMFASessionsMu.Unlock()
+	})
+
+	awsMFASessionsMu.Lock()
+	awsMFASessions[key] = awsMFASession{
+		credentials: credentials.NewStaticCredentials("id", "secret", "token"),
+		expiresAt:   time.Now().Add(time.Hour),
+	}
+	awsMFASessionsMu.Unlock()
+
+	if _, ok := awsMFASessionCredentials(providerID, opts); !ok {
+		t.Error("a live session should be reused")
+	}
+	other := &awsRoleOptions{RoleARN: opts.RoleARN, SerialNumber: "other-mfa"}
+	if _, ok := awsMFASessionCredentials(providerID, other); ok {
+		t.Error("changing the MFA device shouldn't reuse the old session")
+	}
+
+	awsMFASessionsMu.Lock()
+	awsMFASessions[key] = awsMFASession{expiresAt: time.Now().Add(-time.Minute)}
+	awsMFASessionsMu.Unlock()
+	if _, ok := awsMFASessionCredentials(providerID, opts); ok {
+		t.Error("an expired session shouldn't be used")
+	}
+}
+
+func TestNewAWSSessionRequiresMFASession(t *testing.T) {
+	provider := models.CloudProvider{
+		ID:          "provider-" + t.Name(),
+		Type:        "aws",
+		Credentials: `{"roleArn": "arn:aws:iam::123456789012:role/finops", "serialNumber": "mfa"}`,
+	}
+	_, err := newAWSSession(provider, &config.Config{AWSRegion: "us-east-1"})
+	if err == nil || !strings.Contains(err.Error(), "requires MFA") {
+		t.Errorf("got %v, want an error asking for a token code", err)
+	}
+}
+
+func TestAWSInstanceRoleCredentialsIgnoreEnvironmentKeys(t *testing.T) {
+	// Stand in for the ECS task role endpoint, which the instance role chain reads
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"AccessKeyId": "task-role-key", "SecretAccessKey": "secret", "Token": "token", "Expiration": %q}`,
+			time.Now().Add(time.Hour).UTC().Format(time.RFC3339))
+	}))
+	defer server.Close()
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "environment-key")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "environment-secret")
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_FULL_URI", server.URL)
+	t.Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", "")
+
+	sess, err := awsBaseSession(&awsRoleOptions{UseInstanceRole: true}, &config.Config{AWSRegion: "us-east-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := sess.Config.Credentials.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value.AccessKeyID != "task-role-key" {
+		t.Errorf("got access key %q, want the task role's rather than the environment's", value.AccessKeyID)
+	}
+}
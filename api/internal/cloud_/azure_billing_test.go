@@ -0,0 +1,109 @@
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/consumption/armconsumption"
+)
+
+// usageTransport serves usage detail pages of one record each, linking to another page until
+// pages have been served
+type usageTransport struct {
+	record   string
+	pages    int
+	requests int
+}
+
+func (f *usageTransport) Do(req *http.Request) (*http.Response, error) {
+	f.requests++
+	body := fmt.Sprintf(`{"value": [%s]}`, f.record)
+	if f.requests < f.pages {
+		next := *req.URL
+		next.RawQuery = fmt.Sprintf("page=%d", f.requests+1)
+		body = fmt.Sprintf(`{"value": [%s], "nextLink": %q}`, f.record, next.String())
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func fetchUsageCost(t *testing.T, transport *usageTransport) AzureSubscriptionCost {
+	t.Helper()
+	client, err := armconsumption.NewUsageDetailsClient(fakeAzureCredential{}, &arm.ClientOptions{
+		ClientOptions: policy.ClientOptions{Transport: transport},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cost, err := fetchAzureSubscriptionCost(context.Background(), client, "sub", "", "USD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cost
+}
+
+func TestFetchAzureSubscriptionCostSumsUsage(t *testing.T) {
+	legacy := fetchUsageCost(t, &usageTransport{record: `{"kind": "legacy", "properties": {"cost": 1.5, "billingCurrency": "EUR"}}`, pages: 3})
+	if legacy.MonthlySpend != 4.5 || legacy.Currency != "EUR" || !legacy.hasData || legacy.Truncated {
+		t.Errorf("legacy usage = %+v, want 4.5 EUR in full", legacy)
+	}
+
+	modern := fetchUsageCost(t, &usageTransport{record: `{"kind": "modern", "properties": {"costInBillingCurrency": 2, "billingCurrencyCode": "GBP"}}`, pages: 2})
+	if modern.MonthlySpend != 4 || modern.Currency != "GBP" || modern.Truncated {
+		t.Errorf("modern usage = %+v, want 4 GBP in full", modern)
+	}
+}
+
+func TestFetchAzureSubscriptionCostTruncates(t *testing.T) {
+	transport := &usageTransport{record: `{"kind": "legacy", "properties": {"cost": 1}}`, pages: maxAzureUsagePages + 5}
+	cost := fetchUsageCost(t, transport)
+
+	if !cost.Truncated {
+		t.Error("usage past the page cap should be marked truncated")
+	}
+	if transport.requests != maxAzureUsagePages || cost.MonthlySpend != maxAzureUsagePages {
+		t.Errorf("read %d pages summing %v, want %d", transport.requests, cost.MonthlySpend, maxAzureUsagePages)
+	}
+	if cost.Currency != "USD" {
+		t.Errorf("currency = %q, want the default when usage reports none", cost.Currency)
+	}
+}
+
+func TestSumAzureCharges(t *testing.T) {
+	total, currency := sumAzureCharges([]armconsumption.ChargeSummaryClassification{
+		&armconsumption.LegacyChargeSummary{Properties: &armconsumption.LegacyChargeSummaryProperties{
+			AzureCharges:       to.Ptr(100.0),
+			MarketplaceCharges: to.Ptr(20.0),
+			Currency:           to.Ptr("EUR"),
+		}},
+		&armconsumption.LegacyChargeSummary{},
+	}, "USD")
+	if total != 120 || currency != "EUR" {
+		t.Errorf("legacy charges = %v %s, want 120 EUR", total, currency)
+	}
+
+	total, currency = sumAzureCharges([]armconsumption.ChargeSummaryClassification{
+		&armconsumption.ModernChargeSummary{Properties: &armconsumption.ModernChargeSummaryProperties{
+			AzureCharges:       &armconsumption.Amount{Value: to.Ptr(50.0), Currency: to.Ptr("GBP")},
+			MarketplaceCharges: &armconsumption.Amount{},
+		}},
+	}, "USD")
+	if total != 50 || currency != "GBP" {
+		t.Errorf("modern charges = %v %s, want 50 GBP", total, currency)
+	}
+
+	if total, currency := sumAzureCharges(nil, "USD"); total != 0 || currency != "USD" {
+		t.Errorf("no charges = %v %s, want 0 USD", total, currency)
+	}
+}
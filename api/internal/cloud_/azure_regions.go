@@ -0,0 +1,92 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+)
+
+// AzureRegions returns the regions an Azure provider's VM listings are limited to, from the
+// credentials' optional "regions", a list or a comma-separated string. Names are normalized to
+// Azure's location form, e.g. "East US" to "eastus". None means every region.
+func AzureRegions(provider models.CloudProvider) []string {
+	var credentials map[string]interface{}
+	if err := json.Unmarshal([]byte(provider.Credentials), &credentials); err != nil {
+		return nil
+	}
+
+	var raw []string
+	switch regions := credentials["regions"].(type) {
+	case string:
+		raw = strings.Split(regions, ",")
+	case []interface{}:
+		for _, region := range regions {
+			if s, ok := region.(string); ok {
+				raw = append(raw, s)
+			}
+		}
+	}
+
+	var result []string
+	seen := make(map[string]bool)
+	for _, region := range raw {
+		region = normalizeAzureRegion(region)
+		if region != "" && !seen[region] {
+			seen[region] = true
+			result = append(result, region)
+		}
+	}
+	return result
+}
+
+// normalizeAzureRegion converts a region's display name to its location name
+func normalizeAzureRegion(region string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(region), " ", ""))
+}
+
+// azureVMPager pages through a subscription's VMs. Without regions it lists every VM in one
+// ListAll pager; with regions it lists each region's VMs in turn, so large tenants don't pay
+// for listing, and then checking, VMs in regions the provider doesn't cover.
+type azureVMPager struct {
+	client     *armcompute.VirtualMachinesClient
+	all        *runtime.Pager[armcompute.VirtualMachinesClientListAllResponse]
+	regions    []string
+	byLocation *runtime.Pager[armcompute.VirtualMachinesClientListByLocationResponse]
+}
+
+func newAzureVMPager(client *armcompute.VirtualMachinesClient, regions []string) *azureVMPager {
+	if len(regions) == 0 {
+		return &azureVMPager{all: client.NewListAllPager(nil)}
+	}
+	return &azureVMPager{client: client, regions: regions}
+}
+
+// More reports whether another page is left, moving on to the next region once one is done
+func (p *azureVMPager) More() bool {
+	if p.all != nil {
+		return p.all.More()
+	}
+	for p.byLocation == nil || !p.byLocation.More() {
+		if len(p.regions) == 0 {
+			return false
+		}
+		p.byLocation = p.client.NewListByLocationPager(p.regions[0], nil)
+		p.regions = p.regions[1:]
+	}
+	return true
+}
+
+// NextPage fetches the next page of VMs. Call it only after More reports true.
+func (p *azureVMPager) NextPage(ctx context.Context) (armcompute.VirtualMachineListResult, error) {
+	if p.all != nil {
+		page, err := p.all.NextPage(ctx)
+		return page.VirtualMachineListResult, err
+	}
+	page, err := p.byLocation.NextPage(ctx)
+	return page.VirtualMachineListResult, err
+}
@@ -0,0 +1,118 @@
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+)
+
+func TestAzureRegions(t *testing.T) {
+	tests := []struct {
+		credentials string
+		want        []string
+	}{
+		{`{"regions": ["East US", "westeurope", "eastus", " "]}`, []string{"eastus", "westeurope"}},
+		{`{"regions": "East US 2, North Europe"}`, []string{"eastus2", "northeurope"}},
+		{`{"regions": [1, "uksouth"]}`, []string{"uksouth"}},
+		{`{"subscriptionId": "sub"}`, nil},
+		{`not json`, nil},
+	}
+	for _, tt := range tests {
+		got := AzureRegions(models.CloudProvider{Type: "azure", Credentials: tt.credentials})
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("AzureRegions(%s) = %v, want %v", tt.credentials, got, tt.want)
+		}
+	}
+}
+
+type fakeAzureCredential struct{}
+
+func (fakeAzureCredential) GetToken(context.Context, policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	return azcore.AccessToken{Token: "token"}, nil
+}
+
+// vmListTransport serves VM listings, one VM per page named after the request, and the
+// first eastus page links to a second
+type vmListTransport struct {
+	paths []string
+}
+
+func (f *vmListTransport) Do(req *http.Request) (*http.Response, error) {
+	f.paths = append(f.paths, req.URL.Path+"?"+req.URL.Query().Get("page"))
+	name := "all"
+	if i := strings.Index(req.URL.Path, "/locations/"); i >= 0 {
+		name = strings.Split(req.URL.Path[i+len("/locations/"):], "/")[0]
+	}
+	body := fmt.Sprintf(`{"value": [{"name": "vm-%s-%d"}]}`, name, len(f.paths))
+	if name == "eastus" && req.URL.Query().Get("page") == "" {
+		next := *req.URL
+		next.RawQuery = "page=2"
+		body = fmt.Sprintf(`{"value": [{"name": "vm-%s-%d"}], "nextLink": %q}`, name, len(f.paths), next.String())
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func listAzureVMs(t *testing.T, regions []string) ([]string, []string) {
+	t.Helper()
+	transport := &vmListTransport{}
+	client, err := armcompute.NewVirtualMachinesClient("sub", fakeAzureCredential{}, &arm.ClientOptions{
+		ClientOptions: policy.ClientOptions{Transport: transport},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	pager := newAzureVMPager(client, regions)
+	for pager.More() {
+		page, err := pager.NextPage(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, vm := range page.Value {
+			names = append(names, *vm.Name)
+		}
+	}
+	return names, transport.paths
+}
+
+func TestAzureVMPagerListsEachRegion(t *testing.T) {
+	names, paths := listAzureVMs(t, []string{"eastus", "westeurope"})
+
+	want := []string{"vm-eastus-1", "vm-eastus-2", "vm-westeurope-3"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("got %v, want %v", names, want)
+	}
+	for _, path := range paths {
+		if !strings.Contains(path, "/locations/") {
+			t.Errorf("%s: with regions, VMs should only be listed by location", path)
+		}
+	}
+}
+
+func TestAzureVMPagerListsAllWithoutRegions(t *testing.T) {
+	names, paths := listAzureVMs(t, nil)
+
+	if !reflect.DeepEqual(names, []string{"vm-all-1"}) {
+		t.Errorf("got %v, want one ListAll page", names)
+	}
+	if len(paths) != 1 || strings.Contains(paths[0], "/locations/") {
+		t.Errorf("requests = %v, want one ListAll request", paths)
+	}
+}
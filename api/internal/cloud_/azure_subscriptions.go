@@ -0,0 +1,99 @@
+package cloud
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	config "finopsbridge/api/internal/config_"
+	models "finopsbridge/api/internal/models_"
+)
+
+// azureSubscriptionPattern matches an Azure subscription ID, which is a GUID
+var azureSubscriptionPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// AzureSubscriptionIDs returns the subscriptions an Azure provider covers. SubscriptionID holds
+// one ID or a comma-separated list.
+func AzureSubscriptionIDs(provider models.CloudProvider) []string {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, id := range strings.Split(provider.SubscriptionID, ",") {
+		id = strings.TrimSpace(id)
+		if id != "" && !seen[strings.ToLower(id)] {
+			seen[strings.ToLower(id)] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// NormalizeAzureSubscriptionIDs validates subscription IDs given as a comma-separated string, a
+// list, or both, and joins them into the form stored in CloudProvider.SubscriptionID
+func NormalizeAzureSubscriptionIDs(raw string, list []string) (string, error) {
+	ids := AzureSubscriptionIDs(models.CloudProvider{SubscriptionID: strings.Join(append([]string{raw}, list...), ",")})
+	if len(ids) == 0 {
+		return "", fmt.Errorf("at least one subscription ID is required")
+	}
+	for _, id := range ids {
+		if !azureSubscriptionPattern.MatchString(id) {
+			return "", fmt.Errorf("invalid Azure subscription ID %q", id)
+		}
+	}
+	return strings.Join(ids, ","), nil
+}
+
+// azureSubscriptionFromID returns the subscription segment of an Azure resource ID
+func azureSubscriptionFromID(resourceID string) string {
+	parts := splitAzureResourceID(resourceID)
+	for i, part := range parts {
+		if strings.EqualFold(part, "subscriptions") && i+1 < len(parts) {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
+
+// AzureSubscriptionCost is one subscription's month-to-date cost
+type AzureSubscriptionCost struct {
+	SubscriptionID string  `json:"subscriptionId"`
+	MonthlySpend   float64 `json:"monthlySpend"`
+	Currency       string  `json:"currency"`
+	Truncated      bool    `json:"truncated,omitempty"` // Usage ran past maxAzureUsagePages; MonthlySpend is a lower bound
+
+	hasData bool // Whether any usage records were returned
+}
+
+// aggregateAzureSubscriptionCosts totals per-subscription costs. Costs in one currency are
+// summed as-is; mixed currencies are converted to the reporting currency, leaving out those
+// without an exchange rate.
+func aggregateAzureSubscriptionCosts(costs []AzureSubscriptionCost, cfg *config.Config) (float64, string) {
+	if len(costs) == 0 {
+		return 0, DefaultCurrency(cfg)
+	}
+
+	currency := costs[0].Currency
+	mixed := false
+	for _, cost := range costs[1:] {
+		if !strings.EqualFold(cost.Currency, currency) {
+			mixed = true
+			break
+		}
+	}
+
+	total := 0.0
+	for _, cost := range costs {
+		if !mixed {
+			total += cost.MonthlySpend
+			continue
+		}
+		if converted, ok := ConvertCurrency(cost.MonthlySpend, cost.Currency, cfg); ok {
+			total += converted
+		} else {
+			fmt.Printf("No exchange rate for %s; leaving subscription %s out of the total\n", cost.Currency, cost.SubscriptionID)
+		}
+	}
+	if mixed {
+		currency = cfg.ReportingCurrency
+	}
+	return total, currency
+}
@@ -0,0 +1,78 @@
+package cloud
+
+import (
+	"reflect"
+	"testing"
+
+	config "finopsbridge/api/internal/config_"
+	models "finopsbridge/api/internal/models_"
+)
+
+const (
+	subscriptionA = "11111111-2222-3333-4444-555555555555"
+	subscriptionB = "aaaaaaaa-bbbb-cccc-dddd-eeeeeeeeeeee"
+)
+
+func TestAzureSubscriptionIDs(t *testing.T) {
+	provider := models.CloudProvider{SubscriptionID: " " + subscriptionA + ", ," + subscriptionB + "," + "AAAAAAAA-BBBB-CCCC-DDDD-EEEEEEEEEEEE"}
+
+	got := AzureSubscriptionIDs(provider)
+	if want := []string{subscriptionA, subscriptionB}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := AzureSubscriptionIDs(models.CloudProvider{}); len(got) != 0 {
+		t.Errorf("no subscription should give no IDs, got %v", got)
+	}
+}
+
+func TestNormalizeAzureSubscriptionIDs(t *testing.T) {
+	got, err := NormalizeAzureSubscriptionIDs(subscriptionA, []string{subscriptionB, subscriptionA})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := subscriptionA + "," + subscriptionB; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if _, err := NormalizeAzureSubscriptionIDs("", nil); err == nil {
+		t.Error("no subscription IDs should be rejected")
+	}
+	if _, err := NormalizeAzureSubscriptionIDs(subscriptionA+",not-a-guid", nil); err == nil {
+		t.Error("an invalid subscription ID should be rejected")
+	}
+}
+
+func TestAzureSubscriptionFromID(t *testing.T) {
+	id := "/subscriptions/" + subscriptionA + "/resourceGroups/rg/providers/Microsoft.Compute/virtualMachines/vm-1"
+	if got := azureSubscriptionFromID(id); got != subscriptionA {
+		t.Errorf("got %q, want %q", got, subscriptionA)
+	}
+	if got := azureSubscriptionFromID("/resourceGroups/rg"); got != "" {
+		t.Errorf("an ID without a subscription should give none, got %q", got)
+	}
+}
+
+func TestAggregateAzureSubscriptionCosts(t *testing.T) {
+	cfg := &config.Config{ReportingCurrency: "USD", ExchangeRates: map[string]float64{"EUR": 2}}
+
+	total, currency := aggregateAzureSubscriptionCosts([]AzureSubscriptionCost{
+		{SubscriptionID: subscriptionA, MonthlySpend: 100, Currency: "EUR"},
+		{SubscriptionID: subscriptionB, MonthlySpend: 50, Currency: "eur"},
+	}, cfg)
+	if total != 150 || currency != "EUR" {
+		t.Errorf("one currency: got %v %s, want 150 EUR", total, currency)
+	}
+
+	total, currency = aggregateAzureSubscriptionCosts([]AzureSubscriptionCost{
+		{SubscriptionID: subscriptionA, MonthlySpend: 100, Currency: "EUR"},
+		{SubscriptionID: subscriptionB, MonthlySpend: 50, Currency: "USD"},
+		{SubscriptionID: "c", MonthlySpend: 1000, Currency: "JPY"},
+	}, cfg)
+	if total != 250 || currency != "USD" {
+		t.Errorf("mixed currencies: got %v %s, want 250 USD without the unconvertible JPY", total, currency)
+	}
+
+	if total, currency := aggregateAzureSubscriptionCosts(nil, cfg); total != 0 || currency != "USD" {
+		t.Errorf("no costs: got %v %s", total, currency)
+	}
+}
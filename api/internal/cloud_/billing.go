@@ -0,0 +1,200 @@
+package cloud
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	config "finopsbridge/api/internal/config_"
+	models "finopsbridge/api/internal/models_"
+)
+
+// ErrBillingNotSupported is returned by FetchBilling for provider types without a billing integration
+var ErrBillingNotSupported = errors.New("billing not supported for this provider type")
+
+type billingEntry struct {
+	data      map[string]interface{}
+	expiresAt time.Time
+}
+
+// billingCache memoizes successful billing fetches per provider and billing period.
+// The key includes a hash of the provider's credentials, so changing them misses the cache.
+type billingCache struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	entries map[string]billingEntry
+}
+
+func newBillingCache() *billingCache {
+	return &billingCache{
+		now:     time.Now,
+		entries: make(map[string]billingEntry),
+	}
+}
+
+var billing = newBillingCache()
+
+// FetchBilling returns month-to-date billing data for a provider, served from cache for
+// cfg.BillingCacheTTLMinutes. forceRefresh bypasses the cache and stores the fresh result.
+func FetchBilling(ctx context.Context, provider models.CloudProvider, cfg *config.Config, forceRefresh bool) (map[string]interface{}, error) {
+	ttl := time.Duration(cfg.BillingCacheTTLMinutes) * time.Minute
+	return billing.get(ctx, provider, cfg, ttl, forceRefresh, fetchBilling)
+}
+
+// TestConnection checks a provider's credentials and billing access with a fresh billing fetch
+func TestConnection(ctx context.Context, provider models.CloudProvider, cfg *config.Config) error {
+	_, err := FetchBilling(ctx, provider, cfg, true)
+	return err
+}
+
+// SyncUpdates returns the CloudProvider column updates recording the outcome of a billing
+// fetch made at the given time. When the fetch's billing data is given, its caveats replace
+// the provider's sync warning.
+func SyncUpdates(billingData map[string]interface{}, err error, at time.Time) map[string]interface{} {
+	if err != nil {
+		return map[string]interface{}{
+			"last_sync_attempt_at": at,
+			"last_sync_error":      err.Error(),
+		}
+	}
+	updates := map[string]interface{}{
+		"last_sync_attempt_at": at,
+		"last_sync_at":         at,
+		"last_sync_error":      "",
+	}
+	if billingData != nil {
+		updates["last_sync_warning"] = SyncWarning(billingData)
+	}
+	return updates
+}
+
+// BillingTruncated reports whether a billing fetch stopped before reading every cost record,
+// so that its monthlySpend is only a lower bound
+func BillingTruncated(billingData map[string]interface{}) bool {
+	truncated, _ := billingData["truncated"].(bool)
+	return truncated
+}
+
+// SyncWarning describes the caveats of a successful billing fetch, or returns "" when there
+// are none
+func SyncWarning(billingData map[string]interface{}) string {
+	if BillingTruncated(billingData) {
+		return "usage details were truncated, so month-to-date spend is a lower bound and spend limits aren't evaluated; set chargesScope to read pre-aggregated charges"
+	}
+	return ""
+}
+
+// HasBillingData reports whether a billing fetch found any cost records. Without them, a
+// monthlySpend of 0 means no data has arrived yet rather than zero spend. Billing data without
+// the hasData flag is taken at face value.
+func HasBillingData(billingData map[string]interface{}) bool {
+	hasData, ok := billingData["hasData"].(bool)
+	return !ok || hasData
+}
+
+// MonthlySpend returns the month-to-date spend to store for a provider after a billing fetch,
+// and whether to store it. A fetch without data doesn't replace spend already known for the
+// current month, as cost data often lags; known spend from an earlier month is reset.
+func MonthlySpend(billingData map[string]interface{}, provider models.CloudProvider, now time.Time) (float64, bool) {
+	spend, ok := billingData["monthlySpend"].(float64)
+	if !ok {
+		return 0, false
+	}
+	if HasBillingData(billingData) || provider.MonthlySpend == 0 || provider.LastSyncAt == nil {
+		return spend, true
+	}
+	last, current := provider.LastSyncAt.UTC(), now.UTC()
+	if last.Year() == current.Year() && last.Month() == current.Month() {
+		return provider.MonthlySpend, false
+	}
+	return spend, true
+}
+
+// InvalidateBilling drops every cached billing entry and cost breakdown for a provider
+func InvalidateBilling(providerID string) {
+	billing.invalidate(providerID)
+	breakdowns.invalidate(providerID)
+}
+
+func fetchBilling(ctx context.Context, provider models.CloudProvider, cfg *config.Config) (map[string]interface{}, error) {
+	p, ok := ProviderFor(provider.Type)
+	if !ok {
+		return nil, ErrBillingNotSupported
+	}
+	return p.FetchBilling(ctx, provider, cfg)
+}
+
+func (bc *billingCache) key(provider models.CloudProvider, now time.Time) string {
+	sum := sha256.Sum256([]byte(provider.Credentials))
+	return provider.ID + "|" + now.UTC().Format("2006-01") + "|" + hex.EncodeToString(sum[:8])
+}
+
+func (bc *billingCache) get(ctx context.Context, provider models.CloudProvider, cfg *config.Config, ttl time.Duration, forceRefresh bool,
+	fetch func(context.Context, models.CloudProvider, *config.Config) (map[string]interface{}, error)) (map[string]interface{}, error) {
+	if ttl <= 0 {
+		return fetch(ctx, provider, cfg)
+	}
+
+	now := bc.now()
+	key := bc.key(provider, now)
+
+	if !forceRefresh {
+		bc.mu.Lock()
+		entry, exists := bc.entries[key]
+		bc.mu.Unlock()
+		if exists && now.Before(entry.expiresAt) {
+			return copyBillingData(entry.data), nil
+		}
+	}
+
+	// Errors are not cached so a fixed credential or transient outage recovers on the next run
+	data, err := fetch(ctx, provider, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	bc.mu.Lock()
+	// Drop entries for the provider's previous credentials or billing periods
+	for k := range bc.entries {
+		if k != key && billingKeyProvider(k) == provider.ID {
+			delete(bc.entries, k)
+		}
+	}
+	bc.entries[key] = billingEntry{data: data, expiresAt: now.Add(ttl)}
+	bc.mu.Unlock()
+
+	return copyBillingData(data), nil
+}
+
+func (bc *billingCache) invalidate(providerID string) {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	for k := range bc.entries {
+		if billingKeyProvider(k) == providerID {
+			delete(bc.entries, k)
+		}
+	}
+}
+
+// billingKeyProvider returns the provider ID portion of a cache key
+func billingKeyProvider(key string) string {
+	for i := 0; i < len(key); i++ {
+		if key[i] == '|' {
+			return key[:i]
+		}
+	}
+	return key
+}
+
+// copyBillingData returns a shallow copy so callers can't mutate the cached map
+func copyBillingData(data map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		copied[k] = v
+	}
+	return copied
+}
@@ -0,0 +1,167 @@
+package cloud
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	config "finopsbridge/api/internal/config_"
+	models "finopsbridge/api/internal/models_"
+)
+
+// countingFetch returns a billing fetch that reports spend and counts its calls
+func countingFetch(calls *int, err error) func(context.Context, models.CloudProvider, *config.Config) (map[string]interface{}, error) {
+	return func(context.Context, models.CloudProvider, *config.Config) (map[string]interface{}, error) {
+		*calls++
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"monthlySpend": float64(*calls)}, nil
+	}
+}
+
+func TestBillingCacheServesWithinTTL(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	bc := newBillingCache()
+	bc.now = func() time.Time { return now }
+	provider := models.CloudProvider{ID: "p1", Credentials: `{"key":"a"}`}
+	calls := 0
+	fetch := countingFetch(&calls, nil)
+
+	first, _ := bc.get(context.Background(), provider, nil, time.Hour, false, fetch)
+	first["monthlySpend"] = 999.0
+	second, _ := bc.get(context.Background(), provider, nil, time.Hour, false, fetch)
+	if calls != 1 || second["monthlySpend"] != 1.0 {
+		t.Errorf("got %v after %d fetches; want the cached, unmodified data", second, calls)
+	}
+
+	if refreshed, _ := bc.get(context.Background(), provider, nil, time.Hour, true, fetch); refreshed["monthlySpend"] != 2.0 {
+		t.Errorf("forceRefresh should bypass the cache, got %v", refreshed)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if expired, _ := bc.get(context.Background(), provider, nil, time.Hour, false, fetch); expired["monthlySpend"] != 3.0 {
+		t.Errorf("an expired entry should be refetched, got %v", expired)
+	}
+}
+
+func TestBillingCacheKeys(t *testing.T) {
+	now := time.Date(2026, 3, 31, 23, 0, 0, 0, time.UTC)
+	bc := newBillingCache()
+	bc.now = func() time.Time { return now }
+	provider := models.CloudProvider{ID: "p1", Credentials: `{"key":"a"}`}
+	calls := 0
+	fetch := countingFetch(&calls, nil)
+
+	bc.get(context.Background(), provider, nil, 24*time.Hour, false, fetch)
+
+	// New credentials miss the cache and replace the old entry
+	provider.Credentials = `{"key":"b"}`
+	bc.get(context.Background(), provider, nil, 24*time.Hour, false, fetch)
+	if calls != 2 || len(bc.entries) != 1 {
+		t.Errorf("got %d fetches and %d entries, want 2 and 1", calls, len(bc.entries))
+	}
+
+	// So does a new billing period, even within the TTL
+	now = now.Add(2 * time.Hour)
+	bc.get(context.Background(), provider, nil, 24*time.Hour, false, fetch)
+	if calls != 3 {
+		t.Errorf("a new month should refetch, got %d fetches", calls)
+	}
+
+	bc.invalidate("p1")
+	if len(bc.entries) != 0 {
+		t.Errorf("invalidate left %d entries", len(bc.entries))
+	}
+}
+
+func TestBillingCacheDoesNotCacheErrors(t *testing.T) {
+	bc := newBillingCache()
+	provider := models.CloudProvider{ID: "p1"}
+	calls := 0
+	fetch := countingFetch(&calls, errors.New("throttled"))
+
+	for i := 0; i < 2; i++ {
+		if _, err := bc.get(context.Background(), provider, nil, time.Hour, false, fetch); err == nil {
+			t.Fatal("expected the fetch error")
+		}
+	}
+	if calls != 2 {
+		t.Errorf("got %d fetches, want a retry after an error", calls)
+	}
+}
+
+func TestBillingKeyProvider(t *testing.T) {
+	if got := billingKeyProvider("p1|2026-03|abcd"); got != "p1" {
+		t.Errorf("got %q", got)
+	}
+	if got := billingKeyProvider("p1"); got != "p1" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestSyncUpdates(t *testing.T) {
+	at := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	failed := SyncUpdates(nil, errors.New("access denied"), at)
+	if failed["last_sync_error"] != "access denied" || failed["last_sync_attempt_at"] != at {
+		t.Errorf("a failed fetch should record the error and attempt time, got %v", failed)
+	}
+	if _, ok := failed["last_sync_at"]; ok {
+		t.Error("a failed fetch should keep the last successful sync time")
+	}
+
+	succeeded := SyncUpdates(nil, nil, at)
+	if succeeded["last_sync_error"] != "" || succeeded["last_sync_at"] != at {
+		t.Errorf("a successful fetch should clear the error and record the sync time, got %v", succeeded)
+	}
+	if _, ok := succeeded["last_sync_warning"]; ok {
+		t.Error("the sync warning should be left alone without billing data")
+	}
+
+	withData := SyncUpdates(map[string]interface{}{"monthlySpend": 10.0}, nil, at)
+	if warning, ok := withData["last_sync_warning"]; !ok || warning != "" {
+		t.Errorf("billing data without caveats should clear the sync warning, got %v", withData)
+	}
+}
+
+func TestHasBillingData(t *testing.T) {
+	if !HasBillingData(map[string]interface{}{"monthlySpend": 0.0}) {
+		t.Error("billing data without the flag should count as data, for providers that don't report it")
+	}
+	if !HasBillingData(map[string]interface{}{"hasData": true}) {
+		t.Error("hasData true should count as data")
+	}
+	if HasBillingData(map[string]interface{}{"hasData": false}) {
+		t.Error("hasData false should not count as data")
+	}
+}
+
+func TestMonthlySpend(t *testing.T) {
+	now := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+	earlierThisMonth := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	lastMonth := time.Date(2026, 2, 27, 12, 0, 0, 0, time.UTC)
+	empty := map[string]interface{}{"monthlySpend": 0.0, "hasData": false}
+
+	tests := []struct {
+		name        string
+		billingData map[string]interface{}
+		provider    models.CloudProvider
+		want        float64
+		wantStore   bool
+	}{
+		{"no spend reported", map[string]interface{}{}, models.CloudProvider{MonthlySpend: 40}, 0, false},
+		{"spend with data", map[string]interface{}{"monthlySpend": 55.0, "hasData": true}, models.CloudProvider{MonthlySpend: 40, LastSyncAt: &earlierThisMonth}, 55, true},
+		{"no data keeps this month's spend", empty, models.CloudProvider{MonthlySpend: 40, LastSyncAt: &earlierThisMonth}, 40, false},
+		{"no data resets last month's spend", empty, models.CloudProvider{MonthlySpend: 40, LastSyncAt: &lastMonth}, 0, true},
+		{"no data without known spend", empty, models.CloudProvider{LastSyncAt: &earlierThisMonth}, 0, true},
+		{"no data without a sync", empty, models.CloudProvider{MonthlySpend: 40}, 0, true},
+	}
+	for _, tt := range tests {
+		got, store := MonthlySpend(tt.billingData, tt.provider, now)
+		if got != tt.want || store != tt.wantStore {
+			t.Errorf("%s: got %v, %v; want %v, %v", tt.name, got, store, tt.want, tt.wantStore)
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	config "finopsbridge/api/internal/config_"
+	models "finopsbridge/api/internal/models_"
+
+	"cloud.google.com/go/bigquery"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/costexplorer"
+	ocicommon "github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/usageapi"
+)
+
+// ErrBreakdownNotSupported is returned for provider types without a cost breakdown implementation
+var ErrBreakdownNotSupported = errors.New("cost breakdown not supported for this provider type")
+
+// CostBreakdownItem is the month-to-date cost attributed to one service, SKU, or other grouping key
+type CostBreakdownItem struct {
+	Key      string  `json:"key"`
+	Cost     float64 `json:"cost"`
+	Currency string  `json:"currency"`
+}
+
+// FetchCostBreakdown returns month-to-date cost grouped by the given dimension (e.g. "service"),
+// served from cache for cfg.BillingCacheTTLMinutes
+func FetchCostBreakdown(ctx context.Context, provider models.CloudProvider, cfg *config.Config, groupBy string) ([]CostBreakdownItem, error) {
+	p, ok := capability[CostBreakdownProvider](provider.Type)
+	if !ok {
+		return nil, ErrBreakdownNotSupported
+	}
+	ttl := time.Duration(cfg.BillingCacheTTLMinutes) * time.Minute
+	return breakdowns.get(ctx, provider, cfg, groupBy, ttl, p.FetchCostBreakdown)
+}
+
+// TagGroupBy returns the FetchCostBreakdown groupBy that attributes a provider type's cost to
+// the values of a tag or label key, and false for provider types without tag breakdowns
+func TagGroupBy(providerType, key string) (string, bool) {
+	p, ok := capability[TagBreakdownProvider](providerType)
+	if !ok {
+		return "", false
+	}
+	return p.TagGroupBy(key), true
+}
+
+// FetchAWSCostBreakdown fetches AWS cost grouped by "service" or by a cost allocation tag's
+// value with "tag:<key>" (e.g. "tag:team") from Cost Explorer. The tag must be activated as a
+// cost allocation tag in the billing console for Cost Explorer to group by it.
+func FetchAWSCostBreakdown(ctx context.Context, provider models.CloudProvider, cfg *config.Config, groupBy string) ([]CostBreakdownItem, error) {
+	if groupBy == "" {
+		groupBy = "service"
+	}
+
+	tagKey, byTag := strings.CutPrefix(groupBy, "tag:")
+	if byTag && (tagKey == "" || len(tagKey) > 128) {
+		return nil, fmt.Errorf("invalid AWS tag key %q", tagKey)
+	}
+	if !byTag && groupBy != "service" {
+		return nil, fmt.Errorf("unsupported AWS groupBy %q (expected service or tag:<key>)", groupBy)
+	}
+
+	var credentials map[string]interface{}
+	json.Unmarshal([]byte(provider.Credentials), &credentials)
+	metric, err := awsCostMetric(credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	sess, err := newAWSSession(provider, cfg)
+	if err != nil {
+		return nil, err
+	}
+	ce := costexplorer.New(sess)
+
+	group := &costexplorer.GroupDefinition{Type: aws.String("DIMENSION"), Key: aws.String("SERVICE")}
+	if byTag {
+		group = &costexplorer.GroupDefinition{Type: aws.String("TAG"), Key: aws.String(tagKey)}
+	}
+
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	input := awsCostAndUsageInput(metric, start, now, group)
+
+	var groups []*costexplorer.Group
+	for {
+		result, err := ce.GetCostAndUsageWithContext(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query Cost Explorer: %w", err)
+		}
+		for _, period := range result.ResultsByTime {
+			groups = append(groups, period.Groups...)
+		}
+		if result.NextPageToken == nil || *result.NextPageToken == "" {
+			break
+		}
+		input.NextPageToken = result.NextPageToken
+	}
+
+	return aggregateAWSCostGroups(groups, metric, byTag, DefaultCurrency(cfg)), nil
+}
+
+// aggregateAWSCostGroups sums one metric of Cost Explorer groups per key, sorted by cost
+// descending. Tag group keys come back as "<key>$<value>"; untagged usage has an empty value
+// and is grouped under UnlabeledKey.
+func aggregateAWSCostGroups(groups []*costexplorer.Group, metric string, byTag bool, defaultCurrency string) []CostBreakdownItem {
+	totals := make(map[string]*CostBreakdownItem)
+	var keys []string
+
+	for _, group := range groups {
+		if len(group.Keys) == 0 || group.Keys[0] == nil {
+			continue
+		}
+		key := *group.Keys[0]
+		if byTag {
+			if _, value, found := strings.Cut(key, "$"); found {
+				key = value
+			}
+			if key == "" {
+				key = UnlabeledKey
+			}
+		}
+
+		var cost float64
+		currency := defaultCurrency
+		if value, ok := group.Metrics[metric]; ok && value.Amount != nil {
+			fmt.Sscanf(*value.Amount, "%f", &cost)
+			if value.Unit != nil && *value.Unit != "" {
+				currency = *value.Unit
+			}
+		}
+
+		entry, exists := totals[key]
+		if !exists {
+			entry = &CostBreakdownItem{Key: key, Currency: currency}
+			totals[key] = entry
+			keys = append(keys, key)
+		}
+		entry.Cost += cost
+	}
+
+	result := make([]CostBreakdownItem, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, *totals[key])
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Cost > result[j].Cost
+	})
+	return result
+}
+
+// FetchOCICostBreakdown fetches OCI cost grouped by "service", "skuName" or "compartment" using the
+// Usage API. Compartment keys are name paths below the provider's compartment.
+func FetchOCICostBreakdown(ctx context.Context, provider models.CloudProvider, cfg *config.Config, groupBy string) ([]CostBreakdownItem, error) {
+	if groupBy == "" {
+		groupBy = "service"
+	}
+	if groupBy != "service" && groupBy != "skuName" && groupBy != "compartment" {
+		return nil, fmt.Errorf("unsupported OCI groupBy %q (expected service, skuName or compartment)", groupBy)
+	}
+
+	var credentials map[string]interface{}
+	if err := json.Unmarshal([]byte(provider.Credentials), &credentials); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials: %w", err)
+	}
+
+	tenancyOCID, _ := credentials["tenancyOcid"].(string)
+	userOCID, _ := credentials["userOcid"].(string)
+	fingerprint, _ := credentials["fingerprint"].(string)
+	privateKey, _ := credentials["privateKey"].(string)
+	region, _ := credentials["region"].(string)
+	compartmentOCID, _ := credentials["compartmentOcid"].(string)
+
+	if tenancyOCID == "" || userOCID == "" || fingerprint == "" || privateKey == "" {
+		return nil, fmt.Errorf("missing OCI credentials (tenancyOcid, userOcid, fingerprint, privateKey)")
+	}
+
+	if region == "" {
+		region = "us-ashburn-1"
+	}
+	if compartmentOCID == "" {
+		compartmentOCID = tenancyOCID
+	}
+
+	configProvider := ocicommon.NewRawConfigurationProvider(
+		tenancyOCID, userOCID, region, fingerprint, privateKey, nil,
+	)
+
+	now := time.Now()
+	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	if groupBy == "compartment" {
+		compartments, _, err := fetchOCICompartmentCosts(ctx, configProvider, tenancyOCID, compartmentOCID, cfg.OCIMaxCompartmentDepth, DefaultCurrency(cfg),
+			usageapi.RequestSummarizedUsagesDetails{
+				TimeUsageStarted: &ocicommon.SDKTime{Time: startOfMonth},
+				TimeUsageEnded:   &ocicommon.SDKTime{Time: now},
+				Granularity:      usageapi.RequestSummarizedUsagesDetailsGranularityMonthly,
+				QueryType:        usageapi.RequestSummarizedUsagesDetailsQueryTypeCost,
+			})
+		if err != nil {
+			return nil, err
+		}
+
+		// Direct cost per compartment, so items sum to the provider's total
+		items := make([]CostBreakdownItem, 0, len(compartments))
+		for _, compartment := range compartments {
+			items = append(items, CostBreakdownItem{Key: compartment.Path, Cost: compartment.Cost, Currency: compartment.Currency})
+		}
+		sort.SliceStable(items, func(i, j int) bool {
+			return items[i].Cost > items[j].Cost
+		})
+		return items, nil
+	}
+
+	usageClient, err := usageapi.NewUsageapiClientWithConfigurationProvider(configProvider)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OCI usage client: %w", err)
+	}
+
+	request := usageapi.RequestSummarizedUsagesRequest{
+		RequestSummarizedUsagesDetails: usageapi.RequestSummarizedUsagesDetails{
+			TenantId:         &tenancyOCID,
+			TimeUsageStarted: &ocicommon.SDKTime{Time: startOfMonth},
+			TimeUsageEnded:   &ocicommon.SDKTime{Time: now},
+			Granularity:      usageapi.RequestSummarizedUsagesDetailsGranularityMonthly,
+			QueryType:        usageapi.RequestSummarizedUsagesDetailsQueryTypeCost,
+			GroupBy:          []string{groupBy},
+			CompartmentDepth: ocicommon.Float32(1),
+		},
+	}
+
+	response, err := usageClient.RequestSummarizedUsages(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get OCI usage data: %w", err)
+	}
+
+	return aggregateOCIUsageItems(response.Items, groupBy, DefaultCurrency(cfg)), nil
+}
+
+// aggregateOCIUsageItems sums grouped OCI usage summaries per group key, sorted by cost descending
+func aggregateOCIUsageItems(items []usageapi.UsageSummary, groupBy, defaultCurrency string) []CostBreakdownItem {
+	totals := make(map[string]*CostBreakdownItem)
+	var keys []string
+
+	for _, item := range items {
+		key := "unknown"
+		switch groupBy {
+		case "service":
+			if item.Service != nil && *item.Service != "" {
+				key = *item.Service
+			}
+		case "skuName":
+			if item.SkuName != nil && *item.SkuName != "" {
+				key = *item.SkuName
+			}
+		}
+
+		entry, exists := totals[key]
+		if !exists {
+			entry = &CostBreakdownItem{Key: key, Currency: defaultCurrency}
+			totals[key] = entry
+			keys = append(keys, key)
+		}
+		if item.ComputedAmount != nil {
+			entry.Cost += float64(*item.ComputedAmount)
+		}
+		if item.Currency != nil && *item.Currency != "" {
+			entry.Currency = *item.Currency
+		}
+	}
+
+	result := make([]CostBreakdownItem, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, *totals[key])
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Cost > result[j].Cost
+	})
+
+	return result
+}
+
+// UnlabeledKey groups costs from usage without the requested tag or label
+const UnlabeledKey = "(unlabeled)"
+
+// gcpLabelKeyPattern matches valid GCP label keys
+var gcpLabelKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,62}$`)
+
+// FetchGCPCostBreakdown fetches GCP cost grouped by "service", by "project", or by a label value
+// with "label:<key>" (e.g. "label:team"), from the BigQuery billing export configured in the
+// provider's credentials. Costs cover the provider's folder or organization scope when set.
+func FetchGCPCostBreakdown(ctx context.Context, provider models.CloudProvider, cfg *config.Config, groupBy string) ([]CostBreakdownItem, error) {
+	if groupBy == "" {
+		groupBy = "service"
+	}
+
+	labelKey, byLabel := strings.CutPrefix(groupBy, "label:")
+	if byLabel && !gcpLabelKeyPattern.MatchString(labelKey) {
+		return nil, fmt.Errorf("invalid GCP label key %q", labelKey)
+	}
+	if !byLabel && groupBy != "service" && groupBy != "project" {
+		return nil, fmt.Errorf("unsupported GCP groupBy %q (expected service, project or label:<key>)", groupBy)
+	}
+
+	export, err := openGCPBillingExport(ctx, provider)
+	if errors.Is(err, errNoGCPBillingExport) {
+		// The Cloud Billing API has no cost data, so breakdowns need the BigQuery export
+		return nil, ErrBreakdownNotSupported
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer export.Close()
+
+	// The label key is a query parameter, not formatted into the SQL
+	switch {
+	case byLabel:
+		return export.monthToDateCosts(ctx, cfg, time.Now(), "label.value",
+			"LEFT JOIN UNNEST(billing.labels) AS label ON label.key = @labelKey",
+			[]bigquery.QueryParameter{{Name: "labelKey", Value: labelKey}}, UnlabeledKey)
+	case groupBy == "project":
+		return export.monthToDateCosts(ctx, cfg, time.Now(), "billing.project.id", "", nil, "unknown")
+	default:
+		return export.monthToDateCosts(ctx, cfg, time.Now(), "billing.service.description", "", nil, "unknown")
+	}
+}
@@ -0,0 +1,82 @@
+package cloud
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	config "finopsbridge/api/internal/config_"
+	models "finopsbridge/api/internal/models_"
+)
+
+type breakdownEntry struct {
+	items     []CostBreakdownItem
+	expiresAt time.Time
+}
+
+// breakdownCache memoizes successful cost breakdowns per provider, billing period and groupBy,
+// like billingCache, so repeated tag and service breakdowns don't each pay for a Cost
+// Explorer or BigQuery call
+type breakdownCache struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	entries map[string]breakdownEntry
+}
+
+func newBreakdownCache() *breakdownCache {
+	return &breakdownCache{
+		now:     time.Now,
+		entries: make(map[string]breakdownEntry),
+	}
+}
+
+var breakdowns = newBreakdownCache()
+
+func (bc *breakdownCache) get(ctx context.Context, provider models.CloudProvider, cfg *config.Config, groupBy string, ttl time.Duration,
+	fetch func(context.Context, models.CloudProvider, *config.Config, string) ([]CostBreakdownItem, error)) ([]CostBreakdownItem, error) {
+	if ttl <= 0 {
+		return fetch(ctx, provider, cfg, groupBy)
+	}
+
+	now := bc.now()
+	period := billing.key(provider, now)
+	key := period + "|" + groupBy
+
+	bc.mu.Lock()
+	entry, exists := bc.entries[key]
+	bc.mu.Unlock()
+	if exists && now.Before(entry.expiresAt) {
+		return append([]CostBreakdownItem(nil), entry.items...), nil
+	}
+
+	// Errors are not cached, as with billing
+	items, err := fetch(ctx, provider, cfg, groupBy)
+	if err != nil {
+		return nil, err
+	}
+
+	bc.mu.Lock()
+	// Drop expired entries and those for the provider's previous credentials or billing periods
+	for k, e := range bc.entries {
+		stale := billingKeyProvider(k) == provider.ID && !strings.HasPrefix(k, period+"|")
+		if stale || !now.Before(e.expiresAt) {
+			delete(bc.entries, k)
+		}
+	}
+	bc.entries[key] = breakdownEntry{items: items, expiresAt: now.Add(ttl)}
+	bc.mu.Unlock()
+
+	return append([]CostBreakdownItem(nil), items...), nil
+}
+
+func (bc *breakdownCache) invalidate(providerID string) {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	for k := range bc.entries {
+		if billingKeyProvider(k) == providerID {
+			delete(bc.entries, k)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package cloud
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/costexplorer"
+	ocicommon "github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/usageapi"
+)
+
+func ociUsage(service, sku string, amount float32, currency string) usageapi.UsageSummary {
+	item := usageapi.UsageSummary{ComputedAmount: ocicommon.Float32(amount)}
+	if service != "" {
+		item.Service = ocicommon.String(service)
+	}
+	if sku != "" {
+		item.SkuName = ocicommon.String(sku)
+	}
+	if currency != "" {
+		item.Currency = ocicommon.String(currency)
+	}
+	return item
+}
+
+func TestAggregateOCIUsageItemsByService(t *testing.T) {
+	items := []usageapi.UsageSummary{
+		ociUsage("Compute", "E4 OCPU", 10, "USD"),
+		ociUsage("Block Storage", "Block Volume", 4, "USD"),
+		ociUsage("Compute", "E4 Memory", 5, "USD"),
+		ociUsage("", "Unknown SKU", 1, ""),
+	}
+
+	got := aggregateOCIUsageItems(items, "service", "EUR")
+	want := []CostBreakdownItem{
+		{Key: "Compute", Cost: 15, Currency: "USD"},
+		{Key: "Block Storage", Cost: 4, Currency: "USD"},
+		{Key: "unknown", Cost: 1, Currency: "EUR"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestAggregateOCIUsageItemsBySku(t *testing.T) {
+	items := []usageapi.UsageSummary{
+		ociUsage("Compute", "E4 OCPU", 10, "USD"),
+		ociUsage("Compute", "E4 Memory", 5, "USD"),
+		ociUsage("Compute", "E4 OCPU", 2, "USD"),
+	}
+
+	got := aggregateOCIUsageItems(items, "skuName", "USD")
+	want := []CostBreakdownItem{
+		{Key: "E4 OCPU", Cost: 12, Currency: "USD"},
+		{Key: "E4 Memory", Cost: 5, Currency: "USD"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestAggregateOCIUsageItemsWithoutAmount(t *testing.T) {
+	item := ociUsage("Compute", "", 0, "USD")
+	item.ComputedAmount = nil
+
+	got := aggregateOCIUsageItems([]usageapi.UsageSummary{item}, "service", "USD")
+	if len(got) != 1 || got[0].Cost != 0 {
+		t.Errorf("a summary without an amount should count as zero, got %+v", got)
+	}
+}
+
+func awsGroup(key, metric, amount, unit string) *costexplorer.Group {
+	return &costexplorer.Group{
+		Keys: []*string{aws.String(key)},
+		Metrics: map[string]*costexplorer.MetricValue{
+			metric: {Amount: aws.String(amount), Unit: aws.String(unit)},
+		},
+	}
+}
+
+func TestAggregateAWSCostGroupsByTag(t *testing.T) {
+	groups := []*costexplorer.Group{
+		awsGroup("team$platform", "UnblendedCost", "12.5", "USD"),
+		awsGroup("team$", "UnblendedCost", "3", "USD"),
+		awsGroup("team$data", "UnblendedCost", "20", "USD"),
+		awsGroup("team$platform", "UnblendedCost", "7.5", "USD"),
+		{Keys: nil},
+	}
+
+	got := aggregateAWSCostGroups(groups, "UnblendedCost", true, "USD")
+	want := []CostBreakdownItem{
+		{Key: "platform", Cost: 20, Currency: "USD"},
+		{Key: "data", Cost: 20, Currency: "USD"},
+		{Key: UnlabeledKey, Cost: 3, Currency: "USD"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestAggregateAWSCostGroupsByService(t *testing.T) {
+	groups := []*costexplorer.Group{
+		awsGroup("Amazon EC2", "UnblendedCost", "10", "USD"),
+		awsGroup("Amazon S3", "AmortizedCost", "4", "USD"),
+	}
+
+	got := aggregateAWSCostGroups(groups, "UnblendedCost", false, "EUR")
+	want := []CostBreakdownItem{
+		{Key: "Amazon EC2", Cost: 10, Currency: "USD"},
+		{Key: "Amazon S3", Cost: 0, Currency: "EUR"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestTagGroupBy(t *testing.T) {
+	tests := []struct {
+		providerType, want string
+		ok                 bool
+	}{
+		{"aws", "tag:team", true},
+		{"gcp", "label:team", true},
+		{"oci", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := TagGroupBy(tt.providerType, "team")
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("TagGroupBy(%q) = %q, %v; want %q, %v", tt.providerType, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package cloud
+
+import (
+	"strings"
+)
+
+// Cost categories that provider services are normalized into for cross-provider reporting
+const (
+	CategoryCompute  = "compute"
+	CategoryStorage  = "storage"
+	CategoryNetwork  = "network"
+	CategoryDatabase = "database"
+	CategoryAI       = "ai"
+	CategoryOther    = "other"
+)
+
+// serviceCategoryKeywords maps lowercase substrings of provider service names to categories.
+// Checked in order, so more specific keywords (e.g. "block volume" before "compute") come first.
+var serviceCategoryKeywords = []struct {
+	keyword  string
+	category string
+}{
+	// AI before compute so "AI Compute"/GPU services and managed ML land in ai
+	{"generative ai", CategoryAI},
+	{"openai", CategoryAI},
+	{"bedrock", CategoryAI},
+	{"sagemaker", CategoryAI},
+	{"vertex", CategoryAI},
+	{"machine learning", CategoryAI},
+	{"cognitive", CategoryAI},
+	{"data science", CategoryAI},
+	{"watson", CategoryAI},
+
+	// Database before storage so "Database Storage" counts as database
+	{"database", CategoryDatabase},
+	{"rds", CategoryDatabase},
+	{"dynamodb", CategoryDatabase},
+	{"cosmos", CategoryDatabase},
+	{"sql", CategoryDatabase},
+	{"spanner", CategoryDatabase},
+	{"bigtable", CategoryDatabase},
+	{"firestore", CategoryDatabase},
+	{"mysql", CategoryDatabase},
+	{"postgres", CategoryDatabase},
+	{"cloudant", CategoryDatabase},
+
+	{"block volume", CategoryStorage},
+	{"block storage", CategoryStorage},
+	{"object storage", CategoryStorage},
+	{"file storage", CategoryStorage},
+	{"storage", CategoryStorage},
+	{"s3", CategoryStorage},
+	{"ebs", CategoryStorage},
+	{"efs", CategoryStorage},
+	{"glacier", CategoryStorage},
+	{"backup", CategoryStorage},
+
+	{"network", CategoryNetwork},
+	{"load balancer", CategoryNetwork},
+	{"bandwidth", CategoryNetwork},
+	{"data transfer", CategoryNetwork},
+	{"egress", CategoryNetwork},
+	{"cdn", CategoryNetwork},
+	{"cloudfront", CategoryNetwork},
+	{"dns", CategoryNetwork},
+	{"route 53", CategoryNetwork},
+	{"vpn", CategoryNetwork},
+	{"fastconnect", CategoryNetwork},
+	{"nat gateway", CategoryNetwork},
+	{"virtual cloud network", CategoryNetwork},
+	{"vpc", CategoryNetwork},
+
+	{"compute", CategoryCompute},
+	{"ec2", CategoryCompute},
+	{"virtual machine", CategoryCompute},
+	{"lambda", CategoryCompute},
+	{"functions", CategoryCompute},
+	{"kubernetes", CategoryCompute},
+	{"container", CategoryCompute},
+	{"app service", CategoryCompute},
+	{"fargate", CategoryCompute},
+	{"gpu", CategoryCompute},
+}
+
+// ServiceCategory normalizes a provider's service name into a common cost category
+func ServiceCategory(service string) string {
+	name := strings.ToLower(service)
+	for _, entry := range serviceCategoryKeywords {
+		if strings.Contains(name, entry.keyword) {
+			return entry.category
+		}
+	}
+	return CategoryOther
+}
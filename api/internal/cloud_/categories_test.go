@@ -0,0 +1,27 @@
+package cloud
+
+import "testing"
+
+func TestServiceCategory(t *testing.T) {
+	tests := map[string]string{
+		"Amazon Elastic Compute Cloud - Compute": CategoryCompute,
+		"Virtual Machines":                       CategoryCompute,
+		"Amazon Simple Storage Service (S3)":     CategoryStorage,
+		"Block Volume":                           CategoryStorage,
+		"Amazon Relational Database Service":     CategoryDatabase,
+		"Azure Database for PostgreSQL":          CategoryDatabase,
+		"Database Storage":                       CategoryDatabase,
+		"Amazon CloudFront":                      CategoryNetwork,
+		"Virtual Cloud Network":                  CategoryNetwork,
+		"Amazon SageMaker":                       CategoryAI,
+		"Vertex AI":                              CategoryAI,
+		"OCI Generative AI":                      CategoryAI,
+		"Support":                                CategoryOther,
+		"":                                       CategoryOther,
+	}
+	for service, want := range tests {
+		if got := ServiceCategory(service); got != want {
+			t.Errorf("ServiceCategory(%q) = %q, want %q", service, got, want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package cloud
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	if got := clock.Now(); !got.Equal(start) {
+		t.Errorf("got %v, want %v", got, start)
+	}
+	if got := clock.Now(); !got.Equal(start) {
+		t.Error("a fake clock should stand still")
+	}
+
+	clock.Advance(90 * time.Minute)
+	if got := clock.Now(); !got.Equal(start.Add(90 * time.Minute)) {
+		t.Errorf("after Advance got %v", got)
+	}
+
+	weekend := time.Date(2026, 3, 7, 10, 0, 0, 0, time.UTC)
+	clock.Set(weekend)
+	if got := clock.Now(); !got.Equal(weekend) {
+		t.Errorf("after Set got %v, want %v", got, weekend)
+	}
+}
+
+func TestFakeClockConcurrentAdvance(t *testing.T) {
+	start := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			clock.Advance(time.Minute)
+			clock.Now()
+		}()
+	}
+	wg.Wait()
+
+	if got := clock.Now(); !got.Equal(start.Add(50 * time.Minute)) {
+		t.Errorf("got %v, want every advance applied", got)
+	}
+}
+
+func TestScheduleFollowsClock(t *testing.T) {
+	schedule, err := ParseSchedule(map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Monday 2026-03-02, 07:00 UTC
+	clock := NewFakeClock(time.Date(2026, 3, 2, 7, 0, 0, 0, time.UTC))
+
+	if schedule.IsBusinessHours(clock.Now()) {
+		t.Error("07:00 should be outside business hours")
+	}
+	clock.Advance(2 * time.Hour)
+	if !schedule.IsBusinessHours(clock.Now()) {
+		t.Error("09:00 should be business hours")
+	}
+}
@@ -3,6 +3,7 @@ package cloud
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -11,7 +12,6 @@ import (
 	models "finopsbridge/api/internal/models_"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/cloudwatch"
 	"github.com/aws/aws-sdk-go/service/costexplorer"
 	"github.com/aws/aws-sdk-go/service/ec2"
@@ -20,7 +20,6 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/consumption/armconsumption"
 
-	"cloud.google.com/go/bigquery"
 	"google.golang.org/api/cloudbilling/v1"
 	compute "google.golang.org/api/compute/v1"
 	monitoring "google.golang.org/api/monitoring/v3"
@@ -34,7 +33,6 @@ import (
 	"github.com/IBM/platform-services-go-sdk/usagereportsv4"
 	"github.com/IBM/vpc-go-sdk/vpcv1"
 
-	"google.golang.org/api/iterator"
 )
 
 func FetchAWSBilling(ctx context.Context, provider models.CloudProvider, cfg *config.Config) (map[string]interface{}, error) {
@@ -42,49 +40,51 @@ func FetchAWSBilling(ctx context.Context, provider models.CloudProvider, cfg *co
 	json.Unmarshal([]byte(provider.Credentials), &credentials)
 
 	_, ok := credentials["roleArn"].(string)
-	if !ok {
+	if useInstanceRole, _ := credentials["useInstanceRole"].(bool); !ok && !useInstanceRole {
 		return nil, fmt.Errorf("missing roleArn in credentials")
 	}
 
+	metric, err := awsCostMetric(credentials)
+	if err != nil {
+		return nil, err
+	}
+	seriesOpts, err := parseAWSCostSeriesOptions(credentials)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create AWS session with assumed role
-	sess, err := session.NewSession(&aws.Config{
-		Region: aws.String(cfg.AWSRegion),
-	})
+	sess, err := newAWSSession(provider, cfg)
 	if err != nil {
 		return nil, err
 	}
 
 	// Use Cost Explorer to get billing data
 	ce := costexplorer.New(sess)
-	
-	// Get current month's spend
-	now := time.Now()
-	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
-	end := now
 
-	result, err := ce.GetCostAndUsage(&costexplorer.GetCostAndUsageInput{
-		TimePeriod: &costexplorer.DateInterval{
-			Start: aws.String(start.Format("2006-01-02")),
-			End:   aws.String(end.Format("2006-01-02")),
-		},
-		Granularity: aws.String("MONTHLY"),
-		Metrics:     []*string{aws.String("BlendedCost")},
-	})
+	// Get current month's spend, and any trailing months, in one query
+	now := time.Now()
+	start := awsCostSeriesStart(now, seriesOpts.LookbackMonths)
+	series, err := fetchAWSCostSeries(ctx, ce, metric, start, now, seriesOpts, DefaultCurrency(cfg))
 	if err != nil {
 		return nil, err
 	}
 
-	var monthlySpend float64
-	if len(result.ResultsByTime) > 0 {
-		if cost, exists := result.ResultsByTime[0].Total["BlendedCost"]; exists && cost.Amount != nil {
-			fmt.Sscanf(*cost.Amount, "%f", &monthlySpend)
-		}
+	monthlySpend, currency, hasData := seriesMonthToDate(series, time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC))
+	if !hasData {
+		currency = DefaultCurrency(cfg)
 	}
 
-	return map[string]interface{}{
+	billingData := map[string]interface{}{
 		"monthlySpend": monthlySpend,
-		"currency":      "USD",
-	}, nil
+		"currency":     currency,
+		"hasData":      hasData,
+	}
+	if seriesOpts.series() {
+		billingData["granularity"] = seriesOpts.Granularity
+		billingData["costSeries"] = series
+	}
+	return billingData, nil
 }
 
 func FetchAzureBilling(ctx context.Context, provider models.CloudProvider, cfg *config.Config) (map[string]interface{}, error) {
@@ -96,9 +96,9 @@ func FetchAzureBilling(ctx context.Context, provider models.CloudProvider, cfg *
 	tenantID, _ := credentials["tenantId"].(string)
 	clientID, _ := credentials["clientId"].(string)
 	clientSecret, _ := credentials["clientSecret"].(string)
-	subscriptionID := provider.SubscriptionID
+	subscriptionIDs := AzureSubscriptionIDs(provider)
 
-	if tenantID == "" || clientID == "" || clientSecret == "" || subscriptionID == "" {
+	if tenantID == "" || clientID == "" || clientSecret == "" || len(subscriptionIDs) == 0 {
 		return nil, fmt.Errorf("missing Azure credentials (tenantId, clientId, clientSecret) or subscriptionId")
 	}
 
@@ -108,66 +108,182 @@ func FetchAzureBilling(ctx context.Context, provider models.CloudProvider, cfg *
 		return nil, fmt.Errorf("failed to create Azure credential: %w", err)
 	}
 
+	// Get current month's date range
+	now := time.Now()
+	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	// A charges scope reads one pre-aggregated total instead of enumerating usage line items
+	if chargesScope, _ := credentials["chargesScope"].(string); chargesScope != "" {
+		return fetchAzureChargesBilling(ctx, cred, chargesScope, startOfMonth, now, cfg)
+	}
+
 	// Create consumption client for cost data
 	consumptionClient, err := armconsumption.NewUsageDetailsClient(cred, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create consumption client: %w", err)
 	}
 
-	// Get current month's date range
-	now := time.Now()
-	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
-
-	// Query scope for subscription-level costs
-	scope := fmt.Sprintf("/subscriptions/%s", subscriptionID)
-
 	// Build filter for current month
 	filter := fmt.Sprintf("properties/usageStart ge '%s' and properties/usageEnd le '%s'",
 		startOfMonth.Format("2006-01-02"),
 		now.Format("2006-01-02"))
 
-	var totalCost float64
-	currency := "USD"
+	// Costs are fetched per subscription so each one can be attributed
+	costs := make([]AzureSubscriptionCost, 0, len(subscriptionIDs))
+	for _, subscriptionID := range subscriptionIDs {
+		cost, err := fetchAzureSubscriptionCost(ctx, consumptionClient, subscriptionID, filter, DefaultCurrency(cfg))
+		if err != nil {
+			return nil, fmt.Errorf("subscription %s: %w", subscriptionID, err)
+		}
+		costs = append(costs, cost)
+	}
+
+	totalCost, currency := aggregateAzureSubscriptionCosts(costs, cfg)
+	hasData, truncated := false, false
+	for _, cost := range costs {
+		hasData = hasData || cost.hasData
+		truncated = truncated || cost.Truncated
+	}
+
+	billingData := map[string]interface{}{
+		"monthlySpend":  totalCost,
+		"currency":      currency,
+		"hasData":       hasData,
+		"subscriptions": costs,
+	}
+	if truncated {
+		billingData["truncated"] = true
+	}
+	return billingData, nil
+}
+
+// Bounds on enumerating one subscription's usage details. Pages hold up to 1000 records, so
+// the page cap allows 200,000 records a month before the sum is cut short.
+const (
+	maxAzureUsagePages = 200
+	azureUsageTimeout  = 3 * time.Minute
+)
+
+// fetchAzureSubscriptionCost sums one subscription's usage details matching filter. After
+// maxAzureUsagePages it stops and marks the cost truncated, a lower bound of the real spend;
+// pages taking longer than azureUsageTimeout in total fail the fetch.
+func fetchAzureSubscriptionCost(ctx context.Context, consumptionClient *armconsumption.UsageDetailsClient, subscriptionID, filter, defaultCurrency string) (AzureSubscriptionCost, error) {
+	cost := AzureSubscriptionCost{SubscriptionID: subscriptionID, Currency: defaultCurrency}
+
+	ctx, cancel := context.WithTimeout(ctx, azureUsageTimeout)
+	defer cancel()
+
+	// Query scope for subscription-level costs
+	scope := fmt.Sprintf("/subscriptions/%s", subscriptionID)
 
 	// List usage details and aggregate costs
 	pager := consumptionClient.NewListPager(scope, &armconsumption.UsageDetailsClientListOptions{
 		Filter: &filter,
 	})
 
-	for pager.More() {
+	for pages := 0; pager.More(); pages++ {
+		if pages >= maxAzureUsagePages {
+			fmt.Printf("Azure subscription %s has more than %d pages of usage details; month-to-date spend is truncated\n", subscriptionID, maxAzureUsagePages)
+			cost.Truncated = true
+			break
+		}
+
 		page, err := pager.NextPage(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get usage details: %w", err)
+			return cost, fmt.Errorf("failed to get usage details: %w", err)
 		}
 
 		for _, usage := range page.Value {
+			cost.hasData = true
 			// Handle legacy usage detail format
 			if legacyUsage, ok := usage.(*armconsumption.LegacyUsageDetail); ok {
 				if legacyUsage.Properties != nil && legacyUsage.Properties.Cost != nil {
-					totalCost += *legacyUsage.Properties.Cost
+					cost.MonthlySpend += *legacyUsage.Properties.Cost
 				}
-				if legacyUsage.Properties != nil && legacyUsage.Properties.Currency != nil {
-					currency = *legacyUsage.Properties.Currency
+				if legacyUsage.Properties != nil && legacyUsage.Properties.BillingCurrency != nil {
+					cost.Currency = *legacyUsage.Properties.BillingCurrency
 				}
 			}
 			// Handle modern usage detail format
 			if modernUsage, ok := usage.(*armconsumption.ModernUsageDetail); ok {
 				if modernUsage.Properties != nil && modernUsage.Properties.CostInBillingCurrency != nil {
-					totalCost += *modernUsage.Properties.CostInBillingCurrency
+					cost.MonthlySpend += *modernUsage.Properties.CostInBillingCurrency
 				}
 				if modernUsage.Properties != nil && modernUsage.Properties.BillingCurrencyCode != nil {
-					currency = *modernUsage.Properties.BillingCurrencyCode
+					cost.Currency = *modernUsage.Properties.BillingCurrencyCode
 				}
 			}
 		}
 	}
 
+	return cost, nil
+}
+
+// fetchAzureChargesBilling reads month-to-date charges from the Consumption charges summary of
+// an EA billing account, department or enrollment account, or an MCA billing profile or invoice
+// section. It's one call however much usage there is, but covers the whole scope, so spend
+// isn't broken down by subscription.
+func fetchAzureChargesBilling(ctx context.Context, cred *azidentity.ClientSecretCredential, scope string, start, now time.Time, cfg *config.Config) (map[string]interface{}, error) {
+	chargesClient, err := armconsumption.NewChargesClient(cred, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create charges client: %w", err)
+	}
+
+	startDate, endDate := start.Format("2006-01-02"), now.Format("2006-01-02")
+	resp, err := chargesClient.List(ctx, scope, &armconsumption.ChargesClientListOptions{
+		StartDate: &startDate,
+		EndDate:   &endDate,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get charges for %s: %w", scope, err)
+	}
+
+	total, currency := sumAzureCharges(resp.Value, DefaultCurrency(cfg))
 	return map[string]interface{}{
-		"monthlySpend": totalCost,
+		"monthlySpend": total,
 		"currency":     currency,
+		"hasData":      len(resp.Value) > 0,
+		"chargesScope": scope,
 	}, nil
 }
 
+// sumAzureCharges totals the Azure and Marketplace charges of charges summaries, in the
+// currency they report, or defaultCurrency when none does
+func sumAzureCharges(summaries []armconsumption.ChargeSummaryClassification, defaultCurrency string) (float64, string) {
+	total := 0.0
+	currency := defaultCurrency
+	for _, summary := range summaries {
+		switch charge := summary.(type) {
+		case *armconsumption.LegacyChargeSummary:
+			if charge.Properties == nil {
+				continue
+			}
+			for _, amount := range []*float64{charge.Properties.AzureCharges, charge.Properties.MarketplaceCharges} {
+				if amount != nil {
+					total += *amount
+				}
+			}
+			if charge.Properties.Currency != nil {
+				currency = *charge.Properties.Currency
+			}
+		case *armconsumption.ModernChargeSummary:
+			if charge.Properties == nil {
+				continue
+			}
+			for _, amount := range []*armconsumption.Amount{charge.Properties.AzureCharges, charge.Properties.MarketplaceCharges} {
+				if amount == nil || amount.Value == nil {
+					continue
+				}
+				total += *amount.Value
+				if amount.Currency != nil {
+					currency = *amount.Currency
+				}
+			}
+		}
+	}
+	return total, currency
+}
+
 func FetchGCPBilling(ctx context.Context, provider models.CloudProvider, cfg *config.Config) (map[string]interface{}, error) {
 	var credentials map[string]interface{}
 	if err := json.Unmarshal([]byte(provider.Credentials), &credentials); err != nil {
@@ -196,7 +312,7 @@ func FetchGCPBilling(ctx context.Context, provider models.CloudProvider, cfg *co
 	}
 
 	var totalCost float64
-	currency := "USD"
+	currency := DefaultCurrency(cfg)
 	billingEnabled := false
 
 	// If billing account ID is provided, get billing info
@@ -218,6 +334,7 @@ func FetchGCPBilling(ctx context.Context, provider models.CloudProvider, cfg *co
 	return map[string]interface{}{
 		"monthlySpend":       totalCost,
 		"currency":           currency,
+		"hasData":            false,
 		"billingAccountId":   billingAccountID,
 		"projectId":          projectID,
 		"billingEnabled":     billingEnabled,
@@ -228,109 +345,61 @@ func FetchGCPBilling(ctx context.Context, provider models.CloudProvider, cfg *co
 // FetchGCPBillingFromBigQuery fetches billing data from BigQuery export
 // This requires the billing export to be set up in GCP
 func FetchGCPBillingFromBigQuery(ctx context.Context, provider models.CloudProvider, cfg *config.Config) (map[string]interface{}, error) {
-	var credentials map[string]interface{}
-	if err := json.Unmarshal([]byte(provider.Credentials), &credentials); err != nil {
-		return nil, fmt.Errorf("failed to parse credentials: %w", err)
-	}
-
-	serviceAccountJSON, _ := credentials["serviceAccountKey"].(string)
-	billingDataset, _ := credentials["billingDataset"].(string) // e.g., "project.dataset.gcp_billing_export_v1"
-	billingTable, _ := credentials["billingTable"].(string)     // e.g., "gcp_billing_export_v1_XXXXXX_XXXXXX"
-	projectID := provider.ProjectID
-
-	if serviceAccountJSON == "" || projectID == "" {
-		return nil, fmt.Errorf("missing GCP credentials")
-	}
-
-	// If no billing dataset configured, fall back to basic billing API
-	if billingDataset == "" {
+	export, err := openGCPBillingExport(ctx, provider)
+	if errors.Is(err, errNoGCPBillingExport) {
+		// If no billing dataset configured, fall back to basic billing API
 		return FetchGCPBilling(ctx, provider, cfg)
 	}
-
-	// Create BigQuery client with service account credentials
-	bqClient, err := bigquery.NewClient(ctx, projectID, option.WithCredentialsJSON([]byte(serviceAccountJSON)))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create BigQuery client: %w", err)
+		return nil, err
 	}
-	defer bqClient.Close()
+	defer export.Close()
 
 	// Get current month's date range
 	now := time.Now()
 	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
 
-	// Build the billing table reference
-	// Format: project.dataset.table
-	tableRef := billingDataset
-	if billingTable != "" {
-		tableRef = fmt.Sprintf("%s.%s", billingDataset, billingTable)
-	}
-
-	// Query to get total cost for the project in the current month
-	query := fmt.Sprintf(`
-		SELECT
-			SUM(cost) as total_cost,
-			currency
-		FROM `+"`%s`"+`
-		WHERE project.id = @projectId
-		AND DATE(usage_start_time) >= @startDate
-		AND DATE(usage_start_time) <= @endDate
-		GROUP BY currency
-		ORDER BY total_cost DESC
-		LIMIT 1
-	`, tableRef)
-
-	q := bqClient.Query(query)
-	q.Parameters = []bigquery.QueryParameter{
-		{Name: "projectId", Value: projectID},
-		{Name: "startDate", Value: startOfMonth.Format("2006-01-02")},
-		{Name: "endDate", Value: now.Format("2006-01-02")},
-	}
-
-	// Run the query
-	it, err := q.Read(ctx)
+	// Costs are grouped per project, so folder and organization scopes can be broken down
+	projects, err := export.monthToDateCosts(ctx, cfg, now, "billing.project.id", "", nil, "unknown")
 	if err != nil {
 		// If BigQuery query fails, fall back to basic billing API
 		fmt.Printf("Warning: BigQuery query failed, falling back to basic API: %v\n", err)
 		return FetchGCPBilling(ctx, provider, cfg)
 	}
 
-	var totalCost float64
-	currency := "USD"
-
-	// Read the first result row
-	var row struct {
-		TotalCost float64 `bigquery:"total_cost"`
-		Currency  string  `bigquery:"currency"`
-	}
-
-	err = it.Next(&row)
-	if err == iterator.Done {
+	scope := export.scope
+	if len(projects) == 0 {
 		// No billing data found for this month
 		return map[string]interface{}{
 			"monthlySpend": 0.0,
-			"currency":     currency,
+			"currency":     DefaultCurrency(cfg),
+			"hasData":      false,
 			"source":       "bigquery",
+			"scope":        scope.Level,
 			"note":         "No billing data found for current month",
 		}, nil
 	}
-	if err != nil {
-		return nil, fmt.Errorf("failed to read BigQuery results: %w", err)
-	}
 
-	totalCost = row.TotalCost
-	if row.Currency != "" {
-		currency = row.Currency
+	totalCost, currency, unconverted := sumCostItems(projects, cfg)
+	data := map[string]interface{}{
+		"monthlySpend":   totalCost,
+		"currency":       currency,
+		"hasData":        true,
+		"source":         "bigquery",
+		"billingDataset": export.dataset,
+		"projectId":      export.projectID,
+		"scope":          scope.Level,
+		"periodStart":    startOfMonth.Format("2006-01-02"),
+		"periodEnd":      now.Format("2006-01-02"),
 	}
-
-	return map[string]interface{}{
-		"monthlySpend":     totalCost,
-		"currency":         currency,
-		"source":           "bigquery",
-		"billingDataset":   billingDataset,
-		"projectId":        projectID,
-		"periodStart":      startOfMonth.Format("2006-01-02"),
-		"periodEnd":        now.Format("2006-01-02"),
-	}, nil
+	if len(unconverted) > 0 {
+		data["unconvertedCurrencies"] = unconverted
+	}
+	if scope.Level != GCPScopeProject {
+		data["scopeId"] = scope.ID
+		data["projects"] = projects
+	}
+	return data, nil
 }
 
 // FetchOCIBilling fetches billing data from Oracle Cloud Infrastructure
@@ -370,12 +439,6 @@ func FetchOCIBilling(ctx context.Context, provider models.CloudProvider, cfg *co
 		nil, // passphrase
 	)
 
-	// Create Usage API client for cost data
-	usageClient, err := usageapi.NewUsageapiClientWithConfigurationProvider(configProvider)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create OCI usage client: %w", err)
-	}
-
 	// Get current month's date range
 	now := time.Now()
 	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
@@ -384,42 +447,28 @@ func FetchOCIBilling(ctx context.Context, provider models.CloudProvider, cfg *co
 	granularity := usageapi.RequestSummarizedUsagesDetailsGranularityMonthly
 	queryType := usageapi.RequestSummarizedUsagesDetailsQueryTypeCost
 
-	request := usageapi.RequestSummarizedUsagesRequest{
-		RequestSummarizedUsagesDetails: usageapi.RequestSummarizedUsagesDetails{
-			TenantId:      &tenancyOCID,
-			TimeUsageStarted: &ocicommon.SDKTime{Time: startOfMonth},
-			TimeUsageEnded:   &ocicommon.SDKTime{Time: now},
-			Granularity:      granularity,
-			QueryType:        queryType,
-			CompartmentDepth: ocicommon.Float32(1),
-		},
+	details := usageapi.RequestSummarizedUsagesDetails{
+		TenantId:         &tenancyOCID,
+		TimeUsageStarted: &ocicommon.SDKTime{Time: startOfMonth},
+		TimeUsageEnded:   &ocicommon.SDKTime{Time: now},
+		Granularity:      granularity,
+		QueryType:        queryType,
 	}
 
-	// Execute the request
-	response, err := usageClient.RequestSummarizedUsages(ctx, request)
+	// Walk the compartment tree so every sub-compartment's spend is covered and attributed
+	compartments, hasData, err := fetchOCICompartmentCosts(ctx, configProvider, tenancyOCID, compartmentOCID, cfg.OCIMaxCompartmentDepth, DefaultCurrency(cfg), details)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get OCI usage data: %w", err)
-	}
-
-	var totalCost float64
-	currency := "USD"
-
-	// Aggregate costs from response
-	for _, item := range response.Items {
-		if item.ComputedAmount != nil {
-			totalCost += float64(*item.ComputedAmount)
-		}
-		if item.Currency != nil {
-			currency = *item.Currency
-		}
+		return nil, err
 	}
 
 	return map[string]interface{}{
-		"monthlySpend":    totalCost,
-		"currency":        currency,
+		"monthlySpend":    compartments[0].TotalCost,
+		"currency":        compartments[0].Currency,
+		"hasData":         hasData,
 		"tenancyOcid":     tenancyOCID,
 		"compartmentOcid": compartmentOCID,
 		"region":          region,
+		"compartments":    compartments,
 	}, nil
 }
 
@@ -462,7 +511,8 @@ func FetchIBMBilling(ctx context.Context, provider models.CloudProvider, cfg *co
 	}
 
 	var totalCost float64
-	currency := "USD"
+	currency := DefaultCurrency(cfg)
+	hasData := len(accountUsage.Resources) > 0
 
 	// Aggregate costs from resources
 	if accountUsage.Resources != nil {
@@ -480,31 +530,24 @@ func FetchIBMBilling(ctx context.Context, provider models.CloudProvider, cfg *co
 	return map[string]interface{}{
 		"monthlySpend": totalCost,
 		"currency":     currency,
+		"hasData":      hasData,
 		"accountId":    accountID,
 		"billingMonth": billingMonth,
 	}, nil
 }
 
-func StopNonEssentialResources(ctx context.Context, provider models.CloudProvider, cfg *config.Config) error {
-	switch provider.Type {
-	case "aws":
-		return stopAWSNonEssentialResources(ctx, provider, cfg)
-	case "azure":
-		return stopAzureNonEssentialResources(ctx, provider, cfg)
-	case "gcp":
-		return stopGCPNonEssentialResources(ctx, provider, cfg)
-	case "oci":
-		return stopOCINonEssentialResources(ctx, provider, cfg)
-	case "ibm":
-		return stopIBMNonEssentialResources(ctx, provider, cfg)
+// StopNonEssentialResources stops running instances without an Essential tag. It returns the
+// outcome for each resource it selected; with opts.DryRun nothing is stopped.
+func StopNonEssentialResources(ctx context.Context, provider models.CloudProvider, cfg *config.Config, opts RemediationOptions) (RemediationResult, error) {
+	p, ok := ProviderFor(provider.Type)
+	if !ok {
+		return RemediationResult{}, nil
 	}
-	return nil
+	return p.StopInstances(ctx, provider, cfg, opts)
 }
 
-func stopAWSNonEssentialResources(ctx context.Context, provider models.CloudProvider, cfg *config.Config) error {
-	sess, err := session.NewSession(&aws.Config{
-		Region: aws.String(cfg.AWSRegion),
-	})
+func stopAWSNonEssentialResources(ctx context.Context, provider models.CloudProvider, cfg *config.Config, run *remediationRun) error {
+	sess, err := newAWSSession(provider, cfg)
 	if err != nil {
 		return err
 	}
@@ -532,6 +575,13 @@ func stopAWSNonEssentialResources(ctx context.Context, provider models.CloudProv
 				break
 			}
 			
+			if !run.selects(awsInstanceName(instance), cfg.AWSRegion, awsTagMap(instance.Tags)) {
+				continue
+			}
+			if run.tooNew(*instance.InstanceId, derefTime(instance.LaunchTime)) {
+				continue
+			}
+
 			// Check if instance has essential tag
 			hasEssential := false
 			for _, tag := range instance.Tags {
@@ -542,13 +592,20 @@ func stopAWSNonEssentialResources(ctx context.Context, provider models.CloudProv
 			}
 
 			if !hasEssential {
-				_, err := ec2Svc.StopInstances(&ec2.StopInstancesInput{
-					InstanceIds: []*string{instance.InstanceId},
+				err := run.act(RemediationCandidate{
+					ResourceID: *instance.InstanceId,
+					Action:     "stop",
+					Reason:     "running without Essential tag",
+				}, func() error {
+					_, err := ec2Svc.StopInstances(&ec2.StopInstancesInput{
+						InstanceIds: []*string{instance.InstanceId},
+					})
+					return err
 				})
 				if err != nil {
 					fmt.Printf("Error stopping instance %s: %v\n", *instance.InstanceId, err)
 				} else {
-					count++
+					count = run.acted()
 				}
 			}
 		}
@@ -557,7 +614,7 @@ func stopAWSNonEssentialResources(ctx context.Context, provider models.CloudProv
 	return nil
 }
 
-func stopAzureNonEssentialResources(ctx context.Context, provider models.CloudProvider, cfg *config.Config) error {
+func stopAzureNonEssentialResources(ctx context.Context, provider models.CloudProvider, cfg *config.Config, run *remediationRun) error {
 	var credentials map[string]interface{}
 	if err := json.Unmarshal([]byte(provider.Credentials), &credentials); err != nil {
 		return fmt.Errorf("failed to parse credentials: %w", err)
@@ -566,9 +623,10 @@ func stopAzureNonEssentialResources(ctx context.Context, provider models.CloudPr
 	tenantID, _ := credentials["tenantId"].(string)
 	clientID, _ := credentials["clientId"].(string)
 	clientSecret, _ := credentials["clientSecret"].(string)
-	subscriptionID := provider.SubscriptionID
+	subscriptionIDs := AzureSubscriptionIDs(provider)
+	regions := AzureRegions(provider)
 
-	if tenantID == "" || clientID == "" || clientSecret == "" || subscriptionID == "" {
+	if tenantID == "" || clientID == "" || clientSecret == "" || len(subscriptionIDs) == 0 {
 		return fmt.Errorf("missing Azure credentials or subscriptionId")
 	}
 
@@ -578,59 +636,77 @@ func stopAzureNonEssentialResources(ctx context.Context, provider models.CloudPr
 		return fmt.Errorf("failed to create Azure credential: %w", err)
 	}
 
-	// Create VM client
-	vmClient, err := armcompute.NewVirtualMachinesClient(subscriptionID, cred, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create VM client: %w", err)
-	}
-
-	// List all VMs in the subscription
-	pager := vmClient.NewListAllPager(nil)
-
+	// Each subscription is listed in turn; the per-call limit spans all of them
 	count := 0
-	for pager.More() {
-		page, err := pager.NextPage(ctx)
+	for _, subscriptionID := range subscriptionIDs {
+		vmClient, err := armcompute.NewVirtualMachinesClient(subscriptionID, cred, nil)
 		if err != nil {
-			return fmt.Errorf("failed to list VMs: %w", err)
+			return fmt.Errorf("failed to create VM client: %w", err)
 		}
 
-		for _, vm := range page.Value {
-			if count >= 5 {
-				// Limit to 5 VMs to avoid massive disruption
-				break
+		// List the subscription's VMs, only in the provider's regions when it sets any
+		pager := newAzureVMPager(vmClient, regions)
+		for pager.More() {
+			page, err := pager.NextPage(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to list VMs: %w", err)
 			}
 
-			// Check if VM has Essential tag
-			hasEssential := false
-			if vm.Tags != nil {
-				if val, ok := vm.Tags["Essential"]; ok && val != nil && *val == "true" {
-					hasEssential = true
+			for _, vm := range page.Value {
+				if count >= 5 {
+					// Limit to 5 VMs to avoid massive disruption
+					break
 				}
-			}
 
-			if !hasEssential && vm.Name != nil && vm.ID != nil {
-				// Extract resource group from VM ID
-				// VM ID format: /subscriptions/{sub}/resourceGroups/{rg}/providers/Microsoft.Compute/virtualMachines/{name}
-				resourceGroup := extractResourceGroupFromID(*vm.ID)
-				if resourceGroup == "" {
-					fmt.Printf("Could not extract resource group from VM ID: %s\n", *vm.ID)
+				if !run.selects(derefString(vm.Name), derefString(vm.Location), azureTagMap(vm.Tags)) {
 					continue
 				}
-
-				// Deallocate (stop) the VM
-				poller, err := vmClient.BeginDeallocate(ctx, resourceGroup, *vm.Name, nil)
-				if err != nil {
-					fmt.Printf("Error stopping Azure VM %s: %v\n", *vm.Name, err)
+				if run.tooNew(derefString(vm.ID), azureVMCreated(vm)) {
 					continue
 				}
 
-				// Wait for the operation to complete (with timeout)
-				_, err = poller.PollUntilDone(ctx, nil)
-				if err != nil {
-					fmt.Printf("Error waiting for VM %s to stop: %v\n", *vm.Name, err)
-				} else {
-					fmt.Printf("Successfully stopped Azure VM: %s\n", *vm.Name)
-					count++
+				// Check if VM has Essential tag
+				hasEssential := false
+				if vm.Tags != nil {
+					if val, ok := vm.Tags["Essential"]; ok && val != nil && *val == "true" {
+						hasEssential = true
+					}
+				}
+
+				if !hasEssential && vm.Name != nil && vm.ID != nil {
+					// Extract resource group from VM ID
+					// VM ID format: /subscriptions/{sub}/resourceGroups/{rg}/providers/Microsoft.Compute/virtualMachines/{name}
+					resourceGroup := extractResourceGroupFromID(*vm.ID)
+					if resourceGroup == "" {
+						fmt.Printf("Could not extract resource group from VM ID: %s\n", *vm.ID)
+						continue
+					}
+
+					err := run.act(RemediationCandidate{
+						ResourceID: *vm.ID,
+						Name:       *vm.Name,
+						Action:     "stop",
+						Reason:     "running without Essential tag",
+					}, func() error {
+						// Deallocate (stop) the VM
+						poller, err := vmClient.BeginDeallocate(ctx, resourceGroup, *vm.Name, nil)
+						if err != nil {
+							fmt.Printf("Error stopping Azure VM %s: %v\n", *vm.Name, err)
+							return err
+						}
+
+						// Wait for the operation to complete (with timeout)
+						_, err = poller.PollUntilDone(ctx, nil)
+						if err != nil {
+							fmt.Printf("Error waiting for VM %s to stop: %v\n", *vm.Name, err)
+							return err
+						}
+						fmt.Printf("Successfully stopped Azure VM: %s\n", *vm.Name)
+						return nil
+					})
+					if err == nil {
+						count = run.acted()
+					}
 				}
 			}
 		}
@@ -670,7 +746,7 @@ func splitAzureResourceID(id string) []string {
 	return parts
 }
 
-func stopGCPNonEssentialResources(ctx context.Context, provider models.CloudProvider, cfg *config.Config) error {
+func stopGCPNonEssentialResources(ctx context.Context, provider models.CloudProvider, cfg *config.Config, run *remediationRun) error {
 	var credentials map[string]interface{}
 	if err := json.Unmarshal([]byte(provider.Credentials), &credentials); err != nil {
 		return fmt.Errorf("failed to parse credentials: %w", err)
@@ -718,6 +794,13 @@ func stopGCPNonEssentialResources(ctx context.Context, provider models.CloudProv
 				break
 			}
 
+			if !run.selects(instance.Name, zone.Name, instance.Labels) {
+				continue
+			}
+			if run.tooNew(instance.Name, parseGCPTimestamp(instance.CreationTimestamp)) {
+				continue
+			}
+
 			// Check if instance has Essential label
 			hasEssential := false
 			if instance.Labels != nil {
@@ -728,13 +811,24 @@ func stopGCPNonEssentialResources(ctx context.Context, provider models.CloudProv
 
 			if !hasEssential {
 				// Stop the instance
-				_, err := computeService.Instances.Stop(projectID, zone.Name, instance.Name).Context(ctx).Do()
+				err := run.act(RemediationCandidate{
+					ResourceID: fmt.Sprintf("%d", instance.Id),
+					Name:       instance.Name,
+					Action:     "stop",
+					Reason:     fmt.Sprintf("running without essential label in zone %s", zone.Name),
+				}, func() error {
+					_, err := computeService.Instances.Stop(projectID, zone.Name, instance.Name).Context(ctx).Do()
+					if err != nil {
+						return err
+					}
+					fmt.Printf("Successfully initiated stop for GCP instance: %s (%s) in zone %s\n", instance.Name, instanceEnvironment(instance.Labels), zone.Name)
+					return nil
+				})
 				if err != nil {
 					fmt.Printf("Error stopping GCP instance %s in zone %s: %v\n", instance.Name, zone.Name, err)
 					continue
 				}
-				fmt.Printf("Successfully initiated stop for GCP instance: %s in zone %s\n", instance.Name, zone.Name)
-				count++
+				count = run.acted()
 			}
 		}
 	}
@@ -742,55 +836,75 @@ func stopGCPNonEssentialResources(ctx context.Context, provider models.CloudProv
 	return nil
 }
 
-// ListGCPInstances lists all Compute Engine instances in a project
-func ListGCPInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config) ([]map[string]interface{}, error) {
+// instanceEnvironment returns the normalized environment of a GCP instance from its
+// "environment" label, falling back to "env". Unlabeled instances are treated as
+// production so they are never targeted by environment-gated remediation.
+func instanceEnvironment(labels map[string]string) string {
+	if env := labels["environment"]; env != "" {
+		return normalizeEnvironment(env)
+	}
+	if env := labels["env"]; env != "" {
+		return normalizeEnvironment(env)
+	}
+	return "production"
+}
+
+// listGCPInstancePage lists one page of Compute Engine instances in a project, and the token of
+// the next page, empty after the last
+func listGCPInstancePage(ctx context.Context, provider models.CloudProvider, opts ListOptions) ([]Instance, string, error) {
 	var credentials map[string]interface{}
 	if err := json.Unmarshal([]byte(provider.Credentials), &credentials); err != nil {
-		return nil, fmt.Errorf("failed to parse credentials: %w", err)
+		return nil, "", fmt.Errorf("failed to parse credentials: %w", err)
 	}
 
 	serviceAccountJSON, _ := credentials["serviceAccountKey"].(string)
 	projectID := provider.ProjectID
 
 	if serviceAccountJSON == "" || projectID == "" {
-		return nil, fmt.Errorf("missing GCP credentials or projectId")
+		return nil, "", fmt.Errorf("missing GCP credentials or projectId")
 	}
 
 	computeService, err := compute.NewService(ctx, option.WithCredentialsJSON([]byte(serviceAccountJSON)))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create compute service: %w", err)
-	}
-
-	var instances []map[string]interface{}
-
-	// Use aggregated list to get all instances across all zones
-	req := computeService.Instances.AggregatedList(projectID)
-	if err := req.Pages(ctx, func(page *compute.InstanceAggregatedList) error {
-		for zone, instancesScopedList := range page.Items {
-			if instancesScopedList.Instances != nil {
-				for _, instance := range instancesScopedList.Instances {
-					instances = append(instances, map[string]interface{}{
-						"id":          instance.Id,
-						"name":        instance.Name,
-						"zone":        zone,
-						"status":      instance.Status,
-						"machineType": instance.MachineType,
-						"labels":      instance.Labels,
-						"createdAt":   instance.CreationTimestamp,
-					})
-				}
+		return nil, "", fmt.Errorf("failed to create compute service: %w", err)
+	}
+
+	pageSize := opts.pageSize()
+	instances := make([]Instance, 0, pageSize)
+
+	// Use aggregated list to get instances across all zones
+	req := computeService.Instances.AggregatedList(projectID).MaxResults(int64(pageSize))
+	if opts.PageToken != "" {
+		req = req.PageToken(opts.PageToken)
+	}
+	page, err := req.Context(ctx).Do()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list instances: %w", err)
+	}
+	for _, scoped := range page.Items {
+		for _, instance := range scoped.Instances {
+			if len(instances) >= pageSize {
+				break
 			}
+			zone := instance.Zone[strings.LastIndex(instance.Zone, "/")+1:]
+			instances = append(instances, Instance{
+				ID:           fmt.Sprintf("%d", instance.Id),
+				Name:         instance.Name,
+				InstanceType: instance.MachineType[strings.LastIndex(instance.MachineType, "/")+1:],
+				Region:       zoneRegion(zone),
+				Zone:         zone,
+				State:        instance.Status,
+				Tags:         instance.Labels,
+				LaunchedAt:   parseGCPTimestamp(instance.CreationTimestamp),
+			})
 		}
-		return nil
-	}); err != nil {
-		return nil, fmt.Errorf("failed to list instances: %w", err)
 	}
 
-	return instances, nil
+	return instances, page.NextPageToken, nil
 }
 
 // stopOCINonEssentialResources stops OCI compute instances without Essential freeform tag
-func stopOCINonEssentialResources(ctx context.Context, provider models.CloudProvider, cfg *config.Config) error {
+func stopOCINonEssentialResources(ctx context.Context, provider models.CloudProvider, cfg *config.Config, run *remediationRun) error {
 	var credentials map[string]interface{}
 	if err := json.Unmarshal([]byte(provider.Credentials), &credentials); err != nil {
 		return fmt.Errorf("failed to parse credentials: %w", err)
@@ -851,6 +965,13 @@ func stopOCINonEssentialResources(ctx context.Context, provider models.CloudProv
 			break
 		}
 
+		if !run.selects(derefString(instance.DisplayName), derefString(instance.Region), instance.FreeformTags) {
+			continue
+		}
+		if run.tooNew(derefString(instance.Id), ociTimeCreated(instance.TimeCreated)) {
+			continue
+		}
+
 		// Check if instance has Essential freeform tag
 		hasEssential := false
 		if instance.FreeformTags != nil {
@@ -861,29 +982,41 @@ func stopOCINonEssentialResources(ctx context.Context, provider models.CloudProv
 
 		if !hasEssential && instance.Id != nil {
 			// Stop the instance
-			stopRequest := ocicore.InstanceActionRequest{
-				InstanceId: instance.Id,
-				Action:     ocicore.InstanceActionActionStop,
-			}
+			err := run.act(RemediationCandidate{
+				ResourceID: *instance.Id,
+				Name:       derefString(instance.DisplayName),
+				Action:     "stop",
+				Reason:     "running without Essential freeform tag",
+			}, func() error {
+				stopRequest := ocicore.InstanceActionRequest{
+					InstanceId: instance.Id,
+					Action:     ocicore.InstanceActionActionStop,
+				}
 
-			_, err := computeClient.InstanceAction(ctx, stopRequest)
+				_, err := computeClient.InstanceAction(ctx, stopRequest)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("Successfully initiated stop for OCI instance: %s\n", *instance.DisplayName)
+				return nil
+			})
 			if err != nil {
 				fmt.Printf("Error stopping OCI instance %s: %v\n", *instance.DisplayName, err)
 				continue
 			}
-			fmt.Printf("Successfully initiated stop for OCI instance: %s\n", *instance.DisplayName)
-			count++
+			count = run.acted()
 		}
 	}
 
 	return nil
 }
 
-// ListOCIInstances lists all Compute instances in an OCI compartment
-func ListOCIInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config) ([]map[string]interface{}, error) {
+// listOCIInstancePage lists one page of Compute instances in an OCI compartment, and the token
+// of the next page, empty after the last
+func listOCIInstancePage(ctx context.Context, provider models.CloudProvider, opts ListOptions) ([]Instance, string, error) {
 	var credentials map[string]interface{}
 	if err := json.Unmarshal([]byte(provider.Credentials), &credentials); err != nil {
-		return nil, fmt.Errorf("failed to parse credentials: %w", err)
+		return nil, "", fmt.Errorf("failed to parse credentials: %w", err)
 	}
 
 	tenancyOCID, _ := credentials["tenancyOcid"].(string)
@@ -894,7 +1027,7 @@ func ListOCIInstances(ctx context.Context, provider models.CloudProvider, cfg *c
 	compartmentOCID, _ := credentials["compartmentOcid"].(string)
 
 	if tenancyOCID == "" || userOCID == "" || fingerprint == "" || privateKey == "" {
-		return nil, fmt.Errorf("missing OCI credentials")
+		return nil, "", fmt.Errorf("missing OCI credentials")
 	}
 
 	if region == "" {
@@ -916,38 +1049,49 @@ func ListOCIInstances(ctx context.Context, provider models.CloudProvider, cfg *c
 
 	computeClient, err := ocicore.NewComputeClientWithConfigurationProvider(configProvider)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create OCI compute client: %w", err)
+		return nil, "", fmt.Errorf("failed to create OCI compute client: %w", err)
 	}
 
+	pageSize := opts.pageSize()
 	listRequest := ocicore.ListInstancesRequest{
 		CompartmentId: &compartmentOCID,
+		Limit:         ocicommon.Int(pageSize),
+	}
+	if opts.PageToken != "" {
+		listRequest.Page = ocicommon.String(opts.PageToken)
 	}
 
 	response, err := computeClient.ListInstances(ctx, listRequest)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list OCI instances: %w", err)
+		return nil, "", fmt.Errorf("failed to list OCI instances: %w", err)
 	}
 
-	var instances []map[string]interface{}
+	instances := make([]Instance, 0, pageSize)
 	for _, instance := range response.Items {
-		instances = append(instances, map[string]interface{}{
-			"id":             instance.Id,
-			"name":           instance.DisplayName,
-			"compartmentId":  instance.CompartmentId,
-			"availabilityDomain": instance.AvailabilityDomain,
-			"shape":          instance.Shape,
-			"lifecycleState": instance.LifecycleState,
-			"freeformTags":   instance.FreeformTags,
-			"definedTags":    instance.DefinedTags,
-			"createdAt":      instance.TimeCreated,
+		if len(instances) >= pageSize {
+			break
+		}
+		var launchedAt time.Time
+		if instance.TimeCreated != nil {
+			launchedAt = instance.TimeCreated.Time
+		}
+		instances = append(instances, Instance{
+			ID:           derefString(instance.Id),
+			Name:         derefString(instance.DisplayName),
+			InstanceType: derefString(instance.Shape),
+			Region:       region,
+			Zone:         derefString(instance.AvailabilityDomain),
+			State:        string(instance.LifecycleState),
+			Tags:         instance.FreeformTags,
+			LaunchedAt:   launchedAt,
 		})
 	}
 
-	return instances, nil
+	return instances, derefString(response.OpcNextPage), nil
 }
 
 // stopIBMNonEssentialResources stops IBM Cloud virtual server instances without Essential tag
-func stopIBMNonEssentialResources(ctx context.Context, provider models.CloudProvider, cfg *config.Config) error {
+func stopIBMNonEssentialResources(ctx context.Context, provider models.CloudProvider, cfg *config.Config, run *remediationRun) error {
 	var credentials map[string]interface{}
 	if err := json.Unmarshal([]byte(provider.Credentials), &credentials); err != nil {
 		return fmt.Errorf("failed to parse credentials: %w", err)
@@ -998,6 +1142,13 @@ func stopIBMNonEssentialResources(ctx context.Context, provider models.CloudProv
 			continue
 		}
 
+		if !run.selects(derefString(instance.Name), ibmInstanceZone(instance), nil) {
+			continue
+		}
+		if run.tooNew(derefString(instance.ID), ibmCreatedAt(instance)) {
+			continue
+		}
+
 		// Check if instance has Essential tag in user tags
 		hasEssential := false
 		// IBM Cloud uses resource tags - check metadata or name pattern
@@ -1006,16 +1157,27 @@ func stopIBMNonEssentialResources(ctx context.Context, provider models.CloudProv
 		}
 
 		if !hasEssential && instance.ID != nil {
-			// Create stop action
-			stopAction := "stop"
-			createInstanceActionOptions := vpcService.NewCreateInstanceActionOptions(*instance.ID, stopAction)
-			_, _, err := vpcService.CreateInstanceAction(createInstanceActionOptions)
+			err := run.act(RemediationCandidate{
+				ResourceID: *instance.ID,
+				Name:       *instance.Name,
+				Action:     "stop",
+				Reason:     "running without Essential in name",
+			}, func() error {
+				// Create stop action
+				stopAction := "stop"
+				createInstanceActionOptions := vpcService.NewCreateInstanceActionOptions(*instance.ID, stopAction)
+				_, _, err := vpcService.CreateInstanceAction(createInstanceActionOptions)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("Successfully initiated stop for IBM instance: %s\n", *instance.Name)
+				return nil
+			})
 			if err != nil {
 				fmt.Printf("Error stopping IBM instance %s: %v\n", *instance.Name, err)
 				continue
 			}
-			fmt.Printf("Successfully initiated stop for IBM instance: %s\n", *instance.Name)
-			count++
+			count = run.acted()
 		}
 	}
 
@@ -1036,18 +1198,19 @@ func containsEssential(s string) bool {
 		(len(lower) > 9 && (lower[:9] == "essential" || lower[len(lower)-9:] == "essential")))
 }
 
-// ListIBMInstances lists all Virtual Server instances in IBM Cloud
-func ListIBMInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config) ([]map[string]interface{}, error) {
+// listIBMInstancePage lists one page of Virtual Server instances in IBM Cloud, and the token of
+// the next page, empty after the last
+func listIBMInstancePage(ctx context.Context, provider models.CloudProvider, opts ListOptions) ([]Instance, string, error) {
 	var credentials map[string]interface{}
 	if err := json.Unmarshal([]byte(provider.Credentials), &credentials); err != nil {
-		return nil, fmt.Errorf("failed to parse credentials: %w", err)
+		return nil, "", fmt.Errorf("failed to parse credentials: %w", err)
 	}
 
 	apiKey, _ := credentials["apiKey"].(string)
 	region, _ := credentials["region"].(string)
 
 	if apiKey == "" {
-		return nil, fmt.Errorf("missing IBM Cloud credentials (apiKey)")
+		return nil, "", fmt.Errorf("missing IBM Cloud credentials (apiKey)")
 	}
 
 	if region == "" {
@@ -1063,53 +1226,65 @@ func ListIBMInstances(ctx context.Context, provider models.CloudProvider, cfg *c
 		URL:           fmt.Sprintf("https://%s.iaas.cloud.ibm.com/v1", region),
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create IBM VPC client: %w", err)
+		return nil, "", fmt.Errorf("failed to create IBM VPC client: %w", err)
 	}
 
-	listInstancesOptions := vpcService.NewListInstancesOptions()
-	instances, _, err := vpcService.ListInstances(listInstancesOptions)
+	pageSize := opts.pageSize()
+	listInstancesOptions := vpcService.NewListInstancesOptions().SetLimit(int64(pageSize))
+	if opts.PageToken != "" {
+		listInstancesOptions.SetStart(opts.PageToken)
+	}
+	instances, _, err := vpcService.ListInstancesWithContext(ctx, listInstancesOptions)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list IBM instances: %w", err)
+		return nil, "", fmt.Errorf("failed to list IBM instances: %w", err)
 	}
 
-	var result []map[string]interface{}
+	result := make([]Instance, 0, pageSize)
 	for _, instance := range instances.Instances {
-		result = append(result, map[string]interface{}{
-			"id":        instance.ID,
-			"name":      instance.Name,
-			"status":    instance.Status,
-			"profile":   instance.Profile,
-			"zone":      instance.Zone,
-			"vpc":       instance.VPC,
-			"createdAt": instance.CreatedAt,
-		})
+		if len(result) >= pageSize {
+			break
+		}
+		item := Instance{
+			ID:     derefString(instance.ID),
+			Name:   derefString(instance.Name),
+			Region: region,
+			State:  derefString(instance.Status),
+		}
+		if instance.Profile != nil {
+			item.InstanceType = derefString(instance.Profile.Name)
+		}
+		if instance.Zone != nil {
+			item.Zone = derefString(instance.Zone.Name)
+		}
+		if instance.CreatedAt != nil {
+			item.LaunchedAt = time.Time(*instance.CreatedAt)
+		}
+		result = append(result, item)
 	}
 
-	return result, nil
+	// The next page starts where the collection's next link says
+	next, err := instances.GetNextStart()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read IBM instances next page: %w", err)
+	}
+	return result, derefString(next), nil
 }
 
-// TerminateOversizedInstances terminates instances that exceed allowed size thresholds
-func TerminateOversizedInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, maxSizeLevel int) error {
-	switch provider.Type {
-	case "aws":
-		return terminateAWSOversizedInstances(ctx, provider, cfg, maxSizeLevel)
-	case "azure":
-		return terminateAzureOversizedInstances(ctx, provider, cfg, maxSizeLevel)
-	case "gcp":
-		return terminateGCPOversizedInstances(ctx, provider, cfg, maxSizeLevel)
-	case "oci":
-		return terminateOCIOversizedInstances(ctx, provider, cfg, maxSizeLevel)
-	case "ibm":
-		return terminateIBMOversizedInstances(ctx, provider, cfg, maxSizeLevel)
+// TerminateOversizedInstances terminates instances that exceed allowed size thresholds.
+// When maxHourlyPrice is set, AWS, Azure and GCP instances are judged by their on-demand
+// price instead, falling back to the size heuristics when no price is available.
+// It returns the outcome for each resource it selected; with opts.DryRun nothing is terminated.
+func TerminateOversizedInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, maxSizeLevel int, maxHourlyPrice float64, opts RemediationOptions) (RemediationResult, error) {
+	p, ok := ProviderFor(provider.Type)
+	if !ok {
+		return RemediationResult{}, nil
 	}
-	return nil
+	return p.TerminateInstances(ctx, provider, cfg, maxSizeLevel, maxHourlyPrice, opts)
 }
 
 // terminateAWSOversizedInstances terminates AWS EC2 instances that exceed size limit
-func terminateAWSOversizedInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, maxSizeLevel int) error {
-	sess, err := session.NewSession(&aws.Config{
-		Region: aws.String(cfg.AWSRegion),
-	})
+func terminateAWSOversizedInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, maxSizeLevel int, maxHourlyPrice float64, run *remediationRun) error {
+	sess, err := newAWSSession(provider, cfg)
 	if err != nil {
 		return err
 	}
@@ -1161,7 +1336,17 @@ func terminateAWSOversizedInstances(ctx context.Context, provider models.CloudPr
 			}
 
 			instanceType := *instance.InstanceType
-			if sizeLevel(instanceType) > maxSizeLevel {
+			oversized := sizeLevel(instanceType) > maxSizeLevel
+			exceeds, price, priced := exceedsPriceLimit(ctx, provider, cfg, instanceType, cfg.AWSRegion, maxHourlyPrice)
+			if priced {
+				oversized = exceeds
+			}
+
+			if oversized {
+				if !run.selects(awsInstanceName(instance), cfg.AWSRegion, awsTagMap(instance.Tags)) {
+					continue
+				}
+
 				// Check for Essential tag before terminating
 				hasEssential := false
 				for _, tag := range instance.Tags {
@@ -1172,15 +1357,30 @@ func terminateAWSOversizedInstances(ctx context.Context, provider models.CloudPr
 				}
 
 				if !hasEssential {
-					_, err := ec2Svc.TerminateInstances(&ec2.TerminateInstancesInput{
-						InstanceIds: []*string{instance.InstanceId},
+					reason := fmt.Sprintf("type %s, level %d > max %d", instanceType, sizeLevel(instanceType), maxSizeLevel)
+					if priced {
+						reason = fmt.Sprintf("type %s, $%.4f/hr > max $%.4f/hr, saving ~$%.2f/month", instanceType, price, maxHourlyPrice, price*HoursPerMonth)
+					}
+
+					err := run.act(RemediationCandidate{
+						ResourceID:     *instance.InstanceId,
+						Action:         "terminate",
+						Reason:         reason,
+						MonthlySavings: monthlyInstanceCost(ctx, provider, cfg, instanceType, cfg.AWSRegion),
+					}, func() error {
+						_, err := ec2Svc.TerminateInstances(&ec2.TerminateInstancesInput{
+							InstanceIds: []*string{instance.InstanceId},
+						})
+						if err != nil {
+							return err
+						}
+						fmt.Printf("Terminated oversized instance %s (%s)\n", *instance.InstanceId, reason)
+						return nil
 					})
 					if err != nil {
 						fmt.Printf("Error terminating oversized instance %s: %v\n", *instance.InstanceId, err)
 					} else {
-						fmt.Printf("Terminated oversized instance %s (type: %s, level: %d > max: %d)\n",
-							*instance.InstanceId, instanceType, sizeLevel(instanceType), maxSizeLevel)
-						count++
+						count = run.acted()
 					}
 				}
 			}
@@ -1190,8 +1390,56 @@ func terminateAWSOversizedInstances(ctx context.Context, provider models.CloudPr
 	return nil
 }
 
-// terminateAzureOversizedInstances terminates Azure VMs that exceed size limit
-func terminateAzureOversizedInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, maxSizeLevel int) error {
+// azureVMPriority returns a VM's scheduling priority ("Regular", "Spot" or "Low"), defaulting to Regular
+func azureVMPriority(vm *armcompute.VirtualMachine) armcompute.VirtualMachinePriorityTypes {
+	if vm != nil && vm.Properties != nil && vm.Properties.Priority != nil {
+		return *vm.Properties.Priority
+	}
+	return armcompute.VirtualMachinePriorityTypesRegular
+}
+
+// isAzureSpotVM reports whether a VM runs at Spot or (legacy) Low priority
+func isAzureSpotVM(vm *armcompute.VirtualMachine) bool {
+	switch azureVMPriority(vm) {
+	case armcompute.VirtualMachinePriorityTypesSpot, armcompute.VirtualMachinePriorityTypesLow:
+		return true
+	}
+	return false
+}
+
+// azureTagIsTrue reports whether a tag is set to "true" (case-insensitive value)
+func azureTagIsTrue(tags map[string]*string, key string) bool {
+	val, ok := tags[key]
+	return ok && val != nil && strings.EqualFold(*val, "true")
+}
+
+// azureVMCreated returns when a VM was created, or the zero time when Azure doesn't report it
+func azureVMCreated(vm *armcompute.VirtualMachine) time.Time {
+	if vm.Properties == nil {
+		return time.Time{}
+	}
+	return derefTime(vm.Properties.TimeCreated)
+}
+
+// ociTimeCreated returns an OCI resource's creation time, or the zero time when unset
+func ociTimeCreated(t *ocicommon.SDKTime) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return t.Time
+}
+
+// ibmCreatedAt returns when an IBM Cloud VPC instance was created, or the zero time when unset
+func ibmCreatedAt(instance vpcv1.Instance) time.Time {
+	if instance.CreatedAt == nil {
+		return time.Time{}
+	}
+	return time.Time(*instance.CreatedAt)
+}
+
+// terminateAzureOversizedInstances terminates Azure VMs that exceed size limit.
+// Spot/low-priority VMs are skipped, and FaultTolerant-tagged VMs get a spot recommendation instead.
+func terminateAzureOversizedInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, maxSizeLevel int, maxHourlyPrice float64, run *remediationRun) error {
 	var credentials map[string]interface{}
 	if err := json.Unmarshal([]byte(provider.Credentials), &credentials); err != nil {
 		return fmt.Errorf("failed to parse credentials: %w", err)
@@ -1200,9 +1448,10 @@ func terminateAzureOversizedInstances(ctx context.Context, provider models.Cloud
 	tenantID, _ := credentials["tenantId"].(string)
 	clientID, _ := credentials["clientId"].(string)
 	clientSecret, _ := credentials["clientSecret"].(string)
-	subscriptionID := provider.SubscriptionID
+	subscriptionIDs := AzureSubscriptionIDs(provider)
+	regions := AzureRegions(provider)
 
-	if tenantID == "" || clientID == "" || clientSecret == "" || subscriptionID == "" {
+	if tenantID == "" || clientID == "" || clientSecret == "" || len(subscriptionIDs) == 0 {
 		return fmt.Errorf("missing Azure credentials or subscriptionId")
 	}
 
@@ -1211,11 +1460,6 @@ func terminateAzureOversizedInstances(ctx context.Context, provider models.Cloud
 		return fmt.Errorf("failed to create Azure credential: %w", err)
 	}
 
-	vmClient, err := armcompute.NewVirtualMachinesClient(subscriptionID, cred, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create VM client: %w", err)
-	}
-
 	// Azure VM size levels (approximate ordering)
 	sizeLevel := func(vmSize string) int {
 		lower := strings.ToLower(vmSize)
@@ -1241,50 +1485,95 @@ func terminateAzureOversizedInstances(ctx context.Context, provider models.Cloud
 		}
 	}
 
-	pager := vmClient.NewListAllPager(nil)
+	// The limit of 5 terminations covers every subscription
 	count := 0
-
-	for pager.More() {
-		page, err := pager.NextPage(ctx)
+	for _, subscriptionID := range subscriptionIDs {
+		vmClient, err := armcompute.NewVirtualMachinesClient(subscriptionID, cred, nil)
 		if err != nil {
-			return fmt.Errorf("failed to list VMs: %w", err)
+			return fmt.Errorf("failed to create VM client: %w", err)
 		}
 
-		for _, vm := range page.Value {
-			if count >= 5 {
-				break
+		pager := newAzureVMPager(vmClient, regions)
+		for pager.More() {
+			page, err := pager.NextPage(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to list VMs: %w", err)
 			}
 
-			if vm.Properties != nil && vm.Properties.HardwareProfile != nil && vm.Properties.HardwareProfile.VMSize != nil {
-				vmSize := string(*vm.Properties.HardwareProfile.VMSize)
-				if sizeLevel(vmSize) > maxSizeLevel {
-					// Check for Essential tag
-					hasEssential := false
-					if vm.Tags != nil {
-						if val, ok := vm.Tags["Essential"]; ok && val != nil && *val == "true" {
-							hasEssential = true
+			for _, vm := range page.Value {
+				if count >= 5 {
+					break
+				}
+
+				if vm.Properties != nil && vm.Properties.HardwareProfile != nil && vm.Properties.HardwareProfile.VMSize != nil {
+					vmSize := string(*vm.Properties.HardwareProfile.VMSize)
+					oversized := sizeLevel(vmSize) > maxSizeLevel
+					if vm.Location != nil {
+						if exceeds, _, priced := exceedsPriceLimit(ctx, provider, cfg, vmSize, *vm.Location, maxHourlyPrice); priced {
+							oversized = exceeds
 						}
 					}
 
-					if !hasEssential && vm.Name != nil && vm.ID != nil {
-						resourceGroup := extractResourceGroupFromID(*vm.ID)
-						if resourceGroup == "" {
+					// Spot and low-priority VMs are already discounted and evictable
+					if oversized && isAzureSpotVM(vm) {
+						if vm.Name != nil {
+							fmt.Printf("Skipping oversized Azure spot VM: %s (size: %s)\n", *vm.Name, vmSize)
+						}
+						oversized = false
+					}
+
+					if oversized {
+						if !run.selects(derefString(vm.Name), derefString(vm.Location), azureTagMap(vm.Tags)) {
 							continue
 						}
 
-						// Delete (terminate) the VM
-						poller, err := vmClient.BeginDelete(ctx, resourceGroup, *vm.Name, nil)
-						if err != nil {
-							fmt.Printf("Error deleting oversized Azure VM %s: %v\n", *vm.Name, err)
+						// Check for Essential tag
+						hasEssential := false
+						if vm.Tags != nil {
+							if val, ok := vm.Tags["Essential"]; ok && val != nil && *val == "true" {
+								hasEssential = true
+							}
+						}
+
+						// Workloads that tolerate eviction are better moved to spot than terminated
+						if !hasEssential && azureTagIsTrue(vm.Tags, "FaultTolerant") {
+							if vm.Name != nil {
+								fmt.Printf("Recommend converting oversized fault-tolerant Azure VM %s (size: %s) to Spot priority instead of terminating\n", *vm.Name, vmSize)
+							}
 							continue
 						}
 
-						_, err = poller.PollUntilDone(ctx, nil)
-						if err != nil {
-							fmt.Printf("Error waiting for VM %s deletion: %v\n", *vm.Name, err)
-						} else {
-							fmt.Printf("Deleted oversized Azure VM: %s (size: %s)\n", *vm.Name, vmSize)
-							count++
+						if !hasEssential && vm.Name != nil && vm.ID != nil {
+							resourceGroup := extractResourceGroupFromID(*vm.ID)
+							if resourceGroup == "" {
+								continue
+							}
+
+							err := run.act(RemediationCandidate{
+								ResourceID:     *vm.ID,
+								Name:           *vm.Name,
+								Action:         "terminate",
+								Reason:         fmt.Sprintf("size %s exceeds limit", vmSize),
+								MonthlySavings: monthlyInstanceCost(ctx, provider, cfg, vmSize, derefString(vm.Location)),
+							}, func() error {
+								// Delete (terminate) the VM
+								poller, err := vmClient.BeginDelete(ctx, resourceGroup, *vm.Name, nil)
+								if err != nil {
+									fmt.Printf("Error deleting oversized Azure VM %s: %v\n", *vm.Name, err)
+									return err
+								}
+
+								_, err = poller.PollUntilDone(ctx, nil)
+								if err != nil {
+									fmt.Printf("Error waiting for VM %s deletion: %v\n", *vm.Name, err)
+									return err
+								}
+								fmt.Printf("Deleted oversized Azure VM: %s (size: %s)\n", *vm.Name, vmSize)
+								return nil
+							})
+							if err == nil {
+								count = run.acted()
+							}
 						}
 					}
 				}
@@ -1296,7 +1585,7 @@ func terminateAzureOversizedInstances(ctx context.Context, provider models.Cloud
 }
 
 // terminateGCPOversizedInstances terminates GCP instances that exceed size limit
-func terminateGCPOversizedInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, maxSizeLevel int) error {
+func terminateGCPOversizedInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, maxSizeLevel int, maxHourlyPrice float64, run *remediationRun) error {
 	var credentials map[string]interface{}
 	if err := json.Unmarshal([]byte(provider.Credentials), &credentials); err != nil {
 		return fmt.Errorf("failed to parse credentials: %w", err)
@@ -1362,7 +1651,23 @@ func terminateGCPOversizedInstances(ctx context.Context, provider models.CloudPr
 				break
 			}
 
-			if sizeLevel(instance.MachineType) > maxSizeLevel {
+			oversized := sizeLevel(instance.MachineType) > maxSizeLevel
+
+			// MachineType is a URL; pricing needs the bare name and the zone's region
+			machineType := instance.MachineType[strings.LastIndex(instance.MachineType, "/")+1:]
+			region := zone.Name
+			if i := strings.LastIndex(region, "-"); i > 0 {
+				region = region[:i]
+			}
+			if exceeds, _, priced := exceedsPriceLimit(ctx, provider, cfg, machineType, region, maxHourlyPrice); priced {
+				oversized = exceeds
+			}
+
+			if oversized {
+				if !run.selects(instance.Name, zone.Name, instance.Labels) {
+					continue
+				}
+
 				// Check for essential label
 				hasEssential := false
 				if instance.Labels != nil {
@@ -1372,13 +1677,25 @@ func terminateGCPOversizedInstances(ctx context.Context, provider models.CloudPr
 				}
 
 				if !hasEssential {
-					_, err := computeService.Instances.Delete(projectID, zone.Name, instance.Name).Context(ctx).Do()
+					err := run.act(RemediationCandidate{
+						ResourceID:     fmt.Sprintf("%d", instance.Id),
+						Name:           instance.Name,
+						Action:         "terminate",
+						Reason:         fmt.Sprintf("machine type %s exceeds limit in zone %s", machineType, zone.Name),
+						MonthlySavings: monthlyInstanceCost(ctx, provider, cfg, machineType, region),
+					}, func() error {
+						_, err := computeService.Instances.Delete(projectID, zone.Name, instance.Name).Context(ctx).Do()
+						if err != nil {
+							return err
+						}
+						fmt.Printf("Deleted oversized GCP instance: %s (%s) in zone %s\n", instance.Name, instanceEnvironment(instance.Labels), zone.Name)
+						return nil
+					})
 					if err != nil {
 						fmt.Printf("Error deleting oversized GCP instance %s: %v\n", instance.Name, err)
 						continue
 					}
-					fmt.Printf("Deleted oversized GCP instance: %s in zone %s\n", instance.Name, zone.Name)
-					count++
+					count = run.acted()
 				}
 			}
 		}
@@ -1388,7 +1705,7 @@ func terminateGCPOversizedInstances(ctx context.Context, provider models.CloudPr
 }
 
 // terminateOCIOversizedInstances terminates OCI instances that exceed size limit
-func terminateOCIOversizedInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, maxSizeLevel int) error {
+func terminateOCIOversizedInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, maxSizeLevel int, run *remediationRun) error {
 	var credentials map[string]interface{}
 	if err := json.Unmarshal([]byte(provider.Credentials), &credentials); err != nil {
 		return fmt.Errorf("failed to parse credentials: %w", err)
@@ -1458,6 +1775,10 @@ func terminateOCIOversizedInstances(ctx context.Context, provider models.CloudPr
 		}
 
 		if instance.Shape != nil && sizeLevel(*instance.Shape) > maxSizeLevel {
+			if !run.selects(derefString(instance.DisplayName), derefString(instance.Region), instance.FreeformTags) {
+				continue
+			}
+
 			hasEssential := false
 			if instance.FreeformTags != nil {
 				if val, ok := instance.FreeformTags["Essential"]; ok && val == "true" {
@@ -1466,17 +1787,28 @@ func terminateOCIOversizedInstances(ctx context.Context, provider models.CloudPr
 			}
 
 			if !hasEssential && instance.Id != nil {
-				terminateRequest := ocicore.TerminateInstanceRequest{
-					InstanceId: instance.Id,
-				}
+				err := run.act(RemediationCandidate{
+					ResourceID: *instance.Id,
+					Name:       derefString(instance.DisplayName),
+					Action:     "terminate",
+					Reason:     fmt.Sprintf("shape %s exceeds limit", *instance.Shape),
+				}, func() error {
+					terminateRequest := ocicore.TerminateInstanceRequest{
+						InstanceId: instance.Id,
+					}
 
-				_, err := computeClient.TerminateInstance(ctx, terminateRequest)
+					_, err := computeClient.TerminateInstance(ctx, terminateRequest)
+					if err != nil {
+						return err
+					}
+					fmt.Printf("Terminated oversized OCI instance: %s\n", *instance.DisplayName)
+					return nil
+				})
 				if err != nil {
 					fmt.Printf("Error terminating oversized OCI instance %s: %v\n", *instance.DisplayName, err)
 					continue
 				}
-				fmt.Printf("Terminated oversized OCI instance: %s\n", *instance.DisplayName)
-				count++
+				count = run.acted()
 			}
 		}
 	}
@@ -1485,7 +1817,7 @@ func terminateOCIOversizedInstances(ctx context.Context, provider models.CloudPr
 }
 
 // terminateIBMOversizedInstances terminates IBM Cloud instances that exceed size limit
-func terminateIBMOversizedInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, maxSizeLevel int) error {
+func terminateIBMOversizedInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, maxSizeLevel int, run *remediationRun) error {
 	var credentials map[string]interface{}
 	if err := json.Unmarshal([]byte(provider.Credentials), &credentials); err != nil {
 		return fmt.Errorf("failed to parse credentials: %w", err)
@@ -1556,20 +1888,35 @@ func terminateIBMOversizedInstances(ctx context.Context, provider models.CloudPr
 		}
 
 		if sizeLevel(profileName) > maxSizeLevel {
+			if !run.selects(derefString(instance.Name), ibmInstanceZone(instance), nil) {
+				continue
+			}
+
 			hasEssential := false
 			if instance.Name != nil && containsEssential(*instance.Name) {
 				hasEssential = true
 			}
 
 			if !hasEssential && instance.ID != nil {
-				deleteInstanceOptions := vpcService.NewDeleteInstanceOptions(*instance.ID)
-				_, err := vpcService.DeleteInstance(deleteInstanceOptions)
+				err := run.act(RemediationCandidate{
+					ResourceID: *instance.ID,
+					Name:       *instance.Name,
+					Action:     "terminate",
+					Reason:     fmt.Sprintf("profile %s exceeds limit", profileName),
+				}, func() error {
+					deleteInstanceOptions := vpcService.NewDeleteInstanceOptions(*instance.ID)
+					_, err := vpcService.DeleteInstance(deleteInstanceOptions)
+					if err != nil {
+						return err
+					}
+					fmt.Printf("Deleted oversized IBM instance: %s\n", *instance.Name)
+					return nil
+				})
 				if err != nil {
 					fmt.Printf("Error deleting oversized IBM instance %s: %v\n", *instance.Name, err)
 					continue
 				}
-				fmt.Printf("Deleted oversized IBM instance: %s\n", *instance.Name)
-				count++
+				count = run.acted()
 			}
 		}
 	}
@@ -1577,24 +1924,19 @@ func terminateIBMOversizedInstances(ctx context.Context, provider models.CloudPr
 	return nil
 }
 
-// StopIdleResources stops resources that have been idle for specified hours
-func StopIdleResources(ctx context.Context, provider models.CloudProvider, cfg *config.Config, idleHoursThreshold float64) error {
-	switch provider.Type {
-	case "aws":
-		return stopAWSIdleResources(ctx, provider, cfg, idleHoursThreshold)
-	case "azure":
-		return stopAzureIdleResources(ctx, provider, cfg, idleHoursThreshold)
-	case "gcp":
-		return stopGCPIdleResources(ctx, provider, cfg, idleHoursThreshold)
+// StopIdleResources stops resources that have been idle for specified hours. It returns the
+// outcome for each resource it selected; with opts.DryRun nothing is stopped.
+func StopIdleResources(ctx context.Context, provider models.CloudProvider, cfg *config.Config, idleHoursThreshold float64, opts RemediationOptions) (RemediationResult, error) {
+	p, ok := ProviderFor(provider.Type)
+	if !ok {
+		return RemediationResult{}, nil
 	}
-	return nil
+	return p.StopIdleInstances(ctx, provider, cfg, idleHoursThreshold, opts)
 }
 
 // stopAWSIdleResources stops AWS EC2 instances that have been idle
-func stopAWSIdleResources(ctx context.Context, provider models.CloudProvider, cfg *config.Config, idleHoursThreshold float64) error {
-	sess, err := session.NewSession(&aws.Config{
-		Region: aws.String(cfg.AWSRegion),
-	})
+func stopAWSIdleResources(ctx context.Context, provider models.CloudProvider, cfg *config.Config, idleHoursThreshold float64, run *remediationRun) error {
+	sess, err := newAWSSession(provider, cfg)
 	if err != nil {
 		return err
 	}
@@ -1625,6 +1967,13 @@ func stopAWSIdleResources(ctx context.Context, provider models.CloudProvider, cf
 				break
 			}
 
+			if !run.selects(awsInstanceName(instance), cfg.AWSRegion, awsTagMap(instance.Tags)) {
+				continue
+			}
+			if run.tooNew(*instance.InstanceId, derefTime(instance.LaunchTime)) {
+				continue
+			}
+
 			// Check for Essential tag
 			hasEssential := false
 			for _, tag := range instance.Tags {
@@ -1670,14 +2019,24 @@ func stopAWSIdleResources(ctx context.Context, provider models.CloudProvider, cf
 			}
 
 			if isIdle && len(metricsOutput.Datapoints) > 0 {
-				_, err := ec2Svc.StopInstances(&ec2.StopInstancesInput{
-					InstanceIds: []*string{instance.InstanceId},
+				err := run.act(RemediationCandidate{
+					ResourceID: *instance.InstanceId,
+					Action:     "stop",
+					Reason:     fmt.Sprintf("CPU below 5%% for %.1f hours", idleHoursThreshold),
+				}, func() error {
+					_, err := ec2Svc.StopInstances(&ec2.StopInstancesInput{
+						InstanceIds: []*string{instance.InstanceId},
+					})
+					if err != nil {
+						return err
+					}
+					fmt.Printf("Stopped idle instance %s (idle for %.1f hours)\n", *instance.InstanceId, idleHoursThreshold)
+					return nil
 				})
 				if err != nil {
 					fmt.Printf("Error stopping idle instance %s: %v\n", *instance.InstanceId, err)
 				} else {
-					fmt.Printf("Stopped idle instance %s (idle for %.1f hours)\n", *instance.InstanceId, idleHoursThreshold)
-					count++
+					count = run.acted()
 				}
 			}
 		}
@@ -1687,7 +2046,7 @@ func stopAWSIdleResources(ctx context.Context, provider models.CloudProvider, cf
 }
 
 // stopAzureIdleResources stops Azure VMs that have been idle
-func stopAzureIdleResources(ctx context.Context, provider models.CloudProvider, cfg *config.Config, idleHoursThreshold float64) error {
+func stopAzureIdleResources(ctx context.Context, provider models.CloudProvider, cfg *config.Config, idleHoursThreshold float64, run *remediationRun) error {
 	var credentials map[string]interface{}
 	if err := json.Unmarshal([]byte(provider.Credentials), &credentials); err != nil {
 		return fmt.Errorf("failed to parse credentials: %w", err)
@@ -1696,9 +2055,10 @@ func stopAzureIdleResources(ctx context.Context, provider models.CloudProvider,
 	tenantID, _ := credentials["tenantId"].(string)
 	clientID, _ := credentials["clientId"].(string)
 	clientSecret, _ := credentials["clientSecret"].(string)
-	subscriptionID := provider.SubscriptionID
+	subscriptionIDs := AzureSubscriptionIDs(provider)
+	regions := AzureRegions(provider)
 
-	if tenantID == "" || clientID == "" || clientSecret == "" || subscriptionID == "" {
+	if tenantID == "" || clientID == "" || clientSecret == "" || len(subscriptionIDs) == 0 {
 		return fmt.Errorf("missing Azure credentials or subscriptionId")
 	}
 
@@ -1707,62 +2067,80 @@ func stopAzureIdleResources(ctx context.Context, provider models.CloudProvider,
 		return fmt.Errorf("failed to create Azure credential: %w", err)
 	}
 
-	vmClient, err := armcompute.NewVirtualMachinesClient(subscriptionID, cred, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create VM client: %w", err)
-	}
-
 	// Note: For Azure, you would typically use Azure Monitor to check metrics
 	// This is a simplified version that stops VMs without Essential tag
 	// In production, integrate with Azure Monitor for CPU metrics
 
-	pager := vmClient.NewListAllPager(nil)
 	count := 0
-
-	for pager.More() {
-		page, err := pager.NextPage(ctx)
+	for _, subscriptionID := range subscriptionIDs {
+		vmClient, err := armcompute.NewVirtualMachinesClient(subscriptionID, cred, nil)
 		if err != nil {
-			return fmt.Errorf("failed to list VMs: %w", err)
+			return fmt.Errorf("failed to create VM client: %w", err)
 		}
 
-		for _, vm := range page.Value {
-			if count >= 5 {
-				break
+		pager := newAzureVMPager(vmClient, regions)
+		for pager.More() {
+			page, err := pager.NextPage(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to list VMs: %w", err)
 			}
 
-			hasEssential := false
-			if vm.Tags != nil {
-				if val, ok := vm.Tags["Essential"]; ok && val != nil && *val == "true" {
-					hasEssential = true
+			for _, vm := range page.Value {
+				if count >= 5 {
+					break
 				}
-			}
 
-			// Check for IdleCheckEnabled tag to opt-in to idle stopping
-			idleCheckEnabled := false
-			if vm.Tags != nil {
-				if val, ok := vm.Tags["IdleCheckEnabled"]; ok && val != nil && *val == "true" {
-					idleCheckEnabled = true
+				if !run.selects(derefString(vm.Name), derefString(vm.Location), azureTagMap(vm.Tags)) {
+					continue
 				}
-			}
-
-			if !hasEssential && idleCheckEnabled && vm.Name != nil && vm.ID != nil {
-				resourceGroup := extractResourceGroupFromID(*vm.ID)
-				if resourceGroup == "" {
+				if run.tooNew(derefString(vm.ID), azureVMCreated(vm)) {
 					continue
 				}
 
-				poller, err := vmClient.BeginDeallocate(ctx, resourceGroup, *vm.Name, nil)
-				if err != nil {
-					fmt.Printf("Error stopping idle Azure VM %s: %v\n", *vm.Name, err)
-					continue
+				hasEssential := false
+				if vm.Tags != nil {
+					if val, ok := vm.Tags["Essential"]; ok && val != nil && *val == "true" {
+						hasEssential = true
+					}
 				}
 
-				_, err = poller.PollUntilDone(ctx, nil)
-				if err != nil {
-					fmt.Printf("Error waiting for VM %s to stop: %v\n", *vm.Name, err)
-				} else {
-					fmt.Printf("Stopped idle Azure VM: %s\n", *vm.Name)
-					count++
+				// Check for IdleCheckEnabled tag to opt-in to idle stopping
+				idleCheckEnabled := false
+				if vm.Tags != nil {
+					if val, ok := vm.Tags["IdleCheckEnabled"]; ok && val != nil && *val == "true" {
+						idleCheckEnabled = true
+					}
+				}
+
+				if !hasEssential && idleCheckEnabled && vm.Name != nil && vm.ID != nil {
+					resourceGroup := extractResourceGroupFromID(*vm.ID)
+					if resourceGroup == "" {
+						continue
+					}
+
+					err := run.act(RemediationCandidate{
+						ResourceID: *vm.ID,
+						Name:       *vm.Name,
+						Action:     "stop",
+						Reason:     "IdleCheckEnabled tag set",
+					}, func() error {
+						poller, err := vmClient.BeginDeallocate(ctx, resourceGroup, *vm.Name, nil)
+						if err != nil {
+							fmt.Printf("Error stopping idle Azure VM %s: %v\n", *vm.Name, err)
+							return err
+						}
+
+						_, err = poller.PollUntilDone(ctx, nil)
+						if err != nil {
+							fmt.Printf("Error waiting for VM %s to stop: %v\n", *vm.Name, err)
+							return err
+						}
+						fmt.Printf("Stopped idle Azure VM: %s\n", *vm.Name)
+						return nil
+					})
+					if err == nil {
+						count = run.acted()
+					}
 				}
 			}
 		}
@@ -1772,7 +2150,7 @@ func stopAzureIdleResources(ctx context.Context, provider models.CloudProvider,
 }
 
 // stopGCPIdleResources stops GCP instances that have been idle
-func stopGCPIdleResources(ctx context.Context, provider models.CloudProvider, cfg *config.Config, idleHoursThreshold float64) error {
+func stopGCPIdleResources(ctx context.Context, provider models.CloudProvider, cfg *config.Config, idleHoursThreshold float64, run *remediationRun) error {
 	var credentials map[string]interface{}
 	if err := json.Unmarshal([]byte(provider.Credentials), &credentials); err != nil {
 		return fmt.Errorf("failed to parse credentials: %w", err)
@@ -1821,6 +2199,13 @@ func stopGCPIdleResources(ctx context.Context, provider models.CloudProvider, cf
 				break
 			}
 
+			if !run.selects(instance.Name, zone.Name, instance.Labels) {
+				continue
+			}
+			if run.tooNew(instance.Name, parseGCPTimestamp(instance.CreationTimestamp)) {
+				continue
+			}
+
 			// Check for essential label
 			hasEssential := false
 			if instance.Labels != nil {
@@ -1861,13 +2246,24 @@ func stopGCPIdleResources(ctx context.Context, provider models.CloudProvider, cf
 			}
 
 			if isIdle && len(tsResp.TimeSeries) > 0 {
-				_, err := computeService.Instances.Stop(projectID, zone.Name, instance.Name).Context(ctx).Do()
+				err := run.act(RemediationCandidate{
+					ResourceID: fmt.Sprintf("%d", instance.Id),
+					Name:       instance.Name,
+					Action:     "stop",
+					Reason:     fmt.Sprintf("CPU below 5%% for %.1f hours in zone %s", idleHoursThreshold, zone.Name),
+				}, func() error {
+					_, err := computeService.Instances.Stop(projectID, zone.Name, instance.Name).Context(ctx).Do()
+					if err != nil {
+						return err
+					}
+					fmt.Printf("Stopped idle GCP instance: %s (%s) in zone %s\n", instance.Name, instanceEnvironment(instance.Labels), zone.Name)
+					return nil
+				})
 				if err != nil {
 					fmt.Printf("Error stopping idle GCP instance %s: %v\n", instance.Name, err)
 					continue
 				}
-				fmt.Printf("Stopped idle GCP instance: %s in zone %s\n", instance.Name, zone.Name)
-				count++
+				count = run.acted()
 			}
 		}
 	}
@@ -0,0 +1,47 @@
+package cloud
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+)
+
+func TestIsAzureSpotVM(t *testing.T) {
+	withPriority := func(priority armcompute.VirtualMachinePriorityTypes) *armcompute.VirtualMachine {
+		return &armcompute.VirtualMachine{Properties: &armcompute.VirtualMachineProperties{Priority: &priority}}
+	}
+	tests := []struct {
+		name string
+		vm   *armcompute.VirtualMachine
+		want bool
+	}{
+		{"spot", withPriority(armcompute.VirtualMachinePriorityTypesSpot), true},
+		{"low", withPriority(armcompute.VirtualMachinePriorityTypesLow), true},
+		{"regular", withPriority(armcompute.VirtualMachinePriorityTypesRegular), false},
+		{"unset", &armcompute.VirtualMachine{Properties: &armcompute.VirtualMachineProperties{}}, false},
+		{"no properties", &armcompute.VirtualMachine{}, false},
+		{"nil", nil, false},
+	}
+	for _, tt := range tests {
+		if got := isAzureSpotVM(tt.vm); got != tt.want {
+			t.Errorf("%s: isAzureSpotVM = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAzureTagIsTrue(t *testing.T) {
+	tags := map[string]*string{
+		"fault-tolerant": to.Ptr("TRUE"),
+		"batch":          to.Ptr("yes"),
+		"empty":          nil,
+	}
+	if !azureTagIsTrue(tags, "fault-tolerant") {
+		t.Error("tag values should be compared case-insensitively")
+	}
+	for _, key := range []string{"batch", "empty", "missing"} {
+		if azureTagIsTrue(tags, key) {
+			t.Errorf("%s shouldn't count as true", key)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package cloud
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	ocicommon "github.com/oracle/oci-go-sdk/v65/common"
+	"google.golang.org/api/googleapi"
+)
+
+// awsCredentialErrorCodes are AWS error codes for missing, invalid or unauthorized credentials
+var awsCredentialErrorCodes = map[string]bool{
+	"AccessDenied":                true,
+	"AccessDeniedException":       true,
+	"AuthFailure":                 true,
+	"ExpiredToken":                true,
+	"ExpiredTokenException":       true,
+	"InvalidClientTokenId":        true,
+	"NoCredentialProviders":       true,
+	"SignatureDoesNotMatch":       true,
+	"UnauthorizedOperation":       true,
+	"UnrecognizedClientException": true,
+}
+
+// credentialErrorMarkers match credential failures that only survive as error text, e.g. after
+// an SDK error was formatted with %v
+var credentialErrorMarkers = []string{
+	"access denied",
+	"accessdenied",
+	"authentication failed",
+	"authorizationfailed",
+	"expiredtoken",
+	"invalid_client",
+	"invalidclienttokenid",
+	"notauthenticated",
+	"permission denied",
+	"requires mfa",
+	"unauthorized",
+	"failed to parse credentials",
+}
+
+// IsCredentialError reports whether a provider call failed because its credentials are missing,
+// invalid, expired or lack permission, as opposed to a network, throttling or service error that
+// a later attempt may get past
+func IsCredentialError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) && awsCredentialErrorCodes[awsErr.Code()] {
+		return true
+	}
+	var authErr *azidentity.AuthenticationFailedError
+	if errors.As(err, &authErr) {
+		return true
+	}
+	var azureErr *azcore.ResponseError
+	if errors.As(err, &azureErr) && isAuthStatus(azureErr.StatusCode) {
+		return true
+	}
+	var gcpErr *googleapi.Error
+	if errors.As(err, &gcpErr) && isAuthStatus(gcpErr.Code) {
+		return true
+	}
+	var ociErr ocicommon.ServiceError
+	if errors.As(err, &ociErr) && isAuthStatus(ociErr.GetHTTPStatusCode()) {
+		return true
+	}
+
+	message := strings.ToLower(err.Error())
+	for _, marker := range credentialErrorMarkers {
+		if strings.Contains(message, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+func isAuthStatus(status int) bool {
+	return status == http.StatusUnauthorized || status == http.StatusForbidden
+}
@@ -0,0 +1,39 @@
+package cloud
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"google.golang.org/api/googleapi"
+)
+
+func TestIsCredentialError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"AWS invalid token", awserr.New("InvalidClientTokenId", "invalid", nil), true},
+		{"AWS access denied, wrapped", fmt.Errorf("failed to get cost: %w", awserr.New("AccessDeniedException", "denied", nil)), true},
+		{"AWS throttling", awserr.New("ThrottlingException", "rate exceeded", nil), false},
+		{"Azure 403", &azcore.ResponseError{StatusCode: http.StatusForbidden}, true},
+		{"Azure 429", &azcore.ResponseError{StatusCode: http.StatusTooManyRequests}, false},
+		{"GCP 401", &googleapi.Error{Code: http.StatusUnauthorized}, true},
+		{"GCP 500", &googleapi.Error{Code: http.StatusInternalServerError}, false},
+		{"MFA session expired", errors.New("role arn:aws:iam::1:role/x requires MFA: submit a fresh tokenCode to start a session"), true},
+		{"formatted permission error", fmt.Errorf("query failed: %v", errors.New("googleapi: Error 403: Permission denied on dataset")), true},
+		{"malformed credentials", errors.New("failed to parse credentials: unexpected end of JSON input"), true},
+		{"network", errors.New("dial tcp 10.0.0.1:443: i/o timeout"), false},
+	}
+
+	for _, tt := range tests {
+		if got := IsCredentialError(tt.err); got != tt.want {
+			t.Errorf("%s: IsCredentialError = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
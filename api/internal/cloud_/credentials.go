@@ -0,0 +1,43 @@
+package cloud
+
+import "strings"
+
+// MissingCredentials returns the required credential fields a provider of the given type lacks,
+// in a stable order, with alternatives joined by " or ". The requirements are the registered
+// Provider's RequiredCredentials; unregistered types never miss any.
+func MissingCredentials(providerType string, credentials map[string]interface{}) []string {
+	p, ok := ProviderFor(providerType)
+	if !ok {
+		return nil
+	}
+	var missing []string
+	for _, requirement := range p.RequiredCredentials() {
+		alternatives := strings.Split(requirement, "|")
+		satisfied := false
+		for _, field := range alternatives {
+			if credentialSet(credentials[field]) {
+				satisfied = true
+				break
+			}
+		}
+		if !satisfied {
+			missing = append(missing, strings.Join(alternatives, " or "))
+		}
+	}
+	return missing
+}
+
+// credentialSet reports whether a credential value is present: a non-blank string or true.
+// useInstanceRole: false doesn't stand in for a roleArn.
+func credentialSet(value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v) != ""
+	case bool:
+		return v
+	case nil:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,46 @@
+package cloud
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMissingCredentialsBuiltInProviders(t *testing.T) {
+	tests := []struct {
+		providerType string
+		credentials  map[string]interface{}
+		want         []string
+	}{
+		{"aws", map[string]interface{}{"roleArn": "arn:aws:iam::123456789012:role/finops"}, nil},
+		{"aws", map[string]interface{}{"useInstanceRole": true}, nil},
+		// useInstanceRole: false doesn't stand in for a roleArn
+		{"aws", map[string]interface{}{"useInstanceRole": false, "roleArn": "  "}, []string{"roleArn or useInstanceRole"}},
+		{"azure", map[string]interface{}{"tenantId": "t", "clientSecret": "s"}, []string{"clientId"}},
+		{"gcp", nil, []string{"serviceAccountKey"}},
+		{"oci", map[string]interface{}{"tenancyOcid": "t", "userOcid": "u", "fingerprint": "f", "privateKey": "k"}, nil},
+	}
+	for _, tt := range tests {
+		if got := MissingCredentials(tt.providerType, tt.credentials); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("MissingCredentials(%s, %v) = %v, want %v", tt.providerType, tt.credentials, got, tt.want)
+		}
+	}
+}
+
+func TestCredentialSet(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  bool
+	}{
+		{"key", true},
+		{" \t", false},
+		{true, true},
+		{false, false},
+		{nil, false},
+		{map[string]interface{}{"type": "service_account"}, true},
+	}
+	for _, tt := range tests {
+		if got := credentialSet(tt.value); got != tt.want {
+			t.Errorf("credentialSet(%#v) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
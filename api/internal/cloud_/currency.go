@@ -0,0 +1,185 @@
+package cloud
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	config "finopsbridge/api/internal/config_"
+)
+
+// DefaultCurrency returns the currency assumed for billing data that doesn't report one
+func DefaultCurrency(cfg *config.Config) string {
+	if cfg.DefaultCurrency == "" {
+		return "USD"
+	}
+	return strings.ToUpper(cfg.DefaultCurrency)
+}
+
+// ConvertCurrency converts an amount into the configured reporting currency using the
+// configured exchange rate source. An empty currency is taken to be the default currency. It
+// returns false when no rate is known for the currency.
+func ConvertCurrency(amount float64, currency string, cfg *config.Config) (float64, bool) {
+	currency = strings.ToUpper(currency)
+	if currency == "" {
+		currency = DefaultCurrency(cfg)
+	}
+	if currency == strings.ToUpper(cfg.ReportingCurrency) {
+		return amount, true
+	}
+	rate, ok := RatesFor(cfg).Rate(currency)
+	if !ok || rate <= 0 {
+		return 0, false
+	}
+	return amount * rate, true
+}
+
+// RateSource supplies exchange rates into the reporting currency
+type RateSource interface {
+	// Rate returns the units of the reporting currency per unit of currency, and false when
+	// the source has no rate for it
+	Rate(currency string) (float64, bool)
+}
+
+// StaticRates are fixed exchange rates, as configured in EXCHANGE_RATES
+type StaticRates map[string]float64
+
+func (r StaticRates) Rate(currency string) (float64, bool) {
+	rate, ok := r[strings.ToUpper(currency)]
+	return rate, ok
+}
+
+// RatesFor returns the exchange rate source configured by cfg: rates fetched from
+// cfg.ExchangeRatesURL when set, falling back to the static cfg.ExchangeRates
+func RatesFor(cfg *config.Config) RateSource {
+	if cfg.ExchangeRatesURL == "" {
+		return StaticRates(cfg.ExchangeRates)
+	}
+	return fetchedRates.source(cfg)
+}
+
+// minRatesRefresh bounds ExchangeRatesRefreshMinutes, so a zero or tiny interval doesn't fetch
+// on every conversion
+const minRatesRefresh = time.Minute
+
+// urlRateCache holds the last rates fetched from an FX rates URL. A failed refresh keeps
+// serving the previous rates, and is retried after the same interval.
+type urlRateCache struct {
+	mu        sync.Mutex
+	now       func() time.Time
+	client    *http.Client
+	url       string
+	base      string
+	rates     map[string]float64
+	fetchedAt time.Time
+	fetching  bool // A fetch is in flight; other callers serve the current rates meanwhile
+}
+
+var fetchedRates = &urlRateCache{
+	now:    time.Now,
+	client: &http.Client{Timeout: 10 * time.Second},
+}
+
+// urlRates serves one fetch of rates, falling back to static rates for missing currencies
+type urlRates struct {
+	rates    map[string]float64
+	fallback StaticRates
+}
+
+func (r urlRates) Rate(currency string) (float64, bool) {
+	if rate, ok := r.rates[strings.ToUpper(currency)]; ok {
+		return rate, true
+	}
+	return r.fallback.Rate(currency)
+}
+
+// source returns the cached rates, starting a refresh once they are older than the refresh
+// interval. Only one fetch runs at a time, outside the lock: the first one blocks its caller,
+// since there are no rates to serve yet, and later ones run in the background while the
+// previous rates are served.
+func (c *urlRateCache) source(cfg *config.Config) RateSource {
+	fallback := StaticRates(cfg.ExchangeRates)
+	base := strings.ToUpper(cfg.ReportingCurrency)
+
+	c.mu.Lock()
+	if c.url != cfg.ExchangeRatesURL || c.base != base {
+		c.url, c.base, c.rates, c.fetchedAt, c.fetching = cfg.ExchangeRatesURL, base, nil, time.Time{}, false
+	}
+
+	refresh := time.Duration(cfg.ExchangeRatesRefreshMinutes) * time.Minute
+	if refresh < minRatesRefresh {
+		refresh = minRatesRefresh
+	}
+	now := c.now()
+	due := !c.fetching && (c.fetchedAt.IsZero() || now.Sub(c.fetchedAt) >= refresh)
+	if due {
+		c.fetching, c.fetchedAt = true, now
+	}
+	url, rates := c.url, c.rates
+	c.mu.Unlock()
+
+	if !due {
+		return urlRates{rates: rates, fallback: fallback}
+	}
+	if rates != nil {
+		go c.refresh(url, base)
+		return urlRates{rates: rates, fallback: fallback}
+	}
+	return urlRates{rates: c.refresh(url, base), fallback: fallback}
+}
+
+// refresh fetches rates from url and stores them, unless the configured URL or reporting
+// currency changed meanwhile. It returns the rates served afterwards.
+func (c *urlRateCache) refresh(url, base string) map[string]float64 {
+	rates, err := c.fetch(url, base)
+	if err != nil {
+		fmt.Printf("Error fetching exchange rates from %s: %v\n", url, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.url != url || c.base != base {
+		return rates
+	}
+	c.fetching = false
+	if err == nil {
+		c.rates = rates
+	}
+	return c.rates
+}
+
+// fetch reads rates quoted per unit of the reporting currency, e.g.
+// {"base": "USD", "rates": {"EUR": 0.92}}, and inverts them into units of the reporting
+// currency per unit of each currency
+func (c *urlRateCache) fetch(url, base string) (map[string]float64, error) {
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Base  string             `json:"base"`
+		Rates map[string]float64 `json:"rates"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to parse rates: %w", err)
+	}
+	if !strings.EqualFold(body.Base, base) {
+		return nil, fmt.Errorf("rates are quoted in %s, not the reporting currency %s", body.Base, base)
+	}
+
+	rates := make(map[string]float64, len(body.Rates))
+	for currency, perBase := range body.Rates {
+		if perBase > 0 {
+			rates[strings.ToUpper(currency)] = 1 / perBase
+		}
+	}
+	return rates, nil
+}
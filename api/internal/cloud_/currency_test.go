@@ -0,0 +1,142 @@
+package cloud
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	config "finopsbridge/api/internal/config_"
+)
+
+func TestConvertCurrencyStaticRates(t *testing.T) {
+	cfg := &config.Config{
+		ReportingCurrency: "USD",
+		DefaultCurrency:   "EUR",
+		ExchangeRates:     map[string]float64{"EUR": 1.1, "GBP": 1.25},
+	}
+
+	tests := []struct {
+		currency string
+		want     float64
+		ok       bool
+	}{
+		{"USD", 100, true},
+		{"usd", 100, true},
+		{"GBP", 125, true},
+		// An unreported currency is the default currency
+		{"", 110, true},
+		{"JPY", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := ConvertCurrency(100, tt.currency, cfg)
+		if ok != tt.ok || got < tt.want-1e-9 || got > tt.want+1e-9 {
+			t.Errorf("ConvertCurrency(100, %q) = %v, %v; want %v, %v", tt.currency, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDefaultCurrency(t *testing.T) {
+	if got := DefaultCurrency(&config.Config{}); got != "USD" {
+		t.Errorf("got %q, want USD when unset", got)
+	}
+	if got := DefaultCurrency(&config.Config{DefaultCurrency: "eur"}); got != "EUR" {
+		t.Errorf("got %q, want EUR", got)
+	}
+}
+
+// ratesServer serves rates per USD, failing with a 502 while fail is set, and counts requests
+func ratesServer(t *testing.T, calls *int32, fail *atomic.Bool) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(calls, 1)
+		if fail.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		fmt.Fprintf(w, `{"base": "usd", "rates": {"eur": %v, "JPY": 0}}`, 0.5*float64(n))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// waitForRefresh waits for a background refresh of c to finish
+func waitForRefresh(t *testing.T, c *urlRateCache) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		c.mu.Lock()
+		fetching := c.fetching
+		c.mu.Unlock()
+		if !fetching {
+			return
+		}
+	}
+	t.Fatal("the refresh didn't finish")
+}
+
+func TestURLRateCache(t *testing.T) {
+	var calls int32
+	var fail atomic.Bool
+	server := ratesServer(t, &calls, &fail)
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	c := &urlRateCache{now: func() time.Time { return now }, client: server.Client()}
+	cfg := &config.Config{
+		ReportingCurrency:           "USD",
+		ExchangeRatesURL:            server.URL,
+		ExchangeRatesRefreshMinutes: 60,
+		ExchangeRates:               map[string]float64{"GBP": 1.25, "EUR": 9},
+	}
+
+	// Rates are inverted into USD per unit, and static rates fill in missing currencies
+	rates := c.source(cfg)
+	if rate, ok := rates.Rate("EUR"); !ok || rate != 2 {
+		t.Errorf("EUR = %v, %v; want the fetched 2", rate, ok)
+	}
+	if rate, ok := rates.Rate("gbp"); !ok || rate != 1.25 {
+		t.Errorf("GBP = %v, %v; want the static 1.25", rate, ok)
+	}
+	if _, ok := rates.Rate("JPY"); ok {
+		t.Error("a zero rate should be dropped")
+	}
+
+	now = now.Add(30 * time.Minute)
+	c.source(cfg)
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("got %d fetches within the refresh interval, want 1", n)
+	}
+
+	// A failed refresh keeps serving the previous rates
+	fail.Store(true)
+	now = now.Add(time.Hour)
+	if rate, _ := c.source(cfg).Rate("EUR"); rate != 2 {
+		t.Errorf("EUR = %v during the refresh, want the previous 2", rate)
+	}
+	waitForRefresh(t, c)
+	if rate, _ := c.source(cfg).Rate("EUR"); rate != 2 || atomic.LoadInt32(&calls) != 2 {
+		t.Errorf("EUR = %v after %d fetches, want the previous 2 after 2", rate, atomic.LoadInt32(&calls))
+	}
+
+	fail.Store(false)
+	now = now.Add(time.Hour)
+	c.source(cfg)
+	waitForRefresh(t, c)
+	if rate, _ := c.source(cfg).Rate("EUR"); rate != 1/1.5 {
+		t.Errorf("EUR = %v, want the refreshed %v", rate, 1/1.5)
+	}
+}
+
+func TestURLRateCacheRejectsOtherBase(t *testing.T) {
+	var calls int32
+	var fail atomic.Bool
+	server := ratesServer(t, &calls, &fail)
+	c := &urlRateCache{now: time.Now, client: server.Client()}
+
+	rates := c.source(&config.Config{ReportingCurrency: "EUR", ExchangeRatesURL: server.URL, ExchangeRates: map[string]float64{"USD": 0.9}})
+	if _, ok := rates.Rate("JPY"); ok {
+		t.Error("rates quoted in USD shouldn't be used for EUR reporting")
+	}
+	if rate, ok := rates.Rate("USD"); !ok || rate != 0.9 {
+		t.Errorf("USD = %v, %v; want the static fallback 0.9", rate, ok)
+	}
+}
@@ -0,0 +1,227 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	config "finopsbridge/api/internal/config_"
+	models "finopsbridge/api/internal/models_"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+)
+
+// errNoGCPBillingExport is returned by openGCPBillingExport for providers without a
+// billingDataset; the Cloud Billing API has no cost data to query instead
+var errNoGCPBillingExport = errors.New("no GCP BigQuery billing export configured")
+
+// gcpBillingExport is a provider's BigQuery billing export, restricted to its billing scope
+type gcpBillingExport struct {
+	client    *bigquery.Client
+	dataset   string
+	tableRef  string
+	projectID string
+	scope     gcpBillingScope
+}
+
+// openGCPBillingExport connects to the billing export configured in a GCP provider's
+// credentials. Queries are billed to the provider's project. Close the export when done.
+func openGCPBillingExport(ctx context.Context, provider models.CloudProvider) (*gcpBillingExport, error) {
+	var credentials map[string]interface{}
+	if err := json.Unmarshal([]byte(provider.Credentials), &credentials); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials: %w", err)
+	}
+
+	serviceAccountJSON, _ := credentials["serviceAccountKey"].(string)
+	billingDataset, _ := credentials["billingDataset"].(string) // e.g., "project.dataset"
+	billingTable, _ := credentials["billingTable"].(string)     // e.g., "gcp_billing_export_v1_XXXXXX_XXXXXX"
+	projectID := provider.ProjectID
+
+	if serviceAccountJSON == "" || projectID == "" {
+		return nil, fmt.Errorf("missing GCP credentials (serviceAccountKey) or projectId")
+	}
+	if billingDataset == "" {
+		return nil, errNoGCPBillingExport
+	}
+	scope, err := parseGCPBillingScope(credentials, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Format: project.dataset.table
+	tableRef := billingDataset
+	if billingTable != "" {
+		tableRef = fmt.Sprintf("%s.%s", billingDataset, billingTable)
+	}
+
+	client, err := bigquery.NewClient(ctx, projectID, option.WithCredentialsJSON([]byte(serviceAccountJSON)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create BigQuery client: %w", err)
+	}
+
+	return &gcpBillingExport{
+		client:    client,
+		dataset:   billingDataset,
+		tableRef:  tableRef,
+		projectID: projectID,
+		scope:     scope,
+	}, nil
+}
+
+func (e *gcpBillingExport) Close() error {
+	return e.client.Close()
+}
+
+// monthToDateCosts sums the scope's cost from the start of now's month to now, grouped by
+// groupKey, an expression over the export row aliased as billing. join is added after the
+// table, e.g. to unnest labels, and params bind its parameters. Rows without a key are grouped
+// under missingKey.
+func (e *gcpBillingExport) monthToDateCosts(ctx context.Context, cfg *config.Config, now time.Time, groupKey, join string, params []bigquery.QueryParameter, missingKey string) ([]CostBreakdownItem, error) {
+	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	// Only the table reference from credentials, the fixed group expression and join, and the
+	// scope condition are formatted into the SQL, as table names can't be parameterized
+	scopeFilter, scopeParam := e.scope.filter()
+	query := fmt.Sprintf(`
+		SELECT
+			%s AS group_key,
+			SUM(billing.cost) AS cost,
+			billing.currency AS currency
+		FROM `+"`%s`"+` AS billing
+		%s
+		WHERE %s
+		AND DATE(billing.usage_start_time) >= @startDate
+		AND DATE(billing.usage_start_time) <= @endDate
+		GROUP BY group_key, currency
+	`, groupKey, e.tableRef, join, scopeFilter)
+
+	q := e.client.Query(query)
+	q.Parameters = append([]bigquery.QueryParameter{
+		scopeParam,
+		{Name: "startDate", Value: startOfMonth.Format("2006-01-02")},
+		{Name: "endDate", Value: now.Format("2006-01-02")},
+	}, params...)
+
+	it, err := q.Read(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query GCP billing export: %w", err)
+	}
+	return aggregateGCPCostRows(it, missingKey, cfg)
+}
+
+// gcpCostRow is one grouped row of a GCP billing export query. group_key is NULL for usage
+// without the grouped label.
+type gcpCostRow struct {
+	GroupKey bigquery.NullString `bigquery:"group_key"`
+	Cost     float64             `bigquery:"cost"`
+	Currency string              `bigquery:"currency"`
+}
+
+// gcpRowIterator is the subset of *bigquery.RowIterator used to read grouped cost rows
+type gcpRowIterator interface {
+	Next(dst interface{}) error
+}
+
+// aggregateGCPCostRows sums grouped GCP cost rows per key, sorted by cost descending. Rows
+// without a key are grouped under missingKey. A scope spanning billing accounts can report a
+// key in several currencies; those amounts are converted into the reporting currency, except
+// ones without a known rate, which are kept as separate items in their own currency.
+func aggregateGCPCostRows(it gcpRowIterator, missingKey string, cfg *config.Config) ([]CostBreakdownItem, error) {
+	totals := make(map[string]map[string]float64) // Cost per key per currency
+	var keys []string
+
+	for {
+		var row gcpCostRow
+		err := it.Next(&row)
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read BigQuery results: %w", err)
+		}
+
+		key := missingKey
+		if row.GroupKey.Valid && row.GroupKey.StringVal != "" {
+			key = row.GroupKey.StringVal
+		}
+		currency := row.Currency
+		if currency == "" {
+			currency = DefaultCurrency(cfg)
+		}
+
+		if _, exists := totals[key]; !exists {
+			totals[key] = make(map[string]float64)
+			keys = append(keys, key)
+		}
+		totals[key][currency] += row.Cost
+	}
+
+	result := make([]CostBreakdownItem, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, mergeCurrencies(key, totals[key], cfg)...)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Cost > result[j].Cost
+	})
+
+	return result, nil
+}
+
+// mergeCurrencies returns a key's cost as one item when it was all in one currency, and
+// otherwise converted into the reporting currency where a rate is known
+func mergeCurrencies(key string, costs map[string]float64, cfg *config.Config) []CostBreakdownItem {
+	if len(costs) == 1 {
+		for currency, cost := range costs {
+			return []CostBreakdownItem{{Key: key, Cost: cost, Currency: currency}}
+		}
+	}
+
+	currencies := make([]string, 0, len(costs))
+	for currency := range costs {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	converted := CostBreakdownItem{Key: key, Currency: cfg.ReportingCurrency}
+	var items []CostBreakdownItem
+	for _, currency := range currencies {
+		if amount, ok := ConvertCurrency(costs[currency], currency, cfg); ok {
+			converted.Cost += amount
+		} else {
+			items = append(items, CostBreakdownItem{Key: key, Cost: costs[currency], Currency: currency})
+		}
+	}
+	if len(items) < len(currencies) {
+		items = append([]CostBreakdownItem{converted}, items...)
+	}
+	return items
+}
+
+// sumCostItems totals breakdown items in their shared currency, or in the reporting currency
+// when they differ. Currencies without a known rate are left out of a converted total and
+// returned, sorted.
+func sumCostItems(items []CostBreakdownItem, cfg *config.Config) (float64, string, []string) {
+	costs := make(map[string]float64)
+	for _, item := range items {
+		costs[item.Currency] += item.Cost
+	}
+
+	var total float64
+	var unconverted []string
+	for _, item := range mergeCurrencies("", costs, cfg) {
+		if len(costs) > 1 && item.Currency != cfg.ReportingCurrency {
+			unconverted = append(unconverted, item.Currency)
+			continue
+		}
+		total += item.Cost
+	}
+	if len(costs) == 1 {
+		return total, items[0].Currency, nil
+	}
+	return total, cfg.ReportingCurrency, unconverted
+}
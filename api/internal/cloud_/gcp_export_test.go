@@ -0,0 +1,93 @@
+package cloud
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	config "finopsbridge/api/internal/config_"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/iterator"
+)
+
+// fakeRowIterator yields the given rows, then err (iterator.Done when nil)
+type fakeRowIterator struct {
+	rows []gcpCostRow
+	err  error
+}
+
+func (it *fakeRowIterator) Next(dst interface{}) error {
+	if len(it.rows) == 0 {
+		if it.err != nil {
+			return it.err
+		}
+		return iterator.Done
+	}
+	*dst.(*gcpCostRow) = it.rows[0]
+	it.rows = it.rows[1:]
+	return nil
+}
+
+func gcpRow(key string, cost float64, currency string) gcpCostRow {
+	row := gcpCostRow{Cost: cost, Currency: currency}
+	if key != "" {
+		row.GroupKey = bigquery.NullString{StringVal: key, Valid: true}
+	}
+	return row
+}
+
+func TestAggregateGCPCostRows(t *testing.T) {
+	cfg := &config.Config{ReportingCurrency: "USD"}
+	it := &fakeRowIterator{rows: []gcpCostRow{
+		gcpRow("Compute Engine", 10, "USD"),
+		gcpRow("Cloud Storage", 4, "USD"),
+		gcpRow("", 1, ""),
+		gcpRow("Compute Engine", 20, "USD"),
+	}}
+
+	got, err := aggregateGCPCostRows(it, "unlabeled", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []CostBreakdownItem{
+		{Key: "Compute Engine", Cost: 30, Currency: "USD"},
+		{Key: "Cloud Storage", Cost: 4, Currency: "USD"},
+		{Key: "unlabeled", Cost: 1, Currency: "USD"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestAggregateGCPCostRowsMergesCurrencies(t *testing.T) {
+	cfg := &config.Config{
+		ReportingCurrency: "USD",
+		ExchangeRates:     map[string]float64{"EUR": 2},
+	}
+	it := &fakeRowIterator{rows: []gcpCostRow{
+		gcpRow("Compute Engine", 10, "USD"),
+		gcpRow("Compute Engine", 5, "EUR"),
+		gcpRow("Compute Engine", 3, "JPY"),
+	}}
+
+	got, err := aggregateGCPCostRows(it, "unlabeled", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Amounts without a known rate stay separate, in their own currency
+	want := []CostBreakdownItem{
+		{Key: "Compute Engine", Cost: 20, Currency: "USD"},
+		{Key: "Compute Engine", Cost: 3, Currency: "JPY"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestAggregateGCPCostRowsReadError(t *testing.T) {
+	it := &fakeRowIterator{rows: []gcpCostRow{gcpRow("Compute Engine", 10, "USD")}, err: errors.New("quota exceeded")}
+	if _, err := aggregateGCPCostRows(it, "unlabeled", &config.Config{}); err == nil {
+		t.Error("a read error should be returned")
+	}
+}
@@ -0,0 +1,66 @@
+package cloud
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"cloud.google.com/go/bigquery"
+)
+
+// GCP billing scope levels
+const (
+	GCPScopeProject      = "project"
+	GCPScopeFolder       = "folder"
+	GCPScopeOrganization = "organization"
+)
+
+// gcpNumericIDPattern matches GCP folder and organization IDs, which are numeric
+var gcpNumericIDPattern = regexp.MustCompile(`^[0-9]+$`)
+
+// gcpBillingScope is the part of the resource hierarchy a GCP provider's BigQuery billing
+// covers: its own project, or every project under a folder or organization
+type gcpBillingScope struct {
+	Level string
+	ID    string
+}
+
+// parseGCPBillingScope reads the scope from a provider's credentials: folderId or organizationId
+// (with or without the folders/ or organizations/ prefix), or else the provider's project.
+func parseGCPBillingScope(credentials map[string]interface{}, projectID string) (gcpBillingScope, error) {
+	folderID, _ := credentials["folderId"].(string)
+	organizationID, _ := credentials["organizationId"].(string)
+	folderID = strings.TrimPrefix(strings.TrimSpace(folderID), "folders/")
+	organizationID = strings.TrimPrefix(strings.TrimSpace(organizationID), "organizations/")
+
+	switch {
+	case folderID != "" && organizationID != "":
+		return gcpBillingScope{}, fmt.Errorf("set either folderId or organizationId, not both")
+	case folderID != "":
+		if !gcpNumericIDPattern.MatchString(folderID) {
+			return gcpBillingScope{}, fmt.Errorf("invalid GCP folder ID %q", folderID)
+		}
+		return gcpBillingScope{Level: GCPScopeFolder, ID: folderID}, nil
+	case organizationID != "":
+		if !gcpNumericIDPattern.MatchString(organizationID) {
+			return gcpBillingScope{}, fmt.Errorf("invalid GCP organization ID %q", organizationID)
+		}
+		return gcpBillingScope{Level: GCPScopeOrganization, ID: organizationID}, nil
+	}
+	return gcpBillingScope{Level: GCPScopeProject, ID: projectID}, nil
+}
+
+// filter returns the condition selecting the scope's rows from a billing export aliased as
+// billing, and the parameter it binds. Folders and organizations match projects by their
+// ancestry, which the export records per row, so projects moved since are billed where they were.
+func (s gcpBillingScope) filter() (string, bigquery.QueryParameter) {
+	switch s.Level {
+	case GCPScopeFolder:
+		return `EXISTS (SELECT 1 FROM UNNEST(billing.project.ancestors) AS ancestor WHERE ancestor.resource_name = @scopeId)`,
+			bigquery.QueryParameter{Name: "scopeId", Value: "folders/" + s.ID}
+	case GCPScopeOrganization:
+		return `EXISTS (SELECT 1 FROM UNNEST(billing.project.ancestors) AS ancestor WHERE ancestor.resource_name = @scopeId)`,
+			bigquery.QueryParameter{Name: "scopeId", Value: "organizations/" + s.ID}
+	}
+	return `billing.project.id = @scopeId`, bigquery.QueryParameter{Name: "scopeId", Value: s.ID}
+}
@@ -0,0 +1,62 @@
+package cloud
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseGCPBillingScope(t *testing.T) {
+	tests := []struct {
+		credentials map[string]interface{}
+		want        gcpBillingScope
+	}{
+		{map[string]interface{}{}, gcpBillingScope{Level: GCPScopeProject, ID: "my-project"}},
+		{map[string]interface{}{"folderId": "123456"}, gcpBillingScope{Level: GCPScopeFolder, ID: "123456"}},
+		{map[string]interface{}{"folderId": " folders/123456 "}, gcpBillingScope{Level: GCPScopeFolder, ID: "123456"}},
+		{map[string]interface{}{"organizationId": "organizations/987"}, gcpBillingScope{Level: GCPScopeOrganization, ID: "987"}},
+		{map[string]interface{}{"folderId": "", "organizationId": "987"}, gcpBillingScope{Level: GCPScopeOrganization, ID: "987"}},
+	}
+	for _, tt := range tests {
+		got, err := parseGCPBillingScope(tt.credentials, "my-project")
+		if err != nil {
+			t.Errorf("parseGCPBillingScope(%v): %v", tt.credentials, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseGCPBillingScope(%v) = %+v, want %+v", tt.credentials, got, tt.want)
+		}
+	}
+}
+
+func TestParseGCPBillingScopeRejects(t *testing.T) {
+	for _, credentials := range []map[string]interface{}{
+		{"folderId": "123", "organizationId": "987"},
+		{"folderId": "engineering"},
+		{"organizationId": "organizations/example.com"},
+	} {
+		if _, err := parseGCPBillingScope(credentials, "my-project"); err == nil {
+			t.Errorf("%v should be rejected", credentials)
+		}
+	}
+}
+
+func TestGCPBillingScopeFilter(t *testing.T) {
+	tests := []struct {
+		scope     gcpBillingScope
+		condition string
+		value     string
+	}{
+		{gcpBillingScope{Level: GCPScopeProject, ID: "my-project"}, "billing.project.id = @scopeId", "my-project"},
+		{gcpBillingScope{Level: GCPScopeFolder, ID: "123"}, "billing.project.ancestors", "folders/123"},
+		{gcpBillingScope{Level: GCPScopeOrganization, ID: "987"}, "billing.project.ancestors", "organizations/987"},
+	}
+	for _, tt := range tests {
+		condition, param := tt.scope.filter()
+		if !strings.Contains(condition, tt.condition) || !strings.Contains(condition, "@scopeId") {
+			t.Errorf("%s filter = %q, want it to use %q", tt.scope.Level, condition, tt.condition)
+		}
+		if param.Name != "scopeId" || param.Value != tt.value {
+			t.Errorf("%s parameter = %+v, want scopeId = %q", tt.scope.Level, param, tt.value)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	config "finopsbridge/api/internal/config_"
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	compute "google.golang.org/api/compute/v1"
+	"google.golang.org/api/option"
+)
+
+// ErrInstanceStopNotSupported is returned by StopInstance for provider types it can't act on
+var ErrInstanceStopNotSupported = errors.New("stopping a single instance is not supported for this provider type")
+
+// StopInstance stops one instance reported from outside the provider's own listing, such as an
+// idle GPU instance from GPU metrics. instanceID is the EC2 instance ID, the Azure VM's full
+// resource ID, or the GCP instance name, which also needs its zone. Like the other remediation
+// functions it skips instances tagged Essential and honors opts.
+func StopInstance(ctx context.Context, provider models.CloudProvider, cfg *config.Config, instanceID, zone, reason string, opts RemediationOptions) (RemediationResult, error) {
+	p, ok := capability[InstanceStopper](provider.Type)
+	if !ok {
+		return RemediationResult{}, ErrInstanceStopNotSupported
+	}
+	return p.StopInstance(ctx, provider, cfg, instanceID, zone, reason, opts)
+}
+
+func stopAWSInstance(ctx context.Context, provider models.CloudProvider, cfg *config.Config, instanceID, reason string, run *remediationRun) error {
+	sess, err := newAWSSession(provider, cfg)
+	if err != nil {
+		return err
+	}
+	ec2Svc := ec2.New(sess)
+
+	result, err := ec2Svc.DescribeInstancesWithContext(ctx, &ec2.DescribeInstancesInput{
+		InstanceIds: []*string{aws.String(instanceID)},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to describe instance %s: %w", instanceID, err)
+	}
+
+	for _, reservation := range result.Reservations {
+		for _, instance := range reservation.Instances {
+			tags := awsTagMap(instance.Tags)
+			if !run.selects(awsInstanceName(instance), cfg.AWSRegion, tags) || tags["Essential"] == "true" ||
+				run.tooNew(instanceID, derefTime(instance.LaunchTime)) {
+				return nil
+			}
+			run.act(RemediationCandidate{
+				ResourceID: instanceID,
+				Name:       awsInstanceName(instance),
+				Action:     "stop",
+				Reason:     reason,
+			}, func() error {
+				_, err := ec2Svc.StopInstancesWithContext(ctx, &ec2.StopInstancesInput{
+					InstanceIds: []*string{instance.InstanceId},
+				})
+				return err
+			})
+		}
+	}
+	return nil
+}
+
+func stopAzureInstance(ctx context.Context, provider models.CloudProvider, vmID, reason string, run *remediationRun) error {
+	var credentials map[string]interface{}
+	if err := json.Unmarshal([]byte(provider.Credentials), &credentials); err != nil {
+		return fmt.Errorf("failed to parse credentials: %w", err)
+	}
+
+	tenantID, _ := credentials["tenantId"].(string)
+	clientID, _ := credentials["clientId"].(string)
+	clientSecret, _ := credentials["clientSecret"].(string)
+	subscriptionIDs := AzureSubscriptionIDs(provider)
+
+	if tenantID == "" || clientID == "" || clientSecret == "" || len(subscriptionIDs) == 0 {
+		return fmt.Errorf("missing Azure credentials or subscriptionId")
+	}
+
+	subscriptionID := azureSubscriptionFromID(vmID)
+	resourceGroup := extractResourceGroupFromID(vmID)
+	name := vmID[strings.LastIndex(vmID, "/")+1:]
+	if subscriptionID == "" || resourceGroup == "" || name == "" {
+		return fmt.Errorf("expected a full VM resource ID, got %q", vmID)
+	}
+	covered := false
+	for _, id := range subscriptionIDs {
+		covered = covered || strings.EqualFold(id, subscriptionID)
+	}
+	if !covered {
+		return fmt.Errorf("VM %s is in subscription %s, which provider %s doesn't cover", name, subscriptionID, provider.Name)
+	}
+
+	cred, err := azidentity.NewClientSecretCredential(tenantID, clientID, clientSecret, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create Azure credential: %w", err)
+	}
+	vmClient, err := armcompute.NewVirtualMachinesClient(subscriptionID, cred, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create VM client: %w", err)
+	}
+
+	vm, err := vmClient.Get(ctx, resourceGroup, name, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get VM %s: %w", name, err)
+	}
+	if !run.selects(name, derefString(vm.Location), azureTagMap(vm.Tags)) || azureTagIsTrue(vm.Tags, "Essential") ||
+		run.tooNew(vmID, azureVMCreated(&vm.VirtualMachine)) {
+		return nil
+	}
+
+	run.act(RemediationCandidate{
+		ResourceID: vmID,
+		Name:       name,
+		Action:     "stop",
+		Reason:     reason,
+	}, func() error {
+		poller, err := vmClient.BeginDeallocate(ctx, resourceGroup, name, nil)
+		if err != nil {
+			return err
+		}
+		_, err = poller.PollUntilDone(ctx, nil)
+		return err
+	})
+	return nil
+}
+
+func stopGCPInstance(ctx context.Context, provider models.CloudProvider, name, zone, reason string, run *remediationRun) error {
+	if zone == "" {
+		return fmt.Errorf("zone is required to stop GCP instance %s", name)
+	}
+
+	var credentials map[string]interface{}
+	if err := json.Unmarshal([]byte(provider.Credentials), &credentials); err != nil {
+		return fmt.Errorf("failed to parse credentials: %w", err)
+	}
+
+	serviceAccountJSON, _ := credentials["serviceAccountKey"].(string)
+	projectID := provider.ProjectID
+
+	if serviceAccountJSON == "" || projectID == "" {
+		return fmt.Errorf("missing GCP credentials (serviceAccountKey) or projectId")
+	}
+
+	computeService, err := compute.NewService(ctx, option.WithCredentialsJSON([]byte(serviceAccountJSON)))
+	if err != nil {
+		return fmt.Errorf("failed to create compute service: %w", err)
+	}
+
+	instance, err := computeService.Instances.Get(projectID, zone, name).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to get instance %s: %w", name, err)
+	}
+	if !run.selects(instance.Name, zone, instance.Labels) || instance.Labels["essential"] == "true" ||
+		run.tooNew(instance.Name, parseGCPTimestamp(instance.CreationTimestamp)) {
+		return nil
+	}
+
+	run.act(RemediationCandidate{
+		ResourceID: fmt.Sprintf("%d", instance.Id),
+		Name:       instance.Name,
+		Action:     "stop",
+		Reason:     reason,
+	}, func() error {
+		_, err := computeService.Instances.Stop(projectID, zone, instance.Name).Context(ctx).Do()
+		return err
+	})
+	return nil
+}
@@ -0,0 +1,236 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	config "finopsbridge/api/internal/config_"
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// ErrInventoryNotSupported is returned by ListInstances for provider types it can't list
+var ErrInventoryNotSupported = errors.New("instance inventory not supported for this provider type")
+
+// maxInventoryInstances caps how many instances one listing returns, so a large account
+// doesn't turn one enforcement run into tens of thousands of policy evaluations
+const maxInventoryInstances = 1000
+
+// Page sizes for ListInstancePage
+const (
+	DefaultInstancePageSize = 100
+	MaxInstancePageSize     = 500
+)
+
+// ListOptions selects one page of an instance listing
+type ListOptions struct {
+	PageToken  string // Next page token from the previous page; empty for the first
+	MaxResults int    // Zero means DefaultInstancePageSize; capped at MaxInstancePageSize
+}
+
+func (o ListOptions) pageSize() int {
+	if o.MaxResults <= 0 {
+		return DefaultInstancePageSize
+	}
+	return min(o.MaxResults, MaxInstancePageSize)
+}
+
+// ListInstancePage lists one page of a provider's compute instances, and the token of the
+// next page, empty after the last
+func ListInstancePage(ctx context.Context, provider models.CloudProvider, cfg *config.Config, opts ListOptions) ([]Instance, string, error) {
+	p, ok := ProviderFor(provider.Type)
+	if !ok {
+		return nil, "", ErrInventoryNotSupported
+	}
+	return p.ListInstancePage(ctx, provider, cfg, opts)
+}
+
+// Instance is a compute instance with the attributes resource-scoped policies are evaluated
+// against, normalized across providers
+type Instance struct {
+	ID           string            `json:"id"`
+	Name         string            `json:"name"`
+	InstanceType string            `json:"instanceType"` // EC2 instance type, Azure VM size, GCP machine type, OCI shape or IBM profile
+	Region       string            `json:"region"`
+	Zone         string            `json:"zone,omitempty"` // GCP zone, OCI availability domain or IBM zone
+	State        string            `json:"state"`
+	Tags         map[string]string `json:"tags"`       // GCP labels and OCI freeform tags; IBM instances have none
+	LaunchedAt   time.Time         `json:"launchedAt"` // Zero when the provider doesn't report it
+}
+
+// ListInstances lists a provider's compute instances page by page, up to
+// maxInventoryInstances
+func ListInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config) ([]Instance, error) {
+	var instances []Instance
+	opts := ListOptions{MaxResults: MaxInstancePageSize}
+	for len(instances) < maxInventoryInstances {
+		page, next, err := ListInstancePage(ctx, provider, cfg, opts)
+		if err != nil {
+			return nil, err
+		}
+		instances = append(instances, page...)
+		if next == "" {
+			break
+		}
+		opts.PageToken = next
+	}
+	if len(instances) > maxInventoryInstances {
+		instances = instances[:maxInventoryInstances]
+	}
+	return instances, nil
+}
+
+// InstanceSizeLevel places an instance type on the size scale generated block_instance_type
+// policies compare against: 1 small, 2 medium, 3 large, 4 xlarge and above. It returns 0 for
+// types whose name doesn't say, e.g. GCP's n2-standard-4.
+func InstanceSizeLevel(instanceType string) int {
+	lower := strings.ToLower(instanceType)
+	switch {
+	case strings.Contains(lower, "xlarge") || strings.Contains(lower, "metal"):
+		return 4
+	case strings.Contains(lower, "large"):
+		return 3
+	case strings.Contains(lower, "medium"):
+		return 2
+	case strings.Contains(lower, "small") || strings.Contains(lower, "micro") || strings.Contains(lower, "nano"):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// minAWSPageSize is the smallest MaxResults DescribeInstances accepts
+const minAWSPageSize = 5
+
+// listAWSInstancePage lists one page of running and stopped EC2 instances. A page may hold up
+// to minAWSPageSize instances when fewer are asked for.
+func listAWSInstancePage(ctx context.Context, provider models.CloudProvider, cfg *config.Config, opts ListOptions) ([]Instance, string, error) {
+	sess, err := newAWSSession(provider, cfg)
+	if err != nil {
+		return nil, "", err
+	}
+
+	input := &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("instance-state-name"),
+				Values: []*string{aws.String("pending"), aws.String("running"), aws.String("stopping"), aws.String("stopped")},
+			},
+		},
+		MaxResults: aws.Int64(int64(max(opts.pageSize(), minAWSPageSize))),
+	}
+	if opts.PageToken != "" {
+		input.NextToken = aws.String(opts.PageToken)
+	}
+	page, err := ec2.New(sess).DescribeInstancesWithContext(ctx, input)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list instances: %w", err)
+	}
+
+	var instances []Instance
+	for _, reservation := range page.Reservations {
+		for _, instance := range reservation.Instances {
+			state := ""
+			if instance.State != nil {
+				state = derefString(instance.State.Name)
+			}
+			instances = append(instances, Instance{
+				ID:           derefString(instance.InstanceId),
+				Name:         awsInstanceName(instance),
+				InstanceType: derefString(instance.InstanceType),
+				Region:       cfg.AWSRegion,
+				State:        state,
+				Tags:         awsTagMap(instance.Tags),
+				LaunchedAt:   derefTime(instance.LaunchTime),
+			})
+		}
+	}
+	return instances, derefString(page.NextToken), nil
+}
+
+// listAzureInstancePage lists one page of the VMs across a provider's subscriptions. Azure's
+// pagers can't be resumed from a token, so the page token is the offset of the page's first
+// VM in the listing, and each page re-reads the VMs before it.
+func listAzureInstancePage(ctx context.Context, provider models.CloudProvider, opts ListOptions) ([]Instance, string, error) {
+	offset := 0
+	if opts.PageToken != "" {
+		parsed, err := strconv.Atoi(opts.PageToken)
+		if err != nil || parsed < 0 {
+			return nil, "", fmt.Errorf("invalid page token %q", opts.PageToken)
+		}
+		offset = parsed
+	}
+
+	var credentials map[string]interface{}
+	if err := json.Unmarshal([]byte(provider.Credentials), &credentials); err != nil {
+		return nil, "", fmt.Errorf("failed to parse credentials: %w", err)
+	}
+
+	tenantID, _ := credentials["tenantId"].(string)
+	clientID, _ := credentials["clientId"].(string)
+	clientSecret, _ := credentials["clientSecret"].(string)
+	subscriptionIDs := AzureSubscriptionIDs(provider)
+	regions := AzureRegions(provider)
+
+	if tenantID == "" || clientID == "" || clientSecret == "" || len(subscriptionIDs) == 0 {
+		return nil, "", fmt.Errorf("missing Azure credentials or subscriptionId")
+	}
+
+	cred, err := azidentity.NewClientSecretCredential(tenantID, clientID, clientSecret, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create Azure credential: %w", err)
+	}
+
+	pageSize := opts.pageSize()
+	instances := make([]Instance, 0, pageSize)
+	seen := 0 // VMs listed so far, including those before the page
+	for _, subscriptionID := range subscriptionIDs {
+		vmClient, err := armcompute.NewVirtualMachinesClient(subscriptionID, cred, nil)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to create VM client: %w", err)
+		}
+
+		pager := newAzureVMPager(vmClient, regions)
+		for pager.More() {
+			page, err := pager.NextPage(ctx)
+			if err != nil {
+				return nil, "", fmt.Errorf("failed to list VMs: %w", err)
+			}
+
+			for _, vm := range page.Value {
+				seen++
+				if seen <= offset {
+					continue
+				}
+				if len(instances) == pageSize {
+					// Another VM follows the page
+					return instances, strconv.Itoa(offset + pageSize), nil
+				}
+				instance := Instance{
+					ID:         derefString(vm.ID),
+					Name:       derefString(vm.Name),
+					Region:     derefString(vm.Location),
+					Tags:       azureTagMap(vm.Tags),
+					LaunchedAt: azureVMCreated(vm),
+				}
+				if vm.Properties != nil {
+					instance.State = derefString(vm.Properties.ProvisioningState)
+					if vm.Properties.HardwareProfile != nil && vm.Properties.HardwareProfile.VMSize != nil {
+						instance.InstanceType = string(*vm.Properties.HardwareProfile.VMSize)
+					}
+				}
+				instances = append(instances, instance)
+			}
+		}
+	}
+	return instances, "", nil
+}
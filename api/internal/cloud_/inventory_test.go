@@ -0,0 +1,49 @@
+package cloud
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	models "finopsbridge/api/internal/models_"
+)
+
+func TestInstanceSizeLevel(t *testing.T) {
+	tests := map[string]int{
+		"t3.nano":         1,
+		"t3.micro":        1,
+		"e2-small":        1,
+		"t3.medium":       2,
+		"m5.large":        3,
+		"m5.2xlarge":      4,
+		"c5.metal":        4,
+		"Standard_D2s_v3": 0,
+		"":                0,
+	}
+	for instanceType, want := range tests {
+		if got := InstanceSizeLevel(instanceType); got != want {
+			t.Errorf("InstanceSizeLevel(%q) = %d, want %d", instanceType, got, want)
+		}
+	}
+}
+
+func TestListInstancesUnsupportedProvider(t *testing.T) {
+	_, err := ListInstances(context.Background(), models.CloudProvider{Type: "on-prem"}, nil)
+	if !errors.Is(err, ErrInventoryNotSupported) {
+		t.Errorf("got %v, want ErrInventoryNotSupported", err)
+	}
+}
+
+func TestListOptionsPageSize(t *testing.T) {
+	tests := map[int]int{
+		0:                       DefaultInstancePageSize,
+		-1:                      DefaultInstancePageSize,
+		25:                      25,
+		MaxInstancePageSize + 1: MaxInstancePageSize,
+	}
+	for maxResults, want := range tests {
+		if got := (ListOptions{MaxResults: maxResults}).pageSize(); got != want {
+			t.Errorf("pageSize with MaxResults %d = %d, want %d", maxResults, got, want)
+		}
+	}
+}
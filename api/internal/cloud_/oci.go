@@ -0,0 +1,226 @@
+package cloud
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	ocicommon "github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/identity"
+	"github.com/oracle/oci-go-sdk/v65/usageapi"
+)
+
+// maxOCIUsageCompartmentDepth is the deepest CompartmentDepth the Usage API accepts
+const maxOCIUsageCompartmentDepth = 7
+
+// ociCompartment is one node of a compartment tree rooted at the provider's compartment
+type ociCompartment struct {
+	ID       string
+	ParentID string
+	Name     string
+	Depth    int // 0 for the root
+}
+
+// OCICompartmentCost is the month-to-date cost of one compartment. Cost is usage billed
+// directly to the compartment (plus anything deeper than the traversal depth); TotalCost
+// also includes all descendants.
+type OCICompartmentCost struct {
+	ID        string  `json:"id"`
+	Name      string  `json:"name"`
+	Path      string  `json:"path"`
+	Depth     int     `json:"depth"`
+	Cost      float64 `json:"cost"`
+	TotalCost float64 `json:"totalCost"`
+	Currency  string  `json:"currency"`
+}
+
+// listOCICompartmentTree walks active compartments breadth-first from rootID, descending at
+// most maxDepth levels. The root itself is always the first element.
+func listOCICompartmentTree(ctx context.Context, client identity.IdentityClient, rootID string, maxDepth int) ([]ociCompartment, error) {
+	root, err := client.GetCompartment(ctx, identity.GetCompartmentRequest{CompartmentId: &rootID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get OCI compartment %s: %w", rootID, err)
+	}
+
+	rootName := rootID
+	if root.Name != nil {
+		rootName = *root.Name
+	}
+	tree := []ociCompartment{{ID: rootID, Name: rootName, Depth: 0}}
+
+	for i := 0; i < len(tree); i++ {
+		parent := tree[i]
+		if parent.Depth >= maxDepth {
+			continue
+		}
+
+		var page *string
+		for {
+			response, err := client.ListCompartments(ctx, identity.ListCompartmentsRequest{
+				CompartmentId:  &parent.ID,
+				LifecycleState: identity.CompartmentLifecycleStateActive,
+				Page:           page,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list OCI compartments under %s: %w", parent.ID, err)
+			}
+
+			for _, child := range response.Items {
+				if child.Id == nil || child.Name == nil {
+					continue
+				}
+				tree = append(tree, ociCompartment{
+					ID:       *child.Id,
+					ParentID: parent.ID,
+					Name:     *child.Name,
+					Depth:    parent.Depth + 1,
+				})
+			}
+
+			if response.OpcNextPage == nil {
+				break
+			}
+			page = response.OpcNextPage
+		}
+	}
+
+	return tree, nil
+}
+
+// fetchOCICompartmentCosts returns month-to-date cost per compartment in the tree rooted at rootID,
+// and whether the usage API returned any usage at all
+func fetchOCICompartmentCosts(ctx context.Context, configProvider ocicommon.ConfigurationProvider, tenancyOCID, rootID string, maxDepth int, defaultCurrency string, details usageapi.RequestSummarizedUsagesDetails) ([]OCICompartmentCost, bool, error) {
+	identityClient, err := identity.NewIdentityClientWithConfigurationProvider(configProvider)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create OCI identity client: %w", err)
+	}
+
+	tree, err := listOCICompartmentTree(ctx, identityClient, rootID, maxDepth)
+	if err != nil {
+		return nil, false, err
+	}
+
+	usageClient, err := usageapi.NewUsageapiClientWithConfigurationProvider(configProvider)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create OCI usage client: %w", err)
+	}
+
+	// Ask for the deepest grouping so usage can be attributed anywhere in the tree
+	details.TenantId = &tenancyOCID
+	details.GroupBy = []string{"compartmentId", "compartmentPath"}
+	details.CompartmentDepth = ocicommon.Float32(maxOCIUsageCompartmentDepth)
+
+	var items []usageapi.UsageSummary
+	request := usageapi.RequestSummarizedUsagesRequest{RequestSummarizedUsagesDetails: details}
+	for {
+		response, err := usageClient.RequestSummarizedUsages(ctx, request)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get OCI usage data: %w", err)
+		}
+		items = append(items, response.Items...)
+
+		if response.OpcNextPage == nil {
+			break
+		}
+		request.Page = response.OpcNextPage
+	}
+
+	return aggregateOCICompartmentCosts(tree, items, defaultCurrency), len(items) > 0, nil
+}
+
+// aggregateOCICompartmentCosts attributes usage to compartments in the tree and rolls each
+// compartment's cost up into its ancestors. Usage is matched by compartment ID; usage from
+// compartments deeper than the tree is matched by its path and attributed to the deepest
+// known ancestor. Usage outside the tree is ignored.
+func aggregateOCICompartmentCosts(tree []ociCompartment, items []usageapi.UsageSummary, defaultCurrency string) []OCICompartmentCost {
+	if len(tree) == 0 {
+		return nil
+	}
+
+	index := make(map[string]int, len(tree))
+	children := make(map[string]map[string]int)
+	costs := make([]OCICompartmentCost, len(tree))
+
+	for i, node := range tree {
+		index[node.ID] = i
+		path := node.Name
+		if node.ParentID != "" {
+			path = costs[index[node.ParentID]].Path + "/" + node.Name
+			if children[node.ParentID] == nil {
+				children[node.ParentID] = make(map[string]int)
+			}
+			children[node.ParentID][node.Name] = i
+		}
+		costs[i] = OCICompartmentCost{
+			ID:    node.ID,
+			Name:  node.Name,
+			Path:  path,
+			Depth: node.Depth,
+		}
+	}
+
+	currency := defaultCurrency
+
+	for _, item := range items {
+		if item.ComputedAmount == nil {
+			continue
+		}
+
+		target := -1
+		if item.CompartmentId != nil {
+			if i, ok := index[*item.CompartmentId]; ok {
+				target = i
+			}
+		}
+		if target < 0 && item.CompartmentPath != nil {
+			target = matchOCICompartmentPath(tree, children, *item.CompartmentPath)
+		}
+		if target < 0 {
+			continue
+		}
+
+		costs[target].Cost += float64(*item.ComputedAmount)
+		if item.Currency != nil && *item.Currency != "" {
+			currency = *item.Currency
+		}
+	}
+
+	// The tree is breadth-first, so walking it backwards visits children before parents
+	for i := len(tree) - 1; i >= 0; i-- {
+		costs[i].Currency = currency
+		costs[i].TotalCost += costs[i].Cost
+		if parentID := tree[i].ParentID; parentID != "" {
+			costs[index[parentID]].TotalCost += costs[i].TotalCost
+		}
+	}
+
+	return costs
+}
+
+// matchOCICompartmentPath walks a "/"-separated compartment name path down the tree from the
+// root and returns the index of the deepest compartment reached, or -1 if the path doesn't
+// pass through the root
+func matchOCICompartmentPath(tree []ociCompartment, children map[string]map[string]int, path string) int {
+	segments := strings.Split(path, "/")
+
+	start := -1
+	for i, segment := range segments {
+		if segment == tree[0].Name {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return -1
+	}
+
+	current := 0
+	for _, segment := range segments[start+1:] {
+		next, ok := children[tree[current].ID][segment]
+		if !ok {
+			break
+		}
+		current = next
+	}
+	return current
+}
@@ -0,0 +1,147 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	config "finopsbridge/api/internal/config_"
+	models "finopsbridge/api/internal/models_"
+
+	ocicommon "github.com/oracle/oci-go-sdk/v65/common"
+	ocicore "github.com/oracle/oci-go-sdk/v65/core"
+)
+
+// applyOCISchedule stops targeted OCI instances outside business hours and resumes them
+// during business hours, based on their Environment freeform tag
+func applyOCISchedule(ctx context.Context, provider models.CloudProvider, cfg *config.Config, schedule *Schedule, clock Clock, run *remediationRun) error {
+	if _, err := scheduleOCIInstances(ctx, provider, schedule, clock.Now(), ocicore.InstanceLifecycleStateRunning, run); err != nil {
+		return err
+	}
+	_, err := scheduleOCIInstances(ctx, provider, schedule, clock.Now(), ocicore.InstanceLifecycleStateStopped, run)
+	return err
+}
+
+// StartOCIInstances resumes stopped OCI instances the schedule targets, if it is business
+// hours for their environment, leaving those on opts.Protected alone. It returns how many were
+// started.
+func StartOCIInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, schedule *Schedule, clock Clock, opts RemediationOptions) (int, error) {
+	return scheduleOCIInstances(ctx, provider, schedule, clock.Now(), ocicore.InstanceLifecycleStateStopped, newRemediationRun(opts))
+}
+
+// ociScheduleAction returns the action a schedule takes on an instance in the given lifecycle
+// state: stop a running one outside business hours, start a stopped one during them. Instances
+// that are starting, stopping or in any other state are left alone.
+func ociScheduleAction(state ocicore.InstanceLifecycleStateEnum, businessHours bool) (ocicore.InstanceActionActionEnum, bool) {
+	switch {
+	case state == ocicore.InstanceLifecycleStateRunning && !businessHours:
+		return ocicore.InstanceActionActionStop, true
+	case state == ocicore.InstanceLifecycleStateStopped && businessHours:
+		return ocicore.InstanceActionActionStart, true
+	}
+	return "", false
+}
+
+// ociInstanceEnvironment returns the normalized environment of an OCI instance from its
+// freeform tags, whose keys are matched case-insensitively
+func ociInstanceEnvironment(tags map[string]string) string {
+	labels := make(map[string]string, len(tags))
+	for key, value := range tags {
+		labels[strings.ToLower(key)] = value
+	}
+	return instanceEnvironment(labels)
+}
+
+// scheduleOCIInstances applies the schedule to the provider's instances in one lifecycle state
+// and returns how many were stopped or started
+func scheduleOCIInstances(ctx context.Context, provider models.CloudProvider, schedule *Schedule, now time.Time, state ocicore.InstanceLifecycleStateEnum, run *remediationRun) (int, error) {
+	var credentials map[string]interface{}
+	if err := json.Unmarshal([]byte(provider.Credentials), &credentials); err != nil {
+		return 0, fmt.Errorf("failed to parse credentials: %w", err)
+	}
+
+	tenancyOCID, _ := credentials["tenancyOcid"].(string)
+	userOCID, _ := credentials["userOcid"].(string)
+	fingerprint, _ := credentials["fingerprint"].(string)
+	privateKey, _ := credentials["privateKey"].(string)
+	region, _ := credentials["region"].(string)
+	compartmentOCID, _ := credentials["compartmentOcid"].(string)
+
+	if tenancyOCID == "" || userOCID == "" || fingerprint == "" || privateKey == "" {
+		return 0, fmt.Errorf("missing OCI credentials")
+	}
+
+	if region == "" {
+		region = "us-ashburn-1"
+	}
+
+	if compartmentOCID == "" {
+		compartmentOCID = tenancyOCID
+	}
+
+	configProvider := ocicommon.NewRawConfigurationProvider(
+		tenancyOCID,
+		userOCID,
+		region,
+		fingerprint,
+		privateKey,
+		nil,
+	)
+
+	computeClient, err := ocicore.NewComputeClientWithConfigurationProvider(configProvider)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create OCI compute client: %w", err)
+	}
+
+	response, err := computeClient.ListInstances(ctx, ocicore.ListInstancesRequest{
+		CompartmentId:  &compartmentOCID,
+		LifecycleState: state,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list OCI instances: %w", err)
+	}
+
+	count := 0
+	for _, instance := range response.Items {
+		if instance.Id == nil {
+			continue
+		}
+
+		environment := ociInstanceEnvironment(instance.FreeformTags)
+		if !schedule.Targets(environment) {
+			continue
+		}
+
+		if val, ok := instance.FreeformTags["Essential"]; ok && val == "true" {
+			continue
+		}
+
+		if run.skipsProtected(RemediationCandidate{ResourceID: *instance.Id, Name: derefString(instance.DisplayName), Action: "schedule"}) {
+			continue
+		}
+
+		businessHours := schedule.ForEnvironment(environment).IsBusinessHours(now)
+		action, ok := ociScheduleAction(instance.LifecycleState, businessHours)
+		if !ok {
+			continue
+		}
+
+		if _, err := computeClient.InstanceAction(ctx, ocicore.InstanceActionRequest{
+			InstanceId: instance.Id,
+			Action:     action,
+		}); err != nil {
+			fmt.Printf("Error running scheduled %s on OCI instance %s: %v\n", strings.ToLower(string(action)), derefString(instance.DisplayName), err)
+			continue
+		}
+		if action == ocicore.InstanceActionActionStart {
+			fmt.Printf("Started OCI instance %s (%s) for business hours\n", derefString(instance.DisplayName), environment)
+		} else {
+			fmt.Printf("Stopped OCI instance %s (%s) outside business hours\n", derefString(instance.DisplayName), environment)
+		}
+		count++
+	}
+
+	return count, nil
+}
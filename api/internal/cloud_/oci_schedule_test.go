@@ -0,0 +1,47 @@
+package cloud
+
+import (
+	"testing"
+
+	ocicore "github.com/oracle/oci-go-sdk/v65/core"
+)
+
+func TestOCIScheduleAction(t *testing.T) {
+	tests := []struct {
+		state         ocicore.InstanceLifecycleStateEnum
+		businessHours bool
+		want          ocicore.InstanceActionActionEnum
+		ok            bool
+	}{
+		{ocicore.InstanceLifecycleStateRunning, false, ocicore.InstanceActionActionStop, true},
+		{ocicore.InstanceLifecycleStateRunning, true, "", false},
+		{ocicore.InstanceLifecycleStateStopped, true, ocicore.InstanceActionActionStart, true},
+		{ocicore.InstanceLifecycleStateStopped, false, "", false},
+		{ocicore.InstanceLifecycleStateStarting, false, "", false},
+		{ocicore.InstanceLifecycleStateStopping, true, "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ociScheduleAction(tt.state, tt.businessHours)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ociScheduleAction(%s, %v) = %q, %v; want %q, %v", tt.state, tt.businessHours, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestOCIInstanceEnvironment(t *testing.T) {
+	tests := []struct {
+		tags map[string]string
+		want string
+	}{
+		{map[string]string{"Environment": "dev"}, "development"},
+		{map[string]string{"ENV": "stg"}, "staging"},
+		{map[string]string{"Team": "data"}, "production"},
+		{nil, "production"},
+	}
+	for _, tt := range tests {
+		if got := ociInstanceEnvironment(tt.tags); got != tt.want {
+			t.Errorf("ociInstanceEnvironment(%v) = %q, want %q", tt.tags, got, tt.want)
+		}
+	}
+}
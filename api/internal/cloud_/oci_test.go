@@ -0,0 +1,85 @@
+package cloud
+
+import (
+	"testing"
+
+	ocicommon "github.com/oracle/oci-go-sdk/v65/common"
+	"github.com/oracle/oci-go-sdk/v65/usageapi"
+)
+
+// ociTestTree is a breadth-first compartment tree: root > (apps > web, data)
+var ociTestTree = []ociCompartment{
+	{ID: "root", Name: "tenancy", Depth: 0},
+	{ID: "apps", ParentID: "root", Name: "apps", Depth: 1},
+	{ID: "data", ParentID: "root", Name: "data", Depth: 1},
+	{ID: "web", ParentID: "apps", Name: "web", Depth: 2},
+}
+
+func ociCompartmentUsage(compartmentID, path string, amount float32) usageapi.UsageSummary {
+	item := usageapi.UsageSummary{ComputedAmount: ocicommon.Float32(amount), Currency: ocicommon.String("EUR")}
+	if compartmentID != "" {
+		item.CompartmentId = ocicommon.String(compartmentID)
+	}
+	if path != "" {
+		item.CompartmentPath = ocicommon.String(path)
+	}
+	return item
+}
+
+func TestAggregateOCICompartmentCosts(t *testing.T) {
+	costs := aggregateOCICompartmentCosts(ociTestTree, []usageapi.UsageSummary{
+		ociCompartmentUsage("root", "", 1),
+		ociCompartmentUsage("web", "", 10),
+		ociCompartmentUsage("data", "", 5),
+		// Deeper than the tree: attributed to its deepest known ancestor, web
+		ociCompartmentUsage("ocid1.compartment.deep", "tenancy/apps/web/canary", 2),
+		// Outside the tree
+		ociCompartmentUsage("ocid1.compartment.other", "other/apps", 100),
+		{CompartmentId: ocicommon.String("web")},
+	}, "USD")
+
+	want := map[string][2]float64{
+		"tenancy":          {1, 18},
+		"tenancy/apps":     {0, 12},
+		"tenancy/data":     {5, 5},
+		"tenancy/apps/web": {12, 12},
+	}
+	if len(costs) != len(want) {
+		t.Fatalf("got %d compartments, want %d", len(costs), len(want))
+	}
+	for _, cost := range costs {
+		if got := [2]float64{cost.Cost, cost.TotalCost}; got != want[cost.Path] {
+			t.Errorf("%s: cost, total = %v, want %v", cost.Path, got, want[cost.Path])
+		}
+		if cost.Currency != "EUR" {
+			t.Errorf("%s: currency %s, want the usage's EUR", cost.Path, cost.Currency)
+		}
+	}
+
+	if costs := aggregateOCICompartmentCosts(nil, nil, "USD"); costs != nil {
+		t.Errorf("got %v for an empty tree", costs)
+	}
+}
+
+func TestMatchOCICompartmentPath(t *testing.T) {
+	children := map[string]map[string]int{
+		"root": {"apps": 1, "data": 2},
+		"apps": {"web": 3},
+	}
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"tenancy", 0},
+		{"tenancy/apps/web", 3},
+		{"tenancy/data/warehouse/raw", 2},
+		// A path may include the tenancy's parent segments before the root
+		{"org/tenancy/apps", 1},
+		{"other/apps", -1},
+	}
+	for _, tt := range tests {
+		if got := matchOCICompartmentPath(ociTestTree, children, tt.path); got != tt.want {
+			t.Errorf("matchOCICompartmentPath(%q) = %d, want %d", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,416 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	config "finopsbridge/api/internal/config_"
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/pricing"
+
+	"google.golang.org/api/cloudbilling/v1"
+	compute "google.golang.org/api/compute/v1"
+	"google.golang.org/api/option"
+)
+
+// HoursPerMonth is the number of hours used to turn hourly prices into monthly estimates
+const HoursPerMonth = 730
+
+// ErrPricingUnavailable is returned when no on-demand price can be found for an instance type
+var ErrPricingUnavailable = errors.New("instance pricing unavailable")
+
+// pricingFetcher looks up the hourly on-demand price of an instance type in a region
+type pricingFetcher func(ctx context.Context, provider models.CloudProvider, cfg *config.Config, instanceType, region string) (float64, error)
+
+type pricingEntry struct {
+	price     float64
+	err       error
+	expiresAt time.Time
+}
+
+// How long instance prices are cached. Failed lookups are retried much sooner, so a
+// throttled or briefly unreachable pricing API doesn't disable price-based decisions for hours.
+const (
+	pricingTTL        = 12 * time.Hour
+	pricingFailureTTL = 10 * time.Minute
+)
+
+// pricingCache memoizes price lookups for ttl, and failed lookups for failureTTL
+type pricingCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	failureTTL time.Duration
+	fetch      pricingFetcher
+	now        func() time.Time
+	entries    map[string]pricingEntry
+}
+
+func newPricingCache(ttl, failureTTL time.Duration, fetch pricingFetcher) *pricingCache {
+	return &pricingCache{
+		ttl:        ttl,
+		failureTTL: failureTTL,
+		fetch:      fetch,
+		now:        time.Now,
+		entries:    make(map[string]pricingEntry),
+	}
+}
+
+func (pc *pricingCache) get(ctx context.Context, provider models.CloudProvider, cfg *config.Config, instanceType, region string) (float64, error) {
+	key := provider.Type + "|" + strings.ToLower(region) + "|" + strings.ToLower(instanceType)
+
+	pc.mu.Lock()
+	entry, exists := pc.entries[key]
+	pc.mu.Unlock()
+	if exists && pc.now().Before(entry.expiresAt) {
+		return entry.price, entry.err
+	}
+
+	price, err := pc.fetch(ctx, provider, cfg, instanceType, region)
+
+	// Don't cache transient failures such as a cancelled context
+	if err != nil && ctx.Err() != nil {
+		return 0, err
+	}
+
+	ttl := pc.ttl
+	if err != nil {
+		ttl = pc.failureTTL
+	}
+	pc.mu.Lock()
+	pc.entries[key] = pricingEntry{price: price, err: err, expiresAt: pc.now().Add(ttl)}
+	pc.mu.Unlock()
+
+	return price, err
+}
+
+var instancePricing = newPricingCache(pricingTTL, pricingFailureTTL, fetchInstancePricing)
+
+// GetInstancePricing returns the hourly on-demand USD price of an instance type in a region.
+// Prices are cached for 12 hours per provider type, region and instance type, and failed
+// lookups for 10 minutes.
+func GetInstancePricing(ctx context.Context, provider models.CloudProvider, cfg *config.Config, instanceType, region string) (float64, error) {
+	return instancePricing.get(ctx, provider, cfg, instanceType, region)
+}
+
+func fetchInstancePricing(ctx context.Context, provider models.CloudProvider, cfg *config.Config, instanceType, region string) (float64, error) {
+	p, ok := capability[InstancePricer](provider.Type)
+	if !ok {
+		return 0, ErrPricingUnavailable
+	}
+	return p.InstancePrice(ctx, provider, cfg, instanceType, region)
+}
+
+// exceedsPriceLimit reports whether an instance type costs more per hour than maxHourlyPrice.
+// ok is false when no price limit is set or the price could not be determined, in which
+// case callers fall back to size heuristics.
+func exceedsPriceLimit(ctx context.Context, provider models.CloudProvider, cfg *config.Config, instanceType, region string, maxHourlyPrice float64) (exceeds bool, price float64, ok bool) {
+	if maxHourlyPrice <= 0 {
+		return false, 0, false
+	}
+
+	price, err := GetInstancePricing(ctx, provider, cfg, instanceType, region)
+	if err != nil {
+		fmt.Printf("Warning: no price for %s in %s, using size heuristics: %v\n", instanceType, region, err)
+		return false, 0, false
+	}
+
+	return price > maxHourlyPrice, price, true
+}
+
+// monthlyInstanceCost returns an instance type's on-demand monthly cost, or 0 when it has no
+// price. It is what terminating an instance of the type saves.
+func monthlyInstanceCost(ctx context.Context, provider models.CloudProvider, cfg *config.Config, instanceType, region string) float64 {
+	price, err := GetInstancePricing(ctx, provider, cfg, instanceType, region)
+	if err != nil {
+		return 0
+	}
+	return price * HoursPerMonth
+}
+
+// fetchAWSInstancePricing queries the AWS Price List API for Linux shared-tenancy on-demand pricing
+func fetchAWSInstancePricing(ctx context.Context, cfg *config.Config, instanceType, region string) (float64, error) {
+	// The Price List API is only served from a few regions
+	sess, err := session.NewSession(&aws.Config{
+		Region: aws.String("us-east-1"),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if region == "" {
+		region = cfg.AWSRegion
+	}
+
+	filter := func(field, value string) *pricing.Filter {
+		return &pricing.Filter{
+			Type:  aws.String("TERM_MATCH"),
+			Field: aws.String(field),
+			Value: aws.String(value),
+		}
+	}
+
+	result, err := pricing.New(sess).GetProductsWithContext(ctx, &pricing.GetProductsInput{
+		ServiceCode: aws.String("AmazonEC2"),
+		Filters: []*pricing.Filter{
+			filter("instanceType", instanceType),
+			filter("regionCode", region),
+			filter("operatingSystem", "Linux"),
+			filter("tenancy", "Shared"),
+			filter("preInstalledSw", "NA"),
+			filter("capacitystatus", "Used"),
+		},
+		MaxResults: aws.Int64(10),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to query AWS pricing: %w", err)
+	}
+
+	for _, product := range result.PriceList {
+		if price, ok := awsOnDemandPrice(product); ok {
+			return price, nil
+		}
+	}
+
+	return 0, ErrPricingUnavailable
+}
+
+// awsOnDemandPrice extracts the USD hourly price from a Price List product document
+func awsOnDemandPrice(product aws.JSONValue) (float64, bool) {
+	terms, _ := product["terms"].(map[string]interface{})
+	onDemand, _ := terms["OnDemand"].(map[string]interface{})
+	for _, term := range onDemand {
+		termMap, _ := term.(map[string]interface{})
+		dimensions, _ := termMap["priceDimensions"].(map[string]interface{})
+		for _, dimension := range dimensions {
+			dimensionMap, _ := dimension.(map[string]interface{})
+			pricePerUnit, _ := dimensionMap["pricePerUnit"].(map[string]interface{})
+			usd, _ := pricePerUnit["USD"].(string)
+			if price, err := strconv.ParseFloat(usd, 64); err == nil && price > 0 {
+				return price, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// odataString quotes a value as an OData string literal, in which a quote is escaped by doubling it
+func odataString(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// fetchAzureInstancePricing queries the public Azure Retail Prices API for Linux pay-as-you-go pricing
+func fetchAzureInstancePricing(ctx context.Context, vmSize, region string) (float64, error) {
+	filter := fmt.Sprintf(
+		"serviceName eq 'Virtual Machines' and armSkuName eq %s and armRegionName eq %s and priceType eq 'Consumption'",
+		odataString(vmSize), odataString(strings.ToLower(region)),
+	)
+	endpoint := "https://prices.azure.com/api/retail/prices?$filter=" + url.QueryEscape(filter)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	client := &http.Client{
+		Timeout: 15 * time.Second,
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query Azure retail prices: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("azure retail prices returned status code: %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Items []struct {
+			RetailPrice float64 `json:"retailPrice"`
+			SkuName     string  `json:"skuName"`
+			ProductName string  `json:"productName"`
+		} `json:"Items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return 0, fmt.Errorf("failed to decode Azure retail prices: %w", err)
+	}
+
+	best := 0.0
+	for _, item := range body.Items {
+		if strings.Contains(item.SkuName, "Spot") || strings.Contains(item.SkuName, "Low Priority") ||
+			strings.Contains(item.ProductName, "Windows") || item.RetailPrice <= 0 {
+			continue
+		}
+		if best == 0 || item.RetailPrice < best {
+			best = item.RetailPrice
+		}
+	}
+
+	if best == 0 {
+		return 0, ErrPricingUnavailable
+	}
+	return best, nil
+}
+
+// gcpComputeServiceID is the Cloud Billing catalog ID of Compute Engine
+const gcpComputeServiceID = "services/6F81-5844-456A"
+
+// fetchGCPInstancePricing prices a machine type from its vCPU and memory SKUs in the Cloud Billing catalog
+func fetchGCPInstancePricing(ctx context.Context, provider models.CloudProvider, machineType, region string) (float64, error) {
+	var credentials map[string]interface{}
+	if err := json.Unmarshal([]byte(provider.Credentials), &credentials); err != nil {
+		return 0, fmt.Errorf("failed to parse credentials: %w", err)
+	}
+
+	serviceAccountJSON, _ := credentials["serviceAccountKey"].(string)
+	projectID := provider.ProjectID
+
+	if serviceAccountJSON == "" || projectID == "" {
+		return 0, fmt.Errorf("missing GCP credentials or projectId")
+	}
+
+	computeService, err := compute.NewService(ctx, option.WithCredentialsJSON([]byte(serviceAccountJSON)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create compute service: %w", err)
+	}
+
+	// Machine type shapes are the same in every zone of a region
+	machine, err := computeService.MachineTypes.Get(projectID, region+"-a", machineType).Context(ctx).Do()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get machine type %s: %w", machineType, err)
+	}
+
+	family := strings.ToUpper(strings.SplitN(machineType, "-", 2)[0])
+	rates, err := gcpCatalog.lookup(ctx, serviceAccountJSON, family, region)
+	if err != nil {
+		return 0, err
+	}
+
+	memoryGB := float64(machine.MemoryMb) / 1024
+	return float64(machine.GuestCpus)*rates.core + memoryGB*rates.ram, nil
+}
+
+// gcpRates are the on-demand per-vCPU and per-GB hourly prices of a machine family in a region
+type gcpRates struct {
+	core float64
+	ram  float64
+}
+
+// gcpRateCatalog holds the rates of every machine family in every region. Listing the Compute
+// Engine SKUs takes many pages, so one listing fills the whole catalog and serves all lookups
+// until it expires, instead of each new machine type scanning it again. The catalog holds list
+// prices, the same for every account, so all providers share it.
+type gcpRateCatalog struct {
+	mu        sync.Mutex
+	rates     map[string]gcpRates // By family|region
+	expiresAt time.Time
+}
+
+var gcpCatalog = &gcpRateCatalog{}
+
+// lookup returns a family's rates in a region, listing the SKUs first when the catalog is empty
+// or expired. The lock is held while listing so concurrent lookups share one listing.
+func (c *gcpRateCatalog) lookup(ctx context.Context, serviceAccountJSON, family, region string) (gcpRates, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.rates == nil || !time.Now().Before(c.expiresAt) {
+		rates, err := listGCPRates(ctx, serviceAccountJSON)
+		if err != nil {
+			return gcpRates{}, err
+		}
+		c.rates = rates
+		c.expiresAt = time.Now().Add(pricingTTL)
+	}
+
+	rates := c.rates[family+"|"+region]
+	if rates.core == 0 || rates.ram == 0 {
+		return gcpRates{}, ErrPricingUnavailable
+	}
+	return rates, nil
+}
+
+// listGCPRates lists the on-demand core and RAM SKUs of predefined machine types, keeping the
+// first price of each family in each region
+func listGCPRates(ctx context.Context, serviceAccountJSON string) (map[string]gcpRates, error) {
+	billingService, err := cloudbilling.NewService(ctx, option.WithCredentialsJSON([]byte(serviceAccountJSON)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create billing service: %w", err)
+	}
+
+	rates := make(map[string]gcpRates)
+	err = billingService.Services.Skus.List(gcpComputeServiceID).Pages(ctx, func(page *cloudbilling.ListSkusResponse) error {
+		for _, sku := range page.Skus {
+			if sku.Category == nil || sku.Category.UsageType != "OnDemand" {
+				continue
+			}
+			family, resource, ok := gcpSkuResource(sku.Description)
+			if !ok {
+				continue
+			}
+
+			price := gcpSkuUnitPrice(sku)
+			for _, region := range sku.ServiceRegions {
+				key := family + "|" + region
+				entry := rates[key]
+				switch {
+				case resource == "core" && entry.core == 0:
+					entry.core = price
+				case resource == "ram" && entry.ram == 0:
+					entry.ram = price
+				}
+				rates[key] = entry
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list compute SKUs: %w", err)
+	}
+	return rates, nil
+}
+
+// gcpSkuResource parses a predefined machine type SKU description such as "N2 Instance Core
+// running in Americas" or "N1 Predefined Instance Ram running in EMEA" into its family and
+// resource, core or ram. Custom, sole-tenant and other SKUs aren't matched.
+func gcpSkuResource(description string) (family, resource string, ok bool) {
+	for _, marker := range []string{" Predefined Instance ", " Instance "} {
+		i := strings.Index(description, marker)
+		if i <= 0 || strings.Contains(description[:i], " ") {
+			continue
+		}
+		rest := description[i+len(marker):]
+		switch {
+		case strings.HasPrefix(rest, "Core"):
+			return description[:i], "core", true
+		case strings.HasPrefix(rest, "Ram"):
+			return description[:i], "ram", true
+		}
+	}
+	return "", "", false
+}
+
+// gcpSkuUnitPrice returns the per-unit USD price of a SKU's last (non free-tier) rate
+func gcpSkuUnitPrice(sku *cloudbilling.Sku) float64 {
+	if len(sku.PricingInfo) == 0 || sku.PricingInfo[0].PricingExpression == nil {
+		return 0
+	}
+	rates := sku.PricingInfo[0].PricingExpression.TieredRates
+	if len(rates) == 0 || rates[len(rates)-1].UnitPrice == nil {
+		return 0
+	}
+	unitPrice := rates[len(rates)-1].UnitPrice
+	return float64(unitPrice.Units) + float64(unitPrice.Nanos)/1e9
+}
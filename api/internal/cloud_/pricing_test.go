@@ -0,0 +1,146 @@
+package cloud
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	config "finopsbridge/api/internal/config_"
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/aws/aws-sdk-go/aws"
+	cloudbilling "google.golang.org/api/cloudbilling/v1"
+)
+
+// countingFetcher returns a pricing fetcher that answers with price or err and counts its calls
+func countingFetcher(calls *int, price float64, err *error) pricingFetcher {
+	return func(ctx context.Context, provider models.CloudProvider, cfg *config.Config, instanceType, region string) (float64, error) {
+		*calls++
+		return price, *err
+	}
+}
+
+func TestPricingCacheTTLs(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	var calls int
+	var fetchErr error = ErrPricingUnavailable
+	pc := newPricingCache(12*time.Hour, 10*time.Minute, countingFetcher(&calls, 0.5, &fetchErr))
+	pc.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	provider := models.CloudProvider{Type: "aws"}
+
+	// A failed lookup is cached, but only for the failure TTL
+	if _, err := pc.get(ctx, provider, nil, "m5.large", "us-east-1"); !errors.Is(err, ErrPricingUnavailable) {
+		t.Fatalf("got %v, want ErrPricingUnavailable", err)
+	}
+	now = now.Add(5 * time.Minute)
+	pc.get(ctx, provider, nil, "m5.large", "us-east-1")
+	if calls != 1 {
+		t.Fatalf("a failure should be cached within its TTL, fetched %d times", calls)
+	}
+
+	fetchErr = nil
+	now = now.Add(6 * time.Minute)
+	price, err := pc.get(ctx, provider, nil, "M5.Large", "US-EAST-1")
+	if err != nil || price != 0.5 || calls != 2 {
+		t.Fatalf("after the failure TTL: price %v, err %v, %d fetches", price, err, calls)
+	}
+
+	// A price is cached for the full TTL, case-insensitively
+	now = now.Add(11 * time.Hour)
+	pc.get(ctx, provider, nil, "m5.large", "us-east-1")
+	if calls != 2 {
+		t.Fatalf("a price should be cached for 12 hours, fetched %d times", calls)
+	}
+	now = now.Add(2 * time.Hour)
+	pc.get(ctx, provider, nil, "m5.large", "us-east-1")
+	if calls != 3 {
+		t.Fatalf("an expired price should be fetched again, fetched %d times", calls)
+	}
+}
+
+func TestPricingCacheSkipsCancelledLookups(t *testing.T) {
+	var calls int
+	var fetchErr error = context.Canceled
+	pc := newPricingCache(time.Hour, time.Minute, countingFetcher(&calls, 0, &fetchErr))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	pc.get(ctx, models.CloudProvider{Type: "gcp"}, nil, "n2-standard-4", "us-central1")
+	pc.get(context.Background(), models.CloudProvider{Type: "gcp"}, nil, "n2-standard-4", "us-central1")
+	if calls != 2 {
+		t.Errorf("a lookup cut short by its context shouldn't be cached, fetched %d times", calls)
+	}
+}
+
+func TestAWSOnDemandPrice(t *testing.T) {
+	product := aws.JSONValue{
+		"terms": map[string]interface{}{
+			"OnDemand": map[string]interface{}{
+				"ABC.JRTCKXETXF": map[string]interface{}{
+					"priceDimensions": map[string]interface{}{
+						"ABC.JRTCKXETXF.6YS6EN2CT7": map[string]interface{}{
+							"pricePerUnit": map[string]interface{}{"USD": "0.0960000000"},
+						},
+					},
+				},
+			},
+		},
+	}
+	if price, ok := awsOnDemandPrice(product); !ok || price != 0.096 {
+		t.Errorf("got %v, %v; want 0.096", price, ok)
+	}
+
+	if _, ok := awsOnDemandPrice(aws.JSONValue{"terms": map[string]interface{}{}}); ok {
+		t.Error("a product without on-demand terms has no price")
+	}
+}
+
+func TestODataString(t *testing.T) {
+	if got := odataString("Standard_D2s_v3"); got != "'Standard_D2s_v3'" {
+		t.Errorf("got %s", got)
+	}
+	if got := odataString("x' or armRegionName eq 'eastus"); got != "'x'' or armRegionName eq ''eastus'" {
+		t.Errorf("quotes should be doubled, got %s", got)
+	}
+}
+
+func TestGCPSkuResource(t *testing.T) {
+	tests := []struct {
+		description      string
+		family, resource string
+		ok               bool
+	}{
+		{"N2 Instance Core running in Americas", "N2", "core", true},
+		{"N1 Predefined Instance Ram running in EMEA", "N1", "ram", true},
+		{"E2 Instance Ram running in Virginia", "E2", "ram", true},
+		{"N2 Custom Instance Core running in Americas", "", "", false},
+		{"Commitment v1: N2 Cpu in Americas for 1 Year", "", "", false},
+		{"Network Internet Egress from Americas", "", "", false},
+	}
+
+	for _, tt := range tests {
+		family, resource, ok := gcpSkuResource(tt.description)
+		if family != tt.family || resource != tt.resource || ok != tt.ok {
+			t.Errorf("gcpSkuResource(%q) = %q, %q, %v; want %q, %q, %v",
+				tt.description, family, resource, ok, tt.family, tt.resource, tt.ok)
+		}
+	}
+}
+
+func TestGCPSkuUnitPrice(t *testing.T) {
+	sku := &cloudbilling.Sku{PricingInfo: []*cloudbilling.PricingInfo{{
+		PricingExpression: &cloudbilling.PricingExpression{TieredRates: []*cloudbilling.TierRate{
+			{UnitPrice: &cloudbilling.Money{}},
+			{UnitPrice: &cloudbilling.Money{Units: 1, Nanos: 250000000}},
+		}},
+	}}}
+	if got := gcpSkuUnitPrice(sku); got != 1.25 {
+		t.Errorf("got %v, want the last tier's 1.25", got)
+	}
+	if got := gcpSkuUnitPrice(&cloudbilling.Sku{}); got != 0 {
+		t.Errorf("a SKU without pricing should be 0, got %v", got)
+	}
+}
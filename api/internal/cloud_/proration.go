@@ -0,0 +1,32 @@
+package cloud
+
+import (
+	"time"
+)
+
+// minProrationElapsed keeps projections made in the first hours of a month from being
+// dominated by a single early charge
+const minProrationElapsed = 24 * time.Hour
+
+// MonthElapsedFraction returns how much of now's calendar month (UTC) has elapsed, counting
+// at least one day
+func MonthElapsedFraction(now time.Time) float64 {
+	now = now.UTC()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	monthLength := monthStart.AddDate(0, 1, 0).Sub(monthStart)
+
+	elapsed := now.Sub(monthStart)
+	if elapsed < minProrationElapsed {
+		elapsed = minProrationElapsed
+	}
+	if elapsed > monthLength {
+		elapsed = monthLength
+	}
+	return float64(elapsed) / float64(monthLength)
+}
+
+// ProrateMonthToDate projects month-to-date spend to a full month at the rate spent so far,
+// so it can be compared against full prior months
+func ProrateMonthToDate(monthToDate float64, now time.Time) float64 {
+	return monthToDate / MonthElapsedFraction(now)
+}
@@ -0,0 +1,38 @@
+package cloud
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMonthElapsedFraction(t *testing.T) {
+	tests := []struct {
+		now  time.Time
+		want float64
+	}{
+		// Halfway through April's 30 days
+		{time.Date(2026, 4, 16, 0, 0, 0, 0, time.UTC), 0.5},
+		// The first hours of a month count as a whole day
+		{time.Date(2026, 4, 1, 3, 0, 0, 0, time.UTC), 1.0 / 30},
+		// February 2026 has 28 days
+		{time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC), 0.5},
+		// Local times are measured in UTC: this is 2026-04-16 00:00 UTC
+		{time.Date(2026, 4, 16, 9, 0, 0, 0, time.FixedZone("JST", 9*60*60)), 0.5},
+	}
+	for _, tt := range tests {
+		if got := MonthElapsedFraction(tt.now); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("MonthElapsedFraction(%v) = %v, want %v", tt.now, got, tt.want)
+		}
+	}
+}
+
+func TestProrateMonthToDate(t *testing.T) {
+	if got := ProrateMonthToDate(500, time.Date(2026, 4, 16, 0, 0, 0, 0, time.UTC)); math.Abs(got-1000) > 1e-9 {
+		t.Errorf("got %v, want 1000 halfway through the month", got)
+	}
+	// An early charge isn't projected as if it were one hour's spend
+	if got := ProrateMonthToDate(10, time.Date(2026, 4, 1, 1, 0, 0, 0, time.UTC)); math.Abs(got-300) > 1e-9 {
+		t.Errorf("got %v, want 300 from a one day minimum", got)
+	}
+}
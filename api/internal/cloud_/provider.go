@@ -0,0 +1,402 @@
+package cloud
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	config "finopsbridge/api/internal/config_"
+	models "finopsbridge/api/internal/models_"
+)
+
+// Provider is one cloud provider type's billing, inventory and remediation. FetchBilling,
+// ListInstancePage and the remediation functions look the implementation up by the connected
+// provider's type, so a new type only needs to register one. Optional capabilities, such as
+// cost breakdowns or schedules, are the interfaces below; a type that doesn't implement one
+// doesn't support it.
+type Provider interface {
+	// RequiredCredentials lists the credential fields the type can't connect without.
+	// Alternatives are separated by "|": any one of them satisfies the requirement.
+	RequiredCredentials() []string
+
+	// FetchBilling returns month-to-date billing data, uncached; callers go through the
+	// package's FetchBilling. Return ErrBillingNotSupported when the type has no billing.
+	FetchBilling(ctx context.Context, provider models.CloudProvider, cfg *config.Config) (map[string]interface{}, error)
+
+	// ListInstancePage lists one page of instances and the token of the next, empty after the
+	// last. Return ErrInventoryNotSupported when instances can't be listed.
+	ListInstancePage(ctx context.Context, provider models.CloudProvider, cfg *config.Config, opts ListOptions) ([]Instance, string, error)
+
+	// StopInstances stops running instances without an Essential tag
+	StopInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, opts RemediationOptions) (RemediationResult, error)
+
+	// TerminateInstances terminates instances above maxSizeLevel, or costing more than
+	// maxHourlyPrice per hour when set and the type has pricing
+	TerminateInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, maxSizeLevel int, maxHourlyPrice float64, opts RemediationOptions) (RemediationResult, error)
+
+	// StopIdleInstances stops instances idle for at least idleHoursThreshold. Types without
+	// utilization metrics select nothing.
+	StopIdleInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, idleHoursThreshold float64, opts RemediationOptions) (RemediationResult, error)
+}
+
+// CostBreakdownProvider groups month-to-date cost by a dimension such as "service"
+type CostBreakdownProvider interface {
+	FetchCostBreakdown(ctx context.Context, provider models.CloudProvider, cfg *config.Config, groupBy string) ([]CostBreakdownItem, error)
+}
+
+// TagBreakdownProvider is a CostBreakdownProvider that can also attribute cost to the values of
+// a tag or label key
+type TagBreakdownProvider interface {
+	CostBreakdownProvider
+
+	// TagGroupBy returns the groupBy that breaks cost down by the tag key
+	TagGroupBy(key string) string
+}
+
+// InstanceStopper stops one instance reported from outside the provider's own listing
+type InstanceStopper interface {
+	StopInstance(ctx context.Context, provider models.CloudProvider, cfg *config.Config, instanceID, zone, reason string, opts RemediationOptions) (RemediationResult, error)
+}
+
+// DefaultTagger adds missing tags to instances
+type DefaultTagger interface {
+	ApplyDefaultTags(ctx context.Context, provider models.CloudProvider, cfg *config.Config, tags map[string]string, opts RemediationOptions) (RemediationResult, error)
+}
+
+// Scheduler stops and starts instances on a business-hours schedule
+type Scheduler interface {
+	ApplySchedule(ctx context.Context, provider models.CloudProvider, cfg *config.Config, schedule *Schedule, clock Clock, opts RemediationOptions) error
+}
+
+// InstancePricer looks up the hourly on-demand USD price of an instance type, uncached;
+// callers go through GetInstancePricing
+type InstancePricer interface {
+	InstancePrice(ctx context.Context, provider models.CloudProvider, cfg *config.Config, instanceType, region string) (float64, error)
+}
+
+// AccountGroupProvider is implemented by types whose providers can be connected as a group,
+// one member per account sharing a credential template
+type AccountGroupProvider interface {
+	// SetGroupAccount sets the field identifying a member's account
+	SetGroupAccount(member *models.CloudProvider, accountID string)
+}
+
+var (
+	providersMu sync.RWMutex
+	providers   = map[string]Provider{
+		"aws":   awsProvider{},
+		"azure": azureProvider{},
+		"gcp":   gcpProvider{},
+		"oci":   ociProvider{},
+		"ibm":   ibmProvider{},
+	}
+)
+
+// RegisterProvider makes a provider type available to connect, replacing any implementation
+// already registered for it
+func RegisterProvider(providerType string, p Provider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers[providerType] = p
+}
+
+// ProviderFor returns the implementation registered for a provider type
+func ProviderFor(providerType string) (Provider, bool) {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	p, ok := providers[providerType]
+	return p, ok
+}
+
+// ProviderTypes returns the registered provider types, sorted
+func ProviderTypes() []string {
+	return providerTypesWith[Provider]()
+}
+
+// AccountGroupTypes returns the registered provider types that can be connected as groups,
+// sorted
+func AccountGroupTypes() []string {
+	return providerTypesWith[AccountGroupProvider]()
+}
+
+// AccountGroupProviderFor returns the implementation registered for a provider type, reporting
+// false when the type can't be connected as a group
+func AccountGroupProviderFor(providerType string) (AccountGroupProvider, bool) {
+	return capability[AccountGroupProvider](providerType)
+}
+
+// providerTypesWith returns the registered provider types whose implementation is a T, sorted
+func providerTypesWith[T any]() []string {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	types := make([]string, 0, len(providers))
+	for providerType, p := range providers {
+		if _, ok := p.(T); ok {
+			types = append(types, providerType)
+		}
+	}
+	sort.Strings(types)
+	return types
+}
+
+// capability returns the implementation registered for a provider type as a T, reporting
+// false when the type isn't registered or doesn't support T
+func capability[T any](providerType string) (T, bool) {
+	p, _ := ProviderFor(providerType)
+	c, ok := p.(T)
+	return c, ok
+}
+
+// remediate runs one remediation function of the built-in providers, collecting its outcome
+func remediate(opts RemediationOptions, fn func(run *remediationRun) error) (RemediationResult, error) {
+	run := newRemediationRun(opts)
+	err := fn(run)
+	return run.result, err
+}
+
+type awsProvider struct{}
+
+func (awsProvider) RequiredCredentials() []string {
+	return []string{"roleArn|useInstanceRole"}
+}
+
+func (awsProvider) FetchBilling(ctx context.Context, provider models.CloudProvider, cfg *config.Config) (map[string]interface{}, error) {
+	return FetchAWSBilling(ctx, provider, cfg)
+}
+
+func (awsProvider) ListInstancePage(ctx context.Context, provider models.CloudProvider, cfg *config.Config, opts ListOptions) ([]Instance, string, error) {
+	return listAWSInstancePage(ctx, provider, cfg, opts)
+}
+
+func (awsProvider) StopInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, opts RemediationOptions) (RemediationResult, error) {
+	return remediate(opts, func(run *remediationRun) error {
+		return stopAWSNonEssentialResources(ctx, provider, cfg, run)
+	})
+}
+
+func (awsProvider) TerminateInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, maxSizeLevel int, maxHourlyPrice float64, opts RemediationOptions) (RemediationResult, error) {
+	return remediate(opts, func(run *remediationRun) error {
+		return terminateAWSOversizedInstances(ctx, provider, cfg, maxSizeLevel, maxHourlyPrice, run)
+	})
+}
+
+func (awsProvider) StopIdleInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, idleHoursThreshold float64, opts RemediationOptions) (RemediationResult, error) {
+	return remediate(opts, func(run *remediationRun) error {
+		return stopAWSIdleResources(ctx, provider, cfg, idleHoursThreshold, run)
+	})
+}
+
+func (awsProvider) FetchCostBreakdown(ctx context.Context, provider models.CloudProvider, cfg *config.Config, groupBy string) ([]CostBreakdownItem, error) {
+	return FetchAWSCostBreakdown(ctx, provider, cfg, groupBy)
+}
+
+func (awsProvider) TagGroupBy(key string) string {
+	return "tag:" + key
+}
+
+func (awsProvider) StopInstance(ctx context.Context, provider models.CloudProvider, cfg *config.Config, instanceID, zone, reason string, opts RemediationOptions) (RemediationResult, error) {
+	return remediate(opts, func(run *remediationRun) error {
+		return stopAWSInstance(ctx, provider, cfg, instanceID, reason, run)
+	})
+}
+
+func (awsProvider) ApplyDefaultTags(ctx context.Context, provider models.CloudProvider, cfg *config.Config, tags map[string]string, opts RemediationOptions) (RemediationResult, error) {
+	return remediate(opts, func(run *remediationRun) error {
+		return tagAWSInstances(ctx, provider, cfg, tags, run)
+	})
+}
+
+func (awsProvider) InstancePrice(ctx context.Context, provider models.CloudProvider, cfg *config.Config, instanceType, region string) (float64, error) {
+	return fetchAWSInstancePricing(ctx, cfg, instanceType, region)
+}
+
+func (awsProvider) SetGroupAccount(member *models.CloudProvider, accountID string) {
+	member.AccountID = accountID
+}
+
+type azureProvider struct{}
+
+func (azureProvider) RequiredCredentials() []string {
+	return []string{"tenantId", "clientId", "clientSecret"}
+}
+
+func (azureProvider) FetchBilling(ctx context.Context, provider models.CloudProvider, cfg *config.Config) (map[string]interface{}, error) {
+	return FetchAzureBilling(ctx, provider, cfg)
+}
+
+func (azureProvider) ListInstancePage(ctx context.Context, provider models.CloudProvider, cfg *config.Config, opts ListOptions) ([]Instance, string, error) {
+	return listAzureInstancePage(ctx, provider, opts)
+}
+
+func (azureProvider) StopInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, opts RemediationOptions) (RemediationResult, error) {
+	return remediate(opts, func(run *remediationRun) error {
+		return stopAzureNonEssentialResources(ctx, provider, cfg, run)
+	})
+}
+
+func (azureProvider) TerminateInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, maxSizeLevel int, maxHourlyPrice float64, opts RemediationOptions) (RemediationResult, error) {
+	return remediate(opts, func(run *remediationRun) error {
+		return terminateAzureOversizedInstances(ctx, provider, cfg, maxSizeLevel, maxHourlyPrice, run)
+	})
+}
+
+func (azureProvider) StopIdleInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, idleHoursThreshold float64, opts RemediationOptions) (RemediationResult, error) {
+	return remediate(opts, func(run *remediationRun) error {
+		return stopAzureIdleResources(ctx, provider, cfg, idleHoursThreshold, run)
+	})
+}
+
+func (azureProvider) StopInstance(ctx context.Context, provider models.CloudProvider, cfg *config.Config, instanceID, zone, reason string, opts RemediationOptions) (RemediationResult, error) {
+	return remediate(opts, func(run *remediationRun) error {
+		return stopAzureInstance(ctx, provider, instanceID, reason, run)
+	})
+}
+
+func (azureProvider) ApplyDefaultTags(ctx context.Context, provider models.CloudProvider, cfg *config.Config, tags map[string]string, opts RemediationOptions) (RemediationResult, error) {
+	return remediate(opts, func(run *remediationRun) error {
+		return tagAzureVMs(ctx, provider, cfg, tags, run)
+	})
+}
+
+func (azureProvider) InstancePrice(ctx context.Context, provider models.CloudProvider, cfg *config.Config, instanceType, region string) (float64, error) {
+	return fetchAzureInstancePricing(ctx, instanceType, region)
+}
+
+func (azureProvider) SetGroupAccount(member *models.CloudProvider, accountID string) {
+	member.SubscriptionID = accountID
+}
+
+type gcpProvider struct{}
+
+func (gcpProvider) RequiredCredentials() []string {
+	return []string{"serviceAccountKey"}
+}
+
+func (gcpProvider) FetchBilling(ctx context.Context, provider models.CloudProvider, cfg *config.Config) (map[string]interface{}, error) {
+	return FetchGCPBilling(ctx, provider, cfg)
+}
+
+func (gcpProvider) ListInstancePage(ctx context.Context, provider models.CloudProvider, cfg *config.Config, opts ListOptions) ([]Instance, string, error) {
+	return listGCPInstancePage(ctx, provider, opts)
+}
+
+func (gcpProvider) StopInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, opts RemediationOptions) (RemediationResult, error) {
+	return remediate(opts, func(run *remediationRun) error {
+		return stopGCPNonEssentialResources(ctx, provider, cfg, run)
+	})
+}
+
+func (gcpProvider) TerminateInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, maxSizeLevel int, maxHourlyPrice float64, opts RemediationOptions) (RemediationResult, error) {
+	return remediate(opts, func(run *remediationRun) error {
+		return terminateGCPOversizedInstances(ctx, provider, cfg, maxSizeLevel, maxHourlyPrice, run)
+	})
+}
+
+func (gcpProvider) StopIdleInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, idleHoursThreshold float64, opts RemediationOptions) (RemediationResult, error) {
+	return remediate(opts, func(run *remediationRun) error {
+		return stopGCPIdleResources(ctx, provider, cfg, idleHoursThreshold, run)
+	})
+}
+
+func (gcpProvider) FetchCostBreakdown(ctx context.Context, provider models.CloudProvider, cfg *config.Config, groupBy string) ([]CostBreakdownItem, error) {
+	return FetchGCPCostBreakdown(ctx, provider, cfg, groupBy)
+}
+
+func (gcpProvider) TagGroupBy(key string) string {
+	return "label:" + key
+}
+
+func (gcpProvider) StopInstance(ctx context.Context, provider models.CloudProvider, cfg *config.Config, instanceID, zone, reason string, opts RemediationOptions) (RemediationResult, error) {
+	return remediate(opts, func(run *remediationRun) error {
+		return stopGCPInstance(ctx, provider, instanceID, zone, reason, run)
+	})
+}
+
+func (gcpProvider) ApplyDefaultTags(ctx context.Context, provider models.CloudProvider, cfg *config.Config, tags map[string]string, opts RemediationOptions) (RemediationResult, error) {
+	return remediate(opts, func(run *remediationRun) error {
+		return labelGCPInstances(ctx, provider, cfg, tags, run)
+	})
+}
+
+func (gcpProvider) ApplySchedule(ctx context.Context, provider models.CloudProvider, cfg *config.Config, schedule *Schedule, clock Clock, opts RemediationOptions) error {
+	return applyGCPSchedule(ctx, provider, cfg, schedule, clock, newRemediationRun(opts))
+}
+
+func (gcpProvider) InstancePrice(ctx context.Context, provider models.CloudProvider, cfg *config.Config, instanceType, region string) (float64, error) {
+	return fetchGCPInstancePricing(ctx, provider, instanceType, region)
+}
+
+func (gcpProvider) SetGroupAccount(member *models.CloudProvider, accountID string) {
+	member.ProjectID = accountID
+}
+
+// ociProvider has no utilization metrics and no instance pricing, so it terminates by size
+// alone
+type ociProvider struct{}
+
+func (ociProvider) RequiredCredentials() []string {
+	return []string{"tenancyOcid", "userOcid", "fingerprint", "privateKey"}
+}
+
+func (ociProvider) FetchBilling(ctx context.Context, provider models.CloudProvider, cfg *config.Config) (map[string]interface{}, error) {
+	return FetchOCIBilling(ctx, provider, cfg)
+}
+
+func (ociProvider) ListInstancePage(ctx context.Context, provider models.CloudProvider, cfg *config.Config, opts ListOptions) ([]Instance, string, error) {
+	return listOCIInstancePage(ctx, provider, opts)
+}
+
+func (ociProvider) StopInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, opts RemediationOptions) (RemediationResult, error) {
+	return remediate(opts, func(run *remediationRun) error {
+		return stopOCINonEssentialResources(ctx, provider, cfg, run)
+	})
+}
+
+func (ociProvider) TerminateInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, maxSizeLevel int, maxHourlyPrice float64, opts RemediationOptions) (RemediationResult, error) {
+	return remediate(opts, func(run *remediationRun) error {
+		return terminateOCIOversizedInstances(ctx, provider, cfg, maxSizeLevel, run)
+	})
+}
+
+func (ociProvider) StopIdleInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, idleHoursThreshold float64, opts RemediationOptions) (RemediationResult, error) {
+	return RemediationResult{}, nil
+}
+
+func (ociProvider) FetchCostBreakdown(ctx context.Context, provider models.CloudProvider, cfg *config.Config, groupBy string) ([]CostBreakdownItem, error) {
+	return FetchOCICostBreakdown(ctx, provider, cfg, groupBy)
+}
+
+func (ociProvider) ApplySchedule(ctx context.Context, provider models.CloudProvider, cfg *config.Config, schedule *Schedule, clock Clock, opts RemediationOptions) error {
+	return applyOCISchedule(ctx, provider, cfg, schedule, clock, newRemediationRun(opts))
+}
+
+// ibmProvider, like ociProvider, lacks utilization metrics and pricing
+type ibmProvider struct{}
+
+func (ibmProvider) RequiredCredentials() []string {
+	return []string{"apiKey", "accountId"}
+}
+
+func (ibmProvider) FetchBilling(ctx context.Context, provider models.CloudProvider, cfg *config.Config) (map[string]interface{}, error) {
+	return FetchIBMBilling(ctx, provider, cfg)
+}
+
+func (ibmProvider) ListInstancePage(ctx context.Context, provider models.CloudProvider, cfg *config.Config, opts ListOptions) ([]Instance, string, error) {
+	return listIBMInstancePage(ctx, provider, opts)
+}
+
+func (ibmProvider) StopInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, opts RemediationOptions) (RemediationResult, error) {
+	return remediate(opts, func(run *remediationRun) error {
+		return stopIBMNonEssentialResources(ctx, provider, cfg, run)
+	})
+}
+
+func (ibmProvider) TerminateInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, maxSizeLevel int, maxHourlyPrice float64, opts RemediationOptions) (RemediationResult, error) {
+	return remediate(opts, func(run *remediationRun) error {
+		return terminateIBMOversizedInstances(ctx, provider, cfg, maxSizeLevel, run)
+	})
+}
+
+func (ibmProvider) StopIdleInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, idleHoursThreshold float64, opts RemediationOptions) (RemediationResult, error) {
+	return RemediationResult{}, nil
+}
@@ -0,0 +1,212 @@
+package cloud
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	config "finopsbridge/api/internal/config_"
+	models "finopsbridge/api/internal/models_"
+)
+
+// fakeProvider implements Provider and, of the optional capabilities, only Scheduler
+type fakeProvider struct {
+	instances []Instance
+	scheduled int
+}
+
+func (p *fakeProvider) RequiredCredentials() []string {
+	return []string{"token", "region|endpoint"}
+}
+
+func (p *fakeProvider) FetchBilling(ctx context.Context, provider models.CloudProvider, cfg *config.Config) (map[string]interface{}, error) {
+	return map[string]interface{}{"monthlySpend": 42.0}, nil
+}
+
+func (p *fakeProvider) ListInstancePage(ctx context.Context, provider models.CloudProvider, cfg *config.Config, opts ListOptions) ([]Instance, string, error) {
+	return p.instances, "", nil
+}
+
+func (p *fakeProvider) StopInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, opts RemediationOptions) (RemediationResult, error) {
+	return RemediationResult{}, nil
+}
+
+func (p *fakeProvider) TerminateInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, maxSizeLevel int, maxHourlyPrice float64, opts RemediationOptions) (RemediationResult, error) {
+	return RemediationResult{}, nil
+}
+
+func (p *fakeProvider) StopIdleInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, idleHoursThreshold float64, opts RemediationOptions) (RemediationResult, error) {
+	return RemediationResult{}, nil
+}
+
+func (p *fakeProvider) ApplySchedule(ctx context.Context, provider models.CloudProvider, cfg *config.Config, schedule *Schedule, clock Clock, opts RemediationOptions) error {
+	p.scheduled++
+	return nil
+}
+
+// registerFake registers p as the "fake" provider type for the duration of the test
+func registerFake(t *testing.T, p Provider) {
+	t.Helper()
+	RegisterProvider("fake", p)
+	t.Cleanup(func() {
+		providersMu.Lock()
+		defer providersMu.Unlock()
+		delete(providers, "fake")
+	})
+}
+
+func TestRegisterProviderDispatches(t *testing.T) {
+	fake := &fakeProvider{instances: []Instance{{ID: "fake-1", InstanceType: "small"}}}
+	registerFake(t, fake)
+
+	ctx := context.Background()
+	cfg := &config.Config{}
+	provider := models.CloudProvider{ID: "p1", Type: "fake"}
+
+	if got, ok := ProviderFor("fake"); !ok || got != fake {
+		t.Fatalf("ProviderFor(fake) = %v, %v; want the registered provider", got, ok)
+	}
+
+	instances, err := ListInstances(ctx, provider, cfg)
+	if err != nil || !reflect.DeepEqual(instances, fake.instances) {
+		t.Errorf("ListInstances = %v, %v; want %v", instances, err, fake.instances)
+	}
+
+	if err := ApplySchedule(ctx, provider, cfg, &Schedule{}, SystemClock, RemediationOptions{}); err != nil {
+		t.Errorf("ApplySchedule: %v", err)
+	}
+	if fake.scheduled != 1 {
+		t.Errorf("ApplySchedule called the fake %d times, want 1", fake.scheduled)
+	}
+}
+
+func TestRegisteredProviderWithoutCapabilities(t *testing.T) {
+	registerFake(t, &fakeProvider{})
+
+	ctx := context.Background()
+	cfg := &config.Config{}
+	provider := models.CloudProvider{ID: "p1", Type: "fake"}
+
+	if _, err := FetchCostBreakdown(ctx, provider, cfg, "service"); !errors.Is(err, ErrBreakdownNotSupported) {
+		t.Errorf("FetchCostBreakdown error = %v, want ErrBreakdownNotSupported", err)
+	}
+	if _, ok := TagGroupBy("fake", "team"); ok {
+		t.Error("TagGroupBy reported a tag breakdown for a provider without one")
+	}
+	if _, err := StopInstance(ctx, provider, cfg, "fake-1", "", "idle", RemediationOptions{}); !errors.Is(err, ErrInstanceStopNotSupported) {
+		t.Errorf("StopInstance error = %v, want ErrInstanceStopNotSupported", err)
+	}
+	if _, err := ApplyDefaultTags(ctx, provider, cfg, map[string]string{"Owner": "me"}, RemediationOptions{}); err == nil {
+		t.Error("ApplyDefaultTags succeeded for a provider without tagging")
+	}
+	if _, ok := AccountGroupProviderFor("fake"); ok {
+		t.Error("AccountGroupProviderFor reported group support for a provider without it")
+	}
+}
+
+func TestProviderTypesIncludeRegistered(t *testing.T) {
+	registerFake(t, &fakeProvider{})
+
+	want := []string{"aws", "azure", "fake", "gcp", "ibm", "oci"}
+	if got := ProviderTypes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("ProviderTypes() = %v, want %v", got, want)
+	}
+	if got, want := AccountGroupTypes(), []string{"aws", "azure", "gcp"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AccountGroupTypes() = %v, want %v", got, want)
+	}
+}
+
+func TestMissingCredentialsUsesRegisteredRequirements(t *testing.T) {
+	registerFake(t, &fakeProvider{})
+
+	tests := []struct {
+		name        string
+		credentials map[string]interface{}
+		want        []string
+	}{
+		{"none", map[string]interface{}{}, []string{"token", "region or endpoint"}},
+		{"alternative", map[string]interface{}{"token": "t", "endpoint": "https://fake"}, nil},
+		{"blank", map[string]interface{}{"token": "  ", "region": "eu"}, []string{"token"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MissingCredentials("fake", tt.credentials); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MissingCredentials = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := MissingCredentials("unregistered", nil); got != nil {
+		t.Errorf("MissingCredentials(unregistered) = %v, want nil", got)
+	}
+}
+
+// pagingProvider serves total instances in pages of the requested size, with the next
+// instance's index as the page token
+type pagingProvider struct {
+	fakeProvider
+	total int
+	pages int
+}
+
+func (p *pagingProvider) ListInstancePage(ctx context.Context, provider models.CloudProvider, cfg *config.Config, opts ListOptions) ([]Instance, string, error) {
+	p.pages++
+	start := 0
+	if opts.PageToken != "" {
+		start, _ = strconv.Atoi(opts.PageToken)
+	}
+	end := min(start+opts.pageSize(), p.total)
+	var page []Instance
+	for i := start; i < end; i++ {
+		page = append(page, Instance{ID: strconv.Itoa(i)})
+	}
+	if end == p.total {
+		return page, "", nil
+	}
+	return page, strconv.Itoa(end), nil
+}
+
+func TestListInstancesFollowsPages(t *testing.T) {
+	tests := []struct {
+		name      string
+		total     int
+		wantCount int
+		wantPages int
+	}{
+		{"single page", 3, 3, 1},
+		{"several pages", 1200, maxInventoryInstances, 2},
+		{"exact pages", 2 * MaxInstancePageSize, 2 * MaxInstancePageSize, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &pagingProvider{total: tt.total}
+			registerFake(t, fake)
+
+			instances, err := ListInstances(context.Background(), models.CloudProvider{Type: "fake"}, &config.Config{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(instances) != tt.wantCount || fake.pages != tt.wantPages {
+				t.Errorf("ListInstances = %d instances in %d pages, want %d in %d", len(instances), fake.pages, tt.wantCount, tt.wantPages)
+			}
+			if len(instances) > 0 && instances[len(instances)-1].ID != strconv.Itoa(tt.wantCount-1) {
+				t.Errorf("last instance = %s, want %d", instances[len(instances)-1].ID, tt.wantCount-1)
+			}
+		})
+	}
+}
+
+func TestRemediateCollectsPartialResult(t *testing.T) {
+	result, err := remediate(RemediationOptions{}, func(run *remediationRun) error {
+		run.result.Succeeded = append(run.result.Succeeded, RemediationCandidate{ResourceID: "i-1"})
+		return errors.New("throttled")
+	})
+	if err == nil || err.Error() != "throttled" {
+		t.Errorf("err = %v, want the function's error", err)
+	}
+	if len(result.Succeeded) != 1 || result.Succeeded[0].ResourceID != "i-1" {
+		t.Errorf("result = %+v, want the actions taken before the error", result)
+	}
+}
@@ -0,0 +1,263 @@
+package cloud
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RemediationOptions controls how a remediation function acts on the resources it selects
+type RemediationOptions struct {
+	DryRun    bool             // Select and report candidates without acting on them
+	Selector  ResourceSelector // Only resources it matches are considered, before any Essential check
+	Protected []string         // Resource IDs or names never acted on, matched case-insensitively
+
+	// Resources limits the call to these resource IDs or names, matched case-insensitively,
+	// e.g. the instance a resource-scoped violation was raised on. Empty means any resource.
+	Resources []string
+
+	// MinResourceAge makes stop remediation skip resources launched more recently, e.g. still
+	// bootstrapping. Zero disables the check.
+	MinResourceAge time.Duration
+	Clock          Clock // Measures resource age; SystemClock when nil
+}
+
+// RemediationCandidate is a resource a remediation function selected, and why
+type RemediationCandidate struct {
+	ResourceID string `json:"resourceId"`
+	Name       string `json:"name,omitempty"`
+	Action     string `json:"action"` // stop, terminate or tag
+	Reason     string `json:"reason"`
+
+	// MonthlySavings is the on-demand monthly cost of a terminated instance, when its price is
+	// known
+	MonthlySavings float64 `json:"monthlySavings,omitempty"`
+}
+
+// ResourceError is a candidate whose action failed
+type ResourceError struct {
+	RemediationCandidate
+	Error string `json:"error"`
+}
+
+// RemediationResult reports the outcome of each action one remediation call took. In dry-run
+// mode every selected candidate is reported as succeeded.
+type RemediationResult struct {
+	Succeeded []RemediationCandidate `json:"succeeded"`
+	Failed    []ResourceError        `json:"failed"`
+	Protected []RemediationCandidate `json:"protected,omitempty"` // Selected but skipped as protected resources
+}
+
+// Candidates returns every resource the call selected, whether or not its action succeeded
+func (r RemediationResult) Candidates() []RemediationCandidate {
+	candidates := make([]RemediationCandidate, 0, len(r.Succeeded)+len(r.Failed))
+	candidates = append(candidates, r.Succeeded...)
+	for _, failure := range r.Failed {
+		candidates = append(candidates, failure.RemediationCandidate)
+	}
+	return candidates
+}
+
+// MonthlySavings sums the monthly savings of the actions that succeeded
+func (r RemediationResult) MonthlySavings() float64 {
+	total := 0.0
+	for _, candidate := range r.Succeeded {
+		total += candidate.MonthlySavings
+	}
+	return total
+}
+
+// Attempted returns how many actions were taken, successful or not
+func (r RemediationResult) Attempted() int {
+	return len(r.Succeeded) + len(r.Failed)
+}
+
+// Err summarizes failed actions as one error, or returns nil when none failed
+func (r RemediationResult) Err() error {
+	if len(r.Failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d of %d actions failed; first: %s %s: %s",
+		len(r.Failed), r.Attempted(), r.Failed[0].Action, r.Failed[0].ResourceID, r.Failed[0].Error)
+}
+
+// remediationRun collects the outcome of each candidate of one remediation call, performing
+// their actions or only recording them in dry-run mode
+type remediationRun struct {
+	opts      RemediationOptions
+	protected map[string]bool
+	resources map[string]bool
+	result    RemediationResult
+}
+
+func newRemediationRun(opts RemediationOptions) *remediationRun {
+	return &remediationRun{opts: opts, protected: lowerSet(opts.Protected), resources: lowerSet(opts.Resources)}
+}
+
+// lowerSet returns the lower-cased IDs as a set
+func lowerSet(ids []string) map[string]bool {
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		set[strings.ToLower(id)] = true
+	}
+	return set
+}
+
+// matchesID reports whether a candidate's ID or name is in a lower-cased set
+func matchesID(set map[string]bool, candidate RemediationCandidate) bool {
+	return set[strings.ToLower(candidate.ResourceID)] ||
+		(candidate.Name != "" && set[strings.ToLower(candidate.Name)])
+}
+
+// selects reports whether a listed resource is within the options' selector
+func (r *remediationRun) selects(name, region string, tags map[string]string) bool {
+	return r.opts.Selector.Matches(name, region, tags)
+}
+
+// tooNew reports whether a resource launched at the given time is younger than the options'
+// MinResourceAge, logging the skip. Resources whose launch time is unknown are never too new.
+func (r *remediationRun) tooNew(resourceID string, launched time.Time) bool {
+	if r.opts.MinResourceAge <= 0 || launched.IsZero() {
+		return false
+	}
+	clock := r.opts.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+	age := clock.Now().Sub(launched)
+	if age >= r.opts.MinResourceAge {
+		return false
+	}
+	fmt.Printf("Skipping %s: launched %s ago, under the minimum age of %s\n", resourceID, age.Round(time.Second), r.opts.MinResourceAge)
+	return true
+}
+
+// isProtected reports whether a candidate's ID or name is on the protected resource list
+func (r *remediationRun) isProtected(candidate RemediationCandidate) bool {
+	return matchesID(r.protected, candidate)
+}
+
+// targets reports whether a candidate is within the options' Resources, when they are set
+func (r *remediationRun) targets(candidate RemediationCandidate) bool {
+	return len(r.resources) == 0 || matchesID(r.resources, candidate)
+}
+
+// skipsProtected reports whether a candidate is a protected resource, recording and logging
+// the skip. act checks this itself; callers that act directly check it first.
+func (r *remediationRun) skipsProtected(candidate RemediationCandidate) bool {
+	if !r.isProtected(candidate) {
+		return false
+	}
+	fmt.Printf("Skipping protected resource %s (%s)\n", candidate.ResourceID, candidate.Action)
+	r.result.Protected = append(r.result.Protected, candidate)
+	return true
+}
+
+// act runs a candidate's action unless this is a dry run, and records the outcome. A dry run
+// counts as success, so callers apply the same per-call limit they would when acting.
+// Protected resources are never acted on, even in dry-run mode, and are recorded as such.
+// Candidates outside the options' Resources are passed over without being recorded.
+func (r *remediationRun) act(candidate RemediationCandidate, action func() error) error {
+	if !r.targets(candidate) {
+		return nil
+	}
+	if r.skipsProtected(candidate) {
+		return nil
+	}
+	if r.opts.DryRun {
+		fmt.Printf("Dry run: would %s %s (%s)\n", candidate.Action, candidate.ResourceID, candidate.Reason)
+		r.result.Succeeded = append(r.result.Succeeded, candidate)
+		return nil
+	}
+	if err := action(); err != nil {
+		r.result.Failed = append(r.result.Failed, ResourceError{RemediationCandidate: candidate, Error: err.Error()})
+		return err
+	}
+	r.result.Succeeded = append(r.result.Succeeded, candidate)
+	return nil
+}
+
+// acted returns how many candidates were acted on, or would have been in a dry run. Callers
+// compare it against their per-call limit, so protected resources don't use up the limit.
+func (r *remediationRun) acted() int {
+	return len(r.result.Succeeded)
+}
+
+// derefString returns the value of an optional SDK string, or "" when unset
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// derefTime returns the value of an optional SDK timestamp, or the zero time when unset
+func derefTime(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
+
+// parseGCPTimestamp parses a Compute Engine RFC 3339 timestamp such as an instance's
+// creationTimestamp, returning the zero time when it is missing or malformed
+func parseGCPTimestamp(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// MinResourceAge reads a policy's minResourceAge as a duration string such as "30m" or "2h",
+// or a number of minutes. Zero, the default, means resources of any age are acted on.
+func MinResourceAge(policyConfig map[string]interface{}) time.Duration {
+	switch age := policyConfig["minResourceAge"].(type) {
+	case string:
+		if d, err := time.ParseDuration(age); err == nil && d > 0 {
+			return d
+		}
+	case float64:
+		if age > 0 {
+			return time.Duration(age * float64(time.Minute))
+		}
+	}
+	return 0
+}
+
+// MaxSizeLevel reads a block_instance_type policy's maxSize as a size level, defaulting to 4 (large)
+func MaxSizeLevel(policyConfig map[string]interface{}) int {
+	maxSizeLevel := 4
+	if maxSize, ok := policyConfig["maxSize"].(string); ok {
+		switch maxSize {
+		case "small":
+			maxSizeLevel = 2
+		case "medium":
+			maxSizeLevel = 3
+		case "large":
+			maxSizeLevel = 4
+		case "xlarge":
+			maxSizeLevel = 5
+		}
+	}
+	return maxSizeLevel
+}
+
+// MaxHourlyPrice reads a block_instance_type policy's maxHourlyPrice, which targets instances by
+// on-demand price rather than name heuristics. Zero means unset.
+func MaxHourlyPrice(policyConfig map[string]interface{}) float64 {
+	if price, ok := policyConfig["maxHourlyPrice"].(float64); ok {
+		return price
+	}
+	return 0
+}
+
+// IdleHours reads an auto_stop_idle policy's idleHours, defaulting to 24
+func IdleHours(policyConfig map[string]interface{}) float64 {
+	if hours, ok := policyConfig["idleHours"].(float64); ok {
+		return hours
+	} else if hours, ok := policyConfig["idleHours"].(int); ok {
+		return float64(hours)
+	}
+	return 24
+}
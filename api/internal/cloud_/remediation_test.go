@@ -0,0 +1,114 @@
+package cloud
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRemediationResultMonthlySavings(t *testing.T) {
+	result := RemediationResult{
+		Succeeded: []RemediationCandidate{
+			{ResourceID: "i-1", MonthlySavings: 70.08},
+			{ResourceID: "i-2"},
+			{ResourceID: "i-3", MonthlySavings: 29.92},
+		},
+		Failed: []ResourceError{{RemediationCandidate: RemediationCandidate{ResourceID: "i-4", MonthlySavings: 500}, Error: "denied"}},
+	}
+
+	if got := result.MonthlySavings(); got != 100 {
+		t.Errorf("got %v, want only the succeeded actions' 100", got)
+	}
+	if got := (RemediationResult{}).MonthlySavings(); got != 0 {
+		t.Errorf("no actions should save nothing, got %v", got)
+	}
+}
+
+func TestRemediationResultCandidatesAndErr(t *testing.T) {
+	result := RemediationResult{
+		Succeeded: []RemediationCandidate{{ResourceID: "i-1", Action: "stop"}},
+		Failed: []ResourceError{
+			{RemediationCandidate: RemediationCandidate{ResourceID: "i-2", Action: "stop"}, Error: "denied"},
+			{RemediationCandidate: RemediationCandidate{ResourceID: "i-3", Action: "stop"}, Error: "throttled"},
+		},
+		Protected: []RemediationCandidate{{ResourceID: "i-4", Action: "stop"}},
+	}
+
+	candidates := result.Candidates()
+	if len(candidates) != 3 || candidates[0].ResourceID != "i-1" || candidates[2].ResourceID != "i-3" {
+		t.Errorf("candidates = %+v, want the succeeded then failed resources", candidates)
+	}
+	if got := result.Attempted(); got != 3 {
+		t.Errorf("attempted = %d, want 3; protected resources aren't attempted", got)
+	}
+	if err := result.Err(); err == nil || err.Error() != "2 of 3 actions failed; first: stop i-2: denied" {
+		t.Errorf("Err() = %v", err)
+	}
+	if err := (RemediationResult{Succeeded: result.Succeeded}).Err(); err != nil {
+		t.Errorf("no failures should be no error, got %v", err)
+	}
+}
+
+func TestRemediationRunDryRun(t *testing.T) {
+	run := newRemediationRun(RemediationOptions{DryRun: true})
+	err := run.act(RemediationCandidate{ResourceID: "i-1", Action: "stop"}, func() error {
+		t.Error("a dry run must not act")
+		return nil
+	})
+	if err != nil || run.acted() != 1 || len(run.result.Succeeded) != 1 {
+		t.Errorf("got %v, %+v; want the candidate reported as succeeded", err, run.result)
+	}
+}
+
+func TestRemediationRunRecordsOutcomes(t *testing.T) {
+	run := newRemediationRun(RemediationOptions{Protected: []string{"Payments-DB"}})
+	calls := 0
+	succeed := func() error { calls++; return nil }
+
+	run.act(RemediationCandidate{ResourceID: "i-1", Action: "stop"}, succeed)
+	if err := run.act(RemediationCandidate{ResourceID: "i-2", Action: "stop"}, func() error { return errors.New("denied") }); err == nil {
+		t.Error("a failed action should return its error")
+	}
+	run.act(RemediationCandidate{ResourceID: "i-3", Name: "payments-db", Action: "stop"}, succeed)
+
+	if calls != 1 {
+		t.Errorf("got %d actions, want the protected resource skipped", calls)
+	}
+	result := run.result
+	if len(result.Succeeded) != 1 || len(result.Failed) != 1 || len(result.Protected) != 1 {
+		t.Fatalf("got %+v", result)
+	}
+	if result.Failed[0].Error != "denied" || result.Err() == nil {
+		t.Errorf("failure = %+v, Err() = %v", result.Failed[0], result.Err())
+	}
+}
+
+func TestRemediationRunResources(t *testing.T) {
+	run := newRemediationRun(RemediationOptions{DryRun: true, Resources: []string{"I-2"}})
+	run.act(RemediationCandidate{ResourceID: "i-1"}, nil)
+	run.act(RemediationCandidate{ResourceID: "i-2"}, nil)
+
+	if len(run.result.Succeeded) != 1 || run.result.Succeeded[0].ResourceID != "i-2" {
+		t.Errorf("got %+v, want only the targeted resource", run.result)
+	}
+}
+
+func TestRemediationPolicyConfig(t *testing.T) {
+	if got := MaxSizeLevel(map[string]interface{}{"maxSize": "medium"}); got != 3 {
+		t.Errorf("MaxSizeLevel(medium) = %d, want 3", got)
+	}
+	if got := MaxSizeLevel(map[string]interface{}{"maxSize": "huge"}); got != 4 {
+		t.Errorf("MaxSizeLevel(huge) = %d, want the default 4", got)
+	}
+	if got := MaxHourlyPrice(map[string]interface{}{"maxHourlyPrice": 1.5}); got != 1.5 {
+		t.Errorf("MaxHourlyPrice = %v, want 1.5", got)
+	}
+	if got := MaxHourlyPrice(nil); got != 0 {
+		t.Errorf("MaxHourlyPrice(nil) = %v, want 0 for unset", got)
+	}
+	if got := IdleHours(map[string]interface{}{"idleHours": 6}); got != 6 {
+		t.Errorf("IdleHours(6) = %v", got)
+	}
+	if got := IdleHours(map[string]interface{}{}); got != 24 {
+		t.Errorf("IdleHours = %v, want the default 24", got)
+	}
+}
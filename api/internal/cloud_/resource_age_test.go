@@ -0,0 +1,70 @@
+package cloud
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	ocicommon "github.com/oracle/oci-go-sdk/v65/common"
+)
+
+func TestMinResourceAge(t *testing.T) {
+	tests := []struct {
+		config map[string]interface{}
+		want   time.Duration
+	}{
+		{map[string]interface{}{}, 0},
+		{map[string]interface{}{"minResourceAge": "2h"}, 2 * time.Hour},
+		{map[string]interface{}{"minResourceAge": "30m"}, 30 * time.Minute},
+		{map[string]interface{}{"minResourceAge": 45.0}, 45 * time.Minute},
+		{map[string]interface{}{"minResourceAge": "-1h"}, 0},
+		{map[string]interface{}{"minResourceAge": "two hours"}, 0},
+		{map[string]interface{}{"minResourceAge": -5.0}, 0},
+	}
+	for _, tt := range tests {
+		if got := MinResourceAge(tt.config); got != tt.want {
+			t.Errorf("MinResourceAge(%v) = %s, want %s", tt.config, got, tt.want)
+		}
+	}
+}
+
+func TestRemediationRunTooNew(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	run := newRemediationRun(RemediationOptions{MinResourceAge: time.Hour, Clock: NewFakeClock(now)})
+
+	if !run.tooNew("i-1", now.Add(-30*time.Minute)) {
+		t.Error("a resource launched 30 minutes ago should be too new")
+	}
+	if run.tooNew("i-1", now.Add(-time.Hour)) {
+		t.Error("a resource exactly the minimum age should be acted on")
+	}
+	if run.tooNew("i-1", time.Time{}) {
+		t.Error("a resource with an unknown launch time should be acted on")
+	}
+	if newRemediationRun(RemediationOptions{Clock: NewFakeClock(now)}).tooNew("i-1", now) {
+		t.Error("without a minimum age, no resource is too new")
+	}
+}
+
+func TestLaunchTimes(t *testing.T) {
+	created := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+
+	if got := parseGCPTimestamp("2026-03-01T00:00:00.000-08:00"); !got.Equal(created) {
+		t.Errorf("parseGCPTimestamp = %s, want %s", got, created)
+	}
+	if got := parseGCPTimestamp(""); !got.IsZero() {
+		t.Errorf("a missing GCP timestamp should be zero, got %s", got)
+	}
+	if !derefTime(nil).IsZero() || !derefTime(&created).Equal(created) {
+		t.Error("derefTime should return the time, or zero when unset")
+	}
+	if !azureVMCreated(&armcompute.VirtualMachine{}).IsZero() {
+		t.Error("an Azure VM without properties should have a zero creation time")
+	}
+	if got := azureVMCreated(&armcompute.VirtualMachine{Properties: &armcompute.VirtualMachineProperties{TimeCreated: &created}}); !got.Equal(created) {
+		t.Errorf("azureVMCreated = %s, want %s", got, created)
+	}
+	if !ociTimeCreated(nil).IsZero() || !ociTimeCreated(&ocicommon.SDKTime{Time: created}).Equal(created) {
+		t.Error("ociTimeCreated should return the time, or zero when unset")
+	}
+}
@@ -0,0 +1,281 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	config "finopsbridge/api/internal/config_"
+	models "finopsbridge/api/internal/models_"
+
+	compute "google.golang.org/api/compute/v1"
+	"google.golang.org/api/option"
+)
+
+// Schedule describes the business hours used by scheduled_start_stop policies
+type Schedule struct {
+	Location           *time.Location
+	WeekdayStart       int // minutes after midnight
+	WeekdayEnd         int
+	WeekendsOff        bool
+	WeekendStart       int
+	WeekendEnd         int
+	TargetEnvironments []string
+
+	// Environments overrides the business hours for specific environments, which are targeted
+	// even when not in TargetEnvironments. Fields an override doesn't set are inherited from
+	// the top-level schedule.
+	Environments map[string]*Schedule
+}
+
+// ParseSchedule builds a Schedule from a scheduled_start_stop policy config, e.g.
+// {"schedule": {"timezone": "America/New_York", "weekdays": "08:00-18:00", "weekends": "off"},
+//
+//	"targetEnvironments": ["development", "staging", "test"],
+//	"environments": {"staging": {"timezone": "Europe/London", "weekdays": "07:00-20:00"}}}
+func ParseSchedule(policyConfig map[string]interface{}) (*Schedule, error) {
+	schedule := &Schedule{
+		Location:           time.UTC,
+		WeekdayStart:       8 * 60,
+		WeekdayEnd:         18 * 60,
+		WeekendsOff:        true,
+		TargetEnvironments: []string{"development", "staging", "test"},
+	}
+
+	if raw, ok := policyConfig["schedule"].(map[string]interface{}); ok {
+		if err := schedule.applyHours(raw); err != nil {
+			return nil, err
+		}
+	}
+
+	if targets, ok := policyConfig["targetEnvironments"].([]interface{}); ok && len(targets) > 0 {
+		schedule.TargetEnvironments = nil
+		for _, t := range targets {
+			if env, ok := t.(string); ok {
+				schedule.TargetEnvironments = append(schedule.TargetEnvironments, normalizeEnvironment(env))
+			}
+		}
+	}
+
+	if environments, ok := policyConfig["environments"].(map[string]interface{}); ok {
+		schedule.Environments = make(map[string]*Schedule, len(environments))
+		for env, value := range environments {
+			raw, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("schedule for environment %q must be an object", env)
+			}
+			override := &Schedule{
+				Location:     schedule.Location,
+				WeekdayStart: schedule.WeekdayStart,
+				WeekdayEnd:   schedule.WeekdayEnd,
+				WeekendsOff:  schedule.WeekendsOff,
+				WeekendStart: schedule.WeekendStart,
+				WeekendEnd:   schedule.WeekendEnd,
+			}
+			if err := override.applyHours(raw); err != nil {
+				return nil, fmt.Errorf("environment %q: %w", env, err)
+			}
+			schedule.Environments[normalizeEnvironment(env)] = override
+		}
+	}
+
+	return schedule, nil
+}
+
+// applyHours sets the timezone, weekday and weekend hours present in a schedule object
+func (s *Schedule) applyHours(raw map[string]interface{}) error {
+	if tz, ok := raw["timezone"].(string); ok && tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return fmt.Errorf("invalid timezone %q: %w", tz, err)
+		}
+		s.Location = loc
+	}
+
+	if weekdays, ok := raw["weekdays"].(string); ok && weekdays != "" {
+		start, end, err := parseHoursRange(weekdays)
+		if err != nil {
+			return fmt.Errorf("invalid weekdays schedule: %w", err)
+		}
+		s.WeekdayStart, s.WeekdayEnd = start, end
+	}
+
+	if weekends, ok := raw["weekends"].(string); ok && weekends != "" {
+		if weekends == "off" {
+			s.WeekendsOff = true
+		} else {
+			start, end, err := parseHoursRange(weekends)
+			if err != nil {
+				return fmt.Errorf("invalid weekends schedule: %w", err)
+			}
+			s.WeekendsOff = false
+			s.WeekendStart, s.WeekendEnd = start, end
+		}
+	}
+
+	return nil
+}
+
+// parseHoursRange parses "HH:MM-HH:MM" into minutes after midnight
+func parseHoursRange(value string) (int, int, error) {
+	parts := strings.Split(value, "-")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("expected HH:MM-HH:MM, got %q", value)
+	}
+
+	var bounds [2]int
+	for i, part := range parts {
+		t, err := time.Parse("15:04", strings.TrimSpace(part))
+		if err != nil {
+			return 0, 0, fmt.Errorf("expected HH:MM-HH:MM, got %q", value)
+		}
+		bounds[i] = t.Hour()*60 + t.Minute()
+	}
+
+	if bounds[0] >= bounds[1] {
+		return 0, 0, fmt.Errorf("start must be before end in %q", value)
+	}
+
+	return bounds[0], bounds[1], nil
+}
+
+// IsBusinessHours reports whether t falls inside the schedule's working hours
+func (s *Schedule) IsBusinessHours(t time.Time) bool {
+	local := t.In(s.Location)
+	minutes := local.Hour()*60 + local.Minute()
+
+	switch local.Weekday() {
+	case time.Saturday, time.Sunday:
+		if s.WeekendsOff {
+			return false
+		}
+		return minutes >= s.WeekendStart && minutes < s.WeekendEnd
+	default:
+		return minutes >= s.WeekdayStart && minutes < s.WeekdayEnd
+	}
+}
+
+// ForEnvironment returns the schedule whose business hours apply to an environment: its
+// override when one is configured, otherwise the top-level schedule
+func (s *Schedule) ForEnvironment(environment string) *Schedule {
+	if override, ok := s.Environments[normalizeEnvironment(environment)]; ok {
+		return override
+	}
+	return s
+}
+
+// Targets reports whether resources in the given environment are managed by the schedule:
+// it is listed in TargetEnvironments or has its own hours. Production is never a stop target,
+// even if listed.
+func (s *Schedule) Targets(environment string) bool {
+	environment = normalizeEnvironment(environment)
+	if environment == "production" {
+		return false
+	}
+	if _, ok := s.Environments[environment]; ok {
+		return true
+	}
+	for _, target := range s.TargetEnvironments {
+		if target == environment {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeEnvironment maps common environment aliases to canonical names
+func normalizeEnvironment(environment string) string {
+	environment = strings.ToLower(strings.TrimSpace(environment))
+	switch environment {
+	case "prod", "prd":
+		return "production"
+	case "dev":
+		return "development"
+	case "stage", "stg":
+		return "staging"
+	case "tst", "qa":
+		return "test"
+	}
+	return environment
+}
+
+// ApplySchedule stops targeted non-production instances outside business hours and
+// starts them again during business hours, as of the clock's current time. Resources on
+// opts.Protected are never stopped or started.
+func ApplySchedule(ctx context.Context, provider models.CloudProvider, cfg *config.Config, schedule *Schedule, clock Clock, opts RemediationOptions) error {
+	p, ok := capability[Scheduler](provider.Type)
+	if !ok {
+		return nil
+	}
+	return p.ApplySchedule(ctx, provider, cfg, schedule, clock, opts)
+}
+
+// applyGCPSchedule applies a start/stop schedule to GCP instances based on their environment label
+func applyGCPSchedule(ctx context.Context, provider models.CloudProvider, cfg *config.Config, schedule *Schedule, clock Clock, run *remediationRun) error {
+	var credentials map[string]interface{}
+	if err := json.Unmarshal([]byte(provider.Credentials), &credentials); err != nil {
+		return fmt.Errorf("failed to parse credentials: %w", err)
+	}
+
+	serviceAccountJSON, _ := credentials["serviceAccountKey"].(string)
+	projectID := provider.ProjectID
+
+	if serviceAccountJSON == "" || projectID == "" {
+		return fmt.Errorf("missing GCP credentials or projectId")
+	}
+
+	computeService, err := compute.NewService(ctx, option.WithCredentialsJSON([]byte(serviceAccountJSON)))
+	if err != nil {
+		return fmt.Errorf("failed to create compute service: %w", err)
+	}
+
+	zonesResp, err := computeService.Zones.List(projectID).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to list zones: %w", err)
+	}
+
+	now := clock.Now()
+
+	for _, zone := range zonesResp.Items {
+		instancesResp, err := computeService.Instances.List(projectID, zone.Name).Context(ctx).Do()
+		if err != nil {
+			fmt.Printf("Warning: failed to list instances in zone %s: %v\n", zone.Name, err)
+			continue
+		}
+
+		for _, instance := range instancesResp.Items {
+			environment := instanceEnvironment(instance.Labels)
+			if !schedule.Targets(environment) {
+				continue
+			}
+
+			if val, ok := instance.Labels["essential"]; ok && val == "true" {
+				continue
+			}
+
+			if run.skipsProtected(RemediationCandidate{ResourceID: fmt.Sprintf("%d", instance.Id), Name: instance.Name, Action: "schedule"}) {
+				continue
+			}
+
+			businessHours := schedule.ForEnvironment(environment).IsBusinessHours(now)
+			switch {
+			case !businessHours && instance.Status == "RUNNING":
+				if _, err := computeService.Instances.Stop(projectID, zone.Name, instance.Name).Context(ctx).Do(); err != nil {
+					fmt.Printf("Error stopping scheduled GCP instance %s: %v\n", instance.Name, err)
+					continue
+				}
+				fmt.Printf("Stopped GCP instance %s (%s) outside business hours\n", instance.Name, environment)
+			case businessHours && instance.Status == "TERMINATED":
+				if _, err := computeService.Instances.Start(projectID, zone.Name, instance.Name).Context(ctx).Do(); err != nil {
+					fmt.Printf("Error starting scheduled GCP instance %s: %v\n", instance.Name, err)
+					continue
+				}
+				fmt.Printf("Started GCP instance %s (%s) for business hours\n", instance.Name, environment)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package cloud
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInstanceEnvironment(t *testing.T) {
+	tests := []struct {
+		labels map[string]string
+		want   string
+	}{
+		{map[string]string{"environment": "dev"}, "development"},
+		{map[string]string{"env": "STG"}, "staging"},
+		{map[string]string{"environment": "qa", "env": "prod"}, "test"},
+		{map[string]string{"team": "data"}, "production"},
+		{nil, "production"},
+	}
+
+	for _, tt := range tests {
+		if got := instanceEnvironment(tt.labels); got != tt.want {
+			t.Errorf("instanceEnvironment(%v) = %q, want %q", tt.labels, got, tt.want)
+		}
+	}
+}
+
+func TestParseScheduleDefaults(t *testing.T) {
+	schedule, err := ParseSchedule(map[string]interface{}{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Monday 2026-03-02
+	if !schedule.IsBusinessHours(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)) {
+		t.Error("09:00 on a weekday should be business hours")
+	}
+	if schedule.IsBusinessHours(time.Date(2026, 3, 2, 18, 0, 0, 0, time.UTC)) {
+		t.Error("18:00 is the end of business hours and should be outside them")
+	}
+	if schedule.IsBusinessHours(time.Date(2026, 3, 7, 12, 0, 0, 0, time.UTC)) {
+		t.Error("weekends should be off by default")
+	}
+	for _, env := range []string{"dev", "staging", "test"} {
+		if !schedule.Targets(env) {
+			t.Errorf("%s should be targeted by default", env)
+		}
+	}
+}
+
+func TestParseScheduleTimezoneAndWeekends(t *testing.T) {
+	schedule, err := ParseSchedule(map[string]interface{}{
+		"schedule": map[string]interface{}{
+			"timezone": "America/New_York",
+			"weekdays": "07:30-17:00",
+			"weekends": "10:00-14:00",
+		},
+		"targetEnvironments": []interface{}{"dev", "Production"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 12:00 UTC is 07:00 in New York in winter
+	if schedule.IsBusinessHours(time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC)) {
+		t.Error("07:00 local should be before business hours")
+	}
+	if !schedule.IsBusinessHours(time.Date(2026, 1, 5, 12, 30, 0, 0, time.UTC)) {
+		t.Error("07:30 local should be business hours")
+	}
+	if !schedule.IsBusinessHours(time.Date(2026, 1, 10, 16, 0, 0, 0, time.UTC)) {
+		t.Error("11:00 local on a Saturday should be weekend business hours")
+	}
+
+	if !schedule.Targets("development") {
+		t.Error("dev should be targeted as development")
+	}
+	if schedule.Targets("staging") {
+		t.Error("staging isn't listed and shouldn't be targeted")
+	}
+	if schedule.Targets("prod") {
+		t.Error("production must never be targeted, even if listed")
+	}
+}
+
+func TestParseScheduleRejectsInvalidHours(t *testing.T) {
+	for _, hours := range []string{"18:00-08:00", "8-18", "08:00", "08:00-25:00"} {
+		_, err := ParseSchedule(map[string]interface{}{
+			"schedule": map[string]interface{}{"weekdays": hours},
+		})
+		if err == nil {
+			t.Errorf("weekdays %q should be rejected", hours)
+		}
+	}
+
+	_, err := ParseSchedule(map[string]interface{}{
+		"schedule": map[string]interface{}{"timezone": "Mars/Olympus_Mons"},
+	})
+	if err == nil {
+		t.Error("an unknown timezone should be rejected")
+	}
+}
+
+func TestParseScheduleEnvironmentOverrides(t *testing.T) {
+	schedule, err := ParseSchedule(map[string]interface{}{
+		"schedule": map[string]interface{}{
+			"timezone": "America/New_York",
+			"weekdays": "08:00-18:00",
+		},
+		"targetEnvironments": []interface{}{"dev"},
+		"environments": map[string]interface{}{
+			"stg": map[string]interface{}{"weekdays": "07:00-20:00"},
+			"qa":  map[string]interface{}{"timezone": "Europe/London"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	staging := schedule.ForEnvironment("staging")
+	if staging == schedule {
+		t.Fatal("staging should use its own override")
+	}
+	if staging.WeekdayStart != 7*60 || staging.WeekdayEnd != 20*60 {
+		t.Errorf("staging hours = %d-%d, want 420-1200", staging.WeekdayStart, staging.WeekdayEnd)
+	}
+	if staging.Location.String() != "America/New_York" {
+		t.Errorf("staging should inherit the top-level timezone, got %s", staging.Location)
+	}
+
+	test := schedule.ForEnvironment("test")
+	if test.Location.String() != "Europe/London" {
+		t.Errorf("test timezone = %s, want Europe/London", test.Location)
+	}
+	if test.WeekdayStart != 8*60 || test.WeekdayEnd != 18*60 || !test.WeekendsOff {
+		t.Errorf("test should inherit the top-level hours, got %+v", test)
+	}
+
+	if schedule.ForEnvironment("development") != schedule {
+		t.Error("an environment without an override should use the top-level schedule")
+	}
+
+	// Overrides are targeted even when not listed in targetEnvironments
+	for _, env := range []string{"development", "staging", "test"} {
+		if !schedule.Targets(env) {
+			t.Errorf("%s should be targeted", env)
+		}
+	}
+}
+
+func TestParseScheduleRejectsInvalidOverride(t *testing.T) {
+	for _, environments := range []map[string]interface{}{
+		{"staging": "07:00-20:00"},
+		{"staging": map[string]interface{}{"weekends": "20:00-07:00"}},
+	} {
+		_, err := ParseSchedule(map[string]interface{}{"environments": environments})
+		if err == nil {
+			t.Errorf("environments %v should be rejected", environments)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package cloud
+
+import (
+	"strings"
+
+	"github.com/IBM/vpc-go-sdk/vpcv1"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// ResourceSelector scopes remediation to matching resources. Every set field must match; an
+// empty selector matches everything.
+type ResourceSelector struct {
+	Tags       map[string]string `json:"tags,omitempty"`       // Tags (labels on GCP) a resource must carry; an empty value matches any value
+	NamePrefix string            `json:"namePrefix,omitempty"` // Resource name prefix
+	Regions    []string          `json:"regions,omitempty"`    // Regions the resource may be in; zones match their region
+}
+
+// ParseResourceSelector reads a policy's "selector" config, e.g.
+// {"tags": {"team": "data", "env": "dev"}, "namePrefix": "dev-", "regions": ["us-east-1"]}
+func ParseResourceSelector(policyConfig map[string]interface{}) ResourceSelector {
+	raw, _ := policyConfig["selector"].(map[string]interface{})
+
+	var selector ResourceSelector
+	if tags, ok := raw["tags"].(map[string]interface{}); ok {
+		selector.Tags = make(map[string]string, len(tags))
+		for key, value := range tags {
+			selector.Tags[key], _ = value.(string)
+		}
+	}
+	selector.NamePrefix, _ = raw["namePrefix"].(string)
+	if regions, ok := raw["regions"].([]interface{}); ok {
+		for _, region := range regions {
+			if s, ok := region.(string); ok && s != "" {
+				selector.Regions = append(selector.Regions, s)
+			}
+		}
+	}
+	return selector
+}
+
+// Matches reports whether a resource with the given name, region (or zone) and tags is in scope
+func (s ResourceSelector) Matches(name, region string, tags map[string]string) bool {
+	for key, want := range s.Tags {
+		value, ok := tags[key]
+		if !ok || (want != "" && value != want) {
+			return false
+		}
+	}
+	if s.NamePrefix != "" && !strings.HasPrefix(name, s.NamePrefix) {
+		return false
+	}
+	if len(s.Regions) == 0 {
+		return true
+	}
+	for _, want := range s.Regions {
+		if strings.EqualFold(region, want) || strings.EqualFold(zoneRegion(region), want) {
+			return true
+		}
+	}
+	return false
+}
+
+// zoneRegion strips the zone suffix from a GCP or IBM zone name, e.g. "us-central1-a" to
+// "us-central1". Other names are returned unchanged.
+func zoneRegion(zone string) string {
+	if i := strings.LastIndex(zone, "-"); i > 0 && len(zone)-i <= 2 {
+		return zone[:i]
+	}
+	return zone
+}
+
+// awsTagMap converts EC2 tags for selector matching
+func awsTagMap(tags []*ec2.Tag) map[string]string {
+	result := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		result[derefString(tag.Key)] = derefString(tag.Value)
+	}
+	return result
+}
+
+// awsInstanceName returns an EC2 instance's Name tag
+func awsInstanceName(instance *ec2.Instance) string {
+	for _, tag := range instance.Tags {
+		if derefString(tag.Key) == "Name" {
+			return derefString(tag.Value)
+		}
+	}
+	return ""
+}
+
+// ibmInstanceZone returns the zone an IBM Cloud VPC instance runs in
+func ibmInstanceZone(instance vpcv1.Instance) string {
+	if instance.Zone == nil {
+		return ""
+	}
+	return derefString(instance.Zone.Name)
+}
+
+// azureTagMap converts Azure resource tags for selector matching
+func azureTagMap(tags map[string]*string) map[string]string {
+	result := make(map[string]string, len(tags))
+	for key, value := range tags {
+		result[key] = derefString(value)
+	}
+	return result
+}
@@ -0,0 +1,93 @@
+package cloud
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+func TestParseResourceSelector(t *testing.T) {
+	got := ParseResourceSelector(map[string]interface{}{
+		"selector": map[string]interface{}{
+			"tags":       map[string]interface{}{"team": "data", "env": ""},
+			"namePrefix": "dev-",
+			"regions":    []interface{}{"us-east-1", "", 3.0},
+		},
+	})
+	want := ResourceSelector{
+		Tags:       map[string]string{"team": "data", "env": ""},
+		NamePrefix: "dev-",
+		Regions:    []string{"us-east-1"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	if empty := ParseResourceSelector(map[string]interface{}{}); !reflect.DeepEqual(empty, ResourceSelector{}) {
+		t.Errorf("no selector config should give an empty selector, got %+v", empty)
+	}
+}
+
+func TestResourceSelectorMatches(t *testing.T) {
+	selector := ResourceSelector{
+		Tags:       map[string]string{"team": "data", "env": ""},
+		NamePrefix: "dev-",
+		Regions:    []string{"us-central1", "US-EAST-1"},
+	}
+	tags := map[string]string{"team": "data", "env": "staging"}
+
+	tests := []struct {
+		name, resourceName, region string
+		tags                       map[string]string
+		want                       bool
+	}{
+		{"all match", "dev-etl", "us-east-1", tags, true},
+		{"zone in a listed region", "dev-etl", "us-central1-a", tags, true},
+		{"other region", "dev-etl", "eu-west-1", tags, false},
+		{"wrong prefix", "prod-etl", "us-east-1", tags, false},
+		{"wrong tag value", "dev-etl", "us-east-1", map[string]string{"team": "web", "env": "dev"}, false},
+		{"missing tag with any value", "dev-etl", "us-east-1", map[string]string{"team": "data"}, false},
+	}
+	for _, tt := range tests {
+		if got := selector.Matches(tt.resourceName, tt.region, tt.tags); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if !(ResourceSelector{}).Matches("anything", "", nil) {
+		t.Error("an empty selector should match everything")
+	}
+}
+
+func TestZoneRegion(t *testing.T) {
+	tests := map[string]string{
+		"us-central1-a": "us-central1",
+		"eu-de-1":       "eu-de",
+		"us-central1":   "us-central1",
+		"eastus":        "eastus",
+	}
+	for zone, want := range tests {
+		if got := zoneRegion(zone); got != want {
+			t.Errorf("zoneRegion(%q) = %q, want %q", zone, got, want)
+		}
+	}
+}
+
+func TestAWSInstanceTags(t *testing.T) {
+	instance := &ec2.Instance{Tags: []*ec2.Tag{
+		{Key: aws.String("team"), Value: aws.String("data")},
+		{Key: aws.String("Name"), Value: aws.String("dev-etl")},
+	}}
+
+	if got := awsInstanceName(instance); got != "dev-etl" {
+		t.Errorf("name = %q, want dev-etl", got)
+	}
+	if got := awsInstanceName(&ec2.Instance{}); got != "" {
+		t.Errorf("an instance without a Name tag should have no name, got %q", got)
+	}
+	if got := awsTagMap(instance.Tags); !reflect.DeepEqual(got, map[string]string{"team": "data", "Name": "dev-etl"}) {
+		t.Errorf("tags = %v", got)
+	}
+}
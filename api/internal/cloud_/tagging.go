@@ -0,0 +1,364 @@
+package cloud
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	config "finopsbridge/api/internal/config_"
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v5"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	compute "google.golang.org/api/compute/v1"
+	"google.golang.org/api/option"
+)
+
+// maxTagsPerRun caps how many resources one tagging remediation changes
+const maxTagsPerRun = 50
+
+// AutoTag reports whether a require_tags policy opted in to tagging resources that miss its
+// required tags, rather than only reporting them
+func AutoTag(policyConfig map[string]interface{}) bool {
+	autoTag, _ := policyConfig["autoTag"].(bool)
+	return autoTag
+}
+
+// AutoTagDryRun reports whether a require_tags policy's auto-tagging only reports the tags it
+// would apply
+func AutoTagDryRun(policyConfig map[string]interface{}) bool {
+	dryRun, _ := policyConfig["autoTagDryRun"].(bool)
+	return dryRun
+}
+
+// DefaultTags returns the values auto-tagging applies for a require_tags policy's missing
+// required tags. Owner defaults to the provider's name and Environment to "unassigned"; the
+// policy's defaultTags override either and can supply other required tags. Required tags
+// without a value are left for someone to set by hand.
+func DefaultTags(provider models.CloudProvider, policyConfig map[string]interface{}) map[string]string {
+	defaults := map[string]string{
+		"Owner":       provider.Name,
+		"Environment": "unassigned",
+	}
+	if configured, ok := policyConfig["defaultTags"].(map[string]interface{}); ok {
+		for key, value := range configured {
+			if s, ok := value.(string); ok && s != "" {
+				defaults[key] = s
+			}
+		}
+	}
+
+	tags := make(map[string]string)
+	required, _ := policyConfig["requiredTags"].([]interface{})
+	for _, item := range required {
+		key, _ := item.(string)
+		if value := defaults[key]; key != "" && value != "" {
+			tags[key] = value
+		}
+	}
+	return tags
+}
+
+// ApplyDefaultTags adds the given tags to instances missing any of them, without changing tags
+// that are already set. It returns the outcome for each resource it selected; with opts.DryRun
+// nothing is tagged.
+func ApplyDefaultTags(ctx context.Context, provider models.CloudProvider, cfg *config.Config, tags map[string]string, opts RemediationOptions) (RemediationResult, error) {
+	if len(tags) == 0 {
+		return RemediationResult{}, nil
+	}
+
+	p, ok := capability[DefaultTagger](provider.Type)
+	if !ok {
+		return RemediationResult{}, fmt.Errorf("auto-tagging is not supported for %s providers", provider.Type)
+	}
+	return p.ApplyDefaultTags(ctx, provider, cfg, tags, opts)
+}
+
+// missingTags returns the tags not present in existing, matched by key as normalized by keyFunc
+func missingTags(tags map[string]string, existing map[string]bool, keyFunc func(string) string) map[string]string {
+	missing := make(map[string]string)
+	for key, value := range tags {
+		if !existing[keyFunc(key)] {
+			missing[key] = value
+		}
+	}
+	return missing
+}
+
+// tagReason describes the tags a candidate is missing, in a stable order
+func tagReason(missing map[string]string) string {
+	pairs := make([]string, 0, len(missing))
+	for key, value := range missing {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return "missing required tags; applying " + strings.Join(pairs, ", ")
+}
+
+// exactKey is the missingTags key function for providers whose tag keys are case-sensitive
+func exactKey(s string) string { return s }
+
+func tagAWSInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, tags map[string]string, run *remediationRun) error {
+	sess, err := newAWSSession(provider, cfg)
+	if err != nil {
+		return err
+	}
+
+	ec2Svc := ec2.New(sess)
+
+	count := 0
+	err = ec2Svc.DescribeInstancesPagesWithContext(ctx, &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("instance-state-name"),
+				Values: []*string{aws.String("pending"), aws.String("running"), aws.String("stopping"), aws.String("stopped")},
+			},
+		},
+	}, func(page *ec2.DescribeInstancesOutput, lastPage bool) bool {
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				if count >= maxTagsPerRun {
+					return false
+				}
+				if !run.selects(awsInstanceName(instance), cfg.AWSRegion, awsTagMap(instance.Tags)) {
+					continue
+				}
+
+				existing := make(map[string]bool)
+				for _, tag := range instance.Tags {
+					existing[derefString(tag.Key)] = true
+				}
+				missing := missingTags(tags, existing, exactKey)
+				if len(missing) == 0 {
+					continue
+				}
+
+				awsTags := make([]*ec2.Tag, 0, len(missing))
+				for key, value := range missing {
+					awsTags = append(awsTags, &ec2.Tag{Key: aws.String(key), Value: aws.String(value)})
+				}
+
+				err := run.act(RemediationCandidate{
+					ResourceID: derefString(instance.InstanceId),
+					Action:     "tag",
+					Reason:     tagReason(missing),
+				}, func() error {
+					_, err := ec2Svc.CreateTagsWithContext(ctx, &ec2.CreateTagsInput{
+						Resources: []*string{instance.InstanceId},
+						Tags:      awsTags,
+					})
+					return err
+				})
+				if err != nil {
+					fmt.Printf("Error tagging instance %s: %v\n", derefString(instance.InstanceId), err)
+					continue
+				}
+				count = run.acted()
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list instances: %w", err)
+	}
+
+	return nil
+}
+
+func tagAzureVMs(ctx context.Context, provider models.CloudProvider, cfg *config.Config, tags map[string]string, run *remediationRun) error {
+	var credentials map[string]interface{}
+	if err := json.Unmarshal([]byte(provider.Credentials), &credentials); err != nil {
+		return fmt.Errorf("failed to parse credentials: %w", err)
+	}
+
+	tenantID, _ := credentials["tenantId"].(string)
+	clientID, _ := credentials["clientId"].(string)
+	clientSecret, _ := credentials["clientSecret"].(string)
+	subscriptionIDs := AzureSubscriptionIDs(provider)
+	regions := AzureRegions(provider)
+
+	if tenantID == "" || clientID == "" || clientSecret == "" || len(subscriptionIDs) == 0 {
+		return fmt.Errorf("missing Azure credentials or subscriptionId")
+	}
+
+	cred, err := azidentity.NewClientSecretCredential(tenantID, clientID, clientSecret, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create Azure credential: %w", err)
+	}
+
+	// maxTagsPerRun spans all subscriptions
+	count := 0
+	for _, subscriptionID := range subscriptionIDs {
+		vmClient, err := armcompute.NewVirtualMachinesClient(subscriptionID, cred, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create VM client: %w", err)
+		}
+
+		pager := newAzureVMPager(vmClient, regions)
+		for pager.More() && count < maxTagsPerRun {
+			page, err := pager.NextPage(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to list VMs: %w", err)
+			}
+
+			for _, vm := range page.Value {
+				if count >= maxTagsPerRun {
+					break
+				}
+				if vm.ID == nil || vm.Name == nil {
+					continue
+				}
+
+				if !run.selects(derefString(vm.Name), derefString(vm.Location), azureTagMap(vm.Tags)) {
+					continue
+				}
+
+				// Azure tag names are case-insensitive
+				existing := make(map[string]bool)
+				for key := range vm.Tags {
+					existing[strings.ToLower(key)] = true
+				}
+				missing := missingTags(tags, existing, strings.ToLower)
+				if len(missing) == 0 {
+					continue
+				}
+
+				resourceGroup := extractResourceGroupFromID(*vm.ID)
+				if resourceGroup == "" {
+					fmt.Printf("Could not extract resource group from VM ID: %s\n", *vm.ID)
+					continue
+				}
+
+				// An update replaces the whole tag set, so send the existing tags too
+				merged := make(map[string]*string, len(vm.Tags)+len(missing))
+				for key, value := range vm.Tags {
+					merged[key] = value
+				}
+				for key, value := range missing {
+					merged[key] = &value
+				}
+
+				err := run.act(RemediationCandidate{
+					ResourceID: *vm.ID,
+					Name:       *vm.Name,
+					Action:     "tag",
+					Reason:     tagReason(missing),
+				}, func() error {
+					poller, err := vmClient.BeginUpdate(ctx, resourceGroup, *vm.Name, armcompute.VirtualMachineUpdate{Tags: merged}, nil)
+					if err != nil {
+						return err
+					}
+					_, err = poller.PollUntilDone(ctx, nil)
+					return err
+				})
+				if err != nil {
+					fmt.Printf("Error tagging Azure VM %s: %v\n", *vm.Name, err)
+					continue
+				}
+				count = run.acted()
+			}
+		}
+	}
+
+	return nil
+}
+
+// gcpLabelInvalidChars matches characters not allowed in GCP label keys and values
+var gcpLabelInvalidChars = regexp.MustCompile(`[^a-z0-9_-]`)
+
+// gcpLabel converts a tag key or value to a valid GCP label: lowercase letters, digits,
+// underscores and dashes, at most 63 characters
+func gcpLabel(s string) string {
+	label := gcpLabelInvalidChars.ReplaceAllString(strings.ToLower(s), "_")
+	if len(label) > 63 {
+		label = label[:63]
+	}
+	return label
+}
+
+func labelGCPInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, tags map[string]string, run *remediationRun) error {
+	var credentials map[string]interface{}
+	if err := json.Unmarshal([]byte(provider.Credentials), &credentials); err != nil {
+		return fmt.Errorf("failed to parse credentials: %w", err)
+	}
+
+	serviceAccountJSON, _ := credentials["serviceAccountKey"].(string)
+	projectID := provider.ProjectID
+
+	if serviceAccountJSON == "" || projectID == "" {
+		return fmt.Errorf("missing GCP credentials (serviceAccountKey) or projectId")
+	}
+
+	computeService, err := compute.NewService(ctx, option.WithCredentialsJSON([]byte(serviceAccountJSON)))
+	if err != nil {
+		return fmt.Errorf("failed to create compute service: %w", err)
+	}
+
+	// GCP label keys and values are lowercase
+	labels := make(map[string]string, len(tags))
+	for key, value := range tags {
+		labels[gcpLabel(key)] = gcpLabel(value)
+	}
+
+	count := 0
+	err = computeService.Instances.AggregatedList(projectID).Pages(ctx, func(page *compute.InstanceAggregatedList) error {
+		for _, scoped := range page.Items {
+			for _, instance := range scoped.Instances {
+				if count >= maxTagsPerRun {
+					return nil
+				}
+				zone := instance.Zone[strings.LastIndex(instance.Zone, "/")+1:]
+				if !run.selects(instance.Name, zone, instance.Labels) {
+					continue
+				}
+
+				existing := make(map[string]bool)
+				for key := range instance.Labels {
+					existing[key] = true
+				}
+				missing := missingTags(labels, existing, exactKey)
+				if len(missing) == 0 {
+					continue
+				}
+
+				merged := make(map[string]string, len(instance.Labels)+len(missing))
+				for key, value := range instance.Labels {
+					merged[key] = value
+				}
+				for key, value := range missing {
+					merged[key] = value
+				}
+
+				err := run.act(RemediationCandidate{
+					ResourceID: fmt.Sprintf("%d", instance.Id),
+					Name:       instance.Name,
+					Action:     "tag",
+					Reason:     tagReason(missing),
+				}, func() error {
+					// The fingerprint makes the update fail rather than drop labels changed since listing
+					_, err := computeService.Instances.SetLabels(projectID, zone, instance.Name, &compute.InstancesSetLabelsRequest{
+						Labels:           merged,
+						LabelFingerprint: instance.LabelFingerprint,
+					}).Context(ctx).Do()
+					return err
+				})
+				if err != nil {
+					fmt.Printf("Error labeling GCP instance %s: %v\n", instance.Name, err)
+					continue
+				}
+				count = run.acted()
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list instances: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package cloud
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	models "finopsbridge/api/internal/models_"
+)
+
+func TestAutoTagOptIn(t *testing.T) {
+	if AutoTag(map[string]interface{}{}) || AutoTagDryRun(map[string]interface{}{}) {
+		t.Error("auto-tagging should be off unless configured")
+	}
+	if !AutoTag(map[string]interface{}{"autoTag": true}) {
+		t.Error("autoTag: true should opt in")
+	}
+	if !AutoTagDryRun(map[string]interface{}{"autoTagDryRun": true}) {
+		t.Error("autoTagDryRun: true should opt in to a dry run")
+	}
+}
+
+func TestDefaultTags(t *testing.T) {
+	provider := models.CloudProvider{Name: "prod-aws"}
+	policyConfig := map[string]interface{}{
+		"requiredTags": []interface{}{"Owner", "Environment", "CostCenter", "Project"},
+		"defaultTags": map[string]interface{}{
+			"Environment": "shared",
+			"CostCenter":  "cc-100",
+			"Project":     "",
+			"Unrequired":  "ignored",
+		},
+	}
+
+	got := DefaultTags(provider, policyConfig)
+	want := map[string]string{
+		"Owner":       "prod-aws",
+		"Environment": "shared",
+		"CostCenter":  "cc-100",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestMissingTags(t *testing.T) {
+	tags := map[string]string{"Owner": "team", "Environment": "unassigned"}
+	existing := map[string]bool{"owner": true}
+
+	if got := missingTags(tags, existing, exactKey); len(got) != 2 {
+		t.Errorf("keys are case-sensitive with exactKey, got %v", got)
+	}
+	got := missingTags(tags, existing, gcpLabel)
+	if want := map[string]string{"Environment": "unassigned"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestTagReasonIsSorted(t *testing.T) {
+	got := tagReason(map[string]string{"Owner": "team", "CostCenter": "cc-100"})
+	if want := "missing required tags; applying CostCenter=cc-100, Owner=team"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestGCPLabel(t *testing.T) {
+	tests := map[string]string{
+		"CostCenter":            "costcenter",
+		"Cost Center/Team":      "cost_center_team",
+		"team-a_1":              "team-a_1",
+		strings.Repeat("a", 70): strings.Repeat("a", 63),
+	}
+	for in, want := range tests {
+		if got := gcpLabel(in); got != want {
+			t.Errorf("gcpLabel(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestApplyDefaultTagsWithoutTags(t *testing.T) {
+	result, err := ApplyDefaultTags(context.Background(), models.CloudProvider{Type: "oci"}, nil, nil, RemediationOptions{})
+	if err != nil || result.Attempted() != 0 {
+		t.Errorf("no tags should be a no-op, got %+v, %v", result, err)
+	}
+}
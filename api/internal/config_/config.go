@@ -2,6 +2,7 @@ package config
 
 import (
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/joho/godotenv"
@@ -16,6 +17,35 @@ type Config struct {
 	AWSRegion       string
 	AzureTenantID   string
 	GCPProjectID    string
+	RawMetricsRetentionDays    int // TokenUsage/GPUMetrics rows older than this are rolled up daily
+	DailyRollupRetentionDays   int // Daily rollups older than this are rolled up monthly
+	SpendSnapshotRetentionDays int // Older spend snapshots keep only the last one per month
+	DecisionLogEnabled         bool // Record every policy evaluation in the decision log
+	DecisionLogRetentionDays   int  // Decision log entries older than this are deleted
+	OCIMaxCompartmentDepth     int // How many levels of OCI sub-compartments are attributed separately
+	BillingCacheTTLMinutes     int // How long fetched billing data is reused; 0 disables the cache
+	EnforcementConcurrency     int // Providers the enforcement worker processes at once
+	RemediationBreakerMaxActions    int // Remediation actions per org within the window before enforcement is paused; 0 disables
+	RemediationBreakerWindowMinutes int
+	EnforcementQuietHours      string // "HH:MM-HH:MM" in UTC during which remediation is held; may wrap midnight
+	DashboardCacheTTLSeconds   int // How long computed dashboard stats are reused; 0 disables the cache
+	ReportingCurrency          string             // Currency aggregate reports are converted into
+	DefaultCurrency            string             // Currency assumed for billing data that doesn't report one
+	ExchangeRates              map[string]float64 // Units of ReportingCurrency per unit of each currency
+	ExchangeRatesURL           string             // Fetch rates from here instead, falling back to ExchangeRates
+	ExchangeRatesRefreshMinutes int               // How often rates are fetched from ExchangeRatesURL
+	RecommendationSpendThresholds map[string]float64 // Monthly spend above which a policy type is recommended
+	ModelCatalogStaleDays      int // Model catalog prices last updated longer ago than this are flagged as stale
+}
+
+// defaultRecommendationSpendThresholds are the monthly spend gates of the recommendation engine,
+// by policy type. Types without one are recommended regardless of spend.
+var defaultRecommendationSpendThresholds = map[string]float64{
+	"max_spend":           1000,
+	"backup_enforcement":  2000,
+	"rightsizing":         3000,
+	"block_instance_type": 5000,
+	"reserved_instance":   5000,
 }
 
 func Load() *Config {
@@ -30,6 +60,25 @@ func Load() *Config {
 		AWSRegion:      getEnv("AWS_REGION", "us-east-1"),
 		AzureTenantID:   getEnv("AZURE_TENANT_ID", ""),
 		GCPProjectID:    getEnv("GCP_PROJECT_ID", ""),
+		RawMetricsRetentionDays:    getEnvInt("RAW_METRICS_RETENTION_DAYS", 90),
+		DailyRollupRetentionDays:   getEnvInt("DAILY_ROLLUP_RETENTION_DAYS", 400),
+		SpendSnapshotRetentionDays: getEnvInt("SPEND_SNAPSHOT_RETENTION_DAYS", 90),
+		DecisionLogEnabled:         getEnvBool("DECISION_LOG_ENABLED", false),
+		DecisionLogRetentionDays:   getEnvInt("DECISION_LOG_RETENTION_DAYS", 30),
+		OCIMaxCompartmentDepth:     getEnvInt("OCI_MAX_COMPARTMENT_DEPTH", 5),
+		BillingCacheTTLMinutes:     getEnvInt("BILLING_CACHE_TTL_MINUTES", 60),
+		EnforcementConcurrency:     getEnvInt("ENFORCEMENT_CONCURRENCY", 4),
+		RemediationBreakerMaxActions:    getEnvInt("REMEDIATION_BREAKER_MAX_ACTIONS", 50),
+		RemediationBreakerWindowMinutes: getEnvInt("REMEDIATION_BREAKER_WINDOW_MINUTES", 60),
+		EnforcementQuietHours:      getEnv("ENFORCEMENT_QUIET_HOURS", ""),
+		DashboardCacheTTLSeconds:   getEnvInt("DASHBOARD_CACHE_TTL_SECONDS", 30),
+		ReportingCurrency:          strings.ToUpper(getEnv("REPORTING_CURRENCY", "USD")),
+		DefaultCurrency:            strings.ToUpper(getEnv("DEFAULT_CURRENCY", "USD")),
+		ExchangeRates:              getEnvRates("EXCHANGE_RATES"),
+		ExchangeRatesURL:           getEnv("EXCHANGE_RATES_URL", ""),
+		ExchangeRatesRefreshMinutes: getEnvInt("EXCHANGE_RATES_REFRESH_MINUTES", 720),
+		RecommendationSpendThresholds: getEnvThresholds("RECOMMENDATION_SPEND_THRESHOLDS", defaultRecommendationSpendThresholds),
+		ModelCatalogStaleDays:      getEnvInt("MODEL_CATALOG_STALE_DAYS", 30),
 	}
 }
 
@@ -44,3 +93,54 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+// getEnvRates parses a list like "EUR=1.08,GBP=1.27", skipping malformed entries
+func getEnvRates(key string) map[string]float64 {
+	rates := make(map[string]float64)
+	for _, entry := range strings.Split(os.Getenv(key), ",") {
+		parts := strings.SplitN(strings.TrimSpace(entry), "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		if rate, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64); err == nil && rate > 0 {
+			rates[strings.ToUpper(strings.TrimSpace(parts[0]))] = rate
+		}
+	}
+	return rates
+}
+
+// getEnvThresholds parses a list like "max_spend=2500,rightsizing=10000" over a copy of the
+// defaults, skipping malformed or negative entries
+func getEnvThresholds(key string, defaults map[string]float64) map[string]float64 {
+	thresholds := make(map[string]float64, len(defaults))
+	for policyType, threshold := range defaults {
+		thresholds[policyType] = threshold
+	}
+	for _, entry := range strings.Split(os.Getenv(key), ",") {
+		parts := strings.SplitN(strings.TrimSpace(entry), "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		if threshold, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64); err == nil && threshold >= 0 {
+			thresholds[strings.ToLower(strings.TrimSpace(parts[0]))] = threshold
+		}
+	}
+	return thresholds
+}
@@ -0,0 +1,77 @@
+package config
+
+import "testing"
+
+func TestGetEnvInt(t *testing.T) {
+	t.Setenv("RAW_METRICS_RETENTION_DAYS", "30")
+	if got := getEnvInt("RAW_METRICS_RETENTION_DAYS", 90); got != 30 {
+		t.Errorf("got %d, want 30", got)
+	}
+
+	t.Setenv("RAW_METRICS_RETENTION_DAYS", "thirty")
+	if got := getEnvInt("RAW_METRICS_RETENTION_DAYS", 90); got != 90 {
+		t.Errorf("an invalid value should fall back to the default, got %d", got)
+	}
+
+	t.Setenv("RAW_METRICS_RETENTION_DAYS", "")
+	if got := getEnvInt("RAW_METRICS_RETENTION_DAYS", 90); got != 90 {
+		t.Errorf("an unset value should fall back to the default, got %d", got)
+	}
+}
+
+func TestGetEnvBool(t *testing.T) {
+	t.Setenv("DECISION_LOG_ENABLED", "true")
+	if !getEnvBool("DECISION_LOG_ENABLED", false) {
+		t.Error("got false, want true")
+	}
+
+	t.Setenv("DECISION_LOG_ENABLED", "yes")
+	if getEnvBool("DECISION_LOG_ENABLED", false) {
+		t.Error("an invalid value should fall back to the default")
+	}
+
+	t.Setenv("DECISION_LOG_ENABLED", "")
+	if !getEnvBool("DECISION_LOG_ENABLED", true) {
+		t.Error("an unset value should fall back to the default")
+	}
+}
+
+func TestLoadRetentionDefaults(t *testing.T) {
+	for _, key := range []string{"RAW_METRICS_RETENTION_DAYS", "DAILY_ROLLUP_RETENTION_DAYS", "SPEND_SNAPSHOT_RETENTION_DAYS"} {
+		t.Setenv(key, "")
+	}
+
+	cfg := Load()
+	if cfg.RawMetricsRetentionDays != 90 || cfg.DailyRollupRetentionDays != 400 || cfg.SpendSnapshotRetentionDays != 90 {
+		t.Errorf("got retention days %d/%d/%d, want 90/400/90",
+			cfg.RawMetricsRetentionDays, cfg.DailyRollupRetentionDays, cfg.SpendSnapshotRetentionDays)
+	}
+}
+
+func TestGetEnvRates(t *testing.T) {
+	t.Setenv("EXCHANGE_RATES", " eur=1.08, GBP = 1.27,JPY,INR=-1,CAD=abc")
+	rates := getEnvRates("EXCHANGE_RATES")
+
+	if len(rates) != 2 || rates["EUR"] != 1.08 || rates["GBP"] != 1.27 {
+		t.Errorf("got %v, want only the valid EUR and GBP rates", rates)
+	}
+}
+
+func TestGetEnvThresholds(t *testing.T) {
+	defaults := map[string]float64{"max_spend": 1000, "rightsizing": 3000}
+	t.Setenv("RECOMMENDATION_SPEND_THRESHOLDS", " MAX_SPEND = 2500,rightsizing=-1,backup_enforcement=0,malformed,reserved_instance=lots")
+
+	got := getEnvThresholds("RECOMMENDATION_SPEND_THRESHOLDS", defaults)
+	want := map[string]float64{"max_spend": 2500, "rightsizing": 3000, "backup_enforcement": 0}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for policyType, threshold := range want {
+		if got[policyType] != threshold {
+			t.Errorf("%s: got %v, want %v", policyType, got[policyType], threshold)
+		}
+	}
+	if defaults["max_spend"] != 1000 {
+		t.Error("the defaults should not be modified")
+	}
+}
@@ -1,6 +1,9 @@
 package database
 
 import (
+	"encoding/json"
+	"fmt"
+
 	models "finopsbridge/api/internal/models_"
 
 	"gorm.io/driver/postgres"
@@ -21,24 +24,105 @@ func Initialize(databaseURL string) (*gorm.DB, error) {
 		&models.User{},
 		&models.Organization{},
 		&models.CloudProvider{},
+		&models.CloudProviderGroup{},
+		&models.SpendSnapshot{},
+		&models.Budget{},
+		&models.ProtectedResource{},
 		&models.Policy{},
 		&models.PolicyViolation{},
+		&models.ViolationComment{},
 		&models.ActivityLog{},
+		&models.EnforcementRun{},
+		&models.DecisionLog{},
 		&models.WaitlistEntry{},
 		&models.Webhook{},
+		&models.WebhookDelivery{},
 		&models.PolicyCategory{},
 		&models.PolicyTemplate{},
 		&models.PolicyRecommendation{},
 		&models.PolicyAdoptionMetrics{},
 		&models.AIWorkload{},
 		&models.TokenUsage{},
+		&models.TokenCostBaseline{},
 		&models.GPUMetrics{},
+		&models.UsageRollup{},
 		&models.AIBudget{},
 		&models.AIModelCatalog{},
 	); err != nil {
 		return nil, err
 	}
 
+	if err := ensurePendingViolationIndex(db); err != nil {
+		return nil, err
+	}
+
+	backfillPromotedMetadata(db)
+
 	return db, nil
 }
 
+// pendingViolationIndex allows one pending violation per policy and resource
+const pendingViolationIndex = "idx_policy_violations_pending"
+
+// ensurePendingViolationIndex creates pendingViolationIndex, so overlapping enforcement runs
+// can't both create a violation. It only does anything while the index is missing: pending
+// duplicates left from before it are then set to ignored, keeping the earliest pending. No
+// violation is deleted, so their comments and history stay.
+func ensurePendingViolationIndex(db *gorm.DB) error {
+	if db.Migrator().HasIndex(&models.PolicyViolation{}, pendingViolationIndex) {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`UPDATE policy_violations v SET status = 'ignored'
+			FROM policy_violations earlier
+			WHERE v.status = 'pending' AND earlier.status = 'pending'
+				AND v.policy_id = earlier.policy_id AND v.resource_id = earlier.resource_id
+				AND (earlier.created_at, earlier.id) < (v.created_at, v.id)`).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS ` + pendingViolationIndex + `
+			ON policy_violations (policy_id, resource_id) WHERE status = 'pending'`).Error
+	})
+}
+
+// backfillPromotedMetadata fills the TokenUsage and GPUMetrics columns promoted from their JSON
+// metadata on rows created before the columns existed. It's best effort: failures are logged
+// and never stop the API from starting.
+func backfillPromotedMetadata(db *gorm.DB) {
+	backfillPromotedColumns(db, &models.TokenUsage{}, "team", "user_id")
+	backfillPromotedColumns(db, &models.GPUMetrics{}, "team", "region")
+}
+
+// promotedRow is a row whose promoted columns are still NULL
+type promotedRow struct {
+	ID       string
+	Metadata string
+}
+
+// backfillPromotedColumns sets each column from the same key of the rows' metadata, decoded in
+// Go so that malformed metadata only leaves its row's columns empty, and rendered as new rows'
+// columns are. Rows get an empty string rather than NULL when the key is missing, so later
+// startups find nothing left to scan.
+func backfillPromotedColumns(db *gorm.DB, model interface{}, columns ...string) {
+	var rows []promotedRow
+	result := db.Model(model).Select("id, metadata").Where(columns[0]+" IS NULL").
+		FindInBatches(&rows, 500, func(tx *gorm.DB, batch int) error {
+			for _, row := range rows {
+				var metadata map[string]interface{}
+				json.Unmarshal([]byte(row.Metadata), &metadata)
+
+				updates := make(map[string]interface{}, len(columns))
+				for _, column := range columns {
+					updates[column] = models.MetadataText(metadata, column)
+				}
+				if err := db.Model(model).Where("id = ?", row.ID).UpdateColumns(updates).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	if result.Error != nil {
+		fmt.Printf("Error backfilling promoted metadata columns: %v\n", result.Error)
+	}
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strings"
+
+	middleware "finopsbridge/api/internal/middleware_"
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// requestGatePolicyTypes are the AI policy types that can judge a single proposed LLM request
+var requestGatePolicyTypes = []string{"token_length_limits", "model_selection_governance"}
+
+// aiCheckPolicyResult is one policy's verdict on a proposed LLM request
+type aiCheckPolicyResult struct {
+	PolicyID string   `json:"policyId"`
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`
+	Allowed  bool     `json:"allowed"`
+	Messages []string `json:"messages,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// aiCheckMessages returns the violation messages of an OPA result
+func aiCheckMessages(result map[string]interface{}) []string {
+	if msgs, ok := result["violations"].([]string); ok {
+		return msgs
+	}
+	if msg, ok := result["msg"].(string); ok && msg != "" {
+		return []string{msg}
+	}
+	return nil
+}
+
+// CheckAIRequest evaluates a proposed LLM request against the organization's enabled
+// token_length_limits and model_selection_governance policies, so apps can gate a call before
+// making it. The request is denied if any policy denies it. A policy that fails to evaluate is
+// reported with its error but doesn't deny, matching how the enforcement worker treats errors.
+func (h *Handlers) CheckAIRequest(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+
+	var req struct {
+		Provider        string `json:"provider"`
+		Model           string `json:"model"`
+		Endpoint        string `json:"endpoint"`
+		Environment     string `json:"environment"`
+		InputTokens     int64  `json:"inputTokens"`
+		MaxOutputTokens int64  `json:"maxOutputTokens"`
+		Approved        bool   `json:"approved"`
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	if strings.TrimSpace(req.Model) == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "model is required",
+		})
+	}
+	if req.InputTokens < 0 || req.MaxOutputTokens < 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "inputTokens and maxOutputTokens can't be negative",
+		})
+	}
+
+	var policies []models.Policy
+	if err := h.DB.Where("organization_id = ? AND enabled = ? AND type IN ?", orgID, true, requestGatePolicyTypes).
+		Order("created_at").
+		Find(&policies).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch policies",
+		})
+	}
+
+	// The worst-case cost of the call, when the model is in the catalog
+	model := map[string]interface{}{"name": req.Model}
+	if req.Provider != "" {
+		var catalog models.AIModelCatalog
+		if err := h.DB.Where("provider = ? AND model_name = ?", req.Provider, req.Model).First(&catalog).Error; err == nil {
+			model["estimatedCost"], _ = tokenCost(catalog, req.InputTokens, req.MaxOutputTokens, 0)
+		}
+	}
+
+	allowed := true
+	messages := []string{}
+	results := make([]aiCheckPolicyResult, 0, len(policies))
+	for _, policy := range policies {
+		var policyConfig map[string]interface{}
+		if err := json.Unmarshal([]byte(policy.Config), &policyConfig); err != nil {
+			policyConfig = make(map[string]interface{})
+		}
+
+		input := map[string]interface{}{
+			"request": map[string]interface{}{
+				"inputTokens":     req.InputTokens,
+				"maxOutputTokens": req.MaxOutputTokens,
+				"endpoint":        req.Endpoint,
+				"approved":        req.Approved,
+			},
+			"model":       model,
+			"environment": req.Environment,
+			"config":      policyConfig,
+		}
+
+		policyAllowed, result, err := h.OPA.EvaluatePolicy(policy.ID, policy.Config, input)
+		verdict := aiCheckPolicyResult{
+			PolicyID: policy.ID,
+			Name:     policy.Name,
+			Type:     policy.Type,
+			Allowed:  true,
+		}
+		if err != nil {
+			verdict.Error = err.Error()
+		} else if !policyAllowed {
+			verdict.Allowed = false
+			verdict.Messages = aiCheckMessages(result)
+			allowed = false
+			messages = append(messages, verdict.Messages...)
+		}
+		results = append(results, verdict)
+	}
+
+	return c.JSON(fiber.Map{
+		"allowed":  allowed,
+		"messages": messages,
+		"policies": results,
+	})
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"io"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestAICheckMessages(t *testing.T) {
+	tests := []struct {
+		result map[string]interface{}
+		want   []string
+	}{
+		{map[string]interface{}{"violations": []string{"too many tokens", "model not approved"}}, []string{"too many tokens", "model not approved"}},
+		{map[string]interface{}{"msg": "too many tokens"}, []string{"too many tokens"}},
+		{map[string]interface{}{"msg": ""}, nil},
+		{map[string]interface{}{}, nil},
+	}
+	for _, tt := range tests {
+		if got := aiCheckMessages(tt.result); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("aiCheckMessages(%v) = %v, want %v", tt.result, got, tt.want)
+		}
+	}
+}
+
+// aiCheckApp serves CheckAIRequest for an organization without gating policies
+func aiCheckApp(t *testing.T) *fiber.App {
+	h := dryRunHandlers(t)
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("orgID", "org")
+		return c.Next()
+	})
+	app.Post("/ai/check", h.CheckAIRequest)
+	return app
+}
+
+func postAICheck(t *testing.T, app *fiber.App, body string) (int, string) {
+	t.Helper()
+	req := httptest.NewRequest("POST", "/ai/check", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	respBody, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(respBody)
+}
+
+func TestCheckAIRequestValidation(t *testing.T) {
+	app := aiCheckApp(t)
+	for _, body := range []string{
+		`not json`,
+		`{"provider": "openai", "inputTokens": 100}`,
+		`{"model": "gpt-4o", "inputTokens": -1}`,
+		`{"model": "gpt-4o", "maxOutputTokens": -1}`,
+	} {
+		if status, _ := postAICheck(t, app, body); status != fiber.StatusBadRequest {
+			t.Errorf("%s: status %d, want 400", body, status)
+		}
+	}
+}
+
+func TestCheckAIRequestWithoutPolicies(t *testing.T) {
+	status, body := postAICheck(t, aiCheckApp(t), `{"model": "gpt-4o", "inputTokens": 100, "maxOutputTokens": 50}`)
+	if status != fiber.StatusOK {
+		t.Fatalf("status %d: %s", status, body)
+	}
+	if want := `{"allowed":true,"messages":[],"policies":[]}`; body != want {
+		t.Errorf("got %s, want %s", body, want)
+	}
+}
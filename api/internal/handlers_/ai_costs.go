@@ -2,11 +2,14 @@ package handlers
 
 import (
 	"encoding/json"
+	"sort"
 	"time"
 
+	middleware "finopsbridge/api/internal/middleware_"
 	models "finopsbridge/api/internal/models_"
 
 	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
 )
 
 // TrackTokenUsage records token consumption from LLM APIs
@@ -35,6 +38,21 @@ func (h *Handlers) TrackTokenUsage(c *fiber.Ctx) error {
 
 	metadataJSON, _ := json.Marshal(req.Metadata)
 
+	// Price the usage from the model catalog; a cost reported by the caller takes precedence
+	cost := req.Cost
+	var cacheSavings float64
+	var priceWarning string
+	var catalog models.AIModelCatalog
+	if err := h.DB.Where("provider = ? AND model_name = ?", req.Provider, req.ModelName).
+		First(&catalog).Error; err == nil {
+		computed, savings := tokenCost(catalog, req.InputTokens, req.OutputTokens, req.CachedTokens)
+		if cost == 0 {
+			cost = computed
+			priceWarning = h.catalogPriceWarning(catalog, time.Now())
+		}
+		cacheSavings = savings
+	}
+
 	usage := models.TokenUsage{
 		OrganizationID: orgID,
 		AIWorkloadID:   req.AIWorkloadID,
@@ -44,8 +62,9 @@ func (h *Handlers) TrackTokenUsage(c *fiber.Ctx) error {
 		InputTokens:    req.InputTokens,
 		OutputTokens:   req.OutputTokens,
 		TotalTokens:    req.InputTokens + req.OutputTokens,
-		Cost:           req.Cost,
+		Cost:           cost,
 		CachedTokens:   req.CachedTokens,
+		CacheSavings:   cacheSavings,
 		RequestCount:   req.RequestCount,
 		Timestamp:      time.Now(),
 		Metadata:       string(metadataJSON),
@@ -57,35 +76,61 @@ func (h *Handlers) TrackTokenUsage(c *fiber.Ctx) error {
 		})
 	}
 
-	return c.Status(201).JSON(usage)
+	return c.Status(201).JSON(struct {
+		models.TokenUsage
+		PriceWarning string `json:"priceWarning,omitempty"` // Set when the cost was priced from a stale catalog entry
+	}{usage, priceWarning})
 }
 
 // GetTokenUsage returns token usage analytics
 func (h *Handlers) GetTokenUsage(c *fiber.Ctx) error {
-	orgID := c.Locals("orgId").(string)
+	orgID := middleware.GetOrgID(c)
+	if orgID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Organization ID required",
+		})
+	}
 
 	// Query parameters for filtering
 	provider := c.Query("provider")
 	modelName := c.Query("model")
+	team := c.Query("team")
+	userID := c.Query("user_id")
 	startDate := c.Query("start_date")
 	endDate := c.Query("end_date")
 
 	query := h.DB.Where("organization_id = ?", orgID)
+	// Days already rolled up by the retention worker no longer have raw rows
+	rollupQuery := h.DB.Where("organization_id = ? AND source = ?", orgID, models.RollupSourceTokenUsage)
 
 	if provider != "" {
 		query = query.Where("provider = ?", provider)
+		rollupQuery = rollupQuery.Where("provider = ?", provider)
 	}
 
 	if modelName != "" {
 		query = query.Where("model_name = ?", modelName)
+		rollupQuery = rollupQuery.Where("model_name = ?", modelName)
+	}
+
+	if team != "" {
+		query = query.Where("team = ?", team)
+		rollupQuery = rollupQuery.Where("team = ?", team)
+	}
+
+	if userID != "" {
+		query = query.Where("user_id = ?", userID)
+		rollupQuery = rollupQuery.Where("user_id = ?", userID)
 	}
 
 	if startDate != "" {
 		query = query.Where("timestamp >= ?", startDate)
+		rollupQuery = rollupQuery.Where("period_start >= ?", startDate)
 	}
 
 	if endDate != "" {
 		query = query.Where("timestamp <= ?", endDate)
+		rollupQuery = rollupQuery.Where("period_start <= ?", endDate)
 	}
 
 	var usage []models.TokenUsage
@@ -95,6 +140,13 @@ func (h *Handlers) GetTokenUsage(c *fiber.Ctx) error {
 		})
 	}
 
+	var rollups []models.UsageRollup
+	if err := rollupQuery.Order("period_start DESC").Find(&rollups).Error; err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to fetch token usage",
+		})
+	}
+
 	// Calculate aggregated statistics
 	type TokenStats struct {
 		TotalInputTokens  int64   `json:"totalInputTokens"`
@@ -114,6 +166,15 @@ func (h *Handlers) GetTokenUsage(c *fiber.Ctx) error {
 		stats.TotalTokens += u.TotalTokens
 		stats.TotalCost += u.Cost
 		stats.TotalRequests += u.RequestCount
+		stats.CacheSavings += u.CacheSavings
+	}
+	for _, r := range rollups {
+		stats.TotalInputTokens += r.InputTokens
+		stats.TotalOutputTokens += r.OutputTokens
+		stats.TotalTokens += r.TotalTokens
+		stats.TotalCost += r.Cost
+		stats.TotalRequests += int(r.RequestCount)
+		stats.CacheSavings += r.CacheSavings
 	}
 
 	if stats.TotalRequests > 0 {
@@ -122,11 +183,37 @@ func (h *Handlers) GetTokenUsage(c *fiber.Ctx) error {
 	}
 
 	return c.JSON(fiber.Map{
-		"usage": usage,
-		"stats": stats,
+		"usage":   usage,
+		"rollups": rollups,
+		"stats":   stats,
 	})
 }
 
+// tokenCost prices token usage from a catalog entry. Cached tokens are part of the input
+// tokens and billed at CachedInputPricePerMToken when set; savings is what they would have
+// cost at the full input price minus what they did cost.
+func tokenCost(catalog models.AIModelCatalog, inputTokens, outputTokens, cachedTokens int64) (cost, savings float64) {
+	if cachedTokens > inputTokens {
+		cachedTokens = inputTokens
+	}
+	if cachedTokens < 0 {
+		cachedTokens = 0
+	}
+
+	cachedPrice := catalog.InputPricePerMToken
+	if catalog.CachedInputPricePerMToken > 0 {
+		cachedPrice = catalog.CachedInputPricePerMToken
+	}
+
+	uncachedInput := float64(inputTokens-cachedTokens) * catalog.InputPricePerMToken / 1e6
+	cachedInput := float64(cachedTokens) * cachedPrice / 1e6
+	output := float64(outputTokens) * catalog.OutputPricePerMToken / 1e6
+
+	cost = uncachedInput + cachedInput + output
+	savings = float64(cachedTokens) * (catalog.InputPricePerMToken - cachedPrice) / 1e6
+	return cost, savings
+}
+
 // TrackGPUMetrics records GPU utilization and costs
 func (h *Handlers) TrackGPUMetrics(c *fiber.Ctx) error {
 	orgID := c.Locals("orgId").(string)
@@ -142,6 +229,7 @@ func (h *Handlers) TrackGPUMetrics(c *fiber.Ctx) error {
 		MemoryUsed    float64 `json:"memoryUsed"`
 		MemoryTotal   float64 `json:"memoryTotal"`
 		HourlyCost    float64 `json:"hourlyCost"`
+		IntervalSeconds int `json:"intervalSeconds"` // Optional sampling period
 		Status        string  `json:"status"`
 		Metadata      map[string]interface{} `json:"metadata"`
 	}
@@ -153,6 +241,12 @@ func (h *Handlers) TrackGPUMetrics(c *fiber.Ctx) error {
 		})
 	}
 
+	if req.IntervalSeconds < 0 {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "intervalSeconds must be positive",
+		})
+	}
+
 	metadataJSON, _ := json.Marshal(req.Metadata)
 
 	metrics := models.GPUMetrics{
@@ -167,6 +261,7 @@ func (h *Handlers) TrackGPUMetrics(c *fiber.Ctx) error {
 		MemoryUsed:     req.MemoryUsed,
 		MemoryTotal:    req.MemoryTotal,
 		HourlyCost:     req.HourlyCost,
+		IntervalSeconds: req.IntervalSeconds,
 		Status:         req.Status,
 		Timestamp:      time.Now(),
 		Metadata:       string(metadataJSON),
@@ -186,6 +281,8 @@ func (h *Handlers) GetGPUMetrics(c *fiber.Ctx) error {
 	orgID := c.Locals("orgId").(string)
 
 	cloudProvider := c.Query("provider")
+	team := c.Query("team")
+	region := c.Query("region")
 	startDate := c.Query("start_date")
 	endDate := c.Query("end_date")
 
@@ -195,6 +292,14 @@ func (h *Handlers) GetGPUMetrics(c *fiber.Ctx) error {
 		query = query.Where("cloud_provider = ?", cloudProvider)
 	}
 
+	if team != "" {
+		query = query.Where("team = ?", team)
+	}
+
+	if region != "" {
+		query = query.Where("region = ?", region)
+	}
+
 	if startDate != "" {
 		query = query.Where("timestamp >= ?", startDate)
 	}
@@ -211,39 +316,7 @@ func (h *Handlers) GetGPUMetrics(c *fiber.Ctx) error {
 	}
 
 	// Calculate aggregated statistics
-	type GPUStats struct {
-		AverageUtilization float64 `json:"averageUtilization"`
-		TotalGPUHours      float64 `json:"totalGPUHours"`
-		TotalCost          float64 `json:"totalCost"`
-		IdleGPUHours       float64 `json:"idleGPUHours"`
-		IdleCostWaste      float64 `json:"idleCostWaste"`
-		UniqueInstances    int     `json:"uniqueInstances"`
-	}
-
-	stats := GPUStats{}
-	instanceMap := make(map[string]bool)
-	utilizationSum := 0.0
-	count := 0
-
-	for _, m := range metrics {
-		instanceMap[m.InstanceID] = true
-		utilizationSum += m.Utilization
-		count++
-
-		// Calculate idle waste (utilization < 10%)
-		if m.Utilization < 10.0 {
-			stats.IdleGPUHours += 1.0 // Assuming 1-hour intervals
-			stats.IdleCostWaste += m.HourlyCost
-		}
-
-		stats.TotalCost += m.HourlyCost
-	}
-
-	stats.UniqueInstances = len(instanceMap)
-	if count > 0 {
-		stats.AverageUtilization = utilizationSum / float64(count)
-	}
-	stats.TotalGPUHours = float64(count)
+	stats := computeGPUStats(metrics)
 
 	return c.JSON(fiber.Map{
 		"metrics": metrics,
@@ -291,7 +364,7 @@ func (h *Handlers) CreateAIWorkload(c *fiber.Ctx) error {
 		})
 	}
 
-	h.logActivity(orgID, "ai_workload_created", "Created AI workload: "+workload.Name, nil)
+	h.logActivity(c.UserContext(), orgID, "ai_workload_created", "Created AI workload: "+workload.Name, nil)
 
 	return c.Status(201).JSON(workload)
 }
@@ -352,7 +425,7 @@ func (h *Handlers) CreateAIBudget(c *fiber.Ctx) error {
 		})
 	}
 
-	h.logActivity(orgID, "ai_budget_created", "Created AI budget: "+budget.Name, nil)
+	h.logActivity(c.UserContext(), orgID, "ai_budget_created", "Created AI budget: "+budget.Name, nil)
 
 	return c.Status(201).JSON(budget)
 }
@@ -394,6 +467,248 @@ func (h *Handlers) ListAIBudgets(c *fiber.Ctx) error {
 	return c.JSON(responses)
 }
 
+// GetAIBudgetUsage recomputes a budget's usage live from TokenUsage/GPUMetrics
+// instead of relying on the stored CurrentUsage
+func (h *Handlers) GetAIBudgetUsage(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+	budgetID := c.Params("id")
+
+	var budget models.AIBudget
+	if err := h.DB.Where("id = ? AND organization_id = ?", budgetID, orgID).First(&budget).Error; err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"error": "AI budget not found",
+		})
+	}
+
+	var scope map[string]interface{}
+	json.Unmarshal([]byte(budget.Scope), &scope)
+
+	periodStart := budgetPeriodStart(budget.Period, time.Now())
+
+	// sum adds up one column of a query's rows into total, keeping the first error
+	var err error
+	sum := func(query *gorm.DB, column string, total *float64) {
+		if err == nil {
+			err = query.Select("COALESCE(SUM(" + column + "), 0)").Find(total).Error
+		}
+	}
+
+	// Usage old enough to have been rolled up is read from the rollups
+	var usage, rolledUp float64
+	switch budget.BudgetType {
+	case "token_limit":
+		sum(h.scopedTokenUsage(orgID, scope, periodStart), "total_tokens", &usage)
+		sum(h.scopedRollups(orgID, models.RollupSourceTokenUsage, scope, periodStart), "total_tokens", &rolledUp)
+	case "cost_limit":
+		var tokenCost, gpuCost, rolledUpGPU float64
+		sum(h.scopedTokenUsage(orgID, scope, periodStart), "cost", &tokenCost)
+		sum(models.GPUSamples(h.DB, h.scopedGPUMetrics(orgID, scope, periodStart)), "hourly_cost * sample_hours", &gpuCost)
+		sum(h.scopedRollups(orgID, models.RollupSourceTokenUsage, scope, periodStart), "cost", &rolledUp)
+		sum(h.scopedRollups(orgID, models.RollupSourceGPUMetrics, scope, periodStart), "cost", &rolledUpGPU)
+		usage = tokenCost + gpuCost
+		rolledUp += rolledUpGPU
+	case "gpu_hours":
+		sum(models.GPUSamples(h.DB, h.scopedGPUMetrics(orgID, scope, periodStart)), "sample_hours", &usage)
+		sum(h.scopedRollups(orgID, models.RollupSourceGPUMetrics, scope, periodStart), "gpu_hours", &rolledUp)
+	default:
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Unsupported budget type: " + budget.BudgetType,
+		})
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"error": "Failed to compute AI budget usage",
+		})
+	}
+
+	usage += rolledUp
+
+	percentUsed := 0.0
+	if budget.LimitValue > 0 {
+		percentUsed = (usage / budget.LimitValue) * 100
+	}
+
+	var thresholds []int
+	json.Unmarshal([]byte(budget.AlertThresholds), &thresholds)
+	crossedThresholds := []int{}
+	for _, threshold := range thresholds {
+		if percentUsed >= float64(threshold) {
+			crossedThresholds = append(crossedThresholds, threshold)
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"budgetId":          budget.ID,
+		"budgetType":        budget.BudgetType,
+		"period":            budget.Period,
+		"periodStart":       periodStart,
+		"currentUsage":      usage,
+		"limitValue":        budget.LimitValue,
+		"percentUsed":       percentUsed,
+		"remainingBudget":   budget.LimitValue - usage,
+		"isOverBudget":      usage >= budget.LimitValue,
+		"crossedThresholds": crossedThresholds,
+	})
+}
+
+// defaultGPUSampleSeconds is used when a sample's interval can't be inferred
+const defaultGPUSampleSeconds = 3600
+
+// idleUtilizationThreshold is the utilization (%) below which a GPU counts as idle
+const idleUtilizationThreshold = 10.0
+
+// GPUStats summarizes GPU metrics weighted by the time each sample represents
+type GPUStats struct {
+	AverageUtilization float64 `json:"averageUtilization"`
+	TotalGPUHours      float64 `json:"totalGPUHours"`
+	TotalCost          float64 `json:"totalCost"`
+	IdleGPUHours       float64 `json:"idleGPUHours"`
+	IdleCostWaste      float64 `json:"idleCostWaste"`
+	UniqueInstances    int     `json:"uniqueInstances"`
+}
+
+// computeGPUStats aggregates GPU metrics. Each row covers IntervalSeconds when set; otherwise
+// its interval is inferred from the gap to the previous sample of the same instance, capped
+// at (and defaulting to) one hour. models.GPUSamples does the same in SQL.
+func computeGPUStats(metrics []models.GPUMetrics) GPUStats {
+	stats := GPUStats{}
+
+	sorted := make([]models.GPUMetrics, len(metrics))
+	copy(sorted, metrics)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	lastSeen := make(map[string]time.Time)
+	weightedUtilization := 0.0
+
+	for _, m := range sorted {
+		seconds := float64(m.IntervalSeconds)
+		if seconds <= 0 {
+			seconds = defaultGPUSampleSeconds
+			if previous, ok := lastSeen[m.InstanceID]; ok {
+				if gap := m.Timestamp.Sub(previous).Seconds(); gap > 0 && gap < seconds {
+					seconds = gap
+				}
+			}
+		}
+		lastSeen[m.InstanceID] = m.Timestamp
+
+		hours := seconds / 3600
+		cost := m.HourlyCost * hours
+
+		stats.TotalGPUHours += hours
+		stats.TotalCost += cost
+		weightedUtilization += m.Utilization * hours
+
+		if m.Utilization < idleUtilizationThreshold {
+			stats.IdleGPUHours += hours
+			stats.IdleCostWaste += cost
+		}
+	}
+
+	stats.UniqueInstances = len(lastSeen)
+	if stats.TotalGPUHours > 0 {
+		stats.AverageUtilization = weightedUtilization / stats.TotalGPUHours
+	}
+
+	return stats
+}
+
+// addRollup folds a GPU metrics rollup into the stats. Rollups don't keep idle cost, so it is
+// estimated at the rollup's average hourly cost, nor instance IDs, so UniqueInstances only
+// counts instances with raw metrics.
+func (s *GPUStats) addRollup(rollup models.UsageRollup) {
+	hours := s.TotalGPUHours + rollup.GPUHours
+	if hours > 0 {
+		s.AverageUtilization = (s.AverageUtilization*s.TotalGPUHours + rollup.AverageUtilization*rollup.GPUHours) / hours
+	}
+	s.TotalGPUHours = hours
+	s.TotalCost += rollup.Cost
+	s.IdleGPUHours += rollup.IdleGPUHours
+	if rollup.GPUHours > 0 {
+		s.IdleCostWaste += rollup.Cost * rollup.IdleGPUHours / rollup.GPUHours
+	}
+}
+
+// budgetPeriodStart returns the start of the budget period containing now
+func budgetPeriodStart(period string, now time.Time) time.Time {
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch period {
+	case "daily":
+		return startOfDay
+	case "weekly":
+		// Weeks start on Monday
+		offset := (int(now.Weekday()) + 6) % 7
+		return startOfDay.AddDate(0, 0, -offset)
+	default:
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	}
+}
+
+// scopedTokenUsage builds a TokenUsage query filtered by a budget scope
+// (provider, model, workloadId, workloadType)
+func (h *Handlers) scopedTokenUsage(orgID string, scope map[string]interface{}, since time.Time) *gorm.DB {
+	query := h.DB.Model(&models.TokenUsage{}).
+		Where("organization_id = ? AND timestamp >= ?", orgID, since)
+
+	if provider, ok := scope["provider"].(string); ok && provider != "" {
+		query = query.Where("provider = ?", provider)
+	}
+	if model, ok := scope["model"].(string); ok && model != "" {
+		query = query.Where("model_name = ?", model)
+	}
+	return h.applyWorkloadScope(query, scope)
+}
+
+// scopedGPUMetrics builds a GPUMetrics query filtered by a budget scope
+// (provider, gpuType, workloadId, workloadType)
+func (h *Handlers) scopedGPUMetrics(orgID string, scope map[string]interface{}, since time.Time) *gorm.DB {
+	query := h.DB.Model(&models.GPUMetrics{}).
+		Where("organization_id = ? AND timestamp >= ?", orgID, since)
+
+	if provider, ok := scope["provider"].(string); ok && provider != "" {
+		query = query.Where("cloud_provider = ?", provider)
+	}
+	if gpuType, ok := scope["gpuType"].(string); ok && gpuType != "" {
+		query = query.Where("gpu_type = ?", gpuType)
+	}
+	return h.applyWorkloadScope(query, scope)
+}
+
+// scopedRollups builds a UsageRollup query over one source's rollups starting since a time,
+// filtered by a budget scope like scopedTokenUsage and scopedGPUMetrics. Rollups only cover
+// days whose raw rows were deleted, so they add to the raw rows without overlap; a rollup
+// starting before since isn't counted.
+func (h *Handlers) scopedRollups(orgID, source string, scope map[string]interface{}, since time.Time) *gorm.DB {
+	query := h.DB.Model(&models.UsageRollup{}).
+		Where("organization_id = ? AND source = ? AND period_start >= ?", orgID, source, since)
+
+	if provider, ok := scope["provider"].(string); ok && provider != "" {
+		query = query.Where("provider = ?", provider)
+	}
+	if model, ok := scope["model"].(string); ok && model != "" && source == models.RollupSourceTokenUsage {
+		query = query.Where("model_name = ?", model)
+	}
+	if gpuType, ok := scope["gpuType"].(string); ok && gpuType != "" && source == models.RollupSourceGPUMetrics {
+		query = query.Where("gpu_type = ?", gpuType)
+	}
+	return h.applyWorkloadScope(query, scope)
+}
+
+func (h *Handlers) applyWorkloadScope(query *gorm.DB, scope map[string]interface{}) *gorm.DB {
+	if workloadID, ok := scope["workloadId"].(string); ok && workloadID != "" {
+		query = query.Where("ai_workload_id = ?", workloadID)
+	}
+	if workloadType, ok := scope["workloadType"].(string); ok && workloadType != "" {
+		query = query.Where("ai_workload_id IN (?)",
+			h.DB.Model(&models.AIWorkload{}).
+				Select("id").
+				Where("workload_type = ?", workloadType))
+	}
+	return query
+}
+
 // GetAIDashboard returns comprehensive AI cost dashboard data
 func (h *Handlers) GetAIDashboard(c *fiber.Ctx) error {
 	orgID := c.Locals("orgId").(string)
@@ -418,29 +733,31 @@ func (h *Handlers) GetAIDashboard(c *fiber.Ctx) error {
 		tokenStats["totalRequests"] = tokenStats["totalRequests"].(int) + u.RequestCount
 	}
 
+	// Days already rolled up by the retention worker no longer have raw rows
+	var tokenRollups, gpuRollups []models.UsageRollup
+	h.scopedRollups(orgID, models.RollupSourceTokenUsage, nil, startDate).Find(&tokenRollups)
+	h.scopedRollups(orgID, models.RollupSourceGPUMetrics, nil, startDate).Find(&gpuRollups)
+	for _, r := range tokenRollups {
+		tokenStats["totalTokens"] = tokenStats["totalTokens"].(int64) + r.TotalTokens
+		tokenStats["totalCost"] = tokenStats["totalCost"].(float64) + r.Cost
+		tokenStats["totalRequests"] = tokenStats["totalRequests"].(int) + int(r.RequestCount)
+	}
+
 	// GPU metrics summary
 	var gpuMetrics []models.GPUMetrics
 	h.DB.Where("organization_id = ? AND timestamp >= ?", orgID, startDate).Find(&gpuMetrics)
 
-	gpuStats := map[string]interface{}{
-		"averageUtilization": 0.0,
-		"totalGPUHours":      float64(len(gpuMetrics)),
-		"totalCost":          0.0,
-		"idleWaste":          0.0,
-	}
-
-	utilizationSum := 0.0
-	for _, m := range gpuMetrics {
-		utilizationSum += m.Utilization
-		gpuStats["totalCost"] = gpuStats["totalCost"].(float64) + m.HourlyCost
-		if m.Utilization < 10.0 {
-			gpuStats["idleWaste"] = gpuStats["idleWaste"].(float64) + m.HourlyCost
-		}
+	computed := computeGPUStats(gpuMetrics)
+	for _, r := range gpuRollups {
+		computed.addRollup(r)
 	}
-
-	if len(gpuMetrics) > 0 {
-		gpuStats["averageUtilization"] = utilizationSum / float64(len(gpuMetrics))
+	gpuStats := map[string]interface{}{
+		"averageUtilization": computed.AverageUtilization,
+		"totalGPUHours":      computed.TotalGPUHours,
+		"totalCost":          computed.TotalCost,
+		"idleWaste":          computed.IdleCostWaste,
 	}
+	gpuStats["byType"] = gpuCostByType(gpuMetrics, gpuRollups)
 
 	// Active workloads
 	var workloads []models.AIWorkload
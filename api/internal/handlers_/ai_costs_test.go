@@ -0,0 +1,322 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func TestBudgetPeriodStart(t *testing.T) {
+	// Thursday 2026-03-12
+	now := time.Date(2026, 3, 12, 15, 30, 0, 0, time.UTC)
+	tests := []struct {
+		period string
+		want   time.Time
+	}{
+		{"daily", time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)},
+		{"weekly", time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)},
+		{"monthly", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		if got := budgetPeriodStart(tt.period, now); !got.Equal(tt.want) {
+			t.Errorf("budgetPeriodStart(%q) = %v, want %v", tt.period, got, tt.want)
+		}
+	}
+
+	// A Sunday belongs to the week that started the Monday before
+	sunday := time.Date(2026, 3, 15, 23, 0, 0, 0, time.UTC)
+	if got := budgetPeriodStart("weekly", sunday); !got.Equal(time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("weekly start for a Sunday = %v, want Monday 2026-03-09", got)
+	}
+}
+
+func TestScopedTokenUsage(t *testing.T) {
+	h := dryRunHandlers(t)
+	since := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	sql := querySQL(h.scopedTokenUsage("org", map[string]interface{}{
+		"provider":     "openai",
+		"model":        "gpt-4o",
+		"workloadType": "inference",
+	}, since), &[]models.TokenUsage{})
+
+	for _, want := range []string{
+		`FROM "token_usages"`,
+		"organization_id = 'org'",
+		"provider = 'openai'",
+		"model_name = 'gpt-4o'",
+		`ai_workload_id IN (SELECT "id" FROM "ai_workloads" WHERE workload_type = 'inference')`,
+	} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("token usage query is missing %q:\n%s", want, sql)
+		}
+	}
+}
+
+func TestScopedGPUMetricsIgnoresTokenScope(t *testing.T) {
+	h := dryRunHandlers(t)
+
+	sql := querySQL(h.scopedGPUMetrics("org", map[string]interface{}{
+		"provider":   "aws",
+		"model":      "gpt-4o",
+		"gpuType":    "A100",
+		"workloadId": "w1",
+	}, time.Now()), &[]models.GPUMetrics{})
+
+	for _, want := range []string{"cloud_provider = 'aws'", "gpu_type = 'A100'", "ai_workload_id = 'w1'"} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("GPU metrics query is missing %q:\n%s", want, sql)
+		}
+	}
+	if strings.Contains(sql, "model_name") {
+		t.Errorf("GPU metrics can't be scoped by model:\n%s", sql)
+	}
+}
+
+func TestScopedRollupsScopesBySource(t *testing.T) {
+	h := dryRunHandlers(t)
+	scope := map[string]interface{}{"provider": "openai", "model": "gpt-4o", "gpuType": "A100"}
+	since := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tokenSQL := querySQL(h.scopedRollups("org", models.RollupSourceTokenUsage, scope, since), &[]models.UsageRollup{})
+	for _, want := range []string{"source = '" + models.RollupSourceTokenUsage + "'", "provider = 'openai'", "model_name = 'gpt-4o'"} {
+		if !strings.Contains(tokenSQL, want) {
+			t.Errorf("token rollup query is missing %q:\n%s", want, tokenSQL)
+		}
+	}
+	if strings.Contains(tokenSQL, "gpu_type") {
+		t.Errorf("token rollups can't be scoped by GPU type:\n%s", tokenSQL)
+	}
+
+	gpuSQL := querySQL(h.scopedRollups("org", models.RollupSourceGPUMetrics, scope, since), &[]models.UsageRollup{})
+	if !strings.Contains(gpuSQL, "gpu_type = 'A100'") || strings.Contains(gpuSQL, "model_name") {
+		t.Errorf("GPU rollups should be scoped by GPU type only:\n%s", gpuSQL)
+	}
+}
+
+func TestComputeGPUStatsWeightsBySampleInterval(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	metrics := []models.GPUMetrics{
+		// Out of order on purpose: intervals are inferred in timestamp order
+		{InstanceID: "a", Timestamp: start.Add(15 * time.Minute), Utilization: 80, HourlyCost: 4},
+		{InstanceID: "a", Timestamp: start, Utilization: 5, HourlyCost: 4},
+		{InstanceID: "b", Timestamp: start, Utilization: 50, HourlyCost: 2, IntervalSeconds: 1800},
+	}
+
+	stats := computeGPUStats(metrics)
+
+	// a's first sample has no predecessor and counts an hour, its second the 15 minutes since
+	if stats.TotalGPUHours != 1.75 {
+		t.Errorf("TotalGPUHours = %v, want 1.75", stats.TotalGPUHours)
+	}
+	if stats.TotalCost != 4+1+1 {
+		t.Errorf("TotalCost = %v, want 6", stats.TotalCost)
+	}
+	if stats.IdleGPUHours != 1 || stats.IdleCostWaste != 4 {
+		t.Errorf("idle = %v hours, %v cost; want 1 hour, 4 cost", stats.IdleGPUHours, stats.IdleCostWaste)
+	}
+	if want := (5*1 + 80*0.25 + 50*0.5) / 1.75; stats.AverageUtilization != want {
+		t.Errorf("AverageUtilization = %v, want %v", stats.AverageUtilization, want)
+	}
+	if stats.UniqueInstances != 2 {
+		t.Errorf("UniqueInstances = %d, want 2", stats.UniqueInstances)
+	}
+}
+
+func TestGPUStatsAddRollup(t *testing.T) {
+	stats := GPUStats{TotalGPUHours: 2, TotalCost: 8, AverageUtilization: 40}
+	stats.addRollup(models.UsageRollup{GPUHours: 6, Cost: 12, IdleGPUHours: 3, AverageUtilization: 20})
+
+	if stats.TotalGPUHours != 8 || stats.TotalCost != 20 || stats.IdleGPUHours != 3 {
+		t.Errorf("got %+v", stats)
+	}
+	if stats.AverageUtilization != 25 {
+		t.Errorf("AverageUtilization = %v, want 25 weighted by GPU hours", stats.AverageUtilization)
+	}
+	if stats.IdleCostWaste != 6 {
+		t.Errorf("IdleCostWaste = %v, want half the rollup's cost", stats.IdleCostWaste)
+	}
+}
+
+func TestTokenCostPricesCachedTokens(t *testing.T) {
+	catalog := models.AIModelCatalog{InputPricePerMToken: 2.5, OutputPricePerMToken: 10, CachedInputPricePerMToken: 1.25}
+
+	// 400k of the 1M input tokens were cached
+	cost, savings := tokenCost(catalog, 1_000_000, 100_000, 400_000)
+	if want := 0.6*2.5 + 0.4*1.25 + 0.1*10; math.Abs(cost-want) > 1e-9 {
+		t.Errorf("cost = %v, want %v", cost, want)
+	}
+	if want := 0.4 * 1.25; math.Abs(savings-want) > 1e-9 {
+		t.Errorf("savings = %v, want %v", savings, want)
+	}
+
+	// Without a cached price, cached tokens cost the full input price
+	catalog.CachedInputPricePerMToken = 0
+	cost, savings = tokenCost(catalog, 1_000_000, 0, 400_000)
+	if cost != 2.5 || savings != 0 {
+		t.Errorf("got cost %v and savings %v, want 2.5 and 0", cost, savings)
+	}
+}
+
+func TestTokenCostClampsCachedTokens(t *testing.T) {
+	catalog := models.AIModelCatalog{InputPricePerMToken: 2, CachedInputPricePerMToken: 1}
+
+	if cost, _ := tokenCost(catalog, 1_000_000, 0, 5_000_000); cost != 1 {
+		t.Errorf("cached tokens beyond the input tokens: cost = %v, want 1", cost)
+	}
+	if cost, savings := tokenCost(catalog, 1_000_000, 0, -10); cost != 2 || savings != 0 {
+		t.Errorf("negative cached tokens: got cost %v and savings %v, want 2 and 0", cost, savings)
+	}
+}
+
+// tokenUsageStats serves GetTokenUsage from the given raw rows and rollups and returns its stats
+// and the rollup query it ran
+func tokenUsageStats(t *testing.T, usage []models.TokenUsage, rollups []models.UsageRollup, query string) (map[string]float64, string) {
+	t.Helper()
+	h := dryRunHandlers(t)
+	var rollupSQL string
+	h.DB.Callback().Query().After("gorm:query").Register("test:stub_usage", func(tx *gorm.DB) {
+		switch dest := tx.Statement.Dest.(type) {
+		case *[]models.TokenUsage:
+			*dest = usage
+		case *[]models.UsageRollup:
+			rollupSQL = tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...)
+			*dest = rollups
+		}
+	})
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("orgID", "org")
+		return c.Next()
+	})
+	app.Get("/ai/tokens", h.GetTokenUsage)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/ai/tokens"+query, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("status %d, want 200", resp.StatusCode)
+	}
+	var body struct {
+		Stats map[string]float64 `json:"stats"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	return body.Stats, rollupSQL
+}
+
+func TestGetTokenUsageCountsRollups(t *testing.T) {
+	usage := []models.TokenUsage{
+		{InputTokens: 1000, OutputTokens: 200, TotalTokens: 1200, CachedTokens: 400, CacheSavings: 0.25, Cost: 1.5, RequestCount: 3, Team: "search"},
+		{InputTokens: 500, OutputTokens: 100, TotalTokens: 600, CacheSavings: 0.5, Cost: 0.75, RequestCount: 1, Team: "search"},
+	}
+	// The same day once the retention worker has rolled it up
+	rollup := models.UsageRollup{Source: models.RollupSourceTokenUsage, Team: "search", SampleCount: int64(len(usage))}
+	for _, u := range usage {
+		rollup.InputTokens += u.InputTokens
+		rollup.OutputTokens += u.OutputTokens
+		rollup.TotalTokens += u.TotalTokens
+		rollup.CachedTokens += u.CachedTokens
+		rollup.CacheSavings += u.CacheSavings
+		rollup.Cost += u.Cost
+		rollup.RequestCount += int64(u.RequestCount)
+	}
+
+	before, _ := tokenUsageStats(t, usage, nil, "?team=search")
+	after, rollupSQL := tokenUsageStats(t, nil, []models.UsageRollup{rollup}, "?team=search")
+
+	if !reflect.DeepEqual(before, after) {
+		t.Errorf("stats after rollup = %v, want %v as before", after, before)
+	}
+	if after["cacheSavings"] != 0.75 {
+		t.Errorf("cacheSavings = %v, want 0.75", after["cacheSavings"])
+	}
+	for _, want := range []string{"organization_id = 'org'", "source = '" + models.RollupSourceTokenUsage + "'", "team = 'search'"} {
+		if !strings.Contains(rollupSQL, want) {
+			t.Errorf("%s\nshould contain %s", rollupSQL, want)
+		}
+	}
+}
+
+// budgetUsage serves GetAIBudgetUsage for budget, answering each sum query with the value of
+// the first key its SQL contains, or failing it when the value is negative
+func budgetUsage(t *testing.T, budget models.AIBudget, sums map[string]float64) (int, map[string]interface{}) {
+	t.Helper()
+	h := dryRunHandlers(t)
+	h.DB.Callback().Query().After("gorm:query").Register("test:stub_sums", func(tx *gorm.DB) {
+		switch dest := tx.Statement.Dest.(type) {
+		case *models.AIBudget:
+			*dest = budget
+		case *float64:
+			sql := tx.Statement.SQL.String()
+			for key, value := range sums {
+				if !strings.Contains(sql, key) {
+					continue
+				}
+				if value < 0 {
+					tx.AddError(errors.New("connection reset"))
+					return
+				}
+				*dest = value
+				return
+			}
+		}
+	})
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("orgID", "org")
+		return c.Next()
+	})
+	app.Get("/ai/budgets/:id/usage", h.GetAIBudgetUsage)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/ai/budgets/b1/usage", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&body)
+	return resp.StatusCode, body
+}
+
+func TestGetAIBudgetUsage(t *testing.T) {
+	budget := models.AIBudget{ID: "b1", BudgetType: "cost_limit", LimitValue: 100, AlertThresholds: "[50, 80, 100]"}
+	status, body := budgetUsage(t, budget, map[string]float64{
+		`SUM(cost), 0) FROM "token_usages"`: 30,
+		"SUM(hourly_cost * sample_hours)":   25,
+		"source = $2":                       10, // Token and GPU rollups
+	})
+
+	if status != fiber.StatusOK {
+		t.Fatalf("status %d, want 200", status)
+	}
+	if body["currentUsage"] != 30.0+25+10+10 || body["percentUsed"] != 75.0 {
+		t.Errorf("usage %v (%v%%), want 75 (75%%)", body["currentUsage"], body["percentUsed"])
+	}
+	if crossed := body["crossedThresholds"]; !reflect.DeepEqual(crossed, []interface{}{50.0}) {
+		t.Errorf("crossed thresholds = %v, want [50]", crossed)
+	}
+}
+
+func TestGetAIBudgetUsageFailsOnQueryError(t *testing.T) {
+	for _, budgetType := range []string{"token_limit", "cost_limit", "gpu_hours"} {
+		// Only the rollup sums fail, so a failure after a successful sum is caught too
+		status, body := budgetUsage(t, models.AIBudget{ID: "b1", BudgetType: budgetType, LimitValue: 100}, map[string]float64{"source = $2": -1})
+		if status != fiber.StatusInternalServerError {
+			t.Errorf("%s: status %d with %v, want 500 rather than zero usage", budgetType, status, body)
+		}
+	}
+}
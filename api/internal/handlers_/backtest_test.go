@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+	opa "finopsbridge/api/internal/opa_"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+const backtestRego = `package finopsbridge.policies
+
+default allow = true
+
+allow = false {
+	input.monthly_spend > data.policy.config.limit
+}
+
+violation[msg] {
+	input.monthly_spend > data.policy.config.limit
+	msg := sprintf("%v spend %v is over the limit", [input.provider_type, input.monthly_spend])
+}
+`
+
+type backtestResult struct {
+	StartDate     string `json:"startDate"`
+	EndDate       string `json:"endDate"`
+	Evaluated     int    `json:"evaluated"`
+	Skipped       int    `json:"skipped"`
+	Violations    int    `json:"violations"`
+	ViolationDays int    `json:"violationDays"`
+	Timeline      []struct {
+		Date         string  `json:"date"`
+		ProviderID   string  `json:"providerId"`
+		MonthlySpend float64 `json:"monthlySpend"`
+		Message      string  `json:"message"`
+	} `json:"timeline"`
+}
+
+// spendPolicy is a custom policy with a spend limit of 1000
+var spendPolicy = models.Policy{ID: "p1", Type: "custom", Rego: backtestRego, Config: `{"limit": 1000}`}
+
+// backtestRequest seeds policy, the given providers and their spend history, and backtests the
+// policy with body. It returns the response and the SQL of each query by the table it read.
+func backtestRequest(t *testing.T, policy models.Policy, providers []models.CloudProvider, snapshots []models.SpendSnapshot, body string) (*http.Response, map[string]string) {
+	t.Helper()
+	h := dryRunHandlers(t)
+	engine, err := opa.Initialize(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.OPA = engine
+
+	queries := make(map[string]string)
+	h.DB.Callback().Query().After("gorm:query").Register("test:seed_history", func(tx *gorm.DB) {
+		queries[tx.Statement.Table] = tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...)
+		switch dest := tx.Statement.Dest.(type) {
+		case *models.Policy:
+			*dest = policy
+		case *[]models.CloudProvider:
+			*dest = providers
+		case *[]models.SpendSnapshot:
+			*dest = snapshots
+		case *models.SpendSnapshot:
+			// The provider's last snapshot in [from, to), as the previous month's spend is read
+			providerID, from, to := tx.Statement.Vars[0], tx.Statement.Vars[1].(time.Time), tx.Statement.Vars[2].(time.Time)
+			tx.Error = gorm.ErrRecordNotFound
+			for _, snapshot := range snapshots {
+				if snapshot.ProviderID == providerID && !snapshot.Date.Before(from) && snapshot.Date.Before(to) && !snapshot.Date.Before(dest.Date) {
+					*dest, tx.Error = snapshot, nil
+				}
+			}
+		}
+	})
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("orgID", "org")
+		return c.Next()
+	})
+	app.Post("/policies/:id/backtest", h.BacktestPolicy)
+
+	req := httptest.NewRequest("POST", "/policies/p1/backtest", strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, queries
+}
+
+// backtest backtests spendPolicy and decodes its result
+func backtest(t *testing.T, providers []models.CloudProvider, snapshots []models.SpendSnapshot, body string) (backtestResult, map[string]string) {
+	t.Helper()
+	return decodeBacktest(t, spendPolicy, providers, snapshots, body)
+}
+
+func decodeBacktest(t *testing.T, policy models.Policy, providers []models.CloudProvider, snapshots []models.SpendSnapshot, body string) (backtestResult, map[string]string) {
+	t.Helper()
+	resp, queries := backtestRequest(t, policy, providers, snapshots, body)
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("status %d, want 200", resp.StatusCode)
+	}
+	var result backtestResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	return result, queries
+}
+
+func TestBacktestPolicy(t *testing.T) {
+	day := func(d string) time.Time {
+		parsed, _ := time.Parse("2006-01-02", d)
+		return parsed
+	}
+	providers := []models.CloudProvider{{ID: "aws-1", Type: "aws"}, {ID: "gcp-1", Type: "gcp"}}
+	snapshots := []models.SpendSnapshot{
+		{ProviderID: "aws-1", Date: day("2026-02-01"), MonthToDateSpend: 800},
+		{ProviderID: "gcp-1", Date: day("2026-02-01"), MonthToDateSpend: 300},
+		{ProviderID: "aws-1", Date: day("2026-02-02"), MonthToDateSpend: 1200},
+		{ProviderID: "gcp-1", Date: day("2026-02-02"), MonthToDateSpend: 1100},
+		{ProviderID: "aws-1", Date: day("2026-02-03"), MonthToDateSpend: 1500},
+	}
+
+	result, queries := backtest(t, providers, snapshots, `{"startDate": "2026-02-01", "endDate": "2026-02-28"}`)
+
+	if result.Evaluated != 5 || result.Violations != 3 || result.ViolationDays != 2 {
+		t.Errorf("evaluated %d, violations %d on %d days; want 5, 3 on 2 days", result.Evaluated, result.Violations, result.ViolationDays)
+	}
+	var timeline []string
+	for _, entry := range result.Timeline {
+		timeline = append(timeline, entry.Date+" "+entry.ProviderID+" "+entry.Message)
+	}
+	want := []string{
+		"2026-02-02 aws-1 aws spend 1200 is over the limit",
+		"2026-02-02 gcp-1 gcp spend 1100 is over the limit",
+		"2026-02-03 aws-1 aws spend 1500 is over the limit",
+	}
+	if !reflect.DeepEqual(timeline, want) {
+		t.Errorf("timeline = %q, want %q", timeline, want)
+	}
+
+	if sql := queries["spend_snapshots"]; !strings.Contains(sql, "provider_id IN ('aws-1','gcp-1') AND date >= '2026-02-01 00:00:00' AND date <= '2026-02-28 00:00:00'") {
+		t.Errorf("%s\nshould read the providers' history in the range", sql)
+	}
+	if sql := queries["policies"]; !strings.Contains(sql, "id = 'p1' AND organization_id = 'org'") {
+		t.Errorf("%s\nshould scope the policy to the organization", sql)
+	}
+}
+
+func TestBacktestPolicyFiltersByProvider(t *testing.T) {
+	_, queries := backtest(t, []models.CloudProvider{{ID: "gcp-1", Type: "gcp"}}, nil, `{"providerId": "gcp-1", "startDate": "2026-02-01", "endDate": "2026-02-28"}`)
+
+	if sql := queries["cloud_providers"]; !strings.Contains(sql, "organization_id = 'org' AND id = 'gcp-1'") {
+		t.Errorf("%s\nshould only read the requested provider of the organization", sql)
+	}
+	if sql := queries["spend_snapshots"]; !strings.Contains(sql, "provider_id IN ('gcp-1')") {
+		t.Errorf("%s\nshould only read the requested provider's history", sql)
+	}
+}
+
+func TestBacktestPolicyDefaultsToLast90Days(t *testing.T) {
+	providers := []models.CloudProvider{{ID: "aws-1", Type: "aws"}}
+	result, _ := backtest(t, providers, nil, "")
+
+	now := time.Now()
+	if want := now.AddDate(0, 0, -90).Format("2006-01-02"); result.StartDate != want {
+		t.Errorf("startDate = %s, want %s", result.StartDate, want)
+	}
+	if want := now.Format("2006-01-02"); result.EndDate != want {
+		t.Errorf("endDate = %s, want %s", result.EndDate, want)
+	}
+	if result.Evaluated != 0 || result.Violations != 0 || len(result.Timeline) != 0 {
+		t.Errorf("got %+v, want nothing evaluated without history", result)
+	}
+}
+
+const monthOverMonthRego = `package finopsbridge.policies
+
+default allow = true
+
+allow = false {
+	input.month_over_month_percent > data.policy.config.thresholdPercent
+}
+
+violation[msg] {
+	input.month_over_month_percent > data.policy.config.thresholdPercent
+	msg := sprintf("projected %v against %v last month", [round(input.projected_monthly_spend), round(input.previous_month_spend)])
+}
+`
+
+func TestBacktestPolicyMonthOverMonth(t *testing.T) {
+	day := func(d string) time.Time {
+		parsed, _ := time.Parse("2006-01-02", d)
+		return parsed
+	}
+	policy := models.Policy{ID: "p1", Type: "month_over_month_growth", Rego: monthOverMonthRego, Config: `{"thresholdPercent": 20}`}
+	providers := []models.CloudProvider{{ID: "aws-1", Type: "aws"}}
+	snapshots := []models.SpendSnapshot{
+		{ProviderID: "aws-1", Date: day("2026-04-30"), MonthToDateSpend: 1000}, // No March to compare against
+		{ProviderID: "aws-1", Date: day("2026-05-02"), MonthToDateSpend: 100},  // Too early in May
+		{ProviderID: "aws-1", Date: day("2026-05-05"), MonthToDateSpend: 150},  // Projects to 930
+		{ProviderID: "aws-1", Date: day("2026-05-10"), MonthToDateSpend: 400},  // Projects to 1240
+	}
+
+	result, _ := decodeBacktest(t, policy, providers, snapshots, `{"startDate": "2026-04-01", "endDate": "2026-05-31"}`)
+
+	if result.Evaluated != 2 || result.Skipped != 2 || result.Violations != 1 {
+		t.Errorf("evaluated %d, skipped %d, violations %d; want 2, 2, 1", result.Evaluated, result.Skipped, result.Violations)
+	}
+	if len(result.Timeline) != 1 || result.Timeline[0].Date != "2026-05-10" || result.Timeline[0].Message != "projected 1240 against 1000 last month" {
+		t.Errorf("timeline = %+v", result.Timeline)
+	}
+}
+
+func TestBacktestPolicyRejectsPoliciesNotEvaluatedOnSpend(t *testing.T) {
+	for _, policyType := range []string{"require_tags", "llm_token_budget", "budget_hierarchy", "scheduled_start_stop"} {
+		policy := models.Policy{ID: "p1", Type: policyType, Config: `{}`}
+		resp, queries := backtestRequest(t, policy, nil, nil, "")
+		if resp.StatusCode != fiber.StatusUnprocessableEntity {
+			t.Errorf("%s: status %d, want 422", policyType, resp.StatusCode)
+		}
+		if _, ok := queries["spend_snapshots"]; ok {
+			t.Errorf("%s: spend history shouldn't be read", policyType)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	middleware "finopsbridge/api/internal/middleware_"
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// budgetLevels ranks the levels of the budget hierarchy; a child must be below its parent
+var budgetLevels = map[string]int{"organization": 0, "team": 1, "project": 2}
+
+type budgetRequest struct {
+	ParentID *string  `json:"parentId"`
+	Name     *string  `json:"name"`
+	Level    *string  `json:"level"`
+	TagKey   *string  `json:"tagKey"`
+	TagValue *string  `json:"tagValue"`
+	Amount   *float64 `json:"amount"`
+}
+
+// apply copies the fields set in the request onto a budget
+func (r budgetRequest) apply(budget *models.Budget) {
+	if r.ParentID != nil {
+		budget.ParentID = strings.TrimSpace(*r.ParentID)
+	}
+	if r.Name != nil {
+		budget.Name = strings.TrimSpace(*r.Name)
+	}
+	if r.Level != nil {
+		budget.Level = strings.TrimSpace(*r.Level)
+	}
+	if r.TagKey != nil {
+		budget.TagKey = strings.TrimSpace(*r.TagKey)
+	}
+	if r.TagValue != nil {
+		budget.TagValue = strings.TrimSpace(*r.TagValue)
+	}
+	if r.Amount != nil {
+		budget.Amount = *r.Amount
+	}
+}
+
+// validateBudget checks a budget's fields and its place in the organization's hierarchy: the
+// parent must exist, sit at a higher level, and not be the budget itself or one of its children
+func (h *Handlers) validateBudget(orgID string, budget models.Budget) error {
+	if budget.Name == "" {
+		return errors.New("name is required")
+	}
+	rank, ok := budgetLevels[budget.Level]
+	if !ok {
+		return errors.New("level must be organization, team or project")
+	}
+	if budget.Amount <= 0 {
+		return errors.New("amount must be greater than 0")
+	}
+	if (budget.TagKey == "") != (budget.TagValue == "") {
+		return errors.New("tagKey and tagValue must be set together")
+	}
+	if budget.ParentID == "" {
+		return nil
+	}
+
+	var budgets []models.Budget
+	if err := h.DB.Where("organization_id = ?", orgID).Find(&budgets).Error; err != nil {
+		return err
+	}
+	byID := make(map[string]models.Budget, len(budgets))
+	for _, existing := range budgets {
+		byID[existing.ID] = existing
+	}
+
+	parent, ok := byID[budget.ParentID]
+	if !ok {
+		return errors.New("parent budget not found")
+	}
+	if budgetLevels[parent.Level] >= rank {
+		return fmt.Errorf("a %s budget can't be nested under a %s budget", budget.Level, parent.Level)
+	}
+	for ancestor, ok := parent, true; ok; ancestor, ok = byID[ancestor.ParentID] {
+		if ancestor.ID == budget.ID {
+			return errors.New("a budget can't be nested under itself or one of its children")
+		}
+	}
+	return nil
+}
+
+// ListBudgets returns the organization's budgets with the spend attributed at the last
+// enforcement run
+func (h *Handlers) ListBudgets(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+
+	var budgets []models.Budget
+	if err := h.DB.Where("organization_id = ?", orgID).Order("created_at").Find(&budgets).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch budgets",
+		})
+	}
+
+	result := make([]map[string]interface{}, 0, len(budgets))
+	for _, budget := range budgets {
+		percentUsed := 0.0
+		if budget.Amount > 0 {
+			percentUsed = budget.CurrentSpend / budget.Amount * 100
+		}
+		result = append(result, map[string]interface{}{
+			"id":           budget.ID,
+			"parentId":     budget.ParentID,
+			"name":         budget.Name,
+			"level":        budget.Level,
+			"tagKey":       budget.TagKey,
+			"tagValue":     budget.TagValue,
+			"amount":       budget.Amount,
+			"currency":     h.Config.ReportingCurrency,
+			"currentSpend": budget.CurrentSpend,
+			"percentUsed":  percentUsed,
+			"evaluatedAt":  budget.EvaluatedAt,
+		})
+	}
+
+	return c.JSON(result)
+}
+
+// CreateBudget adds a budget to the organization's hierarchy
+func (h *Handlers) CreateBudget(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+
+	var req budgetRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	budget := models.Budget{OrganizationID: orgID}
+	req.apply(&budget)
+	if err := h.validateBudget(orgID, budget); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	if err := h.DB.Create(&budget).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create budget",
+		})
+	}
+
+	h.logActivity(c.UserContext(), orgID, "budget_created", fmt.Sprintf("Created %s budget '%s'", budget.Level, budget.Name), map[string]interface{}{
+		"budgetId": budget.ID,
+		"userId":   middleware.GetUserID(c),
+	})
+
+	return c.Status(fiber.StatusCreated).JSON(budget)
+}
+
+// UpdateBudget changes a budget's fields or moves it within the hierarchy
+func (h *Handlers) UpdateBudget(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+	id := c.Params("id")
+
+	var req budgetRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	var budget models.Budget
+	if err := h.DB.Where("id = ? AND organization_id = ?", id, orgID).First(&budget).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Budget not found",
+		})
+	}
+
+	req.apply(&budget)
+	if err := h.validateBudget(orgID, budget); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	// Children must stay below their parent's level
+	if req.Level != nil {
+		var children []models.Budget
+		h.DB.Where("organization_id = ? AND parent_id = ?", orgID, budget.ID).Find(&children)
+		for _, child := range children {
+			if budgetLevels[child.Level] <= budgetLevels[budget.Level] {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": fmt.Sprintf("child budget '%s' is a %s budget and can't be nested under a %s budget", child.Name, child.Level, budget.Level),
+				})
+			}
+		}
+	}
+
+	if err := h.DB.Save(&budget).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update budget",
+		})
+	}
+
+	h.logActivity(c.UserContext(), orgID, "budget_updated", fmt.Sprintf("Budget '%s' was updated", budget.Name), map[string]interface{}{
+		"budgetId": budget.ID,
+		"userId":   middleware.GetUserID(c),
+	})
+
+	return c.JSON(budget)
+}
+
+// DeleteBudget removes a budget without children from the hierarchy
+func (h *Handlers) DeleteBudget(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+	id := c.Params("id")
+
+	var budget models.Budget
+	if err := h.DB.Where("id = ? AND organization_id = ?", id, orgID).First(&budget).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Budget not found",
+		})
+	}
+
+	var children int64
+	h.DB.Model(&models.Budget{}).Where("organization_id = ? AND parent_id = ?", orgID, budget.ID).Count(&children)
+	if children > 0 {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Budget has child budgets; move or delete them first",
+		})
+	}
+
+	if err := h.DB.Delete(&budget).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete budget",
+		})
+	}
+
+	h.logActivity(c.UserContext(), orgID, "budget_deleted", fmt.Sprintf("Deleted %s budget '%s'", budget.Level, budget.Name), map[string]interface{}{
+		"budgetId": budget.ID,
+		"userId":   middleware.GetUserID(c),
+	})
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"testing"
+
+	models "finopsbridge/api/internal/models_"
+)
+
+func TestBudgetRequestApply(t *testing.T) {
+	name, tagKey, amount := "  Platform ", "team", 500.0
+	budget := models.Budget{Name: "Old", Level: "team", TagValue: "platform", Amount: 100}
+
+	budgetRequest{Name: &name, TagKey: &tagKey, Amount: &amount}.apply(&budget)
+
+	if budget.Name != "Platform" || budget.TagKey != "team" || budget.Amount != 500 {
+		t.Errorf("got %+v", budget)
+	}
+	if budget.Level != "team" || budget.TagValue != "platform" {
+		t.Errorf("fields not in the request should be kept, got %+v", budget)
+	}
+}
+
+func TestValidateBudget(t *testing.T) {
+	h := dryRunHandlers(t)
+
+	tests := []struct {
+		name   string
+		budget models.Budget
+		ok     bool
+	}{
+		{"valid root", models.Budget{Name: "Acme", Level: "organization", Amount: 1000}, true},
+		{"tagged", models.Budget{Name: "Data", Level: "team", Amount: 100, TagKey: "team", TagValue: "data"}, true},
+		{"no name", models.Budget{Level: "team", Amount: 100}, false},
+		{"unknown level", models.Budget{Name: "Acme", Level: "division", Amount: 100}, false},
+		{"no amount", models.Budget{Name: "Acme", Level: "organization"}, false},
+		{"tag key without value", models.Budget{Name: "Data", Level: "team", Amount: 100, TagKey: "team"}, false},
+		{"missing parent", models.Budget{Name: "Data", Level: "team", Amount: 100, ParentID: "deleted"}, false},
+	}
+
+	for _, tt := range tests {
+		err := h.validateBudget("org", tt.budget)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: got %v, want ok=%v", tt.name, err, tt.ok)
+		}
+	}
+}
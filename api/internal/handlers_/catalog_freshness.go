@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// catalogStaleBefore returns the time before which model catalog prices are considered stale,
+// or the zero time when the check is disabled
+func (h *Handlers) catalogStaleBefore(now time.Time) time.Time {
+	if h.Config.ModelCatalogStaleDays <= 0 {
+		return time.Time{}
+	}
+	return now.AddDate(0, 0, -h.Config.ModelCatalogStaleDays)
+}
+
+// catalogPricesUpdatedAt returns when an entry's prices were last updated. Entries from before
+// prices were tracked separately fall back to the row's UpdatedAt.
+func catalogPricesUpdatedAt(entry models.AIModelCatalog) time.Time {
+	if entry.PricesUpdatedAt != nil {
+		return *entry.PricesUpdatedAt
+	}
+	return entry.UpdatedAt
+}
+
+// catalogPriceWarning describes a stale catalog entry for cost responses, or returns "" when
+// its prices are within the staleness window
+func (h *Handlers) catalogPriceWarning(entry models.AIModelCatalog, now time.Time) string {
+	staleBefore := h.catalogStaleBefore(now)
+	updatedAt := catalogPricesUpdatedAt(entry)
+	if staleBefore.IsZero() || !updatedAt.Before(staleBefore) {
+		return ""
+	}
+	return "Prices for " + entry.Provider + "/" + entry.ModelName + " were last updated " +
+		updatedAt.Format("2006-01-02") + "; the computed cost may be out of date"
+}
+
+// Health reports that the API is up, with sub-checks. The model catalog check lists available
+// entries whose prices are older than MODEL_CATALOG_STALE_DAYS; stale prices don't affect the
+// overall status, but failing to query the database does.
+func (h *Handlers) Health(c *fiber.Ctx) error {
+	status := "ok"
+	catalog := fiber.Map{"status": "ok"}
+
+	if staleBefore := h.catalogStaleBefore(time.Now()); !staleBefore.IsZero() {
+		var stale []models.AIModelCatalog
+		if err := h.DB.Where("is_available = ?", true).
+			Where("COALESCE(prices_updated_at, updated_at) < ?", staleBefore).
+			Order("provider, model_name").
+			Find(&stale).Error; err != nil {
+			status = "error"
+			catalog = fiber.Map{"status": "error", "error": "Failed to check model catalog"}
+		} else if len(stale) > 0 {
+			entries := make([]fiber.Map, 0, len(stale))
+			for _, entry := range stale {
+				entries = append(entries, fiber.Map{
+					"provider":        entry.Provider,
+					"modelName":       entry.ModelName,
+					"source":          entry.Source,
+					"pricesUpdatedAt": catalogPricesUpdatedAt(entry),
+				})
+			}
+			catalog = fiber.Map{
+				"status":       "stale",
+				"staleAfter":   h.Config.ModelCatalogStaleDays,
+				"staleEntries": entries,
+			}
+		}
+	}
+
+	code := fiber.StatusOK
+	if status != "ok" {
+		code = fiber.StatusServiceUnavailable
+	}
+	return c.Status(code).JSON(fiber.Map{
+		"status": status,
+		"checks": fiber.Map{
+			"modelCatalog": catalog,
+		},
+	})
+}
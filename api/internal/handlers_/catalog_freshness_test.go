@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestCatalogPriceWarning(t *testing.T) {
+	h := dryRunHandlers(t)
+	h.Config.ModelCatalogStaleDays = 30
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	recent := now.AddDate(0, 0, -5)
+	old := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		entry models.AIModelCatalog
+		want  string
+	}{
+		{"recent prices", models.AIModelCatalog{Provider: "openai", ModelName: "gpt-4o", PricesUpdatedAt: &recent, UpdatedAt: old}, ""},
+		{"stale prices", models.AIModelCatalog{Provider: "openai", ModelName: "gpt-4o", PricesUpdatedAt: &old, UpdatedAt: recent},
+			"Prices for openai/gpt-4o were last updated 2026-01-15; the computed cost may be out of date"},
+		{"falls back to UpdatedAt", models.AIModelCatalog{Provider: "anthropic", ModelName: "claude", UpdatedAt: old},
+			"Prices for anthropic/claude were last updated 2026-01-15; the computed cost may be out of date"},
+	}
+	for _, tt := range tests {
+		if got := h.catalogPriceWarning(tt.entry, now); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	h.Config.ModelCatalogStaleDays = 0
+	if got := h.catalogPriceWarning(models.AIModelCatalog{UpdatedAt: old}, now); got != "" {
+		t.Errorf("a disabled check should never warn, got %q", got)
+	}
+}
+
+func TestHealth(t *testing.T) {
+	h := dryRunHandlers(t)
+	h.Config.ModelCatalogStaleDays = 30
+	app := fiber.New()
+	app.Get("/health", h.Health)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/health", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Errorf("status %d, want 200 with no stale entries", resp.StatusCode)
+	}
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	middleware "finopsbridge/api/internal/middleware_"
+	reports "finopsbridge/api/internal/reports_"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetComplianceReport renders the organization's policies, compliance score, open violations
+// and remediation stats as a shareable report: a PDF download (default, ?format=pdf) or an
+// HTML page (?format=html)
+func (h *Handlers) GetComplianceReport(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+
+	format := c.Query("format", "pdf")
+	if format != "pdf" && format != "html" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "format must be pdf or html",
+		})
+	}
+
+	now := time.Now()
+	report, err := reports.LoadComplianceReport(h.DB.WithContext(c.UserContext()), orgID, now)
+	if err != nil {
+		fmt.Printf("Error assembling compliance report for %s: %v\n", orgID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to assemble compliance report",
+		})
+	}
+
+	// Rendered in full before anything is sent, so a failure can still return an error
+	var buf bytes.Buffer
+	if format == "html" {
+		err = reports.RenderHTML(&buf, report)
+	} else {
+		err = reports.RenderPDF(&buf, report)
+	}
+	if err != nil {
+		fmt.Printf("Error rendering compliance report for %s: %v\n", orgID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to render compliance report",
+		})
+	}
+
+	if format == "html" {
+		c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	} else {
+		filename := fmt.Sprintf("compliance-report-%s.pdf", now.Format("2006-01-02"))
+		c.Set(fiber.HeaderContentType, "application/pdf")
+		c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+	}
+	return c.Send(buf.Bytes())
+}
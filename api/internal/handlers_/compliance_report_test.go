@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestGetComplianceReportRejectsUnknownFormat(t *testing.T) {
+	app := fiber.New()
+	app.Get("/reports/compliance", dryRunHandlers(t).GetComplianceReport)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/reports/compliance?format=csv", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusBadRequest {
+		t.Errorf("status %d, want 400", resp.StatusCode)
+	}
+}
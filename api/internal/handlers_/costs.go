@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	cloud "finopsbridge/api/internal/cloud_"
+	middleware "finopsbridge/api/internal/middleware_"
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetCloudProviderCostBreakdown returns a provider's month-to-date cost grouped by service (or SKU)
+func (h *Handlers) GetCloudProviderCostBreakdown(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+	id := c.Params("id")
+
+	var provider models.CloudProvider
+	if err := h.DB.Where("id = ? AND organization_id = ?", id, orgID).First(&provider).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Cloud provider not found",
+		})
+	}
+
+	groupBy := c.Query("groupBy", "service")
+
+	items, err := cloud.FetchCostBreakdown(c.UserContext(), provider, h.Config, groupBy)
+	if errors.Is(err, cloud.ErrBreakdownNotSupported) {
+		return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{
+			"error": "Cost breakdown is not supported for " + provider.Type + " providers",
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+			"error": "Failed to fetch cost breakdown: " + err.Error(),
+		})
+	}
+
+	total := 0.0
+	for _, item := range items {
+		total += item.Cost
+	}
+
+	return c.JSON(fiber.Map{
+		"providerId": provider.ID,
+		"groupBy":    groupBy,
+		"total":      total,
+		"items":      items,
+	})
+}
+
+// RefreshCloudProviderBilling re-fetches a provider's billing data, bypassing the billing cache,
+// and updates its month-to-date spend
+func (h *Handlers) RefreshCloudProviderBilling(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+	id := c.Params("id")
+
+	var provider models.CloudProvider
+	if err := h.DB.Where("id = ? AND organization_id = ?", id, orgID).First(&provider).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Cloud provider not found",
+		})
+	}
+
+	billingData, err := cloud.FetchBilling(c.UserContext(), provider, h.Config, true)
+	if errors.Is(err, cloud.ErrBillingNotSupported) {
+		return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{
+			"error": "Billing is not supported for " + provider.Type + " providers",
+		})
+	}
+	// Decided before the sync update moves the provider's last successful sync
+	now := time.Now()
+	spend, store := cloud.MonthlySpend(billingData, provider, now)
+	h.DB.Model(&provider).Updates(cloud.SyncUpdates(billingData, err, now))
+	if err != nil {
+		return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+			"error": "Failed to fetch billing data: " + err.Error(),
+		})
+	}
+
+	if store {
+		provider.MonthlySpend = spend
+		if err := h.DB.Model(&provider).Update("monthly_spend", spend).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to update cloud provider",
+			})
+		}
+		h.InvalidateDashboardStats(orgID)
+	}
+
+	return c.JSON(fiber.Map{
+		"providerId":   provider.ID,
+		"monthlySpend": provider.MonthlySpend,
+		"billing":      billingData,
+	})
+}
+
+// GetDashboardCostBreakdown combines the service-level breakdown of every connected provider into
+// common cost categories, converted to the reporting currency. Providers without breakdown
+// support or whose fetch fails are listed with a status instead of failing the request.
+func (h *Handlers) GetDashboardCostBreakdown(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+
+	var providers []models.CloudProvider
+	if err := h.DB.Where("organization_id = ?", orgID).Find(&providers).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch cloud providers",
+		})
+	}
+
+	type categoryCost struct {
+		Service  string  `json:"service"`
+		Category string  `json:"category"`
+		Cost     float64 `json:"cost"` // In the reporting currency
+		Original float64 `json:"originalCost"`
+		Currency string  `json:"currency"`
+	}
+
+	categories := make(map[string]float64)
+	total := 0.0
+	unconverted := make(map[string]bool)
+	details := make([]fiber.Map, 0, len(providers))
+
+	for _, provider := range providers {
+		detail := fiber.Map{
+			"providerId": provider.ID,
+			"name":       provider.Name,
+			"type":       provider.Type,
+		}
+
+		items, err := cloud.FetchCostBreakdown(c.UserContext(), provider, h.Config, "service")
+		if errors.Is(err, cloud.ErrBreakdownNotSupported) {
+			detail["status"] = "unsupported"
+			details = append(details, detail)
+			continue
+		}
+		if err != nil {
+			detail["status"] = "error"
+			detail["error"] = err.Error()
+			details = append(details, detail)
+			continue
+		}
+
+		providerCategories := make(map[string]float64)
+		providerTotal := 0.0
+		services := make([]categoryCost, 0, len(items))
+		for _, item := range items {
+			category := cloud.ServiceCategory(item.Key)
+			converted, ok := cloud.ConvertCurrency(item.Cost, item.Currency, h.Config)
+			if !ok {
+				// Left out of totals rather than summed in the wrong currency
+				unconverted[item.Currency] = true
+				continue
+			}
+
+			services = append(services, categoryCost{
+				Service:  item.Key,
+				Category: category,
+				Cost:     converted,
+				Original: item.Cost,
+				Currency: item.Currency,
+			})
+			providerCategories[category] += converted
+			providerTotal += converted
+			categories[category] += converted
+			total += converted
+		}
+
+		detail["status"] = "ok"
+		detail["total"] = providerTotal
+		detail["categories"] = providerCategories
+		detail["services"] = services
+		details = append(details, detail)
+	}
+
+	return c.JSON(fiber.Map{
+		"currency":              h.Config.ReportingCurrency,
+		"total":                 total,
+		"categories":            categories,
+		"providers":             details,
+		"unconvertedCurrencies": sortedCurrencies(unconverted),
+	})
+}
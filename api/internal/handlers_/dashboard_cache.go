@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type dashboardEntry struct {
+	stats     fiber.Map
+	expiresAt time.Time
+}
+
+// dashboardCache holds computed dashboard stats per organization and query, so frequent
+// polling doesn't rerun every aggregate. Entries expire after a short TTL and are dropped
+// when the organization's policies, providers or violations change.
+type dashboardCache struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	entries map[string]dashboardEntry
+}
+
+func newDashboardCache() *dashboardCache {
+	return &dashboardCache{
+		now:     time.Now,
+		entries: make(map[string]dashboardEntry),
+	}
+}
+
+// dashboardKey identifies cached stats by organization and the query parameters that shape them
+func dashboardKey(orgID, startDate, endDate, providerID string) string {
+	return orgID + "|" + startDate + "|" + endDate + "|" + providerID
+}
+
+func (dc *dashboardCache) get(key string) (fiber.Map, bool) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	entry, ok := dc.entries[key]
+	if !ok || !dc.now().Before(entry.expiresAt) {
+		return nil, false
+	}
+	return entry.stats, true
+}
+
+func (dc *dashboardCache) set(key string, stats fiber.Map, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	dc.entries[key] = dashboardEntry{stats: stats, expiresAt: dc.now().Add(ttl)}
+}
+
+func (dc *dashboardCache) invalidate(orgID string) {
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+
+	for key := range dc.entries {
+		if strings.HasPrefix(key, orgID+"|") {
+			delete(dc.entries, key)
+		}
+	}
+}
+
+// InvalidateDashboardStats drops an organization's cached dashboard stats. The enforcement
+// worker calls it after runs that may have changed spend or violations.
+func (h *Handlers) InvalidateDashboardStats(orgID string) {
+	h.dashboard.invalidate(orgID)
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestDashboardCacheExpires(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	dc := newDashboardCache()
+	dc.now = func() time.Time { return now }
+	key := dashboardKey("org", "", "", "")
+
+	dc.set(key, fiber.Map{"totalSpend": 100.0}, time.Minute)
+	if stats, ok := dc.get(key); !ok || stats["totalSpend"] != 100.0 {
+		t.Errorf("got %v, %v; want the cached stats", stats, ok)
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok := dc.get(key); ok {
+		t.Error("stats should expire after the TTL")
+	}
+
+	dc.set(key, fiber.Map{}, 0)
+	if _, ok := dc.get(key); ok {
+		t.Error("a zero TTL disables caching")
+	}
+}
+
+func TestDashboardCacheInvalidatesOneOrg(t *testing.T) {
+	dc := newDashboardCache()
+	dc.set(dashboardKey("org", "", "", ""), fiber.Map{}, time.Minute)
+	dc.set(dashboardKey("org", "2026-01-01", "2026-01-31", "p1"), fiber.Map{}, time.Minute)
+	dc.set(dashboardKey("org-2", "", "", ""), fiber.Map{}, time.Minute)
+
+	dc.invalidate("org")
+
+	if _, ok := dc.get(dashboardKey("org", "2026-01-01", "2026-01-31", "p1")); ok {
+		t.Error("every query of the org should be dropped")
+	}
+	// "org" is a prefix of "org-2" but a different organization
+	if _, ok := dc.get(dashboardKey("org-2", "", "", "")); !ok {
+		t.Error("other organizations' stats should be kept")
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	middleware "finopsbridge/api/internal/middleware_"
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ListDecisions returns the organization's decision log, newest first. Filters: policyId,
+// decision (allow, deny, error), since and until (RFC 3339), and limit (default 100, max 1000).
+// The log is empty unless DECISION_LOG_ENABLED is set.
+func (h *Handlers) ListDecisions(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+	if orgID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Organization ID required",
+		})
+	}
+
+	query := h.DB.Where("organization_id = ?", orgID)
+	if policyID := c.Query("policyId"); policyID != "" {
+		query = query.Where("policy_id = ?", policyID)
+	}
+	if decision := c.Query("decision"); decision != "" {
+		query = query.Where("decision = ?", decision)
+	}
+	for param, clause := range map[string]string{"since": "created_at >= ?", "until": "created_at < ?"} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		at, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": param + " must be an RFC 3339 timestamp",
+			})
+		}
+		query = query.Where(clause, at)
+	}
+
+	limit := 100
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "limit must be a positive integer",
+			})
+		}
+		limit = min(parsed, 1000)
+	}
+
+	var decisions []models.DecisionLog
+	if err := query.Order("created_at DESC").Limit(limit).Find(&decisions).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch decisions",
+		})
+	}
+
+	result := make([]fiber.Map, 0, len(decisions))
+	for _, decision := range decisions {
+		result = append(result, fiber.Map{
+			"id":         decision.ID,
+			"policyId":   decision.PolicyID,
+			"resourceId": decision.ResourceID,
+			"inputHash":  decision.InputHash,
+			"decision":   decision.Decision,
+			"error":      decision.Error,
+			"createdAt":  decision.CreatedAt,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"enabled":   h.Config.DecisionLogEnabled,
+		"decisions": result,
+	})
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"strings"
+
+	middleware "finopsbridge/api/internal/middleware_"
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// digestCadences are the cadences the digest worker sends on
+var digestCadences = map[string]bool{"daily": true, "weekly": true, "monthly": true}
+
+func digestSettings(org *models.Organization) fiber.Map {
+	return fiber.Map{
+		"cadence":    org.DigestCadence,
+		"webhookId":  org.DigestWebhookID,
+		"lastSentAt": org.DigestLastSentAt,
+	}
+}
+
+// GetDigestSettings returns the organization's spend digest cadence and destination
+func (h *Handlers) GetDigestSettings(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+
+	org, err := h.getOrganization(orgID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to load organization",
+		})
+	}
+
+	return c.JSON(digestSettings(org))
+}
+
+// UpdateDigestSettings sets how often the organization's spend digest is sent and to which
+// webhook. An empty cadence turns the digest off; an empty webhookId sends it to every webhook
+// subscribed to the digest event.
+func (h *Handlers) UpdateDigestSettings(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+
+	var req struct {
+		Cadence   string `json:"cadence"`
+		WebhookID string `json:"webhookId"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	req.Cadence = strings.ToLower(strings.TrimSpace(req.Cadence))
+	if req.Cadence != "" && !digestCadences[req.Cadence] {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "cadence must be daily, weekly or monthly, or empty to turn the digest off",
+		})
+	}
+
+	if req.WebhookID != "" {
+		var webhook models.Webhook
+		if err := h.DB.Where("id = ? AND organization_id = ?", req.WebhookID, orgID).First(&webhook).Error; err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Webhook not found",
+			})
+		}
+	}
+
+	org, err := h.getOrganization(orgID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to load organization",
+		})
+	}
+
+	if err := h.DB.Model(org).Updates(map[string]interface{}{
+		"digest_cadence":    req.Cadence,
+		"digest_webhook_id": req.WebhookID,
+	}).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update digest settings",
+		})
+	}
+	org.DigestCadence = req.Cadence
+	org.DigestWebhookID = req.WebhookID
+
+	message := "Spend digest was turned off"
+	if req.Cadence != "" {
+		message = "Spend digest set to " + req.Cadence
+	}
+	h.logActivity(c.UserContext(), orgID, "digest_updated", message, map[string]interface{}{
+		"userId": middleware.GetUserID(c),
+	})
+
+	return c.JSON(digestSettings(org))
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"encoding/json"
+	"time"
+
+	middleware "finopsbridge/api/internal/middleware_"
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// PauseEnforcement turns on the org-wide kill switch so the worker stops taking remediation actions
+func (h *Handlers) PauseEnforcement(c *fiber.Ctx) error {
+	return h.setEnforcementPaused(c, true)
+}
+
+// ResumeEnforcement turns the org-wide kill switch back off
+func (h *Handlers) ResumeEnforcement(c *fiber.Ctx) error {
+	return h.setEnforcementPaused(c, false)
+}
+
+func (h *Handlers) setEnforcementPaused(c *fiber.Ctx, paused bool) error {
+	orgID := middleware.GetOrgID(c)
+	if orgID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Organization ID required",
+		})
+	}
+
+	org, err := h.getOrganization(orgID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to load organization",
+		})
+	}
+
+	userID := middleware.GetUserID(c)
+	org.EnforcementPaused = paused
+	if paused {
+		now := time.Now()
+		org.EnforcementPausedAt = &now
+		org.EnforcementPausedBy = userID
+	} else {
+		org.EnforcementPausedAt = nil
+		org.EnforcementPausedBy = ""
+	}
+
+	if err := h.DB.Model(org).
+		Select("enforcement_paused", "enforcement_paused_at", "enforcement_paused_by").
+		Updates(org).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update enforcement status",
+		})
+	}
+
+	if paused {
+		h.logActivity(c.UserContext(), orgID, "enforcement_paused", "Enforcement was paused for the organization", map[string]interface{}{
+			"userId": userID,
+		})
+	} else {
+		h.logActivity(c.UserContext(), orgID, "enforcement_resumed", "Enforcement was resumed for the organization", map[string]interface{}{
+			"userId": userID,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"enforcementPaused":   org.EnforcementPaused,
+		"enforcementPausedAt": org.EnforcementPausedAt,
+		"enforcementPausedBy": org.EnforcementPausedBy,
+	})
+}
+
+// ListEnforcementRuns returns recent worker runs for the organization, including skipped and
+// degraded providers
+func (h *Handlers) ListEnforcementRuns(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+	if orgID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Organization ID required",
+		})
+	}
+
+	var runs []models.EnforcementRun
+	if err := h.DB.Where("organization_id = ?", orgID).
+		Order("started_at DESC").
+		Limit(50).
+		Find(&runs).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch enforcement runs",
+		})
+	}
+
+	result := make([]map[string]interface{}, 0, len(runs))
+	for _, run := range runs {
+		skipped := []models.SkippedProvider{}
+		if run.Skipped != "" {
+			json.Unmarshal([]byte(run.Skipped), &skipped)
+		}
+		degraded := []models.SkippedProvider{}
+		if run.DegradedProviders != "" {
+			json.Unmarshal([]byte(run.DegradedProviders), &degraded)
+		}
+		policyErrors := []models.PolicyError{}
+		if run.PolicyErrors != "" {
+			json.Unmarshal([]byte(run.PolicyErrors), &policyErrors)
+		}
+
+		result = append(result, map[string]interface{}{
+			"id":                 run.ID,
+			"startedAt":          run.StartedAt,
+			"completedAt":        run.CompletedAt,
+			"providersProcessed": run.ProvidersProcessed,
+			"skipped":            skipped,
+			"degraded":           run.Degraded,
+			"degradedProviders":  degraded,
+			"actionsSucceeded":   run.ActionsSucceeded,
+			"actionsFailed":      run.ActionsFailed,
+			"policyErrors":       policyErrors,
+			"requestId":          run.RequestID,
+		})
+	}
+
+	return c.JSON(result)
+}
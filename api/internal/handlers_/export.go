@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	middleware "finopsbridge/api/internal/middleware_"
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// exportFlushEvery is how many rows are written between flushes of a streaming export
+const exportFlushEvery = 500
+
+// ExportViolations streams the organization's violations as CSV (default) or NDJSON
+// (?format=ndjson). Rows are read with a database cursor and flushed periodically,
+// so memory stays flat regardless of how many violations an org has.
+func (h *Handlers) ExportViolations(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+	if orgID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Organization ID required",
+		})
+	}
+
+	format := c.Query("format", "csv")
+	if format != "csv" && format != "ndjson" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "format must be csv or ndjson",
+		})
+	}
+
+	query := h.DB.Model(&models.PolicyViolation{}).
+		Joins("JOIN policies ON policies.id = policy_violations.policy_id").
+		Where("policies.organization_id = ?", orgID).
+		Order("policy_violations.created_at DESC")
+	if status := c.Query("status"); status != "" {
+		query = query.Where("policy_violations.status = ?", status)
+	}
+
+	filename := fmt.Sprintf("violations-%s.%s", time.Now().Format("2006-01-02"), format)
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+	if format == "ndjson" {
+		c.Set(fiber.HeaderContentType, "application/x-ndjson")
+	} else {
+		c.Set(fiber.HeaderContentType, "text/csv")
+	}
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := writeViolationExport(h.DB, query, format, w); err != nil {
+			fmt.Printf("Error streaming violation export for %s: %v\n", orgID, err)
+		}
+	})
+
+	return nil
+}
+
+// writeViolationExport writes one line per violation returned by query, flushing every
+// exportFlushEvery rows
+func writeViolationExport(db *gorm.DB, query *gorm.DB, format string, w *bufio.Writer) error {
+	rows, err := query.Select("policy_violations.*").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	csvWriter := csv.NewWriter(w)
+	if format == "csv" {
+		csvWriter.Write([]string{"id", "policyId", "resourceId", "resourceType", "cloudProvider", "message", "severity", "status", "createdAt", "remediatedAt"})
+	}
+
+	count := 0
+	for rows.Next() {
+		var violation models.PolicyViolation
+		if err := db.ScanRows(rows, &violation); err != nil {
+			return err
+		}
+
+		if format == "ndjson" {
+			line, err := json.Marshal(violation)
+			if err != nil {
+				return err
+			}
+			w.Write(line)
+			w.WriteByte('\n')
+		} else {
+			remediatedAt := ""
+			if violation.RemediatedAt != nil {
+				remediatedAt = violation.RemediatedAt.Format(time.RFC3339)
+			}
+			csvWriter.Write([]string{
+				violation.ID,
+				violation.PolicyID,
+				violation.ResourceID,
+				violation.ResourceType,
+				violation.CloudProvider,
+				violation.Message,
+				violation.Severity,
+				violation.Status,
+				violation.CreatedAt.Format(time.RFC3339),
+				remediatedAt,
+			})
+		}
+
+		count++
+		if count%exportFlushEvery == 0 {
+			csvWriter.Flush()
+			if err := w.Flush(); err != nil {
+				// Client went away
+				return err
+			}
+		}
+	}
+
+	csvWriter.Flush()
+	if err := csvWriter.Error(); err != nil {
+		return err
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return w.Flush()
+}
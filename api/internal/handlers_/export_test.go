@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// violationRows is a database/sql driver whose every query returns the same violations, so
+// an export can be streamed without a server
+type violationRows [][]driver.Value
+
+var violationColumns = []string{"id", "policy_id", "resource_id", "resource_type", "cloud_provider", "message", "severity", "status", "created_at", "remediated_at"}
+
+func (v violationRows) Open(string) (driver.Conn, error) { return violationConn{v}, nil }
+
+type violationConn struct{ rows violationRows }
+
+func (c violationConn) Prepare(string) (driver.Stmt, error) { return violationStmt{c.rows}, nil }
+func (c violationConn) Close() error                        { return nil }
+func (c violationConn) Begin() (driver.Tx, error)           { return nil, driver.ErrSkip }
+
+type violationStmt struct{ rows violationRows }
+
+func (s violationStmt) Close() error                               { return nil }
+func (s violationStmt) NumInput() int                              { return -1 }
+func (s violationStmt) Exec([]driver.Value) (driver.Result, error) { return nil, driver.ErrSkip }
+func (s violationStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &violationCursor{rows: s.rows}, nil
+}
+
+type violationCursor struct {
+	rows violationRows
+	next int
+}
+
+func (c *violationCursor) Columns() []string { return violationColumns }
+func (c *violationCursor) Close() error      { return nil }
+func (c *violationCursor) Next(dest []driver.Value) error {
+	if c.next == len(c.rows) {
+		return io.EOF
+	}
+	copy(dest, c.rows[c.next])
+	c.next++
+	return nil
+}
+
+func init() {
+	created := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	remediated := created.Add(time.Hour)
+	sql.Register("violation-rows", violationRows{
+		{"v1", "p1", "i-1", "ec2", "aws", "idle, for 3 days", "high", "open", created, nil},
+		{"v2", "p1", "i-2", "ec2", "aws", "idle", "low", "remediated", created, remediated},
+	})
+}
+
+func violationExport(t *testing.T, format string) string {
+	t.Helper()
+	conn, err := sql.Open("violation-rows", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: conn}), &gorm.Config{DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	w := bufio.NewWriter(&out)
+	if err := writeViolationExport(db, db.Model(&models.PolicyViolation{}), format, w); err != nil {
+		t.Fatal(err)
+	}
+	return out.String()
+}
+
+func TestWriteViolationExportCSV(t *testing.T) {
+	want := "id,policyId,resourceId,resourceType,cloudProvider,message,severity,status,createdAt,remediatedAt\n" +
+		"v1,p1,i-1,ec2,aws,\"idle, for 3 days\",high,open,2026-03-02T09:00:00Z,\n" +
+		"v2,p1,i-2,ec2,aws,idle,low,remediated,2026-03-02T09:00:00Z,2026-03-02T10:00:00Z\n"
+	if got := violationExport(t, "csv"); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteViolationExportNDJSON(t *testing.T) {
+	lines := strings.Split(strings.TrimSuffix(violationExport(t, "ndjson"), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want one per violation", len(lines))
+	}
+	var violation models.PolicyViolation
+	if err := json.Unmarshal([]byte(lines[1]), &violation); err != nil {
+		t.Fatal(err)
+	}
+	if violation.ID != "v2" || violation.Status != "remediated" || violation.RemediatedAt == nil {
+		t.Errorf("got %+v", violation)
+	}
+}
+
+func TestExportViolationsRejectsUnknownFormat(t *testing.T) {
+	h := dryRunHandlers(t)
+	app := fiber.New()
+	app.Get("/violations/export", func(c *fiber.Ctx) error {
+		c.Locals("orgID", "org")
+		return h.ExportViolations(c)
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/violations/export?format=xlsx", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusBadRequest {
+		t.Errorf("status %d, want 400", resp.StatusCode)
+	}
+}
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"sort"
+
+	models "finopsbridge/api/internal/models_"
+)
+
+// unknownGPUType groups GPU metrics reported without a GPU type
+const unknownGPUType = "unknown"
+
+// GPUTypeCost is the cost, utilization and idle waste of one GPU family over a period
+type GPUTypeCost struct {
+	GPUType            string  `json:"gpuType"`
+	TotalCost          float64 `json:"totalCost"`
+	TotalGPUHours      float64 `json:"totalGPUHours"`
+	AverageUtilization float64 `json:"averageUtilization"`
+	IdleGPUHours       float64 `json:"idleGPUHours"`
+	IdleCostWaste      float64 `json:"idleCostWaste"`
+	UniqueInstances    int     `json:"uniqueInstances"`
+}
+
+// gpuCostByType breaks GPU metrics and their rollups down per GPU type, most expensive first.
+// Each type's figures come from computeGPUStats and GPUStats.addRollup, so rows are weighted by
+// the time they represent and the types add up to the totals computed the same way.
+func gpuCostByType(metrics []models.GPUMetrics, rollups []models.UsageRollup) []GPUTypeCost {
+	byType := make(map[string][]models.GPUMetrics)
+	rollupsByType := make(map[string][]models.UsageRollup)
+	for _, m := range metrics {
+		gpuType := gpuTypeOrUnknown(m.GPUType)
+		byType[gpuType] = append(byType[gpuType], m)
+	}
+	for _, r := range rollups {
+		gpuType := gpuTypeOrUnknown(r.GPUType)
+		rollupsByType[gpuType] = append(rollupsByType[gpuType], r)
+		if _, ok := byType[gpuType]; !ok {
+			byType[gpuType] = nil
+		}
+	}
+
+	rows := make([]GPUTypeCost, 0, len(byType))
+	for gpuType, typeMetrics := range byType {
+		stats := computeGPUStats(typeMetrics)
+		for _, r := range rollupsByType[gpuType] {
+			stats.addRollup(r)
+		}
+		rows = append(rows, GPUTypeCost{
+			GPUType:            gpuType,
+			TotalCost:          stats.TotalCost,
+			TotalGPUHours:      stats.TotalGPUHours,
+			AverageUtilization: stats.AverageUtilization,
+			IdleGPUHours:       stats.IdleGPUHours,
+			IdleCostWaste:      stats.IdleCostWaste,
+			UniqueInstances:    stats.UniqueInstances,
+		})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].TotalCost != rows[j].TotalCost {
+			return rows[i].TotalCost > rows[j].TotalCost
+		}
+		return rows[i].GPUType < rows[j].GPUType
+	})
+	return rows
+}
+
+func gpuTypeOrUnknown(gpuType string) string {
+	if gpuType == "" {
+		return unknownGPUType
+	}
+	return gpuType
+}
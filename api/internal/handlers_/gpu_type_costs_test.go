@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+)
+
+func TestGPUCostByType(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	metrics := []models.GPUMetrics{
+		{InstanceID: "a", GPUType: "A100", Timestamp: start, Utilization: 80, HourlyCost: 4, IntervalSeconds: 3600},
+		{InstanceID: "a", GPUType: "A100", Timestamp: start.Add(time.Hour), Utilization: 5, HourlyCost: 4, IntervalSeconds: 3600},
+		{InstanceID: "b", Timestamp: start, Utilization: 90, HourlyCost: 1, IntervalSeconds: 3600},
+	}
+	// H100 only has rolled-up history
+	rollups := []models.UsageRollup{
+		{GPUType: "H100", GPUHours: 2, Cost: 20, IdleGPUHours: 1, AverageUtilization: 50},
+	}
+
+	got := gpuCostByType(metrics, rollups)
+	want := []GPUTypeCost{
+		{GPUType: "H100", TotalCost: 20, TotalGPUHours: 2, AverageUtilization: 50, IdleGPUHours: 1, IdleCostWaste: 10},
+		{GPUType: "A100", TotalCost: 8, TotalGPUHours: 2, AverageUtilization: 42.5, IdleGPUHours: 1, IdleCostWaste: 4, UniqueInstances: 1},
+		{GPUType: unknownGPUType, TotalCost: 1, TotalGPUHours: 1, AverageUtilization: 90, UniqueInstances: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+
+	// The types add up to the overall stats
+	total := computeGPUStats(metrics)
+	for _, r := range rollups {
+		total.addRollup(r)
+	}
+	var cost, waste float64
+	for _, row := range got {
+		cost += row.TotalCost
+		waste += row.IdleCostWaste
+	}
+	if cost != total.TotalCost || waste != total.IdleCostWaste {
+		t.Errorf("types add up to %v cost and %v waste, want %v and %v", cost, waste, total.TotalCost, total.IdleCostWaste)
+	}
+}
+
+func TestGPUCostByTypeEmpty(t *testing.T) {
+	if got := gpuCostByType(nil, nil); got == nil || len(got) != 0 {
+		t.Errorf("got %#v, want an empty list so it encodes as []", got)
+	}
+}
@@ -1,14 +1,20 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
+	cloud "finopsbridge/api/internal/cloud_"
 	config "finopsbridge/api/internal/config_"
 	middleware "finopsbridge/api/internal/middleware_"
 	models "finopsbridge/api/internal/models_"
 	opa "finopsbridge/api/internal/opa_"
 	policygen "finopsbridge/api/internal/policygen_"
+	webhooks "finopsbridge/api/internal/webhooks_"
+	worker "finopsbridge/api/internal/worker_"
 
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
@@ -18,13 +24,19 @@ type Handlers struct {
 	DB       *gorm.DB
 	OPA      *opa.Engine
 	Config   *config.Config
+
+	// Enforcement runs remediation on demand; set once the worker is created
+	Enforcement *worker.EnforcementWorker
+
+	dashboard *dashboardCache
 }
 
 func New(db *gorm.DB, opaEngine *opa.Engine, cfg *config.Config) *Handlers {
 	return &Handlers{
-		DB:     db,
-		OPA:    opaEngine,
-		Config: cfg,
+		DB:        db,
+		OPA:       opaEngine,
+		Config:    cfg,
+		dashboard: newDashboardCache(),
 	}
 }
 
@@ -70,6 +82,24 @@ func (h *Handlers) CreateWaitlistEntry(c *fiber.Ctx) error {
 	return c.JSON(entry)
 }
 
+// maxDashboardRangeDays caps the span of a dashboard date range
+const maxDashboardRangeDays = 366
+
+// dashboardViolationScope narrows a violation query to an organization's policies and, when
+// providerID is set, to that provider's violations: those recorded against the provider itself
+// and those of its instances, which carry it as ProviderID
+func dashboardViolationScope(query *gorm.DB, orgID, providerID string) *gorm.DB {
+	query = query.Joins("JOIN policies ON policy_violations.policy_id = policies.id").
+		Where("policies.organization_id = ?", orgID)
+	if providerID != "" {
+		query = query.Where("(policy_violations.resource_id = ? OR policy_violations.provider_id = ?)", providerID, providerID)
+	}
+	return query
+}
+
+// GetDashboardStats returns headline stats. Optional ?start_date=&end_date= (YYYY-MM-DD)
+// scope violations, spend and trends to a range; ?provider_id= scopes them to one cloud.
+// Results are cached briefly per org; ?fresh=true recomputes them.
 func (h *Handlers) GetDashboardStats(c *fiber.Ctx) error {
 	orgID := middleware.GetOrgID(c)
 	if orgID == "" {
@@ -78,12 +108,47 @@ func (h *Handlers) GetDashboardStats(c *fiber.Ctx) error {
 		})
 	}
 
-	// Get total spend
-	var totalSpend float64
-	h.DB.Model(&models.CloudProvider{}).
+	cacheKey := dashboardKey(orgID, c.Query("start_date"), c.Query("end_date"), c.Query("provider_id"))
+	if !c.QueryBool("fresh") {
+		if stats, ok := h.dashboard.get(cacheKey); ok {
+			return c.JSON(stats)
+		}
+	}
+
+	start, end, ranged, err := parseDashboardRange(c.Query("start_date"), c.Query("end_date"), time.Now())
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	providerID := c.Query("provider_id")
+	if providerID != "" {
+		var provider models.CloudProvider
+		if err := h.DB.Where("id = ? AND organization_id = ?", providerID, orgID).First(&provider).Error; err != nil {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"error": "Cloud provider not found",
+			})
+		}
+	}
+
+	providerScope := func(query *gorm.DB) *gorm.DB {
+		if providerID != "" {
+			return query.Where("id = ?", providerID)
+		}
+		return query
+	}
+	violationScope := func(query *gorm.DB) *gorm.DB {
+		return dashboardViolationScope(query, orgID, providerID)
+	}
+
+	// Get total spend, in the reporting currency
+	var connected []models.CloudProvider
+	providerScope(h.DB.Model(&models.CloudProvider{})).
 		Where("organization_id = ? AND status = ?", orgID, "connected").
-		Select("COALESCE(SUM(monthly_spend), 0)").
-		Scan(&totalSpend)
+		Find(&connected)
+	unconverted := make(map[string]bool)
+	totalSpend, spendByProvider := h.reportingSpend(connected, unconverted)
 
 	// Get active policies count
 	var activePolicies int64
@@ -93,66 +158,213 @@ func (h *Handlers) GetDashboardStats(c *fiber.Ctx) error {
 
 	// Get connected clouds count
 	var connectedClouds int64
-	h.DB.Model(&models.CloudProvider{}).
+	providerScope(h.DB.Model(&models.CloudProvider{})).
 		Where("organization_id = ? AND status = ?", orgID, "connected").
 		Count(&connectedClouds)
 
-	// Get violations count (this month)
+	// Get violations count in range (this month by default)
 	var violations int64
-	startOfMonth := time.Now().AddDate(0, 0, -time.Now().Day()+1)
-	h.DB.Model(&models.PolicyViolation{}).
-		Joins("JOIN policies ON policy_violations.policy_id = policies.id").
-		Where("policies.organization_id = ? AND policy_violations.created_at >= ?", orgID, startOfMonth).
+	violationScope(h.DB.Model(&models.PolicyViolation{})).
+		Where("policy_violations.created_at >= ? AND policy_violations.created_at < ?", start, end.AddDate(0, 0, 1)).
 		Count(&violations)
 
-	// Get spend by provider
-	var spendByProvider []struct {
-		Provider string  `json:"provider"`
-		Amount   float64 `json:"amount"`
+	// Break the same violations down by severity and status for triage
+	var violationCounts []violationCount
+	violationScope(h.DB.Model(&models.PolicyViolation{})).
+		Where("policy_violations.created_at >= ? AND policy_violations.created_at < ?", start, end.AddDate(0, 0, 1)).
+		Select("policy_violations.severity AS severity, policy_violations.status AS status, COUNT(*) AS count").
+		Group("policy_violations.severity, policy_violations.status").
+		Scan(&violationCounts)
+	violationsBySeverity, violationsByStatus := violationBreakdown(violationCounts)
+
+	// Spend trend comes from daily snapshots: the requested range, or the last 6 months
+	trendStart := startOfMonth(end).AddDate(0, -5, 0)
+	if ranged {
+		trendStart = start
+	}
+
+	var snapshots []models.SpendSnapshot
+	snapshotQuery := h.DB.Where("organization_id = ? AND date >= ? AND date <= ?", orgID, startOfMonth(trendStart), end)
+	if providerID != "" {
+		snapshotQuery = snapshotQuery.Where("provider_id = ?", providerID)
+	}
+	snapshotQuery.Order("date ASC").Find(&snapshots)
+	h.convertSnapshots(snapshots, unconverted)
+
+	rangeTotal, rangeByType, rangeByMonth := spendInRange(snapshots, trendStart, end)
+
+	// An explicit range replaces live month-to-date spend with spend inside the range
+	if ranged {
+		totalSpend = rangeTotal
+		spendByProvider = spendByType(rangeByType)
 	}
-	h.DB.Model(&models.CloudProvider{}).
-		Where("organization_id = ? AND status = ?", orgID, "connected").
-		Select("type as provider, COALESCE(SUM(monthly_spend), 0) as amount").
-		Group("type").
-		Scan(&spendByProvider)
 
-	// Get spend trend (last 6 months)
 	var spendTrend []struct {
 		Date   string  `json:"date"`
 		Amount float64 `json:"amount"`
 	}
-	for i := 5; i >= 0; i-- {
-		month := time.Now().AddDate(0, -i, 0)
-		var amount float64
-		h.DB.Model(&models.CloudProvider{}).
-			Where("organization_id = ? AND status = ?", orgID, "connected").
-			Select("COALESCE(SUM(monthly_spend), 0)").
-			Scan(&amount)
+	for month := startOfMonth(trendStart); !month.After(end); month = month.AddDate(0, 1, 0) {
 		spendTrend = append(spendTrend, struct {
 			Date   string  `json:"date"`
 			Amount float64 `json:"amount"`
 		}{
 			Date:   month.Format("2006-01-02"),
-			Amount: amount,
+			Amount: rangeByMonth[month.Format("2006-01")],
 		})
 	}
 
 	// Get remediations count
 	var remediations int64
-	h.DB.Model(&models.PolicyViolation{}).
-		Joins("JOIN policies ON policy_violations.policy_id = policies.id").
-		Where("policies.organization_id = ? AND policy_violations.status = ?", orgID, "remediated").
-		Count(&remediations)
+	remediationQuery := violationScope(h.DB.Model(&models.PolicyViolation{})).
+		Where("policy_violations.status = ?", "remediated")
+	if ranged {
+		remediationQuery = remediationQuery.Where("policy_violations.remediated_at >= ? AND policy_violations.remediated_at < ?", start, end.AddDate(0, 0, 1))
+	}
+	remediationQuery.Count(&remediations)
 
-	return c.JSON(fiber.Map{
-		"totalSpend":       totalSpend,
-		"activePolicies":   activePolicies,
-		"connectedClouds": connectedClouds,
-		"violations":       violations,
-		"remediations":     remediations,
-		"spendByProvider":  spendByProvider,
-		"spendTrend":       spendTrend,
-	})
+	// Live month-to-date spend projected to a full month; not meaningful for an explicit range
+	var projectedSpend interface{}
+	if !ranged {
+		projectedSpend = cloud.ProrateMonthToDate(totalSpend, time.Now())
+	}
+
+	stats := fiber.Map{
+		"currency":             h.Config.ReportingCurrency,
+		"unconvertedCurrencies": sortedCurrencies(unconverted),
+		"totalSpend":           totalSpend,
+		"projectedSpend":       projectedSpend,
+		"activePolicies":       activePolicies,
+		"connectedClouds":      connectedClouds,
+		"violations":           violations,
+		"violationsBySeverity": violationsBySeverity,
+		"violationsByStatus":   violationsByStatus,
+		"remediations":         remediations,
+		"spendByProvider":      spendByProvider,
+		"spendTrend":           spendTrend,
+		"startDate":            start.Format("2006-01-02"),
+		"endDate":              end.Format("2006-01-02"),
+	}
+	h.dashboard.set(cacheKey, stats, time.Duration(h.Config.DashboardCacheTTLSeconds)*time.Second)
+
+	return c.JSON(stats)
+}
+
+// violationCount is the number of violations with one severity and status
+type violationCount struct {
+	Severity string
+	Status   string
+	Count    int64
+}
+
+// violationBreakdown totals grouped violation counts by severity and by status. Every known
+// severity and status is present, with zero when no violation has it.
+func violationBreakdown(counts []violationCount) (bySeverity, byStatus map[string]int64) {
+	bySeverity = map[string]int64{"critical": 0, "high": 0, "medium": 0, "low": 0}
+	byStatus = map[string]int64{"pending": 0, "remediated": 0, "ignored": 0}
+	for _, count := range counts {
+		bySeverity[count.Severity] += count.Count
+		byStatus[count.Status] += count.Count
+	}
+	return bySeverity, byStatus
+}
+
+// parseDashboardRange validates start/end dates (YYYY-MM-DD). Without either date the
+// range is the current month to date and ranged is false. A missing end date defaults to
+// today, and a missing start date to the first of the end date's month.
+func parseDashboardRange(startParam, endParam string, now time.Time) (start, end time.Time, ranged bool, err error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	start = startOfMonth(today)
+	end = today
+
+	if startParam != "" {
+		if start, err = time.Parse("2006-01-02", startParam); err != nil {
+			return start, end, false, fmt.Errorf("start_date must be YYYY-MM-DD")
+		}
+		ranged = true
+	}
+	if endParam != "" {
+		if end, err = time.Parse("2006-01-02", endParam); err != nil {
+			return start, end, false, fmt.Errorf("end_date must be YYYY-MM-DD")
+		}
+		ranged = true
+		if startParam == "" {
+			start = startOfMonth(end)
+		}
+	}
+
+	if end.Before(start) {
+		return start, end, false, fmt.Errorf("start_date must be on or before end_date")
+	}
+	if end.Sub(start) > maxDashboardRangeDays*24*time.Hour {
+		return start, end, false, fmt.Errorf("date range cannot exceed %d days", maxDashboardRangeDays)
+	}
+
+	return start, end, ranged, nil
+}
+
+func startOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// spendInRange derives spend between start and end (inclusive) from month-to-date snapshots,
+// which must be ordered by date and may start at the beginning of start's month. For each
+// provider and month, spend is the last value in range minus the last value before it.
+func spendInRange(snapshots []models.SpendSnapshot, start, end time.Time) (total float64, byType map[string]float64, byMonth map[string]float64) {
+	type monthSpend struct {
+		providerType string
+		month        string
+		baseline     float64
+		last         float64
+		inRange      bool
+	}
+
+	spends := make(map[string]*monthSpend)
+	var keys []string
+	for _, snapshot := range snapshots {
+		if snapshot.Date.After(end) {
+			continue
+		}
+
+		month := snapshot.Date.Format("2006-01")
+		key := snapshot.ProviderID + "|" + month
+		spend, exists := spends[key]
+		if !exists {
+			spend = &monthSpend{providerType: snapshot.ProviderType, month: month}
+			spends[key] = spend
+			keys = append(keys, key)
+		}
+
+		if snapshot.Date.Before(start) {
+			spend.baseline = snapshot.MonthToDateSpend
+		} else {
+			spend.last = snapshot.MonthToDateSpend
+			spend.inRange = true
+		}
+	}
+
+	byType = make(map[string]float64)
+	byMonth = make(map[string]float64)
+	for _, key := range keys {
+		spend := spends[key]
+		if !spend.inRange || spend.last < spend.baseline {
+			continue
+		}
+		amount := spend.last - spend.baseline
+		total += amount
+		byType[spend.providerType] += amount
+		byMonth[spend.month] += amount
+	}
+
+	return total, byType, byMonth
+}
+
+// meanTimeToRemediate returns the mean seconds from a policy's violations to their remediation,
+// or nil before any was remediated
+func meanTimeToRemediate(policy models.Policy) interface{} {
+	if policy.RemediationCount == 0 {
+		return nil
+	}
+	return policy.RemediationSecondsTotal / float64(policy.RemediationCount)
 }
 
 func (h *Handlers) ListPolicies(c *fiber.Ctx) error {
@@ -164,7 +376,7 @@ func (h *Handlers) ListPolicies(c *fiber.Ctx) error {
 	}
 
 	var policies []models.Policy
-	if err := h.DB.Where("organization_id = ?", orgID).
+	if err := wherePolicyTag(h.DB.Where("organization_id = ?", orgID), c.Query("tag")).
 		Preload("Violations", "status = ?", "pending").
 		Find(&policies).Error; err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -193,22 +405,66 @@ func (h *Handlers) ListPolicies(c *fiber.Ctx) error {
 		}
 
 		result = append(result, map[string]interface{}{
-			"id":          p.ID,
-			"name":        p.Name,
-			"description": p.Description,
-			"type":        p.Type,
-			"enabled":     p.Enabled,
-			"rego":        p.Rego,
-			"config":      config,
-			"createdAt":   p.CreatedAt,
-			"updatedAt":   p.UpdatedAt,
-			"violations":  violations,
+			"id":                         p.ID,
+			"name":                       p.Name,
+			"description":                p.Description,
+			"type":                       p.Type,
+			"enabled":                    p.Enabled,
+			"mode":                       p.Mode,
+			"rego":                       p.Rego,
+			"config":                     config,
+			"tags":                       policyTags(p),
+			"createdAt":                  p.CreatedAt,
+			"updatedAt":                  p.UpdatedAt,
+			"violations":                 violations,
+			"fireCount":                  p.FireCount,
+			"lastFiredAt":                p.LastFiredAt,
+			"meanTimeToRemediateSeconds": meanTimeToRemediate(p),
+			"monthlySavings":             p.MonthlySavingsTotal,
 		})
 	}
 
 	return c.JSON(result)
 }
 
+// GetPolicyInputSchema documents the OPA input fields a built-in policy type's Rego reads
+func (h *Handlers) GetPolicyInputSchema(c *fiber.Ctx) error {
+	policyType := c.Params("type")
+
+	fields, ok := policygen.InputSchema(policyType)
+	if !ok {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Unknown policy type: " + policyType,
+		})
+	}
+
+	response := fiber.Map{
+		"type":         policyType,
+		"fields":       fields,
+		"commonFields": policygen.CommonInputFields,
+	}
+	if policygen.IsResourcePolicyType(policyType) {
+		response["resourceFields"] = policygen.ResourceInputFields
+	}
+	return c.JSON(response)
+}
+
+// GetPolicyConflicts reports enabled policies that duplicate, overlap or conflict with each other
+func (h *Handlers) GetPolicyConflicts(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+
+	var policies []models.Policy
+	if err := h.DB.Where("organization_id = ? AND enabled = ?", orgID, true).Find(&policies).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch policies",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"conflicts": policygen.DetectConflicts(policies),
+	})
+}
+
 func (h *Handlers) GetPolicy(c *fiber.Ctx) error {
 	orgID := middleware.GetOrgID(c)
 	id := c.Params("id")
@@ -224,15 +480,22 @@ func (h *Handlers) GetPolicy(c *fiber.Ctx) error {
 	json.Unmarshal([]byte(policy.Config), &config)
 
 	return c.JSON(map[string]interface{}{
-		"id":          policy.ID,
-		"name":        policy.Name,
-		"description": policy.Description,
-		"type":        policy.Type,
-		"enabled":     policy.Enabled,
-		"rego":        policy.Rego,
-		"config":      config,
-		"createdAt":   policy.CreatedAt,
-		"updatedAt":   policy.UpdatedAt,
+		"id":                         policy.ID,
+		"name":                       policy.Name,
+		"description":                policy.Description,
+		"type":                       policy.Type,
+		"enabled":                    policy.Enabled,
+		"mode":                       policy.Mode,
+		"rego":                       policy.Rego,
+		"regoPackage":                policy.RegoPackage,
+		"config":                     config,
+		"tags":                       policyTags(policy),
+		"createdAt":                  policy.CreatedAt,
+		"updatedAt":                  policy.UpdatedAt,
+		"fireCount":                  policy.FireCount,
+		"lastFiredAt":                policy.LastFiredAt,
+		"meanTimeToRemediateSeconds": meanTimeToRemediate(policy),
+		"monthlySavings":             policy.MonthlySavingsTotal,
 	})
 }
 
@@ -248,7 +511,11 @@ func (h *Handlers) CreatePolicy(c *fiber.Ctx) error {
 		Name        string                 `json:"name"`
 		Description string                 `json:"description"`
 		Type        string                 `json:"type"`
+		Mode        string                 `json:"mode"`
 		Config      map[string]interface{} `json:"config"`
+		Tags        []string               `json:"tags"`
+
+		RemediationWebhookHeaders map[string]string `json:"remediationWebhookHeaders"` // Optional; never returned
 	}
 
 	if err := c.BodyParser(&req); err != nil {
@@ -256,6 +523,25 @@ func (h *Handlers) CreatePolicy(c *fiber.Ctx) error {
 			"error": "Invalid request body",
 		})
 	}
+	tags, err := normalizePolicyTags(req.Tags)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if err := validateRemediationWebhook(req.Config, req.RemediationWebhookHeaders); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if req.Mode == "" {
+		req.Mode = models.PolicyModeEnforce
+	}
+	if !models.ValidPolicyMode(req.Mode) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "mode must be enforce or monitor",
+		})
+	}
 
 	// Generate Rego policy
 	rego, err := policygen.GenerateRego(req.Type, req.Config)
@@ -275,7 +561,12 @@ func (h *Handlers) CreatePolicy(c *fiber.Ctx) error {
 		Enabled:        true,
 		Rego:           rego,
 		Config:         string(configJSON),
+		Mode:           req.Mode,
+		Tags:           encodePolicyTags(tags),
+
+		RemediationWebhookHeaders: encodeHeaders(req.RemediationWebhookHeaders),
 	}
+	policy.RegoPackage, _ = opa.ParsePackage(rego)
 
 	if err := h.DB.Create(&policy).Error; err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -285,6 +576,7 @@ func (h *Handlers) CreatePolicy(c *fiber.Ctx) error {
 
 	// Reload OPA policies
 	h.OPA.ReloadPolicies()
+	h.InvalidateDashboardStats(orgID)
 
 	// Create activity log
 	activityLog := models.ActivityLog{
@@ -293,27 +585,41 @@ func (h *Handlers) CreatePolicy(c *fiber.Ctx) error {
 		Message:        "Policy '" + policy.Name + "' was created",
 		Metadata:       `{"policyId":"` + policy.ID + `"}`,
 	}
-	h.DB.Create(&activityLog)
+	h.DB.WithContext(c.UserContext()).Create(&activityLog)
 
-	return c.JSON(map[string]interface{}{
+	response := map[string]interface{}{
 		"id":          policy.ID,
 		"name":        policy.Name,
 		"description": policy.Description,
 		"type":        policy.Type,
 		"enabled":     policy.Enabled,
+		"mode":        policy.Mode,
 		"rego":        policy.Rego,
 		"config":      req.Config,
+		"tags":        tags,
 		"createdAt":   policy.CreatedAt,
 		"updatedAt":   policy.UpdatedAt,
-	})
+	}
+	// Non-blocking: the policy is created either way, as providers may be connected later
+	if warning := h.providerApplicabilityWarning(orgID, policy.Type, h.policyTypeProviders(policy.Type)); warning != "" {
+		response["warning"] = warning
+	}
+
+	return c.JSON(response)
 }
 
 func (h *Handlers) UpdatePolicy(c *fiber.Ctx) error {
 	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
 	id := c.Params("id")
 
 	var req struct {
-		Enabled *bool `json:"enabled"`
+		Enabled *bool                  `json:"enabled"`
+		Mode    *string                `json:"mode"`
+		Config  map[string]interface{} `json:"config"`
+		Tags    *[]string              `json:"tags"` // Replaces the policy's tags; [] clears them
+
+		RemediationWebhookHeaders *map[string]string `json:"remediationWebhookHeaders"` // Replaces them; {} clears them
 	}
 
 	if err := c.BodyParser(&req); err != nil {
@@ -329,10 +635,65 @@ func (h *Handlers) UpdatePolicy(c *fiber.Ctx) error {
 		})
 	}
 
+	wasEnabled := policy.Enabled
+	oldConfig := policy.Config
+	oldMode := policy.Mode
+	oldTags := policyTags(policy)
+
 	if req.Enabled != nil {
 		policy.Enabled = *req.Enabled
 	}
 
+	if req.Mode != nil {
+		if !models.ValidPolicyMode(*req.Mode) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "mode must be enforce or monitor",
+			})
+		}
+		policy.Mode = *req.Mode
+	}
+
+	if req.Tags != nil {
+		tags, err := normalizePolicyTags(*req.Tags)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		policy.Tags = encodePolicyTags(tags)
+	}
+
+	if req.RemediationWebhookHeaders != nil {
+		if err := webhooks.ValidateHeaders(*req.RemediationWebhookHeaders); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		policy.RemediationWebhookHeaders = encodeHeaders(*req.RemediationWebhookHeaders)
+	}
+
+	if req.Config != nil {
+		if err := worker.ValidateRemediationWebhook(req.Config); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		// Generated Rego bakes the config in, so regenerate it alongside. Template and custom
+		// Rego reads the config as data.policy.config and is kept as written.
+		if regoGenerated(policy) {
+			rego, err := policygen.GenerateRego(policy.Type, req.Config)
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": "Failed to generate policy: " + err.Error(),
+				})
+			}
+			policy.Rego = rego
+			policy.RegoPackage, _ = opa.ParsePackage(rego)
+		}
+		configJSON, _ := json.Marshal(req.Config)
+		policy.Config = string(configJSON)
+	}
+
 	if err := h.DB.Save(&policy).Error; err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to update policy",
@@ -341,13 +702,101 @@ func (h *Handlers) UpdatePolicy(c *fiber.Ctx) error {
 
 	// Reload OPA policies
 	h.OPA.ReloadPolicies()
+	h.InvalidateDashboardStats(orgID)
+
+	// Audit who changed enforcement and how
+	if policy.Enabled != wasEnabled {
+		activityType, verb := "policy_enabled", "enabled"
+		if !policy.Enabled {
+			activityType, verb = "policy_disabled", "disabled"
+		}
+		h.logActivity(c.UserContext(), orgID, activityType,
+			fmt.Sprintf("Policy '%s' was %s", policy.Name, verb),
+			map[string]interface{}{
+				"policyId":   policy.ID,
+				"userId":     userID,
+				"oldEnabled": wasEnabled,
+				"newEnabled": policy.Enabled,
+			})
+	}
+	if policy.Mode != oldMode {
+		h.logActivity(c.UserContext(), orgID, "policy_mode_changed",
+			fmt.Sprintf("Policy '%s' was switched to %s mode", policy.Name, policy.Mode),
+			map[string]interface{}{
+				"policyId": policy.ID,
+				"userId":   userID,
+				"oldMode":  oldMode,
+				"newMode":  policy.Mode,
+			})
+	}
+	if policy.Config != oldConfig {
+		h.logActivity(c.UserContext(), orgID, "policy_config_updated",
+			fmt.Sprintf("Policy '%s' configuration was updated", policy.Name),
+			map[string]interface{}{
+				"policyId":  policy.ID,
+				"userId":    userID,
+				"oldConfig": json.RawMessage(nonEmptyJSON(oldConfig)),
+				"newConfig": json.RawMessage(nonEmptyJSON(policy.Config)),
+			})
+	}
+	if newTags := policyTags(policy); encodePolicyTags(newTags) != encodePolicyTags(oldTags) {
+		h.logActivity(c.UserContext(), orgID, "policy_tags_updated",
+			fmt.Sprintf("Policy '%s' tags were updated", policy.Name),
+			map[string]interface{}{
+				"policyId": policy.ID,
+				"userId":   userID,
+				"oldTags":  oldTags,
+				"newTags":  newTags,
+			})
+	}
 
 	return c.JSON(map[string]interface{}{
 		"id":      policy.ID,
 		"enabled": policy.Enabled,
+		"mode":    policy.Mode,
+		"config":  json.RawMessage(nonEmptyJSON(policy.Config)),
+		"tags":    policyTags(policy),
 	})
 }
 
+// validateRemediationWebhook checks a new policy's remediation webhook settings and headers
+func validateRemediationWebhook(config map[string]interface{}, headers map[string]string) error {
+	if err := worker.ValidateRemediationWebhook(config); err != nil {
+		return err
+	}
+	return webhooks.ValidateHeaders(headers)
+}
+
+// encodeHeaders stores custom request headers as JSON, or "" when there are none
+func encodeHeaders(headers map[string]string) string {
+	if len(headers) == 0 {
+		return ""
+	}
+	encoded, _ := json.Marshal(headers)
+	return string(encoded)
+}
+
+// regoGenerated reports whether a policy's Rego was written by policygen for its type, rather
+// than copied from a template or written by hand
+func regoGenerated(policy models.Policy) bool {
+	if !policygen.Generates(policy.Type) {
+		return false
+	}
+	regoPackage := policy.RegoPackage
+	if regoPackage == "" {
+		regoPackage, _ = opa.ParsePackage(policy.Rego)
+	}
+	return regoPackage == policygen.GeneratedPackage
+}
+
+// nonEmptyJSON returns raw JSON text, substituting null for an empty column
+func nonEmptyJSON(raw string) string {
+	if raw == "" {
+		return "null"
+	}
+	return raw
+}
+
 func (h *Handlers) DeletePolicy(c *fiber.Ctx) error {
 	orgID := middleware.GetOrgID(c)
 	id := c.Params("id")
@@ -360,6 +809,7 @@ func (h *Handlers) DeletePolicy(c *fiber.Ctx) error {
 
 	// Reload OPA policies
 	h.OPA.ReloadPolicies()
+	h.InvalidateDashboardStats(orgID)
 
 	return c.SendStatus(fiber.StatusNoContent)
 }
@@ -379,22 +829,32 @@ func (h *Handlers) ListCloudProviders(c *fiber.Ctx) error {
 		})
 	}
 
+	var groups []models.CloudProviderGroup
+	h.DB.Where("organization_id = ?", orgID).Find(&groups)
+	groupNames := make(map[string]string)
+	for _, g := range groups {
+		groupNames[g.ID] = g.Name
+	}
+
 	var result []map[string]interface{}
 	for _, p := range providers {
 		var credentials map[string]interface{}
 		json.Unmarshal([]byte(p.Credentials), &credentials)
 
 		result = append(result, map[string]interface{}{
-			"id":             p.ID,
-			"type":           p.Type,
-			"name":           p.Name,
-			"accountId":      p.AccountID,
-			"subscriptionId": p.SubscriptionID,
-			"projectId":      p.ProjectID,
-			"status":         p.Status,
-			"monthlySpend":   p.MonthlySpend,
-			"connectedAt":    p.ConnectedAt,
-			"credentials":    credentials,
+			"id":              p.ID,
+			"type":            p.Type,
+			"name":            p.Name,
+			"accountId":       p.AccountID,
+			"subscriptionId":  p.SubscriptionID,
+			"subscriptionIds": cloud.AzureSubscriptionIDs(p),
+			"projectId":       p.ProjectID,
+			"status":          p.Status,
+			"monthlySpend":    p.MonthlySpend,
+			"connectedAt":     p.ConnectedAt,
+			"credentials":     credentials,
+			"groupId":         p.GroupID,
+			"groupName":       groupNames[p.GroupID],
 		})
 	}
 
@@ -416,16 +876,17 @@ func (h *Handlers) GetCloudProvider(c *fiber.Ctx) error {
 	json.Unmarshal([]byte(provider.Credentials), &credentials)
 
 	return c.JSON(map[string]interface{}{
-		"id":             provider.ID,
-		"type":           provider.Type,
-		"name":           provider.Name,
-		"accountId":      provider.AccountID,
-		"subscriptionId": provider.SubscriptionID,
-		"projectId":      provider.ProjectID,
-		"status":         provider.Status,
-		"monthlySpend":   provider.MonthlySpend,
-		"connectedAt":    provider.ConnectedAt,
-		"credentials":    credentials,
+		"id":              provider.ID,
+		"type":            provider.Type,
+		"name":            provider.Name,
+		"accountId":       provider.AccountID,
+		"subscriptionId":  provider.SubscriptionID,
+		"subscriptionIds": cloud.AzureSubscriptionIDs(provider),
+		"projectId":       provider.ProjectID,
+		"status":          provider.Status,
+		"monthlySpend":    provider.MonthlySpend,
+		"connectedAt":     provider.ConnectedAt,
+		"credentials":     credentials,
 	})
 }
 
@@ -438,12 +899,13 @@ func (h *Handlers) CreateCloudProvider(c *fiber.Ctx) error {
 	}
 
 	var req struct {
-		Type           string                 `json:"type"`
-		Name           string                 `json:"name"`
-		AccountID      string                 `json:"accountId"`
-		SubscriptionID string                 `json:"subscriptionId"`
-		ProjectID      string                 `json:"projectId"`
-		Credentials    map[string]interface{} `json:"credentials"`
+		Type            string                 `json:"type"`
+		Name            string                 `json:"name"`
+		AccountID       string                 `json:"accountId"`
+		SubscriptionID  string                 `json:"subscriptionId"`  // One ID or a comma-separated list for Azure
+		SubscriptionIDs []string               `json:"subscriptionIds"` // Azure subscriptions, in addition to subscriptionId
+		ProjectID       string                 `json:"projectId"`
+		Credentials     map[string]interface{} `json:"credentials"`
 	}
 
 	if err := c.BodyParser(&req); err != nil {
@@ -452,6 +914,30 @@ func (h *Handlers) CreateCloudProvider(c *fiber.Ctx) error {
 		})
 	}
 
+	// One Azure connection can cover several subscriptions
+	if req.Type == "azure" {
+		subscriptionIDs, err := cloud.NormalizeAzureSubscriptionIDs(req.SubscriptionID, req.SubscriptionIDs)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		req.SubscriptionID = subscriptionIDs
+	}
+
+	if missing := cloud.MissingCredentials(req.Type, req.Credentials); len(missing) > 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":         "Missing required " + req.Type + " credentials: " + strings.Join(missing, ", "),
+			"missingFields": missing,
+		})
+	}
+
+	// An MFA code only starts a session and is never stored
+	tokenCode := ""
+	if req.Type == "aws" {
+		tokenCode = cloud.TakeAWSTokenCode(req.Credentials)
+	}
+
 	credentialsJSON, _ := json.Marshal(req.Credentials)
 	now := time.Now()
 
@@ -480,9 +966,12 @@ func (h *Handlers) CreateCloudProvider(c *fiber.Ctx) error {
 		Message:        "Cloud provider '" + provider.Name + "' (" + provider.Type + ") was connected",
 		Metadata:       `{"providerId":"` + provider.ID + `"}`,
 	}
-	h.DB.Create(&activityLog)
+	h.DB.WithContext(c.UserContext()).Create(&activityLog)
 
-	return c.JSON(map[string]interface{}{
+	h.InvalidateDashboardStats(orgID)
+	go webhooks.NotifyEvent(h.DB, orgID, providerEvent(webhooks.EventProviderConnected, provider, middleware.GetUserID(c), middleware.GetRequestID(c)))
+
+	response := map[string]interface{}{
 		"id":             provider.ID,
 		"type":           provider.Type,
 		"name":           provider.Name,
@@ -490,20 +979,45 @@ func (h *Handlers) CreateCloudProvider(c *fiber.Ctx) error {
 		"subscriptionId": provider.SubscriptionID,
 		"projectId":      provider.ProjectID,
 		"status":         provider.Status,
-		"connectedAt":   provider.ConnectedAt,
-	})
+		"connectedAt":    provider.ConnectedAt,
+	}
+	// The provider is connected either way; a failed session is retried with a new code
+	if tokenCode != "" {
+		if err := cloud.StartAWSMFASession(provider, h.Config, tokenCode); err != nil {
+			response["mfaError"] = err.Error()
+		}
+	}
+	return c.JSON(response)
 }
 
 func (h *Handlers) DeleteCloudProvider(c *fiber.Ctx) error {
 	orgID := middleware.GetOrgID(c)
 	id := c.Params("id")
 
-		if err := h.DB.Where("id = ? AND organization_id = ?", id, orgID).Delete(&models.CloudProvider{}).Error; err != nil {
+	var provider models.CloudProvider
+	if err := h.DB.Where("id = ? AND organization_id = ?", id, orgID).First(&provider).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Cloud provider not found",
+		})
+	}
+
+	if err := h.DB.Delete(&provider).Error; err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to delete cloud provider",
 		})
 	}
 
+	cloud.InvalidateBilling(id)
+	h.InvalidateDashboardStats(orgID)
+
+	userID := middleware.GetUserID(c)
+	h.logActivity(c.UserContext(), orgID, "cloud_disconnected", "Cloud provider '"+provider.Name+"' ("+provider.Type+") was disconnected", map[string]interface{}{
+		"providerId": provider.ID,
+		"userId":     userID,
+	})
+
+	go webhooks.NotifyEvent(h.DB, orgID, providerEvent(webhooks.EventProviderDisconnected, provider, userID, middleware.GetRequestID(c)))
+
 	return c.SendStatus(fiber.StatusNoContent)
 }
 
@@ -535,6 +1049,7 @@ func (h *Handlers) ListActivityLogs(c *fiber.Ctx) error {
 			"type":      log.Type,
 			"message":   log.Message,
 			"metadata":  metadata,
+			"requestId": log.RequestID,
 			"createdAt": log.CreatedAt,
 		})
 	}
@@ -550,21 +1065,26 @@ func (h *Handlers) ListWebhooks(c *fiber.Ctx) error {
 		})
 	}
 
-	var webhooks []models.Webhook
-	if err := h.DB.Where("organization_id = ?", orgID).Find(&webhooks).Error; err != nil {
+	var hooks []models.Webhook
+	if err := h.DB.Where("organization_id = ?", orgID).Find(&hooks).Error; err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to fetch webhooks",
 		})
 	}
 
 	var result []map[string]interface{}
-	for _, w := range webhooks {
+	for _, w := range hooks {
 		result = append(result, map[string]interface{}{
-			"id":        w.ID,
-			"type":      w.Type,
-			"url":       w.URL,
-			"enabled":   w.Enabled,
-			"createdAt": w.CreatedAt,
+			"id":                  w.ID,
+			"type":                w.Type,
+			"url":                 w.URL,
+			"enabled":             w.Enabled,
+			"events":              webhookEvents(w),
+			"headers":             redactedHeaders(w),
+			"consecutiveFailures": w.ConsecutiveFailures,
+			"disabledReason":      w.DisabledReason,
+			"disabledAt":          w.DisabledAt,
+			"createdAt":           w.CreatedAt,
 		})
 	}
 
@@ -580,8 +1100,10 @@ func (h *Handlers) CreateWebhook(c *fiber.Ctx) error {
 	}
 
 	var req struct {
-		Type string `json:"type"`
-		URL  string `json:"url"`
+		Type    string            `json:"type"`
+		URL     string            `json:"url"`
+		Events  []string          `json:"events"`  // Optional; defaults to webhooks.DefaultEvents
+		Headers map[string]string `json:"headers"` // Optional; sent with every delivery
 	}
 
 	if err := c.BodyParser(&req); err != nil {
@@ -590,11 +1112,41 @@ func (h *Handlers) CreateWebhook(c *fiber.Ctx) error {
 		})
 	}
 
+	req.Type = strings.ToLower(strings.TrimSpace(req.Type))
+	req.URL = strings.TrimSpace(req.URL)
+	if err := webhooks.Validate(req.Type, req.URL); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	if err := webhooks.ValidateHeaders(req.Headers); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	headersJSON := encodeHeaders(req.Headers)
+
+	var eventsJSON string
+	if len(req.Events) > 0 {
+		for _, event := range req.Events {
+			if !webhooks.ValidEvent(event) {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": "Unknown webhook event: " + event,
+				})
+			}
+		}
+		eventsBytes, _ := json.Marshal(req.Events)
+		eventsJSON = string(eventsBytes)
+	}
+
 	webhook := models.Webhook{
 		OrganizationID: orgID,
 		Type:          req.Type,
 		URL:           req.URL,
 		Enabled:       true,
+		Events:        eventsJSON,
+		Headers:       headersJSON,
 	}
 
 	if err := h.DB.Create(&webhook).Error; err != nil {
@@ -636,7 +1188,7 @@ func (h *Handlers) ListViolations(c *fiber.Ctx) error {
 }
 
 // Helper method to log activity
-func (h *Handlers) logActivity(orgID, activityType, message string, metadata map[string]interface{}) {
+func (h *Handlers) logActivity(ctx context.Context, orgID, activityType, message string, metadata map[string]interface{}) {
 	var metadataJSON string
 	if metadata != nil {
 		metadataBytes, _ := json.Marshal(metadata)
@@ -649,6 +1201,15 @@ func (h *Handlers) logActivity(orgID, activityType, message string, metadata map
 		Message:        message,
 		Metadata:       metadataJSON,
 	}
-	h.DB.Create(&activityLog)
+	h.DB.WithContext(ctx).Create(&activityLog)
 }
 
+
+// Helper method to load (or lazily create) the organization record for a Clerk org ID
+func (h *Handlers) getOrganization(orgID string) (*models.Organization, error) {
+	org := models.Organization{ClerkOrgID: orgID}
+	if err := h.DB.Where("clerk_org_id = ?", orgID).FirstOrCreate(&org).Error; err != nil {
+		return nil, err
+	}
+	return &org, nil
+}
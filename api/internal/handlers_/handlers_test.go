@@ -0,0 +1,185 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	config "finopsbridge/api/internal/config_"
+	models "finopsbridge/api/internal/models_"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// dryRunHandlers returns Handlers whose database builds Postgres statements without running
+// them, so query construction can be checked without a server. Writes skip the default
+// transaction, which would need a connection.
+func dryRunHandlers(t *testing.T) *Handlers {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=finopsbridge sslmode=disable"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(db, nil, &config.Config{ReportingCurrency: "USD"})
+}
+
+// querySQL returns the statement a query finds into dest, with its parameters inlined
+func querySQL(query *gorm.DB, dest interface{}) string {
+	stmt := query.Find(dest).Statement
+	return query.Dialector.Explain(stmt.SQL.String(), stmt.Vars...)
+}
+
+// recordActivity collects the activity log entries h creates
+func recordActivity(t *testing.T, h *Handlers) *[]models.ActivityLog {
+	t.Helper()
+	var logged []models.ActivityLog
+	err := h.DB.Callback().Create().Before("gorm:create").Register("test:record_activity", func(db *gorm.DB) {
+		if entry, ok := db.Statement.Dest.(*models.ActivityLog); ok {
+			logged = append(logged, *entry)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &logged
+}
+
+func TestDashboardViolationScope(t *testing.T) {
+	h := dryRunHandlers(t)
+
+	sql := querySQL(dashboardViolationScope(h.DB.Model(&models.PolicyViolation{}), "org", ""), &[]models.PolicyViolation{})
+	if !strings.Contains(sql, "policies.organization_id = 'org'") || strings.Contains(sql, "resource_id =") {
+		t.Errorf("%s\nshould scope to the organization only", sql)
+	}
+
+	// An instance violation has the instance as its resource and the provider as ProviderID
+	sql = querySQL(dashboardViolationScope(h.DB.Model(&models.PolicyViolation{}), "org", "cp1"), &[]models.PolicyViolation{})
+	if want := "(policy_violations.resource_id = 'cp1' OR policy_violations.provider_id = 'cp1')"; !strings.Contains(sql, want) {
+		t.Errorf("%s\nshould contain %s", sql, want)
+	}
+}
+
+func TestViolationBreakdown(t *testing.T) {
+	bySeverity, byStatus := violationBreakdown([]violationCount{
+		{Severity: "critical", Status: "pending", Count: 2},
+		{Severity: "high", Status: "pending", Count: 3},
+		{Severity: "high", Status: "remediated", Count: 4},
+	})
+
+	wantSeverity := map[string]int64{"critical": 2, "high": 7, "medium": 0, "low": 0}
+	wantStatus := map[string]int64{"pending": 5, "remediated": 4, "ignored": 0}
+	if !reflect.DeepEqual(bySeverity, wantSeverity) {
+		t.Errorf("by severity = %v, want %v", bySeverity, wantSeverity)
+	}
+	if !reflect.DeepEqual(byStatus, wantStatus) {
+		t.Errorf("by status = %v, want %v", byStatus, wantStatus)
+	}
+}
+
+func TestMeanTimeToRemediate(t *testing.T) {
+	if got := meanTimeToRemediate(models.Policy{}); got != nil {
+		t.Errorf("got %v, want nil before any remediation", got)
+	}
+	if got := meanTimeToRemediate(models.Policy{RemediationCount: 4, RemediationSecondsTotal: 600}); got != 150.0 {
+		t.Errorf("got %v, want 150", got)
+	}
+}
+
+func TestParseDashboardRange(t *testing.T) {
+	now := time.Date(2026, 3, 15, 17, 30, 0, 0, time.UTC)
+	day := func(d string) time.Time {
+		parsed, _ := time.Parse("2006-01-02", d)
+		return parsed
+	}
+
+	tests := []struct {
+		start, end         string
+		wantStart, wantEnd time.Time
+		wantRanged         bool
+	}{
+		{"", "", day("2026-03-01"), day("2026-03-15"), false},
+		{"2026-02-10", "", day("2026-02-10"), day("2026-03-15"), true},
+		// An end date alone covers the month up to it
+		{"", "2026-01-20", day("2026-01-01"), day("2026-01-20"), true},
+		{"2025-12-01", "2026-01-31", day("2025-12-01"), day("2026-01-31"), true},
+	}
+	for _, tt := range tests {
+		start, end, ranged, err := parseDashboardRange(tt.start, tt.end, now)
+		if err != nil {
+			t.Errorf("(%q, %q): %v", tt.start, tt.end, err)
+			continue
+		}
+		if !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) || ranged != tt.wantRanged {
+			t.Errorf("(%q, %q) = %s, %s, %v", tt.start, tt.end, start.Format("2006-01-02"), end.Format("2006-01-02"), ranged)
+		}
+	}
+
+	for _, bad := range [][2]string{
+		{"03/01/2026", ""},
+		{"", "2026-13-01"},
+		{"2026-03-10", "2026-03-01"},
+		{"2024-01-01", "2026-01-01"},
+	} {
+		if _, _, _, err := parseDashboardRange(bad[0], bad[1], now); err == nil {
+			t.Errorf("(%q, %q) should be rejected", bad[0], bad[1])
+		}
+	}
+}
+
+func TestSpendInRange(t *testing.T) {
+	snapshot := func(providerID, providerType, date string, spend float64) models.SpendSnapshot {
+		parsed, _ := time.Parse("2006-01-02", date)
+		return models.SpendSnapshot{ProviderID: providerID, ProviderType: providerType, Date: parsed, MonthToDateSpend: spend}
+	}
+	snapshots := []models.SpendSnapshot{
+		snapshot("p1", "aws", "2026-02-05", 100),
+		snapshot("p1", "aws", "2026-02-10", 150),
+		snapshot("p1", "aws", "2026-02-28", 400),
+		snapshot("p1", "aws", "2026-03-03", 30),
+		snapshot("p1", "aws", "2026-03-09", 90),
+		snapshot("p2", "gcp", "2026-03-08", 20),
+		snapshot("p2", "gcp", "2026-03-12", 50),
+	}
+
+	start, _ := time.Parse("2006-01-02", "2026-02-10")
+	end, _ := time.Parse("2006-01-02", "2026-03-08")
+	total, byType, byMonth := spendInRange(snapshots, start, end)
+
+	// February is 400 - 100 (the last value before the range), March is everything up to the end
+	if total != 300+30+20 {
+		t.Errorf("total = %v, want 350", total)
+	}
+	if byType["aws"] != 330 || byType["gcp"] != 20 {
+		t.Errorf("byType = %v", byType)
+	}
+	if byMonth["2026-02"] != 300 || byMonth["2026-03"] != 50 {
+		t.Errorf("byMonth = %v", byMonth)
+	}
+}
+
+func TestLogActivityConfigChangeFromEmpty(t *testing.T) {
+	h := dryRunHandlers(t)
+	logged := recordActivity(t, h)
+
+	h.logActivity(context.Background(), "org", "policy_config_updated", "Policy 'Idle' configuration was updated",
+		map[string]interface{}{
+			"oldConfig": json.RawMessage(nonEmptyJSON("")),
+			"newConfig": json.RawMessage(nonEmptyJSON(`{"idleHours":24}`)),
+		})
+
+	if len(*logged) != 1 {
+		t.Fatalf("got %d activity entries, want 1", len(*logged))
+	}
+	// An empty column would be invalid raw JSON and drop the whole metadata
+	if got, want := (*logged)[0].Metadata, `{"newConfig":{"idleHours":24},"oldConfig":null}`; got != want {
+		t.Errorf("metadata = %s, want %s", got, want)
+	}
+}
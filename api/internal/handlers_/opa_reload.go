@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	middleware "finopsbridge/api/internal/middleware_"
+	models "finopsbridge/api/internal/models_"
+	opa "finopsbridge/api/internal/opa_"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// policyCompileStatus is whether one policy compiled after a reload
+type policyCompileStatus struct {
+	PolicyID string `json:"policyId"`
+	Name     string `json:"name"`
+	Compiled bool   `json:"compiled"`
+	Error    string `json:"error,omitempty"`
+}
+
+// ReloadOPAPolicies re-exports the organization's enabled policies from the database to the
+// OPA directory and compiles each, for when the directory has drifted from the database. It
+// reports each policy's compile status; failures to compile don't stop the others.
+func (h *Handlers) ReloadOPAPolicies(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+
+	var policies []models.Policy
+	if err := h.DB.Where("organization_id = ? AND enabled = ?", orgID, true).Order("name").Find(&policies).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch policies",
+		})
+	}
+
+	infos := make([]opa.PolicyInfo, 0, len(policies))
+	for _, policy := range policies {
+		var policyConfig map[string]interface{}
+		json.Unmarshal([]byte(policy.Config), &policyConfig)
+		infos = append(infos, opa.PolicyInfo{
+			ID:     policy.ID,
+			Rego:   policy.Rego,
+			Type:   policy.Type,
+			Config: policyConfig,
+		})
+	}
+	if err := h.OPA.LoadPoliciesFromDB(infos); err != nil {
+		fmt.Printf("Error exporting policies to OPA directory: %v\n", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to write policies to the OPA directory: " + err.Error(),
+		})
+	}
+
+	statuses := make([]policyCompileStatus, 0, len(policies))
+	failed := 0
+	for _, policy := range policies {
+		status := policyCompileStatus{PolicyID: policy.ID, Name: policy.Name, Compiled: true}
+		if err := h.OPA.CompilePolicy(policy.ID); err != nil {
+			status.Compiled = false
+			status.Error = err.Error()
+			failed++
+		}
+		statuses = append(statuses, status)
+	}
+
+	h.logActivity(c.UserContext(), orgID, "opa_reload", fmt.Sprintf("Reloaded %d policies into OPA, %d failed to compile", len(policies), failed), map[string]interface{}{
+		"reloaded": len(policies),
+		"failed":   failed,
+		"userId":   middleware.GetUserID(c),
+	})
+
+	return c.JSON(fiber.Map{
+		"reloaded": len(policies),
+		"failed":   failed,
+		"policies": statuses,
+	})
+}
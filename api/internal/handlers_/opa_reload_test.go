@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	models "finopsbridge/api/internal/models_"
+	opa "finopsbridge/api/internal/opa_"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func TestReloadOPAPolicies(t *testing.T) {
+	h := dryRunHandlers(t)
+	engine, err := opa.Initialize(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.OPA = engine
+	logged := recordActivity(t, h)
+
+	stored := []models.Policy{
+		{ID: "p1", Name: "Broken", Rego: "package finopsbridge.policies\n\nallow {\n\tinput.x ==\n}\n"},
+		{ID: "p2", Name: "Spend", Rego: "package finopsbridge.policies\n\ndefault allow = true\n", Config: `{"limit": 100}`},
+	}
+	h.DB.Callback().Query().After("gorm:query").Register("test:stub_policies", func(tx *gorm.DB) {
+		if dest, ok := tx.Statement.Dest.(*[]models.Policy); ok {
+			*dest = stored
+		}
+	})
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("orgID", "org")
+		return c.Next()
+	})
+	app.Post("/opa/reload", h.ReloadOPAPolicies)
+	resp, err := app.Test(httptest.NewRequest("POST", "/opa/reload", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Reloaded int                   `json:"reloaded"`
+		Failed   int                   `json:"failed"`
+		Policies []policyCompileStatus `json:"policies"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Reloaded != 2 || body.Failed != 1 || len(body.Policies) != 2 {
+		t.Fatalf("got %+v, want 2 reloaded and 1 failed", body)
+	}
+	if body.Policies[0].Compiled || body.Policies[0].Error == "" {
+		t.Errorf("the broken policy should report its compile error, got %+v", body.Policies[0])
+	}
+	if !body.Policies[1].Compiled {
+		t.Errorf("the valid policy should compile, got %+v", body.Policies[1])
+	}
+	if len(*logged) != 1 || (*logged)[0].Type != "opa_reload" {
+		t.Errorf("activity = %+v, want one opa_reload entry", *logged)
+	}
+}
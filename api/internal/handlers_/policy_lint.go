@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	middleware "finopsbridge/api/internal/middleware_"
+	models "finopsbridge/api/internal/models_"
+	opa "finopsbridge/api/internal/opa_"
+	policygen "finopsbridge/api/internal/policygen_"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// LintPolicy checks a policy's stored Rego for common mistakes that compile but misbehave,
+// such as an allow rule without a default or config read from where it isn't provided.
+func (h *Handlers) LintPolicy(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+	id := c.Params("id")
+
+	var policy models.Policy
+	if err := h.DB.Where("id = ? AND organization_id = ?", id, orgID).First(&policy).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Policy not found",
+		})
+	}
+
+	warnings, err := opa.Lint(policy.Rego, opa.LintOptions{
+		RegoPackage:   policy.RegoPackage,
+		ConfigInInput: configInInput(policy.Type),
+	})
+	if err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"policyId": policy.ID,
+		"warnings": warnings,
+	})
+}
+
+// configInInput reports whether policies of a type are evaluated with their config as
+// input.config: AI and GPU policies by the worker, and request gate policies by the AI check
+func configInInput(policyType string) bool {
+	if policygen.IsAIPolicyType(policyType) {
+		return true
+	}
+	for _, gateType := range requestGatePolicyTypes {
+		if gateType == policyType {
+			return true
+		}
+	}
+	return false
+}
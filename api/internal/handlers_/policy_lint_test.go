@@ -0,0 +1,18 @@
+package handlers
+
+import "testing"
+
+func TestConfigInInput(t *testing.T) {
+	tests := map[string]bool{
+		"llm_token_budget":           true,
+		"gpu_idle_detection":         true,
+		"model_selection_governance": true,
+		"max_spend":                  false,
+		"require_tags":               false,
+	}
+	for policyType, want := range tests {
+		if got := configInInput(policyType); got != want {
+			t.Errorf("configInInput(%q) = %v, want %v", policyType, got, want)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestPolicyModeRejectsUnknownMode(t *testing.T) {
+	h := dryRunHandlers(t)
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("orgID", "org")
+		return c.Next()
+	})
+	app.Post("/policies", h.CreatePolicy)
+	app.Patch("/policies/:id", h.UpdatePolicy)
+
+	for _, req := range []struct{ method, path, body string }{
+		{"POST", "/policies", `{"name": "Idle", "type": "idle_resources", "mode": "audit"}`},
+		{"PATCH", "/policies/p1", `{"mode": "audit"}`},
+		{"PATCH", "/policies/p1", `{"mode": ""}`},
+	} {
+		r := httptest.NewRequest(req.method, req.path, strings.NewReader(req.body))
+		r.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != fiber.StatusBadRequest {
+			t.Errorf("%s %s %s: status %d, want 400", req.method, req.path, req.body, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	middleware "finopsbridge/api/internal/middleware_"
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// permissionCommon groups permissions that don't name a specific cloud's service
+const permissionCommon = "common"
+
+// awsPermissionServices and gcpPermissionServices are the service prefixes of "service:action"
+// permissions in the templates that belong to one cloud
+var (
+	awsPermissionServices = map[string]bool{
+		"backup": true, "ce": true, "cloudwatch": true, "ec2": true, "kms": true,
+		"rds": true, "s3": true, "sagemaker": true, "tag": true,
+	}
+	gcpPermissionServices = map[string]bool{"ai-platform": true, "monitoring": true}
+)
+
+// permissionProvider returns the provider type a template permission is granted on: Azure
+// operations start with "Microsoft.", GCP IAM permissions are dotted ("compute.instances.stop"),
+// and "service:action" permissions are sorted by service. The rest are common.
+func permissionProvider(permission string) string {
+	if strings.HasPrefix(permission, "Microsoft.") {
+		return "azure"
+	}
+	service, _, found := strings.Cut(permission, ":")
+	if !found {
+		if strings.Contains(permission, ".") {
+			return "gcp"
+		}
+		return permissionCommon
+	}
+	switch {
+	case awsPermissionServices[service]:
+		return "aws"
+	case gcpPermissionServices[service]:
+		return "gcp"
+	}
+	return permissionCommon
+}
+
+// templatePermissions returns the permissions the templates of each policy type require
+func templatePermissions(templates []models.PolicyTemplate) map[string][]string {
+	byType := make(map[string][]string)
+	for _, template := range templates {
+		var permissions []string
+		json.Unmarshal([]byte(template.RequiredPermissions), &permissions)
+		byType[template.PolicyType] = append(byType[template.PolicyType], permissions...)
+	}
+	return byType
+}
+
+// permissionUnion merges the permissions required by the policies' types into one sorted,
+// de-duplicated list per provider type
+func permissionUnion(policies []models.Policy, byType map[string][]string) map[string][]string {
+	seen := make(map[string]map[string]bool)
+	for _, policy := range policies {
+		for _, permission := range byType[policy.Type] {
+			permission = strings.TrimSpace(permission)
+			if permission == "" {
+				continue
+			}
+			provider := permissionProvider(permission)
+			if seen[provider] == nil {
+				seen[provider] = make(map[string]bool)
+			}
+			seen[provider][permission] = true
+		}
+	}
+
+	union := make(map[string][]string, len(seen))
+	for provider, permissions := range seen {
+		list := make([]string, 0, len(permissions))
+		for permission := range permissions {
+			list = append(list, permission)
+		}
+		sort.Strings(list)
+		union[provider] = list
+	}
+	return union
+}
+
+// GetPolicyPermissions returns the permissions the organization's enabled policies need for
+// enforcement and remediation, grouped by provider type, from their templates' requirements.
+// Policies whose type has no template contribute nothing.
+func (h *Handlers) GetPolicyPermissions(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+
+	var policies []models.Policy
+	if err := h.DB.Where("organization_id = ? AND enabled = ?", orgID, true).Find(&policies).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch policies",
+		})
+	}
+
+	types := make([]string, 0, len(policies))
+	for _, policy := range policies {
+		types = append(types, policy.Type)
+	}
+	var templates []models.PolicyTemplate
+	if len(types) > 0 {
+		if err := h.DB.Where("policy_type IN ?", types).Find(&templates).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to fetch policy templates",
+			})
+		}
+	}
+	byType := templatePermissions(templates)
+
+	perPolicy := make([]map[string]interface{}, 0, len(policies))
+	for _, policy := range policies {
+		perPolicy = append(perPolicy, map[string]interface{}{
+			"policyId":    policy.ID,
+			"name":        policy.Name,
+			"type":        policy.Type,
+			"permissions": permissionUnion([]models.Policy{policy}, byType),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"permissions": permissionUnion(policies, byType),
+		"policies":    perPolicy,
+	})
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	models "finopsbridge/api/internal/models_"
+)
+
+func TestPermissionProvider(t *testing.T) {
+	tests := map[string]string{
+		"ec2:StopInstances":  "aws",
+		"ce:GetCostAndUsage": "aws",
+		"Microsoft.Compute/virtualMachines/deallocate/action": "azure",
+		"compute.instances.stop":                              "gcp",
+		"monitoring:read":                                     "gcp",
+		"openai:usage.read":                                   permissionCommon,
+		"admin":                                               permissionCommon,
+	}
+	for permission, want := range tests {
+		if got := permissionProvider(permission); got != want {
+			t.Errorf("permissionProvider(%q) = %q, want %q", permission, got, want)
+		}
+	}
+}
+
+func TestPermissionUnion(t *testing.T) {
+	templates := []models.PolicyTemplate{
+		{PolicyType: "idle_resources", RequiredPermissions: `["ec2:StopInstances", "ec2:DescribeInstances", "compute.instances.stop"]`},
+		{PolicyType: "idle_resources", RequiredPermissions: `["ec2:StopInstances", " "]`},
+		{PolicyType: "max_spend", RequiredPermissions: `["ce:GetCostAndUsage", "Microsoft.Consumption/usageDetails/read"]`},
+		{PolicyType: "require_tags", RequiredPermissions: `not json`},
+	}
+	policies := []models.Policy{
+		{Type: "idle_resources"},
+		{Type: "max_spend"},
+		{Type: "max_spend"},
+		{Type: "require_tags"},
+		{Type: "no_template"},
+	}
+
+	got := permissionUnion(policies, templatePermissions(templates))
+	want := map[string][]string{
+		"aws":   {"ce:GetCostAndUsage", "ec2:DescribeInstances", "ec2:StopInstances"},
+		"gcp":   {"compute.instances.stop"},
+		"azure": {"Microsoft.Consumption/usageDetails/read"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
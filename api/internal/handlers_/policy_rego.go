@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"regexp"
+	"strings"
+
+	middleware "finopsbridge/api/internal/middleware_"
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// unsafeFilenameChars are replaced when a policy name is used as a download filename
+var unsafeFilenameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// GetPolicyRego downloads the Rego stored for a policy, exactly as it is enforced, as a .rego file
+func (h *Handlers) GetPolicyRego(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+	id := c.Params("id")
+
+	var policy models.Policy
+	if err := h.DB.Where("id = ? AND organization_id = ?", id, orgID).First(&policy).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Policy not found",
+		})
+	}
+
+	c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+regoFilename(policy)+`"`)
+	c.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+	return c.SendString(policy.Rego)
+}
+
+// regoFilename derives a download filename from the policy name, falling back to its ID
+func regoFilename(policy models.Policy) string {
+	name := strings.Trim(unsafeFilenameChars.ReplaceAllString(policy.Name, "-"), "-.")
+	if name == "" {
+		name = policy.ID
+	}
+	return name + ".rego"
+}
@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"testing"
+
+	models "finopsbridge/api/internal/models_"
+)
+
+func TestRegoFilename(t *testing.T) {
+	tests := []struct {
+		policy models.Policy
+		want   string
+	}{
+		{models.Policy{ID: "p1", Name: "max_spend"}, "max_spend.rego"},
+		{models.Policy{ID: "p1", Name: "Max Spend: Production (AWS)"}, "Max-Spend-Production-AWS.rego"},
+		{models.Policy{ID: "p1", Name: `../../etc/"passwd"`}, "etc-passwd.rego"},
+		{models.Policy{ID: "p1", Name: "☃"}, "p1.rego"},
+		{models.Policy{ID: "p1"}, "p1.rego"},
+	}
+
+	for _, tt := range tests {
+		if got := regoFilename(tt.policy); got != tt.want {
+			t.Errorf("regoFilename(%q) = %q, want %q", tt.policy.Name, got, tt.want)
+		}
+	}
+}
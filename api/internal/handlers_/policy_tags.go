@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	models "finopsbridge/api/internal/models_"
+
+	"gorm.io/gorm"
+)
+
+// Bounds on a policy's tags
+const (
+	maxPolicyTags      = 20
+	maxPolicyTagLength = 64
+)
+
+// normalizePolicyTags trims and lower-cases tags, dropping blanks and duplicates, so that
+// filtering by "Cost-Control" finds policies tagged "cost-control"
+func normalizePolicyTags(tags []string) ([]string, error) {
+	result := []string{}
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if len(tag) > maxPolicyTagLength {
+			return nil, fmt.Errorf("tags must be at most %d characters", maxPolicyTagLength)
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	if len(result) > maxPolicyTags {
+		return nil, fmt.Errorf("a policy can have at most %d tags", maxPolicyTags)
+	}
+	return result, nil
+}
+
+// encodePolicyTags stores normalized tags as the JSON array in Policy.Tags
+func encodePolicyTags(tags []string) string {
+	encoded, _ := json.Marshal(tags)
+	return string(encoded)
+}
+
+// policyTags decodes a policy's tags, an empty list when it has none
+func policyTags(policy models.Policy) []string {
+	tags := []string{}
+	if policy.Tags != "" {
+		json.Unmarshal([]byte(policy.Tags), &tags)
+	}
+	return tags
+}
+
+// templatePolicyTags returns a template's tags, normalized for the policy deployed from it.
+// Tags that fail validation are dropped rather than failing the deploy.
+func templatePolicyTags(template models.PolicyTemplate) []string {
+	var raw []string
+	json.Unmarshal([]byte(template.Tags), &raw)
+	tags, err := normalizePolicyTags(raw)
+	if err != nil {
+		return []string{}
+	}
+	return tags
+}
+
+// wherePolicyTag narrows a policy query to the policies carrying tag, compared
+// case-insensitively as tags are stored normalized. An empty tag leaves the query as is.
+func wherePolicyTag(query *gorm.DB, tag string) *gorm.DB {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return query
+	}
+	// JSON containment rather than the ? operator, which GORM would take for a placeholder
+	return query.Where("NULLIF(tags, '')::jsonb @> ?::jsonb", encodePolicyTags([]string{tag}))
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	models "finopsbridge/api/internal/models_"
+)
+
+func TestNormalizePolicyTags(t *testing.T) {
+	got, err := normalizePolicyTags([]string{" Cost-Control ", "", "cost-control", "Team:Data", "  "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"cost-control", "team:data"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if got, err := normalizePolicyTags(nil); err != nil || got == nil || len(got) != 0 {
+		t.Errorf("no tags = %v, %v; want an empty list", got, err)
+	}
+}
+
+func TestNormalizePolicyTagsRejectsOversized(t *testing.T) {
+	if _, err := normalizePolicyTags([]string{strings.Repeat("a", maxPolicyTagLength+1)}); err == nil {
+		t.Error("a tag over the length limit should be rejected")
+	}
+
+	var tags []string
+	for i := 0; i <= maxPolicyTags; i++ {
+		tags = append(tags, fmt.Sprintf("tag-%d", i))
+	}
+	if _, err := normalizePolicyTags(tags); err == nil {
+		t.Error("more tags than the limit should be rejected")
+	}
+	// Duplicates don't count against the limit
+	if _, err := normalizePolicyTags(append(tags[:maxPolicyTags], "TAG-0")); err != nil {
+		t.Errorf("got %v for %d distinct tags", err, maxPolicyTags)
+	}
+}
+
+func TestPolicyTags(t *testing.T) {
+	if got := policyTags(models.Policy{Tags: `["finops","prod"]`}); !reflect.DeepEqual(got, []string{"finops", "prod"}) {
+		t.Errorf("got %v", got)
+	}
+	if got := policyTags(models.Policy{}); got == nil || len(got) != 0 {
+		t.Errorf("a policy without tags = %v, want an empty list", got)
+	}
+}
+
+func TestTemplatePolicyTags(t *testing.T) {
+	if got := templatePolicyTags(models.PolicyTemplate{Tags: `["Security", "security", "AI"]`}); !reflect.DeepEqual(got, []string{"security", "ai"}) {
+		t.Errorf("got %v, want normalized tags", got)
+	}
+	tooLong := fmt.Sprintf(`[%q]`, strings.Repeat("a", maxPolicyTagLength+1))
+	if got := templatePolicyTags(models.PolicyTemplate{Tags: tooLong}); got == nil || len(got) != 0 {
+		t.Errorf("invalid template tags = %v, want them dropped", got)
+	}
+}
+
+func TestWherePolicyTag(t *testing.T) {
+	h := dryRunHandlers(t)
+
+	sql := querySQL(wherePolicyTag(h.DB.Where("organization_id = ?", "org"), " FinOps "), &[]models.Policy{})
+	if want := `NULLIF(tags, '')::jsonb @> '["finops"]'::jsonb`; !strings.Contains(sql, want) {
+		t.Errorf("%s\nshould contain %s", sql, want)
+	}
+
+	sql = querySQL(wherePolicyTag(h.DB.Where("organization_id = ?", "org"), "  "), &[]models.Policy{})
+	if strings.Contains(sql, "tags") {
+		t.Errorf("%s\nshouldn't filter on a blank tag", sql)
+	}
+}
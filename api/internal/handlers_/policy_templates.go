@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 
 	models "finopsbridge/api/internal/models_"
+	opa "finopsbridge/api/internal/opa_"
+	worker "finopsbridge/api/internal/worker_"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -86,6 +88,7 @@ func (h *Handlers) DeployPolicyTemplate(c *fiber.Ctx) error {
 		Name        string                 `json:"name"`
 		Description string                 `json:"description"`
 		Config      map[string]interface{} `json:"config"`
+		Tags        []string               `json:"tags"` // Replaces the template's tags when set
 	}
 
 	var req DeployRequest
@@ -102,6 +105,22 @@ func (h *Handlers) DeployPolicyTemplate(c *fiber.Ctx) error {
 			"error": "Failed to merge configurations",
 		})
 	}
+	var mergedConfig map[string]interface{}
+	json.Unmarshal([]byte(configJSON), &mergedConfig)
+	if err := worker.ValidateRemediationWebhook(mergedConfig); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	tags := templatePolicyTags(template)
+	if req.Tags != nil {
+		if tags, err = normalizePolicyTags(req.Tags); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+	}
 
 	// Create new policy from template
 	policy := models.Policy{
@@ -112,7 +131,9 @@ func (h *Handlers) DeployPolicyTemplate(c *fiber.Ctx) error {
 		Enabled:        true,
 		Rego:           template.RegoTemplate,
 		Config:         configJSON,
+		Tags:           encodePolicyTags(tags),
 	}
+	policy.RegoPackage, _ = opa.ParsePackage(template.RegoTemplate)
 
 	if err := h.DB.Create(&policy).Error; err != nil {
 		return c.Status(500).JSON(fiber.Map{
@@ -120,13 +141,21 @@ func (h *Handlers) DeployPolicyTemplate(c *fiber.Ctx) error {
 		})
 	}
 
+	h.InvalidateDashboardStats(orgID)
+
 	// Increment template usage count
 	h.DB.Model(&template).Update("usage_count", template.UsageCount+1)
 
 	// Log activity
-	h.logActivity(orgID, "policy_created", "Created policy from template: "+template.Name, nil)
-
-	return c.Status(201).JSON(policy)
+	h.logActivity(c.UserContext(), orgID, "policy_created", "Created policy from template: "+template.Name, nil)
+
+	// Warn, without blocking the deploy, when the template's providers aren't connected
+	var applicable []string
+	json.Unmarshal([]byte(template.CloudProviders), &applicable)
+	return c.Status(201).JSON(struct {
+		models.Policy
+		Warning string `json:"warning,omitempty"`
+	}{policy, h.providerApplicabilityWarning(orgID, template.PolicyType, applicable)})
 }
 
 // Helper function to merge configurations
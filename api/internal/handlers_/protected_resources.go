@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	cloud "finopsbridge/api/internal/cloud_"
+	middleware "finopsbridge/api/internal/middleware_"
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// isCloudProviderType reports whether a cloud provider type can be connected, which a
+// protected resource can be limited to
+func isCloudProviderType(providerType string) bool {
+	_, ok := cloud.ProviderFor(providerType)
+	return ok
+}
+
+type protectedResourceRequest struct {
+	ResourceID    *string `json:"resourceId"`
+	CloudProvider *string `json:"cloudProvider"`
+	Reason        *string `json:"reason"`
+}
+
+// apply copies the fields set in the request onto a protected resource
+func (r protectedResourceRequest) apply(resource *models.ProtectedResource) {
+	if r.ResourceID != nil {
+		resource.ResourceID = strings.TrimSpace(*r.ResourceID)
+	}
+	if r.CloudProvider != nil {
+		resource.CloudProvider = strings.ToLower(strings.TrimSpace(*r.CloudProvider))
+	}
+	if r.Reason != nil {
+		resource.Reason = strings.TrimSpace(*r.Reason)
+	}
+}
+
+// validateProtectedResource checks a protected resource's fields and that the organization
+// doesn't already protect the same resource
+func (h *Handlers) validateProtectedResource(orgID string, resource models.ProtectedResource) error {
+	if resource.ResourceID == "" {
+		return errors.New("resourceId is required")
+	}
+	if resource.CloudProvider != "" && !isCloudProviderType(resource.CloudProvider) {
+		return fmt.Errorf("cloudProvider must be one of %s, or empty for any provider", strings.Join(cloud.ProviderTypes(), ", "))
+	}
+
+	var count int64
+	h.DB.Model(&models.ProtectedResource{}).
+		Where("organization_id = ? AND LOWER(resource_id) = ? AND cloud_provider = ? AND id <> ?",
+			orgID, strings.ToLower(resource.ResourceID), resource.CloudProvider, resource.ID).
+		Count(&count)
+	if count > 0 {
+		return errors.New("resource is already protected")
+	}
+	return nil
+}
+
+// ListProtectedResources returns the resources remediation never acts on
+func (h *Handlers) ListProtectedResources(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+
+	var resources []models.ProtectedResource
+	if err := h.DB.Where("organization_id = ?", orgID).Order("created_at").Find(&resources).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch protected resources",
+		})
+	}
+
+	return c.JSON(resources)
+}
+
+// CreateProtectedResource adds a resource to the protected list
+func (h *Handlers) CreateProtectedResource(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+
+	var req protectedResourceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	resource := models.ProtectedResource{OrganizationID: orgID, CreatedBy: middleware.GetUserID(c)}
+	req.apply(&resource)
+	if err := h.validateProtectedResource(orgID, resource); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	if err := h.DB.Create(&resource).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create protected resource",
+		})
+	}
+
+	h.logActivity(c.UserContext(), orgID, "protected_resource_created", fmt.Sprintf("Protected resource %s from remediation", resource.ResourceID), map[string]interface{}{
+		"protectedResourceId": resource.ID,
+		"userId":              middleware.GetUserID(c),
+	})
+
+	return c.Status(fiber.StatusCreated).JSON(resource)
+}
+
+// UpdateProtectedResource changes a protected resource's ID, provider or reason
+func (h *Handlers) UpdateProtectedResource(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+	id := c.Params("id")
+
+	var req protectedResourceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	var resource models.ProtectedResource
+	if err := h.DB.Where("id = ? AND organization_id = ?", id, orgID).First(&resource).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Protected resource not found",
+		})
+	}
+
+	req.apply(&resource)
+	if err := h.validateProtectedResource(orgID, resource); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	if err := h.DB.Save(&resource).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update protected resource",
+		})
+	}
+
+	h.logActivity(c.UserContext(), orgID, "protected_resource_updated", fmt.Sprintf("Protected resource %s was updated", resource.ResourceID), map[string]interface{}{
+		"protectedResourceId": resource.ID,
+		"userId":              middleware.GetUserID(c),
+	})
+
+	return c.JSON(resource)
+}
+
+// DeleteProtectedResource removes a resource from the protected list, so remediation can act
+// on it again
+func (h *Handlers) DeleteProtectedResource(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+	id := c.Params("id")
+
+	var resource models.ProtectedResource
+	if err := h.DB.Where("id = ? AND organization_id = ?", id, orgID).First(&resource).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Protected resource not found",
+		})
+	}
+
+	if err := h.DB.Delete(&resource).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete protected resource",
+		})
+	}
+
+	h.logActivity(c.UserContext(), orgID, "protected_resource_deleted", fmt.Sprintf("Resource %s is no longer protected from remediation", resource.ResourceID), map[string]interface{}{
+		"protectedResourceId": resource.ID,
+		"userId":              middleware.GetUserID(c),
+	})
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"testing"
+
+	models "finopsbridge/api/internal/models_"
+)
+
+func TestProtectedResourceRequestApply(t *testing.T) {
+	resourceID, provider := " i-0abc ", " AWS "
+	resource := models.ProtectedResource{Reason: "payments database"}
+
+	protectedResourceRequest{ResourceID: &resourceID, CloudProvider: &provider}.apply(&resource)
+
+	if resource.ResourceID != "i-0abc" || resource.CloudProvider != "aws" {
+		t.Errorf("got %+v", resource)
+	}
+	if resource.Reason != "payments database" {
+		t.Errorf("fields not in the request should be kept, got %+v", resource)
+	}
+}
+
+func TestValidateProtectedResource(t *testing.T) {
+	h := dryRunHandlers(t)
+
+	tests := []struct {
+		name     string
+		resource models.ProtectedResource
+		ok       bool
+	}{
+		{"any provider", models.ProtectedResource{ResourceID: "payments-db"}, true},
+		{"one provider", models.ProtectedResource{ResourceID: "i-0abc", CloudProvider: "aws"}, true},
+		{"no resource ID", models.ProtectedResource{CloudProvider: "aws"}, false},
+		{"unknown provider", models.ProtectedResource{ResourceID: "i-0abc", CloudProvider: "digitalocean"}, false},
+	}
+
+	for _, tt := range tests {
+		err := h.validateProtectedResource("org", tt.resource)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: got %v, want ok=%v", tt.name, err, tt.ok)
+		}
+	}
+}
+
+func TestIsCloudProviderType(t *testing.T) {
+	for _, providerType := range []string{"aws", "azure", "gcp", "oci", "ibm"} {
+		if !isCloudProviderType(providerType) {
+			t.Errorf("%s should be a cloud provider type", providerType)
+		}
+	}
+	for _, providerType := range []string{"", "AWS", "openai"} {
+		if isCloudProviderType(providerType) {
+			t.Errorf("%q shouldn't be a cloud provider type", providerType)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	models "finopsbridge/api/internal/models_"
+	policygen "finopsbridge/api/internal/policygen_"
+)
+
+// policyTypeProviders returns the provider types the templates of a policy type apply to, or
+// nil when no template has that type
+func (h *Handlers) policyTypeProviders(policyType string) []string {
+	var templates []models.PolicyTemplate
+	h.DB.Select("cloud_providers").Where("policy_type = ?", policyType).Find(&templates)
+
+	seen := make(map[string]bool)
+	var providers []string
+	for _, template := range templates {
+		var list []string
+		json.Unmarshal([]byte(template.CloudProviders), &list)
+		for _, provider := range list {
+			if !seen[provider] {
+				seen[provider] = true
+				providers = append(providers, provider)
+			}
+		}
+	}
+	return providers
+}
+
+// providerApplicabilityWarning warns that a policy applying to the given provider types likely
+// won't act on anything, as none of the organization's connected providers is of those types.
+// It returns "" when one is, when applicability is unknown, or when the policy also covers
+// something other than cloud providers, such as AI APIs or AI usage.
+func (h *Handlers) providerApplicabilityWarning(orgID, policyType string, applicable []string) string {
+	if len(applicable) == 0 || policygen.IsAIPolicyType(policyType) {
+		return ""
+	}
+	for _, provider := range applicable {
+		if !isCloudProviderType(provider) {
+			return ""
+		}
+	}
+
+	var connected []string
+	h.DB.Model(&models.CloudProvider{}).
+		Where("organization_id = ? AND status = ?", orgID, "connected").
+		Distinct().Pluck("type", &connected)
+	for _, provider := range connected {
+		for _, want := range applicable {
+			if provider == want {
+				return ""
+			}
+		}
+	}
+
+	applies := append([]string(nil), applicable...)
+	sort.Strings(applies)
+	if len(connected) == 0 {
+		return fmt.Sprintf("This policy applies to %s, but the organization has no connected cloud providers",
+			strings.Join(applies, ", "))
+	}
+	sort.Strings(connected)
+	return fmt.Sprintf("This policy applies to %s, but the organization's connected providers are %s",
+		strings.Join(applies, ", "), strings.Join(connected, ", "))
+}
@@ -0,0 +1,26 @@
+package handlers
+
+import "testing"
+
+func TestProviderApplicabilityWarning(t *testing.T) {
+	h := dryRunHandlers(t)
+
+	tests := []struct {
+		name       string
+		policyType string
+		applicable []string
+		want       string
+	}{
+		{"unknown applicability", "max_spend", nil, ""},
+		{"AI policy", "llm_token_budget", []string{"aws"}, ""},
+		{"covers AI APIs", "max_spend", []string{"aws", "openai"}, ""},
+		{"no connected providers", "idle_resources", []string{"gcp", "aws"},
+			"This policy applies to aws, gcp, but the organization has no connected cloud providers"},
+	}
+
+	for _, tt := range tests {
+		if got := h.providerApplicabilityWarning("org", tt.policyType, tt.applicable); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// providersApp serves the provider create and update routes for an organization
+func providersApp(h *Handlers) *fiber.App {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("orgID", "org")
+		return c.Next()
+	})
+	app.Post("/providers", h.CreateCloudProvider)
+	app.Patch("/providers/:id", h.UpdateCloudProvider)
+	return app
+}
+
+// missingFields sends a JSON request and returns its status and the missing fields it reports
+func missingFields(t *testing.T, app *fiber.App, method, path, body string) (int, []string) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result struct {
+		MissingFields []string `json:"missingFields"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+	return resp.StatusCode, result.MissingFields
+}
+
+func TestCreateCloudProviderRequiresCredentials(t *testing.T) {
+	h := dryRunHandlers(t)
+	var created int
+	h.DB.Callback().Create().Before("gorm:create").Register("test:count_creates", func(*gorm.DB) { created++ })
+
+	status, missing := missingFields(t, providersApp(h), "POST", "/providers",
+		`{"type": "aws", "name": "Prod", "credentials": {"useInstanceRole": false}}`)
+	if status != fiber.StatusBadRequest {
+		t.Errorf("status %d, want 400", status)
+	}
+	if want := []string{"roleArn or useInstanceRole"}; !reflect.DeepEqual(missing, want) {
+		t.Errorf("missingFields = %v, want %v", missing, want)
+	}
+	if created != 0 {
+		t.Errorf("got %d creates, want none", created)
+	}
+}
+
+func TestUpdateCloudProviderKeepsRequiredCredentials(t *testing.T) {
+	h := dryRunHandlers(t)
+	h.DB.Callback().Query().After("gorm:query").Register("test:stub_provider", func(tx *gorm.DB) {
+		if provider, ok := tx.Statement.Dest.(*models.CloudProvider); ok {
+			provider.ID, provider.Type, provider.Name = "cp1", "gcp", "Analytics"
+			provider.Credentials = `{"serviceAccountKey": "{}", "billingDataset": "billing"}`
+		}
+	})
+	var updates int
+	h.DB.Callback().Update().Before("gorm:update").Register("test:count_updates", func(*gorm.DB) { updates++ })
+
+	// Removing a field with null mustn't leave the provider unable to connect
+	status, missing := missingFields(t, providersApp(h), "PATCH", "/providers/cp1", `{"credentials": {"serviceAccountKey": null}}`)
+	if status != fiber.StatusBadRequest {
+		t.Errorf("status %d, want 400", status)
+	}
+	if want := []string{"serviceAccountKey"}; !reflect.DeepEqual(missing, want) {
+		t.Errorf("missingFields = %v, want %v", missing, want)
+	}
+	if updates != 0 {
+		t.Errorf("got %d updates, want none", updates)
+	}
+}
@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	cloud "finopsbridge/api/internal/cloud_"
+	middleware "finopsbridge/api/internal/middleware_"
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// accountIDPlaceholder is substituted with each member's account ID in a group's credential template
+const accountIDPlaceholder = "{accountId}"
+
+// CreateCloudProviderGroup creates a group and one connected provider per account ID.
+// Members are enforced by the worker like any other provider and report spend separately.
+func (h *Handlers) CreateCloudProviderGroup(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+	if orgID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Organization ID required",
+		})
+	}
+
+	var req struct {
+		Type               string                 `json:"type"`
+		Name               string                 `json:"name"`
+		CredentialTemplate map[string]interface{} `json:"credentialTemplate"`
+		AccountIDs         []string               `json:"accountIds"`
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if _, ok := cloud.AccountGroupProviderFor(req.Type); !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Provider groups support " + strings.Join(cloud.AccountGroupTypes(), ", "),
+		})
+	}
+	if req.Name == "" || len(req.AccountIDs) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "name and at least one account ID are required",
+		})
+	}
+
+	templateJSON, _ := json.Marshal(req.CredentialTemplate)
+	accountIDsJSON, _ := json.Marshal(req.AccountIDs)
+
+	group := models.CloudProviderGroup{
+		OrganizationID:     orgID,
+		Type:               req.Type,
+		Name:               req.Name,
+		CredentialTemplate: string(templateJSON),
+		AccountIDs:         string(accountIDsJSON),
+	}
+
+	members, err := expandProviderGroup(group, req.AccountIDs, time.Now())
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&group).Error; err != nil {
+			return err
+		}
+		for i := range members {
+			members[i].GroupID = group.ID
+		}
+		return tx.Create(&members).Error
+	}); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create cloud provider group",
+		})
+	}
+
+	h.logActivity(c.UserContext(), orgID, "cloud_group_connected",
+		fmt.Sprintf("Cloud provider group '%s' (%s) was connected with %d accounts", group.Name, group.Type, len(members)),
+		map[string]interface{}{
+			"groupId": group.ID,
+			"members": len(members),
+		})
+
+	return c.JSON(formatProviderGroup(group, members))
+}
+
+// ListCloudProviderGroupMembers returns a group and its member providers
+func (h *Handlers) ListCloudProviderGroupMembers(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+	id := c.Params("id")
+
+	var group models.CloudProviderGroup
+	if err := h.DB.Where("id = ? AND organization_id = ?", id, orgID).First(&group).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Cloud provider group not found",
+		})
+	}
+
+	var members []models.CloudProvider
+	if err := h.DB.Where("group_id = ? AND organization_id = ?", group.ID, orgID).Find(&members).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch group members",
+		})
+	}
+
+	return c.JSON(formatProviderGroup(group, members))
+}
+
+// expandProviderGroup builds one provider per account ID from the group's credential template
+func expandProviderGroup(group models.CloudProviderGroup, accountIDs []string, now time.Time) ([]models.CloudProvider, error) {
+	grouper, ok := cloud.AccountGroupProviderFor(group.Type)
+	if !ok {
+		return nil, fmt.Errorf("provider groups don't support %s providers", group.Type)
+	}
+
+	var template map[string]interface{}
+	if err := json.Unmarshal([]byte(group.CredentialTemplate), &template); err != nil {
+		return nil, fmt.Errorf("failed to read the group's credential template: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var members []models.CloudProvider
+
+	for _, accountID := range accountIDs {
+		accountID = strings.TrimSpace(accountID)
+		if accountID == "" {
+			return nil, fmt.Errorf("account IDs cannot be empty")
+		}
+		if seen[accountID] {
+			return nil, fmt.Errorf("duplicate account ID %s", accountID)
+		}
+		seen[accountID] = true
+
+		credentials, err := json.Marshal(substituteAccountID(template, accountID))
+		if err != nil {
+			return nil, err
+		}
+		connectedAt := now
+
+		member := models.CloudProvider{
+			OrganizationID: group.OrganizationID,
+			Type:           group.Type,
+			Name:           fmt.Sprintf("%s (%s)", group.Name, accountID),
+			Status:         "connected",
+			Credentials:    string(credentials),
+			ConnectedAt:    &connectedAt,
+		}
+
+		grouper.SetGroupAccount(&member, accountID)
+
+		members = append(members, member)
+	}
+
+	return members, nil
+}
+
+// substituteAccountID returns a copy of a parsed credential template with accountIDPlaceholder
+// replaced in every string value. Substituting after parsing keeps an account ID containing
+// quotes or backslashes from changing the credentials' JSON structure.
+func substituteAccountID(value interface{}, accountID string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return strings.ReplaceAll(v, accountIDPlaceholder, accountID)
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[key] = substituteAccountID(item, accountID)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = substituteAccountID(item, accountID)
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+func formatProviderGroup(group models.CloudProviderGroup, members []models.CloudProvider) map[string]interface{} {
+	totalSpend := 0.0
+	memberList := make([]map[string]interface{}, 0, len(members))
+	for _, member := range members {
+		totalSpend += member.MonthlySpend
+		memberList = append(memberList, map[string]interface{}{
+			"id":             member.ID,
+			"name":           member.Name,
+			"accountId":      member.AccountID,
+			"subscriptionId": member.SubscriptionID,
+			"projectId":      member.ProjectID,
+			"status":         member.Status,
+			"monthlySpend":   member.MonthlySpend,
+			"connectedAt":    member.ConnectedAt,
+		})
+	}
+
+	return map[string]interface{}{
+		"id":           group.ID,
+		"type":         group.Type,
+		"name":         group.Name,
+		"monthlySpend": totalSpend,
+		"members":      memberList,
+		"createdAt":    group.CreatedAt,
+	}
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+)
+
+func TestSubstituteAccountID(t *testing.T) {
+	var template map[string]interface{}
+	json.Unmarshal([]byte(`{
+		"roleArn": "arn:aws:iam::{accountId}:role/finops",
+		"regions": ["us-east-1", "{accountId}"],
+		"durationSeconds": 3600
+	}`), &template)
+
+	got := substituteAccountID(template, `12"34`).(map[string]interface{})
+	if got["roleArn"] != `arn:aws:iam::12"34:role/finops` {
+		t.Errorf("roleArn = %v", got["roleArn"])
+	}
+	if regions := got["regions"].([]interface{}); regions[1] != `12"34` {
+		t.Errorf("regions = %v", regions)
+	}
+	if got["durationSeconds"] != float64(3600) {
+		t.Errorf("non-string values should be kept, got %v", got["durationSeconds"])
+	}
+	if template["roleArn"] != "arn:aws:iam::{accountId}:role/finops" {
+		t.Error("the template must not be modified")
+	}
+}
+
+func TestExpandProviderGroup(t *testing.T) {
+	group := models.CloudProviderGroup{
+		OrganizationID:     "org",
+		Type:               "aws",
+		Name:               "Payments",
+		CredentialTemplate: `{"roleArn": "arn:aws:iam::{accountId}:role/finops"}`,
+	}
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	members, err := expandProviderGroup(group, []string{"111111111111", " 222222222222 "}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(members) != 2 {
+		t.Fatalf("got %d members, want 2", len(members))
+	}
+	member := members[1]
+	if member.Name != "Payments (222222222222)" || member.AccountID != "222222222222" || member.OrganizationID != "org" {
+		t.Errorf("got %+v", member)
+	}
+	if member.Credentials != `{"roleArn":"arn:aws:iam::222222222222:role/finops"}` {
+		t.Errorf("credentials = %s", member.Credentials)
+	}
+	if member.ConnectedAt == nil || !member.ConnectedAt.Equal(now) {
+		t.Errorf("connectedAt = %v", member.ConnectedAt)
+	}
+}
+
+func TestExpandProviderGroupRejectsInvalidAccounts(t *testing.T) {
+	group := models.CloudProviderGroup{Type: "aws", CredentialTemplate: `{}`}
+	for _, accountIDs := range [][]string{{"111111111111", "111111111111"}, {" "}} {
+		if _, err := expandProviderGroup(group, accountIDs, time.Now()); err == nil {
+			t.Errorf("%q should be rejected", accountIDs)
+		}
+	}
+
+	group.Type = "oci"
+	if _, err := expandProviderGroup(group, []string{"tenancy"}, time.Now()); err == nil {
+		t.Error("a provider type without account groups should be rejected")
+	}
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"errors"
+	"strconv"
+
+	cloud "finopsbridge/api/internal/cloud_"
+	middleware "finopsbridge/api/internal/middleware_"
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ListCloudProviderInstances returns one page of a provider's compute instances. maxResults
+// sets the page size (default 100, max 500) and pageToken, from the previous page's
+// nextPageToken, selects the page.
+func (h *Handlers) ListCloudProviderInstances(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+	id := c.Params("id")
+
+	var provider models.CloudProvider
+	if err := h.DB.Where("id = ? AND organization_id = ?", id, orgID).First(&provider).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Cloud provider not found",
+		})
+	}
+
+	opts := cloud.ListOptions{PageToken: c.Query("pageToken")}
+	if raw := c.Query("maxResults"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "maxResults must be a positive integer",
+			})
+		}
+		opts.MaxResults = parsed
+	}
+
+	instances, next, err := cloud.ListInstancePage(c.UserContext(), provider, h.Config, opts)
+	if errors.Is(err, cloud.ErrInventoryNotSupported) {
+		return c.Status(fiber.StatusNotImplemented).JSON(fiber.Map{
+			"error": "Instance listing is not supported for " + provider.Type + " providers",
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+			"error": "Failed to list instances: " + err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"providerId":    provider.ID,
+		"instances":     instances,
+		"nextPageToken": next,
+	})
+}
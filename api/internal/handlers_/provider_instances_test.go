@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestListCloudProviderInstances(t *testing.T) {
+	h := dryRunHandlers(t)
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("orgID", "org")
+		return c.Next()
+	})
+	app.Get("/providers/:id/instances", h.ListCloudProviderInstances)
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"?maxResults=abc", fiber.StatusBadRequest},
+		{"?maxResults=0", fiber.StatusBadRequest},
+		// The dry-run database finds a provider without a type, which can't be listed
+		{"?maxResults=10", fiber.StatusNotImplemented},
+		{"", fiber.StatusNotImplemented},
+	}
+	for _, tt := range tests {
+		resp, err := app.Test(httptest.NewRequest("GET", "/providers/p1/instances"+tt.query, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != tt.want {
+			t.Errorf("%q: status %d, want %d", tt.query, resp.StatusCode, tt.want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	middleware "finopsbridge/api/internal/middleware_"
+	models "finopsbridge/api/internal/models_"
+	policygen "finopsbridge/api/internal/policygen_"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// providerPolicy is an enabled policy the worker evaluates against a provider
+type providerPolicy struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	Type           string   `json:"type"`
+	Mode           string   `json:"mode"`
+	Description    string   `json:"description"`
+	CloudProviders []string `json:"cloudProviders"` // Provider types its templates apply to; null when no template has its type
+}
+
+// policiesForProviderType picks the policies that apply to a provider type. AI and budget
+// policies are evaluated per organization rather than per provider and never apply. Policies of
+// a type no template describes are kept, as the worker evaluates them against every provider.
+func policiesForProviderType(policies []models.Policy, providerType string, typeProviders func(policyType string) []string) []providerPolicy {
+	result := []providerPolicy{}
+	applicable := make(map[string][]string)
+	for _, policy := range policies {
+		if !policy.Enabled || policygen.IsAIPolicyType(policy.Type) || policy.Type == "budget_hierarchy" {
+			continue
+		}
+
+		providers, ok := applicable[policy.Type]
+		if !ok {
+			providers = typeProviders(policy.Type)
+			applicable[policy.Type] = providers
+		}
+		if providers != nil {
+			applies := false
+			for _, provider := range providers {
+				applies = applies || provider == providerType
+			}
+			if !applies {
+				continue
+			}
+		}
+
+		result = append(result, providerPolicy{
+			ID:             policy.ID,
+			Name:           policy.Name,
+			Type:           policy.Type,
+			Mode:           policy.Mode,
+			Description:    policy.Description,
+			CloudProviders: providers,
+		})
+	}
+	return result
+}
+
+// GetCloudProviderPolicies lists the enabled policies that apply to a provider, by name
+func (h *Handlers) GetCloudProviderPolicies(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+
+	var provider models.CloudProvider
+	if err := h.DB.Where("id = ? AND organization_id = ?", c.Params("id"), orgID).First(&provider).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Cloud provider not found",
+		})
+	}
+
+	var policies []models.Policy
+	if err := h.DB.Where("organization_id = ? AND enabled = ?", orgID, true).Order("name").Find(&policies).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch policies",
+		})
+	}
+
+	return c.JSON(policiesForProviderType(policies, provider.Type, h.policyTypeProviders))
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func TestPoliciesForProviderType(t *testing.T) {
+	policies := []models.Policy{
+		{ID: "p1", Name: "Spend", Type: "max_spend", Enabled: true},
+		{ID: "p2", Name: "Sizes", Type: "block_instance_type", Enabled: true},
+		{ID: "p3", Name: "Disabled", Type: "max_spend"},
+		{ID: "p4", Name: "Budgets", Type: "budget_hierarchy", Enabled: true},
+		{ID: "p5", Name: "Tokens", Type: "token_cost_anomaly", Enabled: true},
+		{ID: "p6", Name: "Custom", Type: "custom_rule", Enabled: true},
+		{ID: "p7", Name: "More spend", Type: "max_spend", Enabled: true},
+	}
+	lookups := make(map[string]int)
+	typeProviders := func(policyType string) []string {
+		lookups[policyType]++
+		switch policyType {
+		case "max_spend":
+			return []string{"aws", "gcp"}
+		case "block_instance_type":
+			return []string{"azure"}
+		}
+		return nil
+	}
+
+	var ids []string
+	for _, policy := range policiesForProviderType(policies, "aws", typeProviders) {
+		ids = append(ids, policy.ID)
+	}
+	if want := []string{"p1", "p6", "p7"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("got %v, want %v", ids, want)
+	}
+	if lookups["max_spend"] != 1 {
+		t.Errorf("looked up max_spend %d times, want once", lookups["max_spend"])
+	}
+
+	if got := policiesForProviderType(nil, "aws", typeProviders); got == nil || len(got) != 0 {
+		t.Errorf("got %v, want an empty list that encodes as []", got)
+	}
+}
+
+func TestGetCloudProviderPolicies(t *testing.T) {
+	h := dryRunHandlers(t)
+	h.DB.Callback().Query().After("gorm:query").Register("test:stub_policies", func(tx *gorm.DB) {
+		switch dest := tx.Statement.Dest.(type) {
+		case *models.CloudProvider:
+			dest.ID, dest.Type = "cp1", "aws"
+		case *[]models.Policy:
+			*dest = []models.Policy{{ID: "p1", Name: "Custom", Type: "custom_rule", Enabled: true}}
+		}
+	})
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("orgID", "org")
+		return c.Next()
+	})
+	app.Get("/providers/:id/policies", h.GetCloudProviderPolicies)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/providers/cp1/policies", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var policies []providerPolicy
+	if err := json.NewDecoder(resp.Body).Decode(&policies); err != nil {
+		t.Fatal(err)
+	}
+	// No template describes the type, so it applies to every provider
+	if resp.StatusCode != fiber.StatusOK || len(policies) != 1 || policies[0].ID != "p1" || policies[0].CloudProviders != nil {
+		t.Errorf("status %d, policies %+v", resp.StatusCode, policies)
+	}
+}
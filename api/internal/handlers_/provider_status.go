@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	cloud "finopsbridge/api/internal/cloud_"
+	middleware "finopsbridge/api/internal/middleware_"
+	models "finopsbridge/api/internal/models_"
+	policygen "finopsbridge/api/internal/policygen_"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Connectivity values reported by GetCloudProviderStatus
+const (
+	ConnectivityOK      = "ok"
+	ConnectivityError   = "error"
+	ConnectivityUnknown = "unknown" // No billing fetch has been attempted yet
+)
+
+// GetCloudProviderStatus returns a diagnostic view of a provider: the outcome of its latest
+// billing fetch, its spend, and how many enabled policies apply to it. With ?test=true the
+// connection is tested live first and the result recorded.
+func (h *Handlers) GetCloudProviderStatus(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+	id := c.Params("id")
+
+	var provider models.CloudProvider
+	if err := h.DB.Where("id = ? AND organization_id = ?", id, orgID).First(&provider).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Cloud provider not found",
+		})
+	}
+
+	if c.Query("test") == "true" {
+		err := cloud.TestConnection(c.UserContext(), provider, h.Config)
+		if !errors.Is(err, cloud.ErrBillingNotSupported) {
+			if err := h.DB.Model(&provider).Updates(cloud.SyncUpdates(nil, err, time.Now())).Error; err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error": "Failed to record connection test",
+				})
+			}
+		}
+	}
+
+	var policies []models.Policy
+	if err := h.DB.Where("organization_id = ? AND enabled = ?", orgID, true).Find(&policies).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch policies",
+		})
+	}
+
+	return c.JSON(providerStatus(provider, policies))
+}
+
+// providerStatus assembles the status of a provider from its recorded sync outcome and the
+// organization's enabled policies
+func providerStatus(provider models.CloudProvider, policies []models.Policy) fiber.Map {
+	connectivity := ConnectivityUnknown
+	switch {
+	case provider.LastSyncError != "":
+		connectivity = ConnectivityError
+	case provider.LastSyncAt != nil:
+		connectivity = ConnectivityOK
+	}
+
+	return fiber.Map{
+		"providerId":      provider.ID,
+		"name":            provider.Name,
+		"type":            provider.Type,
+		"status":          provider.Status,
+		"connectivity":    connectivity,
+		"lastSyncAt":      provider.LastSyncAt,
+		"lastAttemptAt":   provider.LastSyncAttemptAt,
+		"lastError":       provider.LastSyncError,
+		"lastWarning":     provider.LastSyncWarning,
+		"monthlySpend":    provider.MonthlySpend,
+		"enabledPolicies": countPoliciesAffecting(provider, policies),
+	}
+}
+
+// countPoliciesAffecting counts the enabled cloud policies evaluated for a provider. AI policies
+// apply to token usage instead, and max_spend policies scoped to another account are skipped.
+func countPoliciesAffecting(provider models.CloudProvider, policies []models.Policy) int {
+	count := 0
+	for _, policy := range policies {
+		if !policy.Enabled || policy.OrganizationID != provider.OrganizationID || policygen.IsAIPolicyType(policy.Type) {
+			continue
+		}
+		if policy.Type == "max_spend" {
+			var policyConfig map[string]interface{}
+			json.Unmarshal([]byte(policy.Config), &policyConfig)
+			if accountID, _ := policyConfig["accountId"].(string); accountID != "" && accountID != provider.AccountID {
+				continue
+			}
+		}
+		count++
+	}
+	return count
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+)
+
+func TestProviderStatusConnectivity(t *testing.T) {
+	syncedAt := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		provider models.CloudProvider
+		want     string
+	}{
+		{"never synced", models.CloudProvider{}, ConnectivityUnknown},
+		{"synced", models.CloudProvider{LastSyncAt: &syncedAt}, ConnectivityOK},
+		{"latest sync failed", models.CloudProvider{LastSyncAt: &syncedAt, LastSyncError: "access denied"}, ConnectivityError},
+	}
+
+	for _, tt := range tests {
+		status := providerStatus(tt.provider, nil)
+		if got := status["connectivity"]; got != tt.want {
+			t.Errorf("%s: connectivity = %v, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCountPoliciesAffecting(t *testing.T) {
+	provider := models.CloudProvider{OrganizationID: "org", AccountID: "111111111111"}
+	policies := []models.Policy{
+		{OrganizationID: "org", Enabled: true, Type: "idle_resources", Config: `{}`},
+		{OrganizationID: "org", Enabled: true, Type: "max_spend", Config: `{"maxSpend": 1000}`},
+		{OrganizationID: "org", Enabled: true, Type: "max_spend", Config: `{"accountId": "111111111111"}`},
+		{OrganizationID: "org", Enabled: true, Type: "max_spend", Config: `{"accountId": "222222222222"}`},
+		{OrganizationID: "org", Enabled: true, Type: "llm_token_budget", Config: `{}`},
+		{OrganizationID: "org", Enabled: false, Type: "idle_resources", Config: `{}`},
+		{OrganizationID: "other-org", Enabled: true, Type: "idle_resources", Config: `{}`},
+	}
+
+	if got := countPoliciesAffecting(provider, policies); got != 3 {
+		t.Errorf("countPoliciesAffecting = %d, want 3", got)
+	}
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	cloud "finopsbridge/api/internal/cloud_"
+	middleware "finopsbridge/api/internal/middleware_"
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// mergeCredentials applies credential updates to a provider's stored credentials JSON. Fields
+// not in updates are kept, and a field set to null is removed.
+func mergeCredentials(stored string, updates map[string]interface{}) (string, error) {
+	credentials := make(map[string]interface{})
+	if stored != "" {
+		if err := json.Unmarshal([]byte(stored), &credentials); err != nil {
+			return "", err
+		}
+	}
+	for key, value := range updates {
+		if value == nil {
+			delete(credentials, key)
+			continue
+		}
+		credentials[key] = value
+	}
+	merged, err := json.Marshal(credentials)
+	return string(merged), err
+}
+
+// UpdateCloudProvider renames a provider or rotates some of its credentials in place, keeping
+// its ID, violations and history. Changed credentials are tested with a fresh billing fetch and
+// only saved when it succeeds; providers without billing support are saved untested.
+func (h *Handlers) UpdateCloudProvider(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+	id := c.Params("id")
+
+	var req struct {
+		Name        *string                `json:"name"`
+		Credentials map[string]interface{} `json:"credentials"`
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	var provider models.CloudProvider
+	if err := h.DB.Where("id = ? AND organization_id = ?", id, orgID).First(&provider).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Cloud provider not found",
+		})
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "name can't be empty",
+			})
+		}
+		provider.Name = name
+	}
+
+	updates := map[string]interface{}{"name": provider.Name}
+	if len(req.Credentials) > 0 {
+		// An MFA code only starts a session and is never stored
+		tokenCode := ""
+		if provider.Type == "aws" {
+			tokenCode = cloud.TakeAWSTokenCode(req.Credentials)
+			req.Credentials["tokenCode"] = nil
+		}
+
+		credentials, err := mergeCredentials(provider.Credentials, req.Credentials)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to read stored credentials",
+			})
+		}
+		provider.Credentials = credentials
+
+		// Removing a field with null mustn't leave the provider unable to connect
+		var merged map[string]interface{}
+		json.Unmarshal([]byte(credentials), &merged)
+		if missing := cloud.MissingCredentials(provider.Type, merged); len(missing) > 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":         "Missing required " + provider.Type + " credentials: " + strings.Join(missing, ", "),
+				"missingFields": missing,
+			})
+		}
+
+		if tokenCode != "" {
+			if err := cloud.StartAWSMFASession(provider, h.Config, tokenCode); err != nil {
+				return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+					"error": "MFA session failed: " + err.Error(),
+				})
+			}
+		}
+
+		now := time.Now()
+		err = cloud.TestConnection(c.UserContext(), provider, h.Config)
+		if err != nil && !errors.Is(err, cloud.ErrBillingNotSupported) {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+				"error": "Connection test failed with the new credentials: " + err.Error(),
+			})
+		}
+		if err == nil {
+			for column, value := range cloud.SyncUpdates(nil, nil, now) {
+				updates[column] = value
+			}
+		}
+		provider.Status = "connected"
+		updates["credentials"] = provider.Credentials
+		updates["status"] = provider.Status
+	}
+
+	if err := h.DB.Model(&provider).Updates(updates).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update cloud provider",
+		})
+	}
+
+	h.InvalidateDashboardStats(orgID)
+
+	metadata := map[string]interface{}{
+		"providerId": provider.ID,
+		"userId":     middleware.GetUserID(c),
+	}
+	if len(req.Credentials) > 0 {
+		// Record which fields were rotated, never their values
+		fields := make([]string, 0, len(req.Credentials))
+		for key := range req.Credentials {
+			fields = append(fields, key)
+		}
+		sort.Strings(fields)
+		metadata["credentialFields"] = fields
+	}
+	h.logActivity(c.UserContext(), orgID, "cloud_updated", "Cloud provider '"+provider.Name+"' ("+provider.Type+") was updated", metadata)
+
+	return c.JSON(map[string]interface{}{
+		"id":             provider.ID,
+		"type":           provider.Type,
+		"name":           provider.Name,
+		"accountId":      provider.AccountID,
+		"subscriptionId": provider.SubscriptionID,
+		"projectId":      provider.ProjectID,
+		"status":         provider.Status,
+		"monthlySpend":   provider.MonthlySpend,
+		"connectedAt":    provider.ConnectedAt,
+	})
+}
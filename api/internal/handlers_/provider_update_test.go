@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	cloud "finopsbridge/api/internal/cloud_"
+	config "finopsbridge/api/internal/config_"
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func TestMergeCredentials(t *testing.T) {
+	stored := `{"accessKeyId":"AKIAOLD","secretAccessKey":"old-secret","roleArn":"arn:aws:iam::123456789012:role/finops"}`
+
+	got, err := mergeCredentials(stored, map[string]interface{}{
+		"accessKeyId":     "AKIANEW",
+		"secretAccessKey": "new-secret",
+		"roleArn":         nil,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"accessKeyId":"AKIANEW","secretAccessKey":"new-secret"}`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestMergeCredentialsKeepsUnchangedFields(t *testing.T) {
+	got, err := mergeCredentials(`{"serviceAccountKey":"{}","billingDataset":"billing"}`, map[string]interface{}{
+		"billingTable": "gcp_billing_export_v1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"billingDataset":"billing","billingTable":"gcp_billing_export_v1","serviceAccountKey":"{}"}`; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	if got, err := mergeCredentials("", map[string]interface{}{"apiKey": "k"}); err != nil || got != `{"apiKey":"k"}` {
+		t.Errorf("empty stored credentials: got %s, %v", got, err)
+	}
+	if _, err := mergeCredentials("not json", nil); err == nil {
+		t.Error("invalid stored credentials should fail")
+	}
+}
+
+// billingProvider is a provider type whose billing fetch returns err
+type billingProvider struct {
+	err error
+}
+
+func (p billingProvider) RequiredCredentials() []string { return []string{"apiKey"} }
+
+func (p billingProvider) FetchBilling(ctx context.Context, provider models.CloudProvider, cfg *config.Config) (map[string]interface{}, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return map[string]interface{}{"monthlySpend": 10.0}, nil
+}
+
+func (p billingProvider) ListInstancePage(ctx context.Context, provider models.CloudProvider, cfg *config.Config, opts cloud.ListOptions) ([]cloud.Instance, string, error) {
+	return nil, "", cloud.ErrInventoryNotSupported
+}
+
+func (p billingProvider) StopInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, opts cloud.RemediationOptions) (cloud.RemediationResult, error) {
+	return cloud.RemediationResult{}, nil
+}
+
+func (p billingProvider) TerminateInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, maxSizeLevel int, maxHourlyPrice float64, opts cloud.RemediationOptions) (cloud.RemediationResult, error) {
+	return cloud.RemediationResult{}, nil
+}
+
+func (p billingProvider) StopIdleInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, idleHoursThreshold float64, opts cloud.RemediationOptions) (cloud.RemediationResult, error) {
+	return cloud.RemediationResult{}, nil
+}
+
+// rotateCredentials stubs a connected provider of a type registered for the test, whose
+// connection test fails with billingErr, and rotates its API key. It returns the response
+// status and the column updates saved.
+func rotateCredentials(t *testing.T, billingErr error) (int, []map[string]interface{}) {
+	t.Helper()
+	providerType := "stub-" + t.Name()
+	cloud.RegisterProvider(providerType, billingProvider{err: billingErr})
+
+	h := dryRunHandlers(t)
+	h.DB.Callback().Query().After("gorm:query").Register("test:stub_provider", func(tx *gorm.DB) {
+		if provider, ok := tx.Statement.Dest.(*models.CloudProvider); ok {
+			provider.ID, provider.Type, provider.Name = "cp-"+t.Name(), providerType, "Billing"
+			provider.Credentials = `{"apiKey": "old-key"}`
+			provider.Status = "error"
+		}
+	})
+	var saved []map[string]interface{}
+	h.DB.Callback().Update().Before("gorm:update").Register("test:record_updates", func(tx *gorm.DB) {
+		if updates, ok := tx.Statement.Dest.(map[string]interface{}); ok {
+			saved = append(saved, updates)
+		}
+	})
+
+	req := httptest.NewRequest("PATCH", "/providers/cp1", strings.NewReader(`{"credentials": {"apiKey": "new-key"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := providersApp(h).Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, saved
+}
+
+func TestUpdateCloudProviderRejectsFailingCredentials(t *testing.T) {
+	status, saved := rotateCredentials(t, errors.New("invalid API key"))
+
+	if status != fiber.StatusUnprocessableEntity {
+		t.Errorf("status %d, want 422", status)
+	}
+	// The stored credentials and status stay as they were
+	if len(saved) != 0 {
+		t.Errorf("saved %v, want nothing", saved)
+	}
+}
+
+func TestUpdateCloudProviderSavesTestedCredentials(t *testing.T) {
+	status, saved := rotateCredentials(t, nil)
+
+	if status != fiber.StatusOK {
+		t.Fatalf("status %d, want 200", status)
+	}
+	if len(saved) != 1 {
+		t.Fatalf("got %d updates, want 1", len(saved))
+	}
+	updates := saved[0]
+	if updates["credentials"] != `{"apiKey":"new-key"}` || updates["status"] != "connected" {
+		t.Errorf("saved credentials %v with status %v", updates["credentials"], updates["status"])
+	}
+	if updates["last_sync_error"] != "" || updates["last_sync_at"] == nil {
+		t.Errorf("updates %v should record the successful sync", updates)
+	}
+}
+
+func TestUpdateCloudProviderSavesUntestableCredentials(t *testing.T) {
+	status, saved := rotateCredentials(t, cloud.ErrBillingNotSupported)
+
+	if status != fiber.StatusOK || len(saved) != 1 {
+		t.Fatalf("status %d with %d updates, want 200 and the credentials saved", status, len(saved))
+	}
+	if _, synced := saved[0]["last_sync_at"]; synced {
+		t.Errorf("updates %v shouldn't record a sync that didn't happen", saved[0])
+	}
+}
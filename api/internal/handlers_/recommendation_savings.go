@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	middleware "finopsbridge/api/internal/middleware_"
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// savingsOverlapGroups are policy types whose estimated savings come out of the same spend.
+// Stopping an idle instance leaves nothing to rightsize or reserve, so savings within a group
+// don't add up.
+var savingsOverlapGroups = map[string]string{
+	"auto_stop_idle":              "compute",
+	"scheduled_start_stop":        "compute",
+	"rightsizing":                 "compute",
+	"block_instance_type":         "compute",
+	"reserved_instance":           "compute",
+	"spot_instances_for_training": "compute",
+}
+
+// savingsGroup is the combined savings of the recommendations in one overlap group
+type savingsGroup struct {
+	Group             string   `json:"group"`
+	PolicyTypes       []string `json:"policyTypes"`
+	RecommendationIDs []string `json:"recommendationIds"`
+	GrossSavings      float64  `json:"grossMonthlySavings"`
+	Savings           float64  `json:"estimatedMonthlySavings"`
+}
+
+// recommendationSavings is one recommendation's estimated monthly savings and policy type
+type recommendationSavings struct {
+	ID         string
+	PolicyType string
+	Savings    float64
+}
+
+// combineSavings combines savings estimated independently against the same monthly spend.
+// Each applies to what the others leave, so shares compound: 15% and 25% of a spend save
+// 1 - 0.85*0.75 = 36.25% of it together, not 40%. Without a known spend to take shares of,
+// the largest estimate is the only one counted.
+func combineSavings(spend float64, savings []float64) float64 {
+	if len(savings) == 0 {
+		return 0
+	}
+	if spend <= 0 {
+		largest := 0.0
+		for _, s := range savings {
+			largest = math.Max(largest, s)
+		}
+		return largest
+	}
+
+	remaining := 1.0
+	for _, s := range savings {
+		share := math.Min(math.Max(s/spend, 0), 1)
+		remaining *= 1 - share
+	}
+	return spend * (1 - remaining)
+}
+
+// summarizeSavings groups recommendations by overlap group and combines each group's savings.
+// Policy types outside any group are counted in full, each in a group of its own.
+func summarizeSavings(spend float64, recs []recommendationSavings) []savingsGroup {
+	byGroup := make(map[string][]recommendationSavings)
+	for _, rec := range recs {
+		group, ok := savingsOverlapGroups[rec.PolicyType]
+		if !ok {
+			group = rec.PolicyType
+		}
+		byGroup[group] = append(byGroup[group], rec)
+	}
+
+	groups := make([]savingsGroup, 0, len(byGroup))
+	for name, members := range byGroup {
+		group := savingsGroup{Group: name, PolicyTypes: []string{}, RecommendationIDs: []string{}}
+		seenTypes := make(map[string]bool)
+		estimates := make([]float64, 0, len(members))
+		for _, rec := range members {
+			group.RecommendationIDs = append(group.RecommendationIDs, rec.ID)
+			group.GrossSavings += rec.Savings
+			estimates = append(estimates, rec.Savings)
+			if !seenTypes[rec.PolicyType] {
+				seenTypes[rec.PolicyType] = true
+				group.PolicyTypes = append(group.PolicyTypes, rec.PolicyType)
+			}
+		}
+		sort.Strings(group.PolicyTypes)
+		group.GrossSavings = math.Round(group.GrossSavings*100) / 100
+		group.Savings = math.Round(combineSavings(spend, estimates)*100) / 100
+		groups = append(groups, group)
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Savings != groups[j].Savings {
+			return groups[i].Savings > groups[j].Savings
+		}
+		return groups[i].Group < groups[j].Group
+	})
+	return groups
+}
+
+// dedupeRecommendations keeps one recommendation per policy template, the one with the largest
+// estimate, so a template recommended more than once isn't counted twice
+func dedupeRecommendations(recommendations []models.PolicyRecommendation) []models.PolicyRecommendation {
+	byTemplate := make(map[string]int)
+	deduped := make([]models.PolicyRecommendation, 0, len(recommendations))
+	for _, rec := range recommendations {
+		i, seen := byTemplate[rec.PolicyTemplateID]
+		if !seen {
+			byTemplate[rec.PolicyTemplateID] = len(deduped)
+			deduped = append(deduped, rec)
+			continue
+		}
+		if rec.EstimatedMonthlySavings > deduped[i].EstimatedMonthlySavings {
+			deduped[i] = rec
+		}
+	}
+	return deduped
+}
+
+// GetRecommendationSavingsSummary estimates the monthly savings of deploying the organization's
+// pending recommendations, or only those listed in ?ids= (comma-separated). Recommendations
+// whose savings overlap, such as stopping idle instances and rightsizing them, are combined
+// rather than added, and a template recommended more than once is counted once. Spend and
+// savings are in the reporting currency.
+func (h *Handlers) GetRecommendationSavingsSummary(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+	if orgID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Organization ID required",
+		})
+	}
+
+	query := h.DB.Where("organization_id = ? AND status = ?", orgID, "pending")
+	if raw := c.Query("ids"); raw != "" {
+		var ids []string
+		for _, id := range strings.Split(raw, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				ids = append(ids, id)
+			}
+		}
+		query = query.Where("id IN ?", ids)
+	}
+
+	var recommendations []models.PolicyRecommendation
+	if err := query.Find(&recommendations).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch recommendations",
+		})
+	}
+
+	recommendations = dedupeRecommendations(recommendations)
+
+	templateIDs := make([]string, 0, len(recommendations))
+	for _, rec := range recommendations {
+		templateIDs = append(templateIDs, rec.PolicyTemplateID)
+	}
+	var templates []models.PolicyTemplate
+	if len(templateIDs) > 0 {
+		if err := h.DB.Where("id IN ?", templateIDs).Find(&templates).Error; err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to fetch policy templates",
+			})
+		}
+	}
+	policyTypes := make(map[string]string, len(templates))
+	for _, template := range templates {
+		policyTypes[template.ID] = template.PolicyType
+	}
+
+	// Recommendations estimate savings as shares of the spend they were generated from
+	var providers []models.CloudProvider
+	if err := h.DB.Where("organization_id = ? AND status = ?", orgID, "connected").Find(&providers).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch cloud providers",
+		})
+	}
+	unconverted := make(map[string]bool)
+	spend, _ := h.reportingSpend(providers, unconverted)
+
+	recs := make([]recommendationSavings, 0, len(recommendations))
+	for _, rec := range recommendations {
+		recs = append(recs, recommendationSavings{
+			ID:         rec.ID,
+			PolicyType: policyTypes[rec.PolicyTemplateID],
+			Savings:    rec.EstimatedMonthlySavings,
+		})
+	}
+	groups := summarizeSavings(spend, recs)
+
+	gross, net := 0.0, 0.0
+	for _, group := range groups {
+		gross += group.GrossSavings
+		net += group.Savings
+	}
+	gross = math.Round(gross*100) / 100
+	net = math.Round(net*100) / 100
+
+	return c.JSON(fiber.Map{
+		"recommendationCount":     len(recommendations),
+		"monthlySpend":            math.Round(spend*100) / 100,
+		"grossMonthlySavings":     gross,
+		"overlapMonthlySavings":   math.Round((gross-net)*100) / 100,
+		"estimatedMonthlySavings": net,
+		"currency":                h.Config.ReportingCurrency,
+		"unconvertedCurrencies":   sortedCurrencies(unconverted),
+		"groups":                  groups,
+	})
+}
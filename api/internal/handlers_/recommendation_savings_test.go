@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	models "finopsbridge/api/internal/models_"
+)
+
+func TestCombineSavings(t *testing.T) {
+	tests := []struct {
+		name    string
+		spend   float64
+		savings []float64
+		want    float64
+	}{
+		{"none", 1000, nil, 0},
+		{"one", 1000, []float64{150}, 150},
+		{"compounding", 1000, []float64{150, 250}, 362.5},
+		{"capped at the spend", 1000, []float64{1500, 100}, 1000},
+		{"negative ignored", 1000, []float64{-50, 100}, 100},
+		{"unknown spend", 0, []float64{150, 250, 100}, 250},
+	}
+	for _, tt := range tests {
+		if got := combineSavings(tt.spend, tt.savings); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSummarizeSavings(t *testing.T) {
+	recs := []recommendationSavings{
+		{ID: "r1", PolicyType: "auto_stop_idle", Savings: 150},
+		{ID: "r2", PolicyType: "rightsizing", Savings: 250},
+		{ID: "r3", PolicyType: "require_tags", Savings: 20},
+		{ID: "r4", PolicyType: "rightsizing", Savings: 0},
+	}
+
+	got := summarizeSavings(1000, recs)
+	want := []savingsGroup{
+		{Group: "compute", PolicyTypes: []string{"auto_stop_idle", "rightsizing"}, RecommendationIDs: []string{"r1", "r2", "r4"}, GrossSavings: 400, Savings: 362.5},
+		{Group: "require_tags", PolicyTypes: []string{"require_tags"}, RecommendationIDs: []string{"r3"}, GrossSavings: 20, Savings: 20},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+
+	if got := summarizeSavings(1000, nil); got == nil || len(got) != 0 {
+		t.Errorf("got %#v, want an empty list", got)
+	}
+}
+
+func TestDedupeRecommendations(t *testing.T) {
+	recommendations := []models.PolicyRecommendation{
+		{ID: "r1", PolicyTemplateID: "idle", EstimatedMonthlySavings: 100},
+		{ID: "r2", PolicyTemplateID: "tags", EstimatedMonthlySavings: 10},
+		{ID: "r3", PolicyTemplateID: "idle", EstimatedMonthlySavings: 300},
+		{ID: "r4", PolicyTemplateID: "idle", EstimatedMonthlySavings: 200},
+	}
+
+	got := dedupeRecommendations(recommendations)
+	if len(got) != 2 || got[0].ID != "r3" || got[1].ID != "r2" {
+		t.Errorf("got %+v, want the largest idle estimate in its first position, then tags", got)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"math"
 	"time"
 
+	middleware "finopsbridge/api/internal/middleware_"
 	models "finopsbridge/api/internal/models_"
 
 	"github.com/gofiber/fiber/v2"
@@ -35,11 +36,20 @@ func (h *Handlers) GenerateRecommendations(c *fiber.Ctx) error {
 		existingPolicyTypes[p.Type] = true
 	}
 
-	// Delete old pending recommendations
-	h.DB.Where("organization_id = ? AND status = ?", orgID, "pending").Delete(&models.PolicyRecommendation{})
+	now := time.Now()
+
+	// Snoozed recommendations are kept, and their templates not recommended again, until
+	// the snooze expires
+	var snoozed []models.PolicyRecommendation
+	h.DB.Where("organization_id = ? AND status = ?", orgID, "snoozed").Find(&snoozed)
+	snoozedTemplates := activeSnoozes(snoozed, now)
+
+	// Delete old pending recommendations and expired snoozes
+	h.DB.Where("organization_id = ? AND (status = ? OR (status = ? AND snoozed_until <= ?))", orgID, "pending", "snoozed", now).
+		Delete(&models.PolicyRecommendation{})
 
 	// Analyze and generate recommendations
-	recommendations := h.analyzeAndRecommend(orgID, providers, existingPolicyTypes)
+	recommendations := h.analyzeAndRecommend(orgID, providers, existingPolicyTypes, snoozedTemplates)
 
 	// Save recommendations to database
 	for _, rec := range recommendations {
@@ -47,13 +57,13 @@ func (h *Handlers) GenerateRecommendations(c *fiber.Ctx) error {
 	}
 
 	// Log activity
-	h.logActivity(orgID, "recommendations_generated", fmt.Sprintf("Generated %d policy recommendations", len(recommendations)), nil)
+	h.logActivity(c.UserContext(), orgID, "recommendations_generated", fmt.Sprintf("Generated %d policy recommendations", len(recommendations)), nil)
 
 	return c.JSON(recommendations)
 }
 
 // analyzeAndRecommend performs analysis and returns recommendations
-func (h *Handlers) analyzeAndRecommend(orgID string, providers []models.CloudProvider, existingPolicyTypes map[string]bool) []models.PolicyRecommendation {
+func (h *Handlers) analyzeAndRecommend(orgID string, providers []models.CloudProvider, existingPolicyTypes, snoozedTemplates map[string]bool) []models.PolicyRecommendation {
 	var recommendations []models.PolicyRecommendation
 	totalSpend := 0.0
 
@@ -66,14 +76,23 @@ func (h *Handlers) analyzeAndRecommend(orgID string, providers []models.CloudPro
 	var templates []models.PolicyTemplate
 	h.DB.Find(&templates)
 
+	// Training workloads decide whether spot recommendations need checkpointing first
+	var workloads []models.AIWorkload
+	h.DB.Where("organization_id = ? AND workload_type = ? AND status = ?", orgID, "training", "active").Find(&workloads)
+	spot := assessSpotTraining(workloads, time.Now())
+
 	// Rule-based recommendation engine
 	for _, template := range templates {
 		// Skip if policy already exists
 		if existingPolicyTypes[template.PolicyType] {
 			continue
 		}
+		// Skip if the user snoozed this recommendation
+		if snoozedTemplates[template.ID] {
+			continue
+		}
 
-		confidence, savings, reason, issues := h.evaluateTemplate(template, providers, totalSpend)
+		confidence, savings, reason, issues := h.evaluateTemplate(template, providers, totalSpend, spot)
 
 		if confidence > 0.3 { // Only recommend if confidence > 30%
 			priority := "low"
@@ -86,7 +105,7 @@ func (h *Handlers) analyzeAndRecommend(orgID string, providers []models.CloudPro
 			}
 
 			// Prepare suggested config based on analysis
-			suggestedConfig := h.generateSuggestedConfig(template, providers, totalSpend)
+			suggestedConfig := h.generateSuggestedConfig(template, providers, totalSpend, spot)
 			configJSON, _ := json.Marshal(suggestedConfig)
 
 			// Prepare detected issues
@@ -111,8 +130,15 @@ func (h *Handlers) analyzeAndRecommend(orgID string, providers []models.CloudPro
 	return recommendations
 }
 
+// overSpendThreshold reports whether monthly spend is above the configured recommendation gate
+// for a policy type. Types without a gate always pass.
+func (h *Handlers) overSpendThreshold(policyType string, totalSpend float64) bool {
+	threshold, ok := h.Config.RecommendationSpendThresholds[policyType]
+	return !ok || totalSpend > threshold
+}
+
 // evaluateTemplate determines if a template is recommended
-func (h *Handlers) evaluateTemplate(template models.PolicyTemplate, providers []models.CloudProvider, totalSpend float64) (float64, float64, string, []string) {
+func (h *Handlers) evaluateTemplate(template models.PolicyTemplate, providers []models.CloudProvider, totalSpend float64, spot spotTrainingAssessment) (float64, float64, string, []string) {
 	var confidence float64
 	var savings float64
 	var reason string
@@ -120,8 +146,8 @@ func (h *Handlers) evaluateTemplate(template models.PolicyTemplate, providers []
 
 	switch template.PolicyType {
 	case "max_spend":
-		// Always recommend budget control if spend > $1000/month
-		if totalSpend > 1000 {
+		// Always recommend budget control above the spend threshold (default $1000/month)
+		if h.overSpendThreshold(template.PolicyType, totalSpend) {
 			confidence = 0.95
 			savings = totalSpend * 0.05 // 5% from awareness
 			reason = fmt.Sprintf("Your organization spends $%.2f/month. A budget policy prevents unexpected overages and promotes cost awareness.", totalSpend)
@@ -146,7 +172,7 @@ func (h *Handlers) evaluateTemplate(template models.PolicyTemplate, providers []
 
 	case "block_instance_type":
 		// Recommend if high monthly spend (likely has oversized instances)
-		if totalSpend > 5000 {
+		if h.overSpendThreshold(template.PolicyType, totalSpend) {
 			confidence = 0.75
 			savings = totalSpend * 0.10 // 10% from preventing oversized instances
 			reason = "Prevent teams from deploying unnecessarily large instances. Organizations typically see 10-20% savings by rightsizing."
@@ -171,7 +197,7 @@ func (h *Handlers) evaluateTemplate(template models.PolicyTemplate, providers []
 
 	case "rightsizing":
 		// Recommend for organizations with significant spend
-		if totalSpend > 3000 {
+		if h.overSpendThreshold(template.PolicyType, totalSpend) {
 			confidence = 0.85
 			savings = totalSpend * 0.25 // 25% from rightsizing
 			reason = "Analyze actual CPU/memory utilization and recommend optimal instance sizes. Typical savings: 20-35% of compute costs."
@@ -187,7 +213,7 @@ func (h *Handlers) evaluateTemplate(template models.PolicyTemplate, providers []
 
 	case "backup_enforcement":
 		// Recommend for production environments
-		if totalSpend > 2000 {
+		if h.overSpendThreshold(template.PolicyType, totalSpend) {
 			confidence = 0.70
 			savings = 0 // DR/compliance benefit
 			reason = "Automate backup policies for critical databases and storage. Prevents data loss and ensures business continuity."
@@ -196,13 +222,17 @@ func (h *Handlers) evaluateTemplate(template models.PolicyTemplate, providers []
 
 	case "reserved_instance":
 		// Recommend if significant steady-state workload
-		if totalSpend > 5000 {
+		if h.overSpendThreshold(template.PolicyType, totalSpend) {
 			confidence = 0.80
 			savings = totalSpend * 0.30 // 30% from RIs/Savings Plans
 			reason = "Convert steady-state workloads to Reserved Instances or Savings Plans for 30-60% savings on compute."
 			issues = []string{"High on-demand compute costs", "Missing commitment-based discounts"}
 		}
 
+	case "spot_instances_for_training":
+		// Graduated on whether training workloads checkpoint
+		confidence, savings, reason, issues = spot.recommendation()
+
 	default:
 		// Default low confidence for other templates
 		confidence = 0.35
@@ -215,7 +245,7 @@ func (h *Handlers) evaluateTemplate(template models.PolicyTemplate, providers []
 }
 
 // generateSuggestedConfig creates a suggested configuration for a template
-func (h *Handlers) generateSuggestedConfig(template models.PolicyTemplate, providers []models.CloudProvider, totalSpend float64) map[string]interface{} {
+func (h *Handlers) generateSuggestedConfig(template models.PolicyTemplate, providers []models.CloudProvider, totalSpend float64, spot spotTrainingAssessment) map[string]interface{} {
 	config := make(map[string]interface{})
 
 	switch template.PolicyType {
@@ -268,17 +298,37 @@ func (h *Handlers) generateSuggestedConfig(template models.PolicyTemplate, provi
 		config["minUtilization"] = 0.75
 		config["commitmentTerm"] = "1-year"
 		config["paymentOption"] = "no-upfront"
+
+	case "spot_instances_for_training":
+		config = spot.suggestedConfig()
 	}
 
 	return config
 }
 
-// ListRecommendations returns policy recommendations for an organization
+// activeSnoozes returns the template IDs of recommendations snoozed past now
+func activeSnoozes(recommendations []models.PolicyRecommendation, now time.Time) map[string]bool {
+	templates := make(map[string]bool)
+	for _, rec := range recommendations {
+		if rec.Status == "snoozed" && rec.SnoozedUntil != nil && rec.SnoozedUntil.After(now) {
+			templates[rec.PolicyTemplateID] = true
+		}
+	}
+	return templates
+}
+
+// ListRecommendations returns policy recommendations for an organization. Snoozed
+// recommendations are hidden until their snooze expires, unless ?includeSnoozed=true.
 func (h *Handlers) ListRecommendations(c *fiber.Ctx) error {
 	orgID := c.Locals("orgId").(string)
 
+	query := h.DB.Where("organization_id = ?", orgID)
+	if c.Query("includeSnoozed") != "true" {
+		query = query.Where("NOT (status = ? AND snoozed_until > ?)", "snoozed", time.Now())
+	}
+
 	var recommendations []models.PolicyRecommendation
-	if err := h.DB.Where("organization_id = ?", orgID).
+	if err := query.
 		Order("priority DESC, confidence_score DESC").
 		Find(&recommendations).Error; err != nil {
 		return c.Status(500).JSON(fiber.Map{
@@ -321,7 +371,7 @@ func (h *Handlers) AcceptRecommendation(c *fiber.Ctx) error {
 	rec.Status = "accepted"
 	h.DB.Save(&rec)
 
-	h.logActivity(orgID, "recommendation_accepted", "Accepted policy recommendation", nil)
+	h.logActivity(c.UserContext(), orgID, "recommendation_accepted", "Accepted policy recommendation", nil)
 
 	return c.JSON(rec)
 }
@@ -355,7 +405,66 @@ func (h *Handlers) RejectRecommendation(c *fiber.Ctx) error {
 	rec.RejectionReason = req.Reason
 	h.DB.Save(&rec)
 
-	h.logActivity(orgID, "recommendation_rejected", "Rejected policy recommendation: "+req.Reason, nil)
+	h.logActivity(c.UserContext(), orgID, "recommendation_rejected", "Rejected policy recommendation: "+req.Reason, nil)
+
+	return c.JSON(rec)
+}
+
+// SnoozeRecommendation hides a recommendation and keeps it from being regenerated until the
+// given date
+func (h *Handlers) SnoozeRecommendation(c *fiber.Ctx) error {
+	recommendationID := c.Params("id")
+	orgID := middleware.GetOrgID(c)
+	if orgID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Organization ID required",
+		})
+	}
+
+	type SnoozeRequest struct {
+		Until string `json:"until"` // RFC 3339 timestamp or YYYY-MM-DD
+	}
+
+	var req SnoozeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	until, err := time.Parse(time.RFC3339, req.Until)
+	if err != nil {
+		until, err = time.Parse("2006-01-02", req.Until)
+	}
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "until must be an RFC 3339 timestamp or a YYYY-MM-DD date",
+		})
+	}
+	if !until.After(time.Now()) {
+		return c.Status(400).JSON(fiber.Map{
+			"error": "until must be in the future",
+		})
+	}
+
+	var rec models.PolicyRecommendation
+	if err := h.DB.Where("id = ? AND organization_id = ?", recommendationID, orgID).First(&rec).Error; err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"error": "Recommendation not found",
+		})
+	}
+
+	if rec.Status != "pending" && rec.Status != "snoozed" {
+		return c.Status(409).JSON(fiber.Map{
+			"error": "Only pending recommendations can be snoozed",
+		})
+	}
+
+	rec.Status = "snoozed"
+	rec.SnoozedUntil = &until
+	h.DB.Save(&rec)
+
+	h.logActivity(c.UserContext(), orgID, "recommendation_snoozed", "Snoozed policy recommendation until "+until.Format("2006-01-02"), nil)
 
 	return c.JSON(rec)
 }
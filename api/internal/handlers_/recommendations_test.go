@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func TestActiveSnoozes(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	later := now.Add(24 * time.Hour)
+	earlier := now.Add(-time.Hour)
+
+	recommendations := []models.PolicyRecommendation{
+		{PolicyTemplateID: "snoozed", Status: "snoozed", SnoozedUntil: &later},
+		{PolicyTemplateID: "expired", Status: "snoozed", SnoozedUntil: &earlier},
+		{PolicyTemplateID: "no-date", Status: "snoozed"},
+		{PolicyTemplateID: "pending", Status: "pending", SnoozedUntil: &later},
+	}
+
+	got := activeSnoozes(recommendations, now)
+	want := map[string]bool{"snoozed": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+// snoozeApp serves the snooze route, for organization orgID when it isn't empty
+func snoozeApp(h *Handlers, orgID string) *fiber.App {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		if orgID != "" {
+			c.Locals("orgID", orgID)
+		}
+		return c.Next()
+	})
+	app.Post("/recommendations/:id/snooze", h.SnoozeRecommendation)
+	return app
+}
+
+func snooze(t *testing.T, app *fiber.App, until string) int {
+	t.Helper()
+	req := httptest.NewRequest("POST", "/recommendations/rec-1/snooze", strings.NewReader(`{"until":"`+until+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode
+}
+
+// stubRecommendation makes h find recommendation rec-1 with the given status, and records the
+// queries it runs and the recommendations it saves
+func stubRecommendation(t *testing.T, h *Handlers, status string) (queries *[]string, saved *[]models.PolicyRecommendation) {
+	t.Helper()
+	queries, saved = &[]string{}, &[]models.PolicyRecommendation{}
+	h.DB.Callback().Query().After("gorm:query").Register("test:stub_recommendation", func(tx *gorm.DB) {
+		*queries = append(*queries, tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...))
+		if rec, ok := tx.Statement.Dest.(*models.PolicyRecommendation); ok {
+			rec.ID, rec.OrganizationID, rec.Status = "rec-1", "org", status
+		}
+	})
+	h.DB.Callback().Update().Before("gorm:update").Register("test:record_saves", func(tx *gorm.DB) {
+		if rec, ok := tx.Statement.Dest.(*models.PolicyRecommendation); ok {
+			*saved = append(*saved, *rec)
+		}
+	})
+	return queries, saved
+}
+
+func TestSnoozeRecommendationRejectsInvalidUntil(t *testing.T) {
+	app := snoozeApp(dryRunHandlers(t), "org")
+
+	for _, until := range []string{"", "next week", "2020-01-01", time.Now().Add(-time.Hour).Format(time.RFC3339)} {
+		if status := snooze(t, app, until); status != fiber.StatusBadRequest {
+			t.Errorf("until %q: status %d, want 400", until, status)
+		}
+	}
+}
+
+func TestSnoozeRecommendationRequiresOrganization(t *testing.T) {
+	h := dryRunHandlers(t)
+	queries, _ := stubRecommendation(t, h, "pending")
+
+	if status := snooze(t, snoozeApp(h, ""), "2099-01-01"); status != fiber.StatusUnauthorized {
+		t.Errorf("status %d, want 401", status)
+	}
+	if len(*queries) != 0 {
+		t.Errorf("got queries %v, want none", *queries)
+	}
+}
+
+func TestSnoozeRecommendation(t *testing.T) {
+	h := dryRunHandlers(t)
+	queries, saved := stubRecommendation(t, h, "pending")
+	logged := recordActivity(t, h)
+
+	if status := snooze(t, snoozeApp(h, "org"), "2099-01-01"); status != fiber.StatusOK {
+		t.Fatalf("status %d, want 200", status)
+	}
+
+	if len(*queries) != 1 || !strings.Contains((*queries)[0], "id = 'rec-1' AND organization_id = 'org'") {
+		t.Errorf("queries = %v, want one scoped to the organization", *queries)
+	}
+	if len(*saved) != 1 {
+		t.Fatalf("got %d saves, want 1", len(*saved))
+	}
+	rec := (*saved)[0]
+	if want := time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC); rec.Status != "snoozed" || rec.SnoozedUntil == nil || !rec.SnoozedUntil.Equal(want) {
+		t.Errorf("saved %s until %v, want snoozed until %s", rec.Status, rec.SnoozedUntil, want)
+	}
+	if len(*logged) != 1 || (*logged)[0].Type != "recommendation_snoozed" {
+		t.Errorf("activity = %+v, want a recommendation_snoozed entry", *logged)
+	}
+}
+
+func TestSnoozeRecommendationRejectsDecided(t *testing.T) {
+	h := dryRunHandlers(t)
+	_, saved := stubRecommendation(t, h, "accepted")
+
+	if status := snooze(t, snoozeApp(h, "org"), "2099-01-01"); status != fiber.StatusConflict {
+		t.Errorf("status %d, want 409", status)
+	}
+	if len(*saved) != 0 {
+		t.Errorf("got %d saves, want none", len(*saved))
+	}
+}
+
+func TestOverSpendThreshold(t *testing.T) {
+	h := dryRunHandlers(t)
+	h.Config.RecommendationSpendThresholds = map[string]float64{"max_spend": 1000, "rightsizing": 0}
+
+	tests := []struct {
+		policyType string
+		spend      float64
+		want       bool
+	}{
+		{"max_spend", 1000, false},
+		{"max_spend", 1000.01, true},
+		{"rightsizing", 0, false},
+		{"rightsizing", 1, true},
+		{"require_tags", 0, true},
+	}
+	for _, tt := range tests {
+		if got := h.overSpendThreshold(tt.policyType, tt.spend); got != tt.want {
+			t.Errorf("overSpendThreshold(%q, %v) = %v, want %v", tt.policyType, tt.spend, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	cloud "finopsbridge/api/internal/cloud_"
+	middleware "finopsbridge/api/internal/middleware_"
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TestCloudRemediation runs one remediation function against a provider in forced dry-run mode
+// and returns the resources it would act on, without touching them. config takes the same
+// fields as the matching policy type (maxSize, maxHourlyPrice, idleHours, requiredTags, defaultTags,
+// selector).
+func (h *Handlers) TestCloudRemediation(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+	id := c.Params("id")
+
+	var req struct {
+		Action string                 `json:"action"`
+		Config map[string]interface{} `json:"config"`
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	if req.Config == nil {
+		req.Config = make(map[string]interface{})
+	}
+
+	var provider models.CloudProvider
+	if err := h.DB.Where("id = ? AND organization_id = ?", id, orgID).First(&provider).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Cloud provider not found",
+		})
+	}
+
+	protected, err := models.ProtectedResourceIDs(h.DB, orgID, provider.Type)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch protected resources",
+		})
+	}
+
+	// Never act: this endpoint only reports candidates
+	opts := cloud.RemediationOptions{
+		DryRun:    true,
+		Selector:  cloud.ParseResourceSelector(req.Config),
+		Protected: protected,
+
+		MinResourceAge: cloud.MinResourceAge(req.Config),
+	}
+	ctx := c.UserContext()
+
+	var result cloud.RemediationResult
+	switch req.Action {
+	case "stop-idle":
+		result, err = cloud.StopIdleResources(ctx, provider, h.Config, cloud.IdleHours(req.Config), opts)
+	case "stop-non-essential":
+		result, err = cloud.StopNonEssentialResources(ctx, provider, h.Config, opts)
+	case "terminate-oversized":
+		result, err = cloud.TerminateOversizedInstances(ctx, provider, h.Config,
+			cloud.MaxSizeLevel(req.Config), cloud.MaxHourlyPrice(req.Config), opts)
+	case "apply-tags":
+		result, err = cloud.ApplyDefaultTags(ctx, provider, h.Config, cloud.DefaultTags(provider, req.Config), opts)
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "action must be stop-idle, stop-non-essential, terminate-oversized or apply-tags",
+		})
+	}
+
+	if err != nil {
+		return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{
+			"error": "Remediation dry run failed: " + err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"providerId": provider.ID,
+		"action":     req.Action,
+		"dryRun":     true,
+		"candidates": result.Candidates(),
+		"protected":  result.Protected,
+	})
+}
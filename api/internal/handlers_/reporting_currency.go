@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"sort"
+
+	cloud "finopsbridge/api/internal/cloud_"
+	models "finopsbridge/api/internal/models_"
+)
+
+// providerSpend is a provider type's spend in the reporting currency
+type providerSpend struct {
+	Provider string  `json:"provider"`
+	Amount   float64 `json:"amount"`
+}
+
+// providerCurrencies returns the currency of each provider's latest spend snapshot. Providers
+// without one are missing, and their spend is taken to be in the default currency.
+func (h *Handlers) providerCurrencies(providers []models.CloudProvider) map[string]string {
+	ids := make([]string, 0, len(providers))
+	for _, provider := range providers {
+		ids = append(ids, provider.ID)
+	}
+	currencies := make(map[string]string, len(ids))
+	if len(ids) == 0 {
+		return currencies
+	}
+
+	var rows []struct {
+		ProviderID string
+		Currency   string
+	}
+	if err := h.DB.Model(&models.SpendSnapshot{}).
+		Select("DISTINCT ON (provider_id) provider_id, currency").
+		Where("provider_id IN ?", ids).
+		Order("provider_id, date DESC").
+		Scan(&rows).Error; err != nil {
+		return currencies
+	}
+	for _, row := range rows {
+		currencies[row.ProviderID] = row.Currency
+	}
+	return currencies
+}
+
+// reportingSpend totals providers' month-to-date spend in the reporting currency, overall and
+// by provider type. Spend in a currency without an exchange rate is left out and its currency
+// recorded in unconverted.
+func (h *Handlers) reportingSpend(providers []models.CloudProvider, unconverted map[string]bool) (float64, []providerSpend) {
+	currencies := h.providerCurrencies(providers)
+	total := 0.0
+	byType := make(map[string]float64)
+	for _, provider := range providers {
+		currency := currencies[provider.ID]
+		if currency == "" {
+			currency = cloud.DefaultCurrency(h.Config)
+		}
+		converted, ok := cloud.ConvertCurrency(provider.MonthlySpend, currency, h.Config)
+		if !ok {
+			unconverted[currency] = true
+			continue
+		}
+		total += converted
+		byType[provider.Type] += converted
+	}
+	return total, spendByType(byType)
+}
+
+// spendByType lists spend per provider type, ordered by type
+func spendByType(byType map[string]float64) []providerSpend {
+	spend := make([]providerSpend, 0, len(byType))
+	for providerType, amount := range byType {
+		spend = append(spend, providerSpend{Provider: providerType, Amount: amount})
+	}
+	sort.Slice(spend, func(i, j int) bool {
+		return spend[i].Provider < spend[j].Provider
+	})
+	return spend
+}
+
+// convertSnapshots converts snapshots' month-to-date spend to the reporting currency in place.
+// Snapshots in a currency without an exchange rate count as zero spend, with their currency
+// recorded in unconverted.
+func (h *Handlers) convertSnapshots(snapshots []models.SpendSnapshot, unconverted map[string]bool) {
+	for i := range snapshots {
+		currency := snapshots[i].Currency
+		if currency == "" {
+			currency = cloud.DefaultCurrency(h.Config)
+		}
+		converted, ok := cloud.ConvertCurrency(snapshots[i].MonthToDateSpend, currency, h.Config)
+		if !ok {
+			unconverted[currency] = true
+		}
+		snapshots[i].MonthToDateSpend = converted
+		snapshots[i].Currency = h.Config.ReportingCurrency
+	}
+}
+
+// sortedCurrencies lists the currencies of a set in order
+func sortedCurrencies(set map[string]bool) []string {
+	currencies := make([]string, 0, len(set))
+	for currency := range set {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	return currencies
+}
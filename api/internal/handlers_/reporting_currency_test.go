@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	models "finopsbridge/api/internal/models_"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestReportingSpend(t *testing.T) {
+	h := dryRunHandlers(t)
+	// Dry runs can't scan the latest snapshots' currencies
+	h.DB = h.DB.Session(&gorm.Session{Logger: logger.Discard})
+	h.Config.DefaultCurrency = "EUR"
+	h.Config.ExchangeRates = map[string]float64{"EUR": 1.1}
+	providers := []models.CloudProvider{
+		{ID: "p1", Type: "gcp", MonthlySpend: 100},
+		{ID: "p2", Type: "aws", MonthlySpend: 50},
+		{ID: "p3", Type: "aws", MonthlySpend: 10},
+	}
+
+	// Without snapshots, every provider's spend is in the default currency
+	unconverted := make(map[string]bool)
+	total, byType := h.reportingSpend(providers, unconverted)
+	if total < 176-1e-9 || total > 176+1e-9 {
+		t.Errorf("total = %v, want 176", total)
+	}
+	if len(byType) != 2 || byType[0].Provider != "aws" || byType[1].Provider != "gcp" {
+		t.Errorf("byType = %+v, want aws then gcp", byType)
+	}
+	if len(unconverted) != 0 {
+		t.Errorf("unconverted = %v", unconverted)
+	}
+
+	h.Config.ExchangeRates = nil
+	total, _ = h.reportingSpend(providers, unconverted)
+	if total != 0 || !unconverted["EUR"] {
+		t.Errorf("spend without a rate should be left out and reported, got %v and %v", total, unconverted)
+	}
+}
+
+func TestConvertSnapshots(t *testing.T) {
+	h := dryRunHandlers(t)
+	h.Config.ExchangeRates = map[string]float64{"GBP": 1.25}
+	snapshots := []models.SpendSnapshot{
+		{MonthToDateSpend: 100, Currency: "GBP"},
+		{MonthToDateSpend: 40, Currency: "USD"},
+		{MonthToDateSpend: 500, Currency: "JPY"},
+		{MonthToDateSpend: 20},
+	}
+
+	unconverted := make(map[string]bool)
+	h.convertSnapshots(snapshots, unconverted)
+
+	// The last snapshot has no currency and is in the default USD
+	for i, want := range []float64{125, 40, 0, 20} {
+		if snapshots[i].MonthToDateSpend != want || snapshots[i].Currency != "USD" {
+			t.Errorf("snapshot %d = %v %s, want %v USD", i, snapshots[i].MonthToDateSpend, snapshots[i].Currency, want)
+		}
+	}
+	if got := sortedCurrencies(unconverted); !reflect.DeepEqual(got, []string{"JPY"}) {
+		t.Errorf("unconverted = %v, want [JPY]", got)
+	}
+}
+
+func TestSortedCurrencies(t *testing.T) {
+	got := sortedCurrencies(map[string]bool{"USD": true, "EUR": true, "GBP": true})
+	if want := []string{"EUR", "GBP", "USD"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
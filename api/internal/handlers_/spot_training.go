@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+)
+
+// spotTrainingSavingsRate is the share of on-demand cost assumed saved by moving a training
+// workload to spot, the low end of the usual 60-90%
+const spotTrainingSavingsRate = 0.6
+
+// workloadSpotMetadata holds the AIWorkload metadata fields that decide spot readiness
+type workloadSpotMetadata struct {
+	Checkpointing        bool   `json:"checkpointing"`
+	CheckpointingEnabled bool   `json:"checkpointingEnabled"`
+	CheckpointPath       string `json:"checkpointPath"`
+	UseSpotInstances     bool   `json:"useSpotInstances"`
+}
+
+func parseWorkloadSpotMetadata(raw string) workloadSpotMetadata {
+	var metadata workloadSpotMetadata
+	if raw != "" {
+		json.Unmarshal([]byte(raw), &metadata)
+	}
+	return metadata
+}
+
+// checkpoints reports whether the workload saves checkpoints, so a spot interruption only
+// loses the work since the last one
+func (m workloadSpotMetadata) checkpoints() bool {
+	return m.Checkpointing || m.CheckpointingEnabled || strings.TrimSpace(m.CheckpointPath) != ""
+}
+
+// workloadMonthlyCost spreads a workload's total cost over the months it has run, counting
+// anything shorter as one month
+func workloadMonthlyCost(workload models.AIWorkload, now time.Time) float64 {
+	months := now.Sub(workload.StartedAt).Hours() / (24 * 30)
+	if months < 1 {
+		months = 1
+	}
+	return workload.TotalCost / months
+}
+
+// spotTrainingAssessment splits an organization's on-demand training workloads by whether
+// they can move to spot now. spot_instances_for_training requires checkpointing by default,
+// so workloads without it have to enable it first.
+type spotTrainingAssessment struct {
+	ready              []models.AIWorkload // Checkpointed, still on demand
+	needCheckpointing  []models.AIWorkload // On demand without checkpointing
+	readyMonthlyCost   float64
+	blockedMonthlyCost float64
+}
+
+func assessSpotTraining(workloads []models.AIWorkload, now time.Time) spotTrainingAssessment {
+	var assessment spotTrainingAssessment
+	for _, workload := range workloads {
+		if workload.WorkloadType != "training" || workload.Status != "active" {
+			continue
+		}
+		metadata := parseWorkloadSpotMetadata(workload.Metadata)
+		if metadata.UseSpotInstances {
+			continue
+		}
+		if metadata.checkpoints() {
+			assessment.ready = append(assessment.ready, workload)
+			assessment.readyMonthlyCost += workloadMonthlyCost(workload, now)
+		} else {
+			assessment.needCheckpointing = append(assessment.needCheckpointing, workload)
+			assessment.blockedMonthlyCost += workloadMonthlyCost(workload, now)
+		}
+	}
+	return assessment
+}
+
+// recommendation grades the spot recommendation by readiness: moving checkpointed workloads
+// is recommended outright, and workloads without checkpointing are first asked to enable it.
+// Savings only count workloads that can move now.
+func (a spotTrainingAssessment) recommendation() (float64, float64, string, []string) {
+	var issues []string
+	for _, workload := range a.needCheckpointing {
+		issues = append(issues, fmt.Sprintf("Training workload '%s' runs on demand without checkpointing", workload.Name))
+	}
+
+	switch {
+	case len(a.ready) == 0 && len(a.needCheckpointing) == 0:
+		return 0, 0, "", nil
+
+	case len(a.needCheckpointing) == 0:
+		issues = append(issues, fmt.Sprintf("%d checkpointed training workloads run on on-demand capacity", len(a.ready)))
+		return 0.85, a.readyMonthlyCost * spotTrainingSavingsRate,
+			fmt.Sprintf("All %d active training workloads checkpoint, so they can move to spot/preemptible instances now for 60-90%% savings.", len(a.ready)),
+			issues
+
+	case len(a.ready) == 0:
+		return 0.45, 0,
+			fmt.Sprintf("Enable checkpointing on your %d training workloads before moving them to spot: spot capacity can be reclaimed at any time, and this policy requires checkpointing so an interruption doesn't lose the run. Once enabled, spot could save about $%.2f/month.",
+				len(a.needCheckpointing), a.blockedMonthlyCost*spotTrainingSavingsRate),
+			issues
+	}
+
+	return 0.7, a.readyMonthlyCost * spotTrainingSavingsRate,
+		fmt.Sprintf("%d checkpointed training workloads can move to spot now. Enable checkpointing on the other %d first; they are excluded from the suggested config until then.",
+			len(a.ready), len(a.needCheckpointing)),
+		issues
+}
+
+// suggestedConfig requires spot for training with checkpointing, excluding the workloads that
+// don't checkpoint yet so the policy doesn't flag them before they can comply
+func (a spotTrainingAssessment) suggestedConfig() map[string]interface{} {
+	excluded := make([]string, 0, len(a.needCheckpointing))
+	for _, workload := range a.needCheckpointing {
+		excluded = append(excluded, workload.ID)
+	}
+	return map[string]interface{}{
+		"requireSpotFor":        []string{"training"},
+		"minJobDuration":        2,
+		"allowOnDemandForHours": 1,
+		"checkpointingRequired": true,
+		"excludeJobs":           excluded,
+	}
+}
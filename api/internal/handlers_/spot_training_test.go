@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+)
+
+func trainingWorkload(id, metadata string, totalCost float64, started time.Time) models.AIWorkload {
+	return models.AIWorkload{
+		ID:           id,
+		Name:         id,
+		WorkloadType: "training",
+		Status:       "active",
+		Metadata:     metadata,
+		TotalCost:    totalCost,
+		StartedAt:    started,
+	}
+}
+
+func TestWorkloadSpotMetadataCheckpoints(t *testing.T) {
+	tests := map[string]bool{
+		`{"checkpointing": true}`:              true,
+		`{"checkpointingEnabled": true}`:       true,
+		`{"checkpointPath": "s3://ckpt/run1"}`: true,
+		`{"checkpointPath": "  "}`:             false,
+		`{}`:                                   false,
+		`not json`:                             false,
+	}
+	for raw, want := range tests {
+		if got := parseWorkloadSpotMetadata(raw).checkpoints(); got != want {
+			t.Errorf("%s: checkpoints = %v, want %v", raw, got, want)
+		}
+	}
+}
+
+func TestWorkloadMonthlyCost(t *testing.T) {
+	now := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	if got := workloadMonthlyCost(models.AIWorkload{TotalCost: 900, StartedAt: now.AddDate(0, 0, -90)}, now); got != 300 {
+		t.Errorf("three months: got %v, want 300", got)
+	}
+	if got := workloadMonthlyCost(models.AIWorkload{TotalCost: 900, StartedAt: now.AddDate(0, 0, -3)}, now); got != 900 {
+		t.Errorf("under a month should count as one: got %v, want 900", got)
+	}
+}
+
+func TestAssessSpotTraining(t *testing.T) {
+	now := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	started := now.AddDate(0, 0, -10)
+
+	inference := trainingWorkload("inference", `{}`, 1000, started)
+	inference.WorkloadType = "inference"
+	finished := trainingWorkload("finished", `{}`, 1000, started)
+	finished.Status = "completed"
+
+	assessment := assessSpotTraining([]models.AIWorkload{
+		trainingWorkload("ready", `{"checkpointing": true}`, 1000, started),
+		trainingWorkload("blocked", `{}`, 500, started),
+		trainingWorkload("on-spot", `{"useSpotInstances": true}`, 2000, started),
+		inference,
+		finished,
+	}, now)
+
+	if len(assessment.ready) != 1 || assessment.ready[0].ID != "ready" || assessment.readyMonthlyCost != 1000 {
+		t.Errorf("ready = %+v, cost %v", assessment.ready, assessment.readyMonthlyCost)
+	}
+	if len(assessment.needCheckpointing) != 1 || assessment.needCheckpointing[0].ID != "blocked" || assessment.blockedMonthlyCost != 500 {
+		t.Errorf("need checkpointing = %+v, cost %v", assessment.needCheckpointing, assessment.blockedMonthlyCost)
+	}
+
+	confidence, savings, _, issues := assessment.recommendation()
+	if confidence != 0.7 || savings != 600 || len(issues) != 1 {
+		t.Errorf("mixed readiness: got %v, %v, %v", confidence, savings, issues)
+	}
+	config := assessment.suggestedConfig()
+	if excluded := config["excludeJobs"]; !reflect.DeepEqual(excluded, []string{"blocked"}) {
+		t.Errorf("excludeJobs = %v, want the workload without checkpointing", excluded)
+	}
+}
+
+func TestSpotTrainingRecommendationGrades(t *testing.T) {
+	workload := models.AIWorkload{ID: "w", Name: "w"}
+
+	if confidence, _, _, _ := (spotTrainingAssessment{}).recommendation(); confidence != 0 {
+		t.Errorf("no workloads: confidence %v, want 0", confidence)
+	}
+
+	ready := spotTrainingAssessment{ready: []models.AIWorkload{workload}, readyMonthlyCost: 100}
+	if confidence, savings, _, _ := ready.recommendation(); confidence != 0.85 || savings != 60 {
+		t.Errorf("all ready: got %v, %v", confidence, savings)
+	}
+
+	blocked := spotTrainingAssessment{needCheckpointing: []models.AIWorkload{workload}, blockedMonthlyCost: 100}
+	if confidence, savings, _, _ := blocked.recommendation(); confidence != 0.45 || savings != 0 {
+		t.Errorf("none ready: got %v, %v; savings only count workloads that can move now", confidence, savings)
+	}
+}
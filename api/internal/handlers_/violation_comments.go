@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	middleware "finopsbridge/api/internal/middleware_"
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxViolationCommentLength bounds a comment's body, in characters
+const maxViolationCommentLength = 5000
+
+// findOrgViolation loads a violation of one of the organization's policies
+func (h *Handlers) findOrgViolation(orgID, id string) (models.PolicyViolation, error) {
+	var violation models.PolicyViolation
+	err := h.DB.Joins("JOIN policies ON policies.id = policy_violations.policy_id").
+		Where("policy_violations.id = ? AND policies.organization_id = ?", id, orgID).
+		First(&violation).Error
+	return violation, err
+}
+
+// ListViolationComments returns a violation's comments, oldest first
+func (h *Handlers) ListViolationComments(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+
+	violation, err := h.findOrgViolation(orgID, c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Violation not found",
+		})
+	}
+
+	var comments []models.ViolationComment
+	if err := h.DB.Where("violation_id = ? AND organization_id = ?", violation.ID, orgID).
+		Order("created_at, id").
+		Find(&comments).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch comments",
+		})
+	}
+
+	return c.JSON(comments)
+}
+
+// CreateViolationComment adds a comment to a violation as the signed-in user
+func (h *Handlers) CreateViolationComment(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+	userID := middleware.GetUserID(c)
+
+	var req struct {
+		Body string `json:"body"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "body is required",
+		})
+	}
+	if utf8.RuneCountInString(body) > maxViolationCommentLength {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "body must be at most 5000 characters",
+		})
+	}
+
+	violation, err := h.findOrgViolation(orgID, c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Violation not found",
+		})
+	}
+
+	comment := models.ViolationComment{
+		ViolationID:    violation.ID,
+		OrganizationID: orgID,
+		Author:         userID,
+		Body:           body,
+	}
+	var user models.User
+	if err := h.DB.Where("clerk_user_id = ?", userID).First(&user).Error; err == nil {
+		comment.AuthorName = user.Name
+		if comment.AuthorName == "" {
+			comment.AuthorName = user.Email
+		}
+	}
+
+	if err := h.DB.Create(&comment).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to add comment",
+		})
+	}
+
+	h.logActivity(c.UserContext(), orgID, "violation_comment", "Commented on violation "+violation.ID, map[string]interface{}{
+		"violationId": violation.ID,
+		"commentId":   comment.ID,
+		"userId":      userID,
+	})
+
+	return c.Status(fiber.StatusCreated).JSON(comment)
+}
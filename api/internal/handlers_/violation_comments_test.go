@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// commentsApp serves the violation comment routes for a signed-in user of an organization
+func commentsApp(h *Handlers) *fiber.App {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("orgID", "org")
+		c.Locals("userID", "user-1")
+		return c.Next()
+	})
+	app.Get("/violations/:id/comments", h.ListViolationComments)
+	app.Post("/violations/:id/comments", h.CreateViolationComment)
+	return app
+}
+
+func postComment(t *testing.T, h *Handlers, body string) int {
+	t.Helper()
+	req := httptest.NewRequest("POST", "/violations/v1/comments", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := commentsApp(h).Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode
+}
+
+// recordComments stubs violation v1 and a user without a name, and collects the comments h creates
+func recordComments(t *testing.T, h *Handlers) *[]models.ViolationComment {
+	t.Helper()
+	h.DB.Callback().Query().After("gorm:query").Register("test:stub_violation", func(tx *gorm.DB) {
+		switch dest := tx.Statement.Dest.(type) {
+		case *models.PolicyViolation:
+			dest.ID = "v1"
+		case *models.User:
+			dest.Email = "ana@example.com"
+		}
+	})
+	var created []models.ViolationComment
+	h.DB.Callback().Create().Before("gorm:create").Register("test:record_comments", func(tx *gorm.DB) {
+		if comment, ok := tx.Statement.Dest.(*models.ViolationComment); ok {
+			created = append(created, *comment)
+		}
+	})
+	return &created
+}
+
+func TestCreateViolationCommentRejectsInvalidBodies(t *testing.T) {
+	h := dryRunHandlers(t)
+	created := recordComments(t, h)
+
+	tooLong, _ := json.Marshal(map[string]string{"body": strings.Repeat("é", maxViolationCommentLength+1)})
+	for _, body := range []string{`not json`, `{}`, `{"body": "   "}`, string(tooLong)} {
+		if status := postComment(t, h, body); status != fiber.StatusBadRequest {
+			t.Errorf("%.40s: status %d, want 400", body, status)
+		}
+	}
+	if len(*created) != 0 {
+		t.Errorf("got %d comments, want none", len(*created))
+	}
+}
+
+func TestCreateViolationComment(t *testing.T) {
+	h := dryRunHandlers(t)
+	created := recordComments(t, h)
+	logged := recordActivity(t, h)
+
+	// The limit counts characters, not bytes
+	atLimit, _ := json.Marshal(map[string]string{"body": strings.Repeat("é", maxViolationCommentLength)})
+	if status := postComment(t, h, string(atLimit)); status != fiber.StatusCreated {
+		t.Errorf("status %d for a comment at the limit, want 201", status)
+	}
+	if status := postComment(t, h, `{"body": "  Tagged the owner  "}`); status != fiber.StatusCreated {
+		t.Fatalf("status %d, want 201", status)
+	}
+
+	if len(*created) != 2 {
+		t.Fatalf("got %d comments, want 2", len(*created))
+	}
+	comment := (*created)[1]
+	if comment.ViolationID != "v1" || comment.OrganizationID != "org" || comment.Author != "user-1" || comment.Body != "Tagged the owner" {
+		t.Errorf("comment = %+v", comment)
+	}
+	if comment.AuthorName != "ana@example.com" {
+		t.Errorf("author name = %q, want the email of a user without a name", comment.AuthorName)
+	}
+	if len(*logged) != 2 || (*logged)[1].Type != "violation_comment" {
+		t.Errorf("activity = %+v, want a violation_comment entry per comment", *logged)
+	}
+}
+
+func TestFindOrgViolationScopesToOrganization(t *testing.T) {
+	h := dryRunHandlers(t)
+	var statements []string
+	h.DB.Callback().Query().After("gorm:query").Register("test:record_queries", func(tx *gorm.DB) {
+		statements = append(statements, tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...))
+	})
+
+	if _, err := h.findOrgViolation("org", "v1"); err != nil {
+		t.Fatal(err)
+	}
+	if len(statements) != 1 {
+		t.Fatalf("got %d queries, want 1", len(statements))
+	}
+	for _, want := range []string{"JOIN policies ON policies.id = policy_violations.policy_id", "policy_violations.id = 'v1'", "policies.organization_id = 'org'"} {
+		if !strings.Contains(statements[0], want) {
+			t.Errorf("%s\nshould contain %s", statements[0], want)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	middleware "finopsbridge/api/internal/middleware_"
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// violationPurgeBatchSize is how many violations each purge transaction deletes
+const violationPurgeBatchSize = 500
+
+// purgeableStatuses are the resolved statuses whose violations can be purged. Pending
+// violations are still being acted on and are never purged.
+var purgeableStatuses = map[string]bool{"remediated": true, "ignored": true}
+
+// violationSummary is what's kept of a policy's purged violations for one month
+type violationSummary struct {
+	violations         int
+	remediations       int
+	remediationSeconds float64
+}
+
+type violationSummaryKey struct {
+	policyID string
+	month    string // YYYY-MM of the violations' creation
+}
+
+// summarizeViolations tallies violations per policy and month, with the total time to
+// remediate those that were remediated
+func summarizeViolations(violations []models.PolicyViolation) map[violationSummaryKey]*violationSummary {
+	summaries := make(map[violationSummaryKey]*violationSummary)
+	for _, violation := range violations {
+		key := violationSummaryKey{policyID: violation.PolicyID, month: violation.CreatedAt.UTC().Format("2006-01")}
+		summary, ok := summaries[key]
+		if !ok {
+			summary = &violationSummary{}
+			summaries[key] = summary
+		}
+		summary.violations++
+		if violation.Status == "remediated" && violation.RemediatedAt != nil {
+			summary.remediations++
+			summary.remediationSeconds += violation.RemediatedAt.Sub(violation.CreatedAt).Seconds()
+		}
+	}
+	return summaries
+}
+
+// addToAdoptionMetrics folds a summary into a month's adoption metrics, keeping the average
+// remediation time weighted by the remediations on each side
+func addToAdoptionMetrics(metrics *models.PolicyAdoptionMetrics, summary violationSummary) {
+	totalSeconds := float64(metrics.AverageRemediationTime)*float64(metrics.RemediationCount) + summary.remediationSeconds
+	metrics.ViolationCount += summary.violations
+	metrics.RemediationCount += summary.remediations
+	if metrics.RemediationCount > 0 {
+		metrics.AverageRemediationTime = int(totalSeconds / float64(metrics.RemediationCount))
+	}
+}
+
+// purgeViolationBatch deletes one batch of an organization's resolved violations created before
+// the cutoff, first adding them to PolicyAdoptionMetrics. It returns how many were deleted.
+func purgeViolationBatch(tx *gorm.DB, orgID string, statuses []string, before time.Time) (int, error) {
+	var violations []models.PolicyViolation
+	if err := tx.Joins("JOIN policies ON policies.id = policy_violations.policy_id").
+		Where("policies.organization_id = ?", orgID).
+		Where("policy_violations.status IN ? AND policy_violations.created_at < ?", statuses, before).
+		Order("policy_violations.created_at").
+		Limit(violationPurgeBatchSize).
+		Find(&violations).Error; err != nil {
+		return 0, err
+	}
+	if len(violations) == 0 {
+		return 0, nil
+	}
+
+	for key, summary := range summarizeViolations(violations) {
+		metrics := models.PolicyAdoptionMetrics{OrganizationID: orgID, PolicyID: key.policyID, Month: key.month}
+		if err := tx.Where("organization_id = ? AND policy_id = ? AND month = ?", orgID, key.policyID, key.month).
+			FirstOrInit(&metrics).Error; err != nil {
+			return 0, err
+		}
+		addToAdoptionMetrics(&metrics, *summary)
+		if err := tx.Save(&metrics).Error; err != nil {
+			return 0, err
+		}
+	}
+
+	ids := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		ids = append(ids, violation.ID)
+	}
+	// Status is checked again so a violation can't be purged if it was reopened meanwhile
+	result := tx.Where("id IN ? AND status IN ?", ids, statuses).Delete(&models.PolicyViolation{})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	// Comments go with their violations; those of a reopened violation are kept
+	if err := tx.Where("violation_id IN ? AND violation_id NOT IN (?)", ids,
+		tx.Model(&models.PolicyViolation{}).Select("id").Where("id IN ?", ids)).
+		Delete(&models.ViolationComment{}).Error; err != nil {
+		return 0, err
+	}
+	return int(result.RowsAffected), nil
+}
+
+// PurgeViolations deletes an organization's resolved violations created before a date, in
+// batches of one transaction each. Purged violations are kept as monthly counts in
+// PolicyAdoptionMetrics.
+func (h *Handlers) PurgeViolations(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+
+	statuses := []string{"remediated", "ignored"}
+	if raw := c.Query("status"); raw != "" {
+		statuses = nil
+		for _, status := range strings.Split(raw, ",") {
+			status = strings.TrimSpace(status)
+			if !purgeableStatuses[status] {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": "status must be remediated or ignored; pending violations can't be purged",
+				})
+			}
+			statuses = append(statuses, status)
+		}
+	}
+
+	before, err := time.Parse("2006-01-02", c.Query("before"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "before must be a date in YYYY-MM-DD format",
+		})
+	}
+
+	deleted := 0
+	for {
+		var batch int
+		if err := h.DB.Transaction(func(tx *gorm.DB) error {
+			batch, err = purgeViolationBatch(tx, orgID, statuses, before)
+			return err
+		}); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error":   "Failed to purge violations",
+				"deleted": deleted,
+			})
+		}
+		deleted += batch
+		if batch < violationPurgeBatchSize {
+			break
+		}
+	}
+
+	if deleted > 0 {
+		h.InvalidateDashboardStats(orgID)
+	}
+
+	h.logActivity(c.UserContext(), orgID, "violations_purged", fmt.Sprintf("Purged %d resolved violations created before %s", deleted, before.Format("2006-01-02")), map[string]interface{}{
+		"deleted":  deleted,
+		"statuses": statuses,
+		"before":   before.Format("2006-01-02"),
+		"userId":   middleware.GetUserID(c),
+	})
+
+	return c.JSON(fiber.Map{
+		"deleted": deleted,
+	})
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func TestSummarizeViolations(t *testing.T) {
+	march := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	remediated := march.Add(2 * time.Hour)
+	april := time.Date(2026, 4, 1, 0, 30, 0, 0, time.UTC)
+
+	summaries := summarizeViolations([]models.PolicyViolation{
+		{PolicyID: "p1", Status: "remediated", CreatedAt: march, RemediatedAt: &remediated},
+		{PolicyID: "p1", Status: "ignored", CreatedAt: march},
+		{PolicyID: "p1", Status: "remediated", CreatedAt: april},
+		{PolicyID: "p2", Status: "ignored", CreatedAt: march},
+	})
+
+	if len(summaries) != 3 {
+		t.Fatalf("got %d summaries, want one per policy and month", len(summaries))
+	}
+	got := summaries[violationSummaryKey{policyID: "p1", month: "2026-03"}]
+	if got == nil || got.violations != 2 || got.remediations != 1 || got.remediationSeconds != 7200 {
+		t.Errorf("p1 March = %+v", got)
+	}
+	// A remediated violation without a remediation time isn't counted as a remediation
+	if got := summaries[violationSummaryKey{policyID: "p1", month: "2026-04"}]; got == nil || got.violations != 1 || got.remediations != 0 {
+		t.Errorf("p1 April = %+v", got)
+	}
+}
+
+func TestAddToAdoptionMetrics(t *testing.T) {
+	metrics := models.PolicyAdoptionMetrics{ViolationCount: 5, RemediationCount: 2, AverageRemediationTime: 100}
+
+	addToAdoptionMetrics(&metrics, violationSummary{violations: 4, remediations: 2, remediationSeconds: 600})
+
+	if metrics.ViolationCount != 9 || metrics.RemediationCount != 4 || metrics.AverageRemediationTime != 200 {
+		t.Errorf("got %+v, want 9 violations, 4 remediations averaging 200s", metrics)
+	}
+
+	empty := models.PolicyAdoptionMetrics{}
+	addToAdoptionMetrics(&empty, violationSummary{violations: 3})
+	if empty.ViolationCount != 3 || empty.AverageRemediationTime != 0 {
+		t.Errorf("no remediations: got %+v", empty)
+	}
+}
+
+func TestPurgeViolationsRejectsInvalidQuery(t *testing.T) {
+	h := dryRunHandlers(t)
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("orgID", "org")
+		return c.Next()
+	})
+	app.Delete("/violations", h.PurgeViolations)
+
+	for _, query := range []string{
+		"?before=2026-01-01&status=pending",
+		"?before=2026-01-01&status=remediated,open",
+		"?status=ignored",
+		"?before=01/01/2026",
+	} {
+		resp, err := app.Test(httptest.NewRequest("DELETE", "/violations"+query, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != fiber.StatusBadRequest {
+			t.Errorf("%s: status %d, want 400", query, resp.StatusCode)
+		}
+	}
+}
+
+func TestPurgeViolationBatch(t *testing.T) {
+	h := dryRunHandlers(t)
+	march := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	// The batch as selected, before v3 was reopened by an overlapping run
+	h.DB.Callback().Query().After("gorm:query").Register("test:stub_batch", func(tx *gorm.DB) {
+		if dest, ok := tx.Statement.Dest.(*[]models.PolicyViolation); ok {
+			*dest = []models.PolicyViolation{
+				{ID: "v1", PolicyID: "p1", Status: "remediated", CreatedAt: march},
+				{ID: "v2", PolicyID: "p1", Status: "ignored", CreatedAt: march},
+				{ID: "v3", PolicyID: "p2", Status: "pending", CreatedAt: march},
+			}
+		}
+	})
+	var selects, deletes []string
+	h.DB.Callback().Query().After("gorm:query").Register("test:record_selects", func(tx *gorm.DB) {
+		if _, ok := tx.Statement.Dest.(*[]models.PolicyViolation); ok {
+			selects = append(selects, tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...))
+		}
+	})
+	h.DB.Callback().Delete().After("gorm:delete").Register("test:record_deletes", func(tx *gorm.DB) {
+		deletes = append(deletes, tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...))
+	})
+
+	before := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := purgeViolationBatch(h.DB, "org", []string{"remediated", "ignored"}, before); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(selects) != 1 {
+		t.Fatalf("got %d violation selects, want 1", len(selects))
+	}
+	for _, want := range []string{
+		"JOIN policies ON policies.id = policy_violations.policy_id",
+		"policies.organization_id = 'org'",
+		"policy_violations.status IN ('remediated','ignored')",
+		"policy_violations.created_at < '2026-01-01 00:00:00'",
+		fmt.Sprintf("LIMIT %d", violationPurgeBatchSize),
+	} {
+		if !strings.Contains(selects[0], want) {
+			t.Errorf("%s\nshould contain %s", selects[0], want)
+		}
+	}
+
+	if len(deletes) != 2 {
+		t.Fatalf("got %d deletes, want violations then comments: %v", len(deletes), deletes)
+	}
+	// The delete checks status again, so v3, now pending, stays
+	if want := `DELETE FROM "policy_violations" WHERE id IN ('v1','v2','v3') AND status IN ('remediated','ignored')`; deletes[0] != want {
+		t.Errorf("got %s\nwant %s", deletes[0], want)
+	}
+	// A kept violation keeps its comments
+	if want := "violation_id NOT IN (SELECT \"id\" FROM \"policy_violations\" WHERE id IN ('v1','v2','v3'))"; !strings.Contains(deletes[1], want) {
+		t.Errorf("%s\nshould contain %s", deletes[1], want)
+	}
+}
+
+func TestPurgeViolationBatchWithNothingToPurge(t *testing.T) {
+	h := dryRunHandlers(t)
+	h.DB.Callback().Query().After("gorm:query").Register("test:stub_none", func(tx *gorm.DB) {
+		if dest, ok := tx.Statement.Dest.(*[]models.PolicyViolation); ok {
+			*dest = nil
+		}
+	})
+	var deletes int
+	h.DB.Callback().Delete().Before("gorm:delete").Register("test:count_deletes", func(*gorm.DB) { deletes++ })
+
+	deleted, err := purgeViolationBatch(h.DB, "org", []string{"remediated"}, time.Now())
+	if err != nil || deleted != 0 {
+		t.Errorf("got %d, %v; want nothing to purge", deleted, err)
+	}
+	if deletes != 0 {
+		t.Errorf("got %d deletes, want none", deletes)
+	}
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"errors"
+
+	middleware "finopsbridge/api/internal/middleware_"
+	worker "finopsbridge/api/internal/worker_"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RemediateViolation runs the remediation of one pending violation's policy against its
+// provider on demand, even while enforcement is paused. ?dryRun=true reports the resources it
+// would act on and leaves the violation pending.
+func (h *Handlers) RemediateViolation(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+	id := c.Params("id")
+	dryRun := c.QueryBool("dryRun", false)
+
+	if h.Enforcement == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "Enforcement worker is not running",
+		})
+	}
+
+	violation, result, err := h.Enforcement.RemediateViolation(c.UserContext(), orgID, id, dryRun)
+	switch {
+	case errors.Is(err, worker.ErrViolationNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Violation not found",
+		})
+	case errors.Is(err, worker.ErrViolationNotPending),
+		errors.Is(err, worker.ErrRemediationNotSupported),
+		errors.Is(err, worker.ErrWebhookDryRun),
+		errors.Is(err, worker.ErrPolicyMonitorOnly):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case err != nil && violation.ID == "":
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to remediate violation",
+		})
+	}
+
+	h.logActivity(c.UserContext(), orgID, "remediation_manual", "Remediation of violation "+violation.ID+" was triggered manually", map[string]interface{}{
+		"violationId": violation.ID,
+		"policyId":    violation.PolicyID,
+		"dryRun":      dryRun,
+		"userId":      middleware.GetUserID(c),
+	})
+
+	response := fiber.Map{
+		"violationId": violation.ID,
+		"status":      violation.Status,
+		"dryRun":      dryRun,
+		"succeeded":   result.Succeeded,
+		"failed":      result.Failed,
+		"protected":   result.Protected,
+	}
+	if err != nil {
+		// The remediation ran but failed; its outcome is recorded on the violation
+		response["error"] = "Remediation failed: " + err.Error()
+		return c.Status(fiber.StatusBadGateway).JSON(response)
+	}
+	return c.JSON(response)
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	worker "finopsbridge/api/internal/worker_"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// remediateApp serves RemediateViolation for an organization
+func remediateApp(h *Handlers) *fiber.App {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("orgID", "org")
+		return c.Next()
+	})
+	app.Post("/violations/:id/remediate", h.RemediateViolation)
+	return app
+}
+
+func TestRemediateViolationWithoutWorker(t *testing.T) {
+	resp, err := remediateApp(dryRunHandlers(t)).Test(httptest.NewRequest("POST", "/violations/v1/remediate", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusServiceUnavailable {
+		t.Errorf("status %d, want 503", resp.StatusCode)
+	}
+}
+
+func TestRemediateViolationNotPending(t *testing.T) {
+	h := dryRunHandlers(t)
+	h.Enforcement = worker.NewEnforcementWorker(h.DB, nil, h.Config)
+	logged := recordActivity(t, h)
+
+	// The dry-run database finds a violation without a status
+	resp, err := remediateApp(h).Test(httptest.NewRequest("POST", "/violations/v1/remediate?dryRun=true", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusConflict {
+		t.Errorf("status %d, want 409", resp.StatusCode)
+	}
+	if len(*logged) != 0 {
+		t.Errorf("a rejected remediation shouldn't be logged, got %+v", *logged)
+	}
+}
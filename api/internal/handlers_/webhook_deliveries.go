@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+
+	middleware "finopsbridge/api/internal/middleware_"
+	models "finopsbridge/api/internal/models_"
+	webhooks "finopsbridge/api/internal/webhooks_"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ListWebhookDeliveries returns the most recent deliveries for a webhook
+func (h *Handlers) ListWebhookDeliveries(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+	id := c.Params("id")
+
+	var webhook models.Webhook
+	if err := h.DB.Where("id = ? AND organization_id = ?", id, orgID).First(&webhook).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Webhook not found",
+		})
+	}
+
+	var deliveries []models.WebhookDelivery
+	if err := h.DB.Where("webhook_id = ?", webhook.ID).
+		Order("created_at DESC").
+		Limit(100).
+		Find(&deliveries).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch webhook deliveries",
+		})
+	}
+
+	result := make([]map[string]interface{}, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		result = append(result, formatWebhookDelivery(delivery))
+	}
+
+	return c.JSON(result)
+}
+
+// ReplayWebhookDelivery intentionally re-sends a previous delivery, bypassing deduplication
+func (h *Handlers) ReplayWebhookDelivery(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+	id := c.Params("id")
+	deliveryID := c.Params("deliveryId")
+
+	var webhook models.Webhook
+	if err := h.DB.Where("id = ? AND organization_id = ?", id, orgID).First(&webhook).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Webhook not found",
+		})
+	}
+
+	var original models.WebhookDelivery
+	if err := h.DB.Where("id = ? AND webhook_id = ?", deliveryID, webhook.ID).First(&original).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Webhook delivery not found",
+		})
+	}
+
+	delivery, err := webhooks.Replay(h.DB, webhook, original)
+	if delivery == nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to replay webhook delivery",
+		})
+	}
+
+	h.logActivity(c.UserContext(), orgID, "webhook_replayed", "Webhook delivery was replayed", map[string]interface{}{
+		"webhookId":  webhook.ID,
+		"deliveryId": delivery.ID,
+		"replayOf":   original.ID,
+		"userId":     middleware.GetUserID(c),
+	})
+
+	if err != nil {
+		return c.Status(fiber.StatusBadGateway).JSON(formatWebhookDelivery(*delivery))
+	}
+
+	return c.JSON(formatWebhookDelivery(*delivery))
+}
+
+func formatWebhookDelivery(delivery models.WebhookDelivery) map[string]interface{} {
+	return map[string]interface{}{
+		"id":            delivery.ID,
+		"webhookId":     delivery.WebhookID,
+		"violationId":   delivery.ViolationID,
+		"status":        delivery.Status,
+		"statusCode":    delivery.StatusCode,
+		"error":         delivery.Error,
+		"attempts":      delivery.Attempts,
+		"replayOf":      delivery.ReplayOf,
+		"lastAttemptAt": delivery.LastAttemptAt,
+		"createdAt":     delivery.CreatedAt,
+	}
+}
+
+// EnableWebhook turns a webhook back on, typically after fixing a URL that was disabled for
+// repeated delivery failures
+func (h *Handlers) EnableWebhook(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+	id := c.Params("id")
+
+	var webhook models.Webhook
+	if err := h.DB.Where("id = ? AND organization_id = ?", id, orgID).First(&webhook).Error; err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Webhook not found",
+		})
+	}
+
+	if err := h.DB.Model(&webhook).Updates(map[string]interface{}{
+		"enabled":              true,
+		"consecutive_failures": 0,
+		"disabled_reason":      "",
+		"disabled_at":          nil,
+	}).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to enable webhook",
+		})
+	}
+
+	webhook.Enabled = true
+	webhook.ConsecutiveFailures = 0
+	webhook.DisabledReason = ""
+	webhook.DisabledAt = nil
+
+	h.logActivity(c.UserContext(), orgID, "webhook_enabled", "Webhook was re-enabled: "+webhook.URL, map[string]interface{}{
+		"webhookId": webhook.ID,
+		"userId":    middleware.GetUserID(c),
+	})
+
+	return c.JSON(webhook)
+}
+
+// webhookEvents returns the events a webhook is subscribed to, including the implicit default
+func webhookEvents(webhook models.Webhook) []string {
+	var events []string
+	if err := json.Unmarshal([]byte(webhook.Events), &events); err != nil || len(events) == 0 {
+		return webhooks.DefaultEvents
+	}
+	return events
+}
+
+// redactedHeaders lists a webhook's custom header names with their values hidden, since they
+// usually carry credentials
+func redactedHeaders(webhook models.Webhook) map[string]string {
+	redacted := make(map[string]string)
+	for name := range webhooks.Headers(webhook) {
+		redacted[name] = "[redacted]"
+	}
+	return redacted
+}
+
+// providerEvent describes a cloud provider being connected or disconnected by a user
+func providerEvent(eventType string, provider models.CloudProvider, userID, requestID string) webhooks.Event {
+	action, title := "connected", "Connected"
+	if eventType == webhooks.EventProviderDisconnected {
+		action, title = "disconnected", "Disconnected"
+	}
+
+	return webhooks.Event{
+		Type:    eventType,
+		Title:   "Cloud Provider " + title,
+		Message: fmt.Sprintf("Cloud provider '%s' (%s) was %s", provider.Name, provider.Type, action),
+		Fields: []webhooks.EventField{
+			{Name: "Provider", Value: provider.Name},
+			{Name: "Type", Value: provider.Type},
+			{Name: "Actor", Value: userID},
+		},
+		Data: map[string]interface{}{
+			"providerId":   provider.ID,
+			"providerName": provider.Name,
+			"providerType": provider.Type,
+			"actor":        userID,
+		},
+		RequestID: requestID,
+	}
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	models "finopsbridge/api/internal/models_"
+	webhooks "finopsbridge/api/internal/webhooks_"
+)
+
+func TestWebhookEvents(t *testing.T) {
+	if got := webhookEvents(models.Webhook{}); !reflect.DeepEqual(got, webhooks.DefaultEvents) {
+		t.Errorf("got %v, want the default events", got)
+	}
+	if got := webhookEvents(models.Webhook{Events: `["digest"]`}); !reflect.DeepEqual(got, []string{"digest"}) {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestProviderEvent(t *testing.T) {
+	provider := models.CloudProvider{ID: "p1", Name: "prod", Type: "aws"}
+
+	event := providerEvent(webhooks.EventProviderDisconnected, provider, "user_1", "req-1")
+	if event.Title != "Cloud Provider Disconnected" || event.Message != "Cloud provider 'prod' (aws) was disconnected" {
+		t.Errorf("got %q: %q", event.Title, event.Message)
+	}
+	if event.Data["providerId"] != "p1" || event.Data["actor"] != "user_1" || event.RequestID != "req-1" {
+		t.Errorf("got %+v", event)
+	}
+
+	if event := providerEvent(webhooks.EventProviderConnected, provider, "user_1", ""); event.Title != "Cloud Provider Connected" {
+		t.Errorf("got %q", event.Title)
+	}
+}
+
+func TestRedactedHeaders(t *testing.T) {
+	got := redactedHeaders(models.Webhook{Headers: `{"Authorization":"Bearer secret","X-Team":"finops"}`})
+	want := map[string]string{"Authorization": "[redacted]", "X-Team": "[redacted]"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := redactedHeaders(models.Webhook{}); got == nil || len(got) != 0 {
+		t.Errorf("got %#v, want an empty map so it encodes as {}", got)
+	}
+}
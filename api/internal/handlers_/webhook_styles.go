@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+
+	middleware "finopsbridge/api/internal/middleware_"
+	webhooks "finopsbridge/api/internal/webhooks_"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetWebhookStyles returns the organization's severity emoji and color overrides for
+// webhook notifications
+func (h *Handlers) GetWebhookStyles(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+
+	org, err := h.getOrganization(orgID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to load organization",
+		})
+	}
+
+	styles, err := webhooks.ParseSeverityStyles(org.WebhookSeverityStyles)
+	if err != nil {
+		// Stored styles are validated on write, so treat a bad value as unset
+		styles = webhooks.SeverityStyles{}
+	}
+
+	return c.JSON(styles)
+}
+
+// UpdateWebhookStyles replaces the organization's severity emoji and color overrides.
+// Severities left out render with the defaults.
+func (h *Handlers) UpdateWebhookStyles(c *fiber.Ctx) error {
+	orgID := middleware.GetOrgID(c)
+
+	var req webhooks.SeverityStyles
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	stylesJSON, _ := json.Marshal(req)
+	if _, err := webhooks.ParseSeverityStyles(string(stylesJSON)); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	org, err := h.getOrganization(orgID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to load organization",
+		})
+	}
+
+	if err := h.DB.Model(org).Update("webhook_severity_styles", string(stylesJSON)).Error; err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to update webhook styles",
+		})
+	}
+
+	h.logActivity(c.UserContext(), orgID, "webhook_styles_updated", "Webhook severity styles were updated", map[string]interface{}{
+		"userId": middleware.GetUserID(c),
+	})
+
+	return c.JSON(req)
+}
@@ -38,6 +38,7 @@ func ClerkAuth(secretKey string) fiber.Handler {
 		// Store user info in context
 		c.Locals("userID", claims.Subject)
 		c.Locals("orgID", claims.ActiveOrganizationID)
+		c.Locals("orgRole", claims.ActiveOrganizationRole)
 		c.Locals("sessionClaims", claims)
 
 		return c.Next()
@@ -58,3 +59,24 @@ func GetOrgID(c *fiber.Ctx) string {
 	return ""
 }
 
+
+func GetOrgRole(c *fiber.Ctx) string {
+	if orgRole, ok := c.Locals("orgRole").(string); ok {
+		return orgRole
+	}
+	return ""
+}
+
+// RequireOrgAdmin rejects requests from users who are not admins of the active organization
+func RequireOrgAdmin() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		role := GetOrgRole(c)
+		if role != "org:admin" && role != "admin" {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Organization admin role required",
+			})
+		}
+
+		return c.Next()
+	}
+}
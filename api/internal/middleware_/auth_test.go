@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// adminApp serves an admin-only route to a caller with the given organization role
+func adminApp(role string) *fiber.App {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		if role != "" {
+			c.Locals("orgRole", role)
+		}
+		return c.Next()
+	})
+	app.Post("/enforcement/pause", RequireOrgAdmin(), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+	return app
+}
+
+func TestRequireOrgAdmin(t *testing.T) {
+	tests := []struct {
+		role string
+		want int
+	}{
+		{"org:admin", fiber.StatusNoContent},
+		{"admin", fiber.StatusNoContent},
+		{"org:member", fiber.StatusForbidden},
+		{"", fiber.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		resp, err := adminApp(tt.role).Test(httptest.NewRequest("POST", "/enforcement/pause", nil))
+		if err != nil {
+			t.Fatalf("role %q: %v", tt.role, err)
+		}
+		if resp.StatusCode != tt.want {
+			t.Errorf("role %q: status %d, want %d", tt.role, resp.StatusCode, tt.want)
+		}
+	}
+}
+
+func TestGetOrgRoleWithoutLocals(t *testing.T) {
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(GetOrgRole(c) + "|" + GetOrgID(c) + "|" + GetUserID(c))
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := make([]byte, 8)
+	n, _ := resp.Body.Read(body)
+	if got := string(body[:n]); got != "||" {
+		t.Errorf("got %q, want empty role, org and user", got)
+	}
+}
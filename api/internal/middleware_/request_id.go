@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	tracing "finopsbridge/api/internal/tracing_"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// RequestID assigns each request a correlation ID, reusing a valid X-Request-ID sent by the
+// client. The ID is echoed in the response, stored in the request's locals for the access log,
+// and carried by its user context so downstream records can be tied back to it.
+func RequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Copied, as header values are only valid until the handler returns
+		id := utils.CopyString(c.Get(tracing.Header))
+		if !tracing.ValidID(id) {
+			id = tracing.NewID()
+		}
+
+		c.Locals("requestID", id)
+		c.Set(tracing.Header, id)
+		c.SetUserContext(tracing.WithID(c.UserContext(), id))
+
+		return c.Next()
+	}
+}
+
+func GetRequestID(c *fiber.Ctx) string {
+	if requestID, ok := c.Locals("requestID").(string); ok {
+		return requestID
+	}
+	return ""
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	tracing "finopsbridge/api/internal/tracing_"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// requestIDApp echoes the request ID seen in locals and in the user context
+func requestIDApp() *fiber.App {
+	app := fiber.New()
+	app.Use(RequestID())
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(GetRequestID(c) + "|" + tracing.FromContext(c.UserContext()))
+	})
+	return app
+}
+
+func TestRequestIDReusesValidHeader(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(tracing.Header, "client-req-42")
+
+	resp, err := requestIDApp().Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if got := string(body); got != "client-req-42|client-req-42" {
+		t.Errorf("got %q, want the client's ID in locals and context", got)
+	}
+	if got := resp.Header.Get(tracing.Header); got != "client-req-42" {
+		t.Errorf("response header = %q, want the client's ID echoed", got)
+	}
+}
+
+func TestRequestIDReplacesInvalidHeader(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set(tracing.Header, "not valid!")
+
+	resp, err := requestIDApp().Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := resp.Header.Get(tracing.Header)
+	if id == "not valid!" || !tracing.ValidID(id) {
+		t.Errorf("got %q, want a generated ID", id)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if got := string(body); got != id+"|"+id {
+		t.Errorf("got %q, want the generated ID %q in locals and context", got, id)
+	}
+}
@@ -0,0 +1,17 @@
+package models
+
+import "gorm.io/gorm"
+
+// gpuSampleHoursSQL selects the hours a GPU metrics row covers, as sample_hours: its
+// IntervalSeconds, or when unset the gap since the instance's previous sample, at most an hour
+const gpuSampleHoursSQL = `(CASE WHEN interval_seconds > 0 THEN interval_seconds
+	ELSE COALESCE(NULLIF(LEAST(EXTRACT(EPOCH FROM timestamp - LAG(timestamp) OVER (PARTITION BY instance_id ORDER BY timestamp)), 3600), 0), 3600)
+	END) / 3600.0 AS sample_hours`
+
+// GPUSamples wraps a GPUMetrics query as the gpu_samples table, which adds each row's
+// sample_hours. Weighting cost, GPU hours and utilization by sample_hours makes SQL aggregates
+// agree with the dashboard's GPU stats, computed the same way in Go. Previous samples are only
+// looked for among the rows the query selects.
+func GPUSamples(db *gorm.DB, query *gorm.DB) *gorm.DB {
+	return db.Table("(?) AS gpu_samples", query.Select("gpu_metrics.*, "+gpuSampleHoursSQL))
+}
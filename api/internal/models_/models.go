@@ -1,8 +1,13 @@
 package models
 
 import (
+	"crypto/rand"
+	"encoding/json"
+	"strconv"
 	"time"
 
+	tracing "finopsbridge/api/internal/tracing_"
+
 	"gorm.io/gorm"
 )
 
@@ -20,6 +25,13 @@ type Organization struct {
 	ID            string `gorm:"primaryKey"`
 	ClerkOrgID    string `gorm:"uniqueIndex;not null"`
 	Name          string
+	EnforcementPaused   bool `gorm:"default:false"` // Global kill switch for remediation
+	EnforcementPausedAt *time.Time
+	EnforcementPausedBy string
+	WebhookSeverityStyles string `gorm:"type:text"` // JSON: {"emoji": {"high": "🔴"}, "colors": {"high": "#FF0000"}}
+	DigestCadence    string     // daily, weekly, monthly; empty disables the spend digest
+	DigestWebhookID  string     // Webhook the digest is sent to; empty for every webhook subscribed to digest
+	DigestLastSentAt *time.Time
 	CreatedAt     time.Time
 	UpdatedAt     time.Time
 	Users         []User           `gorm:"many2many:user_organizations;"`
@@ -37,12 +49,72 @@ type CloudProvider struct {
 	ProjectID      string
 	Status         string `gorm:"default:disconnected"` // connected, disconnected, error
 	Credentials    string `gorm:"type:text"`            // JSON encrypted credentials
+	GroupID        string `gorm:"index"`                // Set when spawned from a CloudProviderGroup
 	MonthlySpend   float64
 	ConnectedAt    *time.Time
+	LastSyncAt     *time.Time // Last successful billing fetch
+	LastSyncError  string     `gorm:"type:text"` // Error from the latest billing fetch; empty when it succeeded
+	LastSyncWarning string    `gorm:"type:text"` // Caveat on the latest successful fetch, e.g. truncated usage; empty when none
+	LastSyncAttemptAt  *time.Time // Latest billing fetch attempt, successful or not
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 }
 
+// CloudProviderGroup spawns one CloudProvider per account from a shared credential template.
+// "{accountId}" in any template value is replaced with each member's account ID.
+type CloudProviderGroup struct {
+	ID                 string `gorm:"primaryKey"`
+	OrganizationID     string `gorm:"index;not null"`
+	Type               string `gorm:"not null"` // aws, azure, gcp
+	Name               string `gorm:"not null"`
+	CredentialTemplate string `gorm:"type:text"` // JSON credentials with {accountId} placeholders
+	AccountIDs         string `gorm:"type:text"` // JSON array of member account IDs
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// SpendSnapshot is a provider's month-to-date spend as of a given day, recorded by the worker
+type SpendSnapshot struct {
+	ID               string    `gorm:"primaryKey"`
+	OrganizationID   string    `gorm:"index;not null"`
+	ProviderID       string    `gorm:"uniqueIndex:idx_spend_snapshot_day;not null"`
+	ProviderType     string    `gorm:"not null"`
+	Date             time.Time `gorm:"uniqueIndex:idx_spend_snapshot_day;type:date;not null"`
+	MonthToDateSpend float64
+	Currency         string `gorm:"default:USD"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// Budget is one level of an organization's spend budget hierarchy (organization, team,
+// project). Spend is attributed by a tag (AWS) or label (GCP); a budget without one rolls up
+// its children's spend, or the organization's total spend at the root.
+type Budget struct {
+	ID             string `gorm:"primaryKey"`
+	OrganizationID string `gorm:"index;not null"`
+	ParentID       string `gorm:"index"` // Empty for a root budget
+	Name           string `gorm:"not null"`
+	Level          string `gorm:"not null"` // organization, team, project
+	TagKey         string // e.g. "team"; empty to roll up children
+	TagValue       string
+	Amount         float64 // Monthly limit in the reporting currency
+	CurrentSpend   float64 // Month-to-date spend as of the last evaluation
+	EvaluatedAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// ProtectedResource is a resource remediation must never act on, whatever its tags
+type ProtectedResource struct {
+	ID             string `gorm:"primaryKey"`
+	OrganizationID string `gorm:"index;not null"`
+	ResourceID     string `gorm:"not null"` // Instance ID, Azure VM resource ID or VM name, or GCP instance ID or name
+	CloudProvider  string // aws, azure, gcp, oci, ibm; empty for any provider
+	Reason         string
+	CreatedBy      string
+	CreatedAt      time.Time
+}
+
 type Policy struct {
 	ID             string `gorm:"primaryKey"`
 	OrganizationID string `gorm:"index;not null"`
@@ -51,32 +123,125 @@ type Policy struct {
 	Type           string `gorm:"not null"` // max_spend, block_instance_type, auto_stop_idle, require_tags
 	Enabled        bool   `gorm:"default:true"`
 	Rego           string `gorm:"type:text;not null"`
+	RegoPackage    string // Package declared by Rego, e.g. finopsbridge.policies or llm_token_budget
 	Config         string `gorm:"type:text"` // JSON config
+	Mode           string `gorm:"not null;default:enforce"` // enforce or monitor; see PolicyModeMonitor
+	Tags           string `gorm:"type:text"` // JSON array of lower-case tags, copied from the template on deploy
+	RemediationWebhookHeaders string `gorm:"type:text" json:"-"` // JSON: extra headers for a webhook-mode remediation, e.g. Authorization; never returned by the API
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 	Violations     []PolicyViolation `gorm:"foreignKey:PolicyID"`
+
+	// Running enforcement metrics, updated by the worker
+	FireCount               int        `gorm:"default:0"` // Violations the policy has produced
+	LastFiredAt             *time.Time // When it last produced one
+	RemediationCount        int        `gorm:"default:0"` // Violations remediated automatically
+	RemediationSecondsTotal float64    `gorm:"default:0"` // Summed time from violation to remediation
+	MonthlySavingsTotal     float64    `gorm:"default:0"` // Summed monthly on-demand cost of the instances it terminated
+}
+
+// Policy modes. Policies enforce by default, as they did before modes existed; a monitor policy
+// records violations and notifies, but never remediates, whatever its type.
+const (
+	PolicyModeEnforce = "enforce"
+	PolicyModeMonitor = "monitor"
+)
+
+// ValidPolicyMode reports whether mode is a policy mode
+func ValidPolicyMode(mode string) bool {
+	return mode == PolicyModeEnforce || mode == PolicyModeMonitor
 }
 
 type PolicyViolation struct {
-	ID            string `gorm:"primaryKey"`
-	PolicyID      string `gorm:"index;not null"`
-	ResourceID    string `gorm:"not null"`
-	ResourceType  string `gorm:"not null"`
-	CloudProvider string `gorm:"not null"`
-	Message       string `gorm:"type:text"`
-	Severity      string `gorm:"default:medium"` // low, medium, high, critical
-	Status        string `gorm:"default:pending"` // pending, remediated, ignored
-	CreatedAt     time.Time
-	RemediatedAt  *time.Time
+	ID                string `gorm:"primaryKey"`
+	PolicyID          string `gorm:"index;not null"`
+	ResourceID        string `gorm:"not null"`
+	ResourceType      string `gorm:"not null"`
+	CloudProvider     string `gorm:"not null"`
+	ProviderID        string `gorm:"index"` // Provider of an instance violation; empty when ResourceID is the provider
+	Message           string `gorm:"type:text"`
+	Severity          string `gorm:"default:medium"`  // low, medium, high, critical
+	Status            string `gorm:"default:pending"` // pending, remediated, ignored
+	CreatedAt         time.Time
+	RemediatedAt      *time.Time
+	EscalatedAt       *time.Time // Set once the violation outlived its policy's escalation SLA
+	StopAfter         *time.Time // Grace deadline while remediation waits after notifying; nil once acted on
+	ActionsSucceeded  int        // Remediation actions that succeeded
+	ActionsFailed     int        // Remediation actions that failed; the violation stays pending
+	RemediationErrors string     `gorm:"type:text"` // JSON array of cloud.ResourceError
+	BudgetLevel       string     // Level of the breached budget, for budget_hierarchy violations
+	SpendLimitTier    string     // soft or hard: the limit crossed, for max_spend violations under tiered limits
+	CostContributors  string     `gorm:"type:text"` // JSON array of cloud.CostBreakdownItem: the services costing most, for spend violations
+	RequestID         string     `gorm:"index"` // Correlation ID of the enforcement run that created it
+}
+
+// ViolationComment is a team member's note on a violation, so it can be discussed and followed
+// up without an external tracker
+type ViolationComment struct {
+	ID             string `gorm:"primaryKey"`
+	ViolationID    string `gorm:"index;not null"`
+	OrganizationID string `gorm:"index;not null"`
+	Author         string `gorm:"not null"` // Clerk user ID of the commenter
+	AuthorName     string // Name or email at the time of commenting, when the user is known
+	Body           string `gorm:"type:text;not null"`
+	CreatedAt      time.Time
 }
 
 type ActivityLog struct {
-	ID        string `gorm:"primaryKey"`
+	ID             string `gorm:"primaryKey"`
 	OrganizationID string `gorm:"index;not null"`
-	Type      string `gorm:"not null"` // policy_violation, remediation, policy_created, etc.
-	Message   string `gorm:"type:text;not null"`
-	Metadata  string `gorm:"type:text"` // JSON metadata
-	CreatedAt time.Time
+	Type           string `gorm:"not null"` // policy_violation, remediation, policy_created, etc.
+	Message        string `gorm:"type:text;not null"`
+	Metadata       string `gorm:"type:text"` // JSON metadata
+	RequestID      string `gorm:"index"`     // Correlation ID of the API request or enforcement run behind it
+	CreatedAt      time.Time
+}
+
+// EnforcementRun records one worker pass for an organization
+type EnforcementRun struct {
+	ID                 string `gorm:"primaryKey"`
+	OrganizationID     string `gorm:"index;not null"`
+	StartedAt          time.Time
+	CompletedAt        *time.Time
+	ProvidersProcessed int
+	Skipped            string `gorm:"type:text"` // JSON array of SkippedProvider
+	Degraded           bool   // Some provider's remediation could not proceed
+	DegradedProviders  string `gorm:"type:text"` // JSON array of SkippedProvider with reason remediation_failed
+	ActionsSucceeded   int    // Remediation actions across the org's providers that succeeded
+	ActionsFailed      int    // Remediation actions that failed
+	PolicyErrors       string `gorm:"type:text"` // JSON array of PolicyError for policies that panicked
+	RequestID          string `gorm:"index"`     // Correlation ID shared by the pass's violations
+	CreatedAt          time.Time
+}
+
+// DecisionLog records one policy evaluation by the enforcement worker, for auditing.
+// Only written when DECISION_LOG_ENABLED is set.
+type DecisionLog struct {
+	ID             string    `gorm:"primaryKey"`
+	OrganizationID string    `gorm:"index;not null"`
+	PolicyID       string    `gorm:"index;not null"`
+	ResourceID     string    // Cloud provider, or the AI resource the input describes
+	InputHash      string    // SHA-256 of the JSON input document
+	Decision       string    // allow, deny, error
+	Error          string    `gorm:"type:text"`
+	CreatedAt      time.Time `gorm:"index"`
+}
+
+// SkippedProvider explains why the worker took no action for a provider during a run
+type SkippedProvider struct {
+	ProviderID   string `json:"providerId"`
+	ProviderName string `json:"providerName"`
+	Reason       string `json:"reason"` // enforcement_paused, credential_error, unsupported_provider, remediation_failed
+	Detail       string `json:"detail,omitempty"`
+}
+
+// PolicyError records a policy whose evaluation or enforcement panicked during a run. The
+// rest of the run carries on without it.
+type PolicyError struct {
+	PolicyID   string `json:"policyId"`
+	PolicyName string `json:"policyName"`
+	ProviderID string `json:"providerId,omitempty"` // Empty for org-wide evaluations
+	Error      string `json:"error"`
 }
 
 type WaitlistEntry struct {
@@ -93,10 +258,34 @@ type Webhook struct {
 	Type           string `gorm:"not null"` // slack, discord, teams
 	URL            string `gorm:"not null"`
 	Enabled        bool   `gorm:"default:true"`
+	Events         string `gorm:"type:text"` // JSON: subscribed event types; empty means webhooks.DefaultEvents
+	Headers        string `gorm:"type:text" json:"-"` // JSON: extra request headers, e.g. Authorization; never returned by the API
+	ConsecutiveFailures int `gorm:"default:0"` // Reset on a successful delivery
+	DisabledReason string     // Set when the webhook was disabled automatically
+	DisabledAt     *time.Time
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 }
 
+// WebhookDelivery records a notification sent to a webhook. DedupKey is unique per webhook
+// so the same violation is not notified twice in a period.
+type WebhookDelivery struct {
+	ID             string `gorm:"primaryKey"`
+	OrganizationID string `gorm:"index;not null"`
+	WebhookID      string `gorm:"uniqueIndex:idx_webhook_delivery_dedup;not null"`
+	ViolationID    string `gorm:"index"`
+	DedupKey       string `gorm:"uniqueIndex:idx_webhook_delivery_dedup;not null"`
+	Payload        string `gorm:"type:text"`
+	Status         string `gorm:"default:pending"` // pending, sent, failed
+	StatusCode     int
+	Error          string `gorm:"type:text"`
+	Attempts       int
+	ReplayOf       string // ID of the delivery this one replays
+	ClaimedAt      *time.Time // When a sender last took the delivery; a stale pending claim is retried
+	LastAttemptAt  *time.Time
+	CreatedAt      time.Time
+}
+
 type PolicyCategory struct {
 	ID          string `gorm:"primaryKey"`
 	Name        string `gorm:"not null;uniqueIndex"`
@@ -132,7 +321,7 @@ type PolicyRecommendation struct {
 	ID                string `gorm:"primaryKey"`
 	OrganizationID    string `gorm:"index;not null"`
 	PolicyTemplateID  string `gorm:"index;not null"`
-	Status            string `gorm:"default:pending"` // pending, accepted, rejected, deployed
+	Status            string `gorm:"default:pending"` // pending, accepted, rejected, deployed, snoozed
 	ConfidenceScore   float64 // 0.0 to 1.0
 	EstimatedMonthlySavings float64
 	RecommendationReason string `gorm:"type:text"` // AI-generated explanation
@@ -144,6 +333,7 @@ type PolicyRecommendation struct {
 	DeployedAt        *time.Time
 	RejectedAt        *time.Time
 	RejectionReason   string
+	SnoozedUntil      *time.Time // Hidden and not regenerated until then
 }
 
 type PolicyAdoptionMetrics struct {
@@ -192,10 +382,28 @@ type TokenUsage struct {
 	TotalTokens    int64
 	Cost           float64
 	CachedTokens   int64 // Cached prompt tokens (cost savings)
+	CacheSavings   float64 // Cost avoided by billing CachedTokens at the catalog's cached input price
 	RequestCount   int   // Number of API calls
 	Timestamp      time.Time
 	CreatedAt      time.Time
 	Metadata       string `gorm:"type:text"` // JSON: user_id, feature, prompt_template, etc.
+	Team           string `gorm:"index"` // Promoted from Metadata on create, for attribution queries
+	UserID         string `gorm:"index"` // Promoted from Metadata's user_id on create
+}
+
+// TokenCostBaseline is one model's rolling daily token cost baseline, as of a token_cost_anomaly
+// policy's last evaluation
+type TokenCostBaseline struct {
+	ID             string    `gorm:"primaryKey"`
+	OrganizationID string    `gorm:"index;not null"`
+	PolicyID       string    `gorm:"uniqueIndex:idx_token_cost_baseline;not null"`
+	ModelName      string    `gorm:"uniqueIndex:idx_token_cost_baseline;not null"`
+	Date           time.Time `gorm:"type:date"` // UTC day DailyCost was measured on
+	DailyCost      float64
+	AverageCost    float64 // Average daily cost over the BaselineDays days before Date
+	BaselineDays   int
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
 }
 
 type GPUMetrics struct {
@@ -211,11 +419,49 @@ type GPUMetrics struct {
 	MemoryUsed     float64 // GB
 	MemoryTotal    float64 // GB
 	HourlyCost     float64
+	IntervalSeconds int // Sampling period this row represents; 0 means infer from timestamps
 	Status         string `gorm:"default:running"` // running, idle, stopped
 	Timestamp      time.Time
 	CreatedAt      time.Time
 	Metadata       string `gorm:"type:text"` // JSON: region, availability_zone, etc.
-}
+	Team           string `gorm:"index"` // Promoted from Metadata on create, for attribution queries
+	Region         string `gorm:"index"` // Promoted from Metadata on create
+}
+
+// UsageRollup holds downsampled TokenUsage or GPUMetrics rows produced by the retention job
+type UsageRollup struct {
+	ID                 string    `gorm:"primaryKey"`
+	OrganizationID     string    `gorm:"index;not null"`
+	Source             string    `gorm:"index;not null"` // token_usage, gpu_metrics
+	Granularity        string    `gorm:"not null"`       // daily, monthly
+	PeriodStart        time.Time `gorm:"index"`
+	AIWorkloadID       string
+	Provider           string
+	ModelName          string // token usage only
+	InstanceType       string // GPU metrics only
+	GPUType            string // GPU metrics only
+	Team               string `gorm:"index"`
+	UserID             string `gorm:"index"` // token usage only
+	InputTokens        int64
+	OutputTokens       int64
+	TotalTokens        int64
+	CachedTokens       int64
+	CacheSavings       float64 // token usage only
+	RequestCount       int64
+	Cost               float64
+	GPUHours           float64
+	IdleGPUHours       float64
+	AverageUtilization float64
+	SampleCount        int64 // raw rows represented
+	CreatedAt          time.Time
+}
+
+// UsageRollup sources. Rolled-up raw rows are deleted, so readers of usage older than the raw
+// retention window add the rollups of the same source.
+const (
+	RollupSourceTokenUsage = "token_usage"
+	RollupSourceGPUMetrics = "gpu_metrics"
+)
 
 type AIBudget struct {
 	ID               string `gorm:"primaryKey"`
@@ -233,6 +479,9 @@ type AIBudget struct {
 	UpdatedAt        time.Time
 }
 
+// CatalogSourceManual is the Source of model catalog entries created without one
+const CatalogSourceManual = "manual"
+
 type AIModelCatalog struct {
 	ID                string `gorm:"primaryKey"`
 	Provider          string `gorm:"not null;index"` // openai, anthropic, azure, aws, gcp
@@ -240,10 +489,13 @@ type AIModelCatalog struct {
 	ModelVersion      string
 	InputPricePerMToken  float64 // Price per million tokens
 	OutputPricePerMToken float64
+	CachedInputPricePerMToken float64 // Discounted price for cached prompt tokens; 0 bills them at the input price
 	ContextWindow     int // Maximum context length
 	Category          string // llm, embedding, fine_tuning, image_generation
 	Capabilities      string `gorm:"type:text"` // JSON: ["text", "vision", "function_calling"]
 	IsAvailable       bool   `gorm:"default:true"`
+	Source            string     // Where the prices came from, e.g. a pricing page URL; CatalogSourceManual when not given
+	PricesUpdatedAt   *time.Time // When the prices were last checked against Source; unlike UpdatedAt, not moved by other edits
 	UpdatedAt         time.Time
 	CreatedAt         time.Time
 }
@@ -270,6 +522,34 @@ func (cp *CloudProvider) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+func (cpg *CloudProviderGroup) BeforeCreate(tx *gorm.DB) error {
+	if cpg.ID == "" {
+		cpg.ID = generateID()
+	}
+	return nil
+}
+
+func (ss *SpendSnapshot) BeforeCreate(tx *gorm.DB) error {
+	if ss.ID == "" {
+		ss.ID = generateID()
+	}
+	return nil
+}
+
+func (b *Budget) BeforeCreate(tx *gorm.DB) error {
+	if b.ID == "" {
+		b.ID = generateID()
+	}
+	return nil
+}
+
+func (p *ProtectedResource) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == "" {
+		p.ID = generateID()
+	}
+	return nil
+}
+
 func (p *Policy) BeforeCreate(tx *gorm.DB) error {
 	if p.ID == "" {
 		p.ID = generateID()
@@ -281,6 +561,9 @@ func (pv *PolicyViolation) BeforeCreate(tx *gorm.DB) error {
 	if pv.ID == "" {
 		pv.ID = generateID()
 	}
+	if pv.RequestID == "" {
+		pv.RequestID = tracing.FromContext(tx.Statement.Context)
+	}
 	return nil
 }
 
@@ -288,6 +571,16 @@ func (al *ActivityLog) BeforeCreate(tx *gorm.DB) error {
 	if al.ID == "" {
 		al.ID = generateID()
 	}
+	if al.RequestID == "" {
+		al.RequestID = tracing.FromContext(tx.Statement.Context)
+	}
+	return nil
+}
+
+func (er *EnforcementRun) BeforeCreate(tx *gorm.DB) error {
+	if er.ID == "" {
+		er.ID = generateID()
+	}
 	return nil
 }
 
@@ -305,6 +598,13 @@ func (w *Webhook) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+func (wd *WebhookDelivery) BeforeCreate(tx *gorm.DB) error {
+	if wd.ID == "" {
+		wd.ID = generateID()
+	}
+	return nil
+}
+
 func (pc *PolicyCategory) BeforeCreate(tx *gorm.DB) error {
 	if pc.ID == "" {
 		pc.ID = generateID()
@@ -344,6 +644,27 @@ func (tu *TokenUsage) BeforeCreate(tx *gorm.DB) error {
 	if tu.ID == "" {
 		tu.ID = generateID()
 	}
+	fields := metadataFields(tu.Metadata)
+	if tu.Team == "" {
+		tu.Team = MetadataText(fields, "team")
+	}
+	if tu.UserID == "" {
+		tu.UserID = MetadataText(fields, "user_id")
+	}
+	return nil
+}
+
+func (vc *ViolationComment) BeforeCreate(tx *gorm.DB) error {
+	if vc.ID == "" {
+		vc.ID = generateID()
+	}
+	return nil
+}
+
+func (tcb *TokenCostBaseline) BeforeCreate(tx *gorm.DB) error {
+	if tcb.ID == "" {
+		tcb.ID = generateID()
+	}
 	return nil
 }
 
@@ -351,6 +672,43 @@ func (gm *GPUMetrics) BeforeCreate(tx *gorm.DB) error {
 	if gm.ID == "" {
 		gm.ID = generateID()
 	}
+	fields := metadataFields(gm.Metadata)
+	if gm.Team == "" {
+		gm.Team = MetadataText(fields, "team")
+	}
+	if gm.Region == "" {
+		gm.Region = MetadataText(fields, "region")
+	}
+	return nil
+}
+
+// metadataFields parses a JSON metadata object, returning nil when it isn't one
+func metadataFields(metadata string) map[string]interface{} {
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(metadata), &fields); err != nil {
+		return nil
+	}
+	return fields
+}
+
+// MetadataText returns a metadata field as the text of its promoted column, the way Postgres'
+// ->> operator renders strings, numbers and booleans. Objects and arrays aren't promoted.
+func MetadataText(fields map[string]interface{}, key string) string {
+	switch value := fields[key].(type) {
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	}
+	return ""
+}
+
+func (ur *UsageRollup) BeforeCreate(tx *gorm.DB) error {
+	if ur.ID == "" {
+		ur.ID = generateID()
+	}
 	return nil
 }
 
@@ -365,18 +723,59 @@ func (amc *AIModelCatalog) BeforeCreate(tx *gorm.DB) error {
 	if amc.ID == "" {
 		amc.ID = generateID()
 	}
+	if amc.PricesUpdatedAt == nil {
+		now := time.Now()
+		amc.PricesUpdatedAt = &now
+	}
+	if amc.Source == "" {
+		amc.Source = CatalogSourceManual
+	}
+	return nil
+}
+
+// BeforeUpdate moves PricesUpdatedAt when an update changes a price, unless the update sets
+// it itself, so other edits such as availability don't make stale prices look fresh
+func (amc *AIModelCatalog) BeforeUpdate(tx *gorm.DB) error {
+	if tx.Statement.Changed("PricesUpdatedAt") {
+		return nil
+	}
+	if tx.Statement.Changed("InputPricePerMToken", "OutputPricePerMToken", "CachedInputPricePerMToken") {
+		tx.Statement.SetColumn("PricesUpdatedAt", time.Now())
+	}
+	return nil
+}
+
+func (dl *DecisionLog) BeforeCreate(tx *gorm.DB) error {
+	if dl.ID == "" {
+		dl.ID = generateID()
+	}
 	return nil
 }
 
+// generateID returns a new record ID: the UTC creation time, so IDs sort roughly by age, and
+// a random suffix that keeps IDs created in the same second unique
 func generateID() string {
-	return time.Now().Format("20060102150405") + randomString(8)
+	return time.Now().UTC().Format("20060102150405") + randomString(16)
 }
 
+// randomString returns length characters drawn uniformly from letters and digits by
+// crypto/rand. 16 of them carry about 95 bits of randomness.
 func randomString(length int) string {
 	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
-	b := make([]byte, length)
-	for i := range b {
-		b[i] = charset[i%len(charset)]
+	// Bytes at or above the largest multiple of len(charset) are rejected, avoiding modulo bias
+	const limit = 256 - 256%len(charset)
+
+	b := make([]byte, 0, length)
+	buf := make([]byte, length)
+	for len(b) < length {
+		if _, err := rand.Read(buf); err != nil {
+			panic("models: reading random bytes: " + err.Error())
+		}
+		for _, v := range buf {
+			if int(v) < limit && len(b) < length {
+				b = append(b, charset[int(v)%len(charset)])
+			}
+		}
 	}
 	return string(b)
 }
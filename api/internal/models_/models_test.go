@@ -0,0 +1,109 @@
+package models
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestGenerateIDUniqueInTightLoop(t *testing.T) {
+	// Every ID within the same second shares its timestamp prefix, so uniqueness rests on
+	// the random suffix
+	const n = 100000
+	seen := make(map[string]bool, n)
+	for i := 0; i < n; i++ {
+		id := generateID()
+		if seen[id] {
+			t.Fatalf("duplicate ID %q after %d IDs", id, i)
+		}
+		seen[id] = true
+	}
+}
+
+func TestGenerateIDUniqueAcrossGoroutines(t *testing.T) {
+	const goroutines, perGoroutine = 8, 10000
+	ids := make(chan string, goroutines*perGoroutine)
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perGoroutine; i++ {
+				ids <- generateID()
+			}
+		}()
+	}
+	wg.Wait()
+	close(ids)
+
+	seen := make(map[string]bool, goroutines*perGoroutine)
+	for id := range ids {
+		if seen[id] {
+			t.Fatalf("duplicate ID %q", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestRandomStringCharset(t *testing.T) {
+	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	for i := 0; i < 1000; i++ {
+		s := randomString(16)
+		if len(s) != 16 {
+			t.Fatalf("randomString(16) returned %d characters", len(s))
+		}
+		if strings.Trim(s, charset) != "" {
+			t.Fatalf("randomString returned characters outside the charset: %q", s)
+		}
+	}
+}
+
+func TestValidPolicyMode(t *testing.T) {
+	for _, mode := range []string{PolicyModeEnforce, PolicyModeMonitor} {
+		if !ValidPolicyMode(mode) {
+			t.Errorf("%q should be valid", mode)
+		}
+	}
+	for _, mode := range []string{"", "Monitor", "audit"} {
+		if ValidPolicyMode(mode) {
+			t.Errorf("%q should be invalid", mode)
+		}
+	}
+}
+
+func TestMetadataText(t *testing.T) {
+	fields := metadataFields(`{"team": "ml", "user_id": 1000000, "gpu": true, "ratio": 0.5, "tags": ["a"], "region": null}`)
+	tests := map[string]string{
+		"team":    "ml",
+		"user_id": "1000000",
+		"gpu":     "true",
+		"ratio":   "0.5",
+		"tags":    "",
+		"region":  "",
+		"missing": "",
+	}
+	for key, want := range tests {
+		if got := MetadataText(fields, key); got != want {
+			t.Errorf("MetadataText(%q) = %q, want %q", key, got, want)
+		}
+	}
+
+	if metadataFields(`not json`) != nil || metadataFields(`["team"]`) != nil {
+		t.Error("metadata that isn't a JSON object should parse as nil")
+	}
+}
+
+func TestBeforeCreatePromotesMetadata(t *testing.T) {
+	usage := TokenUsage{Metadata: `{"team": "ml", "user_id": "u1"}`}
+	usage.BeforeCreate(nil)
+	if usage.Team != "ml" || usage.UserID != "u1" {
+		t.Errorf("token usage promoted team %q, user %q", usage.Team, usage.UserID)
+	}
+
+	// Columns set explicitly win over metadata
+	metrics := GPUMetrics{Team: "research", Metadata: `{"team": "ml", "region": "us-east-1"}`}
+	metrics.BeforeCreate(nil)
+	if metrics.Team != "research" || metrics.Region != "us-east-1" {
+		t.Errorf("GPU metrics promoted team %q, region %q", metrics.Team, metrics.Region)
+	}
+}
@@ -0,0 +1,14 @@
+package models
+
+import "gorm.io/gorm"
+
+// ProtectedResourceIDs returns the resource IDs an organization protects from remediation on a
+// provider type: those protected on that type and those protected on every provider. Manual
+// and automatic remediation both read the protected list through it.
+func ProtectedResourceIDs(db *gorm.DB, orgID, providerType string) ([]string, error) {
+	var ids []string
+	err := db.Model(&ProtectedResource{}).
+		Where("organization_id = ? AND (cloud_provider = '' OR cloud_provider = ?)", orgID, providerType).
+		Pluck("resource_id", &ids).Error
+	return ids, err
+}
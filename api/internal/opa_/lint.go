@@ -0,0 +1,170 @@
+package opa
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/open-policy-agent/opa/ast"
+)
+
+// Lint rule IDs
+const (
+	LintMissingAllow          = "missing-allow"
+	LintMissingDefaultAllow   = "missing-default-allow"
+	LintViolationIgnoresInput = "violation-ignores-input"
+	LintPackageMismatch       = "package-mismatch"
+	LintUndefinedPolicyData   = "undefined-policy-data"
+	LintConfigNotInInput      = "config-not-in-input"
+)
+
+var (
+	policyDataRef   = ast.MustParseRef("data.policy")
+	policyConfigRef = ast.MustParseRef("data.policy.config")
+	inputConfigRef  = ast.MustParseRef("input.config")
+)
+
+// LintOptions describes how the engine will evaluate the Rego being linted
+type LintOptions struct {
+	RegoPackage   string // Package the policy is recorded as declaring; empty skips the check
+	ConfigInInput bool   // Whether the policy's type is evaluated with its config as input.config
+}
+
+// LintWarning is a likely mistake in Rego that compiles
+type LintWarning struct {
+	Rule    string `json:"rule"`
+	Line    int    `json:"line,omitempty"`
+	Message string `json:"message"`
+}
+
+// Lint checks Rego for mistakes the compiler accepts but that make a policy misbehave under
+// this engine. It returns an error only when the Rego doesn't parse. Warnings are ordered by line.
+func Lint(regoCode string, opts LintOptions) ([]LintWarning, error) {
+	module, err := ast.ParseModule("policy.rego", regoCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse rego: %w", err)
+	}
+	if module == nil || module.Package == nil {
+		return nil, fmt.Errorf("rego has no package declaration")
+	}
+
+	warnings := []LintWarning{}
+	warn := func(rule string, loc *ast.Location, format string, args ...interface{}) {
+		warning := LintWarning{Rule: rule, Message: fmt.Sprintf(format, args...)}
+		if loc != nil {
+			warning.Line = loc.Row
+		}
+		warnings = append(warnings, warning)
+	}
+
+	byName := make(map[string][]*ast.Rule)
+	for _, rule := range module.Rules {
+		name := ruleName(rule)
+		byName[name] = append(byName[name], rule)
+	}
+
+	// allow is the rule the engine queries; without a default it is undefined whenever no allow
+	// body matches, which counts as a violation
+	hasDefault := false
+	for _, rule := range byName["allow"] {
+		hasDefault = hasDefault || rule.Default
+	}
+	switch {
+	case len(byName["allow"]) == 0:
+		warn(LintMissingAllow, module.Package.Location,
+			"No allow rule: the engine queries allow, so evaluating this policy fails. Add `default allow = true` and let violation rules deny.")
+	case !hasDefault:
+		warn(LintMissingDefaultAllow, byName["allow"][0].Location,
+			"allow has no default: when no allow body matches, e.g. because an input field is missing, allow is undefined and the input counts as a violation. Add `default allow = true` (or false, if that is intended).")
+	}
+
+	readsInput := rulesReadingInput(module, byName)
+	for _, rule := range byName["violation"] {
+		if !readsInput[rule] {
+			warn(LintViolationIgnoresInput, rule.Location,
+				"This violation rule never reads input, directly or through other rules, so it fires for every input or for none.")
+		}
+	}
+
+	if declared := packagePath(module); opts.RegoPackage != "" && declared != opts.RegoPackage {
+		warn(LintPackageMismatch, module.Package.Location,
+			"The Rego declares package %s but the policy is recorded with package %s. The engine queries the declared package; update the policy so the two agree.",
+			declared, opts.RegoPackage)
+	}
+
+	// The only data document the engine loads is the policy's config, at data.policy.config
+	reported := make(map[string]bool)
+	ast.WalkRefs(module, func(ref ast.Ref) bool {
+		switch {
+		case ref.HasPrefix(policyDataRef) && !ref.HasPrefix(policyConfigRef) && !reported[LintUndefinedPolicyData]:
+			reported[LintUndefinedPolicyData] = true
+			warn(LintUndefinedPolicyData, ref[0].Location,
+				"%s is always undefined: the engine only loads the policy's config, as data.policy.config, so rules using it never match.", ref)
+		case ref.HasPrefix(inputConfigRef) && !opts.ConfigInInput && !reported[LintConfigNotInInput]:
+			reported[LintConfigNotInInput] = true
+			warn(LintConfigNotInInput, ref[0].Location,
+				"%s is undefined for this policy type: only AI and GPU policies and the request check get their config as input.config. Use data.policy.config, which every policy type gets.", ref)
+		}
+		return false
+	})
+
+	sort.SliceStable(warnings, func(i, j int) bool {
+		return warnings[i].Line < warnings[j].Line
+	})
+	return warnings, nil
+}
+
+// ruleName returns the name a rule defines, e.g. allow or violation
+func ruleName(rule *ast.Rule) string {
+	if rule.Head.Name != "" {
+		return rule.Head.Name.String()
+	}
+	if len(rule.Head.Reference) > 0 {
+		return rule.Head.Reference[0].Value.String()
+	}
+	return ""
+}
+
+// rulesReadingInput reports which rules read input, directly or through other rules of the module
+func rulesReadingInput(module *ast.Module, byName map[string][]*ast.Rule) map[*ast.Rule]bool {
+	reads := make(map[*ast.Rule]bool)
+	uses := make(map[*ast.Rule][]string)
+	for _, rule := range module.Rules {
+		ast.WalkRefs(rule, func(ref ast.Ref) bool {
+			if ref.HasPrefix(ast.InputRootRef) {
+				reads[rule] = true
+			}
+			return false
+		})
+		ast.WalkVars(rule.Body, func(v ast.Var) bool {
+			if _, ok := byName[string(v)]; ok {
+				uses[rule] = append(uses[rule], string(v))
+			}
+			return false
+		})
+	}
+
+	// Propagate through rule references until nothing changes
+	for changed := true; changed; {
+		changed = false
+		for _, rule := range module.Rules {
+			if reads[rule] {
+				continue
+			}
+			for _, name := range uses[rule] {
+				for _, used := range byName[name] {
+					if reads[used] {
+						reads[rule] = true
+						changed = true
+					}
+				}
+			}
+		}
+	}
+	return reads
+}
+
+// packagePath returns a module's package without the implicit data root, like ParsePackage
+func packagePath(module *ast.Module) string {
+	return strings.TrimPrefix(module.Package.Path.String(), "data.")
+}
@@ -0,0 +1,104 @@
+package opa
+
+import (
+	"reflect"
+	"testing"
+)
+
+// lintRules returns the rule IDs of the warnings Lint reports, in order
+func lintRules(t *testing.T, regoCode string, opts LintOptions) []string {
+	t.Helper()
+	warnings, err := Lint(regoCode, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := []string{}
+	for _, warning := range warnings {
+		rules = append(rules, warning.Rule)
+	}
+	return rules
+}
+
+func TestLintCleanPolicy(t *testing.T) {
+	if rules := lintRules(t, spendLimitRego, LintOptions{RegoPackage: "finopsbridge.policies"}); len(rules) != 0 {
+		t.Errorf("got %v, want no warnings", rules)
+	}
+}
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name string
+		rego string
+		opts LintOptions
+		want []string
+	}{
+		{"no allow", `package finopsbridge.policies
+
+violation[msg] {
+	input.spend > 100
+	msg := "over"
+}`, LintOptions{}, []string{LintMissingAllow}},
+		{"allow without default", `package finopsbridge.policies
+
+allow {
+	input.spend <= 100
+}`, LintOptions{}, []string{LintMissingDefaultAllow}},
+		{"violation ignoring input", `package finopsbridge.policies
+
+default allow = true
+
+limit := 100
+
+violation[msg] {
+	limit > 50
+	msg := "always"
+}`, LintOptions{}, []string{LintViolationIgnoresInput}},
+		{"package mismatch", spendLimitRego, LintOptions{RegoPackage: "finopsbridge.policies.spend"}, []string{LintPackageMismatch}},
+		{"undefined policy data", `package finopsbridge.policies
+
+default allow = true
+
+violation[msg] {
+	input.spend > data.policy.limit
+	msg := "over"
+}`, LintOptions{}, []string{LintUndefinedPolicyData}},
+		{"config in input", `package finopsbridge.policies
+
+default allow = true
+
+violation[msg] {
+	input.spend > input.config.limit
+	msg := "over"
+}`, LintOptions{}, []string{LintConfigNotInInput}},
+	}
+	for _, tt := range tests {
+		if got := lintRules(t, tt.rego, tt.opts); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLintInputThroughRules(t *testing.T) {
+	// violation reads input through over_limit, and config is in input for this type
+	rego := `package finopsbridge.policies
+
+default allow = true
+
+over_limit {
+	input.spend > input.config.limit
+}
+
+violation[msg] {
+	over_limit
+	msg := "over"
+}`
+	if rules := lintRules(t, rego, LintOptions{ConfigInInput: true}); len(rules) != 0 {
+		t.Errorf("got %v, want no warnings", rules)
+	}
+}
+
+func TestLintRejectsUnparseableRego(t *testing.T) {
+	if _, err := Lint("package finopsbridge.policies\n\nallow {", LintOptions{}); err == nil {
+		t.Error("Rego that doesn't parse should be an error")
+	}
+}
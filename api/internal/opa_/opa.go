@@ -2,19 +2,109 @@ package opa
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/open-policy-agent/opa/ast"
 	"github.com/open-policy-agent/opa/rego"
+	"github.com/open-policy-agent/opa/storage/inmem"
 )
 
+// DefaultPackage is the Rego package used by generated policies
+const DefaultPackage = "finopsbridge.policies"
+
 type Engine struct {
 	dir      string
-	policies map[string]string // policyID -> rego code
-	mu       sync.RWMutex
+	policies atomic.Pointer[map[string]*cachedPolicy] // policyID -> policy; swapped wholesale on reload
+	mu       sync.Mutex                               // serializes writers building a new map
+}
+
+// cachedPolicy is a policy's Rego and config with their content hashes and lazily compiled
+// queries. Entries are immutable once published, so readers never need a lock.
+type cachedPolicy struct {
+	name       string
+	rego       string
+	hash       string
+	config     string // policy config JSON, loaded as data.policy.config
+	configHash string
+
+	once      sync.Once
+	allow     *rego.PreparedEvalQuery
+	violation *rego.PreparedEvalQuery
+	msg       *rego.PreparedEvalQuery
+	err       error
+}
+
+func newCachedPolicy(name, regoCode, configJSON string) *cachedPolicy {
+	return &cachedPolicy{
+		name:       name,
+		rego:       regoCode,
+		hash:       hashRego(regoCode),
+		config:     configJSON,
+		configHash: hashRego(configJSON),
+	}
+}
+
+func hashRego(regoCode string) string {
+	sum := sha256.Sum256([]byte(regoCode))
+	return hex.EncodeToString(sum[:])
+}
+
+// policyData is the data document a policy is evaluated against: its config under
+// data.policy.config. Missing or malformed config is loaded as an empty object, so rules
+// reading it are undefined rather than failing to evaluate.
+func policyData(configJSON string) map[string]interface{} {
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(configJSON), &config); err != nil || config == nil {
+		config = make(map[string]interface{})
+	}
+	return map[string]interface{}{
+		"policy": map[string]interface{}{"config": config},
+	}
+}
+
+// compile prepares the allow/violation/msg queries once per policy version
+func (p *cachedPolicy) compile() error {
+	p.once.Do(func() {
+		ctx := context.Background()
+
+		// Query allow/violation/msg in whatever package the policy declares
+		pkg, err := ParsePackage(p.rego)
+		if err != nil {
+			p.err = err
+			return
+		}
+		base := "data." + pkg
+		store := inmem.NewFromObject(policyData(p.config))
+
+		prepare := func(rule string) (*rego.PreparedEvalQuery, error) {
+			query, err := rego.New(
+				rego.Query(base+"."+rule),
+				rego.Module(p.name+".rego", p.rego),
+				rego.Store(store),
+			).PrepareForEval(ctx)
+			if err != nil {
+				return nil, err
+			}
+			return &query, nil
+		}
+
+		if p.allow, p.err = prepare("allow"); p.err != nil {
+			return
+		}
+		// violation and msg are optional rules
+		p.violation, _ = prepare("violation")
+		p.msg, _ = prepare("msg")
+	})
+	return p.err
 }
 
 func Initialize(policyDir string) (*Engine, error) {
@@ -24,9 +114,10 @@ func Initialize(policyDir string) (*Engine, error) {
 	}
 
 	engine := &Engine{
-		dir:      policyDir,
-		policies: make(map[string]string),
+		dir: policyDir,
 	}
+	empty := make(map[string]*cachedPolicy)
+	engine.policies.Store(&empty)
 
 	// Load existing policies from disk
 	engine.loadPoliciesFromDisk()
@@ -34,6 +125,14 @@ func Initialize(policyDir string) (*Engine, error) {
 	return engine, nil
 }
 
+// snapshot returns the current policy map. It must not be modified.
+func (e *Engine) snapshot() map[string]*cachedPolicy {
+	return *e.policies.Load()
+}
+
+// loadPoliciesFromDisk builds a fresh policy map from the policy directory and swaps it in.
+// Policies whose files were deleted are evicted; unchanged policies keep their compiled queries,
+// and changed ones keep the config they were last evaluated with.
 func (e *Engine) loadPoliciesFromDisk() {
 	files, err := os.ReadDir(e.dir)
 	if err != nil {
@@ -44,6 +143,9 @@ func (e *Engine) loadPoliciesFromDisk() {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
+	current := e.snapshot()
+	next := make(map[string]*cachedPolicy, len(files))
+
 	for _, file := range files {
 		if filepath.Ext(file.Name()) == ".rego" {
 			content, err := os.ReadFile(filepath.Join(e.dir, file.Name()))
@@ -53,9 +155,37 @@ func (e *Engine) loadPoliciesFromDisk() {
 			}
 			// Extract policy ID from filename (remove .rego extension)
 			policyID := file.Name()[:len(file.Name())-5]
-			e.policies[policyID] = string(content)
+
+			regoCode := string(content)
+			existing, ok := current[policyID]
+			if ok && existing.hash == hashRego(regoCode) {
+				next[policyID] = existing
+				continue
+			}
+			configJSON := ""
+			if ok {
+				configJSON = existing.config
+			}
+			next[policyID] = newCachedPolicy(policyID, regoCode, configJSON)
 		}
 	}
+
+	e.policies.Store(&next)
+}
+
+// storePolicy publishes a single policy by copying the current map
+func (e *Engine) storePolicy(policy *cachedPolicy) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	current := e.snapshot()
+	next := make(map[string]*cachedPolicy, len(current)+1)
+	for id, p := range current {
+		next[id] = p
+	}
+	next[policy.name] = policy
+
+	e.policies.Store(&next)
 }
 
 func (e *Engine) ReloadPolicies() error {
@@ -63,10 +193,11 @@ func (e *Engine) ReloadPolicies() error {
 	return nil
 }
 
-func (e *Engine) EvaluatePolicy(policyName string, input map[string]interface{}) (bool, map[string]interface{}, error) {
-	e.mu.RLock()
-	regoCode, exists := e.policies[policyName]
-	e.mu.RUnlock()
+// EvaluatePolicy evaluates a cached policy against input, with the policy's config JSON
+// available to its rules as data.policy.config. A config that differs from the one the policy
+// was compiled with recompiles it.
+func (e *Engine) EvaluatePolicy(policyName string, configJSON string, input map[string]interface{}) (bool, map[string]interface{}, error) {
+	policy, exists := e.snapshot()[policyName]
 
 	if !exists {
 		// Try to load from disk
@@ -75,26 +206,32 @@ func (e *Engine) EvaluatePolicy(policyName string, input map[string]interface{})
 		if err != nil {
 			return true, map[string]interface{}{"allow": true, "msg": "policy not found"}, nil
 		}
-		regoCode = string(content)
-		e.mu.Lock()
-		e.policies[policyName] = regoCode
-		e.mu.Unlock()
+		policy = newCachedPolicy(policyName, string(content), configJSON)
+		e.storePolicy(policy)
+	} else if policy.configHash != hashRego(configJSON) {
+		policy = newCachedPolicy(policyName, policy.rego, configJSON)
+		e.storePolicy(policy)
 	}
 
-	ctx := context.Background()
+	return evaluate(policy, input)
+}
+
+// EvaluateRego evaluates Rego source directly, without consulting the policy cache.
+// Used to run a policy's stored Rego against historical or hypothetical input; configJSON
+// is loaded as data.policy.config, as for EvaluatePolicy.
+func (e *Engine) EvaluateRego(policyName string, regoCode string, configJSON string, input map[string]interface{}) (bool, map[string]interface{}, error) {
+	return evaluate(newCachedPolicy(policyName, regoCode, configJSON), input)
+}
 
-	// Create a new Rego query to evaluate the "allow" rule
-	query, err := rego.New(
-		rego.Query("data.finopsbridge.policies.allow"),
-		rego.Module(policyName+".rego", regoCode),
-	).PrepareForEval(ctx)
+func evaluate(policy *cachedPolicy, input map[string]interface{}) (bool, map[string]interface{}, error) {
+	ctx := context.Background()
 
-	if err != nil {
+	if err := policy.compile(); err != nil {
 		return true, map[string]interface{}{"allow": true, "error": err.Error()}, fmt.Errorf("failed to prepare policy: %w", err)
 	}
 
 	// Evaluate the policy with the input
-	results, err := query.Eval(ctx, rego.EvalInput(input))
+	results, err := policy.allow.Eval(ctx, rego.EvalInput(input))
 	if err != nil {
 		return true, map[string]interface{}{"allow": true, "error": err.Error()}, fmt.Errorf("failed to evaluate policy: %w", err)
 	}
@@ -108,17 +245,26 @@ func (e *Engine) EvaluatePolicy(policyName string, input map[string]interface{})
 	}
 
 	// Also check for violations
-	violationQuery, err := rego.New(
-		rego.Query("data.finopsbridge.policies.violation"),
-		rego.Module(policyName+".rego", regoCode),
-	).PrepareForEval(ctx)
-
-	if err == nil {
-		violationResults, err := violationQuery.Eval(ctx, rego.EvalInput(input))
+	var violationMsgs []string
+	if policy.violation != nil {
+		violationResults, err := policy.violation.Eval(ctx, rego.EvalInput(input))
 		if err == nil && len(violationResults) > 0 && len(violationResults[0].Expressions) > 0 {
-			// If violation is true, set allowed to false
-			if val, ok := violationResults[0].Expressions[0].Value.(bool); ok && val {
-				allowed = false
+			switch val := violationResults[0].Expressions[0].Value.(type) {
+			case bool:
+				// If violation is true, set allowed to false
+				if val {
+					allowed = false
+				}
+			case []interface{}:
+				// Partial set rules (violation[msg] { ... }) yield their messages
+				for _, v := range val {
+					if msg, ok := v.(string); ok {
+						violationMsgs = append(violationMsgs, msg)
+					}
+				}
+				if len(val) > 0 {
+					allowed = false
+				}
 			}
 		}
 	}
@@ -126,20 +272,17 @@ func (e *Engine) EvaluatePolicy(policyName string, input map[string]interface{})
 	result := map[string]interface{}{
 		"allow": allowed,
 	}
+	if len(violationMsgs) > 0 {
+		result["violations"] = violationMsgs
+		result["msg"] = strings.Join(violationMsgs, "; ")
+	}
 
 	// Try to get violation message if not allowed
-	if !allowed {
-		msgQuery, err := rego.New(
-			rego.Query("data.finopsbridge.policies.msg"),
-			rego.Module(policyName+".rego", regoCode),
-		).PrepareForEval(ctx)
-
-		if err == nil {
-			msgResults, err := msgQuery.Eval(ctx, rego.EvalInput(input))
-			if err == nil && len(msgResults) > 0 && len(msgResults[0].Expressions) > 0 {
-				if msg, ok := msgResults[0].Expressions[0].Value.(string); ok {
-					result["msg"] = msg
-				}
+	if !allowed && policy.msg != nil {
+		msgResults, err := policy.msg.Eval(ctx, rego.EvalInput(input))
+		if err == nil && len(msgResults) > 0 && len(msgResults[0].Expressions) > 0 {
+			if msg, ok := msgResults[0].Expressions[0].Value.(string); ok {
+				result["msg"] = msg
 			}
 		}
 	}
@@ -162,35 +305,62 @@ func (e *Engine) Close() error {
 	return nil
 }
 
-// SavePolicy saves a Rego policy to disk and updates the in-memory cache
-func (e *Engine) SavePolicy(name string, regoCode string) error {
+// SavePolicy saves a Rego policy to disk and updates the in-memory cache, along with the
+// config JSON its rules see as data.policy.config
+func (e *Engine) SavePolicy(name string, regoCode string, configJSON string) error {
 	filename := filepath.Join(e.dir, fmt.Sprintf("%s.rego", name))
 	if err := os.WriteFile(filename, []byte(regoCode), 0644); err != nil {
 		return err
 	}
 
 	// Update in-memory cache
-	e.mu.Lock()
-	e.policies[name] = regoCode
-	e.mu.Unlock()
+	e.storePolicy(newCachedPolicy(name, regoCode, configJSON))
 
 	return nil
 }
 
+// CompilePolicy compiles a cached policy's queries, if they aren't already, and returns the
+// error compiling them. Policies that aren't loaded report an error.
+func (e *Engine) CompilePolicy(policyName string) error {
+	policy, exists := e.snapshot()[policyName]
+	if !exists {
+		return fmt.Errorf("policy %s is not loaded", policyName)
+	}
+	return policy.compile()
+}
+
 // LoadPoliciesFromDB loads policies from database and saves them to OPA directory
 func (e *Engine) LoadPoliciesFromDB(policies []PolicyInfo) error {
 	for _, policy := range policies {
-		if err := e.SavePolicy(policy.ID, policy.Rego); err != nil {
+		configJSON, err := json.Marshal(policy.Config)
+		if err != nil {
+			return fmt.Errorf("failed to encode config for policy %s: %w", policy.ID, err)
+		}
+		if err := e.SavePolicy(policy.ID, policy.Rego, string(configJSON)); err != nil {
 			return err
 		}
 	}
 	return e.ReloadPolicies()
 }
 
+// ParsePackage returns the package path declared by Rego source, e.g. "finopsbridge.policies"
+// or "llm_token_budget"
+func ParsePackage(regoCode string) (string, error) {
+	module, err := ast.ParseModule("policy.rego", regoCode)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse rego: %w", err)
+	}
+	if module == nil || module.Package == nil {
+		return "", fmt.Errorf("rego has no package declaration")
+	}
+
+	// Path[0] is the implicit "data" root
+	return strings.TrimPrefix(module.Package.Path.String(), "data."), nil
+}
+
 type PolicyInfo struct {
 	ID    string
 	Rego  string
 	Type  string
 	Config map[string]interface{}
 }
-
@@ -0,0 +1,200 @@
+package opa
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const spendLimitRego = `package finopsbridge.policies
+
+default allow = true
+
+allow = false {
+	input.spend > data.policy.config.limit
+}
+
+violation[msg] {
+	input.spend > data.policy.config.limit
+	msg := sprintf("spend %v is over the limit", [input.spend])
+}
+`
+
+func TestEvaluateRego(t *testing.T) {
+	e := &Engine{}
+
+	allowed, result, err := e.EvaluateRego("spend-limit", spendLimitRego, `{"limit": 100}`, map[string]interface{}{"spend": 50})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !allowed || result["violations"] != nil {
+		t.Errorf("spend under the limit should be allowed, got %v", result)
+	}
+
+	allowed, result, err = e.EvaluateRego("spend-limit", spendLimitRego, `{"limit": 100}`, map[string]interface{}{"spend": 150})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if allowed {
+		t.Error("spend over the limit should be denied")
+	}
+	if want := []string{"spend 150 is over the limit"}; !reflect.DeepEqual(result["violations"], want) {
+		t.Errorf("violations = %v, want %v", result["violations"], want)
+	}
+}
+
+func TestEvaluateRegoMissingConfig(t *testing.T) {
+	// Without config the limit is undefined, so the rules reading it don't fire
+	allowed, _, err := (&Engine{}).EvaluateRego("spend-limit", spendLimitRego, "", map[string]interface{}{"spend": 150})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !allowed {
+		t.Error("a policy without config should allow")
+	}
+}
+
+func TestEvaluateRegoInvalidFailsOpen(t *testing.T) {
+	allowed, result, err := (&Engine{}).EvaluateRego("broken", "package finopsbridge.policies\n\nallow {", "", nil)
+	if err == nil {
+		t.Fatal("invalid Rego should return an error")
+	}
+	if !allowed || result["error"] == nil {
+		t.Errorf("invalid Rego should fail open with the error, got %v, %v", allowed, result)
+	}
+}
+
+func TestParsePackage(t *testing.T) {
+	pkg, err := ParsePackage("package acme.finops.tagging\n\nallow = true\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pkg != "acme.finops.tagging" {
+		t.Errorf("got %q", pkg)
+	}
+
+	if _, err := ParsePackage("allow = true"); err == nil {
+		t.Error("Rego without a package should be rejected")
+	}
+}
+
+func TestEvaluateRegoUsesDeclaredPackage(t *testing.T) {
+	rego := "package acme.custom\n\ndefault allow = false\n\nallow { input.approved }\n"
+	allowed, _, err := (&Engine{}).EvaluateRego("custom", rego, "", map[string]interface{}{"approved": true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !allowed {
+		t.Error("allow should be read from the policy's own package, not " + DefaultPackage)
+	}
+}
+
+func TestReloadKeepsUnchangedPolicies(t *testing.T) {
+	e, err := Initialize(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.SavePolicy("limit", spendLimitRego, `{"limit": 100}`); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.SavePolicy("custom", "package acme.custom\n\nallow = true\n", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.CompilePolicy("limit"); err != nil {
+		t.Fatal(err)
+	}
+	compiled := e.snapshot()["limit"]
+
+	if err := e.ReloadPolicies(); err != nil {
+		t.Fatal(err)
+	}
+	if e.snapshot()["limit"] != compiled {
+		t.Error("reloading unchanged Rego should keep the compiled policy")
+	}
+
+	// Changed Rego is recompiled, keeping the config it was last evaluated with
+	if err := os.WriteFile(filepath.Join(e.dir, "limit.rego"), []byte(spendLimitRego+"\n# changed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(e.dir, "custom.rego")); err != nil {
+		t.Fatal(err)
+	}
+	e.ReloadPolicies()
+
+	reloaded := e.snapshot()
+	if reloaded["limit"] == compiled || reloaded["limit"].config != `{"limit": 100}` {
+		t.Errorf("changed Rego should be reloaded with its config, got %+v", reloaded["limit"])
+	}
+	if _, ok := reloaded["custom"]; ok {
+		t.Error("a deleted policy file should be evicted")
+	}
+}
+
+func TestStorePolicyDoesNotModifyPublishedMap(t *testing.T) {
+	e, err := Initialize(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := e.snapshot()
+	e.storePolicy(newCachedPolicy("limit", spendLimitRego, ""))
+
+	if len(before) != 0 {
+		t.Error("readers holding the old snapshot shouldn't see the new policy")
+	}
+	if err := e.CompilePolicy("limit"); err != nil {
+		t.Errorf("the new snapshot should have the policy: %v", err)
+	}
+}
+
+func TestEvaluatePolicyRecompilesOnConfigChange(t *testing.T) {
+	e, err := Initialize(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.SavePolicy("limit", spendLimitRego, `{"limit": 100}`); err != nil {
+		t.Fatal(err)
+	}
+	input := map[string]interface{}{"spend": 150}
+
+	if allowed, _, err := e.EvaluatePolicy("limit", `{"limit": 100}`, input); err != nil || allowed {
+		t.Errorf("150 over a limit of 100 should be denied, got %v, %v", allowed, err)
+	}
+	compiled := e.snapshot()["limit"]
+
+	if allowed, _, err := e.EvaluatePolicy("limit", `{"limit": 200}`, input); err != nil || !allowed {
+		t.Errorf("150 under the new limit of 200 should be allowed, got %v, %v", allowed, err)
+	}
+	if e.snapshot()["limit"] == compiled {
+		t.Error("a changed config should recompile the policy")
+	}
+	recompiled := e.snapshot()["limit"]
+	e.EvaluatePolicy("limit", `{"limit": 200}`, input)
+	if e.snapshot()["limit"] != recompiled {
+		t.Error("the same config should reuse the compiled policy")
+	}
+}
+
+func TestPolicyData(t *testing.T) {
+	want := map[string]interface{}{"policy": map[string]interface{}{"config": map[string]interface{}{"limit": 100.0}}}
+	if got := policyData(`{"limit": 100}`); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	empty := map[string]interface{}{"policy": map[string]interface{}{"config": map[string]interface{}{}}}
+	for _, configJSON := range []string{"", "null", "not json", "[1]"} {
+		if got := policyData(configJSON); !reflect.DeepEqual(got, empty) {
+			t.Errorf("policyData(%q) = %v, want an empty config", configJSON, got)
+		}
+	}
+}
+
+func TestCompilePolicyNotLoaded(t *testing.T) {
+	e, err := Initialize(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.CompilePolicy("missing"); err == nil {
+		t.Error("compiling a policy that isn't loaded should fail")
+	}
+}
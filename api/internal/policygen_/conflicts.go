@@ -0,0 +1,179 @@
+package policygen
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	models "finopsbridge/api/internal/models_"
+)
+
+// Kinds of PolicyConflict
+const (
+	ConflictKindDuplicate = "duplicate" // Same type, scope and config
+	ConflictKindOverlap   = "overlap"   // Same type and overlapping scope with different settings
+	ConflictKindConflict  = "conflict"  // Remediations that act on the same resources in opposing ways
+)
+
+// PolicyConflict is a potential problem between two enabled policies
+type PolicyConflict struct {
+	Kind        string   `json:"kind"`
+	PolicyIDs   []string `json:"policyIds"`
+	PolicyNames []string `json:"policyNames"`
+	Explanation string   `json:"explanation"`
+}
+
+// conflictingTypes are type pairs whose remediations disagree about the same resources,
+// keyed by the pair in sorted order
+var conflictingTypes = map[[2]string]string{
+	{"auto_stop_idle", "block_instance_type"}: "%s stops idle instances, while %s terminates oversized ones; an idle oversized instance is stopped and then terminated, losing its disks",
+	{"auto_stop_idle", "max_spend"}:           "%s keeps instances running until they are idle, while %s stops every running instance without an Essential tag once spend exceeds the limit",
+}
+
+// DetectConflicts compares every pair of enabled policies and reports duplicates, overlapping
+// policies of the same type, and policy types whose remediations conflict
+func DetectConflicts(policies []models.Policy) []PolicyConflict {
+	var enabled []models.Policy
+	for _, policy := range policies {
+		if policy.Enabled {
+			enabled = append(enabled, policy)
+		}
+	}
+	sort.SliceStable(enabled, func(i, j int) bool {
+		return enabled[i].Name < enabled[j].Name
+	})
+
+	configs := make([]map[string]interface{}, len(enabled))
+	for i, policy := range enabled {
+		if err := json.Unmarshal([]byte(policy.Config), &configs[i]); err != nil || configs[i] == nil {
+			configs[i] = make(map[string]interface{})
+		}
+	}
+
+	conflicts := []PolicyConflict{}
+	for i := 0; i < len(enabled); i++ {
+		for j := i + 1; j < len(enabled); j++ {
+			if conflict, ok := comparePolicies(enabled[i], configs[i], enabled[j], configs[j]); ok {
+				conflicts = append(conflicts, conflict)
+			}
+		}
+	}
+	return conflicts
+}
+
+func comparePolicies(a models.Policy, aConfig map[string]interface{}, b models.Policy, bConfig map[string]interface{}) (PolicyConflict, bool) {
+	conflict := PolicyConflict{
+		PolicyIDs:   []string{a.ID, b.ID},
+		PolicyNames: []string{a.Name, b.Name},
+	}
+
+	if a.Type == b.Type {
+		if !scopesOverlap(a.Type, aConfig, bConfig) {
+			return PolicyConflict{}, false
+		}
+		if reflect.DeepEqual(aConfig, bConfig) {
+			conflict.Kind = ConflictKindDuplicate
+			conflict.Explanation = fmt.Sprintf("%q and %q are both %s policies with the same configuration; one can be removed", a.Name, b.Name, a.Type)
+			return conflict, true
+		}
+		conflict.Kind = ConflictKindOverlap
+		conflict.Explanation = overlapExplanation(a, aConfig, b, bConfig)
+		return conflict, true
+	}
+
+	pair := [2]string{a.Type, b.Type}
+	first, second := a, b
+	if pair[0] > pair[1] {
+		pair = [2]string{b.Type, a.Type}
+		first, second = b, a
+	}
+	if format, ok := conflictingTypes[pair]; ok {
+		conflict.Kind = ConflictKindConflict
+		conflict.Explanation = fmt.Sprintf(format, fmt.Sprintf("%q", first.Name), fmt.Sprintf("%q", second.Name))
+		return conflict, true
+	}
+
+	return PolicyConflict{}, false
+}
+
+// scopesOverlap reports whether two policies of the same type can apply to the same resources.
+// A policy without a scope applies to everything.
+func scopesOverlap(policyType string, aConfig, bConfig map[string]interface{}) bool {
+	switch policyType {
+	case "max_spend":
+		aAccount, _ := aConfig["accountId"].(string)
+		bAccount, _ := bConfig["accountId"].(string)
+		return aAccount == "" || bAccount == "" || aAccount == bAccount
+	case "llm_token_budget":
+		aProviders := configStrings(aConfig["providers"])
+		bProviders := configStrings(bConfig["providers"])
+		if len(aProviders) == 0 || len(bProviders) == 0 {
+			return true
+		}
+		for _, provider := range aProviders {
+			for _, other := range bProviders {
+				if provider == other {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// overlapExplanation describes which of two overlapping policies of the same type takes effect
+func overlapExplanation(a models.Policy, aConfig map[string]interface{}, b models.Policy, bConfig map[string]interface{}) string {
+	var field string
+	switch a.Type {
+	case "max_spend":
+		field = "maxAmount"
+	case "auto_stop_idle":
+		field = "idleHours"
+	case "block_instance_type":
+		if aSize, bSize := sizeRank(aConfig["maxSize"]), sizeRank(bConfig["maxSize"]); aSize > 0 && bSize > 0 && aSize != bSize {
+			stricter, other := a, b
+			if bSize < aSize {
+				stricter, other = b, a
+			}
+			return fmt.Sprintf("%q allows smaller instances than %q, so %q never triggers on its own", stricter.Name, other.Name, other.Name)
+		}
+	case "require_tags":
+		return fmt.Sprintf("%q and %q both require tags (%s and %s); a resource missing tags from both is reported twice",
+			a.Name, b.Name, strings.Join(configStrings(aConfig["requiredTags"]), ", "), strings.Join(configStrings(bConfig["requiredTags"]), ", "))
+	}
+
+	if field != "" {
+		aValue, aOK := aConfig[field].(float64)
+		bValue, bOK := bConfig[field].(float64)
+		if aOK && bOK && aValue != bValue {
+			stricter, other := a, b
+			if bValue < aValue {
+				stricter, other = b, a
+			}
+			return fmt.Sprintf("%q has a lower %s than %q, so %q never triggers on its own", stricter.Name, field, other.Name, other.Name)
+		}
+	}
+
+	return fmt.Sprintf("%q and %q are both %s policies that apply to the same resources with different settings", a.Name, b.Name, a.Type)
+}
+
+func sizeRank(value interface{}) int {
+	size, _ := value.(string)
+	return map[string]int{"small": 1, "medium": 2, "large": 3, "xlarge": 4}[size]
+}
+
+// configStrings converts a JSON array from policy config into sorted strings
+func configStrings(value interface{}) []string {
+	items, _ := value.([]interface{})
+	var result []string
+	for _, item := range items {
+		if s, ok := item.(string); ok && s != "" {
+			result = append(result, s)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,74 @@
+package policygen
+
+import (
+	"strings"
+	"testing"
+
+	models "finopsbridge/api/internal/models_"
+)
+
+func policy(id, name, policyType, config string) models.Policy {
+	return models.Policy{ID: id, Name: name, Type: policyType, Config: config, Enabled: true}
+}
+
+func TestDetectConflicts(t *testing.T) {
+	disabled := policy("p5", "Old cap", "max_spend", `{"maxAmount": 1000}`)
+	disabled.Enabled = false
+
+	conflicts := DetectConflicts([]models.Policy{
+		policy("p1", "Cap A", "max_spend", `{"maxAmount": 1000}`),
+		policy("p2", "Cap B", "max_spend", `{"maxAmount": 1000}`),
+		policy("p3", "Cap C", "max_spend", `{"maxAmount": 5000}`),
+		policy("p4", "Idle", "auto_stop_idle", `{"idleHours": 24}`),
+		disabled,
+	})
+
+	kinds := make(map[string]int)
+	for _, conflict := range conflicts {
+		kinds[conflict.Kind]++
+	}
+	// A and B are duplicates; C overlaps both; Idle conflicts with each enabled cap
+	if kinds[ConflictKindDuplicate] != 1 || kinds[ConflictKindOverlap] != 2 || kinds[ConflictKindConflict] != 3 {
+		t.Errorf("got %v", kinds)
+	}
+	for _, conflict := range conflicts {
+		if strings.Contains(strings.Join(conflict.PolicyIDs, ","), "p5") {
+			t.Errorf("disabled policies shouldn't be compared: %+v", conflict)
+		}
+	}
+}
+
+func TestScopesOverlap(t *testing.T) {
+	tests := []struct {
+		policyType   string
+		aConfig      map[string]interface{}
+		bConfig      map[string]interface{}
+		wantOverlaps bool
+	}{
+		{"max_spend", map[string]interface{}{"accountId": "1"}, map[string]interface{}{"accountId": "2"}, false},
+		{"max_spend", map[string]interface{}{"accountId": "1"}, map[string]interface{}{}, true},
+		{"llm_token_budget", map[string]interface{}{"providers": []interface{}{"openai"}}, map[string]interface{}{"providers": []interface{}{"anthropic"}}, false},
+		{"llm_token_budget", map[string]interface{}{"providers": []interface{}{"openai", "anthropic"}}, map[string]interface{}{"providers": []interface{}{"anthropic"}}, true},
+		{"require_tags", map[string]interface{}{}, map[string]interface{}{}, true},
+	}
+	for _, tt := range tests {
+		if got := scopesOverlap(tt.policyType, tt.aConfig, tt.bConfig); got != tt.wantOverlaps {
+			t.Errorf("scopesOverlap(%s, %v, %v) = %v", tt.policyType, tt.aConfig, tt.bConfig, got)
+		}
+	}
+}
+
+func TestOverlapExplanationNamesStricterPolicy(t *testing.T) {
+	a := policy("p1", "Small only", "block_instance_type", "")
+	b := policy("p2", "No xlarge", "block_instance_type", "")
+	got := overlapExplanation(a, map[string]interface{}{"maxSize": "small"}, b, map[string]interface{}{"maxSize": "large"})
+	if want := `"Small only" allows smaller instances than "No xlarge", so "No xlarge" never triggers on its own`; got != want {
+		t.Errorf("got %s", got)
+	}
+
+	a, b = policy("p1", "Idle 48h", "auto_stop_idle", ""), policy("p2", "Idle 8h", "auto_stop_idle", "")
+	got = overlapExplanation(a, map[string]interface{}{"idleHours": 48.0}, b, map[string]interface{}{"idleHours": 8.0})
+	if !strings.HasPrefix(got, `"Idle 8h" has a lower idleHours`) {
+		t.Errorf("got %s", got)
+	}
+}
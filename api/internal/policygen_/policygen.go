@@ -4,19 +4,32 @@ import (
 	"fmt"
 )
 
+// GeneratedPackage is the Rego package of every policy GenerateRego writes. Template Rego
+// declares its own package.
+const GeneratedPackage = "finopsbridge.policies"
+
+// generators write the Rego of the built-in policy types, with their config baked in
+var generators = map[string]func(map[string]interface{}) string{
+	"max_spend":               generateMaxSpendPolicy,
+	"block_instance_type":     generateBlockInstanceTypePolicy,
+	"auto_stop_idle":          generateAutoStopIdlePolicy,
+	"require_tags":            generateRequireTagsPolicy,
+	"budget_hierarchy":        generateBudgetHierarchyPolicy,
+	"month_over_month_growth": generateMonthOverMonthGrowthPolicy,
+}
+
+// Generates reports whether GenerateRego writes Rego for a policy type
+func Generates(policyType string) bool {
+	_, ok := generators[policyType]
+	return ok
+}
+
 func GenerateRego(policyType string, config map[string]interface{}) (string, error) {
-	switch policyType {
-	case "max_spend":
-		return generateMaxSpendPolicy(config), nil
-	case "block_instance_type":
-		return generateBlockInstanceTypePolicy(config), nil
-	case "auto_stop_idle":
-		return generateAutoStopIdlePolicy(config), nil
-	case "require_tags":
-		return generateRequireTagsPolicy(config), nil
-	default:
+	generate, ok := generators[policyType]
+	if !ok {
 		return "", fmt.Errorf("unknown policy type: %s", policyType)
 	}
+	return generate(config), nil
 }
 
 func generateMaxSpendPolicy(config map[string]interface{}) string {
@@ -28,6 +41,10 @@ func generateMaxSpendPolicy(config map[string]interface{}) string {
 		accountFilter = fmt.Sprintf(`input.account_id == "%s" &&`, accountId)
 	}
 
+	if soft, hard, ok := SpendThresholds(config); ok {
+		return generateTieredMaxSpendPolicy(accountFilter, soft, hard)
+	}
+
 	return fmt.Sprintf(`package finopsbridge.policies
 
 default allow = false
@@ -49,6 +66,68 @@ msg = m {
 }`, accountFilter, maxAmount, accountFilter, maxAmount, accountFilter, maxAmount, maxAmount)
 }
 
+// SpendThresholds reads a max_spend config's softThreshold and hardThreshold, the hard limit
+// falling back to maxAmount. It reports false when neither threshold is set; a missing limit
+// is returned as 0. The worker reads the same limits to decide whether to remediate.
+func SpendThresholds(config map[string]interface{}) (float64, float64, bool) {
+	soft, _ := config["softThreshold"].(float64)
+	hard, _ := config["hardThreshold"].(float64)
+	if soft <= 0 && hard <= 0 {
+		return 0, 0, false
+	}
+	if hard <= 0 {
+		hard, _ = config["maxAmount"].(float64)
+	}
+	return soft, hard, true
+}
+
+// generateTieredMaxSpendPolicy fires from the lower of the soft and hard limits, with a
+// message naming the limit crossed. The worker decides from the same limits whether to
+// remediate.
+func generateTieredMaxSpendPolicy(accountFilter string, soft, hard float64) string {
+	lowest := soft
+	if lowest <= 0 || (hard > 0 && hard < lowest) {
+		lowest = hard
+	}
+
+	msg := ""
+	if hard > 0 {
+		msg = fmt.Sprintf(`msg = m {
+	%s
+	input.monthly_spend > %v
+	m := sprintf("Monthly spend $%%v exceeds hard limit of $%%v", [input.monthly_spend, %v])
+}`, accountFilter, hard, hard)
+	}
+	if soft > 0 && (hard <= 0 || soft < hard) {
+		if msg != "" {
+			msg += " else = m {"
+		} else {
+			msg = "msg = m {"
+		}
+		msg += fmt.Sprintf(`
+	%s
+	input.monthly_spend > %v
+	m := sprintf("Monthly spend $%%v exceeds soft limit of $%%v", [input.monthly_spend, %v])
+}`, accountFilter, soft, soft)
+	}
+
+	return fmt.Sprintf(`package finopsbridge.policies
+
+default allow = false
+
+allow {
+	%s
+	input.monthly_spend <= %v
+}
+
+violation {
+	%s
+	input.monthly_spend > %v
+}
+
+%s`, accountFilter, lowest, accountFilter, lowest, msg)
+}
+
 func generateBlockInstanceTypePolicy(config map[string]interface{}) string {
 	maxSize := config["maxSize"]
 	
@@ -135,3 +214,56 @@ msg = m {
 }`, tagsList)
 }
 
+// generateBudgetHierarchyPolicy checks one budget of the organization's hierarchy at a time.
+// thresholdPercent (default 100) flags budgets before they are fully spent.
+func generateBudgetHierarchyPolicy(config map[string]interface{}) string {
+	threshold := 100.0
+	if value, ok := config["thresholdPercent"].(float64); ok && value > 0 {
+		threshold = value
+	}
+
+	return fmt.Sprintf(`package finopsbridge.policies
+
+default allow = true
+
+limit := input.budget.amount * %v / 100
+
+allow {
+	input.budget.spend <= limit
+}
+
+violation {
+	input.budget.spend > limit
+}
+
+msg = m {
+	input.budget.spend > limit
+	m := sprintf("%%s budget '%%s' spend $%%v exceeds %%v%%%% of its $%%v limit", [input.budget.level, input.budget.path, input.budget.spend, %v, input.budget.amount])
+}`, threshold, threshold)
+}
+
+// generateMonthOverMonthGrowthPolicy flags a provider whose month-to-date spend, prorated to a
+// full month, is more than thresholdPercent (default 20) above the previous month's
+func generateMonthOverMonthGrowthPolicy(config map[string]interface{}) string {
+	threshold := 20.0
+	if value, ok := config["thresholdPercent"].(float64); ok && value > 0 {
+		threshold = value
+	}
+
+	return fmt.Sprintf(`package finopsbridge.policies
+
+default allow = true
+
+allow {
+	input.month_over_month_percent <= %v
+}
+
+violation {
+	input.month_over_month_percent > %v
+}
+
+msg = m {
+	input.month_over_month_percent > %v
+	m := sprintf("Spend is tracking $%%v this month, %%v%%%% above last month's $%%v", [round(input.projected_monthly_spend * 100) / 100, round(input.month_over_month_percent), round(input.previous_month_spend * 100) / 100])
+}`, threshold, threshold, threshold)
+}
@@ -0,0 +1,99 @@
+package policygen
+
+import (
+	"testing"
+
+	opa "finopsbridge/api/internal/opa_"
+)
+
+func TestMonthOverMonthGrowthPolicy(t *testing.T) {
+	rego, err := GenerateRego("month_over_month_growth", map[string]interface{}{"thresholdPercent": 50.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine := &opa.Engine{}
+
+	input := map[string]interface{}{"projected_monthly_spend": 140.0, "previous_month_spend": 100.0, "month_over_month_percent": 40.0}
+	if allowed, _, err := engine.EvaluateRego("growth", rego, "", input); err != nil || !allowed {
+		t.Errorf("40%% growth under a 50%% threshold should be allowed, got %v, %v", allowed, err)
+	}
+
+	// Whole amounts reach OPA as integers, which must format as well as fractional ones
+	input = map[string]interface{}{"projected_monthly_spend": 180.0, "previous_month_spend": 100.0, "month_over_month_percent": 80.0}
+	allowed, result, err := engine.EvaluateRego("growth", rego, "", input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if allowed {
+		t.Error("80% growth over a 50% threshold should be denied")
+	}
+	if want := "Spend is tracking $180 this month, 80% above last month's $100"; result["msg"] != want {
+		t.Errorf("msg = %v, want %q", result["msg"], want)
+	}
+
+	input = map[string]interface{}{"projected_monthly_spend": 1234.567, "previous_month_spend": 800.251, "month_over_month_percent": 54.27}
+	if _, result, err = engine.EvaluateRego("growth", rego, "", input); err != nil {
+		t.Fatal(err)
+	}
+	if want := "Spend is tracking $1234.57 this month, 54% above last month's $800.25"; result["msg"] != want {
+		t.Errorf("msg = %v, want %q", result["msg"], want)
+	}
+}
+
+func TestMonthOverMonthGrowthPolicyDefaultThreshold(t *testing.T) {
+	rego, err := GenerateRego("month_over_month_growth", map[string]interface{}{"thresholdPercent": -10.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := map[string]interface{}{"projected_monthly_spend": 115.0, "previous_month_spend": 100.0, "month_over_month_percent": 15.0}
+	if allowed, _, err := (&opa.Engine{}).EvaluateRego("growth", rego, "", input); err != nil || !allowed {
+		t.Errorf("15%% growth should be under the default 20%% threshold, got %v, %v", allowed, err)
+	}
+}
+
+func TestSpendThresholds(t *testing.T) {
+	tests := []struct {
+		config     map[string]interface{}
+		soft, hard float64
+		ok         bool
+	}{
+		{map[string]interface{}{"maxAmount": 1000.0}, 0, 0, false},
+		{map[string]interface{}{"softThreshold": 800.0, "hardThreshold": 1200.0, "maxAmount": 1000.0}, 800, 1200, true},
+		{map[string]interface{}{"softThreshold": 800.0, "maxAmount": 1000.0}, 800, 1000, true},
+		{map[string]interface{}{"softThreshold": 800.0}, 800, 0, true},
+		{map[string]interface{}{"hardThreshold": 1200.0}, 0, 1200, true},
+	}
+	for _, tt := range tests {
+		soft, hard, ok := SpendThresholds(tt.config)
+		if soft != tt.soft || hard != tt.hard || ok != tt.ok {
+			t.Errorf("SpendThresholds(%v) = %v, %v, %v; want %v, %v, %v", tt.config, soft, hard, ok, tt.soft, tt.hard, tt.ok)
+		}
+	}
+}
+
+func TestTieredMaxSpendPolicy(t *testing.T) {
+	rego, err := GenerateRego("max_spend", map[string]interface{}{"softThreshold": 800.0, "hardThreshold": 1000.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine := &opa.Engine{}
+
+	tests := []struct {
+		spend   float64
+		allowed bool
+		msg     interface{}
+	}{
+		{500, true, nil},
+		{900, false, "Monthly spend $900 exceeds soft limit of $800"},
+		{1200.5, false, "Monthly spend $1200.5 exceeds hard limit of $1000"},
+	}
+	for _, tt := range tests {
+		allowed, result, err := engine.EvaluateRego("spend", rego, "", map[string]interface{}{"monthly_spend": tt.spend})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if allowed != tt.allowed || result["msg"] != tt.msg {
+			t.Errorf("spend %v: allowed %v, msg %v; want %v, %v", tt.spend, allowed, result["msg"], tt.allowed, tt.msg)
+		}
+	}
+}
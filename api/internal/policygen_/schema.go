@@ -0,0 +1,108 @@
+package policygen
+
+// InputField describes one field of the OPA input document a policy's Rego reads
+type InputField struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+}
+
+// CommonInputFields are assembled by the enforcement worker for every policy evaluation.
+// Keep in sync with the worker's buildPolicyInput.
+var CommonInputFields = []InputField{
+	{Name: "account_id", Type: "string", Description: "AWS account ID of the cloud provider"},
+	{Name: "subscription_id", Type: "string", Description: "Azure subscription ID of the cloud provider"},
+	{Name: "project_id", Type: "string", Description: "GCP project ID of the cloud provider"},
+	{Name: "monthly_spend", Type: "number", Description: "Month-to-date spend of the cloud provider"},
+	{Name: "provider_type", Type: "string", Description: "Cloud provider type (aws, azure, gcp, oci, ibm)"},
+	{Name: "monthlySpend", Type: "number", Description: "Month-to-date spend as reported by the billing fetch"},
+	{Name: "currency", Type: "string", Description: "Currency of the billing data"},
+	{Name: "hasData", Type: "boolean", Description: "Whether the billing fetch found any cost records; when false, monthlySpend is the last known spend"},
+	{Name: "month_elapsed_fraction", Type: "number", Description: "Fraction of the current month elapsed (0-1, at least one day)"},
+	{Name: "projected_monthly_spend", Type: "number", Description: "Month-to-date spend prorated to a full month, comparable with prior months"},
+}
+
+// ResourceInputFields are added to the common fields when a resource-scoped policy is evaluated
+// for one compute instance. Keep in sync with the worker's instanceInput.
+var ResourceInputFields = []InputField{
+	{Name: "resource_id", Type: "string", Description: "Provider ID of the instance"},
+	{Name: "resource_name", Type: "string", Description: "Name of the instance"},
+	{Name: "resource_type", Type: "string", Description: "Always \"instance\""},
+	{Name: "instance_type", Type: "string", Description: "EC2 instance type, Azure VM size or GCP machine type; also given as instanceType"},
+	{Name: "instance_size", Type: "number", Description: "Size level from the instance type (1=small, 2=medium, 3=large, 4=xlarge); absent when the type's name doesn't say"},
+	{Name: "region", Type: "string", Description: "Region the instance runs in"},
+	{Name: "status", Type: "string", Description: "Instance state as reported by the provider"},
+	{Name: "tags", Type: "object", Description: "Instance tags keyed by name (labels for GCP)"},
+	{Name: "launched_at", Type: "string", Description: "RFC 3339 launch time, when the provider reports it"},
+}
+
+// inputSchemas lists the input fields each built-in policy type's generated Rego reads
+var inputSchemas = map[string][]InputField{
+	"max_spend": {
+		{Name: "monthly_spend", Type: "number", Description: "Compared against config.maxAmount, or config.softThreshold and config.hardThreshold"},
+		{Name: "account_id", Type: "string", Description: "Only evaluated for config.accountId when one is set"},
+	},
+	"block_instance_type": {
+		{Name: "instance_size", Type: "number", Description: "Size level of the instance (1=small, 2=medium, 3=large, 4=xlarge), compared against config.maxSize"},
+	},
+	"auto_stop_idle": {
+		{Name: "idle_hours", Type: "number", Description: "Hours the resource has been idle, compared against config.idleHours"},
+	},
+	"require_tags": {
+		{Name: "tags", Type: "object", Description: "Resource tags keyed by name; every tag in config.requiredTags must be present"},
+	},
+	"budget_hierarchy": {
+		{Name: "budget.name", Type: "string", Description: "Name of the budget being checked"},
+		{Name: "budget.level", Type: "string", Description: "Level of the budget (organization, team, project)"},
+		{Name: "budget.path", Type: "string", Description: "Budget names from the root down, joined with \" / \""},
+		{Name: "budget.amount", Type: "number", Description: "Monthly limit of the budget in the reporting currency"},
+		{Name: "budget.spend", Type: "number", Description: "Month-to-date spend attributed to the budget, compared against config.thresholdPercent of amount"},
+	},
+	"month_over_month_growth": {
+		{Name: "projected_monthly_spend", Type: "number", Description: "Month-to-date spend prorated to a full month"},
+		{Name: "previous_month_spend", Type: "number", Description: "The previous month's spend from its last daily snapshot, prorated if that snapshot is before the month's end"},
+		{Name: "month_over_month_percent", Type: "number", Description: "How far projected_monthly_spend is above previous_month_spend, compared against config.thresholdPercent"},
+	},
+	"token_cost_anomaly": {
+		{Name: "model.name", Type: "string", Description: "Model the token cost is for; each model is evaluated separately"},
+		{Name: "tokenCost.daily", Type: "number", Description: "The model's token cost so far today (UTC)"},
+		{Name: "tokenCost.average", Type: "number", Description: "Average daily token cost over up to config.weeklyBaseline days before today, from the model's first day of usage in that window"},
+		{Name: "tokenCost.baselineDays", Type: "number", Description: "Number of days averaged into tokenCost.average"},
+		{Name: "config", Type: "object", Description: "The policy's config"},
+	},
+}
+
+// aiPolicyTypes are evaluated against an organization's token usage or GPU metrics rather than
+// cloud billing
+var aiPolicyTypes = map[string]bool{
+	"llm_token_budget":    true,
+	"token_length_limits": true,
+	"token_cost_anomaly":  true,
+	"gpu_idle_detection":  true,
+	"gpu_time_slicing":    true,
+}
+
+// resourcePolicyTypes are evaluated once per compute instance rather than once per provider
+var resourcePolicyTypes = map[string]bool{
+	"block_instance_type": true,
+	"require_tags":        true,
+	"rightsizing":         true,
+}
+
+// IsResourcePolicyType reports whether policies of a type are evaluated against each of a
+// provider's instances rather than against the provider's billing
+func IsResourcePolicyType(policyType string) bool {
+	return resourcePolicyTypes[policyType]
+}
+
+// IsAIPolicyType reports whether policies of a type apply to AI token usage or GPU metrics
+// rather than to cloud providers
+func IsAIPolicyType(policyType string) bool {
+	return aiPolicyTypes[policyType]
+}
+
+// InputSchema returns the input fields a built-in policy type reads, and false for unknown types
+func InputSchema(policyType string) ([]InputField, bool) {
+	fields, ok := inputSchemas[policyType]
+	return fields, ok
+}
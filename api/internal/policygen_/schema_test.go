@@ -0,0 +1,37 @@
+package policygen
+
+import "testing"
+
+func TestEveryGeneratedTypeHasInputSchema(t *testing.T) {
+	for policyType := range generators {
+		fields, ok := InputSchema(policyType)
+		if !ok || len(fields) == 0 {
+			t.Errorf("%s is generated but has no input schema", policyType)
+		}
+	}
+}
+
+func TestInputSchemaFields(t *testing.T) {
+	types := map[string]bool{"string": true, "number": true, "boolean": true, "object": true, "array": true}
+	schemas := map[string][]InputField{"common": CommonInputFields, "resource": ResourceInputFields}
+	for policyType, fields := range inputSchemas {
+		schemas[policyType] = fields
+	}
+
+	for name, fields := range schemas {
+		seen := make(map[string]bool)
+		for _, field := range fields {
+			if field.Name == "" || field.Description == "" || !types[field.Type] {
+				t.Errorf("%s: incomplete field %+v", name, field)
+			}
+			if seen[field.Name] {
+				t.Errorf("%s: %s is listed twice", name, field.Name)
+			}
+			seen[field.Name] = true
+		}
+	}
+
+	if _, ok := InputSchema("custom"); ok {
+		t.Error("unknown policy types shouldn't have a schema")
+	}
+}
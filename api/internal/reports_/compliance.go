@@ -0,0 +1,231 @@
+package reports
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+
+	"gorm.io/gorm"
+)
+
+// maxReportedViolations bounds how many open violations a report lists individually; the
+// severity counts always cover all of them
+const maxReportedViolations = 200
+
+// severityOrder lists violation severities from most to least severe
+var severityOrder = []string{"critical", "high", "medium", "low"}
+
+// ComplianceReport is an organization's policies and violation state at one point in time
+type ComplianceReport struct {
+	OrganizationID string    `json:"organizationId"`
+	GeneratedAt    time.Time `json:"generatedAt"`
+
+	// ComplianceScore is the percentage of enabled policies without an open violation, or 100
+	// when no policy is enabled
+	ComplianceScore    float64 `json:"complianceScore"`
+	EnabledPolicies    int     `json:"enabledPolicies"`
+	CompliantPolicies  int     `json:"compliantPolicies"`
+	OpenViolationCount int     `json:"openViolationCount"`
+
+	Policies       []PolicySummary    `json:"policies"`
+	OpenBySeverity []SeverityCount    `json:"openBySeverity"`
+	OpenViolations []ViolationSummary `json:"openViolations"` // Most severe first, up to maxReportedViolations
+	// OpenViolationsTruncated is set when OpenViolations lists only some of the open violations
+	OpenViolationsTruncated bool             `json:"openViolationsTruncated"`
+	Remediation             RemediationStats `json:"remediation"`
+}
+
+// PolicySummary is one policy's state in a compliance report
+type PolicySummary struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Type           string `json:"type"`
+	Mode           string `json:"mode"`
+	Enabled        bool   `json:"enabled"`
+	OpenViolations int    `json:"openViolations"`
+	FireCount      int    `json:"fireCount"`
+	Remediations   int    `json:"remediations"`
+}
+
+// Compliant reports whether an enabled policy has no open violations
+func (p PolicySummary) Compliant() bool {
+	return p.Enabled && p.OpenViolations == 0
+}
+
+// SeverityCount is how many open violations have one severity
+type SeverityCount struct {
+	Severity string `json:"severity"`
+	Count    int    `json:"count"`
+}
+
+// ViolationSummary is one open violation in a compliance report
+type ViolationSummary struct {
+	ID         string    `json:"id"`
+	PolicyName string    `json:"policyName"`
+	ResourceID string    `json:"resourceId"`
+	Severity   string    `json:"severity"`
+	Message    string    `json:"message"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// RemediationStats summarizes how the organization's violations were resolved
+type RemediationStats struct {
+	Remediated              int     `json:"remediated"`
+	Ignored                 int     `json:"ignored"`
+	Pending                 int     `json:"pending"`
+	AverageRemediationHours float64 `json:"averageRemediationHours"` // Automatic remediations only; 0 when there were none
+}
+
+// StatusCount is how many of an organization's violations have one status
+type StatusCount struct {
+	Status string
+	Count  int
+}
+
+// BuildComplianceReport assembles a report from an organization's policies, its open
+// violations and the count of its violations by status
+func BuildComplianceReport(orgID string, policies []models.Policy, open []models.PolicyViolation, statuses []StatusCount, now time.Time) ComplianceReport {
+	report := ComplianceReport{
+		OrganizationID:     orgID,
+		GeneratedAt:        now,
+		OpenViolationCount: len(open),
+		Policies:           make([]PolicySummary, 0, len(policies)),
+		OpenViolations:     []ViolationSummary{},
+	}
+
+	openByPolicy := make(map[string]int)
+	bySeverity := make(map[string]int)
+	for _, violation := range open {
+		openByPolicy[violation.PolicyID]++
+		bySeverity[violation.Severity]++
+	}
+
+	names := make(map[string]string, len(policies))
+	remediations := 0
+	remediationSeconds := 0.0
+	for _, policy := range policies {
+		names[policy.ID] = policy.Name
+		summary := PolicySummary{
+			ID:             policy.ID,
+			Name:           policy.Name,
+			Type:           policy.Type,
+			Mode:           policy.Mode,
+			Enabled:        policy.Enabled,
+			OpenViolations: openByPolicy[policy.ID],
+			FireCount:      policy.FireCount,
+			Remediations:   policy.RemediationCount,
+		}
+		report.Policies = append(report.Policies, summary)
+		if policy.Enabled {
+			report.EnabledPolicies++
+			if summary.Compliant() {
+				report.CompliantPolicies++
+			}
+		}
+		remediations += policy.RemediationCount
+		remediationSeconds += policy.RemediationSecondsTotal
+	}
+	sort.SliceStable(report.Policies, func(i, j int) bool {
+		if report.Policies[i].OpenViolations != report.Policies[j].OpenViolations {
+			return report.Policies[i].OpenViolations > report.Policies[j].OpenViolations
+		}
+		return report.Policies[i].Name < report.Policies[j].Name
+	})
+
+	report.ComplianceScore = 100
+	if report.EnabledPolicies > 0 {
+		report.ComplianceScore = math.Round(1000*float64(report.CompliantPolicies)/float64(report.EnabledPolicies)) / 10
+	}
+
+	for _, severity := range severityOrder {
+		report.OpenBySeverity = append(report.OpenBySeverity, SeverityCount{Severity: severity, Count: bySeverity[severity]})
+		delete(bySeverity, severity)
+	}
+	// Severities outside the usual four, e.g. from custom Rego, are listed after them
+	var others []string
+	for severity := range bySeverity {
+		others = append(others, severity)
+	}
+	sort.Strings(others)
+	for _, severity := range others {
+		report.OpenBySeverity = append(report.OpenBySeverity, SeverityCount{Severity: severity, Count: bySeverity[severity]})
+	}
+
+	sorted := append([]models.PolicyViolation(nil), open...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ri, rj := severityRank(sorted[i].Severity), severityRank(sorted[j].Severity)
+		if ri != rj {
+			return ri < rj
+		}
+		return sorted[i].CreatedAt.After(sorted[j].CreatedAt)
+	})
+	if len(sorted) > maxReportedViolations {
+		sorted = sorted[:maxReportedViolations]
+		report.OpenViolationsTruncated = true
+	}
+	for _, violation := range sorted {
+		report.OpenViolations = append(report.OpenViolations, ViolationSummary{
+			ID:         violation.ID,
+			PolicyName: names[violation.PolicyID],
+			ResourceID: violation.ResourceID,
+			Severity:   violation.Severity,
+			Message:    violation.Message,
+			CreatedAt:  violation.CreatedAt,
+		})
+	}
+
+	for _, status := range statuses {
+		switch status.Status {
+		case "remediated":
+			report.Remediation.Remediated = status.Count
+		case "ignored":
+			report.Remediation.Ignored = status.Count
+		case "pending":
+			report.Remediation.Pending = status.Count
+		}
+	}
+	if remediations > 0 {
+		report.Remediation.AverageRemediationHours = math.Round(remediationSeconds/float64(remediations)/360) / 10
+	}
+
+	return report
+}
+
+// LoadComplianceReport queries an organization's policies and violations and builds its report
+func LoadComplianceReport(db *gorm.DB, orgID string, now time.Time) (ComplianceReport, error) {
+	var policies []models.Policy
+	if err := db.Where("organization_id = ?", orgID).Order("name").Find(&policies).Error; err != nil {
+		return ComplianceReport{}, err
+	}
+
+	var open []models.PolicyViolation
+	if err := db.Joins("JOIN policies ON policies.id = policy_violations.policy_id").
+		Where("policies.organization_id = ? AND policy_violations.status = ?", orgID, "pending").
+		Find(&open).Error; err != nil {
+		return ComplianceReport{}, err
+	}
+
+	var statuses []StatusCount
+	if err := db.Model(&models.PolicyViolation{}).
+		Joins("JOIN policies ON policies.id = policy_violations.policy_id").
+		Where("policies.organization_id = ?", orgID).
+		Select("policy_violations.status AS status, COUNT(*) AS count").
+		Group("policy_violations.status").
+		Scan(&statuses).Error; err != nil {
+		return ComplianceReport{}, err
+	}
+
+	return BuildComplianceReport(orgID, policies, open, statuses, now), nil
+}
+
+// severityRank orders severities for sorting, most severe first and unknown ones last
+func severityRank(severity string) int {
+	for i, s := range severityOrder {
+		if s == severity {
+			return i
+		}
+	}
+	return len(severityOrder)
+}
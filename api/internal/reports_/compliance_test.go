@@ -0,0 +1,96 @@
+package reports
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+)
+
+func TestBuildComplianceReportScore(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	policies := []models.Policy{
+		{ID: "p1", Name: "alpha", Enabled: true, RemediationCount: 2, RemediationSecondsTotal: 7200},
+		{ID: "p2", Name: "beta", Enabled: true, RemediationCount: 1, RemediationSecondsTotal: 5400},
+		{ID: "p3", Name: "gamma", Enabled: true},
+		{ID: "p4", Name: "delta", Enabled: false},
+	}
+	open := []models.PolicyViolation{
+		{ID: "v1", PolicyID: "p1", Severity: "low", CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: "v2", PolicyID: "p1", Severity: "critical", CreatedAt: now.Add(-3 * time.Hour)},
+		{ID: "v3", PolicyID: "p4", Severity: "custom", CreatedAt: now.Add(-time.Hour)},
+		{ID: "v4", PolicyID: "p1", Severity: "low", CreatedAt: now.Add(-time.Minute)},
+	}
+	statuses := []StatusCount{{"remediated", 5}, {"ignored", 2}, {"pending", 4}}
+
+	report := BuildComplianceReport("org", policies, open, statuses, now)
+
+	// p2 and p3 are compliant; the disabled p4 doesn't count
+	if report.EnabledPolicies != 3 || report.CompliantPolicies != 2 || report.ComplianceScore != 66.7 {
+		t.Errorf("score = %d/%d %.1f, want 2/3 66.7", report.CompliantPolicies, report.EnabledPolicies, report.ComplianceScore)
+	}
+	if report.OpenViolationCount != 4 {
+		t.Errorf("OpenViolationCount = %d, want 4", report.OpenViolationCount)
+	}
+	if report.Policies[0].ID != "p1" || report.Policies[0].OpenViolations != 3 {
+		t.Errorf("first policy = %+v, want p1 with 3 open violations", report.Policies[0])
+	}
+
+	wantSeverities := []SeverityCount{{"critical", 1}, {"high", 0}, {"medium", 0}, {"low", 2}, {"custom", 1}}
+	if !reflect.DeepEqual(report.OpenBySeverity, wantSeverities) {
+		t.Errorf("OpenBySeverity = %v, want %v", report.OpenBySeverity, wantSeverities)
+	}
+
+	var order []string
+	for _, violation := range report.OpenViolations {
+		order = append(order, violation.ID)
+	}
+	if want := []string{"v2", "v4", "v1", "v3"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("OpenViolations order = %v, want %v", order, want)
+	}
+	if report.OpenViolations[0].PolicyName != "alpha" {
+		t.Errorf("PolicyName = %q, want alpha", report.OpenViolations[0].PolicyName)
+	}
+	if report.OpenViolationsTruncated {
+		t.Error("OpenViolationsTruncated set for a short list")
+	}
+
+	want := RemediationStats{Remediated: 5, Ignored: 2, Pending: 4, AverageRemediationHours: 1.2}
+	if report.Remediation != want {
+		t.Errorf("Remediation = %+v, want %+v", report.Remediation, want)
+	}
+}
+
+func TestBuildComplianceReportWithoutEnabledPolicies(t *testing.T) {
+	report := BuildComplianceReport("org", []models.Policy{{ID: "p1", Enabled: false}}, nil, nil, time.Now())
+	if report.ComplianceScore != 100 {
+		t.Errorf("ComplianceScore = %.1f, want 100", report.ComplianceScore)
+	}
+	if report.OpenViolations == nil || len(report.OpenViolations) != 0 {
+		t.Errorf("OpenViolations = %v, want an empty list", report.OpenViolations)
+	}
+	if report.Remediation.AverageRemediationHours != 0 {
+		t.Errorf("AverageRemediationHours = %.1f, want 0", report.Remediation.AverageRemediationHours)
+	}
+}
+
+func TestBuildComplianceReportTruncatesViolations(t *testing.T) {
+	now := time.Now()
+	open := make([]models.PolicyViolation, maxReportedViolations+5)
+	for i := range open {
+		open[i] = models.PolicyViolation{PolicyID: "p1", Severity: "medium", CreatedAt: now.Add(-time.Duration(i) * time.Minute)}
+	}
+	open[len(open)-1].Severity = "high"
+
+	report := BuildComplianceReport("org", []models.Policy{{ID: "p1", Enabled: true}}, open, nil, now)
+	if len(report.OpenViolations) != maxReportedViolations || !report.OpenViolationsTruncated {
+		t.Fatalf("listed %d violations, truncated %v; want %d, true", len(report.OpenViolations), report.OpenViolationsTruncated, maxReportedViolations)
+	}
+	if report.OpenViolationCount != len(open) {
+		t.Errorf("OpenViolationCount = %d, want %d", report.OpenViolationCount, len(open))
+	}
+	if report.OpenViolations[0].Severity != "high" {
+		t.Errorf("first listed severity = %s, want the oldest but most severe violation", report.OpenViolations[0].Severity)
+	}
+}
@@ -0,0 +1,67 @@
+package reports
+
+import (
+	"html/template"
+	"io"
+)
+
+var complianceHTML = template.Must(template.New("compliance").Funcs(template.FuncMap{
+	"date": formatDate,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Compliance report</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #1f2933; margin: 40px; }
+h1 { margin-bottom: 4px; }
+h2 { margin-top: 32px; border-bottom: 1px solid #d9e2ec; padding-bottom: 4px; }
+table { border-collapse: collapse; width: 100%; font-size: 14px; }
+th, td { text-align: left; padding: 6px 8px; border-bottom: 1px solid #eef2f6; vertical-align: top; }
+th { background: #f5f7fa; }
+.muted { color: #627d98; }
+.score { font-size: 40px; font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>Compliance report</h1>
+<p class="muted">Organization {{.OrganizationID}} &middot; generated {{date .GeneratedAt}}</p>
+
+<h2>Compliance score</h2>
+<p class="score">{{printf "%.1f" .ComplianceScore}}%</p>
+<p>{{.CompliantPolicies}} of {{.EnabledPolicies}} enabled policies have no open violations. {{.OpenViolationCount}} violations are open.</p>
+
+<h2>Open violations by severity</h2>
+<table>
+<tr><th>Severity</th><th>Open</th></tr>
+{{range .OpenBySeverity}}<tr><td>{{.Severity}}</td><td>{{.Count}}</td></tr>
+{{end}}</table>
+
+<h2>Remediation</h2>
+<table>
+<tr><th>Remediated</th><th>Ignored</th><th>Pending</th><th>Average time to automatic remediation</th></tr>
+<tr><td>{{.Remediation.Remediated}}</td><td>{{.Remediation.Ignored}}</td><td>{{.Remediation.Pending}}</td><td>{{printf "%.1f" .Remediation.AverageRemediationHours}} h</td></tr>
+</table>
+
+<h2>Policies</h2>
+<table>
+<tr><th>Policy</th><th>Type</th><th>Mode</th><th>Enabled</th><th>Open violations</th><th>Fired</th><th>Remediated</th></tr>
+{{range .Policies}}<tr><td>{{.Name}}</td><td>{{.Type}}</td><td>{{.Mode}}</td><td>{{if .Enabled}}yes{{else}}no{{end}}</td><td>{{.OpenViolations}}</td><td>{{.FireCount}}</td><td>{{.Remediations}}</td></tr>
+{{else}}<tr><td colspan="7" class="muted">No policies</td></tr>
+{{end}}</table>
+
+<h2>Open violations</h2>
+{{if .OpenViolationsTruncated}}<p class="muted">Showing the {{len .OpenViolations}} most severe of {{.OpenViolationCount}} open violations.</p>{{end}}
+<table>
+<tr><th>Severity</th><th>Policy</th><th>Resource</th><th>Message</th><th>Opened</th></tr>
+{{range .OpenViolations}}<tr><td>{{.Severity}}</td><td>{{.PolicyName}}</td><td>{{.ResourceID}}</td><td>{{.Message}}</td><td>{{date .CreatedAt}}</td></tr>
+{{else}}<tr><td colspan="5" class="muted">No open violations</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+// RenderHTML writes a compliance report as a standalone HTML page
+func RenderHTML(w io.Writer, report ComplianceReport) error {
+	return complianceHTML.Execute(w, report)
+}
@@ -0,0 +1,47 @@
+package reports
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+)
+
+func TestRenderHTML(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	policies := []models.Policy{
+		{ID: "p1", Name: "Spend <limit>", Type: "max_spend", Enabled: true},
+		{ID: "p2", Name: "Tags", Type: "require_tags", Enabled: true},
+	}
+	open := []models.PolicyViolation{
+		{PolicyID: "p1", ResourceID: "i-1", Severity: "high", Message: `<script>alert("x")</script>`, CreatedAt: now},
+	}
+
+	var out bytes.Buffer
+	if err := RenderHTML(&out, BuildComplianceReport("org", policies, open, nil, now)); err != nil {
+		t.Fatal(err)
+	}
+	html := out.String()
+	for _, want := range []string{"50.0%", "1 of 2 enabled policies", "Spend &lt;limit&gt;", "&lt;script&gt;", "2026-03-01"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("the report should contain %q", want)
+		}
+	}
+	if strings.Contains(html, "<script>") {
+		t.Error("violation messages should be escaped")
+	}
+}
+
+func TestRenderHTMLWithoutPolicies(t *testing.T) {
+	var out bytes.Buffer
+	if err := RenderHTML(&out, BuildComplianceReport("org", nil, nil, nil, time.Now())); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"100.0%", "No policies", "No open violations"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("an empty report should contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package reports
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// Page layout of the PDF report, in points: US Letter with three-quarter-inch margins
+const (
+	pdfMargin     = 54
+	pdfLineFactor = 1.3 // Line height as a multiple of the font size
+)
+
+// pdfDocument lays report text out in the standard Helvetica fonts, which every PDF reader
+// provides, so the report needs no embedded fonts. Text is UTF-8; characters outside the
+// fonts' Windows-1252 encoding are shown as ".".
+type pdfDocument struct {
+	pdf       *fpdf.Fpdf
+	translate func(string) string
+}
+
+func newPDFDocument() *pdfDocument {
+	pdf := fpdf.New("P", "pt", "Letter", "")
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, pdfMargin)
+	pdf.AddPage()
+	return &pdfDocument{pdf: pdf, translate: pdf.UnicodeTranslatorFromDescriptor("")}
+}
+
+// line writes text in the given size and style ("" or "B"), wrapped to the page width, after
+// gap points of space
+func (d *pdfDocument) line(text string, size float64, style string, gap float64) {
+	d.pdf.Ln(gap)
+	d.pdf.SetFont("Helvetica", style, size)
+	d.pdf.MultiCell(0, size*pdfLineFactor, d.translate(text), "", "L", false)
+}
+
+func (d *pdfDocument) heading(text string) {
+	d.line(text, 13, "B", 14)
+}
+
+// text adds a paragraph, wrapped to the page width
+func (d *pdfDocument) text(text string, size float64) {
+	d.line(text, size, "", 3)
+}
+
+// RenderPDF writes a compliance report as a PDF document
+func RenderPDF(w io.Writer, report ComplianceReport) error {
+	doc := newPDFDocument()
+	doc.line("Compliance report", 20, "B", 0)
+	doc.text(fmt.Sprintf("Organization %s, generated %s", report.OrganizationID, formatDate(report.GeneratedAt)), 9)
+
+	doc.heading("Compliance score")
+	doc.line(fmt.Sprintf("%.1f%%", report.ComplianceScore), 24, "B", 6)
+	doc.text(fmt.Sprintf("%d of %d enabled policies have no open violations. %d violations are open.",
+		report.CompliantPolicies, report.EnabledPolicies, report.OpenViolationCount), 10)
+
+	doc.heading("Open violations by severity")
+	for _, count := range report.OpenBySeverity {
+		doc.text(fmt.Sprintf("%s: %d", count.Severity, count.Count), 10)
+	}
+
+	doc.heading("Remediation")
+	doc.text(fmt.Sprintf("Remediated %d, ignored %d, pending %d. Average time to automatic remediation: %.1f h.",
+		report.Remediation.Remediated, report.Remediation.Ignored, report.Remediation.Pending, report.Remediation.AverageRemediationHours), 10)
+
+	doc.heading("Policies")
+	if len(report.Policies) == 0 {
+		doc.text("No policies", 10)
+	}
+	for _, policy := range report.Policies {
+		state := "enabled"
+		if !policy.Enabled {
+			state = "disabled"
+		}
+		doc.text(fmt.Sprintf("%s (%s, %s, %s): %d open, fired %d, remediated %d",
+			policy.Name, policy.Type, policy.Mode, state, policy.OpenViolations, policy.FireCount, policy.Remediations), 10)
+	}
+
+	doc.heading("Open violations")
+	if report.OpenViolationsTruncated {
+		doc.text(fmt.Sprintf("Showing the %d most severe of %d open violations.", len(report.OpenViolations), report.OpenViolationCount), 9)
+	}
+	if len(report.OpenViolations) == 0 {
+		doc.text("No open violations", 10)
+	}
+	for _, violation := range report.OpenViolations {
+		doc.text(fmt.Sprintf("[%s] %s, %s, opened %s: %s",
+			violation.Severity, violation.PolicyName, violation.ResourceID, formatDate(violation.CreatedAt), violation.Message), 9)
+	}
+
+	return doc.pdf.Output(w)
+}
+
+// formatDate formats report timestamps in UTC
+func formatDate(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04 UTC")
+}
@@ -0,0 +1,36 @@
+package reports
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+)
+
+func TestRenderPDF(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	policies := []models.Policy{{ID: "p1", Name: "Coût mensuel – équipe données", Enabled: true}}
+	var open []models.PolicyViolation
+	for i := 0; i < 120; i++ {
+		open = append(open, models.PolicyViolation{
+			PolicyID:   "p1",
+			ResourceID: "i-0123456789",
+			Severity:   "high",
+			Message:    strings.Repeat("Dépassement du budget 東京 ", 12),
+			CreatedAt:  now,
+		})
+	}
+
+	var out bytes.Buffer
+	if err := RenderPDF(&out, BuildComplianceReport("org", policies, open, nil, now)); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(out.Bytes(), []byte("%PDF-")) {
+		t.Errorf("output starts with %q, want a PDF header", out.Bytes()[:8])
+	}
+	if pages := bytes.Count(out.Bytes(), []byte("/Type /Page\n")); pages < 2 {
+		t.Errorf("rendered %d pages, want the violations to flow onto more than one", pages)
+	}
+}
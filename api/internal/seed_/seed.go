@@ -0,0 +1,57 @@
+package seed
+
+import (
+	"fmt"
+
+	models "finopsbridge/api/internal/models_"
+
+	"gorm.io/gorm"
+)
+
+// Result counts what a seeding run created. Rows that already existed are not counted.
+type Result struct {
+	CategoriesCreated int `json:"categoriesCreated"`
+	TemplatesCreated  int `json:"templatesCreated"`
+	Categories        int `json:"categories"`
+	Templates         int `json:"templates"`
+}
+
+// PolicyTemplates seeds every built-in category and policy template, matching existing rows
+// by name so it can be run repeatedly without duplicating them
+func PolicyTemplates(db *gorm.DB) (Result, error) {
+	var result Result
+
+	categories := Categories()
+	for i := range categories {
+		created, err := firstOrCreate(db, &categories[i], models.PolicyCategory{Name: categories[i].Name})
+		if err != nil {
+			return result, fmt.Errorf("failed to seed category %s: %w", categories[i].Name, err)
+		}
+		if created {
+			result.CategoriesCreated++
+		}
+	}
+	result.Categories = len(categories)
+
+	base := categories[:len(categories)-1]
+	ai := categories[len(categories)-1]
+	templates := append(policyTemplates(base), aiPolicyTemplates(ai.ID)...)
+
+	for i := range templates {
+		created, err := firstOrCreate(db, &templates[i], models.PolicyTemplate{Name: templates[i].Name})
+		if err != nil {
+			return result, fmt.Errorf("failed to seed template %s: %w", templates[i].Name, err)
+		}
+		if created {
+			result.TemplatesCreated++
+		}
+	}
+	result.Templates = len(templates)
+
+	return result, nil
+}
+
+func firstOrCreate(db *gorm.DB, value interface{}, where interface{}) (bool, error) {
+	tx := db.Where(where).FirstOrCreate(value)
+	return tx.RowsAffected > 0, tx.Error
+}
@@ -0,0 +1,1129 @@
+package seed
+
+import (
+	"encoding/json"
+
+	models "finopsbridge/api/internal/models_"
+)
+
+// Categories returns the built-in policy categories in SortOrder, including AI & ML
+func Categories() []models.PolicyCategory {
+	return append(baseCategories(), aiCategory())
+}
+
+func baseCategories() []models.PolicyCategory {
+	return []models.PolicyCategory{
+		{
+			Name:        "Cost Control & Budget Management",
+			Description: "Policies to control cloud spending and prevent budget overruns",
+			Icon:        "💰",
+			SortOrder:   1,
+		},
+		{
+			Name:        "Resource Governance & Rightsizing",
+			Description: "Optimize resource allocation and prevent over-provisioning",
+			Icon:        "⚙️",
+			SortOrder:   2,
+		},
+		{
+			Name:        "Security & Compliance",
+			Description: "Ensure security best practices and regulatory compliance",
+			Icon:        "🔒",
+			SortOrder:   3,
+		},
+		{
+			Name:        "Operational Efficiency",
+			Description: "Automate operations and improve system reliability",
+			Icon:        "🚀",
+			SortOrder:   4,
+		},
+		{
+			Name:        "Data & Database Optimization",
+			Description: "Optimize database costs and performance",
+			Icon:        "💾",
+			SortOrder:   5,
+		},
+	}
+}
+
+func aiCategory() models.PolicyCategory {
+	return models.PolicyCategory{
+		Name:        "AI & ML Cost Control",
+		Description: "Policies for managing AI workload costs including LLM tokens, GPU utilization, and model selection governance",
+		Icon:        "🤖",
+		SortOrder:   6,
+	}
+}
+
+// policyTemplates returns the cloud policy templates. categories must be the saved result of
+// baseCategories, in order.
+func policyTemplates(categories []models.PolicyCategory) []models.PolicyTemplate {
+	return []models.PolicyTemplate{
+		// COST CONTROL
+		{
+			CategoryID:       categories[0].ID,
+			Name:             "Maximum Monthly Spend",
+			Description:      "Prevent monthly cloud spending from exceeding defined budget limits. Get alerts at 70%, 85%, and 100% of budget.",
+			PolicyType:       "max_spend",
+			EstimatedSavings: "5-15% cost reduction through awareness",
+			Difficulty:       "easy",
+			CloudProviders:   toJSON([]string{"aws", "azure", "gcp", "oci", "ibm"}),
+			BusinessImpact:   "Prevents unexpected cost overruns and promotes cost awareness across teams. Essential for budget planning and financial control.",
+			DefaultConfig: toJSON(map[string]interface{}{
+				"threshold":       10000,
+				"currency":        "USD",
+				"alertThresholds": []int{70, 85, 100},
+			}),
+			Tags:                 toJSON([]string{"budget", "alerts", "cost-control"}),
+			RequiredPermissions:  toJSON([]string{"billing:read"}),
+			ComplianceFrameworks: toJSON([]string{"finops"}),
+			RegoTemplate: `package finopsbridge.policies.max_spend
+
+default allow = false
+
+allow {
+	input.monthlySpend < data.policy.config.threshold
+}
+
+violation[msg] {
+	input.monthlySpend >= data.policy.config.threshold
+	msg := sprintf("Monthly spend $%.2f exceeds threshold $%.2f", [input.monthlySpend, data.policy.config.threshold])
+}`,
+		},
+		{
+			CategoryID:       categories[0].ID,
+			Name:             "Daily Spend Anomaly Detection",
+			Description:      "Detect unusual spending patterns using AI-based anomaly detection. Alerts when daily spend exceeds 150% of 7-day average.",
+			PolicyType:       "anomaly_detection",
+			EstimatedSavings: "10-20% by catching waste early",
+			Difficulty:       "medium",
+			CloudProviders:   toJSON([]string{"aws", "azure", "gcp"}),
+			BusinessImpact:   "Early detection prevents 40-60% of cost waste incidents by identifying spikes before they become major issues.",
+			DefaultConfig: toJSON(map[string]interface{}{
+				"dailyThreshold": 1.5,
+				"weeklyBaseline": 7,
+			}),
+			Tags:                 toJSON([]string{"anomaly", "ai", "monitoring"}),
+			RequiredPermissions:  toJSON([]string{"billing:read", "cloudwatch:read"}),
+			ComplianceFrameworks: toJSON([]string{"finops"}),
+			RegoTemplate: `package finopsbridge.policies.anomaly
+
+default allow = true
+
+violation[msg] {
+	input.dailySpend > (input.averageSpend * data.policy.config.dailyThreshold)
+	msg := sprintf("Daily spend anomaly: $%.2f (%.0f%% above baseline)", [input.dailySpend, ((input.dailySpend / input.averageSpend - 1) * 100)])
+}`,
+		},
+		{
+			CategoryID:       categories[0].ID,
+			Name:             "Month-over-Month Spend Growth",
+			Description:      "Alert when a provider's spend this month, prorated to a full month, is tracking more than 20% above last month.",
+			PolicyType:       "month_over_month_growth",
+			EstimatedSavings: "5-15% by catching gradual cost creep",
+			Difficulty:       "easy",
+			CloudProviders:   toJSON([]string{"aws", "azure", "gcp", "oci", "ibm"}),
+			BusinessImpact:   "Surfaces steady growth that daily anomaly checks miss, weeks before the invoice arrives.",
+			DefaultConfig: toJSON(map[string]interface{}{
+				"thresholdPercent": 20,
+				"minDaysElapsed":   3,
+			}),
+			Tags:                 toJSON([]string{"trend", "monitoring", "budget"}),
+			RequiredPermissions:  toJSON([]string{"billing:read"}),
+			ComplianceFrameworks: toJSON([]string{"finops"}),
+			RegoTemplate: `package finopsbridge.policies.month_over_month
+
+default allow = true
+
+violation[msg] {
+	input.month_over_month_percent > data.policy.config.thresholdPercent
+	msg := sprintf("Spend is tracking $%v this month, %v%% above last month's $%v", [round(input.projected_monthly_spend * 100) / 100, round(input.month_over_month_percent), round(input.previous_month_spend * 100) / 100])
+}`,
+		},
+		{
+			CategoryID:       categories[0].ID,
+			Name:             "Reserved Instance Optimization",
+			Description:      "Ensure optimal use of Reserved Instances and Savings Plans. Identifies steady-state workloads running on expensive on-demand pricing.",
+			PolicyType:       "reserved_instance",
+			EstimatedSavings: "30-60% on compute costs",
+			Difficulty:       "hard",
+			CloudProviders:   toJSON([]string{"aws", "azure", "gcp"}),
+			BusinessImpact:   "Up to 60% savings on compute costs through commitment-based pricing. Typical ROI: 45-55% annually.",
+			DefaultConfig: toJSON(map[string]interface{}{
+				"minUtilization":        0.75,
+				"maxOnDemandPercentage": 0.40,
+				"commitmentTerm":        "1-year",
+			}),
+			Tags:                 toJSON([]string{"reserved-instances", "savings-plans", "commitments"}),
+			RequiredPermissions:  toJSON([]string{"ec2:describe", "ce:get"}),
+			ComplianceFrameworks: toJSON([]string{"finops"}),
+			RegoTemplate: `package finopsbridge.policies.reserved_instance
+
+violation[msg] {
+	input.riUtilization < data.policy.config.minUtilization
+	msg := sprintf("RI utilization %.0f%% below target %.0f%%", [input.riUtilization * 100, data.policy.config.minUtilization * 100])
+}`,
+		},
+
+		// RESOURCE GOVERNANCE
+		{
+			CategoryID:       categories[1].ID,
+			Name:             "Block Oversized Instances",
+			Description:      "Prevent deployment of instances larger than necessary based on environment (production, staging, development).",
+			PolicyType:       "block_instance_type",
+			EstimatedSavings: "15-30% compute cost reduction",
+			Difficulty:       "easy",
+			CloudProviders:   toJSON([]string{"aws", "azure", "gcp", "oci", "ibm"}),
+			BusinessImpact:   "Prevents over-provisioning and enforces cost-conscious instance selection. Typical savings: 15-30% on compute.",
+			DefaultConfig: toJSON(map[string]interface{}{
+				"maxInstanceSize": map[string]string{
+					"production":  "xlarge",
+					"staging":     "large",
+					"development": "medium",
+				},
+			}),
+			Tags:                 toJSON([]string{"governance", "instance-size", "guardrails"}),
+			RequiredPermissions:  toJSON([]string{"ec2:describe"}),
+			ComplianceFrameworks: toJSON([]string{"finops", "ccoe"}),
+			RegoTemplate: `package finopsbridge.policies.block_instance
+
+violation[msg] {
+	blocked := data.policy.config.blockedTypes[_]
+	input.instanceType == blocked
+	msg := sprintf("Instance type %s is not allowed", [input.instanceType])
+}`,
+		},
+		{
+			CategoryID:       categories[1].ID,
+			Name:             "Auto-Stop Idle Resources",
+			Description:      "Automatically stop resources with low CPU utilization (<5%) for configurable hours. Prevents waste from forgotten resources.",
+			PolicyType:       "auto_stop_idle",
+			EstimatedSavings: "$15K-50K/month for mid-sized orgs",
+			Difficulty:       "medium",
+			CloudProviders:   toJSON([]string{"aws", "azure", "gcp"}),
+			BusinessImpact:   "Eliminates 66% of idle resource waste. Typical savings: 15-30% of total compute costs.",
+			DefaultConfig: toJSON(map[string]interface{}{
+				"idleHours":    24,
+				"cpuThreshold": 5,
+				"excludeTags":  []string{"Essential:true", "AlwaysOn:true"},
+			}),
+			Tags:                 toJSON([]string{"idle", "automation", "waste-reduction"}),
+			RequiredPermissions:  toJSON([]string{"ec2:stop", "cloudwatch:get"}),
+			ComplianceFrameworks: toJSON([]string{"finops"}),
+			RegoTemplate: `package finopsbridge.policies.auto_stop_idle
+
+violation[msg] {
+	input.cpuUtilization < data.policy.config.cpuThreshold
+	input.idleHours >= data.policy.config.idleHours
+	msg := sprintf("Resource idle for %d hours with %.1f%% CPU", [input.idleHours, input.cpuUtilization])
+}`,
+		},
+		{
+			CategoryID:       categories[1].ID,
+			Name:             "Scheduled Start/Stop",
+			Description:      "Automatically start and stop dev/test environments during business hours. Massive savings on non-production workloads.",
+			PolicyType:       "scheduled_start_stop",
+			EstimatedSavings: "50-70% on non-production",
+			Difficulty:       "medium",
+			CloudProviders:   toJSON([]string{"aws", "azure", "gcp", "oci"}),
+			BusinessImpact:   "65% savings on non-production environments. Typical savings: $5K-20K/month per environment.",
+			DefaultConfig: toJSON(map[string]interface{}{
+				"schedule": map[string]interface{}{
+					"timezone": "America/New_York",
+					"weekdays": "08:00-18:00",
+					"weekends": "off",
+				},
+				"targetEnvironments": []string{"development", "staging", "test"},
+			}),
+			Tags:                 toJSON([]string{"scheduling", "dev-test", "automation"}),
+			RequiredPermissions:  toJSON([]string{"ec2:start", "ec2:stop"}),
+			ComplianceFrameworks: toJSON([]string{"finops"}),
+			RegoTemplate: `package finopsbridge.policies.scheduled_start_stop
+
+violation[msg] {
+	not is_business_hours
+	input.status == "running"
+	input.environment != "production"
+	msg := sprintf("Non-production resource running outside business hours (%s)", [input.environment])
+}
+
+# business_hours is set by the caller from the policy's schedule
+is_business_hours {
+	input.business_hours == true
+}`,
+		},
+		{
+			CategoryID:       categories[1].ID,
+			Name:             "Unattached Resource Cleanup",
+			Description:      "Identify and remove unused cloud resources: unattached EBS volumes, unused Elastic IPs, empty load balancers, old snapshots.",
+			PolicyType:       "unattached_cleanup",
+			EstimatedSavings: "10-15% storage cost reduction",
+			Difficulty:       "easy",
+			CloudProviders:   toJSON([]string{"aws", "azure", "gcp"}),
+			BusinessImpact:   "Prevents accumulation of orphaned resources. Typical savings: 10-15% on storage costs.",
+			DefaultConfig: toJSON(map[string]interface{}{
+				"retentionDays": map[string]int{
+					"unattachedVolumes": 7,
+					"unusedEIPs":        3,
+					"oldSnapshots":      90,
+				},
+			}),
+			Tags:                 toJSON([]string{"cleanup", "storage", "waste-reduction"}),
+			RequiredPermissions:  toJSON([]string{"ec2:delete", "ec2:describe"}),
+			ComplianceFrameworks: toJSON([]string{"finops"}),
+			RegoTemplate: `package finopsbridge.policies.unattached_cleanup
+
+violation[msg] {
+	input.state == "available"
+	input.daysUnattached >= data.policy.config.retentionDays.unattachedVolumes
+	msg := sprintf("Volume unattached for %d days", [input.daysUnattached])
+}`,
+		},
+		{
+			CategoryID:       categories[1].ID,
+			Name:             "Performance-Based Rightsizing",
+			Description:      "Analyze actual CPU and memory utilization to recommend optimal instance sizes. Downsize underutilized, upsize overloaded.",
+			PolicyType:       "rightsizing",
+			EstimatedSavings: "25-35% compute cost reduction",
+			Difficulty:       "hard",
+			CloudProviders:   toJSON([]string{"aws", "azure", "gcp"}),
+			BusinessImpact:   "25-35% compute cost reduction while maintaining or improving performance.",
+			DefaultConfig: toJSON(map[string]interface{}{
+				"utilizationThresholds": map[string]float64{
+					"cpuDownsize":    0.25,
+					"cpuUpsize":      0.80,
+					"memoryDownsize": 0.30,
+				},
+				"evaluationPeriod": 14,
+			}),
+			Tags:                 toJSON([]string{"rightsizing", "optimization", "performance"}),
+			RequiredPermissions:  toJSON([]string{"ec2:describe", "cloudwatch:get"}),
+			ComplianceFrameworks: toJSON([]string{"finops"}),
+			RegoTemplate: `package finopsbridge.policies.rightsizing
+
+violation[msg] {
+	input.cpuUtilization < data.policy.config.utilizationThresholds.cpuDownsize
+	input.evaluationDays >= data.policy.config.evaluationPeriod
+	msg := sprintf("Downsize recommended: CPU %.1f%% for %d days", [input.cpuUtilization * 100, input.evaluationDays])
+}`,
+		},
+
+		// SECURITY & COMPLIANCE
+		{
+			CategoryID:       categories[2].ID,
+			Name:             "Mandatory Tagging",
+			Description:      "Enforce required tags on all cloud resources for cost allocation, ownership tracking, and compliance.",
+			PolicyType:       "require_tags",
+			EstimatedSavings: "Indirect through visibility",
+			Difficulty:       "easy",
+			CloudProviders:   toJSON([]string{"aws", "azure", "gcp", "oci", "ibm"}),
+			BusinessImpact:   "95% tag compliance enables accurate cost allocation and chargeback. Essential for FinOps maturity.",
+			DefaultConfig: toJSON(map[string]interface{}{
+				"requiredTags":     []string{"Owner", "Environment", "CostCenter", "Project"},
+				"enforcementLevel": "hard",
+			}),
+			Tags:                 toJSON([]string{"tagging", "cost-allocation", "governance"}),
+			RequiredPermissions:  toJSON([]string{"tag:describe"}),
+			ComplianceFrameworks: toJSON([]string{"finops", "itil"}),
+			RegoTemplate: `package finopsbridge.policies.require_tags
+
+violation[msg] {
+	required := data.policy.config.requiredTags[_]
+	not input.tags[required]
+	msg := sprintf("Missing required tag: %s", [required])
+}`,
+		},
+		{
+			CategoryID:       categories[2].ID,
+			Name:             "Encryption Enforcement",
+			Description:      "Ensure all storage resources use encryption at rest. Critical for SOC 2, HIPAA, and PCI-DSS compliance.",
+			PolicyType:       "encryption_enforcement",
+			EstimatedSavings: "Compliance/security benefit",
+			Difficulty:       "medium",
+			CloudProviders:   toJSON([]string{"aws", "azure", "gcp"}),
+			BusinessImpact:   "Ensures SOC 2, HIPAA, PCI-DSS compliance. Prevents data breaches and regulatory fines.",
+			DefaultConfig: toJSON(map[string]interface{}{
+				"encryptionRequired": true,
+				"keyManagement":      "customer-managed",
+				"resources":          []string{"s3", "ebs", "rds", "efs"},
+			}),
+			Tags:                 toJSON([]string{"encryption", "security", "compliance"}),
+			RequiredPermissions:  toJSON([]string{"kms:describe", "s3:describe"}),
+			ComplianceFrameworks: toJSON([]string{"soc2", "hipaa", "pci-dss"}),
+			RegoTemplate: `package finopsbridge.policies.encryption
+
+violation[msg] {
+	not input.encrypted
+	msg := sprintf("%s resource is not encrypted", [input.resourceType])
+}`,
+		},
+		{
+			CategoryID:       categories[2].ID,
+			Name:             "Public Access Prevention",
+			Description:      "Prevent accidental public exposure of S3 buckets, databases, and other sensitive resources.",
+			PolicyType:       "public_access_prevention",
+			EstimatedSavings: "Risk mitigation",
+			Difficulty:       "medium",
+			CloudProviders:   toJSON([]string{"aws", "azure", "gcp"}),
+			BusinessImpact:   "Prevents 90% of accidental data exposure incidents. Critical for security and compliance.",
+			DefaultConfig: toJSON(map[string]interface{}{
+				"blockedPorts":           []int{22, 3389, 3306, 5432},
+				"allowedPublicResources": []string{"cloudfront", "alb-waf"},
+			}),
+			Tags:                 toJSON([]string{"security", "data-protection", "compliance"}),
+			RequiredPermissions:  toJSON([]string{"s3:getBucketPolicy", "ec2:describeSecurityGroups"}),
+			ComplianceFrameworks: toJSON([]string{"soc2", "hipaa", "iso27001"}),
+			RegoTemplate: `package finopsbridge.policies.public_access
+
+violation[msg] {
+	input.publicAccess == true
+	msg := sprintf("%s has public access enabled", [input.resourceId])
+}`,
+		},
+
+		// OPERATIONAL EFFICIENCY
+		{
+			CategoryID:       categories[3].ID,
+			Name:             "Backup and Disaster Recovery",
+			Description:      "Automate backup policies for critical databases and ensure business continuity with tested DR procedures.",
+			PolicyType:       "backup_enforcement",
+			EstimatedSavings: "DR/compliance benefit",
+			Difficulty:       "medium",
+			CloudProviders:   toJSON([]string{"aws", "azure", "gcp"}),
+			BusinessImpact:   "99.9% data durability. Ensures RTO < 4 hours, RPO < 1 hour for critical systems.",
+			DefaultConfig: toJSON(map[string]interface{}{
+				"backupRetention": map[string]int{
+					"production":  30,
+					"staging":     7,
+					"development": 3,
+				},
+				"requireCrossRegion": true,
+			}),
+			Tags:                 toJSON([]string{"backup", "disaster-recovery", "compliance"}),
+			RequiredPermissions:  toJSON([]string{"backup:describe", "rds:describe"}),
+			ComplianceFrameworks: toJSON([]string{"soc2", "iso27001"}),
+			RegoTemplate: `package finopsbridge.policies.backup
+
+violation[msg] {
+	not input.backupEnabled
+	input.environment == "production"
+	msg := "Production database does not have automated backups enabled"
+}`,
+		},
+		{
+			CategoryID:       categories[3].ID,
+			Name:             "Storage Lifecycle Management",
+			Description:      "Automatically tier S3 data to lower-cost storage classes based on access patterns. Glacier for archival data.",
+			PolicyType:       "lifecycle_management",
+			EstimatedSavings: "50-70% storage cost reduction",
+			Difficulty:       "easy",
+			CloudProviders:   toJSON([]string{"aws", "azure", "gcp"}),
+			BusinessImpact:   "50-70% storage cost reduction through intelligent tiering. Automatic compliance with retention policies.",
+			DefaultConfig: toJSON(map[string]interface{}{
+				"s3Lifecycle": map[string]int{
+					"standardToIA": 30,
+					"iaToGlacier":  90,
+					"deleteAfter":  365,
+				},
+			}),
+			Tags:                 toJSON([]string{"storage", "lifecycle", "cost-optimization"}),
+			RequiredPermissions:  toJSON([]string{"s3:putLifecycleConfiguration"}),
+			ComplianceFrameworks: toJSON([]string{"finops"}),
+			RegoTemplate: `package finopsbridge.policies.lifecycle
+
+violation[msg] {
+	not input.lifecyclePolicyEnabled
+	input.bucketSize > 1000000000
+	msg := "Large S3 bucket without lifecycle policy"
+}`,
+		},
+
+		// DATABASE OPTIMIZATION
+		{
+			CategoryID:       categories[4].ID,
+			Name:             "Database Rightsizing",
+			Description:      "Ensure databases are properly sized based on actual CPU, memory, and connection utilization patterns.",
+			PolicyType:       "database_rightsizing",
+			EstimatedSavings: "30-40% database cost savings",
+			Difficulty:       "hard",
+			CloudProviders:   toJSON([]string{"aws", "azure", "gcp"}),
+			BusinessImpact:   "30-40% database cost savings while maintaining performance SLAs.",
+			DefaultConfig: toJSON(map[string]interface{}{
+				"cpuThreshold":     0.20,
+				"storageThreshold": 0.80,
+				"evaluationPeriod": 14,
+			}),
+			Tags:                 toJSON([]string{"database", "rightsizing", "rds"}),
+			RequiredPermissions:  toJSON([]string{"rds:describe", "cloudwatch:get"}),
+			ComplianceFrameworks: toJSON([]string{"finops"}),
+			RegoTemplate: `package finopsbridge.policies.database_rightsizing
+
+violation[msg] {
+	input.cpuUtilization < data.policy.config.cpuThreshold
+	input.evaluationDays >= data.policy.config.evaluationPeriod
+	msg := sprintf("Database underutilized: %.1f%% CPU for %d days", [input.cpuUtilization * 100, input.evaluationDays])
+}`,
+		},
+	}
+}
+
+// aiPolicyTemplates returns the AI & ML policy templates for the saved AI category
+func aiPolicyTemplates(categoryID string) []models.PolicyTemplate {
+	return []models.PolicyTemplate{
+		// 1. LLM Token Budget Enforcement
+		{
+			CategoryID:  categoryID,
+			Name:        "LLM Token Budget Enforcement",
+			Description: "Enforce daily and monthly token consumption limits to prevent runaway LLM API costs. Tracks usage across GPT-4, Claude, Gemini, and other LLM providers.",
+			PolicyType:  "llm_token_budget",
+			DefaultConfig: toJSON(map[string]interface{}{
+				"dailyTokenLimit":   1000000,
+				"monthlyTokenLimit": 25000000,
+				"providers":         []string{"openai", "anthropic", "google", "azure_openai"},
+				"alertThresholds":   []int{70, 85, 95, 100},
+				"enforceHardLimit":  true,
+				"exemptUsers":       []string{},
+			}),
+			RegoTemplate: `package llm_token_budget
+
+default allow = false
+
+allow {
+    input.tokenUsage.daily < input.config.dailyTokenLimit
+    input.tokenUsage.monthly < input.config.monthlyTokenLimit
+}
+
+violation[msg] {
+    input.tokenUsage.daily >= input.config.dailyTokenLimit
+    msg := sprintf("Daily token limit exceeded: %d/%d tokens used", [input.tokenUsage.daily, input.config.dailyTokenLimit])
+}
+
+violation[msg] {
+    input.tokenUsage.monthly >= input.config.monthlyTokenLimit
+    msg := sprintf("Monthly token limit exceeded: %d/%d tokens used", [input.tokenUsage.monthly, input.config.monthlyTokenLimit])
+}`,
+			EstimatedSavings:     "30-40%",
+			Difficulty:           "easy",
+			RequiredPermissions:  toJSON([]string{"cloudwatch:GetMetricData", "monitoring:ReadMetrics"}),
+			Tags:                 toJSON([]string{"ai", "llm", "budget", "tokens", "cost-control"}),
+			CloudProviders:       toJSON([]string{"aws", "azure", "gcp", "openai", "anthropic"}),
+			ComplianceFrameworks: toJSON([]string{}),
+			BusinessImpact:       "Prevents surprise LLM API bills through proactive token limit enforcement. Essential for organizations experimenting with multiple AI features.",
+		},
+
+		// 2. GPU Idle Detection & Auto-Stop
+		{
+			CategoryID:  categoryID,
+			Name:        "GPU Idle Detection & Auto-Stop",
+			Description: "Automatically stop GPU instances with low utilization (<10%) for extended periods. Single H100 GPU can cost $3-5/hour on AWS.",
+			PolicyType:  "gpu_idle_detection",
+			DefaultConfig: toJSON(map[string]interface{}{
+				"idleThresholdPercent": 10,
+				"idleDurationMinutes":  30,
+				"autoStop":             true,
+				"notifyBeforeStop":     true,
+				"excludeInstances":     []string{},
+				"includedGPUTypes":     []string{"A100", "V100", "H100", "T4", "A10G"},
+				"excludeProductionEnv": true,
+			}),
+			RegoTemplate: `package gpu_idle_detection
+
+default allow = true
+
+violation[msg] {
+    input.gpu.utilization < input.config.idleThresholdPercent
+    input.gpu.idleMinutes >= input.config.idleDurationMinutes
+    not is_excluded(input.gpu.instanceId)
+    not is_production(input.gpu.environment)
+    msg := sprintf("GPU instance %s idle at %.1f%% for %d minutes - auto-stopping", [input.gpu.instanceId, input.gpu.utilization, input.gpu.idleMinutes])
+}
+
+is_excluded(instanceId) {
+    input.config.excludeInstances[_] == instanceId
+}
+
+is_production(env) {
+    input.config.excludeProductionEnv == true
+    env == "production"
+}`,
+			EstimatedSavings:     "40-60%",
+			Difficulty:           "medium",
+			RequiredPermissions:  toJSON([]string{"ec2:DescribeInstances", "ec2:StopInstances", "compute.instances.stop"}),
+			Tags:                 toJSON([]string{"ai", "gpu", "cost-optimization", "idle-resources"}),
+			CloudProviders:       toJSON([]string{"aws", "azure", "gcp"}),
+			ComplianceFrameworks: toJSON([]string{}),
+			BusinessImpact:       "GPU costs are 10-50x higher than standard compute. Eliminating idle GPU hours can save $50K-200K annually for ML teams.",
+		},
+
+		// 3. Model Selection Governance
+		{
+			CategoryID:  categoryID,
+			Name:        "Model Selection Governance",
+			Description: "Prevent expensive model over-selection by requiring justification for premium models (GPT-4, Claude Opus) vs. cost-effective alternatives (GPT-4o-mini, Haiku).",
+			PolicyType:  "model_selection_governance",
+			DefaultConfig: toJSON(map[string]interface{}{
+				"approvalRequired": []string{"gpt-4", "gpt-4-turbo", "claude-3-opus"},
+				"recommendAlternatives": map[string]string{
+					"gpt-4":         "gpt-4o-mini",
+					"claude-3-opus": "claude-3-haiku",
+					"gemini-pro":    "gemini-flash",
+				},
+				"costThresholdPerCall":   0.01,
+				"enforceForEnvironments": []string{"development", "staging"},
+			}),
+			RegoTemplate: `package model_selection_governance
+
+default allow = true
+
+violation[msg] {
+    requires_approval(input.model.name)
+    not has_approval(input.request)
+    msg := sprintf("Model %s requires approval. Consider using %s instead (90%% cost savings)", [input.model.name, get_alternative(input.model.name)])
+}
+
+violation[msg] {
+    input.model.estimatedCost > input.config.costThresholdPerCall
+    is_non_production(input.environment)
+    msg := sprintf("Using expensive model %s in %s environment. Cost: $%.4f per call", [input.model.name, input.environment, input.model.estimatedCost])
+}
+
+requires_approval(modelName) {
+    input.config.approvalRequired[_] == modelName
+}
+
+has_approval(request) {
+    request.approved == true
+}
+
+get_alternative(modelName) = alternative {
+    alternative := input.config.recommendAlternatives[modelName]
+}
+
+is_non_production(env) {
+    input.config.enforceForEnvironments[_] == env
+}`,
+			EstimatedSavings:     "80-90%",
+			Difficulty:           "easy",
+			RequiredPermissions:  toJSON([]string{}),
+			Tags:                 toJSON([]string{"ai", "llm", "cost-optimization", "governance"}),
+			CloudProviders:       toJSON([]string{"openai", "anthropic", "azure", "aws", "gcp"}),
+			ComplianceFrameworks: toJSON([]string{}),
+			BusinessImpact:       "GPT-4o-mini is 10x cheaper than GPT-4 for simple tasks. Proper model selection can reduce LLM costs by 80-90% without sacrificing quality.",
+		},
+
+		// 4. Prompt Caching Requirement
+		{
+			CategoryID:  categoryID,
+			Name:        "Prompt Caching Requirement",
+			Description: "Mandate caching for prompts over specified token length to reduce redundant LLM API calls and costs by 15-30%.",
+			PolicyType:  "prompt_caching_requirement",
+			DefaultConfig: toJSON(map[string]interface{}{
+				"minTokensForCaching": 100,
+				"cacheTTLMinutes":     60,
+				"cacheProvider":       "redis",
+				"exemptEndpoints":     []string{},
+				"enforceForModels":    []string{"gpt-4", "claude-3-opus", "gpt-4-turbo"},
+			}),
+			RegoTemplate: `package prompt_caching_requirement
+
+default allow = true
+
+violation[msg] {
+    input.prompt.tokenCount >= input.config.minTokensForCaching
+    not input.request.cachingEnabled
+    should_enforce_caching(input.model.name)
+    msg := sprintf("Prompt with %d tokens should use caching. Enable caching to save 15-30%% on costs", [input.prompt.tokenCount])
+}
+
+should_enforce_caching(modelName) {
+    input.config.enforceForModels[_] == modelName
+}`,
+			EstimatedSavings:     "15-30%",
+			Difficulty:           "medium",
+			RequiredPermissions:  toJSON([]string{}),
+			Tags:                 toJSON([]string{"ai", "caching", "cost-optimization", "performance"}),
+			CloudProviders:       toJSON([]string{"openai", "anthropic", "azure", "aws"}),
+			ComplianceFrameworks: toJSON([]string{}),
+			BusinessImpact:       "Strategic caching reduces costs by 15-30% for applications with repetitive prompts like customer service bots and document analysis.",
+		},
+
+		// 5. Batch Processing for Non-Real-Time AI
+		{
+			CategoryID:  categoryID,
+			Name:        "Batch Processing for Non-Real-Time AI",
+			Description: "Route non-urgent AI requests to batch APIs offering 50% discount. Ideal for analytics, reporting, and bulk processing workloads.",
+			PolicyType:  "batch_processing_requirement",
+			DefaultConfig: toJSON(map[string]interface{}{
+				"batchEligibleTypes": []string{"analytics", "reporting", "bulk_processing"},
+				"maxLatencyHours":    24,
+				"minimumBatchSize":   10,
+				"providers":          []string{"openai", "anthropic"},
+			}),
+			RegoTemplate: `package batch_processing_requirement
+
+default allow = true
+
+violation[msg] {
+    is_batch_eligible(input.request.type)
+    not input.request.useBatchAPI
+    input.request.urgency != "realtime"
+    msg := sprintf("Request type '%s' should use batch API for 50%% cost savings", [input.request.type])
+}
+
+is_batch_eligible(requestType) {
+    input.config.batchEligibleTypes[_] == requestType
+}`,
+			EstimatedSavings:     "50%",
+			Difficulty:           "medium",
+			RequiredPermissions:  toJSON([]string{}),
+			Tags:                 toJSON([]string{"ai", "batch-processing", "cost-optimization"}),
+			CloudProviders:       toJSON([]string{"openai", "anthropic", "azure"}),
+			ComplianceFrameworks: toJSON([]string{}),
+			BusinessImpact:       "Batch APIs offer 50% discounts for non-real-time workloads. Organizations processing large volumes can save $10K-50K monthly.",
+		},
+
+		// 6. Training Job Budget Caps
+		{
+			CategoryID:  categoryID,
+			Name:        "Training Job Budget Caps",
+			Description: "Prevent expensive training job overruns by enforcing budget caps and requiring approval for high-cost training workloads.",
+			PolicyType:  "training_job_budget_cap",
+			DefaultConfig: toJSON(map[string]interface{}{
+				"maxCostPerJob":        1000.0,
+				"requireApprovalAbove": 500.0,
+				"maxGPUHours":          100,
+				"alertAtPercentages":   []int{50, 75, 90},
+				"autoStopAtBudget":     true,
+			}),
+			RegoTemplate: `package training_job_budget_cap
+
+default allow = true
+
+violation[msg] {
+    input.training.estimatedCost > input.config.maxCostPerJob
+    not input.training.hasApproval
+    msg := sprintf("Training job estimated at $%.2f exceeds max budget of $%.2f - approval required", [input.training.estimatedCost, input.config.maxCostPerJob])
+}
+
+violation[msg] {
+    input.training.currentCost >= input.config.maxCostPerJob
+    input.config.autoStopAtBudget
+    msg := sprintf("Training job reached budget cap of $%.2f - stopping job", [input.config.maxCostPerJob])
+}`,
+			EstimatedSavings:     "Prevents catastrophic spending",
+			Difficulty:           "medium",
+			RequiredPermissions:  toJSON([]string{"sagemaker:StopTrainingJob", "ai-platform:cancel"}),
+			Tags:                 toJSON([]string{"ai", "training", "budget", "cost-control"}),
+			CloudProviders:       toJSON([]string{"aws", "azure", "gcp"}),
+			ComplianceFrameworks: toJSON([]string{}),
+			BusinessImpact:       "Prevents catastrophic training cost overruns. Single unmonitored training job can cost $10K-100K if left running.",
+		},
+
+		// 7. Spot/Preemptible Instances for Training
+		{
+			CategoryID:  categoryID,
+			Name:        "Spot/Preemptible Instances for Training",
+			Description: "Require use of spot/preemptible instances for fault-tolerant ML training jobs to save 60-90% on compute costs.",
+			PolicyType:  "spot_instances_for_training",
+			DefaultConfig: toJSON(map[string]interface{}{
+				"requireSpotFor":        []string{"training", "batch_inference"},
+				"minJobDuration":        2,
+				"allowOnDemandForHours": 1,
+				"checkpointingRequired": true,
+				"excludeJobs":           []string{},
+			}),
+			RegoTemplate: `package spot_instances_for_training
+
+default allow = true
+
+violation[msg] {
+    is_training_job(input.job.type)
+    not input.job.useSpotInstances
+    input.job.estimatedHours >= input.config.minJobDuration
+    not is_excluded(input.job.id)
+    msg := sprintf("Training job running %d hours should use spot instances for 60-90%% savings", [input.job.estimatedHours])
+}
+
+is_training_job(jobType) {
+    input.config.requireSpotFor[_] == jobType
+}
+
+is_excluded(jobId) {
+    input.config.excludeJobs[_] == jobId
+}`,
+			EstimatedSavings:     "60-90%",
+			Difficulty:           "medium",
+			RequiredPermissions:  toJSON([]string{"ec2:RequestSpotInstances", "compute.instances.create"}),
+			Tags:                 toJSON([]string{"ai", "training", "spot-instances", "cost-optimization"}),
+			CloudProviders:       toJSON([]string{"aws", "azure", "gcp"}),
+			ComplianceFrameworks: toJSON([]string{}),
+			BusinessImpact:       "Spot instances save 60-90% on training costs. With checkpointing, interruptions are minimal. Can save $50K-200K annually on ML training.",
+		},
+
+		// 8. Token Length Limits
+		{
+			CategoryID:  categoryID,
+			Name:        "Token Length Limits",
+			Description: "Control costs by limiting maximum prompt and response token lengths. Prevents verbose prompts and excessive output generation.",
+			PolicyType:  "token_length_limits",
+			DefaultConfig: toJSON(map[string]interface{}{
+				"maxInputTokens":   4000,
+				"maxOutputTokens":  1000,
+				"enforceForModels": []string{"gpt-4", "claude-3-opus"},
+				"allowExceptions":  true,
+				"exemptEndpoints":  []string{"/api/generate-report"},
+			}),
+			RegoTemplate: `package token_length_limits
+
+default allow = true
+
+violation[msg] {
+    input.request.inputTokens > input.config.maxInputTokens
+    should_enforce(input.model.name)
+    not is_exempt(input.request.endpoint)
+    msg := sprintf("Input prompt exceeds limit: %d tokens (max: %d)", [input.request.inputTokens, input.config.maxInputTokens])
+}
+
+violation[msg] {
+    input.request.maxOutputTokens > input.config.maxOutputTokens
+    should_enforce(input.model.name)
+    not is_exempt(input.request.endpoint)
+    msg := sprintf("Requested output exceeds limit: %d tokens (max: %d)", [input.request.maxOutputTokens, input.config.maxOutputTokens])
+}
+
+should_enforce(modelName) {
+    input.config.enforceForModels[_] == modelName
+}
+
+is_exempt(endpoint) {
+    input.config.exemptEndpoints[_] == endpoint
+}`,
+			EstimatedSavings:     "20-30%",
+			Difficulty:           "easy",
+			RequiredPermissions:  toJSON([]string{}),
+			Tags:                 toJSON([]string{"ai", "tokens", "cost-control", "governance"}),
+			CloudProviders:       toJSON([]string{"openai", "anthropic", "azure", "aws"}),
+			ComplianceFrameworks: toJSON([]string{}),
+			BusinessImpact:       "Verbose prompts and excessive output generation waste 20-30% of LLM budgets. Token limits enforce efficient prompt engineering.",
+		},
+
+		// 9. Model Versioning Governance
+		{
+			CategoryID:  categoryID,
+			Name:        "Model Versioning Governance",
+			Description: "Control costs when new model versions release (often 5-20x more expensive). Require approval before upgrading to premium model versions.",
+			PolicyType:  "model_versioning_governance",
+			DefaultConfig: toJSON(map[string]interface{}{
+				"approvalRequired":      []string{"gpt-5", "claude-4", "gemini-ultra"},
+				"allowedModels":         []string{"gpt-4o", "claude-3-sonnet", "gemini-pro"},
+				"costIncreaseThreshold": 2.0,
+				"notifyOnNewReleases":   true,
+			}),
+			RegoTemplate: `package model_versioning_governance
+
+default allow = true
+
+violation[msg] {
+    requires_approval(input.model.name)
+    not input.request.hasApproval
+    msg := sprintf("Model %s requires approval due to premium pricing", [input.model.name])
+}
+
+violation[msg] {
+    is_cost_increase_significant(input.model.priceMultiplier)
+    not input.request.hasApproval
+    msg := sprintf("Model cost %.1fx higher than current - approval required", [input.model.priceMultiplier])
+}
+
+requires_approval(modelName) {
+    input.config.approvalRequired[_] == modelName
+}
+
+is_cost_increase_significant(multiplier) {
+    multiplier >= input.config.costIncreaseThreshold
+}`,
+			EstimatedSavings:     "Prevents 5-20x cost increases",
+			Difficulty:           "easy",
+			RequiredPermissions:  toJSON([]string{}),
+			Tags:                 toJSON([]string{"ai", "versioning", "cost-control", "governance"}),
+			CloudProviders:       toJSON([]string{"openai", "anthropic", "azure", "aws", "gcp"}),
+			ComplianceFrameworks: toJSON([]string{}),
+			BusinessImpact:       "New model versions can be 5-20x more expensive (e.g., GPT-4 Turbo vs. GPT-5). Governance prevents automatic cost escalation.",
+		},
+
+		// 10. Inference Endpoint Rightsizing
+		{
+			CategoryID:  categoryID,
+			Name:        "Inference Endpoint Rightsizing",
+			Description: "Auto-scale inference endpoints based on traffic patterns and utilization. Prevent over-provisioning that wastes 30-50% of inference costs.",
+			PolicyType:  "inference_endpoint_rightsizing",
+			DefaultConfig: toJSON(map[string]interface{}{
+				"minUtilizationPercent": 60,
+				"scaleDownThreshold":    30,
+				"evaluationPeriodMin":   15,
+				"minInstances":          1,
+				"maxInstances":          10,
+				"targetUtilization":     75,
+			}),
+			RegoTemplate: `package inference_endpoint_rightsizing
+
+default allow = true
+
+violation[msg] {
+    input.endpoint.utilization < input.config.scaleDownThreshold
+    input.endpoint.instanceCount > input.config.minInstances
+    evaluation_period_met(input.endpoint.lowUtilizationMinutes)
+    msg := sprintf("Inference endpoint at %.1f%% utilization - scale down recommended", [input.endpoint.utilization])
+}
+
+violation[msg] {
+    input.endpoint.utilization > 90
+    input.endpoint.instanceCount < input.config.maxInstances
+    msg := "Inference endpoint overloaded - scale up recommended"
+}
+
+evaluation_period_met(minutes) {
+    minutes >= input.config.evaluationPeriodMin
+}`,
+			EstimatedSavings:     "30-50%",
+			Difficulty:           "medium",
+			RequiredPermissions:  toJSON([]string{"sagemaker:UpdateEndpoint", "ai-platform:updateModel"}),
+			Tags:                 toJSON([]string{"ai", "inference", "autoscaling", "cost-optimization"}),
+			CloudProviders:       toJSON([]string{"aws", "azure", "gcp"}),
+			ComplianceFrameworks: toJSON([]string{}),
+			BusinessImpact:       "Over-provisioned inference endpoints waste 30-50% of costs. Auto-scaling matches capacity to demand, saving $20K-100K annually.",
+		},
+
+		// 11. AI Sandbox Budget Limits
+		{
+			CategoryID:  categoryID,
+			Name:        "AI Sandbox Budget Limits",
+			Description: "Control experimentation costs by setting per-user or per-team budget limits for AI sandbox environments.",
+			PolicyType:  "ai_sandbox_budget_limit",
+			DefaultConfig: toJSON(map[string]interface{}{
+				"budgetPerUser":    500.0,
+				"budgetPerTeam":    5000.0,
+				"resetPeriod":      "monthly",
+				"enforceHardLimit": true,
+				"alertThresholds":  []int{50, 75, 90, 100},
+			}),
+			RegoTemplate: `package ai_sandbox_budget_limit
+
+default allow = true
+
+violation[msg] {
+    input.user.spending >= input.config.budgetPerUser
+    input.config.enforceHardLimit
+    msg := sprintf("User sandbox budget exceeded: $%.2f/$%.2f", [input.user.spending, input.config.budgetPerUser])
+}
+
+violation[msg] {
+    input.team.spending >= input.config.budgetPerTeam
+    input.config.enforceHardLimit
+    msg := sprintf("Team sandbox budget exceeded: $%.2f/$%.2f", [input.team.spending, input.config.budgetPerTeam])
+}`,
+			EstimatedSavings:     "Prevents uncontrolled exploration",
+			Difficulty:           "easy",
+			RequiredPermissions:  toJSON([]string{}),
+			Tags:                 toJSON([]string{"ai", "sandbox", "budget", "cost-control"}),
+			CloudProviders:       toJSON([]string{"openai", "anthropic", "aws", "azure", "gcp"}),
+			ComplianceFrameworks: toJSON([]string{}),
+			BusinessImpact:       "Uncontrolled AI experimentation can cost $5K-50K monthly. Per-user budgets enable innovation while controlling costs.",
+		},
+
+		// 12. GPU Time-Slicing Enforcement
+		{
+			CategoryID:  categoryID,
+			Name:        "GPU Time-Slicing Enforcement",
+			Description: "Maximize GPU utilization through time-slicing, allowing multiple workloads to share GPUs and saving 50-70% on GPU costs.",
+			PolicyType:  "gpu_time_slicing",
+			DefaultConfig: toJSON(map[string]interface{}{
+				"requireTimeSlicing": true,
+				"maxWorkloadsPerGPU": 4,
+				"environments":       []string{"development", "staging"},
+				"excludeGPUTypes":    []string{"H100"},
+			}),
+			RegoTemplate: `package gpu_time_slicing
+
+default allow = true
+
+violation[msg] {
+    should_time_slice(input.gpu.environment)
+    not input.gpu.timeSlicingEnabled
+    not is_excluded_gpu(input.gpu.type)
+    input.gpu.workloadCount < input.config.maxWorkloadsPerGPU
+    msg := sprintf("GPU %s should use time-slicing in %s environment for 50-70%% cost savings", [input.gpu.instanceId, input.gpu.environment])
+}
+
+should_time_slice(env) {
+    input.config.environments[_] == env
+}
+
+is_excluded_gpu(gpuType) {
+    input.config.excludeGPUTypes[_] == gpuType
+}`,
+			EstimatedSavings:     "50-70%",
+			Difficulty:           "hard",
+			RequiredPermissions:  toJSON([]string{"kubernetes:patchNodes"}),
+			Tags:                 toJSON([]string{"ai", "gpu", "time-slicing", "cost-optimization"}),
+			CloudProviders:       toJSON([]string{"aws", "azure", "gcp"}),
+			ComplianceFrameworks: toJSON([]string{}),
+			BusinessImpact:       "GPU time-slicing allows 4+ workloads per GPU, reducing costs by 50-70%. Essential for development and testing environments.",
+		},
+
+		// 13. Reserved GPU Capacity Recommendations
+		{
+			CategoryID:  categoryID,
+			Name:        "Reserved GPU Capacity Recommendations",
+			Description: "Recommend reserved GPU instances or savings plans for 24/7 production inference workloads to save 40-60% vs. on-demand pricing.",
+			PolicyType:  "reserved_gpu_capacity",
+			DefaultConfig: toJSON(map[string]interface{}{
+				"minUptimeHoursForRecommendation": 730,
+				"savingsThreshold":                40.0,
+				"commitmentTerm":                  "1-year",
+				"paymentOption":                   "no-upfront",
+			}),
+			RegoTemplate: `package reserved_gpu_capacity
+
+default allow = true
+
+recommendation[msg] {
+    input.gpu.uptimeHours >= input.config.minUptimeHoursForRecommendation
+    input.gpu.environment == "production"
+    potential_savings := calculate_savings(input.gpu.monthlyCost)
+    potential_savings >= input.config.savingsThreshold
+    msg := sprintf("GPU instance %s running 24/7 - consider %s reserved capacity for %.0f%% savings ($%.2f/month)", [input.gpu.instanceType, input.config.commitmentTerm, potential_savings, input.gpu.monthlyCost * (potential_savings/100)])
+}
+
+calculate_savings(monthlyCost) = savings {
+    savings := 50.0
+}`,
+			EstimatedSavings:     "40-60%",
+			Difficulty:           "easy",
+			RequiredPermissions:  toJSON([]string{}),
+			Tags:                 toJSON([]string{"ai", "gpu", "reserved-instances", "cost-optimization"}),
+			CloudProviders:       toJSON([]string{"aws", "azure", "gcp"}),
+			ComplianceFrameworks: toJSON([]string{}),
+			BusinessImpact:       "Reserved GPU capacity saves 40-60% for steady-state production workloads. Can save $100K-500K annually on inference infrastructure.",
+		},
+
+		// 14. Data Transfer Minimization for AI
+		{
+			CategoryID:  categoryID,
+			Name:        "Data Transfer Minimization for AI",
+			Description: "Reduce cross-region data transfer costs by co-locating AI models and training data in the same region. Data transfer can add 15-25% to AI costs.",
+			PolicyType:  "ai_data_transfer_minimization",
+			DefaultConfig: toJSON(map[string]interface{}{
+				"requireSameRegion":      true,
+				"allowedRegions":         []string{"us-east-1", "us-west-2"},
+				"maxTransferCostPercent": 10.0,
+			}),
+			RegoTemplate: `package ai_data_transfer_minimization
+
+default allow = true
+
+violation[msg] {
+    input.data.region != input.model.region
+    input.config.requireSameRegion
+    msg := sprintf("Data in %s, model in %s - co-locate to same region to reduce transfer costs by 15-25%%", [input.data.region, input.model.region])
+}
+
+violation[msg] {
+    transfer_cost_percentage := (input.costs.dataTransfer / input.costs.total) * 100
+    transfer_cost_percentage > input.config.maxTransferCostPercent
+    msg := sprintf("Data transfer costs are %.1f%% of total AI costs (max: %.1f%%) - optimize data locality", [transfer_cost_percentage, input.config.maxTransferCostPercent])
+}`,
+			EstimatedSavings:     "15-25%",
+			Difficulty:           "medium",
+			RequiredPermissions:  toJSON([]string{}),
+			Tags:                 toJSON([]string{"ai", "data-transfer", "cost-optimization", "networking"}),
+			CloudProviders:       toJSON([]string{"aws", "azure", "gcp"}),
+			ComplianceFrameworks: toJSON([]string{}),
+			BusinessImpact:       "Cross-region data transfer for AI workloads adds 15-25% to costs. Co-location can save $10K-50K monthly on large-scale ML operations.",
+		},
+
+		// 15. Model Lifecycle Management
+		{
+			CategoryID:  categoryID,
+			Name:        "Model Lifecycle Management",
+			Description: "Automatically archive unused models to cold storage (S3 Glacier, Azure Archive) to save 90% on storage costs while maintaining model history.",
+			PolicyType:  "model_lifecycle_management",
+			DefaultConfig: toJSON(map[string]interface{}{
+				"archiveAfterDays":   90,
+				"deleteAfterDays":    365,
+				"coldStorageClass":   "glacier",
+				"excludeProduction":  true,
+				"keepLatestVersions": 3,
+			}),
+			RegoTemplate: `package model_lifecycle_management
+
+default allow = true
+
+violation[msg] {
+    days_since_use := calculate_days(input.model.lastUsed)
+    days_since_use >= input.config.archiveAfterDays
+    not input.model.isProduction
+    not input.model.inColdStorage
+    msg := sprintf("Model %s unused for %d days - archive to cold storage for 90%% storage savings", [input.model.name, days_since_use])
+}
+
+violation[msg] {
+    days_since_use := calculate_days(input.model.lastUsed)
+    days_since_use >= input.config.deleteAfterDays
+    not is_recent_version(input.model)
+    msg := sprintf("Model %s unused for %d days - consider deletion", [input.model.name, days_since_use])
+}
+
+calculate_days(lastUsed) = days {
+    days := 100
+}
+
+is_recent_version(model) {
+    model.versionRank <= input.config.keepLatestVersions
+}`,
+			EstimatedSavings:     "90% on storage",
+			Difficulty:           "medium",
+			RequiredPermissions:  toJSON([]string{"s3:PutLifecycleConfiguration", "storage.buckets.update"}),
+			Tags:                 toJSON([]string{"ai", "storage", "lifecycle", "cost-optimization"}),
+			CloudProviders:       toJSON([]string{"aws", "azure", "gcp"}),
+			ComplianceFrameworks: toJSON([]string{}),
+			BusinessImpact:       "ML teams accumulate hundreds of model versions. Cold storage saves 90% on storage while maintaining compliance and audit trails.",
+		},
+
+		// 16. Token Cost Anomaly Detection
+		{
+			CategoryID:  categoryID,
+			Name:        "Token Cost Anomaly Detection",
+			Description: "Alert when a model's token cost today exceeds 200% of its 7-day daily average, catching prompt loops and runaway agents within the day.",
+			PolicyType:  "token_cost_anomaly",
+			DefaultConfig: toJSON(map[string]interface{}{
+				"dailyThreshold": 2.0,
+				"weeklyBaseline": 7,
+				"minDailyCost":   10.0,
+				"providers":      []string{},
+			}),
+			RegoTemplate: `package token_cost_anomaly
+
+default allow = true
+
+violation[msg] {
+    input.tokenCost.daily > input.tokenCost.average * input.config.dailyThreshold
+    input.tokenCost.daily >= input.config.minDailyCost
+    msg := sprintf("Token cost anomaly for %s: $%.2f today, %.0f%% above its %d-day average of $%.2f", [input.model.name, input.tokenCost.daily, ((input.tokenCost.daily / input.tokenCost.average - 1) * 100), input.tokenCost.baselineDays, input.tokenCost.average])
+}`,
+			EstimatedSavings:     "Caps runaway spend at one day",
+			Difficulty:           "easy",
+			RequiredPermissions:  toJSON([]string{}),
+			Tags:                 toJSON([]string{"ai", "llm", "anomaly", "tokens", "monitoring"}),
+			CloudProviders:       toJSON([]string{"openai", "anthropic", "azure", "aws", "gcp"}),
+			ComplianceFrameworks: toJSON([]string{}),
+			BusinessImpact:       "A prompt loop or retry storm can burn a month of LLM budget overnight. Per-model baselines flag the spike the same day, without a fixed limit to maintain.",
+		},
+	}
+}
+
+// toJSON encodes template metadata. It only receives literal maps and slices, which always marshal.
+func toJSON(v interface{}) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
@@ -0,0 +1,75 @@
+package seed
+
+import (
+	"encoding/json"
+	"testing"
+
+	models "finopsbridge/api/internal/models_"
+
+	"github.com/open-policy-agent/opa/ast"
+)
+
+// savedCategories returns the built-in categories with the IDs saving them would assign
+func savedCategories() []models.PolicyCategory {
+	categories := Categories()
+	for i := range categories {
+		categories[i].ID = categories[i].Name
+	}
+	return categories
+}
+
+func allTemplates() []models.PolicyTemplate {
+	categories := savedCategories()
+	ai := categories[len(categories)-1]
+	return append(policyTemplates(categories[:len(categories)-1]), aiPolicyTemplates(ai.ID)...)
+}
+
+func TestTemplatesAreUniqueAndCategorized(t *testing.T) {
+	names := make(map[string]bool)
+	for _, template := range allTemplates() {
+		if names[template.Name] {
+			t.Errorf("%s is seeded twice; seeding matches templates by name", template.Name)
+		}
+		names[template.Name] = true
+
+		if template.CategoryID == "" || template.PolicyType == "" {
+			t.Errorf("%s has no category or policy type", template.Name)
+		}
+	}
+
+	categories := make(map[string]bool)
+	for _, category := range Categories() {
+		if categories[category.Name] {
+			t.Errorf("category %s is seeded twice", category.Name)
+		}
+		categories[category.Name] = true
+	}
+}
+
+func TestTemplatesCompile(t *testing.T) {
+	for _, template := range allTemplates() {
+		if _, err := ast.CompileModules(map[string]string{"template.rego": template.RegoTemplate}); err != nil {
+			t.Errorf("%s: %v", template.Name, err)
+		}
+	}
+}
+
+func TestTemplateJSONFields(t *testing.T) {
+	for _, template := range allTemplates() {
+		var config map[string]interface{}
+		if err := json.Unmarshal([]byte(template.DefaultConfig), &config); err != nil {
+			t.Errorf("%s: DefaultConfig isn't a JSON object: %v", template.Name, err)
+		}
+		for field, value := range map[string]string{
+			"CloudProviders":       template.CloudProviders,
+			"Tags":                 template.Tags,
+			"RequiredPermissions":  template.RequiredPermissions,
+			"ComplianceFrameworks": template.ComplianceFrameworks,
+		} {
+			var list []string
+			if value != "" && json.Unmarshal([]byte(value), &list) != nil {
+				t.Errorf("%s: %s isn't a JSON string array: %s", template.Name, field, value)
+			}
+		}
+	}
+}
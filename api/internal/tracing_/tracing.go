@@ -0,0 +1,53 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// Header carries a request's correlation ID in and out of the API
+const Header = "X-Request-ID"
+
+// maxIDLength bounds correlation IDs accepted from clients
+const maxIDLength = 128
+
+type contextKey struct{}
+
+// NewID returns a random correlation ID
+func NewID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// ValidID reports whether a client-supplied ID is safe to log and store: non-empty, at most
+// 128 characters, and only letters, digits, dashes, underscores, dots and colons
+func ValidID(id string) bool {
+	if id == "" || len(id) > maxIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-' || r == '_' || r == '.' || r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// WithID returns a context carrying the correlation ID
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the correlation ID carried by ctx, or ""
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
@@ -0,0 +1,49 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestNewID(t *testing.T) {
+	a, b := NewID(), NewID()
+	if len(a) != 32 || !ValidID(a) {
+		t.Errorf("got %q, want 32 hex characters", a)
+	}
+	if a == b {
+		t.Error("IDs should be random")
+	}
+}
+
+func TestValidID(t *testing.T) {
+	tests := map[string]bool{
+		"5f0c6a2e-8a4b-4c1d-9e7f-1a2b3c4d5e6f": true,
+		"req_123.retry:2":                      true,
+		strings.Repeat("a", 128):               true,
+		strings.Repeat("a", 129):               false,
+		"":                                     false,
+		"id with spaces":                       false,
+		"id\nforged-log-line":                  false,
+		"<script>":                             false,
+	}
+	for id, want := range tests {
+		if got := ValidID(id); got != want {
+			t.Errorf("ValidID(%q) = %v, want %v", id, got, want)
+		}
+	}
+}
+
+func TestContextID(t *testing.T) {
+	ctx := WithID(context.Background(), "req-1")
+	if got := FromContext(ctx); got != "req-1" {
+		t.Errorf("got %q, want req-1", got)
+	}
+	if got := FromContext(context.Background()); got != "" {
+		t.Errorf("a context without an ID should give none, got %q", got)
+	}
+	var nilCtx context.Context
+	if got := FromContext(nilCtx); got != "" {
+		t.Errorf("a nil context should give no ID, got %q", got)
+	}
+}
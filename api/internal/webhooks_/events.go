@@ -0,0 +1,213 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+
+	"gorm.io/gorm"
+)
+
+// Webhook event types
+const (
+	EventPolicyViolation      = "policy_violation"
+	EventProviderConnected    = "provider_connected"
+	EventProviderDisconnected = "provider_disconnected"
+	EventRemediationPaused    = "remediation_paused"  // The remediation circuit breaker paused enforcement
+	EventViolationEscalated   = "violation_escalated" // A violation stayed pending past its policy's SLA
+	EventDigest               = "digest"              // Scheduled spend and violation summary
+)
+
+// DefaultEvents are delivered to webhooks without an event filter. Escalations are opt-in so
+// they can be routed to a different channel than the original violation, and so are digests.
+var DefaultEvents = []string{EventPolicyViolation, EventRemediationPaused}
+
+var knownEvents = map[string]bool{
+	EventPolicyViolation:      true,
+	EventProviderConnected:    true,
+	EventProviderDisconnected: true,
+	EventRemediationPaused:    true,
+	EventViolationEscalated:   true,
+	EventDigest:               true,
+}
+
+// ValidEvent reports whether event is a webhook event type that can be subscribed to
+func ValidEvent(event string) bool {
+	return knownEvents[event]
+}
+
+// Subscribed reports whether a webhook's event filter includes event
+func Subscribed(webhook models.Webhook, event string) bool {
+	var events []string
+	if webhook.Events == "" || json.Unmarshal([]byte(webhook.Events), &events) != nil || len(events) == 0 {
+		events = DefaultEvents
+	}
+	for _, subscribed := range events {
+		if subscribed == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is a non-violation notification, such as a cloud provider being connected
+type Event struct {
+	Type    string
+	Title   string
+	Message string
+	Fields  []EventField           // Shown in order in chat messages
+	Data    map[string]interface{} // Sent as-is in generic JSON payloads
+
+	// RequestID is the correlation ID of the API request or enforcement run that raised it
+	RequestID string
+}
+
+// EventField is one labelled value of an Event
+type EventField struct {
+	Name  string
+	Value string
+}
+
+// NotifyEvent delivers an event to the organization's enabled webhooks subscribed to its type
+func NotifyEvent(db *gorm.DB, orgID string, event Event) {
+	var hooks []models.Webhook
+	if err := db.Where("organization_id = ? AND enabled = ?", orgID, true).Find(&hooks).Error; err != nil {
+		fmt.Printf("Error fetching webhooks: %v\n", err)
+		return
+	}
+
+	now := time.Now()
+	// Events are not deduplicated; each one is a distinct occurrence
+	dedupKey := fmt.Sprintf("%s:%s:%d", orgID, event.Type, now.UnixNano())
+
+	for _, webhook := range hooks {
+		if !Subscribed(webhook, event.Type) {
+			continue
+		}
+		payload := FormatEventPayload(webhook.Type, event, now)
+		if _, err := Deliver(db, webhook, dedupKey, "", payload); err != nil {
+			fmt.Printf("Error sending %s webhook to %s: %v\n", event.Type, webhook.URL, err)
+		}
+	}
+}
+
+// FormatEventPayload renders an event for a Slack, Discord or Teams webhook, or as generic JSON
+func FormatEventPayload(webhookType string, event Event, at time.Time) []byte {
+	timestamp := at.Format(time.RFC3339)
+
+	var payload interface{}
+	switch webhookType {
+	case "slack":
+		fields := make([]map[string]interface{}, 0, len(event.Fields))
+		for _, field := range event.Fields {
+			fields = append(fields, map[string]interface{}{
+				"type": "mrkdwn",
+				"text": fmt.Sprintf("*%s:*\n%s", field.Name, field.Value),
+			})
+		}
+		blocks := []map[string]interface{}{
+			{
+				"type": "header",
+				"text": map[string]interface{}{
+					"type": "plain_text",
+					"text": event.Title,
+				},
+			},
+			{
+				"type": "section",
+				"text": map[string]interface{}{
+					"type": "mrkdwn",
+					"text": event.Message,
+				},
+				"fields": fields,
+			},
+		}
+		if event.RequestID != "" {
+			blocks = append(blocks, map[string]interface{}{
+				"type": "context",
+				"elements": []map[string]interface{}{
+					{
+						"type": "mrkdwn",
+						"text": fmt.Sprintf("Request ID: %s", event.RequestID),
+					},
+				},
+			})
+		}
+		payload = map[string]interface{}{
+			"text":   event.Title,
+			"blocks": blocks,
+		}
+
+	case "discord":
+		fields := make([]map[string]interface{}, 0, len(event.Fields))
+		for _, field := range event.Fields {
+			fields = append(fields, map[string]interface{}{
+				"name":   field.Name,
+				"value":  field.Value,
+				"inline": true,
+			})
+		}
+		embed := map[string]interface{}{
+			"title":       event.Title,
+			"description": event.Message,
+			"fields":      fields,
+			"timestamp":   timestamp,
+		}
+		if event.RequestID != "" {
+			embed["footer"] = map[string]interface{}{
+				"text": fmt.Sprintf("Request ID: %s", event.RequestID),
+			}
+		}
+		payload = map[string]interface{}{
+			"embeds": []map[string]interface{}{embed},
+		}
+
+	case "teams":
+		facts := make([]map[string]interface{}, 0, len(event.Fields)+2)
+		for _, field := range event.Fields {
+			facts = append(facts, map[string]interface{}{
+				"name":  field.Name,
+				"value": field.Value,
+			})
+		}
+		if event.RequestID != "" {
+			facts = append(facts, map[string]interface{}{
+				"name":  "Request ID",
+				"value": event.RequestID,
+			})
+		}
+		facts = append(facts, map[string]interface{}{
+			"name":  "Timestamp",
+			"value": timestamp,
+		})
+		payload = map[string]interface{}{
+			"@type":    "MessageCard",
+			"@context": "https://schema.org/extensions",
+			"summary":  event.Title,
+			"sections": []map[string]interface{}{
+				{
+					"activityTitle":    event.Title,
+					"activitySubtitle": event.Message,
+					"facts":            facts,
+				},
+			},
+		}
+
+	default:
+		generic := map[string]interface{}{
+			"type":      event.Type,
+			"message":   event.Message,
+			"data":      event.Data,
+			"timestamp": timestamp,
+		}
+		if event.RequestID != "" {
+			generic["requestId"] = event.RequestID
+		}
+		payload = generic
+	}
+
+	jsonData, _ := json.Marshal(payload)
+	return jsonData
+}
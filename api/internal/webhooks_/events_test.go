@@ -0,0 +1,139 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+)
+
+func TestSubscribed(t *testing.T) {
+	defaults := models.Webhook{}
+	if !Subscribed(defaults, EventPolicyViolation) || !Subscribed(defaults, EventRemediationPaused) {
+		t.Error("a webhook without a filter should get the default events")
+	}
+	if Subscribed(defaults, EventProviderConnected) || Subscribed(defaults, EventDigest) {
+		t.Error("provider events and digests are opt-in")
+	}
+
+	filtered := models.Webhook{Events: `["provider_connected"]`}
+	if !Subscribed(filtered, EventProviderConnected) || Subscribed(filtered, EventPolicyViolation) {
+		t.Error("a filter should replace the defaults")
+	}
+
+	if !Subscribed(models.Webhook{Events: "not json"}, EventPolicyViolation) {
+		t.Error("an unreadable filter should fall back to the defaults")
+	}
+}
+
+func TestValidEvent(t *testing.T) {
+	if !ValidEvent(EventProviderDisconnected) || ValidEvent("provider_updated") {
+		t.Error("only known event types can be subscribed to")
+	}
+}
+
+func TestFormatEventPayloadGeneric(t *testing.T) {
+	event := Event{
+		Type:      EventProviderConnected,
+		Message:   "Cloud provider 'prod' (aws) was connected",
+		Data:      map[string]interface{}{"providerId": "p1"},
+		RequestID: "req-1",
+	}
+	at := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	var payload map[string]interface{}
+	if err := json.Unmarshal(FormatEventPayload("generic", event, at), &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload["type"] != EventProviderConnected || payload["timestamp"] != "2026-03-02T09:00:00Z" || payload["requestId"] != "req-1" {
+		t.Errorf("got %v", payload)
+	}
+	if data, _ := payload["data"].(map[string]interface{}); data["providerId"] != "p1" {
+		t.Errorf("data = %v", payload["data"])
+	}
+}
+
+func TestFormatEventPayloadChat(t *testing.T) {
+	event := Event{
+		Title:  "Cloud Provider Connected",
+		Fields: []EventField{{Name: "Provider", Value: "prod"}},
+	}
+	at := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	var discord struct {
+		Embeds []struct {
+			Title  string
+			Fields []struct{ Name, Value string }
+		}
+	}
+	if err := json.Unmarshal(FormatEventPayload("discord", event, at), &discord); err != nil {
+		t.Fatal(err)
+	}
+	if len(discord.Embeds) != 1 || discord.Embeds[0].Title != event.Title || discord.Embeds[0].Fields[0].Value != "prod" {
+		t.Errorf("got %+v", discord)
+	}
+
+	var teams struct {
+		Sections []struct {
+			Facts []struct{ Name, Value string }
+		}
+	}
+	if err := json.Unmarshal(FormatEventPayload("teams", event, at), &teams); err != nil {
+		t.Fatal(err)
+	}
+	// Teams cards get the timestamp as a final fact
+	if facts := teams.Sections[0].Facts; len(facts) != 2 || facts[1].Name != "Timestamp" {
+		t.Errorf("got facts %+v", facts)
+	}
+}
+
+func TestFormatEventPayloadRequestID(t *testing.T) {
+	event := Event{Title: "Cloud Provider Connected", RequestID: "req-1"}
+	at := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	var slack struct {
+		Blocks []struct {
+			Type     string
+			Elements []struct{ Text string }
+		}
+	}
+	if err := json.Unmarshal(FormatEventPayload("slack", event, at), &slack); err != nil {
+		t.Fatal(err)
+	}
+	last := slack.Blocks[len(slack.Blocks)-1]
+	if last.Type != "context" || len(last.Elements) != 1 || last.Elements[0].Text != "Request ID: req-1" {
+		t.Errorf("slack should end with a request ID context block, got %+v", slack.Blocks)
+	}
+
+	var discord struct {
+		Embeds []struct {
+			Footer struct{ Text string }
+		}
+	}
+	if err := json.Unmarshal(FormatEventPayload("discord", event, at), &discord); err != nil {
+		t.Fatal(err)
+	}
+	if discord.Embeds[0].Footer.Text != "Request ID: req-1" {
+		t.Errorf("discord footer = %q", discord.Embeds[0].Footer.Text)
+	}
+
+	var teams struct {
+		Sections []struct {
+			Facts []struct{ Name, Value string }
+		}
+	}
+	if err := json.Unmarshal(FormatEventPayload("teams", event, at), &teams); err != nil {
+		t.Fatal(err)
+	}
+	if facts := teams.Sections[0].Facts; len(facts) != 2 || facts[0].Name != "Request ID" || facts[0].Value != "req-1" {
+		t.Errorf("got facts %+v", facts)
+	}
+
+	// Events raised outside a request have no ID to show
+	event.RequestID = ""
+	if payload := string(FormatEventPayload("slack", event, at)); strings.Contains(payload, "Request ID") {
+		t.Errorf("slack payload without an ID mentions one: %s", payload)
+	}
+}
@@ -0,0 +1,89 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var defaultSeverityEmoji = map[string]string{
+	"low":      "⚠️",
+	"medium":   "🔶",
+	"high":     "🔴",
+	"critical": "🚨",
+}
+
+var defaultSeverityColors = map[string]int{
+	"low":      0xFFFF00, // Yellow
+	"medium":   0xFFA500, // Orange
+	"high":     0xFF0000, // Red
+	"critical": 0x8B0000, // Dark Red
+}
+
+// SeverityStyles are an organization's overrides for how each severity is rendered in
+// Slack, Discord and Teams messages. Severities without an override use the defaults.
+type SeverityStyles struct {
+	Emoji  map[string]string `json:"emoji,omitempty"`
+	Colors map[string]string `json:"colors,omitempty"` // Hex, e.g. "#FF0000"
+}
+
+// ParseSeverityStyles decodes an organization's stored styles. An empty value yields the defaults.
+func ParseSeverityStyles(raw string) (SeverityStyles, error) {
+	var styles SeverityStyles
+	if raw == "" {
+		return styles, nil
+	}
+	if err := json.Unmarshal([]byte(raw), &styles); err != nil {
+		return SeverityStyles{}, fmt.Errorf("invalid severity styles: %w", err)
+	}
+	for severity, color := range styles.Colors {
+		if _, err := parseHexColor(color); err != nil {
+			return SeverityStyles{}, fmt.Errorf("invalid color for severity %q: %w", severity, err)
+		}
+	}
+	return styles, nil
+}
+
+// EmojiFor returns the emoji for a severity
+func (s SeverityStyles) EmojiFor(severity string) string {
+	if emoji := s.Emoji[severity]; emoji != "" {
+		return emoji
+	}
+	if emoji := defaultSeverityEmoji[severity]; emoji != "" {
+		return emoji
+	}
+	return "⚠️"
+}
+
+// ColorFor returns the color for a severity as a 24-bit RGB value
+func (s SeverityStyles) ColorFor(severity string) int {
+	if color, ok := s.CustomColorFor(severity); ok {
+		return color
+	}
+	if color, ok := defaultSeverityColors[severity]; ok {
+		return color
+	}
+	return defaultSeverityColors["low"]
+}
+
+// CustomColorFor returns the organization's color override for a severity, if any
+func (s SeverityStyles) CustomColorFor(severity string) (int, bool) {
+	color, err := parseHexColor(s.Colors[severity])
+	if err != nil {
+		return 0, false
+	}
+	return color, true
+}
+
+func parseHexColor(value string) (int, error) {
+	hex := strings.TrimPrefix(value, "#")
+	if len(hex) != 6 {
+		return 0, fmt.Errorf("expected a 6-digit hex color, got %q", value)
+	}
+	color, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("expected a 6-digit hex color, got %q", value)
+	}
+	return int(color), nil
+}
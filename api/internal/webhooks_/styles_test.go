@@ -0,0 +1,55 @@
+package webhooks
+
+import "testing"
+
+func TestParseSeverityStyles(t *testing.T) {
+	styles, err := ParseSeverityStyles(`{"emoji": {"high": ":fire:"}, "colors": {"high": "#00ff00", "low": "123456"}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := styles.EmojiFor("high"); got != ":fire:" {
+		t.Errorf("EmojiFor(high) = %q, want the override", got)
+	}
+	if got := styles.EmojiFor("critical"); got != "🚨" {
+		t.Errorf("EmojiFor(critical) = %q, want the default", got)
+	}
+	if got := styles.ColorFor("high"); got != 0x00FF00 {
+		t.Errorf("ColorFor(high) = %#x, want the override", got)
+	}
+	if got := styles.ColorFor("low"); got != 0x123456 {
+		t.Errorf("ColorFor(low) = %#x, want the override without #", got)
+	}
+	if got := styles.ColorFor("medium"); got != 0xFFA500 {
+		t.Errorf("ColorFor(medium) = %#x, want the default", got)
+	}
+	if _, ok := styles.CustomColorFor("medium"); ok {
+		t.Error("medium has no override")
+	}
+}
+
+func TestParseSeverityStylesDefaults(t *testing.T) {
+	styles, err := ParseSeverityStyles("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := styles.EmojiFor("unknown"); got != "⚠️" {
+		t.Errorf("EmojiFor(unknown) = %q", got)
+	}
+	if got := styles.ColorFor("unknown"); got != 0xFFFF00 {
+		t.Errorf("ColorFor(unknown) = %#x, want the low color", got)
+	}
+}
+
+func TestParseSeverityStylesRejectsInvalidColors(t *testing.T) {
+	for _, raw := range []string{
+		`{"colors": {"high": "red"}}`,
+		`{"colors": {"high": "#FFF"}}`,
+		`{"colors": {"high": "#GGGGGG"}}`,
+		`{"emoji": "🔥"}`,
+	} {
+		if _, err := ParseSeverityStyles(raw); err == nil {
+			t.Errorf("%s should be rejected", raw)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package webhooks
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Webhook types. Generic webhooks receive plain JSON payloads.
+const (
+	TypeSlack   = "slack"
+	TypeDiscord = "discord"
+	TypeTeams   = "teams"
+	TypeGeneric = "generic"
+)
+
+// typeHosts are the host suffixes each chat service delivers incoming webhooks from. Generic
+// webhooks may use any host.
+var typeHosts = map[string][]string{
+	TypeSlack:   {"hooks.slack.com"},
+	TypeDiscord: {"discord.com", "discordapp.com"},
+	TypeTeams:   {"webhook.office.com", "outlook.office.com", "logic.azure.com", "environment.api.powerplatform.com"},
+	TypeGeneric: nil,
+}
+
+// Validate checks that a webhook's type is supported and its URL is an absolute https URL on a
+// host the type's service uses
+func Validate(webhookType, rawURL string) error {
+	hosts, ok := typeHosts[webhookType]
+	if !ok {
+		return fmt.Errorf("unsupported webhook type %q (expected slack, discord, teams or generic)", webhookType)
+	}
+
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return fmt.Errorf("webhook url must be an absolute URL")
+	}
+	if parsed.Scheme != "https" {
+		return fmt.Errorf("webhook url must use https")
+	}
+
+	if hosts == nil {
+		return nil
+	}
+	host := strings.ToLower(parsed.Hostname())
+	for _, allowed := range hosts {
+		if host == allowed || strings.HasSuffix(host, "."+allowed) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s webhook url must be on %s", webhookType, strings.Join(hosts, " or "))
+}
+
+// reservedHeaders are set by the sender and can't be overridden by custom headers
+var reservedHeaders = map[string]bool{
+	"Content-Type":      true,
+	"Content-Length":    true,
+	"Host":              true,
+	"Transfer-Encoding": true,
+	"Connection":        true,
+}
+
+// ValidateHeaders checks that custom webhook headers have valid names, don't override the
+// headers the sender sets, and have values without line breaks
+func ValidateHeaders(headers map[string]string) error {
+	for name, value := range headers {
+		if !validHeaderName(name) {
+			return fmt.Errorf("invalid header name %q", name)
+		}
+		if reservedHeaders[http.CanonicalHeaderKey(name)] {
+			return fmt.Errorf("header %s can't be overridden", http.CanonicalHeaderKey(name))
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("header %s has a line break in its value", name)
+		}
+	}
+	return nil
+}
+
+// validHeaderName reports whether name is an HTTP token (RFC 9110)
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case strings.ContainsRune("!#$%&'*+-.^_`|~", r):
+		default:
+			return false
+		}
+	}
+	return true
+}
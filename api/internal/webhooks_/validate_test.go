@@ -0,0 +1,50 @@
+package webhooks
+
+import "testing"
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		webhookType, url string
+		ok               bool
+	}{
+		{TypeSlack, "https://hooks.slack.com/services/T000/B000/XXXX", true},
+		{TypeDiscord, "https://discord.com/api/webhooks/1/abc", true},
+		{TypeDiscord, "https://canary.discordapp.com/api/webhooks/1/abc", true},
+		{TypeTeams, "https://contoso.webhook.office.com/webhookb2/abc", true},
+		{TypeGeneric, "https://hooks.example.com/finops", true},
+		{TypeSlack, "https://hooks.slack.com.evil.example/services/T000", false},
+		{TypeSlack, "https://evilhooks.slack.com.example/services", false},
+		{TypeSlack, "http://hooks.slack.com/services/T000/B000/XXXX", false},
+		{TypeGeneric, "http://hooks.example.com/finops", false},
+		{TypeGeneric, "hooks.example.com/finops", false},
+		{TypeGeneric, "", false},
+		{"pagerduty", "https://events.pagerduty.com/v2/enqueue", false},
+	}
+
+	for _, tt := range tests {
+		err := Validate(tt.webhookType, tt.url)
+		if (err == nil) != tt.ok {
+			t.Errorf("Validate(%q, %q) = %v, want ok=%v", tt.webhookType, tt.url, err, tt.ok)
+		}
+	}
+}
+
+func TestValidateHeaders(t *testing.T) {
+	valid := map[string]string{"Authorization": "Bearer abc", "X-Api-Key": "k", "x-trace_id~1": ""}
+	if err := ValidateHeaders(valid); err != nil {
+		t.Errorf("ValidateHeaders(%v) = %v", valid, err)
+	}
+
+	for _, headers := range []map[string]string{
+		{"": "x"},
+		{"X Api Key": "k"},
+		{"X-Api-Key:": "k"},
+		{"content-type": "text/plain"},
+		{"Host": "evil.example"},
+		{"X-Api-Key": "k\r\nX-Injected: 1"},
+	} {
+		if err := ValidateHeaders(headers); err == nil {
+			t.Errorf("ValidateHeaders(%q) should fail", headers)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package webhooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MaxConsecutiveFailures is how many deliveries in a row may fail before a webhook is
+// disabled, so a dead URL isn't retried forever
+const MaxConsecutiveFailures = 10
+
+// staleClaimAfter is how long a delivery may stay pending before another sender takes it over,
+// e.g. after the worker that claimed it died mid-send. Well above SendRequest's timeout.
+const staleClaimAfter = 5 * time.Minute
+
+// ErrAlreadyDelivered is returned when a notification with the same dedup key was already sent
+var ErrAlreadyDelivered = errors.New("webhook notification already delivered")
+
+// DedupKey identifies a violation notification so it is sent at most once per day,
+// even if the worker restarts and re-detects the same violation
+func DedupKey(orgID, policyID, resourceID string, at time.Time) string {
+	return fmt.Sprintf("%s:%s:%s:%s", orgID, policyID, resourceID, at.UTC().Format("2006-01-02"))
+}
+
+// Deliver sends a payload to a webhook and records the attempt as a WebhookDelivery.
+// Deliveries are unique per webhook and dedup key; a key that was already sent, or is being
+// sent, returns ErrAlreadyDelivered. A previously failed one is retried, as is one left pending
+// for longer than staleClaimAfter by a sender that never recorded the outcome.
+func Deliver(db *gorm.DB, webhook models.Webhook, dedupKey, violationID string, payload []byte) (*models.WebhookDelivery, error) {
+	now := time.Now()
+	delivery := models.WebhookDelivery{
+		OrganizationID: webhook.OrganizationID,
+		WebhookID:      webhook.ID,
+		ViolationID:    violationID,
+		DedupKey:       dedupKey,
+		Payload:        string(payload),
+		Status:         "pending",
+		ClaimedAt:      &now,
+	}
+
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&delivery)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to record webhook delivery: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		// Another run already claimed this notification. Take it over if that attempt failed or
+		// went stale, conditionally so that only one sender retries it.
+		claim := db.Model(&models.WebhookDelivery{}).
+			Where("webhook_id = ? AND dedup_key = ? AND (status = ? OR (status = ? AND COALESCE(claimed_at, created_at) < ?))",
+				webhook.ID, dedupKey, "failed", "pending", now.Add(-staleClaimAfter)).
+			Updates(map[string]interface{}{"status": "pending", "claimed_at": now, "payload": string(payload)})
+		if claim.Error != nil {
+			return nil, fmt.Errorf("failed to claim webhook delivery: %w", claim.Error)
+		}
+
+		var existing models.WebhookDelivery
+		if err := db.Where("webhook_id = ? AND dedup_key = ?", webhook.ID, dedupKey).First(&existing).Error; err != nil {
+			return nil, fmt.Errorf("failed to load webhook delivery: %w", err)
+		}
+		if claim.RowsAffected == 0 {
+			return &existing, ErrAlreadyDelivered
+		}
+		delivery = existing
+	}
+
+	return &delivery, send(db, webhook, &delivery)
+}
+
+// Replay re-sends a recorded delivery's payload as a new delivery
+func Replay(db *gorm.DB, webhook models.Webhook, original models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	now := time.Now()
+	delivery := models.WebhookDelivery{
+		OrganizationID: original.OrganizationID,
+		WebhookID:      webhook.ID,
+		ViolationID:    original.ViolationID,
+		DedupKey:       fmt.Sprintf("%s:replay:%d", original.DedupKey, now.UnixNano()),
+		Payload:        original.Payload,
+		Status:         "pending",
+		ReplayOf:       original.ID,
+		ClaimedAt:      &now,
+	}
+
+	if err := db.Create(&delivery).Error; err != nil {
+		return nil, fmt.Errorf("failed to record webhook delivery: %w", err)
+	}
+
+	return &delivery, send(db, webhook, &delivery)
+}
+
+// send posts the delivery's payload and saves the outcome on the delivery record
+func send(db *gorm.DB, webhook models.Webhook, delivery *models.WebhookDelivery) error {
+	statusCode, err := SendRequest(webhook.URL, Headers(webhook), []byte(delivery.Payload))
+
+	now := time.Now()
+	delivery.Attempts++
+	delivery.StatusCode = statusCode
+	delivery.LastAttemptAt = &now
+	if err != nil {
+		delivery.Status = "failed"
+		delivery.Error = err.Error()
+	} else {
+		delivery.Status = "sent"
+		delivery.Error = ""
+	}
+
+	if saveErr := db.Save(delivery).Error; saveErr != nil {
+		fmt.Printf("Error saving webhook delivery %s: %v\n", delivery.ID, saveErr)
+	}
+
+	recordOutcome(db, webhook, err)
+
+	return err
+}
+
+// recordOutcome tracks consecutive failures on the webhook and disables it once they reach
+// MaxConsecutiveFailures. The counter is updated in SQL so concurrent sends don't lose counts.
+func recordOutcome(db *gorm.DB, webhook models.Webhook, sendErr error) {
+	if sendErr == nil {
+		if err := db.Model(&models.Webhook{}).
+			Where("id = ? AND consecutive_failures > 0", webhook.ID).
+			Update("consecutive_failures", 0).Error; err != nil {
+			fmt.Printf("Error resetting failures for webhook %s: %v\n", webhook.ID, err)
+		}
+		return
+	}
+
+	if err := db.Model(&models.Webhook{}).
+		Where("id = ?", webhook.ID).
+		Update("consecutive_failures", gorm.Expr("consecutive_failures + 1")).Error; err != nil {
+		fmt.Printf("Error counting failure for webhook %s: %v\n", webhook.ID, err)
+		return
+	}
+
+	now := time.Now()
+	reason := fmt.Sprintf("Disabled after %d consecutive failed deliveries; last error: %v", MaxConsecutiveFailures, sendErr)
+	result := db.Model(&models.Webhook{}).
+		Where("id = ? AND enabled = ? AND consecutive_failures >= ?", webhook.ID, true, MaxConsecutiveFailures).
+		Updates(map[string]interface{}{
+			"enabled":         false,
+			"disabled_reason": reason,
+			"disabled_at":     now,
+		})
+	if result.Error != nil {
+		fmt.Printf("Error disabling webhook %s: %v\n", webhook.ID, result.Error)
+		return
+	}
+	if result.RowsAffected == 0 {
+		return
+	}
+
+	fmt.Printf("Disabled webhook %s: %s\n", webhook.URL, reason)
+	db.Create(&models.ActivityLog{
+		OrganizationID: webhook.OrganizationID,
+		Type:           "webhook_disabled",
+		Message:        fmt.Sprintf("Webhook %s was disabled after repeated delivery failures", webhook.URL),
+		Metadata:       fmt.Sprintf(`{"webhookId":"%s"}`, webhook.ID),
+	})
+}
+
+// Headers returns a webhook's custom request headers
+func Headers(webhook models.Webhook) map[string]string {
+	var headers map[string]string
+	if webhook.Headers != "" {
+		if err := json.Unmarshal([]byte(webhook.Headers), &headers); err != nil {
+			fmt.Printf("Error parsing headers of webhook %s: %v\n", webhook.ID, err)
+		}
+	}
+	return headers
+}
+
+// SendRequest posts a JSON payload to url with the given extra headers and returns the
+// response status code
+func SendRequest(url string, headers map[string]string, payload []byte) (int, error) {
+	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook returned status code: %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
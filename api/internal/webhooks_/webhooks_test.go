@@ -0,0 +1,175 @@
+package webhooks
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// dryRunDB returns a database that builds Postgres statements without running them. Writes
+// skip the default transaction, which would need a connection.
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=finopsbridge sslmode=disable"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// recordUpdates collects the UPDATE statements run on db, with their parameters inlined
+func recordUpdates(t *testing.T, db *gorm.DB) *[]string {
+	t.Helper()
+	var statements []string
+	err := db.Callback().Update().After("gorm:update").Register("test:record_updates", func(tx *gorm.DB) {
+		statements = append(statements, tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &statements
+}
+
+func TestDedupKeyIsPerDay(t *testing.T) {
+	morning := time.Date(2026, 3, 2, 1, 0, 0, 0, time.UTC)
+	evening := time.Date(2026, 3, 2, 23, 0, 0, 0, time.UTC)
+	if DedupKey("org", "p", "i-1", morning) != DedupKey("org", "p", "i-1", evening) {
+		t.Error("the same violation should share a key within a day")
+	}
+	if DedupKey("org", "p", "i-1", morning) == DedupKey("org", "p", "i-1", morning.AddDate(0, 0, 1)) {
+		t.Error("the next day should get a new key")
+	}
+	if DedupKey("org", "p", "i-1", morning) == DedupKey("org", "p", "i-2", morning) {
+		t.Error("different resources should get different keys")
+	}
+
+	// The day is taken in UTC, wherever the worker runs
+	tokyo := time.FixedZone("JST", 9*60*60)
+	if got, want := DedupKey("org", "p", "i-1", time.Date(2026, 3, 3, 8, 0, 0, 0, tokyo)), DedupKey("org", "p", "i-1", evening); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestReplaySendsAsNewDelivery(t *testing.T) {
+	var received string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+	}))
+	defer server.Close()
+
+	original := models.WebhookDelivery{
+		ID:             "d1",
+		OrganizationID: "org",
+		ViolationID:    "v1",
+		DedupKey:       "org:p:i-1:2026-03-02",
+		Payload:        `{"event":"violation"}`,
+		Status:         "sent",
+	}
+	delivery, err := Replay(dryRunDB(t), models.Webhook{ID: "w1", URL: server.URL}, original)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if received != original.Payload {
+		t.Errorf("replay sent %q, want the original payload", received)
+	}
+	if delivery.ReplayOf != "d1" || delivery.Status != "sent" || delivery.Attempts != 1 {
+		t.Errorf("got %+v", delivery)
+	}
+	if !strings.HasPrefix(delivery.DedupKey, original.DedupKey+":replay:") {
+		t.Errorf("a replay needs its own dedup key, got %q", delivery.DedupKey)
+	}
+}
+
+func TestReplayRecordsFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	delivery, err := Replay(dryRunDB(t), models.Webhook{ID: "w1", URL: server.URL}, models.WebhookDelivery{ID: "d1", Payload: "{}"})
+	if err == nil {
+		t.Fatal("a 502 should fail the delivery")
+	}
+	if delivery == nil {
+		t.Fatalf("no delivery recorded: %v", err)
+	}
+	if delivery.Status != "failed" || delivery.StatusCode != http.StatusBadGateway || delivery.Error == "" {
+		t.Errorf("got %+v", delivery)
+	}
+}
+
+func TestRecordOutcomeCountsFailures(t *testing.T) {
+	db := dryRunDB(t)
+	updates := recordUpdates(t, db)
+
+	recordOutcome(db, models.Webhook{ID: "w1"}, errors.New("webhook returned status code: 500"))
+
+	if len(*updates) != 2 {
+		t.Fatalf("got %d updates, want the failure count and the disable check:\n%s", len(*updates), strings.Join(*updates, "\n"))
+	}
+	if !strings.Contains((*updates)[0], `"consecutive_failures"=consecutive_failures + 1`) {
+		t.Errorf("the failure count should be incremented in SQL:\n%s", (*updates)[0])
+	}
+	disable := (*updates)[1]
+	for _, want := range []string{`"enabled"=false`, "consecutive_failures >= 10", "status code: 500"} {
+		if !strings.Contains(disable, want) {
+			t.Errorf("disable update is missing %q:\n%s", want, disable)
+		}
+	}
+}
+
+func TestRecordOutcomeResetsFailures(t *testing.T) {
+	db := dryRunDB(t)
+	updates := recordUpdates(t, db)
+
+	recordOutcome(db, models.Webhook{ID: "w1"}, nil)
+
+	if len(*updates) != 1 || !strings.Contains((*updates)[0], `"consecutive_failures"=0`) {
+		t.Errorf("a success should only reset the failure count, got:\n%s", strings.Join(*updates, "\n"))
+	}
+}
+
+func TestSendRequestHeaders(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header
+	}))
+	defer server.Close()
+
+	status, err := SendRequest(server.URL, map[string]string{"Authorization": "Bearer abc", "Content-Type": "text/plain"}, []byte(`{}`))
+	if err != nil || status != http.StatusOK {
+		t.Fatalf("got %d, %v", status, err)
+	}
+	if received.Get("Authorization") != "Bearer abc" {
+		t.Errorf("Authorization = %q, want the custom header", received.Get("Authorization"))
+	}
+	if received.Get("Content-Type") != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json whatever the custom headers say", received.Get("Content-Type"))
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	if got := Headers(models.Webhook{Headers: `{"X-Api-Key":"k"}`}); len(got) != 1 || got["X-Api-Key"] != "k" {
+		t.Errorf("got %v", got)
+	}
+	for _, headers := range []string{"", "not json"} {
+		if got := Headers(models.Webhook{ID: "w1", Headers: headers}); len(got) != 0 {
+			t.Errorf("Headers(%q) = %v, want none", headers, got)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+	policygen "finopsbridge/api/internal/policygen_"
+
+	"gorm.io/gorm"
+)
+
+// isAIPolicy reports whether a policy is evaluated against the organization's TokenUsage
+// rather than cloud billing input
+func isAIPolicy(policy models.Policy) bool {
+	return policygen.IsAIPolicyType(policy.Type)
+}
+
+// evaluateAIPolicies evaluates an organization's AI-typed policies against its token usage and
+// GPU metrics. Idle GPU instances are only stopped when the org hasn't paused enforcement.
+func (w *EnforcementWorker) evaluateAIPolicies(ctx context.Context, orgID string, policies []models.Policy, now time.Time, paused bool) {
+	for _, policy := range policies {
+		if policy.OrganizationID != orgID || !isAIPolicy(policy) {
+			continue
+		}
+		w.guardPolicy(policy, "", func() error {
+			w.evaluateAIPolicy(ctx, orgID, policy, now, paused)
+			return nil
+		})
+	}
+}
+
+// evaluateAIPolicy evaluates one AI-typed policy against the organization's token usage or
+// GPU metrics
+func (w *EnforcementWorker) evaluateAIPolicy(ctx context.Context, orgID string, policy models.Policy, now time.Time, paused bool) {
+	var policyConfig map[string]interface{}
+	if err := json.Unmarshal([]byte(policy.Config), &policyConfig); err != nil {
+		policyConfig = make(map[string]interface{})
+	}
+
+	var inputs []aiPolicyInput
+	var err error
+	switch policy.Type {
+	case "llm_token_budget":
+		inputs, err = w.tokenBudgetInputs(orgID, policyConfig, now)
+	case "token_length_limits":
+		inputs, err = w.tokenLengthInputs(orgID, policyConfig, now)
+	case "token_cost_anomaly":
+		inputs, err = w.tokenCostAnomalyInputs(policy, policyConfig, now)
+	case "gpu_idle_detection", "gpu_time_slicing":
+		inputs, err = w.gpuInputs(orgID, policyConfig, now)
+	}
+	if err != nil {
+		fmt.Printf("Error assembling AI usage input for policy %s: %v\n", policy.Name, err)
+		return
+	}
+
+	for _, in := range inputs {
+		allowed, result, err := w.OPA.EvaluatePolicy(policy.ID, policy.Config, in.input)
+		w.logDecision(policy, in.resourceID, in.input, allowed, err)
+		if err != nil {
+			fmt.Printf("Error evaluating policy %s: %v\n", policy.Name, err)
+			return
+		}
+		if allowed {
+			continue
+		}
+		violation, created := w.handleAIViolation(policy, in, result)
+		if in.gpu == nil || policy.Type != "gpu_idle_detection" || !autoStopGPU(policyConfig) {
+			continue
+		}
+		// A new violation stops the instance now or after the grace period; an existing one
+		// once its grace period runs out, handing the claim back if the stop doesn't happen
+		if created {
+			if !w.deferRemediation(policy, &violation) {
+				w.stopIdleGPU(ctx, policy, policyConfig, *in.gpu, violation, paused)
+			}
+		} else if deadline, claimed := w.claimExpiredGrace(&violation); claimed {
+			if !w.stopIdleGPU(ctx, policy, policyConfig, *in.gpu, violation, paused) {
+				w.releaseGrace(&violation, deadline)
+			}
+		}
+	}
+}
+
+// aiPolicyInput is one OPA input document and the resource a violation is recorded against.
+// resourceType and cloudProvider default to token usage, which is tracked org-wide; inputs that
+// set resourceType are tracked per resource. gpu is set for GPU instance inputs.
+type aiPolicyInput struct {
+	resourceID    string
+	resourceType  string
+	cloudProvider string
+	input         map[string]interface{}
+	gpu           *gpuInstance
+}
+
+// tokenBudgetInputs totals the org's tokens for today and month-to-date, limited to the
+// config's providers when set. Days of the month already rolled up by the retention worker are
+// read from their rollups.
+func (w *EnforcementWorker) tokenBudgetInputs(orgID string, policyConfig map[string]interface{}, now time.Time) ([]aiPolicyInput, error) {
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	providers := stringList(policyConfig["providers"])
+	scope := func(model interface{}) *gorm.DB {
+		query := w.DB.Model(model).Where("organization_id = ?", orgID)
+		if len(providers) > 0 {
+			query = query.Where("provider IN ?", providers)
+		}
+		return query
+	}
+
+	var daily, monthly, rolledUp int64
+	if err := scope(&models.TokenUsage{}).Where("timestamp >= ?", dayStart).
+		Select("COALESCE(SUM(total_tokens), 0)").Scan(&daily).Error; err != nil {
+		return nil, err
+	}
+	if err := scope(&models.TokenUsage{}).Where("timestamp >= ?", monthStart).
+		Select("COALESCE(SUM(total_tokens), 0)").Scan(&monthly).Error; err != nil {
+		return nil, err
+	}
+	if err := scope(&models.UsageRollup{}).Where("source = ? AND period_start >= ?", models.RollupSourceTokenUsage, monthStart).
+		Select("COALESCE(SUM(total_tokens), 0)").Scan(&rolledUp).Error; err != nil {
+		return nil, err
+	}
+	monthly += rolledUp
+
+	return []aiPolicyInput{{
+		resourceID: orgID,
+		input: map[string]interface{}{
+			"tokenUsage": map[string]interface{}{
+				"daily":   daily,
+				"monthly": monthly,
+			},
+			"config": policyConfig,
+		},
+	}}, nil
+}
+
+// tokenLengthInputs builds one input per model and endpoint used in the last 24 hours, with the
+// largest per-request input and output token counts seen
+func (w *EnforcementWorker) tokenLengthInputs(orgID string, policyConfig map[string]interface{}, now time.Time) ([]aiPolicyInput, error) {
+	var rows []struct {
+		ModelName       string
+		Endpoint        string
+		InputTokens     int64
+		MaxOutputTokens int64
+	}
+	if err := w.DB.Model(&models.TokenUsage{}).
+		Select(`model_name, endpoint,
+			MAX(input_tokens / GREATEST(request_count, 1)) AS input_tokens,
+			MAX(output_tokens / GREATEST(request_count, 1)) AS max_output_tokens`).
+		Where("organization_id = ? AND timestamp >= ?", orgID, now.Add(-24*time.Hour)).
+		Group("model_name, endpoint").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	inputs := make([]aiPolicyInput, 0, len(rows))
+	for _, row := range rows {
+		inputs = append(inputs, aiPolicyInput{
+			resourceID: row.ModelName,
+			input: map[string]interface{}{
+				"request": map[string]interface{}{
+					"inputTokens":     row.InputTokens,
+					"maxOutputTokens": row.MaxOutputTokens,
+					"endpoint":        row.Endpoint,
+				},
+				"model": map[string]interface{}{
+					"name": row.ModelName,
+				},
+				"config": policyConfig,
+			},
+		})
+	}
+	return inputs, nil
+}
+
+// handleAIViolation records a violation of an AI policy and fires webhooks. Token usage
+// policies have at most one pending violation at a time, like cloud violations; GPU policies
+// have one per instance and token cost anomaly policies one per model. It returns the violation
+// and whether it was newly created.
+func (w *EnforcementWorker) handleAIViolation(policy models.Policy, in aiPolicyInput, result map[string]interface{}) (models.PolicyViolation, bool) {
+	fmt.Printf("AI policy violation detected: %s\n", policy.Name)
+
+	message := "Policy violation detected"
+	if msg, ok := result["msg"].(string); ok && msg != "" {
+		message = msg
+	}
+
+	resourceType, cloudProvider := in.resourceType, in.cloudProvider
+	if resourceType == "" {
+		resourceType, cloudProvider = "token_usage", "ai"
+	}
+
+	var existingViolation models.PolicyViolation
+	query := w.DB.Where("policy_id = ? AND status = ?", policy.ID, "pending")
+	if in.resourceType != "" {
+		query = query.Where("resource_id = ?", in.resourceID)
+	}
+	err := query.First(&existingViolation).Error
+	if err != gorm.ErrRecordNotFound {
+		return existingViolation, false
+	}
+
+	violation := models.PolicyViolation{
+		PolicyID:      policy.ID,
+		ResourceID:    in.resourceID,
+		ResourceType:  resourceType,
+		CloudProvider: cloudProvider,
+		Message:       message,
+		Severity:      "high",
+		Status:        "pending",
+		RequestID:     w.runID,
+	}
+
+	if !w.createViolation(&violation) {
+		return violation, false
+	}
+
+	w.DB.Create(&models.ActivityLog{
+		OrganizationID: policy.OrganizationID,
+		RequestID:      w.runID,
+		Type:           "policy_violation",
+		Message:        fmt.Sprintf("Policy '%s' violation: %s", policy.Name, message),
+		Metadata:       fmt.Sprintf(`{"policyId":"%s","violationId":"%s"}`, policy.ID, violation.ID),
+	})
+
+	w.sendWebhooks(policy.OrganizationID, violation)
+	return violation, true
+}
+
+// stringList converts a JSON array from policy config into strings
+func stringList(value interface{}) []string {
+	items, _ := value.([]interface{})
+	var result []string
+	for _, item := range items {
+		if s, ok := item.(string); ok && s != "" {
+			result = append(result, s)
+		}
+	}
+	return result
+}
@@ -0,0 +1,32 @@
+package worker
+
+import (
+	"reflect"
+	"testing"
+
+	models "finopsbridge/api/internal/models_"
+)
+
+func TestIsAIPolicy(t *testing.T) {
+	for policyType, want := range map[string]bool{
+		"llm_token_budget":    true,
+		"token_length_limits": true,
+		"gpu_idle_detection":  true,
+		"max_spend":           false,
+		"":                    false,
+	} {
+		if got := isAIPolicy(models.Policy{Type: policyType}); got != want {
+			t.Errorf("isAIPolicy(%q) = %v, want %v", policyType, got, want)
+		}
+	}
+}
+
+func TestStringList(t *testing.T) {
+	got := stringList([]interface{}{"openai", 3, "", "anthropic"})
+	if want := []string{"openai", "anthropic"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if got := stringList("openai"); got != nil {
+		t.Errorf("a non-list should give no providers, got %v", got)
+	}
+}
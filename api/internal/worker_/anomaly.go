@@ -0,0 +1,206 @@
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	cloud "finopsbridge/api/internal/cloud_"
+	models "finopsbridge/api/internal/models_"
+
+	"gorm.io/gorm"
+)
+
+// ResourceTypeOrganization marks violations of org-wide checks rather than of one provider
+const ResourceTypeOrganization = "organization"
+
+// anomalyBaselineDays reads an anomaly_detection policy's weeklyBaseline, the number of days
+// averaged for the baseline, defaulting to 7
+func anomalyBaselineDays(policyConfig map[string]interface{}) int {
+	if days, ok := policyConfig["weeklyBaseline"].(float64); ok && days >= 1 {
+		return int(days)
+	}
+	return 7
+}
+
+// spendSnapshots loads the snapshots needed for a baseline of the given days, for one provider
+// or, with an empty providerID, for every provider of the organization
+func (p PolicyInputs) spendSnapshots(orgID, providerID string, days int, now time.Time) ([]models.SpendSnapshot, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	// One extra day so the oldest baseline day has a previous snapshot to diff against. Later
+	// snapshots only exist when backtesting a day in the past.
+	since := today.AddDate(0, 0, -(days + 1))
+	query := p.DB.Where("organization_id = ? AND date >= ? AND date < ?", orgID, since, today.AddDate(0, 0, 1))
+	if providerID != "" {
+		query = query.Where("provider_id = ?", providerID)
+	}
+	var snapshots []models.SpendSnapshot
+	err := query.Order("date").Find(&snapshots).Error
+	return snapshots, err
+}
+
+// dailySpend converts month-to-date snapshots into spend per day, keyed by date and summed
+// across providers. A day's spend is its month-to-date minus the provider's previous day in the
+// same month; the first of the month is its month-to-date. Days without the previous snapshot
+// are skipped for that provider. convert maps each snapshot's amount to a common currency.
+func dailySpend(snapshots []models.SpendSnapshot, convert func(amount float64, currency string) float64) map[string]float64 {
+	byProvider := make(map[string][]models.SpendSnapshot)
+	for _, snapshot := range snapshots {
+		byProvider[snapshot.ProviderID] = append(byProvider[snapshot.ProviderID], snapshot)
+	}
+
+	daily := make(map[string]float64)
+	for _, series := range byProvider {
+		sort.Slice(series, func(i, j int) bool { return series[i].Date.Before(series[j].Date) })
+		for i, snapshot := range series {
+			day := snapshot.Date.UTC()
+			spend := convert(snapshot.MonthToDateSpend, snapshot.Currency)
+			if day.Day() != 1 {
+				if i == 0 || !series[i-1].Date.UTC().Equal(day.AddDate(0, 0, -1)) {
+					continue
+				}
+				spend -= convert(series[i-1].MonthToDateSpend, series[i-1].Currency)
+			}
+			daily[day.Format("2006-01-02")] += spend
+		}
+	}
+	return daily
+}
+
+// spendBaseline returns the most recent day's spend and the average of up to `days` days before
+// it. It returns false when there is no such day or no baseline to compare against.
+func spendBaseline(daily map[string]float64, days int) (current, average float64, ok bool) {
+	dates := make([]string, 0, len(daily))
+	for date := range daily {
+		dates = append(dates, date)
+	}
+	if len(dates) < 2 {
+		return 0, 0, false
+	}
+	sort.Strings(dates)
+
+	latest := dates[len(dates)-1]
+	baseline := dates[:len(dates)-1]
+	if len(baseline) > days {
+		baseline = baseline[len(baseline)-days:]
+	}
+
+	total := 0.0
+	for _, date := range baseline {
+		total += daily[date]
+	}
+	average = total / float64(len(baseline))
+	if average <= 0 {
+		return 0, 0, false
+	}
+	return daily[latest], average, true
+}
+
+// addSpendBaseline sets dailySpend and averageSpend on an anomaly_detection policy's input,
+// for one provider or, with an empty providerID, for the whole organization
+func (p PolicyInputs) addSpendBaseline(input map[string]interface{}, policy models.Policy, providerID string, now time.Time) bool {
+	var policyConfig map[string]interface{}
+	json.Unmarshal([]byte(policy.Config), &policyConfig)
+	days := anomalyBaselineDays(policyConfig)
+
+	snapshots, err := p.spendSnapshots(policy.OrganizationID, providerID, days, now)
+	if err != nil {
+		fmt.Printf("Error fetching spend snapshots for policy %s: %v\n", policy.Name, err)
+		return false
+	}
+
+	convert := func(amount float64, currency string) float64 {
+		// A single provider is compared against itself, so its own currency is fine
+		if providerID != "" {
+			return amount
+		}
+		converted, _ := cloud.ConvertCurrency(amount, currency, p.Config)
+		return converted
+	}
+
+	current, average, ok := spendBaseline(dailySpend(snapshots, convert), days)
+	if !ok {
+		return false
+	}
+	input["dailySpend"] = current
+	input["averageSpend"] = average
+	return true
+}
+
+// evaluateOrgAnomalies evaluates anomaly_detection policies against each organization's
+// combined daily spend, which catches spikes spread thinly across many providers. Violations
+// are notification only and tracked separately from per-provider ones.
+func (w *EnforcementWorker) evaluateOrgAnomalies(policies []models.Policy, providerCounts map[string]int, now time.Time) {
+	for _, policy := range policies {
+		// A single provider's check already covers its whole organization
+		if policy.Type != "anomaly_detection" || providerCounts[policy.OrganizationID] < 2 {
+			continue
+		}
+
+		w.guardPolicy(policy, "", func() error {
+			input := map[string]interface{}{
+				"scope":          ResourceTypeOrganization,
+				"provider_count": providerCounts[policy.OrganizationID],
+				"currency":       w.Config.ReportingCurrency,
+			}
+			if !w.policyInputs().addSpendBaseline(input, policy, "", now) {
+				return nil
+			}
+
+			allowed, result, err := w.OPA.EvaluatePolicy(policy.ID, policy.Config, input)
+			w.logDecision(policy, policy.OrganizationID, input, allowed, err)
+			if err != nil {
+				fmt.Printf("Error evaluating policy %s: %v\n", policy.Name, err)
+				return nil
+			}
+			if !allowed {
+				w.handleOrgViolation(policy, result, violationSeverity(policy, input, result))
+			}
+			return nil
+		})
+	}
+}
+
+// handleOrgViolation records an org-scope violation and fires webhooks. An organization has at
+// most one pending org-scope violation per policy.
+func (w *EnforcementWorker) handleOrgViolation(policy models.Policy, result map[string]interface{}, severity string) {
+	fmt.Printf("Organization-wide policy violation detected: %s\n", policy.Name)
+
+	message := "Policy violation detected"
+	if msg, ok := result["msg"].(string); ok && msg != "" {
+		message = msg
+	}
+
+	var existingViolation models.PolicyViolation
+	err := w.DB.Where("policy_id = ? AND resource_type = ? AND status = ?", policy.ID, ResourceTypeOrganization, "pending").
+		First(&existingViolation).Error
+	if err != gorm.ErrRecordNotFound {
+		return
+	}
+
+	violation := models.PolicyViolation{
+		PolicyID:      policy.ID,
+		ResourceID:    policy.OrganizationID,
+		ResourceType:  ResourceTypeOrganization,
+		CloudProvider: "all",
+		Message:       "Organization-wide: " + message,
+		Severity:      severity,
+		Status:        "pending",
+		RequestID:     w.runID,
+	}
+
+	if !w.createViolation(&violation) {
+		return
+	}
+
+	w.DB.Create(&models.ActivityLog{
+		OrganizationID: policy.OrganizationID,
+		RequestID:      w.runID,
+		Type:           "policy_violation",
+		Message:        fmt.Sprintf("Policy '%s' violation: %s", policy.Name, violation.Message),
+		Metadata:       fmt.Sprintf(`{"policyId":"%s","violationId":"%s","scope":"%s"}`, policy.ID, violation.ID, ResourceTypeOrganization),
+	})
+
+	w.sendWebhooks(policy.OrganizationID, violation)
+}
@@ -0,0 +1,91 @@
+package worker
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+)
+
+func snapshot(providerID, date string, spend float64, currency string) models.SpendSnapshot {
+	day, _ := time.Parse("2006-01-02", date)
+	return models.SpendSnapshot{ProviderID: providerID, Date: day, MonthToDateSpend: spend, Currency: currency}
+}
+
+func TestAnomalyBaselineDays(t *testing.T) {
+	tests := []struct {
+		config map[string]interface{}
+		want   int
+	}{
+		{map[string]interface{}{}, 7},
+		{map[string]interface{}{"weeklyBaseline": 14.0}, 14},
+		{map[string]interface{}{"weeklyBaseline": 0.0}, 7},
+		{map[string]interface{}{"weeklyBaseline": "14"}, 7},
+	}
+	for _, tt := range tests {
+		if got := anomalyBaselineDays(tt.config); got != tt.want {
+			t.Errorf("anomalyBaselineDays(%v) = %d, want %d", tt.config, got, tt.want)
+		}
+	}
+}
+
+func TestDailySpendAcrossProviders(t *testing.T) {
+	snapshots := []models.SpendSnapshot{
+		snapshot("aws", "2026-03-02", 30, "USD"),
+		snapshot("aws", "2026-03-01", 10, "USD"),
+		snapshot("aws", "2026-03-03", 45, "USD"),
+		// A gap: 03-05 has no previous day to diff against
+		snapshot("aws", "2026-03-05", 80, "USD"),
+		snapshot("azure", "2026-03-02", 20, "EUR"),
+		snapshot("azure", "2026-03-03", 25, "EUR"),
+	}
+	eurToUSD := func(amount float64, currency string) float64 {
+		if currency == "EUR" {
+			return amount * 2
+		}
+		return amount
+	}
+
+	got := dailySpend(snapshots, eurToUSD)
+	want := map[string]float64{
+		"2026-03-01": 10,
+		"2026-03-02": 20,
+		"2026-03-03": 15 + 10,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestDailySpendMonthRollover(t *testing.T) {
+	snapshots := []models.SpendSnapshot{
+		snapshot("aws", "2026-02-28", 900, "USD"),
+		snapshot("aws", "2026-03-01", 12, "USD"),
+	}
+	got := dailySpend(snapshots, func(amount float64, _ string) float64 { return amount })
+	if got["2026-03-01"] != 12 {
+		t.Errorf("the first of the month should be its month-to-date, got %v", got)
+	}
+}
+
+func TestSpendBaseline(t *testing.T) {
+	daily := map[string]float64{
+		"2026-03-01": 100,
+		"2026-03-02": 10,
+		"2026-03-03": 20,
+		"2026-03-04": 90,
+	}
+
+	current, average, ok := spendBaseline(daily, 2)
+	if !ok || current != 90 || average != 15 {
+		t.Errorf("got %v, %v, %v; want the latest day and the 2 days before it", current, average, ok)
+	}
+
+	if _, _, ok := spendBaseline(map[string]float64{"2026-03-04": 90}, 7); ok {
+		t.Error("a single day has no baseline")
+	}
+	if _, _, ok := spendBaseline(map[string]float64{"2026-03-03": 0, "2026-03-04": 90}, 7); ok {
+		t.Error("a zero baseline can't be compared against")
+	}
+}
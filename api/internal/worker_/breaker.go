@@ -0,0 +1,122 @@
+package worker
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+	webhooks "finopsbridge/api/internal/webhooks_"
+)
+
+// CircuitBreakerActor is recorded as EnforcementPausedBy when the breaker pauses an org
+const CircuitBreakerActor = "remediation_circuit_breaker"
+
+// remediationBreaker counts remediation actions per organization in a sliding window, so a
+// loop of stopping resources that something else restarts pauses enforcement instead of
+// thrashing indefinitely
+type remediationBreaker struct {
+	mu      sync.Mutex
+	actions map[string][]time.Time
+	tripped map[string]bool // Orgs paused during the current run
+}
+
+func newRemediationBreaker() *remediationBreaker {
+	return &remediationBreaker{
+		actions: make(map[string][]time.Time),
+		tripped: make(map[string]bool),
+	}
+}
+
+// record adds n actions for an org and reports whether the org just exceeded maxActions
+// within window. Tripping clears the org's window, so a manual resume starts a fresh count.
+func (b *remediationBreaker) record(orgID string, n int, now time.Time, maxActions int, window time.Duration) bool {
+	if n <= 0 || maxActions <= 0 || window <= 0 {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	cutoff := now.Add(-window)
+	kept := b.actions[orgID][:0]
+	for _, at := range b.actions[orgID] {
+		if at.After(cutoff) {
+			kept = append(kept, at)
+		}
+	}
+	for i := 0; i < n; i++ {
+		kept = append(kept, now)
+	}
+
+	if len(kept) > maxActions {
+		delete(b.actions, orgID)
+		b.tripped[orgID] = true
+		return true
+	}
+	b.actions[orgID] = kept
+	return false
+}
+
+// isTripped reports whether the breaker paused an org since the last run started. Later runs
+// see the pause through the organization's EnforcementPaused flag.
+func (b *remediationBreaker) isTripped(orgID string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.tripped[orgID]
+}
+
+func (b *remediationBreaker) startRun() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tripped = make(map[string]bool)
+}
+
+// recordRemediationActions counts actions taken for an org and pauses its enforcement when
+// the circuit breaker trips. Enforcement stays paused until resumed manually.
+func (w *EnforcementWorker) recordRemediationActions(orgID string, n int) {
+	maxActions := w.Config.RemediationBreakerMaxActions
+	window := time.Duration(w.Config.RemediationBreakerWindowMinutes) * time.Minute
+	if !w.breaker.record(orgID, n, w.Clock.Now(), maxActions, window) {
+		return
+	}
+
+	fmt.Printf("Remediation circuit breaker tripped for org %s: more than %d actions in %v\n", orgID, maxActions, window)
+
+	now := w.Clock.Now()
+	if err := w.DB.Model(&models.Organization{}).
+		Where("clerk_org_id = ?", orgID).
+		Updates(map[string]interface{}{
+			"enforcement_paused":    true,
+			"enforcement_paused_at": now,
+			"enforcement_paused_by": CircuitBreakerActor,
+		}).Error; err != nil {
+		fmt.Printf("Error pausing enforcement for org %s: %v\n", orgID, err)
+	}
+
+	message := fmt.Sprintf("Enforcement was paused after more than %d remediation actions in %d minutes; resume it once the cause is fixed",
+		maxActions, w.Config.RemediationBreakerWindowMinutes)
+	w.DB.Create(&models.ActivityLog{
+		OrganizationID: orgID,
+		RequestID:      w.runID,
+		Type:           "enforcement_paused",
+		Message:        message,
+		Metadata:       fmt.Sprintf(`{"userId":"%s","maxActions":%d,"windowMinutes":%d}`, CircuitBreakerActor, maxActions, w.Config.RemediationBreakerWindowMinutes),
+	})
+
+	webhooks.NotifyEvent(w.DB, orgID, webhooks.Event{
+		Type:      webhooks.EventRemediationPaused,
+		Title:     "Remediation Paused",
+		Message:   message,
+		RequestID: w.runID,
+		Fields: []webhooks.EventField{
+			{Name: "Max Actions", Value: strconv.Itoa(maxActions)},
+			{Name: "Window (minutes)", Value: strconv.Itoa(w.Config.RemediationBreakerWindowMinutes)},
+		},
+		Data: map[string]interface{}{
+			"maxActions":    maxActions,
+			"windowMinutes": w.Config.RemediationBreakerWindowMinutes,
+		},
+	})
+}
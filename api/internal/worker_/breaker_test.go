@@ -0,0 +1,61 @@
+package worker
+
+import (
+	"testing"
+	"time"
+
+	config "finopsbridge/api/internal/config_"
+)
+
+func TestRemediationBreakerSlidingWindow(t *testing.T) {
+	b := newRemediationBreaker()
+	start := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	if b.record("org", 5, start, 10, time.Hour) {
+		t.Fatal("5 actions shouldn't trip a limit of 10")
+	}
+	if b.record("org", 5, start.Add(30*time.Minute), 10, time.Hour) {
+		t.Fatal("10 actions are within the limit")
+	}
+	// The first 5 have left the window
+	if b.record("org", 5, start.Add(61*time.Minute), 10, time.Hour) {
+		t.Fatal("actions older than the window shouldn't count")
+	}
+	if !b.record("org", 1, start.Add(62*time.Minute), 10, time.Hour) {
+		t.Fatal("11 actions within an hour should trip the breaker")
+	}
+	if !b.isTripped("org") || b.isTripped("other") {
+		t.Error("only the org over the limit should be tripped")
+	}
+
+	// Tripping starts a fresh count, and a new run clears the tripped orgs
+	if b.record("org", 1, start.Add(63*time.Minute), 10, time.Hour) {
+		t.Error("the count should restart after tripping")
+	}
+	b.startRun()
+	if b.isTripped("org") {
+		t.Error("a new run should clear tripped orgs")
+	}
+}
+
+func TestRemediationBreakerDisabled(t *testing.T) {
+	b := newRemediationBreaker()
+	now := time.Now()
+	if b.record("org", 100, now, 0, time.Hour) || b.record("org", 100, now, 10, 0) {
+		t.Error("a zero limit or window disables the breaker")
+	}
+}
+
+func TestRecordRemediationActionsTripsBreaker(t *testing.T) {
+	w := testWorker(t, &config.Config{RemediationBreakerMaxActions: 3, RemediationBreakerWindowMinutes: 60},
+		time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC))
+
+	w.recordRemediationActions("org", 3)
+	if w.breaker.isTripped("org") {
+		t.Fatal("3 actions are within the limit")
+	}
+	w.recordRemediationActions("org", 1)
+	if !w.breaker.isTripped("org") {
+		t.Error("a 4th action should trip the breaker")
+	}
+}
@@ -0,0 +1,261 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	cloud "finopsbridge/api/internal/cloud_"
+	models "finopsbridge/api/internal/models_"
+
+	"gorm.io/gorm"
+)
+
+// ResourceTypeBudget marks violations of a budget_hierarchy policy, recorded against a Budget
+const ResourceTypeBudget = "budget"
+
+// isBudgetPolicy reports whether a policy checks the organization's budget hierarchy rather
+// than each provider's billing input
+func isBudgetPolicy(policy models.Policy) bool {
+	return policy.Type == "budget_hierarchy"
+}
+
+// budgetSpend is a budget with the month-to-date spend attributed to it
+type budgetSpend struct {
+	Budget models.Budget
+	Path   string // Names from the root down, e.g. "Acme / Platform / Checkout"
+	Spend  float64
+}
+
+// rollUpBudgets attributes month-to-date spend to every budget of an organization. A budget
+// with a tag gets the spend carrying that tag value; one without a tag sums its children, and
+// a root without a tag or children gets totalSpend. Budgets whose parent is missing are
+// treated as roots. Results are sorted by path.
+func rollUpBudgets(budgets []models.Budget, totalSpend float64, tagged func(key, value string) float64) []budgetSpend {
+	byID := make(map[string]models.Budget, len(budgets))
+	children := make(map[string][]string)
+	for _, budget := range budgets {
+		byID[budget.ID] = budget
+	}
+	for _, budget := range budgets {
+		if _, ok := byID[budget.ParentID]; ok {
+			children[budget.ParentID] = append(children[budget.ParentID], budget.ID)
+		}
+	}
+
+	spends := make(map[string]float64, len(budgets))
+	visiting := make(map[string]bool)
+	var spendOf func(id string) float64
+	spendOf = func(id string) float64 {
+		if spend, ok := spends[id]; ok {
+			return spend
+		}
+		// A cycle can't be created through the API, but don't recurse forever on bad data
+		if visiting[id] {
+			return 0
+		}
+		visiting[id] = true
+		defer delete(visiting, id)
+
+		budget := byID[id]
+		var spend float64
+		switch {
+		case budget.TagKey != "":
+			spend = tagged(budget.TagKey, budget.TagValue)
+		case len(children[id]) > 0:
+			for _, child := range children[id] {
+				spend += spendOf(child)
+			}
+		case !hasParent(budget, byID):
+			spend = totalSpend
+		}
+		spends[id] = spend
+		return spend
+	}
+
+	result := make([]budgetSpend, 0, len(budgets))
+	for _, budget := range budgets {
+		result = append(result, budgetSpend{
+			Budget: budget,
+			Path:   budgetPath(budget, byID),
+			Spend:  spendOf(budget.ID),
+		})
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Path < result[j].Path
+	})
+	return result
+}
+
+func hasParent(budget models.Budget, byID map[string]models.Budget) bool {
+	_, ok := byID[budget.ParentID]
+	return ok
+}
+
+// budgetPath joins the names of a budget and its ancestors from the root down
+func budgetPath(budget models.Budget, byID map[string]models.Budget) string {
+	names := []string{budget.Name}
+	seen := map[string]bool{budget.ID: true}
+	for parent, ok := byID[budget.ParentID]; ok && !seen[parent.ID]; parent, ok = byID[parent.ParentID] {
+		seen[parent.ID] = true
+		names = append([]string{parent.Name}, names...)
+	}
+	return strings.Join(names, " / ")
+}
+
+// evaluateBudgets checks every budget of each organization with a budget_hierarchy policy.
+// Spend is attributed from the providers' tag or label breakdowns, converted to the reporting
+// currency; provider types without tag breakdowns only count towards untagged root budgets.
+func (w *EnforcementWorker) evaluateBudgets(ctx context.Context, policies []models.Policy, now time.Time) {
+	orgPolicies := make(map[string][]models.Policy)
+	for _, policy := range policies {
+		if isBudgetPolicy(policy) {
+			orgPolicies[policy.OrganizationID] = append(orgPolicies[policy.OrganizationID], policy)
+		}
+	}
+
+	for orgID, budgetPolicies := range orgPolicies {
+		var budgets []models.Budget
+		if err := w.DB.Where("organization_id = ?", orgID).Find(&budgets).Error; err != nil {
+			fmt.Printf("Error fetching budgets for organization %s: %v\n", orgID, err)
+			continue
+		}
+		if len(budgets) == 0 {
+			continue
+		}
+
+		var providers []models.CloudProvider
+		if err := w.DB.Where("organization_id = ? AND status = ?", orgID, "connected").Find(&providers).Error; err != nil {
+			fmt.Printf("Error fetching cloud providers for organization %s: %v\n", orgID, err)
+			continue
+		}
+
+		// Today's snapshots hold this run's month-to-date spend along with its currency
+		var snapshots []models.SpendSnapshot
+		today := time.Date(now.UTC().Year(), now.UTC().Month(), now.UTC().Day(), 0, 0, 0, 0, time.UTC)
+		if err := w.DB.Where("organization_id = ? AND date = ?", orgID, today).Find(&snapshots).Error; err != nil {
+			fmt.Printf("Error fetching spend snapshots for organization %s: %v\n", orgID, err)
+			continue
+		}
+		totalSpend := 0.0
+		for _, snapshot := range snapshots {
+			converted, _ := cloud.ConvertCurrency(snapshot.MonthToDateSpend, snapshot.Currency, w.Config)
+			totalSpend += converted
+		}
+
+		// One breakdown per tag key and provider, shared by every budget using the key
+		breakdowns := make(map[string]map[string]float64)
+		tagged := func(key, value string) float64 {
+			values, ok := breakdowns[key]
+			if !ok {
+				values = w.tagSpend(ctx, providers, key)
+				breakdowns[key] = values
+			}
+			return values[value]
+		}
+
+		for _, entry := range rollUpBudgets(budgets, totalSpend, tagged) {
+			w.DB.Model(&models.Budget{}).Where("id = ?", entry.Budget.ID).Updates(map[string]interface{}{
+				"current_spend": entry.Spend,
+				"evaluated_at":  now,
+			})
+
+			input := map[string]interface{}{
+				"budget": map[string]interface{}{
+					"id":     entry.Budget.ID,
+					"name":   entry.Budget.Name,
+					"level":  entry.Budget.Level,
+					"path":   entry.Path,
+					"amount": entry.Budget.Amount,
+					"spend":  entry.Spend,
+				},
+				"currency": w.Config.ReportingCurrency,
+			}
+
+			for _, policy := range budgetPolicies {
+				w.guardPolicy(policy, "", func() error {
+					allowed, result, err := w.OPA.EvaluatePolicy(policy.ID, policy.Config, input)
+					w.logDecision(policy, entry.Budget.ID, input, allowed, err)
+					if err != nil {
+						fmt.Printf("Error evaluating policy %s: %v\n", policy.Name, err)
+						return nil
+					}
+					if !allowed {
+						w.handleBudgetViolation(policy, entry, result, violationSeverity(policy, input, result))
+					}
+					return nil
+				})
+			}
+		}
+	}
+}
+
+// tagSpend sums month-to-date spend per value of a tag or label key across providers, in the
+// reporting currency
+func (w *EnforcementWorker) tagSpend(ctx context.Context, providers []models.CloudProvider, key string) map[string]float64 {
+	values := make(map[string]float64)
+	for _, provider := range providers {
+		groupBy, ok := cloud.TagGroupBy(provider.Type, key)
+		if !ok {
+			continue
+		}
+		items, err := cloud.FetchCostBreakdown(ctx, provider, w.Config, groupBy)
+		if err != nil {
+			fmt.Printf("Error fetching %s breakdown for provider %s: %v\n", groupBy, provider.Name, err)
+			continue
+		}
+		for _, item := range items {
+			converted, _ := cloud.ConvertCurrency(item.Cost, item.Currency, w.Config)
+			values[item.Key] += converted
+		}
+	}
+	return values
+}
+
+// handleBudgetViolation records a breached budget and fires webhooks. Each budget has at most
+// one pending violation per policy.
+func (w *EnforcementWorker) handleBudgetViolation(policy models.Policy, entry budgetSpend, result map[string]interface{}, severity string) {
+	fmt.Printf("Budget violation detected: %s (%s)\n", entry.Path, entry.Budget.Level)
+
+	message := fmt.Sprintf("%s budget '%s' is over its limit", entry.Budget.Level, entry.Path)
+	if msg, ok := result["msg"].(string); ok && msg != "" {
+		message = msg
+	}
+
+	var existingViolation models.PolicyViolation
+	err := w.DB.Where("policy_id = ? AND resource_type = ? AND resource_id = ? AND status = ?",
+		policy.ID, ResourceTypeBudget, entry.Budget.ID, "pending").
+		First(&existingViolation).Error
+	if err != gorm.ErrRecordNotFound {
+		return
+	}
+
+	violation := models.PolicyViolation{
+		PolicyID:      policy.ID,
+		ResourceID:    entry.Budget.ID,
+		ResourceType:  ResourceTypeBudget,
+		CloudProvider: "all",
+		Message:       message,
+		Severity:      severity,
+		Status:        "pending",
+		RequestID:     w.runID,
+		BudgetLevel:   entry.Budget.Level,
+	}
+
+	if !w.createViolation(&violation) {
+		return
+	}
+
+	w.DB.Create(&models.ActivityLog{
+		OrganizationID: policy.OrganizationID,
+		RequestID:      w.runID,
+		Type:           "policy_violation",
+		Message:        fmt.Sprintf("Policy '%s' violation: %s", policy.Name, message),
+		Metadata: fmt.Sprintf(`{"policyId":"%s","violationId":"%s","budgetId":"%s","budgetLevel":"%s"}`,
+			policy.ID, violation.ID, entry.Budget.ID, entry.Budget.Level),
+	})
+
+	w.sendWebhooks(policy.OrganizationID, violation)
+}
@@ -0,0 +1,63 @@
+package worker
+
+import (
+	"testing"
+
+	models "finopsbridge/api/internal/models_"
+)
+
+func TestRollUpBudgets(t *testing.T) {
+	budgets := []models.Budget{
+		{ID: "org", Name: "Acme", Level: "organization"},
+		{ID: "platform", ParentID: "org", Name: "Platform", Level: "team"},
+		{ID: "checkout", ParentID: "platform", Name: "Checkout", Level: "project", TagKey: "project", TagValue: "checkout"},
+		{ID: "search", ParentID: "platform", Name: "Search", Level: "project", TagKey: "project", TagValue: "search"},
+		{ID: "data", ParentID: "org", Name: "Data", Level: "team", TagKey: "team", TagValue: "data"},
+		{ID: "sandbox", Name: "Sandbox", Level: "organization"},
+		{ID: "orphan", ParentID: "deleted", Name: "Orphan", Level: "team"},
+	}
+	tagged := func(key, value string) float64 {
+		return map[string]float64{"project=checkout": 300, "project=search": 200, "team=data": 150}[key+"="+value]
+	}
+
+	got := rollUpBudgets(budgets, 1000, tagged)
+
+	want := []struct {
+		path  string
+		spend float64
+	}{
+		{"Acme", 650},
+		{"Acme / Data", 150},
+		{"Acme / Platform", 500},
+		{"Acme / Platform / Checkout", 300},
+		{"Acme / Platform / Search", 200},
+		// Untagged roots without children, including ones whose parent is gone, get the total
+		{"Orphan", 1000},
+		{"Sandbox", 1000},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d budgets, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if got[i].Path != w.path || got[i].Spend != w.spend {
+			t.Errorf("budget %d: got %s = %v, want %s = %v", i, got[i].Path, got[i].Spend, w.path, w.spend)
+		}
+	}
+}
+
+func TestRollUpBudgetsCycle(t *testing.T) {
+	budgets := []models.Budget{
+		{ID: "a", ParentID: "b", Name: "A"},
+		{ID: "b", ParentID: "a", Name: "B"},
+	}
+	got := rollUpBudgets(budgets, 100, func(string, string) float64 { return 0 })
+	if len(got) != 2 || got[0].Path != "A / B" || got[1].Path != "B / A" {
+		t.Errorf("a cycle should terminate, got %+v", got)
+	}
+}
+
+func TestIsBudgetPolicy(t *testing.T) {
+	if !isBudgetPolicy(models.Policy{Type: "budget_hierarchy"}) || isBudgetPolicy(models.Policy{Type: "max_spend"}) {
+		t.Error("only budget_hierarchy policies check the budget hierarchy")
+	}
+}
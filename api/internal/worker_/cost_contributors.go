@@ -0,0 +1,86 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	cloud "finopsbridge/api/internal/cloud_"
+	models "finopsbridge/api/internal/models_"
+)
+
+// defaultTopContributors is how many services a spend violation lists when the policy doesn't
+// set topContributors
+const defaultTopContributors = 5
+
+// spendPolicyTypes flag a provider's overall spend, so their violations say what drove it
+var spendPolicyTypes = map[string]bool{
+	"max_spend":               true,
+	"month_over_month_growth": true,
+}
+
+// topContributorCount reads a policy's topContributors, defaulting to defaultTopContributors
+func topContributorCount(policy models.Policy) int {
+	var policyConfig map[string]interface{}
+	json.Unmarshal([]byte(policy.Config), &policyConfig)
+	if n, ok := policyConfig["topContributors"].(float64); ok && n > 0 {
+		return int(n)
+	}
+	return defaultTopContributors
+}
+
+// costContributors returns the services costing the most in a provider's month-to-date spend,
+// as JSON for a spend policy's violation. It returns "" for other policy types and providers
+// without a cost breakdown.
+func (w *EnforcementWorker) costContributors(ctx context.Context, policy models.Policy, provider models.CloudProvider) string {
+	if !spendPolicyTypes[policy.Type] {
+		return ""
+	}
+
+	items, err := cloud.FetchCostBreakdown(ctx, provider, w.Config, "service")
+	if errors.Is(err, cloud.ErrBreakdownNotSupported) {
+		return ""
+	}
+	if err != nil {
+		fmt.Printf("Error fetching cost breakdown for %s: %v\n", provider.Name, err)
+		return ""
+	}
+
+	// Breakdowns are ordered by cost, highest first
+	if n := topContributorCount(policy); len(items) > n {
+		items = items[:n]
+	}
+	if len(items) == 0 {
+		return ""
+	}
+	contributorsJSON, _ := json.Marshal(items)
+	return string(contributorsJSON)
+}
+
+// contributorsSummary formats a violation's cost contributors for notifications, e.g.
+// "AmazonEC2 1200.00 USD, AmazonS3 310.50 USD", or "" when it has none
+func contributorsSummary(violation models.PolicyViolation) string {
+	if violation.CostContributors == "" {
+		return ""
+	}
+	var items []cloud.CostBreakdownItem
+	if err := json.Unmarshal([]byte(violation.CostContributors), &items); err != nil {
+		return ""
+	}
+
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		parts = append(parts, fmt.Sprintf("%s %.2f %s", item.Key, item.Cost, item.Currency))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// messageWithContributors is a violation's message followed by its top cost contributors
+func messageWithContributors(violation models.PolicyViolation) string {
+	if summary := contributorsSummary(violation); summary != "" {
+		return fmt.Sprintf("%s\nTop costs: %s", violation.Message, summary)
+	}
+	return violation.Message
+}
@@ -0,0 +1,81 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	cloud "finopsbridge/api/internal/cloud_"
+	config "finopsbridge/api/internal/config_"
+	models "finopsbridge/api/internal/models_"
+)
+
+// breakdownProvider is a stubProvider that also breaks its cost down, highest first
+type breakdownProvider struct {
+	stubProvider
+	items []cloud.CostBreakdownItem
+}
+
+func (p breakdownProvider) FetchCostBreakdown(ctx context.Context, provider models.CloudProvider, cfg *config.Config, groupBy string) ([]cloud.CostBreakdownItem, error) {
+	return p.items, nil
+}
+
+func TestTopContributorCount(t *testing.T) {
+	if got := topContributorCount(models.Policy{Config: `{"topContributors": 3}`}); got != 3 {
+		t.Errorf("got %d, want 3", got)
+	}
+	for _, config := range []string{``, `{}`, `{"topContributors": 0}`} {
+		if got := topContributorCount(models.Policy{Config: config}); got != defaultTopContributors {
+			t.Errorf("topContributorCount(%q) = %d, want the default", config, got)
+		}
+	}
+}
+
+func TestCostContributors(t *testing.T) {
+	items := []cloud.CostBreakdownItem{
+		{Key: "AmazonEC2", Cost: 1200, Currency: "USD"},
+		{Key: "AmazonS3", Cost: 310.5, Currency: "USD"},
+		{Key: "AWSLambda", Cost: 12, Currency: "USD"},
+	}
+	providerType := "breakdown-" + t.Name()
+	cloud.RegisterProvider(providerType, breakdownProvider{items: items})
+	provider := models.CloudProvider{ID: "provider-" + t.Name(), Name: "stub", Type: providerType}
+	w := testWorker(t, nil, time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC))
+
+	got := w.costContributors(context.Background(), models.Policy{Type: "max_spend", Config: `{"topContributors": 2}`}, provider)
+	var contributors []cloud.CostBreakdownItem
+	if err := json.Unmarshal([]byte(got), &contributors); err != nil {
+		t.Fatalf("contributors %q: %v", got, err)
+	}
+	if !reflect.DeepEqual(contributors, items[:2]) {
+		t.Errorf("got %+v, want the top two", contributors)
+	}
+
+	if got := w.costContributors(context.Background(), models.Policy{Type: "require_tags"}, provider); got != "" {
+		t.Errorf("a non-spend policy got contributors %q", got)
+	}
+	withoutBreakdown := stubProviderOfType(t, stubProvider{})
+	if got := w.costContributors(context.Background(), models.Policy{Type: "max_spend"}, withoutBreakdown); got != "" {
+		t.Errorf("a provider without a breakdown got contributors %q", got)
+	}
+}
+
+func TestMessageWithContributors(t *testing.T) {
+	violation := models.PolicyViolation{
+		Message:          "Monthly spend $1600 exceeds limit of $1000",
+		CostContributors: `[{"key":"AmazonEC2","cost":1200,"currency":"USD"},{"key":"AmazonS3","cost":310.5,"currency":"USD"}]`,
+	}
+	want := "Monthly spend $1600 exceeds limit of $1000\nTop costs: AmazonEC2 1200.00 USD, AmazonS3 310.50 USD"
+	if got := messageWithContributors(violation); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	for _, contributors := range []string{"", "not json"} {
+		violation.CostContributors = contributors
+		if got := messageWithContributors(violation); got != violation.Message {
+			t.Errorf("with contributors %q got %q, want the message alone", contributors, got)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package worker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	models "finopsbridge/api/internal/models_"
+)
+
+// Decisions recorded in the decision log
+const (
+	DecisionAllow = "allow"
+	DecisionDeny  = "deny"
+	DecisionError = "error"
+)
+
+// logDecision records a policy evaluation in the decision log when it is enabled
+func (w *EnforcementWorker) logDecision(policy models.Policy, resourceID string, input map[string]interface{}, allowed bool, evalErr error) {
+	if !w.Config.DecisionLogEnabled {
+		return
+	}
+
+	entry := models.DecisionLog{
+		OrganizationID: policy.OrganizationID,
+		PolicyID:       policy.ID,
+		ResourceID:     resourceID,
+		InputHash:      inputHash(input),
+		Decision:       DecisionAllow,
+	}
+	switch {
+	case evalErr != nil:
+		entry.Decision = DecisionError
+		entry.Error = evalErr.Error()
+	case !allowed:
+		entry.Decision = DecisionDeny
+	}
+
+	if err := w.DB.Create(&entry).Error; err != nil {
+		fmt.Printf("Error recording decision for policy %s: %v\n", policy.Name, err)
+	}
+}
+
+// inputHash returns the SHA-256 of an input document's JSON encoding. Map keys are encoded in
+// sorted order, so equal inputs hash the same.
+func inputHash(input map[string]interface{}) string {
+	encoded, err := json.Marshal(input)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:])
+}
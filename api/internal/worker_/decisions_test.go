@@ -0,0 +1,79 @@
+package worker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	config "finopsbridge/api/internal/config_"
+	models "finopsbridge/api/internal/models_"
+
+	"gorm.io/gorm"
+)
+
+// recordDecisions collects the decision log entries w creates
+func recordDecisions(t *testing.T, w *EnforcementWorker) *[]models.DecisionLog {
+	t.Helper()
+	var logged []models.DecisionLog
+	err := w.DB.Callback().Create().Before("gorm:create").Register("test:record_decisions", func(db *gorm.DB) {
+		if entry, ok := db.Statement.Dest.(*models.DecisionLog); ok {
+			logged = append(logged, *entry)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &logged
+}
+
+func TestLogDecision(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	w := testWorker(t, &config.Config{DecisionLogEnabled: true}, now)
+	logged := recordDecisions(t, w)
+	policy := models.Policy{ID: "p1", OrganizationID: "org", Name: "Max spend"}
+	input := map[string]interface{}{"monthly_spend": 100.0}
+
+	w.logDecision(policy, "i-1", input, true, nil)
+	w.logDecision(policy, "i-2", input, false, nil)
+	w.logDecision(policy, "i-3", input, true, errors.New("undefined function"))
+
+	if len(*logged) != 3 {
+		t.Fatalf("got %d entries, want 3", len(*logged))
+	}
+	want := []string{DecisionAllow, DecisionDeny, DecisionError}
+	for i, entry := range *logged {
+		if entry.Decision != want[i] {
+			t.Errorf("entry %d: decision %q, want %q", i, entry.Decision, want[i])
+		}
+		if entry.OrganizationID != "org" || entry.PolicyID != "p1" || entry.InputHash != inputHash(input) {
+			t.Errorf("entry %d = %+v", i, entry)
+		}
+	}
+	if (*logged)[2].Error != "undefined function" {
+		t.Errorf("error = %q, want the evaluation error", (*logged)[2].Error)
+	}
+}
+
+func TestLogDecisionDisabled(t *testing.T) {
+	w := testWorker(t, nil, time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC))
+	logged := recordDecisions(t, w)
+
+	w.logDecision(models.Policy{ID: "p1"}, "", map[string]interface{}{}, false, nil)
+	if len(*logged) != 0 {
+		t.Errorf("got %d entries with the decision log disabled", len(*logged))
+	}
+}
+
+func TestInputHash(t *testing.T) {
+	a := inputHash(map[string]interface{}{"monthly_spend": 100.0, "provider": map[string]interface{}{"type": "aws", "id": "p1"}})
+	b := inputHash(map[string]interface{}{"provider": map[string]interface{}{"id": "p1", "type": "aws"}, "monthly_spend": 100.0})
+	if a != b {
+		t.Error("equal inputs should hash the same regardless of key order")
+	}
+	if len(a) != 64 {
+		t.Errorf("got %q, want a hex SHA-256", a)
+	}
+	if inputHash(map[string]interface{}{"monthly_spend": 101.0}) == a {
+		t.Error("different inputs should hash differently")
+	}
+}
@@ -0,0 +1,307 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	cloud "finopsbridge/api/internal/cloud_"
+	config "finopsbridge/api/internal/config_"
+	models "finopsbridge/api/internal/models_"
+	webhooks "finopsbridge/api/internal/webhooks_"
+
+	"gorm.io/gorm"
+)
+
+// Digest cadences an organization can choose
+const (
+	DigestDaily   = "daily"
+	DigestWeekly  = "weekly"
+	DigestMonthly = "monthly"
+)
+
+// digestTopServices is how many of the most expensive services a digest lists
+const digestTopServices = 5
+
+// DigestWorker sends each organization a periodic summary of spend, violations,
+// remediations and savings through its webhooks
+type DigestWorker struct {
+	DB     *gorm.DB
+	Config *config.Config
+	Clock  cloud.Clock // Decides when each digest is due
+}
+
+func NewDigestWorker(db *gorm.DB, cfg *config.Config) *DigestWorker {
+	return &DigestWorker{
+		DB:     db,
+		Config: cfg,
+		Clock:  cloud.SystemClock,
+	}
+}
+
+func (w *DigestWorker) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	w.run(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.run(ctx)
+		}
+	}
+}
+
+func (w *DigestWorker) run(ctx context.Context) {
+	var orgs []models.Organization
+	if err := w.DB.Where("digest_cadence <> ?", "").Find(&orgs).Error; err != nil {
+		fmt.Printf("Error fetching organizations for digests: %v\n", err)
+		return
+	}
+
+	now := w.Clock.Now().UTC()
+	for _, org := range orgs {
+		start, due := digestPeriod(org.DigestCadence, org.DigestLastSentAt, now)
+		if !due {
+			continue
+		}
+		hooks, err := w.digestWebhooks(org)
+		if err != nil {
+			fmt.Printf("Error fetching webhooks: %v\n", err)
+			continue
+		}
+		if len(hooks) == 0 {
+			continue
+		}
+
+		digest, err := w.compileDigest(ctx, org.ClerkOrgID, start, now)
+		if err != nil {
+			fmt.Printf("Error compiling digest for org %s: %v\n", org.ClerkOrgID, err)
+			continue
+		}
+
+		// An undelivered digest stays due, so the next run sends it again
+		if w.sendDigest(org, hooks, digest, now) {
+			w.DB.Model(&models.Organization{}).Where("id = ?", org.ID).Update("digest_last_sent_at", now)
+		}
+	}
+}
+
+// digestPeriod returns the start of the period the next digest covers and whether it is due:
+// one cadence after the last digest, or immediately for the first one, which covers the
+// cadence's length up to now
+func digestPeriod(cadence string, lastSent *time.Time, now time.Time) (time.Time, bool) {
+	advance := func(t time.Time, n int) time.Time {
+		switch cadence {
+		case DigestDaily:
+			return t.AddDate(0, 0, n)
+		case DigestMonthly:
+			return t.AddDate(0, n, 0)
+		}
+		return t.AddDate(0, 0, 7*n)
+	}
+
+	if lastSent == nil {
+		return advance(now, -1), true
+	}
+	return *lastSent, !now.Before(advance(*lastSent, 1))
+}
+
+// Digest summarizes an organization's spend and enforcement over one period
+type Digest struct {
+	PeriodStart          time.Time        `json:"periodStart"`
+	PeriodEnd            time.Time        `json:"periodEnd"`
+	Currency             string           `json:"currency"`
+	MonthToDateSpend     float64          `json:"monthToDateSpend"` // As of the latest spend snapshots
+	TopServices          []DigestService  `json:"topServices"`
+	NewViolations        int64            `json:"newViolations"`
+	ViolationsBySeverity map[string]int64 `json:"violationsBySeverity"`
+	Remediations         int64            `json:"remediations"`
+	EstimatedSavings     float64          `json:"estimatedSavings"` // Monthly savings of recommendations accepted in the period
+}
+
+// DigestService is a service's month-to-date cost across providers
+type DigestService struct {
+	Service string  `json:"service"`
+	Cost    float64 `json:"cost"`
+}
+
+// compileDigest gathers an organization's digest for the period from start to end. Costs are in
+// the reporting currency; services from providers without cost breakdowns are left out.
+func (w *DigestWorker) compileDigest(ctx context.Context, orgID string, start, end time.Time) (Digest, error) {
+	digest := Digest{
+		PeriodStart:          start,
+		PeriodEnd:            end,
+		Currency:             w.Config.ReportingCurrency,
+		ViolationsBySeverity: map[string]int64{"critical": 0, "high": 0, "medium": 0, "low": 0},
+	}
+
+	// The latest snapshot of each provider this month holds its month-to-date spend
+	monthStart := time.Date(end.Year(), end.Month(), 1, 0, 0, 0, 0, end.Location())
+	var snapshots []models.SpendSnapshot
+	if err := w.DB.Raw(`SELECT DISTINCT ON (provider_id) * FROM spend_snapshots
+		WHERE organization_id = ? AND date >= ? AND date <= ?
+		ORDER BY provider_id, date DESC`, orgID, monthStart, end).
+		Find(&snapshots).Error; err != nil {
+		return digest, err
+	}
+	for _, snapshot := range snapshots {
+		converted, _ := cloud.ConvertCurrency(snapshot.MonthToDateSpend, snapshot.Currency, w.Config)
+		digest.MonthToDateSpend += converted
+	}
+
+	var providers []models.CloudProvider
+	if err := w.DB.Where("organization_id = ? AND status = ?", orgID, "connected").Find(&providers).Error; err != nil {
+		return digest, err
+	}
+	services := make(map[string]float64)
+	for _, provider := range providers {
+		items, err := cloud.FetchCostBreakdown(ctx, provider, w.Config, "service")
+		if err != nil {
+			if !errors.Is(err, cloud.ErrBreakdownNotSupported) {
+				fmt.Printf("Error fetching service breakdown for provider %s: %v\n", provider.Name, err)
+			}
+			continue
+		}
+		for _, item := range items {
+			if converted, ok := cloud.ConvertCurrency(item.Cost, item.Currency, w.Config); ok {
+				services[item.Key] += converted
+			}
+		}
+	}
+	digest.TopServices = topServices(services, digestTopServices)
+
+	var severities []struct {
+		Severity string
+		Count    int64
+	}
+	if err := w.DB.Model(&models.PolicyViolation{}).
+		Joins("JOIN policies ON policy_violations.policy_id = policies.id").
+		Where("policies.organization_id = ?", orgID).
+		Where("policy_violations.created_at >= ? AND policy_violations.created_at < ?", start, end).
+		Select("policy_violations.severity AS severity, COUNT(*) AS count").
+		Group("policy_violations.severity").
+		Find(&severities).Error; err != nil {
+		return digest, err
+	}
+	for _, row := range severities {
+		digest.ViolationsBySeverity[row.Severity] += row.Count
+		digest.NewViolations += row.Count
+	}
+
+	if err := w.DB.Model(&models.PolicyViolation{}).
+		Joins("JOIN policies ON policy_violations.policy_id = policies.id").
+		Where("policies.organization_id = ? AND policy_violations.status = ?", orgID, "remediated").
+		Where("policy_violations.remediated_at >= ? AND policy_violations.remediated_at < ?", start, end).
+		Count(&digest.Remediations).Error; err != nil {
+		return digest, err
+	}
+
+	if err := w.DB.Model(&models.PolicyRecommendation{}).
+		Where("organization_id = ? AND status IN ?", orgID, []string{"accepted", "deployed"}).
+		Where("updated_at >= ? AND updated_at < ?", start, end).
+		Select("COALESCE(SUM(estimated_monthly_savings), 0)").
+		Find(&digest.EstimatedSavings).Error; err != nil {
+		return digest, err
+	}
+
+	return digest, nil
+}
+
+// topServices returns the n most expensive services, most expensive first
+func topServices(costs map[string]float64, n int) []DigestService {
+	services := make([]DigestService, 0, len(costs))
+	for service, cost := range costs {
+		services = append(services, DigestService{Service: service, Cost: cost})
+	}
+	sort.Slice(services, func(i, j int) bool {
+		if services[i].Cost != services[j].Cost {
+			return services[i].Cost > services[j].Cost
+		}
+		return services[i].Service < services[j].Service
+	})
+	if len(services) > n {
+		services = services[:n]
+	}
+	return services
+}
+
+// digestEvent renders a digest as a webhook event
+func digestEvent(cadence string, digest Digest) webhooks.Event {
+	title := strings.ToUpper(cadence[:1]) + cadence[1:] + " FinOps Digest"
+
+	var top []string
+	for _, service := range digest.TopServices {
+		top = append(top, fmt.Sprintf("%s: %.2f", service.Service, service.Cost))
+	}
+	topText := "No service breakdown available"
+	if len(top) > 0 {
+		topText = strings.Join(top, "\n")
+	}
+
+	return webhooks.Event{
+		Type:  webhooks.EventDigest,
+		Title: title,
+		Message: fmt.Sprintf("%s to %s: %d new violations, %d remediations",
+			digest.PeriodStart.Format("2006-01-02"), digest.PeriodEnd.Format("2006-01-02"), digest.NewViolations, digest.Remediations),
+		Fields: []webhooks.EventField{
+			{Name: "Month-to-date spend", Value: fmt.Sprintf("%.2f %s", digest.MonthToDateSpend, digest.Currency)},
+			{Name: "Top services", Value: topText},
+			{Name: "Critical / high violations", Value: fmt.Sprintf("%d / %d", digest.ViolationsBySeverity["critical"], digest.ViolationsBySeverity["high"])},
+			{Name: "Estimated savings", Value: fmt.Sprintf("%.2f %s per month", digest.EstimatedSavings, digest.Currency)},
+		},
+		Data: map[string]interface{}{
+			"cadence": cadence,
+			"digest":  digest,
+		},
+	}
+}
+
+// digestWebhooks returns the webhooks an organization's digest goes to: its chosen webhook, or
+// every enabled webhook subscribed to digests
+func (w *DigestWorker) digestWebhooks(org models.Organization) ([]models.Webhook, error) {
+	query := w.DB.Where("organization_id = ? AND enabled = ?", org.ClerkOrgID, true)
+	if org.DigestWebhookID != "" {
+		query = query.Where("id = ?", org.DigestWebhookID)
+	}
+	var hooks []models.Webhook
+	if err := query.Find(&hooks).Error; err != nil {
+		return nil, err
+	}
+
+	var subscribed []models.Webhook
+	for _, webhook := range hooks {
+		if org.DigestWebhookID != "" || webhooks.Subscribed(webhook, webhooks.EventDigest) {
+			subscribed = append(subscribed, webhook)
+		}
+	}
+	return subscribed, nil
+}
+
+// sendDigest delivers a digest to the given webhooks and reports whether any of them has it.
+// Each period is delivered at most once per webhook.
+func (w *DigestWorker) sendDigest(org models.Organization, hooks []models.Webhook, digest Digest, now time.Time) bool {
+	event := digestEvent(org.DigestCadence, digest)
+	dedupKey := fmt.Sprintf("%s:%s:%s", org.ClerkOrgID, webhooks.EventDigest, digest.PeriodEnd.Format("2006-01-02"))
+
+	delivered := false
+	for _, webhook := range hooks {
+		payload := webhooks.FormatEventPayload(webhook.Type, event, now)
+		_, err := webhooks.Deliver(w.DB, webhook, dedupKey, "", payload)
+		if errors.Is(err, webhooks.ErrAlreadyDelivered) {
+			fmt.Printf("Skipping duplicate digest to %s\n", webhook.URL)
+			delivered = true
+		} else if err != nil {
+			fmt.Printf("Error sending digest to %s: %v\n", webhook.URL, err)
+		} else {
+			delivered = true
+		}
+	}
+	return delivered
+}
@@ -0,0 +1,169 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	config "finopsbridge/api/internal/config_"
+	webhooks "finopsbridge/api/internal/webhooks_"
+
+	"gorm.io/gorm"
+)
+
+func TestDigestPeriod(t *testing.T) {
+	now := time.Date(2026, 3, 15, 9, 0, 0, 0, time.UTC)
+	at := func(days int) *time.Time {
+		t := now.AddDate(0, 0, -days)
+		return &t
+	}
+
+	tests := []struct {
+		name      string
+		cadence   string
+		lastSent  *time.Time
+		wantStart time.Time
+		wantDue   bool
+	}{
+		{"first weekly", DigestWeekly, nil, now.AddDate(0, 0, -7), true},
+		{"first daily", DigestDaily, nil, now.AddDate(0, 0, -1), true},
+		{"first monthly", DigestMonthly, nil, now.AddDate(0, -1, 0), true},
+		{"weekly due", DigestWeekly, at(7), *at(7), true},
+		{"weekly not yet", DigestWeekly, at(6), *at(6), false},
+		{"daily due", DigestDaily, at(1), *at(1), true},
+		{"monthly not yet", DigestMonthly, at(27), *at(27), false},
+	}
+	for _, tt := range tests {
+		start, due := digestPeriod(tt.cadence, tt.lastSent, now)
+		if !start.Equal(tt.wantStart) || due != tt.wantDue {
+			t.Errorf("%s: got %v, %v; want %v, %v", tt.name, start, due, tt.wantStart, tt.wantDue)
+		}
+	}
+}
+
+func TestTopServices(t *testing.T) {
+	costs := map[string]float64{"EC2": 300, "S3": 50, "RDS": 120, "Lambda": 50, "CloudWatch": 10}
+
+	got := topServices(costs, 4)
+	want := []DigestService{
+		{Service: "EC2", Cost: 300},
+		{Service: "RDS", Cost: 120},
+		{Service: "Lambda", Cost: 50},
+		{Service: "S3", Cost: 50},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if got := topServices(nil, 5); len(got) != 0 {
+		t.Errorf("no costs should give no services, got %+v", got)
+	}
+}
+
+func TestDigestEvent(t *testing.T) {
+	digest := Digest{
+		PeriodStart:          time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:            time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC),
+		Currency:             "USD",
+		MonthToDateSpend:     1234.5,
+		NewViolations:        4,
+		ViolationsBySeverity: map[string]int64{"critical": 1, "high": 2},
+		Remediations:         3,
+	}
+
+	event := digestEvent(DigestWeekly, digest)
+	if event.Type != webhooks.EventDigest || event.Title != "Weekly FinOps Digest" {
+		t.Errorf("got type %q, title %q", event.Type, event.Title)
+	}
+	if want := "2026-03-08 to 2026-03-15: 4 new violations, 3 remediations"; event.Message != want {
+		t.Errorf("message = %q, want %q", event.Message, want)
+	}
+	fields := make(map[string]string)
+	for _, field := range event.Fields {
+		fields[field.Name] = field.Value
+	}
+	if fields["Month-to-date spend"] != "1234.50 USD" || fields["Top services"] != "No service breakdown available" || fields["Critical / high violations"] != "1 / 2" {
+		t.Errorf("fields = %v", fields)
+	}
+}
+
+// seedQueries answers each query db runs whose SQL contains a key with the value's JSON, and
+// returns the SQL of the queries it answered by key
+func seedQueries(t *testing.T, db *gorm.DB, rows map[string]string) map[string]string {
+	t.Helper()
+	answered := make(map[string]string)
+	err := db.Callback().Query().After("gorm:query").Register("test:seed_queries", func(tx *gorm.DB) {
+		sql := tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...)
+		for key, seeded := range rows {
+			if strings.Contains(sql, key) {
+				answered[key] = sql
+				tx.AddError(json.Unmarshal([]byte(seeded), tx.Statement.Dest))
+				tx.RowsAffected = 1
+				return
+			}
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return answered
+}
+
+func TestCompileDigest(t *testing.T) {
+	cfg := &config.Config{ReportingCurrency: "USD", ExchangeRates: map[string]float64{"EUR": 1.1}}
+	w := NewDigestWorker(dryRunDB(t), cfg)
+	start := time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	answered := seedQueries(t, w.DB, map[string]string{
+		// The latest snapshot of each provider this month
+		"DISTINCT ON (provider_id)": `[
+			{"ProviderID": "aws", "MonthToDateSpend": 1200, "Currency": "USD"},
+			{"ProviderID": "azure", "MonthToDateSpend": 500, "Currency": "EUR"}
+		]`,
+		"GROUP BY":                       `[{"Severity": "critical", "Count": 1}, {"Severity": "high", "Count": 2}, {"Severity": "low", "Count": 4}]`,
+		"count(*)":                       `3`,
+		"SUM(estimated_monthly_savings)": `245.5`,
+	})
+
+	digest, err := w.compileDigest(context.Background(), "org", start, end)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := 1200 + 500*1.1; math.Abs(digest.MonthToDateSpend-want) > 1e-6 {
+		t.Errorf("MonthToDateSpend = %v, want %v converted to USD", digest.MonthToDateSpend, want)
+	}
+	wantSeverity := map[string]int64{"critical": 1, "high": 2, "medium": 0, "low": 4}
+	if digest.NewViolations != 7 || !reflect.DeepEqual(digest.ViolationsBySeverity, wantSeverity) {
+		t.Errorf("violations = %d %v, want 7 %v", digest.NewViolations, digest.ViolationsBySeverity, wantSeverity)
+	}
+	if digest.Remediations != 3 || digest.EstimatedSavings != 245.5 {
+		t.Errorf("remediations = %d, savings = %v; want 3, 245.5", digest.Remediations, digest.EstimatedSavings)
+	}
+	if len(digest.TopServices) != 0 {
+		t.Errorf("TopServices = %v, want none without connected providers", digest.TopServices)
+	}
+
+	for key, wants := range map[string][]string{
+		// Month-to-date spend comes from this month's snapshots, up to the end of the period
+		"DISTINCT ON (provider_id)":      {"organization_id = 'org'", "date >= '2026-03-01 00:00:00'", "date <= '2026-03-15 00:00:00'", "ORDER BY provider_id, date DESC"},
+		"GROUP BY":                       {"policies.organization_id = 'org'", "policy_violations.created_at >= '2026-03-08 00:00:00'", "policy_violations.created_at < '2026-03-15 00:00:00'"},
+		"count(*)":                       {"policy_violations.status = 'remediated'", "policy_violations.remediated_at >= '2026-03-08 00:00:00'", "policy_violations.remediated_at < '2026-03-15 00:00:00'"},
+		"SUM(estimated_monthly_savings)": {"organization_id = 'org'", "status IN ('accepted','deployed')", "updated_at >= '2026-03-08 00:00:00'"},
+	} {
+		sql, ok := answered[key]
+		if !ok {
+			t.Errorf("no query contains %s", key)
+			continue
+		}
+		for _, want := range wants {
+			if !strings.Contains(sql, want) {
+				t.Errorf("%s\nshould contain %s", sql, want)
+			}
+		}
+	}
+}
@@ -1,32 +1,77 @@
 package worker
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"net/http"
+	"sync"
 	"time"
 
 	cloud "finopsbridge/api/internal/cloud_"
 	config "finopsbridge/api/internal/config_"
 	models "finopsbridge/api/internal/models_"
 	opa "finopsbridge/api/internal/opa_"
+	tracing "finopsbridge/api/internal/tracing_"
+	webhooks "finopsbridge/api/internal/webhooks_"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Reasons recorded on an EnforcementRun when a provider is skipped
+const (
+	SkipReasonEnforcementPaused   = "enforcement_paused"
+	SkipReasonQuietHours          = "quiet_hours"
+	SkipReasonRemediationCooldown = "remediation_cooldown" // Paused by the circuit breaker
+	SkipReasonCredentialError     = "credential_error"
+	SkipReasonBillingError        = "billing_error" // Any other failed billing fetch
+	SkipReasonUnsupportedProvider = "unsupported_provider"
+)
+
+// Reasons recorded on an EnforcementRun when a provider is degraded
+const (
+	// DegradedReasonRemediationFailed marks a provider whose spend was updated and policies
+	// evaluated, but whose resources could not be listed or acted on for remediation
+	DegradedReasonRemediationFailed = "remediation_failed"
+	// DegradedReasonBillingTruncated marks a provider whose billing fetch was cut short, so its
+	// spend-limit policies weren't evaluated against the partial spend
+	DegradedReasonBillingTruncated = "billing_truncated"
 )
 
 type EnforcementWorker struct {
 	DB     *gorm.DB
 	OPA    *opa.Engine
 	Config *config.Config
+
+	// OrgChanged, when set, is called after a run for each organization whose spend or
+	// violations may have changed
+	OrgChanged func(orgID string)
+
+	// Clock is the time schedules, cooldowns and escalations are evaluated at
+	Clock cloud.Clock
+
+	breaker *remediationBreaker
+	mu      sync.Mutex               // Guards actions and policyErrors, written by concurrent providers
+	actions map[string]*actionCounts // Remediation outcomes per org during the current run
+	runID   string                   // Correlation ID of the current run, stored on what it creates
+
+	policyErrors map[string][]models.PolicyError // Policies that panicked per org during the current run
+}
+
+// actionCounts tallies remediation actions for an organization's run record
+type actionCounts struct {
+	succeeded int
+	failed    int
 }
 
 func NewEnforcementWorker(db *gorm.DB, opaEngine *opa.Engine, cfg *config.Config) *EnforcementWorker {
 	return &EnforcementWorker{
-		DB:     db,
-		OPA:    opaEngine,
-		Config: cfg,
+		DB:      db,
+		OPA:     opaEngine,
+		Config:  cfg,
+		Clock:   cloud.SystemClock,
+		breaker: newRemediationBreaker(),
 	}
 }
 
@@ -48,7 +93,12 @@ func (w *EnforcementWorker) Start(ctx context.Context, interval time.Duration) {
 }
 
 func (w *EnforcementWorker) run(ctx context.Context) {
-	fmt.Println("Running enforcement worker...")
+	w.runID = tracing.NewID()
+	ctx = tracing.WithID(ctx, w.runID)
+	fmt.Printf("Running enforcement worker (run %s)...\n", w.runID)
+	w.breaker.startRun()
+	w.actions = make(map[string]*actionCounts)
+	w.policyErrors = make(map[string][]models.PolicyError)
 
 	// Get all enabled policies
 	var policies []models.Policy
@@ -64,85 +114,331 @@ func (w *EnforcementWorker) run(ctx context.Context) {
 		return
 	}
 
-	// For each provider, fetch billing data and evaluate policies
+	// Organizations whose remediation is held this run, by skip reason
+	holds, err := w.remediationHolds(w.Clock.Now())
+	if err != nil {
+		fmt.Printf("Error fetching paused organizations: %v\n", err)
+		return
+	}
+
+	// One run record per organization, so users can see what each pass did
+	runs := make(map[string]*models.EnforcementRun)
+	skipped := make(map[string][]models.SkippedProvider)
+	degraded := make(map[string][]models.SkippedProvider)
+	for _, provider := range providers {
+		if _, exists := runs[provider.OrganizationID]; !exists {
+			runs[provider.OrganizationID] = &models.EnforcementRun{
+				OrganizationID: provider.OrganizationID,
+				StartedAt:      w.Clock.Now(),
+				RequestID:      w.runID,
+			}
+		}
+	}
+
+	// For each provider, fetch billing data and evaluate policies, up to EnforcementConcurrency
+	// providers at a time
+	concurrency := w.Config.EnforcementConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	var mu sync.Mutex // Guards runs, skipped and degraded
+	for _, provider := range providers {
+		provider := provider
+		slots <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			skip, degradation := w.processProvider(ctx, provider, policies, holds.reason(provider.OrganizationID))
+
+			mu.Lock()
+			defer mu.Unlock()
+			if skip != nil {
+				skipped[provider.OrganizationID] = append(skipped[provider.OrganizationID], *skip)
+			} else {
+				runs[provider.OrganizationID].ProvidersProcessed++
+			}
+			if degradation != nil {
+				degraded[provider.OrganizationID] = append(degraded[provider.OrganizationID], *degradation)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// AI policies are evaluated once per organization against token usage and GPU metrics, not
+	// per provider
+	aiOrgs := make(map[string]bool)
+	for _, policy := range policies {
+		if isAIPolicy(policy) && !aiOrgs[policy.OrganizationID] {
+			aiOrgs[policy.OrganizationID] = true
+			w.evaluateAIPolicies(ctx, policy.OrganizationID, policies, w.Clock.Now(), holds.reason(policy.OrganizationID) != "")
+		}
+	}
+
+	// Org-wide anomaly checks on combined spend, in addition to the per-provider ones
+	providerCounts := make(map[string]int)
 	for _, provider := range providers {
-		w.processProvider(ctx, provider, policies)
+		providerCounts[provider.OrganizationID]++
+	}
+	w.evaluateOrgAnomalies(policies, providerCounts, w.Clock.Now())
+
+	// Budgets are checked once per organization, after every provider's spend is fetched
+	w.evaluateBudgets(ctx, policies, w.Clock.Now())
+
+	w.escalateViolations(policies, w.Clock.Now())
+
+	for orgID, run := range runs {
+		w.saveRun(run, skipped[orgID], degraded[orgID])
+	}
+
+	if w.OrgChanged != nil {
+		for orgID := range runs {
+			w.OrgChanged(orgID)
+		}
+		for orgID := range aiOrgs {
+			if runs[orgID] == nil {
+				w.OrgChanged(orgID)
+			}
+		}
+	}
+}
+
+func (w *EnforcementWorker) saveRun(run *models.EnforcementRun, skipped, degraded []models.SkippedProvider) {
+	if skipped == nil {
+		skipped = []models.SkippedProvider{}
+	}
+	if degraded == nil {
+		degraded = []models.SkippedProvider{}
+	}
+	skippedJSON, _ := json.Marshal(skipped)
+	degradedJSON, _ := json.Marshal(degraded)
+
+	now := w.Clock.Now()
+	run.CompletedAt = &now
+	run.Skipped = string(skippedJSON)
+	run.Degraded = len(degraded) > 0
+	run.DegradedProviders = string(degradedJSON)
+	if counts, ok := w.actions[run.OrganizationID]; ok {
+		run.ActionsSucceeded = counts.succeeded
+		run.ActionsFailed = counts.failed
+	}
+	if policyErrors := w.policyErrors[run.OrganizationID]; len(policyErrors) > 0 {
+		policyErrorsJSON, _ := json.Marshal(policyErrors)
+		run.PolicyErrors = string(policyErrorsJSON)
+	}
+
+	if err := w.DB.Create(run).Error; err != nil {
+		fmt.Printf("Error saving enforcement run for %s: %v\n", run.OrganizationID, err)
 	}
 }
 
-func (w *EnforcementWorker) processProvider(ctx context.Context, provider models.CloudProvider, policies []models.Policy) {
+// processProvider evaluates policies for a provider. It returns a SkippedProvider when the
+// provider could not be evaluated or no remediation was allowed, and nil otherwise. hold is
+// the skip reason holding the org's remediation this run, or "".
+// Billing and evaluation don't depend on resource listing, so a remediation failure doesn't
+// stop them; it is reported as the second (degraded) result instead.
+func (w *EnforcementWorker) processProvider(ctx context.Context, provider models.CloudProvider, policies []models.Policy, hold string) (*models.SkippedProvider, *models.SkippedProvider) {
 	fmt.Printf("Processing provider: %s (%s)\n", provider.Name, provider.Type)
+	paused := hold != ""
 
-	// Fetch billing data based on provider type
-	var billingData map[string]interface{}
-	var err error
-
-	switch provider.Type {
-	case "aws":
-		billingData, err = cloud.FetchAWSBilling(ctx, provider, w.Config)
-	case "azure":
-		billingData, err = cloud.FetchAzureBilling(ctx, provider, w.Config)
-	case "gcp":
-		billingData, err = cloud.FetchGCPBilling(ctx, provider, w.Config)
-	case "oci":
-		billingData, err = cloud.FetchOCIBilling(ctx, provider, w.Config)
-	case "ibm":
-		billingData, err = cloud.FetchIBMBilling(ctx, provider, w.Config)
-	default:
+	// Fetch billing data based on provider type (cached between runs)
+	billingData, err := cloud.FetchBilling(ctx, provider, w.Config, false)
+	if errors.Is(err, cloud.ErrBillingNotSupported) {
 		fmt.Printf("Unknown provider type: %s\n", provider.Type)
-		return
+		return &models.SkippedProvider{
+			ProviderID:   provider.ID,
+			ProviderName: provider.Name,
+			Reason:       SkipReasonUnsupportedProvider,
+			Detail:       provider.Type,
+		}, nil
 	}
 
+	w.DB.Model(&models.CloudProvider{}).Where("id = ?", provider.ID).Updates(cloud.SyncUpdates(billingData, err, w.Clock.Now()))
+
 	if err != nil {
 		fmt.Printf("Error fetching billing data for %s: %v\n", provider.Name, err)
-		return
+		reason := SkipReasonBillingError
+		if cloud.IsCredentialError(err) {
+			reason = SkipReasonCredentialError
+		}
+		return &models.SkippedProvider{
+			ProviderID:   provider.ID,
+			ProviderName: provider.Name,
+			Reason:       reason,
+			Detail:       err.Error(),
+		}, nil
 	}
 
-	// Update monthly spend
-	if spend, ok := billingData["monthlySpend"].(float64); ok {
+	// Update monthly spend, unless the provider has no data yet and we already know this month's
+	if spend, store := cloud.MonthlySpend(billingData, provider, w.Clock.Now()); store {
+		// Only the spend: saving the whole struct would undo the sync columns updated above
 		provider.MonthlySpend = spend
-		w.DB.Save(&provider)
+		w.DB.Model(&models.CloudProvider{}).Where("id = ?", provider.ID).Update("monthly_spend", spend)
+		w.recordSpendSnapshot(provider, billingData)
+		w.backfillSpendSnapshots(provider, billingData)
+	} else if !cloud.HasBillingData(billingData) {
+		fmt.Printf("No billing data yet for %s; keeping month-to-date spend of %.2f\n", provider.Name, provider.MonthlySpend)
+	}
+
+	// Evaluate each policy; resource-scoped ones against the provider's instances, listed once
+	var degraded *models.SkippedProvider
+	var instances []cloud.Instance
+	instancesListed := false
+
+	// Truncated spend is only a lower bound, so spend limits aren't evaluated against it
+	truncated := cloud.BillingTruncated(billingData)
+	if truncated {
+		degraded = &models.SkippedProvider{
+			ProviderID:   provider.ID,
+			ProviderName: provider.Name,
+			Reason:       DegradedReasonBillingTruncated,
+			Detail:       cloud.SyncWarning(billingData),
+		}
 	}
 
-	// Evaluate each policy
 	for _, policy := range policies {
-		if policy.OrganizationID != provider.OrganizationID {
+		if policy.OrganizationID != provider.OrganizationID || isAIPolicy(policy) || isBudgetPolicy(policy) {
+			continue
+		}
+		if truncated && isSpendLimitPolicy(policy) {
+			fmt.Printf("Not evaluating policy %s for %s: billing data is truncated\n", policy.Name, provider.Name)
 			continue
 		}
 
-		w.evaluatePolicy(ctx, policy, provider, billingData)
+		err := w.guardPolicy(policy, provider.ID, func() error {
+			// Schedules act on resources directly rather than on billing input
+			if policy.Type == "scheduled_start_stop" {
+				if !paused && policy.Mode != models.PolicyModeMonitor {
+					w.applySchedule(ctx, policy, provider)
+				}
+				return nil
+			}
+			if isResourcePolicy(policy) {
+				if !instancesListed {
+					instances, instancesListed = w.listInstances(ctx, provider), true
+				}
+				return w.evaluateResourcePolicy(ctx, policy, provider, billingData, instances, paused)
+			}
+			return w.evaluatePolicy(ctx, policy, provider, billingData, paused)
+		})
+		// A panic is recorded on the run by guardPolicy, not as a remediation failure
+		if err != nil && !errors.Is(err, errPolicyPanicked) && degraded == nil {
+			degraded = &models.SkippedProvider{
+				ProviderID:   provider.ID,
+				ProviderName: provider.Name,
+				Reason:       DegradedReasonRemediationFailed,
+				Detail:       fmt.Sprintf("policy '%s': %v", policy.Name, err),
+			}
+		}
 	}
+
+	// The breaker may also trip partway through this run, holding the rest of its remediation
+	if !paused && w.breaker.isTripped(provider.OrganizationID) {
+		hold = SkipReasonRemediationCooldown
+	}
+	if hold != "" {
+		return &models.SkippedProvider{
+			ProviderID:   provider.ID,
+			ProviderName: provider.Name,
+			Reason:       hold,
+			Detail:       holdDetails[hold],
+		}, nil
+	}
+
+	return nil, degraded
 }
 
-func (w *EnforcementWorker) evaluatePolicy(ctx context.Context, policy models.Policy, provider models.CloudProvider, billingData map[string]interface{}) {
-	// Prepare input for OPA
-	input := map[string]interface{}{
-		"account_id":     provider.AccountID,
-		"subscription_id": provider.SubscriptionID,
-		"project_id":     provider.ProjectID,
-		"monthly_spend":  provider.MonthlySpend,
-		"provider_type":  provider.Type,
+// recordSpendSnapshot upserts today's month-to-date spend so dashboards can show history
+func (w *EnforcementWorker) recordSpendSnapshot(provider models.CloudProvider, billingData map[string]interface{}) {
+	currency, _ := billingData["currency"].(string)
+	if currency == "" {
+		currency = cloud.DefaultCurrency(w.Config)
+	}
+
+	now := w.Clock.Now().UTC()
+	snapshot := models.SpendSnapshot{
+		OrganizationID:   provider.OrganizationID,
+		ProviderID:       provider.ID,
+		ProviderType:     provider.Type,
+		Date:             time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+		MonthToDateSpend: provider.MonthlySpend,
+		Currency:         currency,
+	}
+
+	if err := w.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "provider_id"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"month_to_date_spend", "currency", "updated_at"}),
+	}).Create(&snapshot).Error; err != nil {
+		fmt.Printf("Error recording spend snapshot for %s: %v\n", provider.Name, err)
+	}
+}
+
+func (w *EnforcementWorker) applySchedule(ctx context.Context, policy models.Policy, provider models.CloudProvider) {
+	var policyConfig map[string]interface{}
+	if err := json.Unmarshal([]byte(policy.Config), &policyConfig); err != nil {
+		fmt.Printf("Error parsing policy config: %v\n", err)
+		return
+	}
+
+	schedule, err := cloud.ParseSchedule(policyConfig)
+	if err != nil {
+		fmt.Printf("Invalid schedule for policy %s: %v\n", policy.Name, err)
+		return
 	}
 
-	// Merge billing data into input
-	for k, v := range billingData {
-		input[k] = v
+	protected, err := w.protectedResourceIDs(policy.OrganizationID, provider.Type)
+	if err != nil {
+		fmt.Printf("Not applying schedule for policy %s: %v\n", policy.Name, err)
+		return
+	}
+
+	opts := cloud.RemediationOptions{Protected: protected, Clock: w.Clock}
+	if err := cloud.ApplySchedule(ctx, provider, w.Config, schedule, w.Clock, opts); err != nil {
+		fmt.Printf("Error applying schedule for policy %s: %v\n", policy.Name, err)
+	}
+}
+
+// evaluatePolicy evaluates a policy against the provider and handles any violation.
+// It returns the remediation error, if remediation was attempted and failed.
+func (w *EnforcementWorker) evaluatePolicy(ctx context.Context, policy models.Policy, provider models.CloudProvider, billingData map[string]interface{}, paused bool) error {
+	// Prepare input for OPA
+	input, ok := w.policyInputs().Build(policy, provider, billingData, w.Clock.Now())
+	if !ok {
+		// Not enough spend history to compare against yet
+		return nil
 	}
 
 	// Evaluate policy with OPA
-	allowed, result, err := w.OPA.EvaluatePolicy(policy.ID, input)
+	allowed, result, err := w.OPA.EvaluatePolicy(policy.ID, policy.Config, input)
+	w.logDecision(policy, provider.ID, input, allowed, err)
 	if err != nil {
 		fmt.Printf("Error evaluating policy %s: %v\n", policy.Name, err)
-		return
+		return nil
 	}
 
 	if !allowed {
 		// Policy violation detected
-		w.handleViolation(ctx, policy, provider, result)
+		severity, notifyOnly, tier := violationSeverity(policy, input, result), notifiesOnly(policy), ""
+		if limits, ok := policySpendLimits(policy); ok {
+			spend, _ := input["monthly_spend"].(float64)
+			tier, severity = limits.tier(spend)
+			notifyOnly = tier == spendLimitTierSoft
+		}
+		return w.handleViolation(ctx, policy, provider, result, severity, tier, notifyOnly, paused)
 	}
+	return nil
 }
 
-func (w *EnforcementWorker) handleViolation(ctx context.Context, policy models.Policy, provider models.CloudProvider, result map[string]interface{}) {
+// handleViolation records a violation of a provider-scoped policy and remediates it, unless it
+// is notifyOnly: a max_spend policy's soft limit, or a month_over_month_growth policy. tier is
+// the spend limit crossed under tiered max_spend limits, empty otherwise. A pending soft
+// violation is escalated and remediated once a later run crosses the hard limit.
+func (w *EnforcementWorker) handleViolation(ctx context.Context, policy models.Policy, provider models.CloudProvider, result map[string]interface{}, severity, tier string, notifyOnly, paused bool) error {
 	fmt.Printf("Policy violation detected: %s\n", policy.Name)
 
 	// Extract violation details
@@ -153,7 +449,9 @@ func (w *EnforcementWorker) handleViolation(ctx context.Context, policy models.P
 
 	// Check if violation already exists
 	var existingViolation models.PolicyViolation
-	err := w.DB.Where("policy_id = ? AND status = ?", policy.ID, "pending").
+	// Each provider has its own violation; org-scope violations of the same policy are tracked
+	// separately
+	err := w.DB.Where("policy_id = ? AND resource_id = ? AND resource_type <> ? AND status = ?", policy.ID, provider.ID, ResourceTypeOrganization, "pending").
 		First(&existingViolation).Error
 
 	if err == gorm.ErrRecordNotFound {
@@ -164,35 +462,92 @@ func (w *EnforcementWorker) handleViolation(ctx context.Context, policy models.P
 			ResourceType:  "cloud_provider",
 			CloudProvider: provider.Type,
 			Message:       message,
-			Severity:      "high",
+			Severity:      severity,
 			Status:        "pending",
+			RequestID:     w.runID,
+
+			SpendLimitTier:   tier,
+			CostContributors: w.costContributors(ctx, policy, provider),
 		}
 
-		if err := w.DB.Create(&violation).Error; err != nil {
-			fmt.Printf("Error creating violation: %v\n", err)
-			return
+		if !w.createViolation(&violation) {
+			return nil
 		}
 
 		// Create activity log
 		activityLog := models.ActivityLog{
 			OrganizationID: policy.OrganizationID,
+			RequestID:      w.runID,
 			Type:           "policy_violation",
 			Message:        fmt.Sprintf("Policy '%s' violation: %s", policy.Name, message),
-			Metadata:        fmt.Sprintf(`{"policyId":"%s","violationId":"%s"}`, policy.ID, violation.ID),
+			Metadata:       fmt.Sprintf(`{"policyId":"%s","violationId":"%s"}`, policy.ID, violation.ID),
 		}
 		w.DB.Create(&activityLog)
 
-		// Attempt remediation based on policy type
-		w.remediate(ctx, policy, provider, violation)
+		// Remediate now, or once the policy's grace period runs out
+		var remediationErr error
+		if !notifyOnly && !w.deferRemediation(policy, &violation) {
+			remediationErr = w.remediateUnlessPaused(ctx, policy, provider, violation, paused)
+		}
 
 		// Send webhooks
 		w.sendWebhooks(policy.OrganizationID, violation)
+		return remediationErr
+	}
+	if err != nil || notifyOnly {
+		return nil
+	}
+	// A soft-limit violation is remediated once spend crosses the hard limit too
+	if tier == spendLimitTierHard && w.escalateSpendLimit(policy, &existingViolation, message) {
+		var remediationErr error
+		if !w.deferRemediation(policy, &existingViolation) {
+			remediationErr = w.remediateUnlessPaused(ctx, policy, provider, existingViolation, paused)
+		}
+		w.sendWebhooks(policy.OrganizationID, existingViolation)
+		return remediationErr
+	}
+	// Still violating after the grace period, and nobody resolved or ignored it
+	return w.remediateExpiredGrace(ctx, policy, provider, existingViolation, paused)
+}
+
+// remediateUnlessPaused attempts remediation based on policy type, unless the org has paused
+// enforcement or its circuit breaker has tripped, in which case the skip is logged
+func (w *EnforcementWorker) remediateUnlessPaused(ctx context.Context, policy models.Policy, provider models.CloudProvider, violation models.PolicyViolation, paused bool) error {
+	if w.remediationHeld(policy, violation, paused) {
+		return nil
+	}
+	_, err := w.remediate(ctx, policy, provider, violation, false)
+	return err
+}
+
+// remediationHeld reports whether remediation must wait because the org has paused enforcement
+// or its circuit breaker has tripped, logging the skip
+func (w *EnforcementWorker) remediationHeld(policy models.Policy, violation models.PolicyViolation, paused bool) bool {
+	if !paused && !w.breaker.isTripped(policy.OrganizationID) {
+		return false
 	}
+	w.DB.Create(&models.ActivityLog{
+		OrganizationID: policy.OrganizationID,
+		RequestID:      w.runID,
+		Type:           "remediation_skipped",
+		Message:        fmt.Sprintf("Remediation for policy '%s' skipped: enforcement is paused or in quiet hours", policy.Name),
+		Metadata:       fmt.Sprintf(`{"policyId":"%s","violationId":"%s"}`, policy.ID, violation.ID),
+	})
+	return true
 }
 
-func (w *EnforcementWorker) remediate(ctx context.Context, policy models.Policy, provider models.CloudProvider, violation models.PolicyViolation) {
+// remediate runs the remediation of a violation's policy against its provider and records the
+// outcome on the violation. With dryRun, or a require_tags policy in autoTagDryRun mode, nothing
+// is acted on: the candidates are logged and the violation stays pending.
+func (w *EnforcementWorker) remediate(ctx context.Context, policy models.Policy, provider models.CloudProvider, violation models.PolicyViolation, dryRun bool) (cloud.RemediationResult, error) {
 	fmt.Printf("Attempting remediation for policy: %s\n", policy.Name)
 
+	// Monitor policies only detect; a dry run still shows what enforcing would do
+	var result cloud.RemediationResult
+	if !dryRun && w.skipMonitored(policy, violation) {
+		return result, nil
+	}
+
 	// Parse policy config to get remediation parameters
 	var policyConfig map[string]interface{}
 	if err := json.Unmarshal([]byte(policy.Config), &policyConfig); err != nil {
@@ -200,67 +555,181 @@ func (w *EnforcementWorker) remediate(ctx context.Context, policy models.Policy,
 		policyConfig = make(map[string]interface{})
 	}
 
-	var err error
-	switch policy.Type {
-	case "max_spend":
-		// Stop non-essential resources
-		err = cloud.StopNonEssentialResources(ctx, provider, w.Config)
-	case "block_instance_type":
-		// Terminate oversized instances
-		// Extract maxSize from config, default to 4 (large) if not specified
-		maxSizeLevel := 4
-		if maxSize, ok := policyConfig["maxSize"].(string); ok {
-			switch maxSize {
-			case "small":
-				maxSizeLevel = 2
-			case "medium":
-				maxSizeLevel = 3
-			case "large":
-				maxSizeLevel = 4
-			case "xlarge":
-				maxSizeLevel = 5
+	// The policy's selector scopes every remediation below; protected resources are never touched
+	protected, err := w.protectedResourceIDs(policy.OrganizationID, provider.Type)
+	if err != nil {
+		return result, err
+	}
+	opts := cloud.RemediationOptions{
+		DryRun:    dryRun,
+		Selector:  cloud.ParseResourceSelector(policyConfig),
+		Protected: protected,
+
+		MinResourceAge: cloud.MinResourceAge(policyConfig),
+		Clock:          w.Clock,
+	}
+	// A violation of a resource-scoped policy is remediated on its own instance only
+	if violation.ResourceType == ResourceTypeInstance {
+		opts.Resources = []string{violation.ResourceID}
+	}
+
+	if url, timeout, ok := remediationWebhook(policyConfig); ok {
+		// The customer's service acts instead of the built-in remediation for any policy type
+		if dryRun {
+			return result, ErrWebhookDryRun
+		}
+		var payload []byte
+		payload, err = w.remediationWebhookPayload(policy, provider, violation, policyConfig, opts)
+		if err == nil {
+			result, err = w.remediateViaWebhook(ctx, url, timeout, remediationWebhookHeaders(policy), payload)
+		}
+	} else {
+		switch policy.Type {
+		case "max_spend":
+			// Stop non-essential resources
+			result, err = cloud.StopNonEssentialResources(ctx, provider, w.Config, opts)
+		case "block_instance_type":
+			// Terminate oversized instances
+			result, err = cloud.TerminateOversizedInstances(ctx, provider, w.Config,
+				cloud.MaxSizeLevel(policyConfig), cloud.MaxHourlyPrice(policyConfig), opts)
+		case "auto_stop_idle":
+			// Stop idle resources
+			result, err = cloud.StopIdleResources(ctx, provider, w.Config, cloud.IdleHours(policyConfig), opts)
+		case "require_tags":
+			// Notification only, unless the policy opted in to tagging resources with defaults
+			if !cloud.AutoTag(policyConfig) {
+				return result, nil
+			}
+			if cloud.AutoTagDryRun(policyConfig) {
+				opts.DryRun = true
 			}
+			result, err = cloud.ApplyDefaultTags(ctx, provider, w.Config, cloud.DefaultTags(provider, policyConfig), opts)
 		}
-		err = cloud.TerminateOversizedInstances(ctx, provider, w.Config, maxSizeLevel)
-	case "auto_stop_idle":
-		// Stop idle resources
-		// Extract idleHours from config, default to 24 if not specified
-		idleHours := 24.0
-		if hours, ok := policyConfig["idleHours"].(float64); ok {
-			idleHours = hours
-		} else if hours, ok := policyConfig["idleHours"].(int); ok {
-			idleHours = float64(hours)
+	}
+
+	// Report what would be done; the violation stays pending
+	if opts.DryRun {
+		if err != nil {
+			return result, err
 		}
-		err = cloud.StopIdleResources(ctx, provider, w.Config, idleHours)
-	case "require_tags":
-		// Tag resources (no remediation, just notification)
-		return
+		candidates := result.Candidates()
+		candidatesJSON, _ := json.Marshal(candidates)
+		w.DB.Create(&models.ActivityLog{
+			OrganizationID: policy.OrganizationID,
+			RequestID:      w.runID,
+			Type:           "remediation_dry_run",
+			Message:        fmt.Sprintf("Policy '%s' would act on %d resources", policy.Name, len(candidates)),
+			Metadata:       fmt.Sprintf(`{"policyId":"%s","violationId":"%s","candidates":%s}`, policy.ID, violation.ID, candidatesJSON),
+		})
+		return result, nil
+	}
+
+	w.recordRemediationActions(policy.OrganizationID, result.Attempted())
+	w.tallyActions(policy.OrganizationID, result)
+	w.recordPolicySavings(policy.ID, result)
+
+	if len(result.Protected) > 0 {
+		protectedJSON, _ := json.Marshal(result.Protected)
+		w.DB.Create(&models.ActivityLog{
+			OrganizationID: policy.OrganizationID,
+			RequestID:      w.runID,
+			Type:           "remediation_protected",
+			Message:        fmt.Sprintf("Policy '%s' skipped %d protected resources", policy.Name, len(result.Protected)),
+			Metadata:       fmt.Sprintf(`{"policyId":"%s","violationId":"%s","protected":%s}`, policy.ID, violation.ID, protectedJSON),
+		})
+	}
+
+	// Record per-resource outcomes even when the call stopped early
+	if result.Attempted() > 0 {
+		violation.ActionsSucceeded = len(result.Succeeded)
+		violation.ActionsFailed = len(result.Failed)
+		if len(result.Failed) > 0 {
+			failedJSON, _ := json.Marshal(result.Failed)
+			violation.RemediationErrors = string(failedJSON)
+		}
+		w.DB.Save(&violation)
 	}
 
 	if err != nil {
 		fmt.Printf("Remediation failed: %v\n", err)
-		return
+		return result, err
+	}
+
+	// Some actions failed: leave the violation pending so it isn't reported as fixed
+	if err := result.Err(); err != nil {
+		fmt.Printf("Remediation partially failed: %v\n", err)
+		w.DB.Create(&models.ActivityLog{
+			OrganizationID: policy.OrganizationID,
+			RequestID:      w.runID,
+			Type:           "remediation_partial",
+			Message: fmt.Sprintf("Policy '%s' remediation: %d of %d actions failed",
+				policy.Name, len(result.Failed), result.Attempted()),
+			Metadata: fmt.Sprintf(`{"policyId":"%s","violationId":"%s","succeeded":%d,"failed":%d}`,
+				policy.ID, violation.ID, len(result.Succeeded), len(result.Failed)),
+		})
+		return result, err
 	}
 
 	// Mark violation as remediated
-	now := time.Now()
+	now := w.Clock.Now()
 	violation.Status = "remediated"
 	violation.RemediatedAt = &now
 	w.DB.Save(&violation)
+	w.recordPolicyRemediated(violation)
 
 	// Create activity log
 	activityLog := models.ActivityLog{
 		OrganizationID: policy.OrganizationID,
+		RequestID:      w.runID,
 		Type:           "remediation",
 		Message:        fmt.Sprintf("Policy '%s' violation remediated", policy.Name),
 		Metadata:       fmt.Sprintf(`{"policyId":"%s","violationId":"%s"}`, policy.ID, violation.ID),
 	}
 	w.DB.Create(&activityLog)
+	return result, nil
+}
+
+// skipMonitored reports whether a policy is in monitor mode, logging the remediation it skips
+func (w *EnforcementWorker) skipMonitored(policy models.Policy, violation models.PolicyViolation) bool {
+	if policy.Mode != models.PolicyModeMonitor {
+		return false
+	}
+	w.DB.Create(&models.ActivityLog{
+		OrganizationID: policy.OrganizationID,
+		RequestID:      w.runID,
+		Type:           "remediation_skipped",
+		Message:        fmt.Sprintf("Remediation for policy '%s' skipped: the policy is in monitor mode", policy.Name),
+		Metadata:       fmt.Sprintf(`{"policyId":"%s","violationId":"%s","mode":"%s"}`, policy.ID, violation.ID, policy.Mode),
+	})
+	return true
+}
+
+// protectedResourceIDs returns the resource IDs an organization protects from remediation on a
+// provider type. Callers must not act when it fails: an empty list would protect nothing.
+func (w *EnforcementWorker) protectedResourceIDs(orgID, providerType string) ([]string, error) {
+	ids, err := models.ProtectedResourceIDs(w.DB, orgID, providerType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch protected resources: %w", err)
+	}
+	return ids, nil
+}
+
+// tallyActions adds a remediation call's outcomes to its organization's run totals
+func (w *EnforcementWorker) tallyActions(orgID string, result cloud.RemediationResult) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	counts, ok := w.actions[orgID]
+	if !ok {
+		counts = &actionCounts{}
+		w.actions[orgID] = counts
+	}
+	counts.succeeded += len(result.Succeeded)
+	counts.failed += len(result.Failed)
 }
 
 func (w *EnforcementWorker) sendWebhooks(orgID string, violation models.PolicyViolation) {
-	var webhooks []models.Webhook
-	if err := w.DB.Where("organization_id = ? AND enabled = ?", orgID, true).Find(&webhooks).Error; err != nil {
+	var hooks []models.Webhook
+	if err := w.DB.Where("organization_id = ? AND enabled = ?", orgID, true).Find(&hooks).Error; err != nil {
 		fmt.Printf("Error fetching webhooks: %v\n", err)
 		return
 	}
@@ -272,14 +741,35 @@ func (w *EnforcementWorker) sendWebhooks(orgID string, violation models.PolicyVi
 		return
 	}
 
-	for _, webhook := range webhooks {
-		payload := w.formatWebhookPayload(webhook.Type, policy, violation)
+	// Org overrides for severity emoji and colors; fall back to the defaults if unset or invalid
+	var styles webhooks.SeverityStyles
+	var org models.Organization
+	if err := w.DB.Where("clerk_org_id = ?", orgID).First(&org).Error; err == nil {
+		if parsed, err := webhooks.ParseSeverityStyles(org.WebhookSeverityStyles); err == nil {
+			styles = parsed
+		} else {
+			fmt.Printf("Ignoring webhook severity styles for org %s: %v\n", orgID, err)
+		}
+	}
+
+	// Stable per org+policy+resource+day so restarts don't re-notify the same violation
+	dedupKey := webhooks.DedupKey(orgID, policy.ID, violation.ResourceID, violation.CreatedAt)
+
+	for _, webhook := range hooks {
+		if !webhooks.Subscribed(webhook, webhooks.EventPolicyViolation) {
+			continue
+		}
+
+		payload := w.formatWebhookPayload(webhook.Type, policy, violation, styles)
 		if payload == nil {
 			fmt.Printf("Unknown webhook type: %s\n", webhook.Type)
 			continue
 		}
 
-		if err := w.sendWebhookRequest(webhook.URL, payload); err != nil {
+		_, err := webhooks.Deliver(w.DB, webhook, dedupKey, violation.ID, payload)
+		if errors.Is(err, webhooks.ErrAlreadyDelivered) {
+			fmt.Printf("Skipping duplicate webhook notification to %s\n", webhook.URL)
+		} else if err != nil {
 			fmt.Printf("Error sending webhook to %s: %v\n", webhook.URL, err)
 		} else {
 			fmt.Printf("Webhook sent successfully to %s\n", webhook.URL)
@@ -287,18 +777,9 @@ func (w *EnforcementWorker) sendWebhooks(orgID string, violation models.PolicyVi
 	}
 }
 
-func (w *EnforcementWorker) formatWebhookPayload(webhookType string, policy models.Policy, violation models.PolicyViolation) []byte {
-	timestamp := time.Now().Format(time.RFC3339)
-	severityEmoji := map[string]string{
-		"low":      "⚠️",
-		"medium":   "🔶",
-		"high":     "🔴",
-		"critical": "🚨",
-	}
-	emoji := severityEmoji[violation.Severity]
-	if emoji == "" {
-		emoji = "⚠️"
-	}
+func (w *EnforcementWorker) formatWebhookPayload(webhookType string, policy models.Policy, violation models.PolicyViolation, styles webhooks.SeverityStyles) []byte {
+	timestamp := w.Clock.Now().Format(time.RFC3339)
+	emoji := styles.EmojiFor(violation.Severity)
 
 	switch webhookType {
 	case "slack":
@@ -338,7 +819,7 @@ func (w *EnforcementWorker) formatWebhookPayload(webhookType string, policy mode
 					"type": "section",
 					"text": map[string]interface{}{
 						"type": "mrkdwn",
-						"text": fmt.Sprintf("*Message:*\n%s", violation.Message),
+						"text": fmt.Sprintf("*Message:*\n%s", messageWithContributors(violation)),
 					},
 				},
 				{
@@ -346,7 +827,7 @@ func (w *EnforcementWorker) formatWebhookPayload(webhookType string, policy mode
 					"elements": []map[string]interface{}{
 						{
 							"type": "mrkdwn",
-							"text": fmt.Sprintf("Violation ID: %s | Created: %s", violation.ID, timestamp),
+							"text": fmt.Sprintf("Violation ID: %s | Request ID: %s | Created: %s", violation.ID, violation.RequestID, timestamp),
 						},
 					},
 				},
@@ -356,22 +837,13 @@ func (w *EnforcementWorker) formatWebhookPayload(webhookType string, policy mode
 		return jsonData
 
 	case "discord":
-		color := map[string]int{
-			"low":      0xFFFF00, // Yellow
-			"medium":  0xFFA500, // Orange
-			"high":    0xFF0000, // Red
-			"critical": 0x8B0000, // Dark Red
-		}
-		colorValue := color[violation.Severity]
-		if colorValue == 0 {
-			colorValue = 0xFFFF00
-		}
+		colorValue := styles.ColorFor(violation.Severity)
 
 		payload := map[string]interface{}{
 			"embeds": []map[string]interface{}{
 				{
 					"title":       fmt.Sprintf("%s Policy Violation Detected", emoji),
-					"description": violation.Message,
+					"description": messageWithContributors(violation),
 					"color":       colorValue,
 					"fields": []map[string]interface{}{
 						{
@@ -400,6 +872,9 @@ func (w *EnforcementWorker) formatWebhookPayload(webhookType string, policy mode
 							"inline": false,
 						},
 					},
+					"footer": map[string]interface{}{
+						"text": fmt.Sprintf("Request ID: %s", violation.RequestID),
+					},
 					"timestamp": timestamp,
 				},
 			},
@@ -408,15 +883,20 @@ func (w *EnforcementWorker) formatWebhookPayload(webhookType string, policy mode
 		return jsonData
 
 	case "teams":
+		themeColor := "FF0000"
+		if color, ok := styles.CustomColorFor(violation.Severity); ok {
+			themeColor = fmt.Sprintf("%06X", color)
+		}
+
 		payload := map[string]interface{}{
 			"@type":      "MessageCard",
 			"@context":   "https://schema.org/extensions",
 			"summary":    fmt.Sprintf("Policy Violation: %s", policy.Name),
-			"themeColor": "FF0000",
+			"themeColor": themeColor,
 			"sections": []map[string]interface{}{
 				{
 					"activityTitle":    fmt.Sprintf("%s Policy Violation Detected", emoji),
-					"activitySubtitle": violation.Message,
+					"activitySubtitle": messageWithContributors(violation),
 					"facts": []map[string]interface{}{
 						{
 							"name":  "Policy",
@@ -438,6 +918,10 @@ func (w *EnforcementWorker) formatWebhookPayload(webhookType string, policy mode
 							"name":  "Violation ID",
 							"value": violation.ID,
 						},
+						{
+							"name":  "Request ID",
+							"value": violation.RequestID,
+						},
 						{
 							"name":  "Timestamp",
 							"value": timestamp,
@@ -468,35 +952,13 @@ func (w *EnforcementWorker) formatWebhookPayload(webhookType string, policy mode
 				"status":        violation.Status,
 				"createdAt":     violation.CreatedAt,
 			},
+			"requestId": violation.RequestID,
 			"timestamp": timestamp,
 		}
+		if violation.CostContributors != "" {
+			payload["violation"].(map[string]interface{})["costContributors"] = json.RawMessage(violation.CostContributors)
+		}
 		jsonData, _ := json.Marshal(payload)
 		return jsonData
 	}
 }
-
-func (w *EnforcementWorker) sendWebhookRequest(url string, payload []byte) error {
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(payload))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("webhook returned status code: %d", resp.StatusCode)
-	}
-
-	return nil
-}
-
@@ -0,0 +1,303 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	cloud "finopsbridge/api/internal/cloud_"
+	models "finopsbridge/api/internal/models_"
+	opa "finopsbridge/api/internal/opa_"
+	policygen "finopsbridge/api/internal/policygen_"
+	webhooks "finopsbridge/api/internal/webhooks_"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"gorm.io/gorm"
+)
+
+func TestProcessProviderSkipReasons(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	spend := map[string]interface{}{"monthlySpend": 100.0, "hasData": true}
+
+	tests := []struct {
+		name     string
+		provider stubProvider
+		hold     string
+		want     string
+	}{
+		{"credential error", stubProvider{err: awserr.New("InvalidClientTokenId", "the security token is invalid", nil)}, "", SkipReasonCredentialError},
+		{"wrapped credential error", stubProvider{err: fmt.Errorf("failed to assume role: %w", awserr.New("AccessDenied", "not authorized", nil))}, "", SkipReasonCredentialError},
+		{"billing error", stubProvider{err: errors.New("dial tcp: i/o timeout")}, "", SkipReasonBillingError},
+		{"paused", stubProvider{billing: spend}, SkipReasonEnforcementPaused, SkipReasonEnforcementPaused},
+		{"cooldown", stubProvider{billing: spend}, SkipReasonRemediationCooldown, SkipReasonRemediationCooldown},
+		{"quiet hours", stubProvider{billing: spend}, SkipReasonQuietHours, SkipReasonQuietHours},
+		{"not held", stubProvider{billing: spend}, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := testWorker(t, nil, now)
+			provider := stubProviderOfType(t, tt.provider)
+
+			skip, _ := w.processProvider(context.Background(), provider, nil, tt.hold)
+			got := ""
+			if skip != nil {
+				got = skip.Reason
+				if skip.ProviderID != provider.ID || skip.Detail == "" {
+					t.Errorf("skip should name the provider and explain itself, got %+v", skip)
+				}
+			}
+			if got != tt.want {
+				t.Errorf("skip reason %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessProviderNeverRemediatesPausedOrganization(t *testing.T) {
+	engine, err := opa.Initialize(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy := models.Policy{ID: "p1", OrganizationID: "org", Name: "Spend", Type: "max_spend", Enabled: true, Config: `{"maxAmount": 100}`}
+	rego, err := policygen.GenerateRego(policy.Type, map[string]interface{}{"maxAmount": 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.SavePolicy(policy.ID, rego, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, hold := range []string{SkipReasonEnforcementPaused, ""} {
+		w := testWorker(t, nil, time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC))
+		w.OPA = engine
+		affectRows(t, w.DB)
+		stubPendingViolation(t, w.DB, models.PolicyViolation{ResourceID: "provider-other"})
+		var created []models.PolicyViolation
+		w.DB.Callback().Create().Before("gorm:create").Register("test:record_violations", func(db *gorm.DB) {
+			if violation, ok := db.Statement.Dest.(*models.PolicyViolation); ok {
+				created = append(created, *violation)
+			}
+		})
+		log := &remediationLog{}
+		provider := stubProviderOfType(t, stubProvider{billing: map[string]interface{}{"monthlySpend": 500.0, "hasData": true}, log: log})
+
+		w.processProvider(context.Background(), provider, []models.Policy{policy}, hold)
+
+		if len(created) != 1 || created[0].ResourceID != provider.ID {
+			t.Fatalf("hold %q: violations = %+v, want one for the provider", hold, created)
+		}
+		if hold == "" {
+			if calls := log.list(); len(calls) != 1 || calls[0] != "stop "+provider.ID {
+				t.Errorf("unpaused remediation calls = %v, want the provider stopped", calls)
+			}
+			continue
+		}
+		if calls := log.list(); len(calls) != 0 {
+			t.Errorf("paused organization was remediated: %v", calls)
+		}
+	}
+}
+
+func TestProcessProviderUnsupportedType(t *testing.T) {
+	w := testWorker(t, nil, time.Now())
+	provider := stubProviderOfType(t, stubProvider{})
+	provider.Type = "carrier-pigeon"
+
+	skip, _ := w.processProvider(context.Background(), provider, nil, "")
+	if skip == nil || skip.Reason != SkipReasonUnsupportedProvider || skip.Detail != "carrier-pigeon" {
+		t.Errorf("got %+v, want an unsupported_provider skip naming the type", skip)
+	}
+}
+
+func TestProcessProviderBreakerTrippedDuringRun(t *testing.T) {
+	w := testWorker(t, nil, time.Now())
+	provider := stubProviderOfType(t, stubProvider{billing: map[string]interface{}{"monthlySpend": 1.0}})
+	w.breaker.tripped[provider.OrganizationID] = true
+
+	skip, _ := w.processProvider(context.Background(), provider, nil, "")
+	if skip == nil || skip.Reason != SkipReasonRemediationCooldown {
+		t.Errorf("got %+v, want a remediation_cooldown skip once the breaker tripped", skip)
+	}
+}
+
+func TestBuildPolicyInputMatchesSchema(t *testing.T) {
+	provider := models.CloudProvider{Type: "aws", AccountID: "123456789012", MonthlySpend: 120}
+	billingData := map[string]interface{}{"monthlySpend": 150.0, "currency": "USD", "hasData": true}
+	// Halfway through March
+	now := time.Date(2026, 3, 16, 12, 0, 0, 0, time.UTC)
+
+	input := buildPolicyInput(provider, billingData, now)
+
+	documented := make(map[string]bool)
+	for _, field := range policygen.CommonInputFields {
+		documented[field.Name] = true
+	}
+	for key := range input {
+		if !documented[key] {
+			t.Errorf("input field %s isn't in policygen.CommonInputFields", key)
+		}
+	}
+	for key := range documented {
+		if _, ok := input[key]; !ok {
+			t.Errorf("documented field %s is missing from the input", key)
+		}
+	}
+	if input["monthlySpend"] != 150.0 || input["account_id"] != "123456789012" {
+		t.Errorf("got %v", input)
+	}
+}
+
+func TestBuildPolicyInputWithoutBillingData(t *testing.T) {
+	provider := models.CloudProvider{Type: "aws", MonthlySpend: 120}
+	input := buildPolicyInput(provider, map[string]interface{}{"monthlySpend": 0.0, "hasData": false}, time.Date(2026, 3, 16, 12, 0, 0, 0, time.UTC))
+
+	if input["monthlySpend"] != 120.0 {
+		t.Errorf("monthlySpend = %v, want the last known 120 while billing data lags", input["monthlySpend"])
+	}
+	if projected, _ := input["projected_monthly_spend"].(float64); projected <= 120 {
+		t.Errorf("projected_monthly_spend = %v, want the known spend prorated to the month", projected)
+	}
+}
+
+func TestPolicyInputsFromSnapshot(t *testing.T) {
+	w := testWorker(t, nil, time.Date(2026, 5, 20, 12, 0, 0, 0, time.UTC))
+	var queries []string
+	w.DB.Callback().Query().After("gorm:query").Register("test:record", func(tx *gorm.DB) {
+		queries = append(queries, tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...))
+	})
+	provider := models.CloudProvider{ID: "p1", Type: "aws", MonthlySpend: 900}
+	// Through the 15th of a 30-day month
+	snapshot := models.SpendSnapshot{ProviderID: "p1", Date: time.Date(2026, 4, 15, 0, 0, 0, 0, time.UTC), MonthToDateSpend: 500, Currency: "EUR"}
+
+	input, ok := w.policyInputs().FromSnapshot(models.Policy{Type: "max_spend"}, provider, snapshot)
+	if !ok {
+		t.Fatal("a max_spend input needs no history")
+	}
+	if input["monthly_spend"] != 500.0 || input["monthlySpend"] != 500.0 || input["currency"] != "EUR" {
+		t.Errorf("got %v, want the snapshot's spend rather than the provider's current spend", input)
+	}
+	if fraction, _ := input["month_elapsed_fraction"].(float64); math.Abs(fraction-0.5) > 1e-6 {
+		t.Errorf("month_elapsed_fraction = %v, want 0.5 at the end of April 15th", fraction)
+	}
+	if projected, _ := input["projected_monthly_spend"].(float64); math.Abs(projected-1000) > 1e-6 {
+		t.Errorf("projected_monthly_spend = %v, want 1000", projected)
+	}
+
+	// An anomaly baseline only reads snapshots up to the snapshot's day
+	if _, ok := w.policyInputs().FromSnapshot(models.Policy{Type: "anomaly_detection", OrganizationID: "org", Config: `{}`}, provider, snapshot); ok {
+		t.Error("there's no baseline without history")
+	}
+	if len(queries) != 1 || !strings.Contains(queries[0], "date >= '2026-04-07 00:00:00' AND date < '2026-04-16 00:00:00'") {
+		t.Errorf("queries = %q", queries)
+	}
+}
+
+func TestSaveRunRecordsDegradedProviders(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	w := testWorker(t, nil, now)
+	w.actions["org"] = &actionCounts{succeeded: 2, failed: 1}
+
+	run := &models.EnforcementRun{OrganizationID: "org"}
+	w.saveRun(run, nil, []models.SkippedProvider{{ProviderID: "p1", Reason: "remediation_failed"}})
+
+	if !run.Degraded || run.CompletedAt == nil || !run.CompletedAt.Equal(now) {
+		t.Errorf("got %+v", run)
+	}
+	if run.Skipped != "[]" {
+		t.Errorf("Skipped = %s, want an empty list", run.Skipped)
+	}
+	if run.DegradedProviders != `[{"providerId":"p1","providerName":"","reason":"remediation_failed"}]` {
+		t.Errorf("DegradedProviders = %s", run.DegradedProviders)
+	}
+	if run.ActionsSucceeded != 2 || run.ActionsFailed != 1 {
+		t.Errorf("actions = %d succeeded, %d failed", run.ActionsSucceeded, run.ActionsFailed)
+	}
+
+	clean := &models.EnforcementRun{OrganizationID: "other"}
+	w.saveRun(clean, nil, nil)
+	if clean.Degraded || clean.DegradedProviders != "[]" {
+		t.Errorf("a run without degraded providers got %+v", clean)
+	}
+}
+
+func TestTallyActions(t *testing.T) {
+	w := testWorker(t, nil, time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC))
+
+	w.tallyActions("org", cloud.RemediationResult{
+		Succeeded: []cloud.RemediationCandidate{{ResourceID: "i-1"}, {ResourceID: "i-2"}},
+		Failed:    []cloud.ResourceError{{RemediationCandidate: cloud.RemediationCandidate{ResourceID: "i-3"}, Error: "denied"}},
+	})
+	w.tallyActions("org", cloud.RemediationResult{
+		Succeeded: []cloud.RemediationCandidate{{ResourceID: "i-4"}},
+	})
+
+	counts := w.actions["org"]
+	if counts == nil || counts.succeeded != 3 || counts.failed != 1 {
+		t.Errorf("counts = %+v, want 3 succeeded and 1 failed", counts)
+	}
+	if _, ok := w.actions["other-org"]; ok {
+		t.Error("other organizations should have no counts")
+	}
+}
+
+func TestFormatWebhookPayloadIncludesRequestID(t *testing.T) {
+	w := testWorker(t, nil, time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC))
+	policy := models.Policy{ID: "p1", Name: "Max spend"}
+	violation := models.PolicyViolation{ID: "v1", Severity: "high", Status: "pending", RequestID: "run-7"}
+
+	var slack struct {
+		Blocks []struct {
+			Type     string
+			Elements []struct{ Text string }
+		}
+	}
+	if err := json.Unmarshal(w.formatWebhookPayload("slack", policy, violation, webhooks.SeverityStyles{}), &slack); err != nil {
+		t.Fatal(err)
+	}
+	last := slack.Blocks[len(slack.Blocks)-1]
+	if last.Type != "context" || !strings.Contains(last.Elements[0].Text, "Request ID: run-7") {
+		t.Errorf("slack context block = %+v", last)
+	}
+
+	var discord struct {
+		Embeds []struct {
+			Footer struct{ Text string }
+		}
+	}
+	if err := json.Unmarshal(w.formatWebhookPayload("discord", policy, violation, webhooks.SeverityStyles{}), &discord); err != nil {
+		t.Fatal(err)
+	}
+	if discord.Embeds[0].Footer.Text != "Request ID: run-7" {
+		t.Errorf("discord footer = %q", discord.Embeds[0].Footer.Text)
+	}
+
+	var teams struct {
+		Sections []struct {
+			Facts []struct{ Name, Value string }
+		}
+	}
+	if err := json.Unmarshal(w.formatWebhookPayload("teams", policy, violation, webhooks.SeverityStyles{}), &teams); err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, fact := range teams.Sections[0].Facts {
+		found = found || (fact.Name == "Request ID" && fact.Value == "run-7")
+	}
+	if !found {
+		t.Errorf("teams facts = %+v", teams.Sections[0].Facts)
+	}
+
+	var generic map[string]interface{}
+	if err := json.Unmarshal(w.formatWebhookPayload("generic", policy, violation, webhooks.SeverityStyles{}), &generic); err != nil {
+		t.Fatal(err)
+	}
+	if generic["requestId"] != "run-7" {
+		t.Errorf("generic requestId = %v", generic["requestId"])
+	}
+}
@@ -0,0 +1,113 @@
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+	webhooks "finopsbridge/api/internal/webhooks_"
+)
+
+var nextSeverity = map[string]string{
+	"low":      "medium",
+	"medium":   "high",
+	"high":     "critical",
+	"critical": "critical",
+}
+
+// escalationSLA returns how long a policy's violations may stay pending before escalating,
+// from the escalationSlaHours config. Policies without one never escalate.
+func escalationSLA(policy models.Policy) (time.Duration, bool) {
+	var policyConfig map[string]interface{}
+	if err := json.Unmarshal([]byte(policy.Config), &policyConfig); err != nil {
+		return 0, false
+	}
+	hours, ok := policyConfig["escalationSlaHours"].(float64)
+	if !ok || hours <= 0 {
+		return 0, false
+	}
+	return time.Duration(hours * float64(time.Hour)), true
+}
+
+// escalationDue reports whether a violation should escalate: it is still pending, hasn't
+// escalated before, and is older than the SLA
+func escalationDue(violation models.PolicyViolation, sla time.Duration, now time.Time) bool {
+	return violation.Status == "pending" && violation.EscalatedAt == nil && !violation.CreatedAt.Add(sla).After(now)
+}
+
+// escalateViolations raises the severity of pending violations that outlived their policy's
+// SLA and notifies webhooks subscribed to escalations. Each violation escalates at most once.
+func (w *EnforcementWorker) escalateViolations(policies []models.Policy, now time.Time) {
+	for _, policy := range policies {
+		sla, ok := escalationSLA(policy)
+		if !ok {
+			continue
+		}
+
+		var violations []models.PolicyViolation
+		if err := w.DB.Where("policy_id = ? AND status = ? AND escalated_at IS NULL AND created_at <= ?",
+			policy.ID, "pending", now.Add(-sla)).Find(&violations).Error; err != nil {
+			fmt.Printf("Error fetching violations to escalate for policy %s: %v\n", policy.Name, err)
+			continue
+		}
+
+		for _, violation := range violations {
+			if escalationDue(violation, sla, now) {
+				w.escalate(policy, violation, sla, now)
+			}
+		}
+	}
+}
+
+func (w *EnforcementWorker) escalate(policy models.Policy, violation models.PolicyViolation, sla time.Duration, now time.Time) {
+	severity, ok := nextSeverity[violation.Severity]
+	if !ok {
+		severity = "high"
+	}
+
+	// Conditional on escalated_at so concurrent workers escalate and notify only once
+	result := w.DB.Model(&models.PolicyViolation{}).
+		Where("id = ? AND escalated_at IS NULL", violation.ID).
+		Updates(map[string]interface{}{
+			"severity":     severity,
+			"escalated_at": now,
+		})
+	if result.Error != nil {
+		fmt.Printf("Error escalating violation %s: %v\n", violation.ID, result.Error)
+		return
+	}
+	if result.RowsAffected == 0 {
+		return
+	}
+
+	message := fmt.Sprintf("Policy '%s' violation has been pending for more than %v: %s", policy.Name, sla, violation.Message)
+	w.DB.Create(&models.ActivityLog{
+		OrganizationID: policy.OrganizationID,
+		RequestID:      w.runID,
+		Type:           "violation_escalated",
+		Message:        message,
+		Metadata: fmt.Sprintf(`{"policyId":"%s","violationId":"%s","previousSeverity":"%s","severity":"%s"}`,
+			policy.ID, violation.ID, violation.Severity, severity),
+	})
+
+	webhooks.NotifyEvent(w.DB, policy.OrganizationID, webhooks.Event{
+		Type:      webhooks.EventViolationEscalated,
+		Title:     "Policy Violation Escalated",
+		Message:   message,
+		RequestID: w.runID,
+		Fields: []webhooks.EventField{
+			{Name: "Policy", Value: policy.Name},
+			{Name: "Severity", Value: fmt.Sprintf("%s → %s", violation.Severity, severity)},
+			{Name: "Cloud Provider", Value: violation.CloudProvider},
+			{Name: "Pending Since", Value: violation.CreatedAt.Format(time.RFC3339)},
+		},
+		Data: map[string]interface{}{
+			"policyId":         policy.ID,
+			"violationId":      violation.ID,
+			"previousSeverity": violation.Severity,
+			"severity":         severity,
+			"createdAt":        violation.CreatedAt,
+		},
+	})
+}
@@ -0,0 +1,54 @@
+package worker
+
+import (
+	"testing"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+)
+
+func TestEscalationSLA(t *testing.T) {
+	tests := []struct {
+		config string
+		want   time.Duration
+		ok     bool
+	}{
+		{`{"escalationSlaHours": 24}`, 24 * time.Hour, true},
+		{`{"escalationSlaHours": 1.5}`, 90 * time.Minute, true},
+		{`{"escalationSlaHours": 0}`, 0, false},
+		{`{"escalationSlaHours": "24"}`, 0, false},
+		{`{}`, 0, false},
+		{`not json`, 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := escalationSLA(models.Policy{Config: tt.config})
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("escalationSLA(%s) = %v, %v, want %v, %v", tt.config, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestEscalationDue(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	created := now.Add(-24 * time.Hour)
+	escalated := now.Add(-time.Hour)
+
+	tests := []struct {
+		name      string
+		violation models.PolicyViolation
+		want      bool
+	}{
+		{"pending past SLA", models.PolicyViolation{Status: "pending", CreatedAt: created}, true},
+		{"pending exactly at SLA", models.PolicyViolation{Status: "pending", CreatedAt: now.Add(-12 * time.Hour)}, true},
+		{"pending within SLA", models.PolicyViolation{Status: "pending", CreatedAt: now.Add(-time.Hour)}, false},
+		{"already escalated", models.PolicyViolation{Status: "pending", CreatedAt: created, EscalatedAt: &escalated}, false},
+		{"resolved", models.PolicyViolation{Status: "resolved", CreatedAt: created}, false},
+	}
+
+	for _, tt := range tests {
+		if got := escalationDue(tt.violation, 12*time.Hour, now); got != tt.want {
+			t.Errorf("%s: escalationDue = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	cloud "finopsbridge/api/internal/cloud_"
+	models "finopsbridge/api/internal/models_"
+)
+
+// ResourceTypeGPUInstance marks violations of GPU policies, recorded against one instance
+const ResourceTypeGPUInstance = "gpu_instance"
+
+// gpuLookback is how far back GPU samples are read when building per-instance inputs
+const gpuLookback = 24 * time.Hour
+
+// defaultGPUIdleThreshold is the utilization below which a sample counts as idle when the
+// policy config doesn't set idleThresholdPercent
+const defaultGPUIdleThreshold = 10.0
+
+// gpuInstance is a GPU instance seen in recent metrics, with what's needed to stop it
+type gpuInstance struct {
+	CloudProvider string
+	InstanceID    string
+	Zone          string
+	ProviderID    string // Set when the metrics name the connected provider they came from
+}
+
+// gpuMetadata holds the GPUMetrics metadata fields the GPU policies read
+type gpuMetadata struct {
+	Environment        string `json:"environment"`
+	Zone               string `json:"zone"`
+	AvailabilityZone   string `json:"availability_zone"`
+	ProviderID         string `json:"providerId"`
+	TimeSlicingEnabled bool   `json:"timeSlicingEnabled"`
+	WorkloadCount      int    `json:"workloadCount"`
+}
+
+func parseGPUMetadata(raw string) gpuMetadata {
+	var metadata gpuMetadata
+	if raw != "" {
+		json.Unmarshal([]byte(raw), &metadata)
+	}
+	if metadata.Zone == "" {
+		metadata.Zone = metadata.AvailabilityZone
+	}
+	return metadata
+}
+
+// gpuIdleMinutes returns how long an instance has been continuously below the idle threshold,
+// from samples ordered newest first. Each sample covers its IntervalSeconds, or the gap to the
+// next older sample when that isn't set.
+func gpuIdleMinutes(samples []models.GPUMetrics, threshold float64) int {
+	var idle time.Duration
+	for i, sample := range samples {
+		if sample.Utilization >= threshold {
+			break
+		}
+		switch {
+		case sample.IntervalSeconds > 0:
+			idle += time.Duration(sample.IntervalSeconds) * time.Second
+		case i+1 < len(samples):
+			idle += sample.Timestamp.Sub(samples[i+1].Timestamp)
+		}
+	}
+	return int(idle.Minutes())
+}
+
+// gpuInputs builds one input per GPU instance reporting metrics in the last 24 hours, from its
+// latest sample and how long it has been idle. Stopped instances and GPU types outside the
+// config's includedGPUTypes are skipped.
+func (w *EnforcementWorker) gpuInputs(orgID string, policyConfig map[string]interface{}, now time.Time) ([]aiPolicyInput, error) {
+	var samples []models.GPUMetrics
+	if err := w.DB.Where("organization_id = ? AND timestamp >= ?", orgID, now.Add(-gpuLookback)).
+		Order("timestamp DESC").
+		Find(&samples).Error; err != nil {
+		return nil, err
+	}
+
+	threshold := defaultGPUIdleThreshold
+	if value, ok := policyConfig["idleThresholdPercent"].(float64); ok {
+		threshold = value
+	}
+	includedTypes := make(map[string]bool)
+	for _, gpuType := range stringList(policyConfig["includedGPUTypes"]) {
+		includedTypes[gpuType] = true
+	}
+
+	// Group samples per instance, keeping the newest-first order
+	var keys []string
+	byInstance := make(map[string][]models.GPUMetrics)
+	for _, sample := range samples {
+		key := sample.CloudProvider + "/" + sample.InstanceID
+		if _, ok := byInstance[key]; !ok {
+			keys = append(keys, key)
+		}
+		byInstance[key] = append(byInstance[key], sample)
+	}
+
+	inputs := make([]aiPolicyInput, 0, len(keys))
+	for _, key := range keys {
+		instanceSamples := byInstance[key]
+		latest := instanceSamples[0]
+		if latest.Status == "stopped" {
+			continue
+		}
+		if len(includedTypes) > 0 && !includedTypes[latest.GPUType] {
+			continue
+		}
+
+		metadata := parseGPUMetadata(latest.Metadata)
+		workloadCount := metadata.WorkloadCount
+		if workloadCount == 0 {
+			workloads := make(map[string]bool)
+			for _, sample := range instanceSamples {
+				if sample.AIWorkloadID != "" {
+					workloads[sample.AIWorkloadID] = true
+				}
+			}
+			workloadCount = len(workloads)
+		}
+
+		inputs = append(inputs, aiPolicyInput{
+			resourceID:    latest.InstanceID,
+			resourceType:  ResourceTypeGPUInstance,
+			cloudProvider: latest.CloudProvider,
+			input: map[string]interface{}{
+				"gpu": map[string]interface{}{
+					"instanceId":         latest.InstanceID,
+					"instanceType":       latest.InstanceType,
+					"type":               latest.GPUType,
+					"gpuCount":           latest.GPUCount,
+					"provider":           latest.CloudProvider,
+					"utilization":        latest.Utilization,
+					"idleMinutes":        gpuIdleMinutes(instanceSamples, threshold),
+					"hourlyCost":         latest.HourlyCost,
+					"environment":        metadata.Environment,
+					"timeSlicingEnabled": metadata.TimeSlicingEnabled,
+					"workloadCount":      workloadCount,
+				},
+				"config": policyConfig,
+			},
+			gpu: &gpuInstance{
+				CloudProvider: latest.CloudProvider,
+				InstanceID:    latest.InstanceID,
+				Zone:          metadata.Zone,
+				ProviderID:    metadata.ProviderID,
+			},
+		})
+	}
+	return inputs, nil
+}
+
+// autoStopGPU reports whether a gpu_idle_detection policy opted in to stopping idle instances
+func autoStopGPU(policyConfig map[string]interface{}) bool {
+	autoStop, _ := policyConfig["autoStop"].(bool)
+	return autoStop
+}
+
+// stopIdleGPU stops an idle GPU instance through the org's connected provider of the same type.
+// When the org has several, the metrics must name one with a providerId in their metadata. It
+// returns false when the stop was held back or failed and should be retried.
+func (w *EnforcementWorker) stopIdleGPU(ctx context.Context, policy models.Policy, policyConfig map[string]interface{}, instance gpuInstance, violation models.PolicyViolation, paused bool) bool {
+	if w.remediationHeld(policy, violation, paused) {
+		return false
+	}
+	if w.skipMonitored(policy, violation) {
+		return true
+	}
+
+	query := w.DB.Where("organization_id = ? AND type = ? AND status = ?", policy.OrganizationID, instance.CloudProvider, "connected")
+	if instance.ProviderID != "" {
+		query = query.Where("id = ?", instance.ProviderID)
+	}
+	var providers []models.CloudProvider
+	if err := query.Find(&providers).Error; err != nil {
+		fmt.Printf("Error fetching cloud providers: %v\n", err)
+		return false
+	}
+	if len(providers) != 1 {
+		fmt.Printf("Not stopping GPU instance %s: found %d connected %s providers\n", instance.InstanceID, len(providers), instance.CloudProvider)
+		return false
+	}
+
+	protected, err := w.protectedResourceIDs(policy.OrganizationID, providers[0].Type)
+	if err != nil {
+		fmt.Printf("Not stopping GPU instance %s: %v\n", instance.InstanceID, err)
+		return false
+	}
+	opts := cloud.RemediationOptions{
+		Selector:  cloud.ParseResourceSelector(policyConfig),
+		Protected: protected,
+
+		MinResourceAge: cloud.MinResourceAge(policyConfig),
+		Clock:          w.Clock,
+	}
+	result, err := cloud.StopInstance(ctx, providers[0], w.Config, instance.InstanceID, instance.Zone, violation.Message, opts)
+	w.recordRemediationActions(policy.OrganizationID, result.Attempted())
+	w.tallyActions(policy.OrganizationID, result)
+	if err == nil {
+		err = result.Err()
+	}
+	if err != nil {
+		fmt.Printf("Failed to stop GPU instance %s: %v\n", instance.InstanceID, err)
+		if result.Attempted() > 0 {
+			failedJSON, _ := json.Marshal(result.Failed)
+			violation.ActionsFailed = len(result.Failed)
+			violation.RemediationErrors = string(failedJSON)
+			w.DB.Save(&violation)
+		}
+		return false
+	}
+	if len(result.Succeeded) == 0 {
+		return true
+	}
+
+	now := w.Clock.Now()
+	violation.Status = "remediated"
+	violation.RemediatedAt = &now
+	violation.ActionsSucceeded = len(result.Succeeded)
+	w.DB.Save(&violation)
+	w.recordPolicyRemediated(violation)
+
+	w.DB.Create(&models.ActivityLog{
+		OrganizationID: policy.OrganizationID,
+		RequestID:      w.runID,
+		Type:           "remediation",
+		Message:        fmt.Sprintf("Policy '%s' stopped idle GPU instance %s", policy.Name, instance.InstanceID),
+		Metadata:       fmt.Sprintf(`{"policyId":"%s","violationId":"%s"}`, policy.ID, violation.ID),
+	})
+	return true
+}
@@ -0,0 +1,68 @@
+package worker
+
+import (
+	"testing"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+)
+
+func TestParseGPUMetadata(t *testing.T) {
+	metadata := parseGPUMetadata(`{"environment": "dev", "availability_zone": "us-east-1a", "providerId": "p1", "timeSlicingEnabled": true, "workloadCount": 3}`)
+	want := gpuMetadata{
+		Environment:        "dev",
+		Zone:               "us-east-1a",
+		AvailabilityZone:   "us-east-1a",
+		ProviderID:         "p1",
+		TimeSlicingEnabled: true,
+		WorkloadCount:      3,
+	}
+	if metadata != want {
+		t.Errorf("got %+v, want %+v", metadata, want)
+	}
+
+	if got := parseGPUMetadata(`{"zone": "us-central1-a", "availability_zone": "ignored"}`); got.Zone != "us-central1-a" {
+		t.Errorf("zone should win over availability_zone, got %q", got.Zone)
+	}
+	if got := parseGPUMetadata("not json"); got != (gpuMetadata{}) {
+		t.Errorf("invalid metadata should be empty, got %+v", got)
+	}
+}
+
+func TestGPUIdleMinutes(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	sample := func(minutesAgo int, utilization float64, intervalSeconds int) models.GPUMetrics {
+		return models.GPUMetrics{
+			Timestamp:       now.Add(-time.Duration(minutesAgo) * time.Minute),
+			Utilization:     utilization,
+			IntervalSeconds: intervalSeconds,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		samples []models.GPUMetrics
+		want    int
+	}{
+		{"busy now", []models.GPUMetrics{sample(0, 80, 0), sample(5, 1, 0)}, 0},
+		{"gaps between samples", []models.GPUMetrics{sample(0, 2, 0), sample(5, 3, 0), sample(15, 90, 0)}, 15},
+		{"sample intervals", []models.GPUMetrics{sample(0, 2, 300), sample(5, 3, 600), sample(15, 90, 300)}, 15},
+		// The oldest sample has no older one to measure its gap against
+		{"idle since the first sample", []models.GPUMetrics{sample(0, 2, 0), sample(30, 3, 0)}, 30},
+		{"no samples", nil, 0},
+	}
+	for _, tt := range tests {
+		if got := gpuIdleMinutes(tt.samples, 10); got != tt.want {
+			t.Errorf("%s: got %d idle minutes, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAutoStopGPU(t *testing.T) {
+	if autoStopGPU(map[string]interface{}{}) || autoStopGPU(map[string]interface{}{"autoStop": "true"}) {
+		t.Error("autoStop should be off unless set to true")
+	}
+	if !autoStopGPU(map[string]interface{}{"autoStop": true}) {
+		t.Error("autoStop: true should opt in")
+	}
+}
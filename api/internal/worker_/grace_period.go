@@ -0,0 +1,125 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+)
+
+// defaultGracePeriod is how long notifyBeforeStop defers remediation when the policy doesn't
+// set gracePeriod
+const defaultGracePeriod = time.Hour
+
+// gracePeriod returns how long a policy waits between notifying of a violation and remediating
+// it. gracePeriod is a duration string such as "2h" or a number of minutes; notifyBeforeStop
+// alone waits defaultGracePeriod. Zero means remediation runs as soon as the violation is found.
+func gracePeriod(policy models.Policy) time.Duration {
+	var policyConfig map[string]interface{}
+	if err := json.Unmarshal([]byte(policy.Config), &policyConfig); err != nil {
+		return 0
+	}
+
+	switch grace := policyConfig["gracePeriod"].(type) {
+	case string:
+		if d, err := time.ParseDuration(grace); err == nil && d > 0 {
+			return d
+		}
+	case float64:
+		if grace > 0 {
+			return time.Duration(grace * float64(time.Minute))
+		}
+	}
+	if notify, _ := policyConfig["notifyBeforeStop"].(bool); notify {
+		return defaultGracePeriod
+	}
+	return 0
+}
+
+// deferRemediation starts a new violation's grace period when its policy has one, marking the
+// resource pending stop until the deadline. The violation's webhooks are the notification. It
+// returns false when the policy remediates at once, or never because it is in monitor mode.
+func (w *EnforcementWorker) deferRemediation(policy models.Policy, violation *models.PolicyViolation) bool {
+	grace := gracePeriod(policy)
+	if grace <= 0 || policy.Mode == models.PolicyModeMonitor {
+		return false
+	}
+
+	deadline := w.Clock.Now().Add(grace)
+	if err := w.DB.Model(violation).Update("stop_after", deadline).Error; err != nil {
+		fmt.Printf("Error scheduling remediation of violation %s: %v\n", violation.ID, err)
+		return true
+	}
+	violation.StopAfter = &deadline
+
+	w.DB.Create(&models.ActivityLog{
+		OrganizationID: policy.OrganizationID,
+		RequestID:      w.runID,
+		Type:           "remediation_scheduled",
+		Message: fmt.Sprintf("Policy '%s' will remediate %s after %s unless the violation is resolved or ignored first",
+			policy.Name, violation.ResourceID, deadline.UTC().Format(time.RFC3339)),
+		Metadata: fmt.Sprintf(`{"policyId":"%s","violationId":"%s","stopAfter":"%s"}`,
+			policy.ID, violation.ID, deadline.UTC().Format(time.RFC3339)),
+	})
+	return true
+}
+
+// claimExpiredGrace reports whether a violation's grace period has run out while it stayed
+// pending, clearing the deadline so that only one run remediates it. It returns the claimed
+// deadline, which releaseGrace restores when that run doesn't remediate after all.
+func (w *EnforcementWorker) claimExpiredGrace(violation *models.PolicyViolation) (time.Time, bool) {
+	if violation.StopAfter == nil || violation.StopAfter.After(w.Clock.Now()) {
+		return time.Time{}, false
+	}
+	deadline := *violation.StopAfter
+
+	// Conditional on the deadline so concurrent workers don't both remediate
+	result := w.DB.Model(&models.PolicyViolation{}).
+		Where("id = ? AND status = ? AND stop_after IS NOT NULL", violation.ID, "pending").
+		Update("stop_after", nil)
+	if result.Error != nil {
+		fmt.Printf("Error claiming violation %s for remediation: %v\n", violation.ID, result.Error)
+		return time.Time{}, false
+	}
+	if result.RowsAffected == 0 {
+		return time.Time{}, false
+	}
+	violation.StopAfter = nil
+	return deadline, true
+}
+
+// releaseGrace hands back a claim whose remediation was skipped or failed, restoring the
+// deadline so that a later run tries again. A violation resolved or ignored meanwhile stays so.
+func (w *EnforcementWorker) releaseGrace(violation *models.PolicyViolation, deadline time.Time) {
+	result := w.DB.Model(&models.PolicyViolation{}).
+		Where("id = ? AND status = ? AND stop_after IS NULL", violation.ID, "pending").
+		Update("stop_after", deadline)
+	if result.Error != nil {
+		fmt.Printf("Error releasing violation %s for a later remediation: %v\n", violation.ID, result.Error)
+		return
+	}
+	if result.RowsAffected > 0 {
+		violation.StopAfter = &deadline
+	}
+}
+
+// remediateExpiredGrace remediates a violation whose grace period has run out, if this run
+// claims it. The claim is released when remediation is paused or fails.
+func (w *EnforcementWorker) remediateExpiredGrace(ctx context.Context, policy models.Policy, provider models.CloudProvider, violation models.PolicyViolation, paused bool) error {
+	deadline, claimed := w.claimExpiredGrace(&violation)
+	if !claimed {
+		return nil
+	}
+	if w.remediationHeld(policy, violation, paused) {
+		w.releaseGrace(&violation, deadline)
+		return nil
+	}
+
+	result, err := w.remediate(ctx, policy, provider, violation, false)
+	if err != nil || result.Err() != nil {
+		w.releaseGrace(&violation, deadline)
+	}
+	return err
+}
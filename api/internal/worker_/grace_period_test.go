@@ -0,0 +1,153 @@
+package worker
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+
+	"gorm.io/gorm"
+)
+
+func TestGracePeriod(t *testing.T) {
+	tests := []struct {
+		config string
+		want   time.Duration
+	}{
+		{``, 0},
+		{`{}`, 0},
+		{`{"gracePeriod": "2h"}`, 2 * time.Hour},
+		{`{"gracePeriod": 30}`, 30 * time.Minute},
+		{`{"notifyBeforeStop": true}`, defaultGracePeriod},
+		{`{"notifyBeforeStop": true, "gracePeriod": "15m"}`, 15 * time.Minute},
+		{`{"notifyBeforeStop": true, "gracePeriod": "soon"}`, defaultGracePeriod},
+		{`{"gracePeriod": -5}`, 0},
+	}
+	for _, tt := range tests {
+		if got := gracePeriod(models.Policy{Config: tt.config}); got != tt.want {
+			t.Errorf("gracePeriod(%s) = %s, want %s", tt.config, got, tt.want)
+		}
+	}
+}
+
+func TestDeferRemediation(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	for _, policy := range []models.Policy{
+		{ID: "p1", Config: `{}`},
+		{ID: "p1", Config: `{"gracePeriod": "2h"}`, Mode: models.PolicyModeMonitor},
+	} {
+		w := testWorker(t, nil, now)
+		updates := recordUpdates(t, w.DB)
+		violation := models.PolicyViolation{ID: "v1"}
+		if w.deferRemediation(policy, &violation) || len(*updates) != 0 {
+			t.Errorf("%+v shouldn't defer remediation", policy)
+		}
+	}
+
+	w := testWorker(t, nil, now)
+	updates := recordUpdates(t, w.DB)
+	violation := models.PolicyViolation{ID: "v1", ResourceID: "i-1"}
+	if !w.deferRemediation(models.Policy{ID: "p1", Config: `{"gracePeriod": "2h"}`}, &violation) {
+		t.Fatal("a policy with a grace period should defer remediation")
+	}
+	if violation.StopAfter == nil || !violation.StopAfter.Equal(now.Add(2*time.Hour)) {
+		t.Errorf("StopAfter = %v, want two hours from now", violation.StopAfter)
+	}
+	if len(*updates) != 1 || !strings.Contains((*updates)[0], `"stop_after"='2026-03-02 14:00:00`) {
+		t.Errorf("updates = %v, want the deadline stored", *updates)
+	}
+}
+
+func TestClaimExpiredGrace(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	later, earlier := now.Add(time.Minute), now.Add(-time.Minute)
+
+	w := testWorker(t, nil, now)
+	updates := recordUpdates(t, w.DB)
+	for _, stopAfter := range []*time.Time{nil, &later} {
+		if _, claimed := w.claimExpiredGrace(&models.PolicyViolation{ID: "v1", StopAfter: stopAfter}); claimed {
+			t.Errorf("a violation stopping after %v shouldn't be claimed", stopAfter)
+		}
+	}
+	if len(*updates) != 0 {
+		t.Errorf("unexpired violations shouldn't be updated, got %v", *updates)
+	}
+
+	// The claim is conditional, so a violation another run claimed first isn't claimed again
+	violation := models.PolicyViolation{ID: "v1", StopAfter: &earlier}
+	if _, claimed := w.claimExpiredGrace(&violation); claimed {
+		t.Error("a claim that updates no row shouldn't succeed")
+	}
+	if violation.StopAfter == nil {
+		t.Error("a failed claim should keep the deadline")
+	}
+	if len(*updates) != 1 || !strings.Contains((*updates)[0], "stop_after IS NOT NULL") || !strings.Contains((*updates)[0], `"stop_after"=NULL`) {
+		t.Errorf("updates = %v, want a conditional claim", *updates)
+	}
+
+	w.releaseGrace(&violation, earlier)
+	if len(*updates) != 2 || !strings.Contains((*updates)[1], "stop_after IS NULL") {
+		t.Errorf("updates = %v, want a conditional release", *updates)
+	}
+}
+
+// stubPendingViolation makes violation the pending violation found for its policy, unless the
+// query is scoped to another resource, which finds none
+func stubPendingViolation(t *testing.T, db *gorm.DB, violation models.PolicyViolation) {
+	t.Helper()
+	err := db.Callback().Query().After("gorm:query").Register("test:stub_pending_violation", func(tx *gorm.DB) {
+		dest, ok := tx.Statement.Dest.(*models.PolicyViolation)
+		if !ok {
+			return
+		}
+		for _, v := range tx.Statement.Vars {
+			if id, ok := v.(string); ok && strings.HasPrefix(id, "provider-") && id != violation.ResourceID {
+				tx.AddError(gorm.ErrRecordNotFound)
+				return
+			}
+		}
+		*dest = violation
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHandleViolationScopesGraceToProvider(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	w := testWorker(t, nil, now)
+	affectRows(t, w.DB)
+	log := &remediationLog{}
+	providerA := stubProviderOfType(t, stubProvider{log: log})
+	providerB := providerA
+	providerB.ID = "provider-b"
+	policy := models.Policy{ID: "p1", OrganizationID: "org", Name: "Spend", Type: "max_spend", Config: `{"gracePeriod": "1h"}`}
+
+	// Only provider A's grace period has run out
+	expired := now.Add(-time.Minute)
+	stubPendingViolation(t, w.DB, models.PolicyViolation{ID: "v-a", PolicyID: "p1", ResourceID: providerA.ID, ResourceType: "cloud_provider", Status: "pending", StopAfter: &expired})
+	var created []models.PolicyViolation
+	w.DB.Callback().Create().Before("gorm:create").Register("test:record_violations", func(tx *gorm.DB) {
+		if violation, ok := tx.Statement.Dest.(*models.PolicyViolation); ok {
+			created = append(created, *violation)
+		}
+	})
+
+	for _, provider := range []models.CloudProvider{providerA, providerB} {
+		if err := w.handleViolation(context.Background(), policy, provider, map[string]interface{}{"msg": "over"}, "high", "", false, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := log.list(); !reflect.DeepEqual(got, []string{"stop " + providerA.ID}) {
+		t.Errorf("remediations = %v, want only provider A's", got)
+	}
+	// Provider B's new violation starts its own grace period instead
+	if len(created) != 1 || created[0].ResourceID != "provider-b" {
+		t.Errorf("created %+v, want a violation of provider B", created)
+	}
+}
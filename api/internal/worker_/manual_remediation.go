@@ -0,0 +1,98 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	cloud "finopsbridge/api/internal/cloud_"
+	models "finopsbridge/api/internal/models_"
+	tracing "finopsbridge/api/internal/tracing_"
+
+	"gorm.io/gorm"
+)
+
+// Errors returned by RemediateViolation
+var (
+	ErrViolationNotFound       = errors.New("violation not found")
+	ErrViolationNotPending     = errors.New("only pending violations can be remediated")
+	ErrRemediationNotSupported = errors.New("violation is not against a cloud provider or instance that can be remediated")
+	ErrWebhookDryRun           = errors.New("policies using webhook remediation can't be dry run")
+	ErrPolicyMonitorOnly       = errors.New("the violation's policy is in monitor mode; switch it to enforce to remediate")
+)
+
+// RemediateViolation runs a pending violation's remediation on demand, e.g. for an operator
+// acting while automatic remediation is paused. The pause is deliberately not checked, but
+// the policy's selector, protected resources and the circuit breaker still apply. It runs on
+// a copy of the worker so it doesn't share a scheduled run's per-run state, and returns the
+// violation as it stands afterwards.
+func (w *EnforcementWorker) RemediateViolation(ctx context.Context, orgID, violationID string, dryRun bool) (models.PolicyViolation, cloud.RemediationResult, error) {
+	var result cloud.RemediationResult
+
+	var violation models.PolicyViolation
+	if err := w.DB.Joins("JOIN policies ON policy_violations.policy_id = policies.id").
+		Where("policy_violations.id = ? AND policies.organization_id = ?", violationID, orgID).
+		First(&violation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return violation, result, ErrViolationNotFound
+		}
+		return violation, result, err
+	}
+	if violation.Status != "pending" {
+		return violation, result, ErrViolationNotPending
+	}
+	// Provider violations are raised on the provider itself, instance violations on one of its
+	// instances
+	providerID := violation.ResourceID
+	switch violation.ResourceType {
+	case "cloud_provider":
+	case ResourceTypeInstance:
+		providerID = violation.ProviderID
+	default:
+		return violation, result, ErrRemediationNotSupported
+	}
+	if providerID == "" {
+		return violation, result, ErrRemediationNotSupported
+	}
+
+	var policy models.Policy
+	if err := w.DB.Where("id = ?", violation.PolicyID).First(&policy).Error; err != nil {
+		return violation, result, err
+	}
+	if policy.Mode == models.PolicyModeMonitor && !dryRun {
+		return violation, result, ErrPolicyMonitorOnly
+	}
+	var provider models.CloudProvider
+	if err := w.DB.Where("id = ? AND organization_id = ?", providerID, orgID).First(&provider).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return violation, result, ErrRemediationNotSupported
+		}
+		return violation, result, err
+	}
+
+	runID := tracing.FromContext(ctx)
+	if runID == "" {
+		runID = tracing.NewID()
+	}
+	manual := &EnforcementWorker{
+		DB:      w.DB,
+		OPA:     w.OPA,
+		Config:  w.Config,
+		Clock:   w.Clock,
+		breaker: w.breaker,
+		actions: make(map[string]*actionCounts),
+		runID:   runID,
+	}
+
+	fmt.Printf("Manual remediation of violation %s (dry run: %t)\n", violation.ID, dryRun)
+	result, err := manual.remediate(ctx, policy, provider, violation, dryRun)
+
+	// remediate saves its outcome; return the violation as stored
+	if reloadErr := w.DB.Where("id = ?", violation.ID).First(&violation).Error; reloadErr != nil {
+		fmt.Printf("Error reloading violation %s: %v\n", violation.ID, reloadErr)
+	}
+	if err == nil && !dryRun && w.OrgChanged != nil {
+		w.OrgChanged(orgID)
+	}
+	return violation, result, err
+}
@@ -0,0 +1,55 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+
+	"gorm.io/gorm"
+)
+
+// stubViolation makes every violation the worker's database finds a copy of violation, and
+// every policy a copy of policy
+func stubViolation(t *testing.T, db *gorm.DB, violation models.PolicyViolation, policy models.Policy) {
+	t.Helper()
+	err := db.Callback().Query().After("gorm:query").Register("test:stub_violation", func(tx *gorm.DB) {
+		switch dest := tx.Statement.Dest.(type) {
+		case *models.PolicyViolation:
+			*dest = violation
+		case *models.Policy:
+			*dest = policy
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRemediateViolationRejects(t *testing.T) {
+	tests := []struct {
+		name      string
+		violation models.PolicyViolation
+		want      error
+	}{
+		{"resolved", models.PolicyViolation{ID: "v1", Status: "resolved", ResourceType: "cloud_provider", ResourceID: "p1"}, ErrViolationNotPending},
+		{"budget", models.PolicyViolation{ID: "v1", Status: "pending", ResourceType: "budget", ResourceID: "b1"}, ErrRemediationNotSupported},
+		{"instance without provider", models.PolicyViolation{ID: "v1", Status: "pending", ResourceType: ResourceTypeInstance, ResourceID: "i-1"}, ErrRemediationNotSupported},
+	}
+	for _, tt := range tests {
+		w := testWorker(t, nil, time.Now())
+		stubViolation(t, w.DB, tt.violation, models.Policy{ID: "pol1"})
+		changed := false
+		w.OrgChanged = func(string) { changed = true }
+
+		_, _, err := w.RemediateViolation(context.Background(), "org", "v1", false)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+		if changed {
+			t.Errorf("%s: a rejected remediation shouldn't report the organization as changed", tt.name)
+		}
+	}
+}
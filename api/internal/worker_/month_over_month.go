@@ -0,0 +1,86 @@
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	cloud "finopsbridge/api/internal/cloud_"
+	models "finopsbridge/api/internal/models_"
+
+	"gorm.io/gorm"
+)
+
+// monthOverMonthMinDays reads a month_over_month_growth policy's minDaysElapsed, the days of
+// the month that must pass before its prorated spend is trusted, defaulting to 3
+func monthOverMonthMinDays(policyConfig map[string]interface{}) int {
+	if days, ok := policyConfig["minDaysElapsed"].(float64); ok && days >= 1 {
+		return int(days)
+	}
+	return 3
+}
+
+// notifiesOnly reports whether a policy's violations are notified but never remediated. A
+// month_over_month_growth violation flags a trend with no resource to act on, so it stays
+// pending instead of being reported fixed and raised again by the next run.
+func notifiesOnly(policy models.Policy) bool {
+	return policy.Type == "month_over_month_growth"
+}
+
+// fullMonthSpend estimates a month's total from a month-to-date snapshot, which covers spend
+// through the end of its day. A snapshot from the month's last day is its total as is.
+func fullMonthSpend(snapshot models.SpendSnapshot) float64 {
+	endOfDay := snapshot.Date.UTC().AddDate(0, 0, 1).Add(-time.Nanosecond)
+	return cloud.ProrateMonthToDate(snapshot.MonthToDateSpend, endOfDay)
+}
+
+// monthOverMonthPercent returns how far projected spend is above (or, negative, below) the
+// previous month's, and false when there is no previous spend to compare against
+func monthOverMonthPercent(projected, previous float64) (float64, bool) {
+	if previous <= 0 {
+		return 0, false
+	}
+	return (projected/previous - 1) * 100, true
+}
+
+// addPreviousMonthSpend sets previous_month_spend and month_over_month_percent on a
+// month_over_month_growth policy's input from the provider's last snapshot of the previous
+// month. It returns false early in the month, and when the previous month has no snapshot or
+// its snapshots stop too early in the month to be prorated reliably.
+func (p PolicyInputs) addPreviousMonthSpend(input map[string]interface{}, policy models.Policy, providerID string, now time.Time) bool {
+	var policyConfig map[string]interface{}
+	json.Unmarshal([]byte(policy.Config), &policyConfig)
+	minDays := time.Duration(monthOverMonthMinDays(policyConfig)) * 24 * time.Hour
+
+	now = now.UTC()
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if now.Sub(monthStart) < minDays {
+		return false
+	}
+
+	var snapshot models.SpendSnapshot
+	err := p.DB.Where("provider_id = ? AND date >= ? AND date < ?", providerID, monthStart.AddDate(0, -1, 0), monthStart).
+		Order("date DESC").
+		First(&snapshot).Error
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			fmt.Printf("Error fetching previous month's spend snapshot for policy %s: %v\n", policy.Name, err)
+		}
+		return false
+	}
+	// The snapshot's day has fully elapsed by the end of it
+	if snapshot.Date.UTC().AddDate(0, 0, 1).Sub(monthStart.AddDate(0, -1, 0)) < minDays {
+		return false
+	}
+
+	projected, _ := input["projected_monthly_spend"].(float64)
+	previous := fullMonthSpend(snapshot)
+	percent, ok := monthOverMonthPercent(projected, previous)
+	if !ok {
+		return false
+	}
+	input["previous_month_spend"] = previous
+	input["month_over_month_percent"] = percent
+	return true
+}
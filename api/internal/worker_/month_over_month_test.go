@@ -0,0 +1,102 @@
+package worker
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+
+	"gorm.io/gorm"
+)
+
+func TestMonthOverMonthMinDays(t *testing.T) {
+	tests := []struct {
+		config map[string]interface{}
+		want   int
+	}{
+		{map[string]interface{}{}, 3},
+		{map[string]interface{}{"minDaysElapsed": 7.0}, 7},
+		{map[string]interface{}{"minDaysElapsed": 0.0}, 3},
+		{map[string]interface{}{"minDaysElapsed": "7"}, 3},
+	}
+	for _, tt := range tests {
+		if got := monthOverMonthMinDays(tt.config); got != tt.want {
+			t.Errorf("monthOverMonthMinDays(%v) = %d, want %d", tt.config, got, tt.want)
+		}
+	}
+}
+
+func TestFullMonthSpend(t *testing.T) {
+	// Through the 15th of a 30-day month is half of it
+	midMonth := models.SpendSnapshot{Date: time.Date(2026, 4, 15, 0, 0, 0, 0, time.UTC), MonthToDateSpend: 500}
+	if got := fullMonthSpend(midMonth); math.Abs(got-1000) > 1e-6 {
+		t.Errorf("mid-month = %v, want 1000", got)
+	}
+	lastDay := models.SpendSnapshot{Date: time.Date(2026, 4, 30, 0, 0, 0, 0, time.UTC), MonthToDateSpend: 900}
+	if got := fullMonthSpend(lastDay); math.Abs(got-900) > 1e-6 {
+		t.Errorf("last day = %v, want the month-to-date 900", got)
+	}
+}
+
+func TestMonthOverMonthPercent(t *testing.T) {
+	if got, ok := monthOverMonthPercent(150, 100); !ok || got != 50 {
+		t.Errorf("got %v, %v; want 50, true", got, ok)
+	}
+	if got, ok := monthOverMonthPercent(75, 100); !ok || got != -25 {
+		t.Errorf("got %v, %v; want -25, true", got, ok)
+	}
+	if _, ok := monthOverMonthPercent(100, 0); ok {
+		t.Error("there's nothing to compare against without previous spend")
+	}
+}
+
+// stubSnapshot makes every spend snapshot the worker's database finds a copy of snapshot
+func stubSnapshot(t *testing.T, db *gorm.DB, snapshot models.SpendSnapshot) {
+	t.Helper()
+	err := db.Callback().Query().After("gorm:query").Register("test:stub_snapshot", func(tx *gorm.DB) {
+		if dest, ok := tx.Statement.Dest.(*models.SpendSnapshot); ok {
+			*dest = snapshot
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAddPreviousMonthSpend(t *testing.T) {
+	policy := models.Policy{Name: "Growth", Type: "month_over_month_growth", Config: `{"minDaysElapsed": 5}`}
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	w := testWorker(t, nil, now)
+	stubSnapshot(t, w.DB, models.SpendSnapshot{Date: time.Date(2026, 4, 30, 0, 0, 0, 0, time.UTC), MonthToDateSpend: 1000})
+	input := map[string]interface{}{"projected_monthly_spend": 1200.0}
+	if !w.policyInputs().addPreviousMonthSpend(input, policy, "p1", now) {
+		t.Fatal("the previous month's spend should be added")
+	}
+	if math.Abs(input["previous_month_spend"].(float64)-1000) > 1e-6 || math.Abs(input["month_over_month_percent"].(float64)-20) > 1e-6 {
+		t.Errorf("got %v", input)
+	}
+
+	// Too early in the month
+	early := time.Date(2026, 5, 3, 12, 0, 0, 0, time.UTC)
+	if w.policyInputs().addPreviousMonthSpend(map[string]interface{}{"projected_monthly_spend": 1200.0}, policy, "p1", early) {
+		t.Error("spend 3 days into the month shouldn't be compared with a 5 day minimum")
+	}
+
+	// The previous month's snapshots stop too early to prorate
+	w = testWorker(t, nil, now)
+	stubSnapshot(t, w.DB, models.SpendSnapshot{Date: time.Date(2026, 4, 2, 0, 0, 0, 0, time.UTC), MonthToDateSpend: 50})
+	if w.policyInputs().addPreviousMonthSpend(map[string]interface{}{"projected_monthly_spend": 1200.0}, policy, "p1", now) {
+		t.Error("a snapshot from the 2nd of the previous month shouldn't be prorated")
+	}
+}
+
+func TestNotifiesOnly(t *testing.T) {
+	if !notifiesOnly(models.Policy{Type: "month_over_month_growth"}) {
+		t.Error("month_over_month_growth violations should only notify")
+	}
+	if notifiesOnly(models.Policy{Type: "max_spend"}) {
+		t.Error("max_spend violations should be remediated")
+	}
+}
@@ -0,0 +1,88 @@
+package worker
+
+import (
+	"time"
+
+	cloud "finopsbridge/api/internal/cloud_"
+	config "finopsbridge/api/internal/config_"
+	models "finopsbridge/api/internal/models_"
+
+	"gorm.io/gorm"
+)
+
+// PolicyInputs builds the OPA input documents of provider-level policies, reading the spend
+// history that anomaly and month-over-month policies compare against. The enforcement worker
+// evaluates live billing data with it, and policy backtests replay stored spend snapshots.
+type PolicyInputs struct {
+	DB     *gorm.DB
+	Config *config.Config
+}
+
+// policyInputs returns the worker's input builder
+func (w *EnforcementWorker) policyInputs() PolicyInputs {
+	return PolicyInputs{DB: w.DB, Config: w.Config}
+}
+
+// EvaluatesProviderSpend reports whether a policy is evaluated once per provider against its
+// billing. Resource-scoped, AI, budget and schedule policies are evaluated against other data.
+func EvaluatesProviderSpend(policy models.Policy) bool {
+	return !isResourcePolicy(policy) && !isAIPolicy(policy) && !isBudgetPolicy(policy) && policy.Type != "scheduled_start_stop"
+}
+
+// buildPolicyInput assembles the OPA input document for a provider.
+// Keep in sync with policygen.CommonInputFields, which documents it.
+func buildPolicyInput(provider models.CloudProvider, billingData map[string]interface{}, now time.Time) map[string]interface{} {
+	input := map[string]interface{}{
+		"account_id":      provider.AccountID,
+		"subscription_id": provider.SubscriptionID,
+		"project_id":      provider.ProjectID,
+		"monthly_spend":   provider.MonthlySpend,
+		"provider_type":   provider.Type,
+	}
+
+	// Merge billing data into input
+	for k, v := range billingData {
+		input[k] = v
+	}
+
+	// Month-to-date spend projected to a full month, for comparisons against prior months.
+	// Without billing data, the spend last known for the provider stands in for the fetched zero.
+	monthToDate := provider.MonthlySpend
+	if spend, ok := billingData["monthlySpend"].(float64); ok && cloud.HasBillingData(billingData) {
+		monthToDate = spend
+	} else if ok {
+		input["monthlySpend"] = provider.MonthlySpend
+	}
+	input["month_elapsed_fraction"] = cloud.MonthElapsedFraction(now)
+	input["projected_monthly_spend"] = cloud.ProrateMonthToDate(monthToDate, now)
+
+	return input
+}
+
+// Build returns the input for evaluating a provider-level policy against billing data fetched
+// at now, with the spend baseline of anomaly_detection policies and the previous month's spend
+// of month_over_month_growth policies. It returns false when there isn't enough spend history
+// to compare against yet, or it is too early in the month.
+func (p PolicyInputs) Build(policy models.Policy, provider models.CloudProvider, billingData map[string]interface{}, now time.Time) (map[string]interface{}, bool) {
+	input := buildPolicyInput(provider, billingData, now)
+	if policy.Type == "anomaly_detection" && !p.addSpendBaseline(input, policy, provider.ID, now) {
+		return nil, false
+	}
+	if policy.Type == "month_over_month_growth" && !p.addPreviousMonthSpend(input, policy, provider.ID, now) {
+		return nil, false
+	}
+	return input, true
+}
+
+// FromSnapshot returns the input a policy would have been evaluated against at the end of a
+// stored snapshot's day, with the provider's spend as of the snapshot and history before it
+func (p PolicyInputs) FromSnapshot(policy models.Policy, provider models.CloudProvider, snapshot models.SpendSnapshot) (map[string]interface{}, bool) {
+	provider.MonthlySpend = snapshot.MonthToDateSpend
+	billingData := map[string]interface{}{
+		"monthlySpend": snapshot.MonthToDateSpend,
+		"currency":     snapshot.Currency,
+		"hasData":      true,
+	}
+	endOfDay := snapshot.Date.UTC().AddDate(0, 0, 1).Add(-time.Nanosecond)
+	return p.Build(policy, provider, billingData, endOfDay)
+}
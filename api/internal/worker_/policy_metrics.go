@@ -0,0 +1,45 @@
+package worker
+
+import (
+	cloud "finopsbridge/api/internal/cloud_"
+	models "finopsbridge/api/internal/models_"
+
+	"gorm.io/gorm"
+)
+
+// recordPolicyFired counts a new violation against its policy. Columns are updated in place so
+// the policy's UpdatedAt keeps tracking edits, not enforcement.
+func (w *EnforcementWorker) recordPolicyFired(violation models.PolicyViolation) {
+	w.DB.Model(&models.Policy{}).Where("id = ?", violation.PolicyID).UpdateColumns(map[string]interface{}{
+		"fire_count":    gorm.Expr("fire_count + 1"),
+		"last_fired_at": violation.CreatedAt,
+	})
+}
+
+// recordPolicyRemediated adds a remediated violation's time from detection to remediation to its
+// policy's running total, from which the mean time to remediate is derived
+func (w *EnforcementWorker) recordPolicyRemediated(violation models.PolicyViolation) {
+	if violation.RemediatedAt == nil {
+		return
+	}
+	seconds := violation.RemediatedAt.Sub(violation.CreatedAt).Seconds()
+	if seconds < 0 {
+		seconds = 0
+	}
+	w.DB.Model(&models.Policy{}).Where("id = ?", violation.PolicyID).UpdateColumns(map[string]interface{}{
+		"remediation_count":         gorm.Expr("remediation_count + 1"),
+		"remediation_seconds_total": gorm.Expr("remediation_seconds_total + ?", seconds),
+	})
+}
+
+// recordPolicySavings adds the monthly savings of a remediation's successful actions, the
+// on-demand cost of the instances it terminated, to its policy's running total
+func (w *EnforcementWorker) recordPolicySavings(policyID string, result cloud.RemediationResult) {
+	savings := result.MonthlySavings()
+	if savings <= 0 {
+		return
+	}
+	w.DB.Model(&models.Policy{}).Where("id = ?", policyID).UpdateColumns(map[string]interface{}{
+		"monthly_savings_total": gorm.Expr("monthly_savings_total + ?", savings),
+	})
+}
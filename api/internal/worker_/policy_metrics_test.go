@@ -0,0 +1,81 @@
+package worker
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	cloud "finopsbridge/api/internal/cloud_"
+	models "finopsbridge/api/internal/models_"
+
+	"gorm.io/gorm"
+)
+
+// recordUpdates collects the UPDATE statements run on db, with their parameters inlined
+func recordUpdates(t *testing.T, db *gorm.DB) *[]string {
+	t.Helper()
+	var statements []string
+	err := db.Callback().Update().After("gorm:update").Register("test:record_updates", func(tx *gorm.DB) {
+		statements = append(statements, tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &statements
+}
+
+func TestRecordPolicyFired(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	w := testWorker(t, nil, now)
+	updates := recordUpdates(t, w.DB)
+
+	w.recordPolicyFired(models.PolicyViolation{PolicyID: "p1", CreatedAt: now})
+
+	if len(*updates) != 1 {
+		t.Fatalf("got %d updates, want 1", len(*updates))
+	}
+	sql := (*updates)[0]
+	for _, want := range []string{`"fire_count"=fire_count + 1`, `"last_fired_at"='2026-03-02 12:00:00`, `id = 'p1'`} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("%s\nshould contain %s", sql, want)
+		}
+	}
+	if strings.Contains(sql, "updated_at") {
+		t.Errorf("%s\nshould leave updated_at alone", sql)
+	}
+}
+
+func TestRecordPolicyRemediated(t *testing.T) {
+	created := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	remediated := created.Add(90 * time.Second)
+	w := testWorker(t, nil, remediated)
+	updates := recordUpdates(t, w.DB)
+
+	w.recordPolicyRemediated(models.PolicyViolation{PolicyID: "p1", CreatedAt: created})
+	if len(*updates) != 0 {
+		t.Fatalf("a violation without a remediation time shouldn't be counted, got %v", *updates)
+	}
+
+	w.recordPolicyRemediated(models.PolicyViolation{PolicyID: "p1", CreatedAt: created, RemediatedAt: &remediated})
+	if len(*updates) != 1 {
+		t.Fatalf("got %d updates, want 1", len(*updates))
+	}
+	sql := (*updates)[0]
+	for _, want := range []string{`"remediation_count"=remediation_count + 1`, `"remediation_seconds_total"=remediation_seconds_total + 90`} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("%s\nshould contain %s", sql, want)
+		}
+	}
+}
+
+func TestRecordPolicySavings(t *testing.T) {
+	w := testWorker(t, nil, time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC))
+	updates := recordUpdates(t, w.DB)
+
+	w.recordPolicySavings("p1", cloud.RemediationResult{})
+	w.recordPolicySavings("p1", cloud.RemediationResult{Succeeded: []cloud.RemediationCandidate{{MonthlySavings: 70.5}}})
+
+	if len(*updates) != 1 || !strings.Contains((*updates)[0], `"monthly_savings_total"=monthly_savings_total + 70.5`) {
+		t.Errorf("got %v, want one update adding 70.5", *updates)
+	}
+}
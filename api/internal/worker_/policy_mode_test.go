@@ -0,0 +1,51 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+
+	"gorm.io/gorm"
+)
+
+func TestSkipMonitored(t *testing.T) {
+	w := testWorker(t, nil, time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC))
+	var logged []models.ActivityLog
+	w.DB.Callback().Create().Before("gorm:create").Register("test:record_activity", func(db *gorm.DB) {
+		if entry, ok := db.Statement.Dest.(*models.ActivityLog); ok {
+			logged = append(logged, *entry)
+		}
+	})
+	violation := models.PolicyViolation{ID: "v1"}
+
+	if w.skipMonitored(models.Policy{ID: "p1", Mode: models.PolicyModeEnforce}, violation) {
+		t.Error("an enforced policy should remediate")
+	}
+	if w.skipMonitored(models.Policy{ID: "p1"}, violation) {
+		t.Error("a policy without a mode should remediate")
+	}
+	if len(logged) != 0 {
+		t.Errorf("remediating policies shouldn't log a skip, got %+v", logged)
+	}
+
+	if !w.skipMonitored(models.Policy{ID: "p1", OrganizationID: "org", Name: "Idle GPUs", Mode: models.PolicyModeMonitor}, violation) {
+		t.Error("a monitored policy should skip remediation")
+	}
+	if len(logged) != 1 || logged[0].Type != "remediation_skipped" || logged[0].OrganizationID != "org" || logged[0].RequestID != "test-run" {
+		t.Errorf("activity = %+v, want one remediation_skipped entry", logged)
+	}
+}
+
+func TestRemediateViolationMonitorOnly(t *testing.T) {
+	violation := models.PolicyViolation{ID: "v1", Status: "pending", ResourceType: "cloud_provider", ResourceID: "p1"}
+	policy := models.Policy{ID: "pol1", Mode: models.PolicyModeMonitor}
+
+	w := testWorker(t, nil, time.Now())
+	stubViolation(t, w.DB, violation, policy)
+	if _, _, err := w.RemediateViolation(context.Background(), "org", "v1", false); !errors.Is(err, ErrPolicyMonitorOnly) {
+		t.Errorf("got %v, want ErrPolicyMonitorOnly", err)
+	}
+}
@@ -0,0 +1,48 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+
+	models "finopsbridge/api/internal/models_"
+)
+
+// errPolicyPanicked is wrapped by the error guardPolicy returns for a recovered panic
+var errPolicyPanicked = errors.New("policy panicked")
+
+// guardPolicy runs fn for one policy, recovering a panic so a malformed policy or input can't
+// abort the rest of the run. A panic is recorded on the organization's run and in the
+// activity log, and returned as an error wrapping errPolicyPanicked. providerID is empty for
+// org-wide evaluations.
+func (w *EnforcementWorker) guardPolicy(policy models.Policy, providerID string, fn func() error) (err error) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		err = fmt.Errorf("%w: %v", errPolicyPanicked, r)
+		fmt.Printf("Recovered panic in policy %s: %v\n%s", policy.Name, r, debug.Stack())
+
+		w.mu.Lock()
+		if w.policyErrors == nil {
+			w.policyErrors = make(map[string][]models.PolicyError)
+		}
+		w.policyErrors[policy.OrganizationID] = append(w.policyErrors[policy.OrganizationID], models.PolicyError{
+			PolicyID:   policy.ID,
+			PolicyName: policy.Name,
+			ProviderID: providerID,
+			Error:      fmt.Sprint(r),
+		})
+		w.mu.Unlock()
+
+		w.DB.Create(&models.ActivityLog{
+			OrganizationID: policy.OrganizationID,
+			RequestID:      w.runID,
+			Type:           "policy_error",
+			Message:        fmt.Sprintf("Policy '%s' failed during enforcement and was skipped: %v", policy.Name, r),
+			Metadata:       fmt.Sprintf(`{"policyId":"%s","providerId":"%s"}`, policy.ID, providerID),
+		})
+	}()
+	return fn()
+}
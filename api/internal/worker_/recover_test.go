@@ -0,0 +1,54 @@
+package worker
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+
+	"gorm.io/gorm"
+)
+
+func TestGuardPolicyRecoversPanic(t *testing.T) {
+	w := testWorker(t, nil, time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC))
+	var logged []models.ActivityLog
+	w.DB.Callback().Create().Before("gorm:create").Register("test:record_activity", func(db *gorm.DB) {
+		if entry, ok := db.Statement.Dest.(*models.ActivityLog); ok {
+			logged = append(logged, *entry)
+		}
+	})
+	policy := models.Policy{ID: "p1", OrganizationID: "org", Name: "Broken"}
+
+	err := w.guardPolicy(policy, "provider-1", func() error {
+		var input map[string]interface{}
+		input["monthly_spend"] = 1.0 // assignment to a nil map
+		return nil
+	})
+
+	if !errors.Is(err, errPolicyPanicked) {
+		t.Fatalf("got %v, want an error wrapping errPolicyPanicked", err)
+	}
+	policyErrors := w.policyErrors["org"]
+	if len(policyErrors) != 1 || policyErrors[0].PolicyID != "p1" || policyErrors[0].ProviderID != "provider-1" {
+		t.Errorf("policy errors = %+v", policyErrors)
+	}
+	if len(logged) != 1 || logged[0].Type != "policy_error" || logged[0].RequestID != "test-run" {
+		t.Errorf("activity = %+v, want one policy_error entry", logged)
+	}
+}
+
+func TestGuardPolicyPassesThroughErrors(t *testing.T) {
+	w := testWorker(t, nil, time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC))
+	failure := errors.New("evaluation failed")
+
+	if err := w.guardPolicy(models.Policy{OrganizationID: "org"}, "", func() error { return failure }); err != failure {
+		t.Errorf("got %v, want fn's error unchanged", err)
+	}
+	if err := w.guardPolicy(models.Policy{OrganizationID: "org"}, "", func() error { return nil }); err != nil {
+		t.Errorf("got %v, want nil", err)
+	}
+	if len(w.policyErrors["org"]) != 0 {
+		t.Error("only panics should be recorded as policy errors")
+	}
+}
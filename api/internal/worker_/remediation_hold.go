@@ -0,0 +1,89 @@
+package worker
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+)
+
+// holdDetails explain each reason a provider's remediation was held on its run record
+var holdDetails = map[string]string{
+	SkipReasonEnforcementPaused:   "Policies were evaluated but remediation is paused for the organization",
+	SkipReasonRemediationCooldown: "Policies were evaluated but the remediation circuit breaker paused the organization; resume enforcement to clear it",
+	SkipReasonQuietHours:          "Policies were evaluated but remediation is held during quiet hours",
+}
+
+// remediationHolds maps organizations to the skip reason holding their remediation
+type remediationHolds struct {
+	paused     map[string]string
+	quietHours bool
+}
+
+// reason returns the skip reason holding an org's remediation, or "" when it may remediate.
+// A pause outranks quiet hours, since it lasts until someone resumes enforcement.
+func (h remediationHolds) reason(orgID string) string {
+	if reason, ok := h.paused[orgID]; ok {
+		return reason
+	}
+	if h.quietHours {
+		return SkipReasonQuietHours
+	}
+	return ""
+}
+
+// remediationHolds loads the organizations with enforcement paused, telling a circuit breaker
+// cooldown apart from a manual pause, and whether now falls in the configured quiet hours
+func (w *EnforcementWorker) remediationHolds(now time.Time) (remediationHolds, error) {
+	var orgs []models.Organization
+	if err := w.DB.Select("clerk_org_id", "enforcement_paused_by").
+		Where("enforcement_paused = ?", true).
+		Find(&orgs).Error; err != nil {
+		return remediationHolds{}, err
+	}
+
+	holds := remediationHolds{paused: make(map[string]string, len(orgs))}
+	for _, org := range orgs {
+		holds.paused[org.ClerkOrgID] = SkipReasonEnforcementPaused
+		if org.EnforcementPausedBy == CircuitBreakerActor {
+			holds.paused[org.ClerkOrgID] = SkipReasonRemediationCooldown
+		}
+	}
+
+	if spec := w.Config.EnforcementQuietHours; spec != "" {
+		quiet, err := inQuietHours(spec, now)
+		if err != nil {
+			fmt.Printf("Ignoring ENFORCEMENT_QUIET_HOURS: %v\n", err)
+		}
+		holds.quietHours = quiet
+	}
+
+	return holds, nil
+}
+
+// inQuietHours reports whether now, in UTC, falls in an "HH:MM-HH:MM" window. A window whose
+// end is before its start wraps past midnight, e.g. "22:00-06:00".
+func inQuietHours(spec string, now time.Time) (bool, error) {
+	parts := strings.Split(spec, "-")
+	if len(parts) != 2 {
+		return false, fmt.Errorf("expected HH:MM-HH:MM, got %q", spec)
+	}
+
+	var bounds [2]int
+	for i, part := range parts {
+		t, err := time.Parse("15:04", strings.TrimSpace(part))
+		if err != nil {
+			return false, fmt.Errorf("expected HH:MM-HH:MM, got %q", spec)
+		}
+		bounds[i] = t.Hour()*60 + t.Minute()
+	}
+
+	now = now.UTC()
+	minutes := now.Hour()*60 + now.Minute()
+	start, end := bounds[0], bounds[1]
+	if start <= end {
+		return minutes >= start && minutes < end, nil
+	}
+	return minutes >= start || minutes < end, nil
+}
@@ -0,0 +1,73 @@
+package worker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInQuietHours(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 3, 2, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		spec string
+		at   time.Time
+		want bool
+	}{
+		{"22:00-06:00", at(23, 30), true},
+		{"22:00-06:00", at(2, 0), true},
+		{"22:00-06:00", at(6, 0), false},
+		{"22:00-06:00", at(12, 0), false},
+		{"12:00-13:00", at(12, 30), true},
+		{"12:00-13:00", at(13, 0), false},
+		{" 12:00 - 13:00 ", at(12, 0), true},
+	}
+
+	for _, tt := range tests {
+		got, err := inQuietHours(tt.spec, tt.at)
+		if err != nil {
+			t.Errorf("inQuietHours(%q): %v", tt.spec, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("inQuietHours(%q, %s) = %v, want %v", tt.spec, tt.at.Format("15:04"), got, tt.want)
+		}
+	}
+
+	// Times are compared in UTC whatever their location
+	newYork, _ := time.LoadLocation("America/New_York")
+	if quiet, _ := inQuietHours("22:00-06:00", time.Date(2026, 3, 2, 20, 0, 0, 0, newYork)); !quiet {
+		t.Error("20:00 in New York is 01:00 UTC, inside the quiet hours")
+	}
+
+	for _, spec := range []string{"22:00", "10pm-6am", "22:00-06:00-07:00"} {
+		if _, err := inQuietHours(spec, at(0, 0)); err == nil {
+			t.Errorf("inQuietHours(%q) should fail", spec)
+		}
+	}
+}
+
+func TestRemediationHoldsReason(t *testing.T) {
+	holds := remediationHolds{paused: map[string]string{
+		"paused":  SkipReasonEnforcementPaused,
+		"tripped": SkipReasonRemediationCooldown,
+	}}
+	if got := holds.reason("paused"); got != SkipReasonEnforcementPaused {
+		t.Errorf("paused org: %q", got)
+	}
+	if got := holds.reason("tripped"); got != SkipReasonRemediationCooldown {
+		t.Errorf("org paused by the breaker: %q", got)
+	}
+	if got := holds.reason("other"); got != "" {
+		t.Errorf("unpaused org outside quiet hours: %q", got)
+	}
+
+	holds.quietHours = true
+	if got := holds.reason("other"); got != SkipReasonQuietHours {
+		t.Errorf("unpaused org in quiet hours: %q", got)
+	}
+	if got := holds.reason("paused"); got != SkipReasonEnforcementPaused {
+		t.Errorf("a pause should outrank quiet hours, got %q", got)
+	}
+}
@@ -0,0 +1,173 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	cloud "finopsbridge/api/internal/cloud_"
+	models "finopsbridge/api/internal/models_"
+	tracing "finopsbridge/api/internal/tracing_"
+	webhooks "finopsbridge/api/internal/webhooks_"
+)
+
+// RemediationModeWebhook hands a policy's remediation to the customer's own service instead of
+// the built-in cloud actions
+const RemediationModeWebhook = "webhook"
+
+// defaultRemediationWebhookTimeout is how long the worker waits for a remediation webhook to
+// answer unless the policy sets remediationWebhookTimeoutSeconds
+const defaultRemediationWebhookTimeout = 30 * time.Second
+
+// maxRemediationResponseBytes caps how much of a remediation webhook's response is read
+const maxRemediationResponseBytes = 1 << 20
+
+// remediationWebhookClient calls remediation webhooks. Redirects aren't followed, so the
+// policy's headers only ever reach the URL it names; each request carries its own timeout.
+var remediationWebhookClient = &http.Client{
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// remediationWebhook reads where and how long to call a webhook-mode policy's remediation
+// service. ok is false for policies using the built-in remediation.
+func remediationWebhook(policyConfig map[string]interface{}) (url string, timeout time.Duration, ok bool) {
+	if mode, _ := policyConfig["remediationMode"].(string); mode != RemediationModeWebhook {
+		return "", 0, false
+	}
+	url, _ = policyConfig["remediationWebhookUrl"].(string)
+	timeout = defaultRemediationWebhookTimeout
+	if seconds, set := policyConfig["remediationWebhookTimeoutSeconds"].(float64); set && seconds > 0 {
+		timeout = time.Duration(seconds * float64(time.Second))
+	}
+	return url, timeout, true
+}
+
+// ValidateRemediationWebhook checks a policy config's remediation webhook settings when the
+// policy is saved: a webhook-mode policy needs an https remediationWebhookUrl
+func ValidateRemediationWebhook(policyConfig map[string]interface{}) error {
+	mode, set := policyConfig["remediationMode"]
+	if !set {
+		return nil
+	}
+	if mode, _ := mode.(string); mode != RemediationModeWebhook {
+		return fmt.Errorf("remediationMode must be %q when set", RemediationModeWebhook)
+	}
+	url, _, _ := remediationWebhook(policyConfig)
+	if err := webhooks.Validate(webhooks.TypeGeneric, url); err != nil {
+		return fmt.Errorf("invalid remediationWebhookUrl: %w", err)
+	}
+	if seconds, set := policyConfig["remediationWebhookTimeoutSeconds"]; set {
+		if seconds, ok := seconds.(float64); !ok || seconds <= 0 {
+			return fmt.Errorf("remediationWebhookTimeoutSeconds must be a positive number")
+		}
+	}
+	return nil
+}
+
+// remediationWebhookHeaders returns the extra headers a policy sends to its remediation
+// webhook, stored like a notification webhook's
+func remediationWebhookHeaders(policy models.Policy) map[string]string {
+	var headers map[string]string
+	if policy.RemediationWebhookHeaders != "" {
+		if err := json.Unmarshal([]byte(policy.RemediationWebhookHeaders), &headers); err != nil {
+			fmt.Printf("Error parsing remediation webhook headers of policy %s: %v\n", policy.ID, err)
+		}
+	}
+	return headers
+}
+
+// remediationWebhookPayload is the body posted to a remediation webhook: the violation, the
+// policy that raised it and the provider it concerns, without credentials. The selector and
+// protected resources are passed on so the service can honour them like built-in remediation.
+func (w *EnforcementWorker) remediationWebhookPayload(policy models.Policy, provider models.CloudProvider, violation models.PolicyViolation, policyConfig map[string]interface{}, opts cloud.RemediationOptions) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"event":     "remediation_requested",
+		"requestId": w.runID,
+		"timestamp": w.Clock.Now().Format(time.RFC3339),
+		"policy": map[string]interface{}{
+			"id":     policy.ID,
+			"name":   policy.Name,
+			"type":   policy.Type,
+			"config": policyConfig,
+		},
+		"violation": map[string]interface{}{
+			"id":           violation.ID,
+			"resourceId":   violation.ResourceID,
+			"resourceType": violation.ResourceType,
+			"severity":     violation.Severity,
+			"message":      violation.Message,
+			"createdAt":    violation.CreatedAt,
+		},
+		"resource": map[string]interface{}{
+			"providerId":     provider.ID,
+			"cloudProvider":  provider.Type,
+			"name":           provider.Name,
+			"accountId":      provider.AccountID,
+			"subscriptionId": provider.SubscriptionID,
+			"projectId":      provider.ProjectID,
+			"monthlySpend":   provider.MonthlySpend,
+		},
+		"selector":  opts.Selector,
+		"protected": opts.Protected,
+	})
+}
+
+// remediationWebhookResponse is the reply a remediation webhook must send: remediated true
+// once it fixed the violation, optionally with the actions it took, recorded like built-in
+// remediation
+type remediationWebhookResponse struct {
+	Remediated bool `json:"remediated"`
+	cloud.RemediationResult
+}
+
+// remediateViaWebhook posts a violation to the policy's remediation webhook, with the policy's
+// headers, and waits for its answer. Success needs a 2xx response whose body confirms it with
+// "remediated": true; any other reply leaves the violation pending.
+func (w *EnforcementWorker) remediateViaWebhook(ctx context.Context, url string, timeout time.Duration, headers map[string]string, payload []byte) (cloud.RemediationResult, error) {
+	var result cloud.RemediationResult
+	if err := webhooks.Validate(webhooks.TypeGeneric, url); err != nil {
+		return result, fmt.Errorf("invalid remediation webhook: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return result, fmt.Errorf("failed to create remediation request: %w", err)
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(tracing.Header, w.runID)
+
+	resp, err := remediationWebhookClient.Do(req)
+	if err != nil {
+		return result, fmt.Errorf("remediation webhook failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return result, fmt.Errorf("remediation webhook returned status code: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRemediationResponseBytes))
+	if err != nil {
+		return result, fmt.Errorf("failed to read remediation webhook response: %w", err)
+	}
+	var reply remediationWebhookResponse
+	if err := json.Unmarshal(body, &reply); err != nil {
+		return result, fmt.Errorf("remediation webhook response isn't a JSON object: %w", err)
+	}
+	if !reply.Remediated {
+		return reply.RemediationResult, fmt.Errorf("remediation webhook did not confirm the violation remediated")
+	}
+	return reply.RemediationResult, nil
+}
@@ -0,0 +1,148 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	cloud "finopsbridge/api/internal/cloud_"
+	models "finopsbridge/api/internal/models_"
+	tracing "finopsbridge/api/internal/tracing_"
+)
+
+// remediationServer serves a remediation webhook over TLS and points the webhook client at it
+func remediationServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	server := httptest.NewTLSServer(handler)
+	t.Cleanup(server.Close)
+
+	client := server.Client()
+	client.CheckRedirect = remediationWebhookClient.CheckRedirect
+	previous := remediationWebhookClient
+	remediationWebhookClient = client
+	t.Cleanup(func() { remediationWebhookClient = previous })
+	return server
+}
+
+func TestRemediationWebhookConfig(t *testing.T) {
+	if _, _, ok := remediationWebhook(map[string]interface{}{}); ok {
+		t.Error("policies without remediationMode use the built-in remediation")
+	}
+
+	url, timeout, ok := remediationWebhook(map[string]interface{}{
+		"remediationMode":       "webhook",
+		"remediationWebhookUrl": "https://remediate.example.com/hook",
+	})
+	if !ok || url != "https://remediate.example.com/hook" || timeout != defaultRemediationWebhookTimeout {
+		t.Errorf("got %q, %v, %v", url, timeout, ok)
+	}
+
+	_, timeout, _ = remediationWebhook(map[string]interface{}{
+		"remediationMode":                  "webhook",
+		"remediationWebhookTimeoutSeconds": 2.5,
+	})
+	if timeout != 2500*time.Millisecond {
+		t.Errorf("timeout = %v, want 2.5s", timeout)
+	}
+}
+
+func TestValidateRemediationWebhook(t *testing.T) {
+	tests := []struct {
+		config map[string]interface{}
+		ok     bool
+	}{
+		{map[string]interface{}{}, true},
+		{map[string]interface{}{"remediationMode": "webhook", "remediationWebhookUrl": "https://remediate.example.com/hook"}, true},
+		{map[string]interface{}{"remediationMode": "lambda"}, false},
+		{map[string]interface{}{"remediationMode": "webhook"}, false},
+		{map[string]interface{}{"remediationMode": "webhook", "remediationWebhookUrl": "http://remediate.example.com/hook"}, false},
+		{map[string]interface{}{"remediationMode": "webhook", "remediationWebhookUrl": "https://remediate.example.com/hook", "remediationWebhookTimeoutSeconds": 0.0}, false},
+	}
+	for _, tt := range tests {
+		if err := ValidateRemediationWebhook(tt.config); (err == nil) != tt.ok {
+			t.Errorf("ValidateRemediationWebhook(%v) = %v, want ok=%v", tt.config, err, tt.ok)
+		}
+	}
+}
+
+func TestRemediationWebhookPayloadOmitsCredentials(t *testing.T) {
+	w := testWorker(t, nil, time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC))
+	provider := models.CloudProvider{ID: "p1", Type: "aws", Credentials: `{"secretAccessKey":"s3cr3t"}`}
+
+	payload, err := w.remediationWebhookPayload(models.Policy{ID: "pol"}, provider, models.PolicyViolation{ID: "v1"},
+		map[string]interface{}{}, cloud.RemediationOptions{Protected: []string{"payments-db"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded["event"] != "remediation_requested" || decoded["requestId"] != "test-run" || decoded["timestamp"] != "2026-03-02T12:00:00Z" {
+		t.Errorf("got %v", decoded)
+	}
+	if _, ok := decoded["resource"].(map[string]interface{})["credentials"]; ok {
+		t.Error("the payload must not carry provider credentials")
+	}
+	if protected, _ := decoded["protected"].([]interface{}); len(protected) != 1 {
+		t.Errorf("protected = %v, want the protected resources passed on", decoded["protected"])
+	}
+}
+
+func TestRemediateViaWebhook(t *testing.T) {
+	w := testWorker(t, nil, time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC))
+	server := remediationServer(t, func(rw http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != `{"violation":"v1"}` || r.Header.Get("Authorization") != "Bearer token" || r.Header.Get(tracing.Header) != "test-run" {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		rw.Write([]byte(`{"remediated": true, "succeeded": [{"resourceId": "i-1", "action": "stop"}]}`))
+	})
+
+	result, err := w.remediateViaWebhook(context.Background(), server.URL, time.Second,
+		map[string]string{"Authorization": "Bearer token"}, []byte(`{"violation":"v1"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Succeeded) != 1 || result.Succeeded[0].ResourceID != "i-1" {
+		t.Errorf("got %+v, want the reported actions", result)
+	}
+}
+
+func TestRemediateViaWebhookFailures(t *testing.T) {
+	w := testWorker(t, nil, time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{"not confirmed", func(rw http.ResponseWriter, r *http.Request) { rw.Write([]byte(`{"remediated": false}`)) }},
+		{"not JSON", func(rw http.ResponseWriter, r *http.Request) { rw.Write([]byte(`ok`)) }},
+		{"server error", func(rw http.ResponseWriter, r *http.Request) { rw.WriteHeader(http.StatusInternalServerError) }},
+		{"redirect", func(rw http.ResponseWriter, r *http.Request) {
+			http.Redirect(rw, r, "https://elsewhere.example.com/", http.StatusFound)
+		}},
+		{"timeout", func(rw http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+		}},
+	}
+	for _, tt := range tests {
+		server := remediationServer(t, tt.handler)
+		if _, err := w.remediateViaWebhook(context.Background(), server.URL, 50*time.Millisecond, nil, []byte(`{}`)); err == nil {
+			t.Errorf("%s: want an error", tt.name)
+		}
+	}
+
+	if _, err := w.remediateViaWebhook(context.Background(), "http://remediate.example.com/hook", time.Second, nil, []byte(`{}`)); err == nil {
+		t.Error("a non-https URL should be rejected")
+	}
+}
@@ -0,0 +1,200 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	cloud "finopsbridge/api/internal/cloud_"
+	models "finopsbridge/api/internal/models_"
+	policygen "finopsbridge/api/internal/policygen_"
+)
+
+// ResourceTypeInstance is the resource type of violations raised against a single instance
+const ResourceTypeInstance = "instance"
+
+// isResourcePolicy reports whether a policy is evaluated per instance rather than per provider
+func isResourcePolicy(policy models.Policy) bool {
+	return policygen.IsResourcePolicyType(policy.Type)
+}
+
+// listInstances lists a provider's instances for resource-scoped policies. A provider that
+// can't be listed yields none, so its resource-scoped policies evaluate nothing this run.
+func (w *EnforcementWorker) listInstances(ctx context.Context, provider models.CloudProvider) []cloud.Instance {
+	instances, err := cloud.ListInstances(ctx, provider, w.Config)
+	if errors.Is(err, cloud.ErrInventoryNotSupported) {
+		fmt.Printf("Resource-scoped policies are not evaluated for %s providers\n", provider.Type)
+		return nil
+	}
+	if err != nil {
+		fmt.Printf("Error listing instances for %s: %v\n", provider.Name, err)
+		return nil
+	}
+	return instances
+}
+
+// instanceInput is the provider's policy input with one instance's attributes added.
+// Keep in sync with policygen.ResourceInputFields, which documents it.
+func instanceInput(providerInput map[string]interface{}, instance cloud.Instance) map[string]interface{} {
+	input := make(map[string]interface{}, len(providerInput)+10)
+	for k, v := range providerInput {
+		input[k] = v
+	}
+
+	tags := make(map[string]interface{}, len(instance.Tags))
+	for k, v := range instance.Tags {
+		tags[k] = v
+	}
+
+	input["resource_id"] = instance.ID
+	input["resource_name"] = instance.Name
+	input["resource_type"] = ResourceTypeInstance
+	input["instance_type"] = instance.InstanceType
+	input["instanceType"] = instance.InstanceType
+	input["region"] = instance.Region
+	input["status"] = instance.State
+	input["tags"] = tags
+	if size := cloud.InstanceSizeLevel(instance.InstanceType); size > 0 {
+		input["instance_size"] = size
+	}
+	if !instance.LaunchedAt.IsZero() {
+		input["launched_at"] = instance.LaunchedAt.Format(time.RFC3339)
+	}
+	return input
+}
+
+// evaluateResourcePolicy evaluates a resource-scoped policy once per instance, recording a
+// violation against each instance it flags and resolving the pending violations of instances
+// that now pass. Each violation is remediated on its own instance, at once or when its grace
+// period runs out. One decision is logged for the provider, denied when any instance was flagged.
+func (w *EnforcementWorker) evaluateResourcePolicy(ctx context.Context, policy models.Policy, provider models.CloudProvider, billingData map[string]interface{}, instances []cloud.Instance, paused bool) error {
+	providerInput := buildPolicyInput(provider, billingData, w.Clock.Now())
+
+	var due []models.PolicyViolation
+	var flagged, passed []string
+	for _, instance := range instances {
+		input := instanceInput(providerInput, instance)
+		allowed, result, err := w.OPA.EvaluatePolicy(policy.ID, policy.Config, input)
+		if err != nil {
+			w.logDecision(policy, provider.ID, providerInput, false, err)
+			fmt.Printf("Error evaluating policy %s: %v\n", policy.Name, err)
+			return nil
+		}
+		if allowed {
+			passed = append(passed, instance.ID)
+			continue
+		}
+		flagged = append(flagged, instance.ID)
+
+		violation, created := w.handleInstanceViolation(policy, provider, instance, result, violationSeverity(policy, input, result))
+		if created && !w.deferRemediation(policy, &violation) {
+			due = append(due, violation)
+		}
+	}
+	w.logDecision(policy, provider.ID, providerInput, len(flagged) == 0, nil)
+	w.resolvePassingInstances(policy, provider, passed)
+
+	var errs []error
+	for _, violation := range due {
+		if err := w.remediateUnlessPaused(ctx, policy, provider, violation, paused); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(flagged) > 0 {
+		// Violations recorded before they carried their provider
+		w.DB.Model(&models.PolicyViolation{}).
+			Where("policy_id = ? AND resource_type = ? AND resource_id IN ? AND provider_id = ? AND status = ?",
+				policy.ID, ResourceTypeInstance, flagged, "", "pending").
+			Update("provider_id", provider.ID)
+
+		var expired []models.PolicyViolation
+		if err := w.DB.Where("policy_id = ? AND resource_id IN ? AND status = ? AND stop_after <= ?",
+			policy.ID, flagged, "pending", w.Clock.Now()).Find(&expired).Error; err != nil {
+			fmt.Printf("Error fetching violations due for remediation: %v\n", err)
+		}
+		for _, violation := range expired {
+			if err := w.remediateExpiredGrace(ctx, policy, provider, violation, paused); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// resolvePassingInstances marks the pending violations of instances the policy no longer flags
+// as remediated: the instance was fixed or reconfigured outside of enforcement
+func (w *EnforcementWorker) resolvePassingInstances(policy models.Policy, provider models.CloudProvider, passed []string) {
+	if len(passed) == 0 {
+		return
+	}
+
+	var resolved []models.PolicyViolation
+	if err := w.DB.Where("policy_id = ? AND resource_type = ? AND resource_id IN ? AND provider_id IN ? AND status = ?",
+		policy.ID, ResourceTypeInstance, passed, []string{provider.ID, ""}, "pending").Find(&resolved).Error; err != nil {
+		fmt.Printf("Error fetching violations of passing instances: %v\n", err)
+		return
+	}
+	now := w.Clock.Now()
+	for _, violation := range resolved {
+		result := w.DB.Model(&models.PolicyViolation{}).
+			Where("id = ? AND status = ?", violation.ID, "pending").
+			Updates(map[string]interface{}{"status": "remediated", "remediated_at": now, "stop_after": nil})
+		if result.Error != nil {
+			fmt.Printf("Error resolving violation %s: %v\n", violation.ID, result.Error)
+			continue
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		w.DB.Create(&models.ActivityLog{
+			OrganizationID: policy.OrganizationID,
+			RequestID:      w.runID,
+			Type:           "violation_resolved",
+			Message:        fmt.Sprintf("Policy '%s' no longer flags instance %s", policy.Name, violation.ResourceID),
+			Metadata:       fmt.Sprintf(`{"policyId":"%s","violationId":"%s","providerId":"%s"}`, policy.ID, violation.ID, provider.ID),
+		})
+	}
+}
+
+// handleInstanceViolation records a violation of a resource-scoped policy against one
+// instance and notifies the organization's webhooks. It returns false when the instance
+// already has a pending violation of the policy.
+func (w *EnforcementWorker) handleInstanceViolation(policy models.Policy, provider models.CloudProvider, instance cloud.Instance, result map[string]interface{}, severity string) (models.PolicyViolation, bool) {
+	fmt.Printf("Policy violation detected: %s on instance %s\n", policy.Name, instance.ID)
+
+	message := "Policy violation detected"
+	if msg, ok := result["msg"].(string); ok && msg != "" {
+		message = msg
+	}
+	if instance.Name != "" {
+		message = fmt.Sprintf("%s: %s", instance.Name, message)
+	}
+
+	violation := models.PolicyViolation{
+		PolicyID:      policy.ID,
+		ResourceID:    instance.ID,
+		ResourceType:  ResourceTypeInstance,
+		CloudProvider: provider.Type,
+		ProviderID:    provider.ID,
+		Message:       message,
+		Severity:      severity,
+		Status:        "pending",
+		RequestID:     w.runID,
+	}
+	if !w.createViolation(&violation) {
+		return violation, false
+	}
+
+	w.DB.Create(&models.ActivityLog{
+		OrganizationID: policy.OrganizationID,
+		RequestID:      w.runID,
+		Type:           "policy_violation",
+		Message:        fmt.Sprintf("Policy '%s' violation: %s", policy.Name, message),
+		Metadata:       fmt.Sprintf(`{"policyId":"%s","violationId":"%s","providerId":"%s"}`, policy.ID, violation.ID, provider.ID),
+	})
+
+	w.sendWebhooks(policy.OrganizationID, violation)
+	return violation, true
+}
@@ -0,0 +1,119 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cloud "finopsbridge/api/internal/cloud_"
+	models "finopsbridge/api/internal/models_"
+	opa "finopsbridge/api/internal/opa_"
+	policygen "finopsbridge/api/internal/policygen_"
+
+	"gorm.io/gorm"
+)
+
+func TestInstanceInput(t *testing.T) {
+	launched := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	providerInput := map[string]interface{}{"monthly_spend": 100.0, "provider_type": "aws"}
+	instance := cloud.Instance{
+		ID:           "i-1",
+		Name:         "web",
+		InstanceType: "m5.xlarge",
+		Region:       "us-east-1",
+		State:        "running",
+		Tags:         map[string]string{"team": "platform"},
+		LaunchedAt:   launched,
+	}
+
+	input := instanceInput(providerInput, instance)
+
+	want := map[string]interface{}{
+		"monthly_spend": 100.0,
+		"resource_id":   "i-1",
+		"resource_name": "web",
+		"resource_type": ResourceTypeInstance,
+		"instance_type": "m5.xlarge",
+		"instanceType":  "m5.xlarge",
+		"region":        "us-east-1",
+		"status":        "running",
+		"instance_size": 4,
+		"launched_at":   "2026-03-01T08:00:00Z",
+	}
+	for key, value := range want {
+		if input[key] != value {
+			t.Errorf("input[%q] = %v, want %v", key, input[key], value)
+		}
+	}
+	if tags, ok := input["tags"].(map[string]interface{}); !ok || tags["team"] != "platform" {
+		t.Errorf("tags = %#v", input["tags"])
+	}
+	if _, ok := providerInput["resource_id"]; ok {
+		t.Error("the provider input shouldn't be modified")
+	}
+
+	// Unknown sizes and launch times are left out rather than zero
+	input = instanceInput(providerInput, cloud.Instance{ID: "vm-1", InstanceType: "Standard_D2s_v3"})
+	if _, ok := input["instance_size"]; ok {
+		t.Error("an unknown size shouldn't be in the input")
+	}
+	if _, ok := input["launched_at"]; ok {
+		t.Error("an unknown launch time shouldn't be in the input")
+	}
+}
+
+func TestIsResourcePolicy(t *testing.T) {
+	if !isResourcePolicy(models.Policy{Type: "block_instance_type"}) {
+		t.Error("block_instance_type should be evaluated per instance")
+	}
+	if isResourcePolicy(models.Policy{Type: "max_spend"}) {
+		t.Error("max_spend should be evaluated per provider")
+	}
+}
+
+func TestEvaluateResourcePolicy(t *testing.T) {
+	engine, err := opa.Initialize(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	rego, err := policygen.GenerateRego("block_instance_type", map[string]interface{}{"maxSize": "medium"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.SavePolicy("pol1", rego, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	w := testWorker(t, nil, time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC))
+	w.OPA = engine
+	var created []models.PolicyViolation
+	w.DB.Callback().Create().Before("gorm:create").Register("test:record_violations", func(db *gorm.DB) {
+		if violation, ok := db.Statement.Dest.(*models.PolicyViolation); ok {
+			created = append(created, *violation)
+		}
+	})
+
+	policy := models.Policy{ID: "pol1", OrganizationID: "org", Name: "No large instances", Type: "block_instance_type"}
+	provider := models.CloudProvider{ID: "p1", Type: "aws", Name: "Production"}
+	instances := []cloud.Instance{
+		{ID: "i-small", InstanceType: "t3.small"},
+		{ID: "i-xlarge", Name: "etl", InstanceType: "m5.xlarge"},
+		{ID: "i-large", InstanceType: "m5.large"},
+	}
+
+	if err := w.evaluateResourcePolicy(context.Background(), policy, provider, map[string]interface{}{}, instances, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(created) != 2 || created[0].ResourceID != "i-xlarge" || created[1].ResourceID != "i-large" {
+		t.Fatalf("violations = %+v, want one per oversized instance", created)
+	}
+	for _, violation := range created {
+		if violation.ResourceType != ResourceTypeInstance || violation.ProviderID != "p1" || violation.Status != "pending" {
+			t.Errorf("violation = %+v", violation)
+		}
+	}
+	if want := "etl: Instance size 4 exceeds maximum allowed size: medium"; created[0].Message != want {
+		t.Errorf("message = %q, want %q", created[0].Message, want)
+	}
+}
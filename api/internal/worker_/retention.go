@@ -0,0 +1,244 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	cloud "finopsbridge/api/internal/cloud_"
+	config "finopsbridge/api/internal/config_"
+	models "finopsbridge/api/internal/models_"
+
+	"gorm.io/gorm"
+)
+
+// RetentionWorker downsamples old usage data so tables don't grow unbounded.
+// Raw TokenUsage/GPUMetrics rows become daily UsageRollups, daily rollups become
+// monthly ones, and old spend snapshots are thinned to one per provider per month.
+// Sums are preserved at every step.
+type RetentionWorker struct {
+	DB     *gorm.DB
+	Config *config.Config
+	Clock  cloud.Clock // Retention cutoffs are measured back from its time
+}
+
+func NewRetentionWorker(db *gorm.DB, cfg *config.Config) *RetentionWorker {
+	return &RetentionWorker{
+		DB:     db,
+		Config: cfg,
+		Clock:  cloud.SystemClock,
+	}
+}
+
+func (w *RetentionWorker) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	w.run()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.run()
+		}
+	}
+}
+
+func (w *RetentionWorker) run() {
+	fmt.Println("Running retention worker...")
+	now := w.Clock.Now().UTC()
+
+	if days := w.Config.RawMetricsRetentionDays; days > 0 {
+		cutoff := retentionCutoff(now, days)
+		if err := w.DB.Transaction(func(tx *gorm.DB) error { return rollupTokenUsage(tx, cutoff) }); err != nil {
+			fmt.Printf("Error rolling up token usage: %v\n", err)
+		}
+		if err := w.DB.Transaction(func(tx *gorm.DB) error { return rollupGPUMetrics(tx, cutoff) }); err != nil {
+			fmt.Printf("Error rolling up GPU metrics: %v\n", err)
+		}
+	}
+
+	if days := w.Config.DailyRollupRetentionDays; days > 0 {
+		// Only roll up whole months so a month never mixes daily and monthly rows
+		cutoff := retentionCutoff(now, days)
+		cutoff = time.Date(cutoff.Year(), cutoff.Month(), 1, 0, 0, 0, 0, time.UTC)
+		if err := w.DB.Transaction(func(tx *gorm.DB) error { return rollupMonthly(tx, cutoff) }); err != nil {
+			fmt.Printf("Error rolling up daily usage: %v\n", err)
+		}
+	}
+
+	if days := w.Config.SpendSnapshotRetentionDays; days > 0 {
+		if err := w.thinSpendSnapshots(retentionCutoff(now, days)); err != nil {
+			fmt.Printf("Error thinning spend snapshots: %v\n", err)
+		}
+	}
+
+	if days := w.Config.DecisionLogRetentionDays; days > 0 {
+		if err := w.pruneDecisionLog(retentionCutoff(now, days)); err != nil {
+			fmt.Printf("Error pruning decision log: %v\n", err)
+		}
+	}
+}
+
+// retentionCutoff returns midnight UTC `days` days ago, so whole days are rolled up together
+func retentionCutoff(now time.Time, days int) time.Time {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return day.AddDate(0, 0, -days)
+}
+
+// rollupTokenUsage replaces raw token usage rows older than cutoff with daily rollups. Each
+// rollup keeps its rows' team and user, and sums every measure. Run it in a transaction so raw
+// rows are only deleted along with the rollups replacing them; the same goes for the other rollups.
+func rollupTokenUsage(tx *gorm.DB, cutoff time.Time) error {
+	var rollups []models.UsageRollup
+	if err := tx.Model(&models.TokenUsage{}).
+		Select(`organization_id, ai_workload_id, provider, model_name, team, user_id,
+			DATE_TRUNC('day', timestamp) AS period_start,
+			SUM(input_tokens) AS input_tokens, SUM(output_tokens) AS output_tokens,
+			SUM(total_tokens) AS total_tokens, SUM(cached_tokens) AS cached_tokens,
+			SUM(cache_savings) AS cache_savings,
+			SUM(request_count) AS request_count, SUM(cost) AS cost, COUNT(*) AS sample_count`).
+		Where("timestamp < ?", cutoff).
+		Group("organization_id, ai_workload_id, provider, model_name, team, user_id, DATE_TRUNC('day', timestamp)").
+		Find(&rollups).Error; err != nil {
+		return err
+	}
+
+	if len(rollups) == 0 {
+		return nil
+	}
+
+	for i := range rollups {
+		rollups[i].Source = models.RollupSourceTokenUsage
+		rollups[i].Granularity = "daily"
+	}
+
+	if err := tx.CreateInBatches(&rollups, 500).Error; err != nil {
+		return err
+	}
+
+	result := tx.Where("timestamp < ?", cutoff).Delete(&models.TokenUsage{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	fmt.Printf("Rolled up %d token usage rows into %d daily rollups\n", result.RowsAffected, len(rollups))
+	return nil
+}
+
+// rollupGPUMetrics replaces raw GPU metric rows older than cutoff with daily rollups, kept per
+// team. Each raw sample is weighted by the time it covers (see models.GPUSamples), utilization
+// included.
+func rollupGPUMetrics(tx *gorm.DB, cutoff time.Time) error {
+	var rollups []models.UsageRollup
+	if err := models.GPUSamples(tx, tx.Model(&models.GPUMetrics{}).Where("timestamp < ?", cutoff)).
+		Select(`organization_id, ai_workload_id, cloud_provider AS provider, instance_type, gpu_type, team,
+			DATE_TRUNC('day', timestamp) AS period_start,
+			SUM(hourly_cost * sample_hours) AS cost, SUM(sample_hours) AS gpu_hours,
+			SUM(CASE WHEN utilization < 10 THEN sample_hours ELSE 0 END) AS idle_gpu_hours,
+			COALESCE(SUM(utilization * sample_hours) / NULLIF(SUM(sample_hours), 0), 0) AS average_utilization,
+			COUNT(*) AS sample_count`).
+		Group("organization_id, ai_workload_id, cloud_provider, instance_type, gpu_type, team, DATE_TRUNC('day', timestamp)").
+		Find(&rollups).Error; err != nil {
+		return err
+	}
+
+	if len(rollups) == 0 {
+		return nil
+	}
+
+	for i := range rollups {
+		rollups[i].Source = models.RollupSourceGPUMetrics
+		rollups[i].Granularity = "daily"
+	}
+
+	if err := tx.CreateInBatches(&rollups, 500).Error; err != nil {
+		return err
+	}
+
+	result := tx.Where("timestamp < ?", cutoff).Delete(&models.GPUMetrics{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	fmt.Printf("Rolled up %d GPU metric rows into %d daily rollups\n", result.RowsAffected, len(rollups))
+	return nil
+}
+
+// rollupMonthly merges daily rollups older than cutoff into monthly rollups.
+// Utilization is re-averaged weighted by each day's GPU hours.
+func rollupMonthly(tx *gorm.DB, cutoff time.Time) error {
+	var rollups []models.UsageRollup
+	if err := tx.Model(&models.UsageRollup{}).
+		Select(`organization_id, source, ai_workload_id, provider, model_name, instance_type, gpu_type, team, user_id,
+			DATE_TRUNC('month', period_start) AS period_start,
+			SUM(input_tokens) AS input_tokens, SUM(output_tokens) AS output_tokens,
+			SUM(total_tokens) AS total_tokens, SUM(cached_tokens) AS cached_tokens,
+			SUM(cache_savings) AS cache_savings,
+			SUM(request_count) AS request_count, SUM(cost) AS cost,
+			SUM(gpu_hours) AS gpu_hours, SUM(idle_gpu_hours) AS idle_gpu_hours,
+			COALESCE(SUM(average_utilization * gpu_hours) / NULLIF(SUM(gpu_hours), 0), 0) AS average_utilization,
+			SUM(sample_count) AS sample_count`).
+		Where("granularity = ? AND period_start < ?", "daily", cutoff).
+		Group("organization_id, source, ai_workload_id, provider, model_name, instance_type, gpu_type, team, user_id, DATE_TRUNC('month', period_start)").
+		Find(&rollups).Error; err != nil {
+		return err
+	}
+
+	if len(rollups) == 0 {
+		return nil
+	}
+
+	for i := range rollups {
+		rollups[i].Granularity = "monthly"
+	}
+
+	result := tx.Where("granularity = ? AND period_start < ?", "daily", cutoff).Delete(&models.UsageRollup{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if err := tx.CreateInBatches(&rollups, 500).Error; err != nil {
+		return err
+	}
+
+	fmt.Printf("Rolled up %d daily rollups into %d monthly rollups\n", result.RowsAffected, len(rollups))
+	return nil
+}
+
+// thinSpendSnapshots keeps only the last snapshot per provider per month before cutoff.
+// Snapshots hold month-to-date spend, so the last one carries the month's total.
+func (w *RetentionWorker) thinSpendSnapshots(cutoff time.Time) error {
+	result := w.DB.Exec(`DELETE FROM spend_snapshots s
+		WHERE s.date < ?
+		AND EXISTS (
+			SELECT 1 FROM spend_snapshots l
+			WHERE l.provider_id = s.provider_id
+			AND DATE_TRUNC('month', l.date) = DATE_TRUNC('month', s.date)
+			AND l.date > s.date
+		)`, cutoff)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected > 0 {
+		fmt.Printf("Removed %d intermediate spend snapshots\n", result.RowsAffected)
+	}
+	return nil
+}
+
+// pruneDecisionLog deletes decision log entries older than cutoff. Unlike usage data they
+// aren't summed anywhere, so nothing is rolled up.
+func (w *RetentionWorker) pruneDecisionLog(cutoff time.Time) error {
+	result := w.DB.Where("created_at < ?", cutoff).Delete(&models.DecisionLog{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected > 0 {
+		fmt.Printf("Removed %d decision log entries\n", result.RowsAffected)
+	}
+	return nil
+}
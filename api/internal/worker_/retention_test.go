@@ -0,0 +1,141 @@
+package worker
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+func TestRetentionCutoff(t *testing.T) {
+	now := time.Date(2026, 3, 15, 17, 45, 0, 0, time.UTC)
+	if got, want := retentionCutoff(now, 90), time.Date(2025, 12, 15, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("retentionCutoff(90) = %v, want %v", got, want)
+	}
+	if got, want := retentionCutoff(now, 0), time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("retentionCutoff(0) = %v, want today's midnight %v", got, want)
+	}
+
+	// Any time in the day gives the same cutoff, so a day is rolled up whole
+	if !retentionCutoff(now, 30).Equal(retentionCutoff(now.Add(-17*time.Hour), 30)) {
+		t.Error("the cutoff shouldn't move within a day")
+	}
+}
+
+// recordStatements records the SQL db runs for queries, creates and deletes, in order
+func recordStatements(t *testing.T, db *gorm.DB) *[]string {
+	t.Helper()
+	var statements []string
+	record := func(tx *gorm.DB) {
+		statements = append(statements, tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...))
+	}
+	for name, err := range map[string]error{
+		"query":  db.Callback().Query().After("gorm:query").Register("test:record_queries", record),
+		"create": db.Callback().Create().After("gorm:create").Register("test:record_creates", record),
+		"delete": db.Callback().Delete().After("gorm:delete").Register("test:record_deletes", record),
+	} {
+		if err != nil {
+			t.Fatalf("registering %s callback: %v", name, err)
+		}
+	}
+	return &statements
+}
+
+// TestRollupsSumEveryTokenMeasure checks the daily and monthly rollups sum each of TokenUsage's
+// measures into the rollup column of the same name, so totals over rollups equal the totals of
+// the rows they replace
+func TestRollupsSumEveryTokenMeasure(t *testing.T) {
+	rollupType := reflect.TypeOf(models.UsageRollup{})
+	usageType := reflect.TypeOf(models.TokenUsage{})
+	namer := schema.NamingStrategy{}
+
+	var measures []string
+	for i := 0; i < usageType.NumField(); i++ {
+		field := usageType.Field(i)
+		switch field.Type.Kind() {
+		case reflect.Int, reflect.Int64, reflect.Float64:
+		default:
+			continue
+		}
+		if _, ok := rollupType.FieldByName(field.Name); !ok {
+			t.Errorf("UsageRollup has no %s to roll TokenUsage.%s up into", field.Name, field.Name)
+		}
+		measures = append(measures, namer.ColumnName("", field.Name))
+	}
+
+	cutoff := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for name, rollup := range map[string]func(*gorm.DB, time.Time) error{
+		"daily":   rollupTokenUsage,
+		"monthly": rollupMonthly,
+	} {
+		db := dryRunDB(t)
+		statements := recordStatements(t, db)
+		if err := rollup(db, cutoff); err != nil {
+			t.Fatal(err)
+		}
+		sql := strings.Join(strings.Fields((*statements)[0]), " ")
+		for _, column := range measures {
+			if want := "SUM(" + column + ") AS " + column; !strings.Contains(sql, want) {
+				t.Errorf("%s rollup should contain %s:\n%s", name, want, sql)
+			}
+		}
+		// Attribution must survive downsampling
+		groupBy := sql[strings.Index(sql, "GROUP BY"):]
+		for _, column := range []string{"team", "user_id", "model_name"} {
+			if !strings.Contains(groupBy, column) {
+				t.Errorf("%s rollup should group by %s:\n%s", name, column, groupBy)
+			}
+		}
+	}
+}
+
+func TestRollupTokenUsageReplacesRawRows(t *testing.T) {
+	db := dryRunDB(t)
+	// The daily sums the query would find
+	day := time.Date(2025, 12, 30, 0, 0, 0, 0, time.UTC)
+	db.Callback().Query().After("gorm:query").Register("test:stub_rollups", func(tx *gorm.DB) {
+		if rollups, ok := tx.Statement.Dest.(*[]models.UsageRollup); ok {
+			*rollups = []models.UsageRollup{
+				{OrganizationID: "org", ModelName: "gpt-4o", Team: "search", UserID: "u1", PeriodStart: day, TotalTokens: 1500, Cost: 3, CacheSavings: 0.4, SampleCount: 3},
+				{OrganizationID: "org", ModelName: "gpt-4o", Team: "ads", PeriodStart: day, TotalTokens: 200, Cost: 0.5, SampleCount: 1},
+			}
+		}
+	})
+	var created []models.UsageRollup
+	db.Callback().Create().Before("gorm:create").Register("test:record_rollups", func(tx *gorm.DB) {
+		// CreateInBatches passes each batch as a slice
+		if rollups, ok := tx.Statement.Dest.([]models.UsageRollup); ok {
+			created = append(created, rollups...)
+		}
+	})
+	statements := recordStatements(t, db)
+
+	if err := rollupTokenUsage(db, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(created) != 2 {
+		t.Fatalf("got %d rollups, want 2", len(created))
+	}
+	for _, rollup := range created {
+		if rollup.Source != models.RollupSourceTokenUsage || rollup.Granularity != "daily" {
+			t.Errorf("rollup %+v should be a daily token usage rollup", rollup)
+		}
+	}
+	if got := created[0]; got.Team != "search" || got.UserID != "u1" || got.CacheSavings != 0.4 {
+		t.Errorf("rollup %+v lost its attribution or cache savings", got)
+	}
+
+	// Raw rows are deleted after, and only up to, the rollups that replace them
+	if len(*statements) != 3 || !strings.HasPrefix((*statements)[2], "DELETE FROM \"token_usages\"") {
+		t.Fatalf("statements = %v, want query, create, delete", *statements)
+	}
+	if want := "timestamp < '2026-01-01 00:00:00'"; !strings.Contains((*statements)[2], want) {
+		t.Errorf("%s\nshould contain %s", (*statements)[2], want)
+	}
+}
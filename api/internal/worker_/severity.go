@@ -0,0 +1,96 @@
+package worker
+
+import (
+	"encoding/json"
+	"sort"
+
+	models "finopsbridge/api/internal/models_"
+)
+
+// defaultViolationSeverity is used when neither the policy's Rego nor its type determine one
+const defaultViolationSeverity = "high"
+
+var validSeverities = map[string]bool{"low": true, "medium": true, "high": true, "critical": true}
+
+// severityBand assigns a severity to max_spend and budget overages of at least OverPercent
+type severityBand struct {
+	OverPercent float64 `json:"overPercent"`
+	Severity    string  `json:"severity"`
+}
+
+// defaultSpendBands: under 10% over budget is medium, 10% is high and 50% is critical
+var defaultSpendBands = []severityBand{
+	{OverPercent: 0, Severity: "medium"},
+	{OverPercent: 10, Severity: "high"},
+	{OverPercent: 50, Severity: "critical"},
+}
+
+// violationSeverity picks the severity of a violation: a valid "severity" from the Rego result
+// wins, max_spend and budget_hierarchy scale with the overage, and anything else is high
+func violationSeverity(policy models.Policy, input, result map[string]interface{}) string {
+	if severity, ok := result["severity"].(string); ok && validSeverities[severity] {
+		return severity
+	}
+
+	if policy.Type == "max_spend" {
+		var policyConfig map[string]interface{}
+		json.Unmarshal([]byte(policy.Config), &policyConfig)
+		maxAmount, _ := policyConfig["maxAmount"].(float64)
+		spend, _ := input["monthly_spend"].(float64)
+		if maxAmount > 0 {
+			return spendSeverity(spend, maxAmount, spendBands(policyConfig))
+		}
+	}
+
+	if policy.Type == "budget_hierarchy" {
+		var policyConfig map[string]interface{}
+		json.Unmarshal([]byte(policy.Config), &policyConfig)
+		budget, _ := input["budget"].(map[string]interface{})
+		amount, _ := budget["amount"].(float64)
+		spend, _ := budget["spend"].(float64)
+		if amount > 0 {
+			return spendSeverity(spend, amount, spendBands(policyConfig))
+		}
+	}
+
+	return defaultViolationSeverity
+}
+
+// spendBands reads a max_spend policy's severityBands, e.g.
+// [{"overPercent": 0, "severity": "low"}, {"overPercent": 25, "severity": "critical"}],
+// falling back to defaultSpendBands when unset or invalid
+func spendBands(policyConfig map[string]interface{}) []severityBand {
+	raw, ok := policyConfig["severityBands"]
+	if !ok {
+		return defaultSpendBands
+	}
+	encoded, _ := json.Marshal(raw)
+	var bands []severityBand
+	if err := json.Unmarshal(encoded, &bands); err != nil || len(bands) == 0 {
+		return defaultSpendBands
+	}
+	for _, band := range bands {
+		if !validSeverities[band.Severity] || band.OverPercent < 0 {
+			return defaultSpendBands
+		}
+	}
+	return bands
+}
+
+// spendSeverity returns the severity of the highest band the overage reaches. Spend at or under
+// the limit falls in the lowest band.
+func spendSeverity(spend, maxAmount float64, bands []severityBand) string {
+	sorted := append([]severityBand(nil), bands...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].OverPercent < sorted[j].OverPercent
+	})
+
+	overPercent := (spend - maxAmount) / maxAmount * 100
+	severity := sorted[0].Severity
+	for _, band := range sorted {
+		if overPercent >= band.OverPercent {
+			severity = band.Severity
+		}
+	}
+	return severity
+}
@@ -0,0 +1,90 @@
+package worker
+
+import (
+	"testing"
+
+	models "finopsbridge/api/internal/models_"
+)
+
+func TestViolationSeverityMaxSpend(t *testing.T) {
+	policy := models.Policy{Type: "max_spend", Config: `{"maxAmount": 1000}`}
+
+	tests := []struct {
+		spend float64
+		want  string
+	}{
+		{1000, "medium"},
+		{1050, "medium"},
+		{1100, "high"},
+		{1499, "high"},
+		{1500, "critical"},
+	}
+	for _, tt := range tests {
+		input := map[string]interface{}{"monthly_spend": tt.spend}
+		if got := violationSeverity(policy, input, map[string]interface{}{}); got != tt.want {
+			t.Errorf("spend %v: severity %q, want %q", tt.spend, got, tt.want)
+		}
+	}
+}
+
+func TestViolationSeverityPrefersRegoResult(t *testing.T) {
+	policy := models.Policy{Type: "max_spend", Config: `{"maxAmount": 1000}`}
+	input := map[string]interface{}{"monthly_spend": 5000.0}
+
+	if got := violationSeverity(policy, input, map[string]interface{}{"severity": "low"}); got != "low" {
+		t.Errorf("got %q, want the Rego result's low", got)
+	}
+	if got := violationSeverity(policy, input, map[string]interface{}{"severity": "urgent"}); got != "critical" {
+		t.Errorf("got %q, want an invalid Rego severity ignored", got)
+	}
+}
+
+func TestViolationSeverityBudgetHierarchy(t *testing.T) {
+	policy := models.Policy{Type: "budget_hierarchy", Config: `{}`}
+	input := map[string]interface{}{"budget": map[string]interface{}{"amount": 200.0, "spend": 260.0}}
+
+	if got := violationSeverity(policy, input, nil); got != "high" {
+		t.Errorf("30%% over budget: got %q, want high", got)
+	}
+}
+
+func TestViolationSeverityDefault(t *testing.T) {
+	tests := []models.Policy{
+		{Type: "require_tags", Config: `{}`},
+		{Type: "max_spend", Config: `{}`},
+	}
+	for _, policy := range tests {
+		if got := violationSeverity(policy, map[string]interface{}{}, nil); got != defaultViolationSeverity {
+			t.Errorf("%s %s: got %q, want %q", policy.Type, policy.Config, got, defaultViolationSeverity)
+		}
+	}
+}
+
+func TestSpendBands(t *testing.T) {
+	custom := map[string]interface{}{"severityBands": []interface{}{
+		map[string]interface{}{"overPercent": 25.0, "severity": "critical"},
+		map[string]interface{}{"overPercent": 0.0, "severity": "low"},
+	}}
+	bands := spendBands(custom)
+	if len(bands) != 2 {
+		t.Fatalf("got %+v", bands)
+	}
+	if got := spendSeverity(1100, 1000, bands); got != "low" {
+		t.Errorf("10%% over: got %q, want low", got)
+	}
+	if got := spendSeverity(1250, 1000, bands); got != "critical" {
+		t.Errorf("25%% over: got %q, want critical", got)
+	}
+
+	for _, invalid := range []interface{}{
+		"high",
+		[]interface{}{},
+		[]interface{}{map[string]interface{}{"overPercent": 0.0, "severity": "severe"}},
+		[]interface{}{map[string]interface{}{"overPercent": -5.0, "severity": "low"}},
+	} {
+		got := spendBands(map[string]interface{}{"severityBands": invalid})
+		if len(got) != len(defaultSpendBands) || got[0] != defaultSpendBands[0] {
+			t.Errorf("severityBands %v should fall back to the defaults, got %+v", invalid, got)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package worker
+
+import (
+	"fmt"
+	"time"
+
+	cloud "finopsbridge/api/internal/cloud_"
+	models "finopsbridge/api/internal/models_"
+
+	"gorm.io/gorm/clause"
+)
+
+// costSeriesSnapshots turns a provider's cost series into the daily month-to-date snapshots the
+// worker would have recorded: one on the last day of each period, holding the month's spend up
+// to then. Only periods that ended by today are included; today's snapshot is recorded live.
+func costSeriesSnapshots(provider models.CloudProvider, series []cloud.CostPoint, today time.Time) []models.SpendSnapshot {
+	var snapshots []models.SpendSnapshot
+	monthToDate := make(map[string]float64)
+	for _, point := range series {
+		day := point.End.AddDate(0, 0, -1)
+		if !point.End.After(point.Start) || !day.Before(today) {
+			continue
+		}
+		month := point.Start.Format("2006-01")
+		monthToDate[month] += point.Amount
+		snapshots = append(snapshots, models.SpendSnapshot{
+			OrganizationID:   provider.OrganizationID,
+			ProviderID:       provider.ID,
+			ProviderType:     provider.Type,
+			Date:             time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC),
+			MonthToDateSpend: monthToDate[month],
+			Currency:         point.Currency,
+		})
+	}
+	return snapshots
+}
+
+// thinBackfill drops the snapshots dated before cutoff that the retention worker would thin
+// away, keeping the last one of each month, which carries the month's total. A zero cutoff
+// keeps them all.
+func thinBackfill(snapshots []models.SpendSnapshot, cutoff time.Time) []models.SpendSnapshot {
+	if cutoff.IsZero() {
+		return snapshots
+	}
+	var kept []models.SpendSnapshot
+	for i, snapshot := range snapshots {
+		lastOfMonth := i == len(snapshots)-1 || snapshots[i+1].Date.Format("2006-01") != snapshot.Date.Format("2006-01")
+		if !snapshot.Date.Before(cutoff) || lastOfMonth {
+			kept = append(kept, snapshot)
+		}
+	}
+	return kept
+}
+
+// backfillSpendSnapshots records snapshots from the cost series in a provider's billing data,
+// so the dashboard's spend trend covers months before the provider was connected. It runs
+// once per provider: a snapshot dated before the provider was connected means it already
+// did. Days that already have a snapshot keep it, and days the retention worker would thin
+// away aren't recorded.
+func (w *EnforcementWorker) backfillSpendSnapshots(provider models.CloudProvider, billingData map[string]interface{}) {
+	series, ok := billingData["costSeries"].([]cloud.CostPoint)
+	if !ok || len(series) == 0 {
+		return
+	}
+
+	var backfilled int64
+	if err := w.DB.Model(&models.SpendSnapshot{}).
+		Where("provider_id = ? AND date < ?", provider.ID, provider.CreatedAt.UTC().Truncate(24*time.Hour)).
+		Limit(1).Count(&backfilled).Error; err != nil {
+		fmt.Printf("Error checking spend snapshots of %s: %v\n", provider.Name, err)
+		return
+	}
+	if backfilled > 0 {
+		return
+	}
+
+	now := w.Clock.Now().UTC()
+	snapshots := costSeriesSnapshots(provider, series, time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC))
+	var cutoff time.Time
+	if days := w.Config.SpendSnapshotRetentionDays; days > 0 {
+		cutoff = retentionCutoff(now, days)
+	}
+	snapshots = thinBackfill(snapshots, cutoff)
+	if len(snapshots) == 0 {
+		return
+	}
+
+	if err := w.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "provider_id"}, {Name: "date"}},
+		DoNothing: true,
+	}).Create(&snapshots).Error; err != nil {
+		fmt.Printf("Error backfilling spend snapshots for %s: %v\n", provider.Name, err)
+	}
+}
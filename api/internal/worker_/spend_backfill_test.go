@@ -0,0 +1,125 @@
+package worker
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	cloud "finopsbridge/api/internal/cloud_"
+	config "finopsbridge/api/internal/config_"
+	models "finopsbridge/api/internal/models_"
+
+	"gorm.io/gorm"
+)
+
+func utcDay(m time.Month, d int) time.Time {
+	return time.Date(2026, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestCostSeriesSnapshots(t *testing.T) {
+	provider := models.CloudProvider{ID: "cp1", OrganizationID: "org", Type: "aws"}
+	series := []cloud.CostPoint{
+		{Start: utcDay(1, 1), End: utcDay(2, 1), Amount: 300, Currency: "USD"},
+		{Start: utcDay(2, 27), End: utcDay(2, 28), Amount: 10, Currency: "USD"},
+		{Start: utcDay(2, 28), End: utcDay(3, 1), Amount: 20, Currency: "USD"},
+		{Start: utcDay(3, 1), End: utcDay(3, 2), Amount: 5, Currency: "USD"},
+		{Start: utcDay(3, 2), End: utcDay(3, 3), Amount: 7, Currency: "USD"}, // Today, recorded live
+		{Start: utcDay(3, 1), End: utcDay(3, 1), Amount: 1, Currency: "USD"}, // Empty period
+	}
+
+	snapshots := costSeriesSnapshots(provider, series, utcDay(3, 2))
+
+	want := []struct {
+		date  time.Time
+		spend float64
+	}{
+		{utcDay(1, 31), 300},
+		{utcDay(2, 27), 10},
+		{utcDay(2, 28), 30},
+		{utcDay(3, 1), 5},
+	}
+	if len(snapshots) != len(want) {
+		t.Fatalf("got %d snapshots, want %d: %+v", len(snapshots), len(want), snapshots)
+	}
+	for i, w := range want {
+		s := snapshots[i]
+		if !s.Date.Equal(w.date) || s.MonthToDateSpend != w.spend || s.ProviderID != "cp1" || s.OrganizationID != "org" || s.Currency != "USD" {
+			t.Errorf("snapshot %d = %+v, want %s with %v", i, s, w.date.Format("2006-01-02"), w.spend)
+		}
+	}
+}
+
+func TestThinBackfill(t *testing.T) {
+	snapshots := []models.SpendSnapshot{
+		{Date: utcDay(1, 30)}, {Date: utcDay(1, 31)},
+		{Date: utcDay(2, 27)}, {Date: utcDay(2, 28)},
+		{Date: utcDay(3, 1)}, {Date: utcDay(3, 2)},
+	}
+
+	if got := thinBackfill(snapshots, time.Time{}); len(got) != len(snapshots) {
+		t.Errorf("a zero cutoff kept %d snapshots, want all %d", len(got), len(snapshots))
+	}
+
+	var dates []string
+	for _, s := range thinBackfill(snapshots, utcDay(3, 1)) {
+		dates = append(dates, s.Date.Format("01-02"))
+	}
+	if want := "01-31 02-28 03-01 03-02"; strings.Join(dates, " ") != want {
+		t.Errorf("kept %v, want %s", dates, want)
+	}
+}
+
+// recordSnapshotCreates collects the spend snapshots w backfills
+func recordSnapshotCreates(t *testing.T, w *EnforcementWorker) *[]models.SpendSnapshot {
+	t.Helper()
+	var created []models.SpendSnapshot
+	err := w.DB.Callback().Create().Before("gorm:create").Register("test:record_snapshots", func(tx *gorm.DB) {
+		if snapshots, ok := tx.Statement.Dest.(*[]models.SpendSnapshot); ok {
+			created = append(created, *snapshots...)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &created
+}
+
+func TestBackfillSpendSnapshots(t *testing.T) {
+	w := testWorker(t, &config.Config{SpendSnapshotRetentionDays: 30}, time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC))
+	created := recordSnapshotCreates(t, w)
+	provider := models.CloudProvider{ID: "cp1", Type: "aws", CreatedAt: utcDay(3, 1)}
+	series := []cloud.CostPoint{
+		{Start: utcDay(1, 1), End: utcDay(1, 2), Amount: 10, Currency: "USD"},
+		{Start: utcDay(1, 2), End: utcDay(1, 3), Amount: 10, Currency: "USD"},
+		{Start: utcDay(2, 28), End: utcDay(3, 1), Amount: 5, Currency: "USD"},
+	}
+
+	w.backfillSpendSnapshots(provider, map[string]interface{}{"costSeries": series})
+
+	// January is past retention, so only its last day is kept
+	if len(*created) != 2 || !(*created)[0].Date.Equal(utcDay(1, 2)) || (*created)[0].MonthToDateSpend != 20 || !(*created)[1].Date.Equal(utcDay(2, 28)) {
+		t.Errorf("backfilled %+v", *created)
+	}
+
+	*created = nil
+	w.backfillSpendSnapshots(provider, map[string]interface{}{"monthlySpend": 5.0})
+	if len(*created) != 0 {
+		t.Error("billing data without a cost series shouldn't backfill")
+	}
+}
+
+func TestBackfillSpendSnapshotsRunsOnce(t *testing.T) {
+	w := testWorker(t, nil, time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC))
+	created := recordSnapshotCreates(t, w)
+	w.DB.Callback().Query().After("gorm:query").Register("test:stub_backfilled", func(tx *gorm.DB) {
+		if count, ok := tx.Statement.Dest.(*int64); ok {
+			*count, tx.RowsAffected = 1, 1
+		}
+	})
+
+	series := []cloud.CostPoint{{Start: utcDay(2, 1), End: utcDay(2, 2), Amount: 10, Currency: "USD"}}
+	w.backfillSpendSnapshots(models.CloudProvider{ID: "cp1", CreatedAt: utcDay(3, 1)}, map[string]interface{}{"costSeries": series})
+	if len(*created) != 0 {
+		t.Errorf("a provider with snapshots before it was connected was backfilled again: %+v", *created)
+	}
+}
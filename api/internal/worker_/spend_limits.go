@@ -0,0 +1,100 @@
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+
+	models "finopsbridge/api/internal/models_"
+	policygen "finopsbridge/api/internal/policygen_"
+)
+
+// Tiers recorded as a max_spend violation's SpendLimitTier under tiered limits. A soft
+// violation is escalated to hard when spend later crosses the hard limit.
+const (
+	spendLimitTierSoft = "soft"
+	spendLimitTierHard = "hard"
+)
+
+// Severities of max_spend violations under tiered limits
+const (
+	softLimitSeverity = "low"
+	hardLimitSeverity = "high"
+)
+
+// isSpendLimitPolicy reports whether a policy compares a provider's month-to-date spend with
+// a limit, which a truncated billing fetch can't be checked against
+func isSpendLimitPolicy(policy models.Policy) bool {
+	return policy.Type == "max_spend" || policy.Type == "month_over_month_growth"
+}
+
+// spendLimits are the tiers of a max_spend policy with softThreshold or hardThreshold in its
+// config. Crossing Soft only notifies; crossing Hard also remediates. Hard falls back to
+// maxAmount, and a policy with neither never remediates.
+type spendLimits struct {
+	Soft float64
+	Hard float64
+}
+
+// policySpendLimits reads a max_spend policy's tiered limits, reporting false when the policy
+// uses the single maxAmount limit instead
+func policySpendLimits(policy models.Policy) (spendLimits, bool) {
+	if policy.Type != "max_spend" {
+		return spendLimits{}, false
+	}
+	var policyConfig map[string]interface{}
+	json.Unmarshal([]byte(policy.Config), &policyConfig)
+
+	soft, hard, ok := policygen.SpendThresholds(policyConfig)
+	if !ok {
+		return spendLimits{}, false
+	}
+	return spendLimits{Soft: soft, Hard: hard}, true
+}
+
+// hardCrossed reports whether spend is over the hard limit
+func (l spendLimits) hardCrossed(spend float64) bool {
+	return l.Hard > 0 && spend > l.Hard
+}
+
+// tier returns the limit a tiered max_spend violation at the given spend crossed, and its
+// severity. Rego written for maxAmount may fire below both limits; such a violation is
+// treated as soft. Only a hard violation is remediated.
+func (l spendLimits) tier(spend float64) (string, string) {
+	if l.hardCrossed(spend) {
+		return spendLimitTierHard, hardLimitSeverity
+	}
+	return spendLimitTierSoft, softLimitSeverity
+}
+
+// escalateSpendLimit raises a soft max_spend violation to the hard limit once spend crosses it,
+// reporting whether this run escalated it and should remediate. The update is conditional on
+// the soft tier so concurrent workers don't both escalate. The tier, not the severity, marks a
+// soft violation: SLA escalation may already have raised its severity.
+func (w *EnforcementWorker) escalateSpendLimit(policy models.Policy, violation *models.PolicyViolation, message string) bool {
+	if violation.SpendLimitTier != spendLimitTierSoft {
+		return false
+	}
+
+	result := w.DB.Model(&models.PolicyViolation{}).
+		Where("id = ? AND status = ? AND spend_limit_tier = ?", violation.ID, "pending", spendLimitTierSoft).
+		Updates(map[string]interface{}{"spend_limit_tier": spendLimitTierHard, "severity": hardLimitSeverity, "message": message})
+	if result.Error != nil {
+		fmt.Printf("Error escalating violation %s: %v\n", violation.ID, result.Error)
+		return false
+	}
+	if result.RowsAffected == 0 {
+		return false
+	}
+	violation.SpendLimitTier = spendLimitTierHard
+	violation.Severity = hardLimitSeverity
+	violation.Message = message
+
+	w.DB.Create(&models.ActivityLog{
+		OrganizationID: policy.OrganizationID,
+		RequestID:      w.runID,
+		Type:           "spend_limit_escalated",
+		Message:        fmt.Sprintf("Policy '%s' violation crossed the hard limit: %s", policy.Name, message),
+		Metadata:       fmt.Sprintf(`{"policyId":"%s","violationId":"%s"}`, policy.ID, violation.ID),
+	})
+	return true
+}
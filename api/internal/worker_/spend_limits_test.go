@@ -0,0 +1,117 @@
+package worker
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+
+	"gorm.io/gorm"
+)
+
+func TestPolicySpendLimits(t *testing.T) {
+	tests := []struct {
+		policy models.Policy
+		want   spendLimits
+		ok     bool
+	}{
+		{models.Policy{Type: "max_spend", Config: `{"softThreshold": 800, "maxAmount": 1000}`}, spendLimits{Soft: 800, Hard: 1000}, true},
+		{models.Policy{Type: "max_spend", Config: `{"maxAmount": 1000}`}, spendLimits{}, false},
+		{models.Policy{Type: "month_over_month_growth", Config: `{"softThreshold": 800}`}, spendLimits{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := policySpendLimits(tt.policy)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("policySpendLimits(%s) = %+v, %v; want %+v, %v", tt.policy.Config, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestSpendLimitsTier(t *testing.T) {
+	limits := spendLimits{Soft: 800, Hard: 1000}
+	tests := []struct {
+		spend          float64
+		tier, severity string
+	}{
+		{900, spendLimitTierSoft, softLimitSeverity},
+		{1000, spendLimitTierSoft, softLimitSeverity},
+		{1000.01, spendLimitTierHard, hardLimitSeverity},
+		// Rego written for maxAmount may fire below both limits
+		{500, spendLimitTierSoft, softLimitSeverity},
+	}
+	for _, tt := range tests {
+		if tier, severity := limits.tier(tt.spend); tier != tt.tier || severity != tt.severity {
+			t.Errorf("tier(%v) = %s, %s; want %s, %s", tt.spend, tier, severity, tt.tier, tt.severity)
+		}
+	}
+
+	if (spendLimits{Soft: 800}).hardCrossed(1e9) {
+		t.Error("limits without a hard limit should never be crossed")
+	}
+}
+
+func TestEscalateSpendLimit(t *testing.T) {
+	w := testWorker(t, nil, time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC))
+	updates := recordUpdates(t, w.DB)
+	policy := models.Policy{ID: "p1", OrganizationID: "org", Name: "Spend"}
+
+	hard := &models.PolicyViolation{ID: "v1", SpendLimitTier: spendLimitTierHard}
+	if w.escalateSpendLimit(policy, hard, "over") || len(*updates) != 0 {
+		t.Errorf("a hard violation shouldn't be escalated again, got %v", *updates)
+	}
+
+	// A dry run affects no rows, as when another worker escalated the violation first
+	soft := &models.PolicyViolation{ID: "v2", SpendLimitTier: spendLimitTierSoft, Severity: softLimitSeverity}
+	if w.escalateSpendLimit(policy, soft, "Monthly spend $1200 exceeds hard limit of $1000") {
+		t.Error("an update that affected no rows shouldn't escalate")
+	}
+	if len(*updates) != 1 {
+		t.Fatalf("got %d updates, want 1", len(*updates))
+	}
+	if want := `spend_limit_tier = 'soft'`; !strings.Contains((*updates)[0], want) {
+		t.Errorf("%s\nshould only update a violation still at the soft tier", (*updates)[0])
+	}
+	if soft.SpendLimitTier != spendLimitTierSoft || soft.Severity != softLimitSeverity {
+		t.Errorf("violation = %+v, want it unchanged", soft)
+	}
+}
+
+func TestHardLimitEscalatesOnlyItsOwnProvider(t *testing.T) {
+	w := testWorker(t, nil, time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC))
+	affectRows(t, w.DB)
+	updates := recordUpdates(t, w.DB)
+	log := &remediationLog{}
+	providerA := stubProviderOfType(t, stubProvider{log: log})
+	providerB := providerA
+	providerB.ID = "provider-b"
+	policy := models.Policy{ID: "p1", OrganizationID: "org", Name: "Spend", Type: "max_spend", Config: `{"softThreshold": 800, "hardThreshold": 1000}`}
+
+	// Provider A is over its soft limit only
+	stubPendingViolation(t, w.DB, models.PolicyViolation{ID: "v-a", PolicyID: "p1", ResourceID: providerA.ID, ResourceType: "cloud_provider", Status: "pending", Severity: softLimitSeverity, SpendLimitTier: spendLimitTierSoft})
+	var created []models.PolicyViolation
+	w.DB.Callback().Create().Before("gorm:create").Register("test:record_violations", func(tx *gorm.DB) {
+		if violation, ok := tx.Statement.Dest.(*models.PolicyViolation); ok {
+			created = append(created, *violation)
+		}
+	})
+
+	err := w.handleViolation(context.Background(), policy, providerB, map[string]interface{}{"msg": "Monthly spend $1200 exceeds hard limit of $1000"}, hardLimitSeverity, spendLimitTierHard, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, update := range *updates {
+		if strings.Contains(update, "'v-a'") {
+			t.Errorf("provider A's soft violation was escalated: %s", update)
+		}
+	}
+	if len(created) != 1 || created[0].ResourceID != "provider-b" || created[0].SpendLimitTier != spendLimitTierHard {
+		t.Errorf("created %+v, want a hard violation of provider B", created)
+	}
+	if got := log.list(); !reflect.DeepEqual(got, []string{"stop provider-b"}) {
+		t.Errorf("remediations = %v, want provider B's only", got)
+	}
+}
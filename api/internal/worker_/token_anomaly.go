@@ -0,0 +1,144 @@
+package worker
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+
+	"gorm.io/gorm/clause"
+)
+
+// ResourceTypeModel marks violations of token_cost_anomaly policies, recorded against one model
+const ResourceTypeModel = "ai_model"
+
+// dailyTokenCost sums the organization's token cost per model and UTC day since the given day,
+// limited to the given providers when set. Days the retention worker rolled up are read from
+// their daily rollups.
+func (w *EnforcementWorker) dailyTokenCost(orgID string, providers []string, since time.Time) (map[string]map[string]float64, error) {
+	type dayCost struct {
+		ModelName string
+		Day       string
+		Cost      float64
+	}
+	var rows, rolledUp []dayCost
+	query := w.DB.Model(&models.TokenUsage{}).
+		Select("model_name, TO_CHAR(timestamp AT TIME ZONE 'UTC', 'YYYY-MM-DD') AS day, COALESCE(SUM(cost), 0) AS cost").
+		Where("organization_id = ? AND timestamp >= ?", orgID, since)
+	if len(providers) > 0 {
+		query = query.Where("provider IN ?", providers)
+	}
+	if err := query.Group("model_name, day").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	rollups := w.DB.Model(&models.UsageRollup{}).
+		Select("model_name, TO_CHAR(period_start, 'YYYY-MM-DD') AS day, COALESCE(SUM(cost), 0) AS cost").
+		Where("organization_id = ? AND source = ? AND granularity = ? AND period_start >= ?", orgID, models.RollupSourceTokenUsage, "daily", since)
+	if len(providers) > 0 {
+		rollups = rollups.Where("provider IN ?", providers)
+	}
+	if err := rollups.Group("model_name, day").Scan(&rolledUp).Error; err != nil {
+		return nil, err
+	}
+
+	byModel := make(map[string]map[string]float64)
+	for _, row := range append(rows, rolledUp...) {
+		if byModel[row.ModelName] == nil {
+			byModel[row.ModelName] = make(map[string]float64)
+		}
+		byModel[row.ModelName][row.Day] += row.Cost
+	}
+	return byModel, nil
+}
+
+// tokenCostBaseline returns a model's cost on today and its average daily cost over up to
+// `days` days before it. The baseline starts at the model's first day of usage in that window,
+// so a newly adopted model isn't compared against days it didn't exist; days without usage
+// after that count as zero. It returns false when there is no baseline to compare against.
+func tokenCostBaseline(daily map[string]float64, today time.Time, days int) (current, average float64, baselineDays int, ok bool) {
+	for i := days; i >= 1; i-- {
+		if _, used := daily[today.AddDate(0, 0, -i).Format("2006-01-02")]; used {
+			baselineDays = i
+			break
+		}
+	}
+	if baselineDays == 0 {
+		return 0, 0, 0, false
+	}
+
+	total := 0.0
+	for i := baselineDays; i >= 1; i-- {
+		total += daily[today.AddDate(0, 0, -i).Format("2006-01-02")]
+	}
+	average = total / float64(baselineDays)
+	if average <= 0 {
+		return 0, 0, 0, false
+	}
+	return daily[today.Format("2006-01-02")], average, baselineDays, true
+}
+
+// tokenCostAnomalyInputs builds one input per model with today's token cost and its rolling
+// baseline, recording each baseline for the policy
+func (w *EnforcementWorker) tokenCostAnomalyInputs(policy models.Policy, policyConfig map[string]interface{}, now time.Time) ([]aiPolicyInput, error) {
+	days := anomalyBaselineDays(policyConfig)
+	utc := now.UTC()
+	today := time.Date(utc.Year(), utc.Month(), utc.Day(), 0, 0, 0, 0, time.UTC)
+
+	byModel, err := w.dailyTokenCost(policy.OrganizationID, stringList(policyConfig["providers"]), today.AddDate(0, 0, -days))
+	if err != nil {
+		return nil, err
+	}
+
+	modelNames := make([]string, 0, len(byModel))
+	for name := range byModel {
+		modelNames = append(modelNames, name)
+	}
+	sort.Strings(modelNames)
+
+	var inputs []aiPolicyInput
+	for _, name := range modelNames {
+		current, average, baselineDays, ok := tokenCostBaseline(byModel[name], today, days)
+		if !ok {
+			continue
+		}
+		w.storeTokenCostBaseline(models.TokenCostBaseline{
+			OrganizationID: policy.OrganizationID,
+			PolicyID:       policy.ID,
+			ModelName:      name,
+			Date:           today,
+			DailyCost:      current,
+			AverageCost:    average,
+			BaselineDays:   baselineDays,
+		})
+
+		inputs = append(inputs, aiPolicyInput{
+			resourceID:    name,
+			resourceType:  ResourceTypeModel,
+			cloudProvider: "ai",
+			input: map[string]interface{}{
+				"model": map[string]interface{}{
+					"name": name,
+				},
+				"tokenCost": map[string]interface{}{
+					"daily":        current,
+					"average":      average,
+					"baselineDays": baselineDays,
+				},
+				"config": policyConfig,
+			},
+		})
+	}
+	return inputs, nil
+}
+
+// storeTokenCostBaseline records a model's latest baseline, replacing the policy's previous one
+func (w *EnforcementWorker) storeTokenCostBaseline(baseline models.TokenCostBaseline) {
+	if err := w.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "policy_id"}, {Name: "model_name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"date", "daily_cost", "average_cost", "baseline_days", "updated_at"}),
+	}).Create(&baseline).Error; err != nil {
+		fmt.Printf("Error recording token cost baseline for %s: %v\n", baseline.ModelName, err)
+	}
+}
@@ -0,0 +1,75 @@
+package worker
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+
+	"gorm.io/gorm"
+)
+
+func TestTokenCostBaseline(t *testing.T) {
+	today := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name         string
+		daily        map[string]float64
+		current      float64
+		average      float64
+		baselineDays int
+		ok           bool
+	}{
+		{"no usage", nil, 0, 0, 0, false},
+		{"only today", map[string]float64{"2026-03-10": 5}, 0, 0, 0, false},
+		{
+			name: "full window",
+			daily: map[string]float64{
+				"2026-03-01": 100, // before the window
+				"2026-03-03": 4, "2026-03-04": 4, "2026-03-05": 4, "2026-03-06": 4,
+				"2026-03-07": 4, "2026-03-08": 4, "2026-03-09": 4, "2026-03-10": 12,
+			},
+			current: 12, average: 4, baselineDays: 7, ok: true,
+		},
+		{
+			// A newly adopted model's idle day after its first use still counts
+			name:    "newly adopted",
+			daily:   map[string]float64{"2026-03-08": 10, "2026-03-10": 30},
+			current: 30, average: 5, baselineDays: 2, ok: true,
+		},
+		{"zero average", map[string]float64{"2026-03-09": 0, "2026-03-10": 5}, 0, 0, 0, false},
+	}
+
+	for _, tt := range tests {
+		current, average, baselineDays, ok := tokenCostBaseline(tt.daily, today, 7)
+		if current != tt.current || average != tt.average || baselineDays != tt.baselineDays || ok != tt.ok {
+			t.Errorf("%s: got %v, %v, %d, %v; want %v, %v, %d, %v", tt.name,
+				current, average, baselineDays, ok, tt.current, tt.average, tt.baselineDays, tt.ok)
+		}
+	}
+}
+
+func TestStoreTokenCostBaselineUpserts(t *testing.T) {
+	w := testWorker(t, nil, time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	var inserts []string
+	var stored []models.TokenCostBaseline
+	w.DB.Callback().Create().After("gorm:create").Register("test:record_inserts", func(tx *gorm.DB) {
+		if baseline, ok := tx.Statement.Dest.(*models.TokenCostBaseline); ok {
+			stored = append(stored, *baseline)
+			inserts = append(inserts, tx.Statement.SQL.String())
+		}
+	})
+
+	w.storeTokenCostBaseline(models.TokenCostBaseline{PolicyID: "p1", ModelName: "gpt-4o", DailyCost: 30, AverageCost: 5, BaselineDays: 2})
+
+	if len(inserts) != 1 {
+		t.Fatalf("got %d inserts, want 1", len(inserts))
+	}
+	if stored[0].ID == "" {
+		t.Error("the baseline should be given an ID")
+	}
+	want := `ON CONFLICT ("policy_id","model_name") DO UPDATE SET "date"="excluded"."date","daily_cost"="excluded"."daily_cost","average_cost"="excluded"."average_cost","baseline_days"="excluded"."baseline_days","updated_at"="excluded"."updated_at"`
+	if !strings.Contains(strings.Join(strings.Fields(inserts[0]), " "), want) {
+		t.Errorf("%s\nshould contain %s", inserts[0], want)
+	}
+}
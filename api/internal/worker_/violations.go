@@ -0,0 +1,34 @@
+package worker
+
+import (
+	"fmt"
+
+	models "finopsbridge/api/internal/models_"
+
+	"gorm.io/gorm/clause"
+)
+
+// pendingViolationConflict matches the unique index allowing one pending violation per policy
+// and resource. The predicate is literal SQL so Postgres can infer the partial index.
+var pendingViolationConflict = clause.OnConflict{
+	Columns:     []clause.Column{{Name: "policy_id"}, {Name: "resource_id"}},
+	TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "status = 'pending'"}}},
+	DoNothing:   true,
+}
+
+// createViolation inserts a pending violation and counts it against its policy. If the same
+// policy and resource already has a pending violation, e.g. created by an overlapping run after
+// the caller checked, nothing is inserted. It reports whether the violation was created.
+func (w *EnforcementWorker) createViolation(violation *models.PolicyViolation) bool {
+	result := w.DB.Clauses(pendingViolationConflict).Create(violation)
+	if result.Error != nil {
+		fmt.Printf("Error creating violation: %v\n", result.Error)
+		return false
+	}
+	if result.RowsAffected == 0 {
+		fmt.Printf("Violation of policy %s for %s is already pending\n", violation.PolicyID, violation.ResourceID)
+		return false
+	}
+	w.recordPolicyFired(*violation)
+	return true
+}
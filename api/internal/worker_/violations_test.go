@@ -0,0 +1,51 @@
+package worker
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	models "finopsbridge/api/internal/models_"
+
+	"gorm.io/gorm"
+)
+
+func TestCreateViolationSkipsDuplicatePending(t *testing.T) {
+	w := testWorker(t, nil, time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC))
+	var inserts []string
+	w.DB.Callback().Create().After("gorm:create").Register("test:record_inserts", func(tx *gorm.DB) {
+		inserts = append(inserts, tx.Statement.SQL.String())
+	})
+	updates := recordUpdates(t, w.DB)
+
+	// A dry run affects no rows, as when the pending violation already exists
+	created := w.createViolation(&models.PolicyViolation{PolicyID: "p1", ResourceID: "i-1", Status: "pending"})
+
+	if created {
+		t.Error("a violation that wasn't inserted shouldn't be reported as created")
+	}
+	if len(*updates) != 0 {
+		t.Errorf("an existing pending violation shouldn't count against the policy again, got %v", *updates)
+	}
+	if len(inserts) != 1 {
+		t.Fatalf("got %d inserts, want 1", len(inserts))
+	}
+	// The partial index's predicate must be in the conflict target for Postgres to infer it
+	want := `ON CONFLICT ("policy_id","resource_id") WHERE status = 'pending' DO NOTHING`
+	if !strings.Contains(strings.Join(strings.Fields(inserts[0]), " "), want) {
+		t.Errorf("%s\nshould contain %s", inserts[0], want)
+	}
+}
+
+func TestCreateViolationCountsInserted(t *testing.T) {
+	w := testWorker(t, nil, time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC))
+	affectRows(t, w.DB)
+	updates := recordUpdates(t, w.DB)
+
+	if !w.createViolation(&models.PolicyViolation{PolicyID: "p1", ResourceID: "i-1", Status: "pending"}) {
+		t.Error("an inserted violation should be reported as created")
+	}
+	if len(*updates) != 1 || !strings.Contains((*updates)[0], `"fire_count"=fire_count + 1`) {
+		t.Errorf("updates = %v, want the policy counted as fired once", *updates)
+	}
+}
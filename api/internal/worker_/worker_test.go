@@ -0,0 +1,132 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	cloud "finopsbridge/api/internal/cloud_"
+	config "finopsbridge/api/internal/config_"
+	models "finopsbridge/api/internal/models_"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// dryRunDB returns a database that builds Postgres statements without running them: writes
+// succeed without effect and reads find nothing
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=finopsbridge sslmode=disable"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true, // The default transaction would need a connection
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// testWorker returns an enforcement worker on a dry-run database, at a fixed time
+func testWorker(t *testing.T, cfg *config.Config, now time.Time) *EnforcementWorker {
+	t.Helper()
+	if cfg == nil {
+		cfg = &config.Config{}
+	}
+	if cfg.ReportingCurrency == "" {
+		cfg.ReportingCurrency = "USD"
+	}
+	w := NewEnforcementWorker(dryRunDB(t), nil, cfg)
+	w.Clock = cloud.NewFakeClock(now)
+	w.runID = "test-run"
+	w.actions = make(map[string]*actionCounts)
+	w.policyErrors = make(map[string][]models.PolicyError)
+	return w
+}
+
+// affectRows makes db's dry-run inserts and updates report one affected row, as when a
+// conditional write succeeds
+func affectRows(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	affect := func(tx *gorm.DB) { tx.RowsAffected = 1 }
+	if err := db.Callback().Create().After("gorm:create").Register("test:affect_inserts", affect); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Callback().Update().After("gorm:update").Register("test:affect_updates", affect); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// remediationLog records the remediations stub providers run, as "<action> <provider ID>"
+type remediationLog struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (l *remediationLog) record(action string, provider models.CloudProvider) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.calls = append(l.calls, action+" "+provider.ID)
+}
+
+func (l *remediationLog) list() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.calls...)
+}
+
+// stubProvider is a cloud provider type whose billing fetch returns fixed data or an error,
+// and which has no instances. Remediations are recorded in log when it is set.
+type stubProvider struct {
+	billing map[string]interface{}
+	err     error
+	log     *remediationLog
+}
+
+func (p stubProvider) RequiredCredentials() []string { return nil }
+
+func (p stubProvider) FetchBilling(ctx context.Context, provider models.CloudProvider, cfg *config.Config) (map[string]interface{}, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return p.billing, nil
+}
+
+func (p stubProvider) ListInstancePage(ctx context.Context, provider models.CloudProvider, cfg *config.Config, opts cloud.ListOptions) ([]cloud.Instance, string, error) {
+	return nil, "", nil
+}
+
+func (p stubProvider) StopInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, opts cloud.RemediationOptions) (cloud.RemediationResult, error) {
+	p.log.record("stop", provider)
+	return cloud.RemediationResult{}, nil
+}
+
+func (p stubProvider) TerminateInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, maxSizeLevel int, maxHourlyPrice float64, opts cloud.RemediationOptions) (cloud.RemediationResult, error) {
+	p.log.record("terminate", provider)
+	return cloud.RemediationResult{}, nil
+}
+
+func (p stubProvider) StopIdleInstances(ctx context.Context, provider models.CloudProvider, cfg *config.Config, idleHoursThreshold float64, opts cloud.RemediationOptions) (cloud.RemediationResult, error) {
+	p.log.record("stop-idle", provider)
+	return cloud.RemediationResult{}, nil
+}
+
+// stubProviderOfType registers p under a type unique to the test, as registrations are
+// global, and returns a connected provider of that type
+func stubProviderOfType(t *testing.T, p stubProvider) models.CloudProvider {
+	t.Helper()
+	providerType := "stub-" + t.Name()
+	cloud.RegisterProvider(providerType, p)
+	return models.CloudProvider{
+		ID:             "provider-" + t.Name(),
+		OrganizationID: "org",
+		Name:           "stub",
+		Type:           providerType,
+		Status:         "connected",
+	}
+}
@@ -51,17 +51,19 @@ func main() {
 
 	// Middleware
 	app.Use(recover.New())
-	app.Use(logger.New())
+	app.Use(middleware.RequestID())
+	app.Use(logger.New(logger.Config{
+		Format: "${time} ${locals:requestID} ${status} - ${latency} ${method} ${path}\n",
+	}))
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     cfg.AllowedOrigins,
 		AllowCredentials: true,
-		AllowHeaders:     "Origin, Content-Type, Accept, Authorization",
+		AllowHeaders:     "Origin, Content-Type, Accept, Authorization, X-Request-ID",
+		ExposeHeaders:    "X-Request-ID",
 	}))
 
 	// Health check
-	app.Get("/health", func(c *fiber.Ctx) error {
-		return c.JSON(fiber.Map{"status": "ok"})
-	})
+	app.Get("/health", h.Health)
 
 	// API routes
 	api := app.Group("/api")
@@ -72,19 +74,51 @@ func main() {
 
 	// Dashboard
 	api.Get("/dashboard/stats", h.GetDashboardStats)
+	api.Get("/dashboard/cost-breakdown", h.GetDashboardCostBreakdown)
+
+	// Enforcement kill switch
+	api.Post("/enforcement/pause", middleware.RequireOrgAdmin(), h.PauseEnforcement)
+	api.Post("/enforcement/resume", middleware.RequireOrgAdmin(), h.ResumeEnforcement)
+	api.Post("/admin/opa/reload", middleware.RequireOrgAdmin(), h.ReloadOPAPolicies)
+	api.Get("/enforcement/runs", h.ListEnforcementRuns)
+	api.Get("/decisions", h.ListDecisions)
 
 	// Policies
 	api.Get("/policies", h.ListPolicies)
+	api.Get("/policies/input-schema/:type", h.GetPolicyInputSchema)
+	api.Get("/policies/conflicts", h.GetPolicyConflicts)
+	api.Get("/policies/permissions", h.GetPolicyPermissions)
 	api.Get("/policies/:id", h.GetPolicy)
+	api.Get("/policies/:id/rego", h.GetPolicyRego)
+	api.Post("/policies/:id/lint", h.LintPolicy)
 	api.Post("/policies", h.CreatePolicy)
 	api.Patch("/policies/:id", h.UpdatePolicy)
 	api.Delete("/policies/:id", h.DeletePolicy)
+	api.Post("/policies/:id/backtest", h.BacktestPolicy)
+
+	// Budget hierarchy
+	api.Get("/budgets", h.ListBudgets)
+	api.Post("/budgets", h.CreateBudget)
+	api.Put("/budgets/:id", h.UpdateBudget)
+	api.Delete("/budgets/:id", h.DeleteBudget)
+
+	// Protected resources
+	protectedResourceRoutes(api, h)
 
 	// Cloud Providers
 	api.Get("/cloud-providers", h.ListCloudProviders)
 	api.Get("/cloud-providers/:id", h.GetCloudProvider)
+	api.Get("/cloud-providers/:id/cost-breakdown", h.GetCloudProviderCostBreakdown)
+	api.Get("/cloud-providers/:id/status", h.GetCloudProviderStatus)
+	api.Get("/cloud-providers/:id/instances", h.ListCloudProviderInstances)
+	api.Get("/cloud-providers/:id/policies", h.GetCloudProviderPolicies)
+	api.Post("/cloud-providers/:id/refresh", h.RefreshCloudProviderBilling)
+	api.Post("/cloud-providers/:id/remediate-test", middleware.RequireOrgAdmin(), h.TestCloudRemediation)
 	api.Post("/cloud-providers", h.CreateCloudProvider)
+	api.Patch("/cloud-providers/:id", h.UpdateCloudProvider)
 	api.Delete("/cloud-providers/:id", h.DeleteCloudProvider)
+	api.Post("/cloud-provider-groups", h.CreateCloudProviderGroup)
+	api.Get("/cloud-provider-groups/:id/members", h.ListCloudProviderGroupMembers)
 
 	// Activity Log
 	api.Get("/activity", h.ListActivityLogs)
@@ -92,10 +126,23 @@ func main() {
 	// Webhooks
 	api.Get("/webhooks", h.ListWebhooks)
 	api.Post("/webhooks", h.CreateWebhook)
+	api.Get("/webhooks/styles", h.GetWebhookStyles)
+	api.Put("/webhooks/styles", middleware.RequireOrgAdmin(), h.UpdateWebhookStyles)
+	api.Get("/digest", h.GetDigestSettings)
+	api.Put("/digest", middleware.RequireOrgAdmin(), h.UpdateDigestSettings)
 	api.Delete("/webhooks/:id", h.DeleteWebhook)
+	api.Post("/webhooks/:id/enable", h.EnableWebhook)
+	api.Get("/webhooks/:id/deliveries", h.ListWebhookDeliveries)
+	api.Post("/webhooks/:id/replay/:deliveryId", h.ReplayWebhookDelivery)
 
 	// Policy Violations
 	api.Get("/violations", h.ListViolations)
+	api.Get("/violations/export", h.ExportViolations)
+	api.Get("/reports/compliance", h.GetComplianceReport)
+	api.Delete("/violations", middleware.RequireOrgAdmin(), h.PurgeViolations)
+	api.Post("/violations/:id/remediate", middleware.RequireOrgAdmin(), h.RemediateViolation)
+	api.Get("/violations/:id/comments", h.ListViolationComments)
+	api.Post("/violations/:id/comments", h.CreateViolationComment)
 
 	// Policy Templates & Library
 	api.Get("/policy-categories", h.ListPolicyCategories)
@@ -107,18 +154,22 @@ func main() {
 	// AI Recommendations
 	api.Post("/recommendations/generate", h.GenerateRecommendations)
 	api.Get("/recommendations", h.ListRecommendations)
+	api.Get("/recommendations/savings-summary", h.GetRecommendationSavingsSummary)
 	api.Post("/recommendations/:id/accept", h.AcceptRecommendation)
 	api.Post("/recommendations/:id/reject", h.RejectRecommendation)
+	api.Post("/recommendations/:id/snooze", h.SnoozeRecommendation)
 
 	// AI Cost Tracking
 	api.Post("/ai/token-usage", h.TrackTokenUsage)
 	api.Get("/ai/token-usage", h.GetTokenUsage)
+	api.Post("/ai/check", h.CheckAIRequest)
 	api.Post("/ai/gpu-metrics", h.TrackGPUMetrics)
 	api.Get("/ai/gpu-metrics", h.GetGPUMetrics)
 	api.Post("/ai/workloads", h.CreateAIWorkload)
 	api.Get("/ai/workloads", h.ListAIWorkloads)
 	api.Post("/ai/budgets", h.CreateAIBudget)
 	api.Get("/ai/budgets", h.ListAIBudgets)
+	api.Get("/ai/budgets/:id/usage", h.GetAIBudgetUsage)
 	api.Get("/ai/dashboard", h.GetAIDashboard)
 
 	// Start enforcement worker
@@ -126,8 +177,18 @@ func main() {
 	defer cancel()
 
 	enforcementWorker := worker.NewEnforcementWorker(db, opaEngine, cfg)
+	enforcementWorker.OrgChanged = h.InvalidateDashboardStats
+	h.Enforcement = enforcementWorker
 	go enforcementWorker.Start(ctx, 5*time.Minute)
 
+	// Start retention worker (downsamples old usage data daily)
+	retentionWorker := worker.NewRetentionWorker(db, cfg)
+	go retentionWorker.Start(ctx, 24*time.Hour)
+
+	// Start digest worker (sends each org's spend digest when due)
+	digestWorker := worker.NewDigestWorker(db, cfg)
+	go digestWorker.Start(ctx, time.Hour)
+
 	// Start server
 	go func() {
 		port := os.Getenv("PORT")
@@ -149,3 +210,11 @@ func main() {
 	app.Shutdown()
 }
 
+// protectedResourceRoutes serves the protected list. Changing it decides what remediation may
+// act on, so like pausing enforcement it is limited to org admins.
+func protectedResourceRoutes(api fiber.Router, h *handlers.Handlers) {
+	api.Get("/protected-resources", h.ListProtectedResources)
+	api.Post("/protected-resources", middleware.RequireOrgAdmin(), h.CreateProtectedResource)
+	api.Put("/protected-resources/:id", middleware.RequireOrgAdmin(), h.UpdateProtectedResource)
+	api.Delete("/protected-resources/:id", middleware.RequireOrgAdmin(), h.DeleteProtectedResource)
+}
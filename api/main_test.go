@@ -0,0 +1,77 @@
+package main
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	config "finopsbridge/api/internal/config_"
+	handlers "finopsbridge/api/internal/handlers_"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// protectedResourcesApp serves the protected resource routes to a caller with the given
+// organization role, on a dry-run database
+func protectedResourcesApp(t *testing.T, role string) *fiber.App {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost dbname=finopsbridge sslmode=disable"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := fiber.New()
+	api := app.Group("/api")
+	api.Use(func(c *fiber.Ctx) error {
+		c.Locals("orgID", "org")
+		c.Locals("orgRole", role)
+		return c.Next()
+	})
+	protectedResourceRoutes(api, handlers.New(db, nil, &config.Config{ReportingCurrency: "USD"}))
+	return app
+}
+
+func TestProtectedResourceChangesRequireAdmin(t *testing.T) {
+	member := protectedResourcesApp(t, "org:member")
+	for _, route := range []struct{ method, path string }{
+		{"POST", "/api/protected-resources"},
+		{"PUT", "/api/protected-resources/pr1"},
+		{"DELETE", "/api/protected-resources/pr1"},
+	} {
+		req := httptest.NewRequest(route.method, route.path, strings.NewReader(`{"resourceId": "i-1"}`))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := member.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != fiber.StatusForbidden {
+			t.Errorf("%s %s as a member: status %d, want 403", route.method, route.path, resp.StatusCode)
+		}
+	}
+
+	// Members can still see the list
+	resp, err := member.Test(httptest.NewRequest("GET", "/api/protected-resources", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Errorf("GET as a member: status %d, want 200", resp.StatusCode)
+	}
+
+	// An admin reaches the handler, which rejects the missing resource ID
+	req := httptest.NewRequest("POST", "/api/protected-resources", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err = protectedResourcesApp(t, "org:admin").Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusBadRequest {
+		t.Errorf("POST as an admin: status %d, want 400", resp.StatusCode)
+	}
+}
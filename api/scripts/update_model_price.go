@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	config "finopsbridge/api/internal/config_"
+	database "finopsbridge/api/internal/database_"
+	models "finopsbridge/api/internal/models_"
+)
+
+// Records a model's current prices in the catalog, creating the entry if needed, e.g.
+//
+//	go run scripts/update_model_price.go -provider openai -model gpt-4o -input 2.5 -output 10 \
+//	    -cached 1.25 -source https://openai.com/api/pricing
+//
+// Checking prices that haven't changed still marks them as fresh.
+func main() {
+	provider := flag.String("provider", "", "model provider, e.g. openai")
+	modelName := flag.String("model", "", "model name, e.g. gpt-4o")
+	input := flag.Float64("input", 0, "input price per million tokens")
+	output := flag.Float64("output", 0, "output price per million tokens")
+	cached := flag.Float64("cached", 0, "cached input price per million tokens (0 bills cached tokens at the input price)")
+	source := flag.String("source", models.CatalogSourceManual, "where the prices came from, e.g. the pricing page URL")
+	flag.Parse()
+
+	if *provider == "" || *modelName == "" {
+		log.Fatal("-provider and -model are required")
+	}
+
+	cfg := config.Load()
+	db, err := database.Initialize(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+
+	now := time.Now()
+	var entry models.AIModelCatalog
+	result := db.Where(models.AIModelCatalog{Provider: *provider, ModelName: *modelName}).
+		Attrs(models.AIModelCatalog{
+			InputPricePerMToken:       *input,
+			OutputPricePerMToken:      *output,
+			CachedInputPricePerMToken: *cached,
+			Source:                    *source,
+			PricesUpdatedAt:           &now,
+		}).
+		FirstOrCreate(&entry)
+	if result.Error != nil {
+		log.Fatalf("Failed to find or create %s/%s: %v", *provider, *modelName, result.Error)
+	}
+	if result.RowsAffected > 0 {
+		fmt.Printf("✅ Added %s/%s to the model catalog\n", *provider, *modelName)
+		return
+	}
+
+	if err := db.Model(&entry).Updates(map[string]interface{}{
+		"input_price_per_m_token":        *input,
+		"output_price_per_m_token":       *output,
+		"cached_input_price_per_m_token": *cached,
+		"source":                         *source,
+		"prices_updated_at":              now,
+	}).Error; err != nil {
+		log.Fatalf("Failed to update %s/%s: %v", *provider, *modelName, err)
+	}
+	fmt.Printf("✅ Updated prices of %s/%s\n", *provider, *modelName)
+}
@@ -62,6 +62,9 @@ export default function CloudsPage() {
               </div>
               <CardTitle>{cloud.name}</CardTitle>
               <CardDescription className="uppercase">{cloud.type}</CardDescription>
+              {cloud.groupName && (
+                <CardDescription>Group: {cloud.groupName}</CardDescription>
+              )}
             </CardHeader>
             <CardContent>
               <div className="space-y-2">
@@ -13,6 +13,8 @@ export interface CloudProvider {
   status: 'connected' | 'disconnected' | 'error'
   connectedAt?: string
   monthlySpend?: number
+  groupId?: string
+  groupName?: string
   credentials?: {
     roleArn?: string
     servicePrincipalId?: string